@@ -0,0 +1,77 @@
+package client
+
+import (
+	"strings"
+	"time"
+)
+
+// downFor is how long an endpoint that failed a call is tried only after
+// the others
+const downFor = 10 * time.Second
+
+// spreadMethods are the calls spread across the addresses given to
+// NewClient; other calls start with the first one
+var spreadMethods = map[string]bool{
+	"GetPreParams":     true,
+	"StreamPreParams":  true,
+	"WaitForPreParams": true,
+}
+
+// splitAddresses returns the comma-separated addresses given to NewClient
+func splitAddresses(address string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(address, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// markDown records that a call failed on the endpoint
+func (ep *endpoint) markDown() {
+	ep.failedAt.Store(time.Now().UnixNano())
+}
+
+// markUp records that a call succeeded on the endpoint
+func (ep *endpoint) markUp() {
+	ep.failedAt.Store(0)
+}
+
+// up reports whether the endpoint is expected to work: it has not failed a
+// call recently and, with a health monitor, passed its latest verification
+func (c *PrimeServiceClient) up(ep *endpoint, now time.Time) bool {
+	if failed := ep.failedAt.Load(); failed != 0 && now.Sub(time.Unix(0, failed)) < downFor {
+		return false
+	}
+	return c.opts.healthInterval <= 0 || ep.healthy.Load()
+}
+
+// spread returns the endpoints with the addresses given to NewClient
+// rotated, so successive calls start with successive addresses
+func (c *PrimeServiceClient) spread() []*endpoint {
+	endpoints := append([]*endpoint(nil), c.endpoints...)
+	if c.primaries > 1 {
+		n := int(c.next.Add(1) % uint32(c.primaries))
+		primaries := endpoints[:c.primaries]
+		rotated := append(append([]*endpoint(nil), primaries[n:]...), primaries[:n]...)
+		copy(primaries, rotated)
+	}
+	return endpoints
+}
+
+// upFirst moves the endpoints that are not up behind the others, keeping
+// their order otherwise; if none is up, the order is unchanged
+func (c *PrimeServiceClient) upFirst(endpoints []*endpoint) []*endpoint {
+	now := time.Now()
+	result := make([]*endpoint, 0, len(endpoints))
+	var down []*endpoint
+	for _, ep := range endpoints {
+		if c.up(ep, now) {
+			result = append(result, ep)
+		} else {
+			down = append(down, ep)
+		}
+	}
+	return append(result, down...)
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// budgetSafety is the fraction of the remaining deadline a chunk may use
+const budgetSafety = 0.8
+
+// itemLatency tracks an exponentially weighted moving average of the
+// service time per returned item
+type itemLatency struct {
+	mu      sync.Mutex
+	perItem time.Duration
+}
+
+// observe records an RPC that returned n items in d
+func (l *itemLatency) observe(d time.Duration, n int) {
+	if n <= 0 {
+		return
+	}
+	sample := d / time.Duration(n)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.perItem == 0 {
+		l.perItem = sample
+	} else {
+		l.perItem = (l.perItem*7 + sample*3) / 10
+	}
+}
+
+// estimate returns the current per-item latency (zero if unknown)
+func (l *itemLatency) estimate() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.perItem
+}
+
+// getPreParamsSplit fetches count items across several RPCs sized to the
+// context deadline, stopping early when the service runs dry
+func (c *PrimeServiceClient) getPreParamsSplit(ctx context.Context, count uint32, key string) ([]*PreParamsData, error) {
+	result := make([]*PreParamsData, 0, count)
+
+	for chunkIndex, remaining := 0, count; remaining > 0; chunkIndex++ {
+		deadline, _ := ctx.Deadline()
+		budget := time.Duration(float64(time.Until(deadline)) * budgetSafety)
+		if budget <= 0 {
+			return result, context.DeadlineExceeded
+		}
+
+		chunk := c.chunkSize(remaining, budget)
+
+		chunkCtx, cancel := context.WithTimeout(ctx, budget)
+		params, err := c.fetchPreParams(chunkCtx, chunk, fmt.Sprintf("%s#%d", key, chunkIndex))
+		cancel()
+		if err != nil {
+			return result, err
+		}
+
+		result = append(result, params...)
+		if len(params) < int(chunk) {
+			break // service has nothing more right now
+		}
+		remaining -= uint32(len(params))
+	}
+
+	return result, nil
+}
+
+// chunkSize picks how many items to request so the RPC fits in budget
+func (c *PrimeServiceClient) chunkSize(remaining uint32, budget time.Duration) uint32 {
+	chunk := c.opts.maxChunk
+	if perItem := c.latency.estimate(); perItem > 0 {
+		if fit := uint32(budget / perItem); fit < chunk {
+			chunk = fit
+		}
+	}
+	if chunk > remaining {
+		chunk = remaining
+	}
+	if chunk == 0 {
+		chunk = 1
+	}
+	return chunk
+}
@@ -0,0 +1,26 @@
+package client
+
+import "github.com/TEENet-io/prime-service/client/lite"
+
+// MinBits are the minimum bit lengths received parameters must have. Zero
+// fields are not checked.
+type MinBits = lite.MinBits
+
+// UndersizedError is returned when a received parameter is shorter than
+// MinBits requires
+type UndersizedError = lite.UndersizedError
+
+// WithMinBits makes the client reject received items shorter than req
+// requires, e.g. WithMinBits(MinBits{NTilde: 2048, PaillierN: 2048}),
+// protecting signers from a misconfigured or downgraded service. A failing
+// call returns an *UndersizedError (match it with errors.As) and no items.
+func WithMinBits(req MinBits) Option {
+	return func(o *options) {
+		o.minBits = req
+	}
+}
+
+// CheckBits returns an *UndersizedError if p is shorter than req requires
+func (p *PreParamsData) CheckBits(req MinBits) error {
+	return p.lite().CheckBits(req)
+}
@@ -2,63 +2,294 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/TEENet-io/prime-service/client/lite"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
 	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
 )
 
 // PrimeServiceClient wraps the gRPC client for the prime service
 type PrimeServiceClient struct {
-	conn   *grpc.ClientConn
-	client pb.PrimeServiceClient
+	endpoints []*endpoint // primary first, then fallbacks
+	opts      options
+	done      chan struct{}
+	closeOnce sync.Once
+	latency   itemLatency // measured per-item service latency
+
+	// Addresses given to NewClient, first in endpoints, and their rotation
+	// (see spread)
+	primaries int
+	next      atomic.Uint32
+}
+
+// endpoint is a connection to one prime service address
+type endpoint struct {
+	address  string
+	conn     *grpc.ClientConn
+	client   pb.PrimeServiceClient
+	healthy  atomic.Bool            // result of the latest health verification
+	region   string                 // configured with WithReplicas
+	reported atomic.Pointer[string] // region the service reported
+	failedAt atomic.Int64           // Last failed call (unix nanoseconds; 0: succeeded since)
 }
 
-// NewClient creates a new prime service client
-func NewClient(address string) (*PrimeServiceClient, error) {
-	conn, err := grpc.NewClient(address,
+// NewClient creates a new prime service client. address may list several
+// addresses of equal replicas separated by commas, e.g.
+// "prime-a:50055,prime-b:50055": GetPreParams, StreamPreParams and
+// WaitForPreParams calls are spread across them round robin, and each call
+// fails over to the others (then to WithFallback addresses) when one keeps
+// failing. Endpoints that failed a call in the last 10 seconds, or failed
+// their latest health verification (WithHealthCheck), are tried last.
+func NewClient(address string, opts ...Option) (*PrimeServiceClient, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	dialOpts := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
+	if o.tls {
+		creds, err := lite.TLSCredentials(o.tlsCA, o.tlsCert, o.tlsKey)
+		if err != nil {
+			return nil, err
+		}
+		dialOpts[0] = creds
+	}
+	if o.apiKey != "" {
+		dialOpts = append(dialOpts, lite.APIKeyCredentials(o.apiKey))
+	}
+	if o.keepaliveTime > 0 {
+		dialOpts = append(dialOpts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                o.keepaliveTime,
+			Timeout:             o.keepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	primaries := splitAddresses(address)
+	if len(primaries) == 0 {
+		return nil, fmt.Errorf("no service address")
+	}
+	var addrs []replicaAddress
+	for _, addr := range primaries {
+		addrs = append(addrs, replicaAddress{address: addr})
+	}
+	for _, addr := range o.fallbacks {
+		addrs = append(addrs, replicaAddress{address: addr})
+	}
+	addrs = append(addrs, o.replicas...)
 
-	return &PrimeServiceClient{
-		conn:   conn,
-		client: pb.NewPrimeServiceClient(conn),
-	}, nil
+	c := &PrimeServiceClient{opts: o, primaries: len(primaries), done: make(chan struct{})}
+	for _, addr := range addrs {
+		conn, err := grpc.NewClient(addr.address, dialOpts...)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("failed to connect: %w", err)
+		}
+		c.endpoints = append(c.endpoints, &endpoint{
+			address: addr.address,
+			conn:    conn,
+			client:  pb.NewPrimeServiceClient(conn),
+			region:  addr.region,
+		})
+	}
+
+	if o.healthInterval > 0 {
+		go c.monitor()
+	} else if o.region != "" {
+		go c.discoverRegions()
+	}
+
+	return c, nil
 }
 
-// Close closes the client connection
+// Close closes the client connections
 func (c *PrimeServiceClient) Close() error {
-	return c.conn.Close()
+	c.closeOnce.Do(func() { close(c.done) })
+
+	var firstErr error
+	for _, ep := range c.endpoints {
+		if err := ep.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// call runs fn against the endpoints in order, retrying transient failures
+// and invoking the instrumentation hooks. Endpoints a call moves away from
+// are tried last by the next calls for a while.
+func (c *PrimeServiceClient) call(ctx context.Context, method string, fn func(ctx context.Context, ep *endpoint) error) (err error) {
+	hooks := c.opts.hooks
+	if hooks.OnRequest != nil {
+		hooks.OnRequest(method)
+	}
+	if hooks.ObserveLatency != nil {
+		start := time.Now()
+		defer func() { hooks.ObserveLatency(method, time.Since(start), err) }()
+	}
+
+	endpoints := c.order(spreadMethods[method])
+	for i, ep := range endpoints {
+		if i > 0 && hooks.OnFallback != nil {
+			hooks.OnFallback(method, endpoints[i-1].address, ep.address, err)
+		}
+
+		backoff := c.opts.retryBackoff
+		for attempt := 1; ; attempt++ {
+			err = fn(ctx, ep)
+			if err == nil || !isRetryable(err) || ctx.Err() != nil {
+				break
+			}
+			if attempt >= c.opts.maxAttempts {
+				break
+			}
+			if hooks.OnRetry != nil {
+				hooks.OnRetry(method, attempt, err)
+			}
+			wait := backoff
+			if delay, ok := retryDelay(err); ok && delay > wait {
+				wait = delay
+			}
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return err
+			}
+			backoff *= 2
+		}
+
+		// An endpoint that ran dry is not retried, but another one may still
+		// hold items; it stays in place for the next calls
+		poolEmpty := errors.Is(lite.WrapPoolEmpty(err), lite.ErrPoolEmpty)
+		if err == nil || !isRetryable(err) && !poolEmpty || ctx.Err() != nil {
+			if err == nil {
+				ep.markUp()
+			}
+			return err
+		}
+		if !poolEmpty {
+			ep.markDown()
+		}
+	}
+
+	return err
+}
+
+// isRetryable reports whether an RPC error is transient. ResourceExhausted
+// only is when the server marks it so with a RetryInfo; an empty pool or an
+// oversized response does not change by retrying.
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted:
+		return true
+	case codes.ResourceExhausted:
+		_, ok := retryDelay(err)
+		return ok
+	}
+	return false
+}
+
+// retryDelay returns the delay the server asks for in a RetryInfo detail
+func retryDelay(err error) (time.Duration, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return 0, false
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.RetryInfo); ok {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
 }
 
 // GetPreParams retrieves PreParamsData from the service
 // count: number of parameters to retrieve (default 1 if 0)
+// With WithBatchSplitting and a context deadline, large counts are split
+// across several RPCs sized to the remaining budget; if a later chunk fails
+// the items already received are returned together with the error.
 func (c *PrimeServiceClient) GetPreParams(ctx context.Context, count uint32) ([]*PreParamsData, error) {
 	if count == 0 {
 		count = 1 // Default to 1 if not specified
 	}
 
-	resp, err := c.client.GetPreParams(ctx, &pb.GetPreParamsRequest{
-		Count: count,
-	})
+	// Every call carries an idempotency key so retries never double-consume
+	key, ok := lite.IdempotencyKey(ctx)
+	if !ok {
+		key = lite.NewIdempotencyKey()
+	}
+
+	var result []*PreParamsData
+	var err error
+	if _, ok := ctx.Deadline(); ok && c.opts.maxChunk > 0 && count > 1 && !lite.DistinctProvenance(ctx) && !lite.DistinctSeconds(ctx) {
+		result, err = c.getPreParamsSplit(ctx, count, key)
+	} else {
+		result, err = c.fetchPreParams(ctx, count, key)
+	}
+	// Partial results are verified too, so no unchecked item is handed out
+	if verr := verifyAll(result, c.opts.verify, c.opts.minBits); verr != nil {
+		return nil, verr
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get pre-params: %w", err)
+		return result, err
 	}
 
-	if len(resp.Params) == 0 {
-		return nil, fmt.Errorf("no parameters returned from service")
+	if len(result) == 0 {
+		return nil, ErrNoParams
 	}
 
-	// Convert from protobuf to internal format
-	result := make([]*PreParamsData, len(resp.Params))
-	for i, params := range resp.Params {
+	return result, nil
+}
+
+// fetchPreParams retrieves up to count parameters with a single RPC
+func (c *PrimeServiceClient) fetchPreParams(ctx context.Context, count uint32, key string) ([]*PreParamsData, error) {
+	start := time.Now()
+
+	// Batches too large for one message are streamed
+	var items []*pb.PreParamsData
+	primeBits, paillierBits := lite.BitSizes(ctx)
+	err := c.call(ctx, "GetPreParams", func(ctx context.Context, ep *endpoint) error {
+		var err error
+		items, err = lite.FetchPreParams(ctx, ep.client, &pb.GetPreParamsRequest{
+			Count:              count,
+			IdempotencyKey:     key,
+			DistinctProvenance: lite.DistinctProvenance(ctx),
+			NoGenerate:         lite.NoGenerate(ctx),
+			AllowImported:      lite.AllowImported(ctx),
+			MinAssurance:       lite.MinAssurance(ctx),
+			DistinctSeconds:    lite.DistinctSeconds(ctx),
+			PrimeBitSize:       primeBits,
+			PaillierBitSize:    paillierBits,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, lite.WrapPoolEmpty(fmt.Errorf("failed to get pre-params: %w", err))
+	}
+
+	c.latency.observe(time.Since(start), len(items))
+
+	return fromProto(items), nil
+}
+
+// fromProto converts protobuf parameter sets to the client format
+func fromProto(items []*pb.PreParamsData) []*PreParamsData {
+	result := make([]*PreParamsData, len(items))
+	for i, params := range items {
 		result[i] = &PreParamsData{
 			PaillierKey: &paillier.PrivateKey{
 				PublicKey: paillier.PublicKey{
@@ -77,13 +308,23 @@ func (c *PrimeServiceClient) GetPreParams(ctx context.Context, count uint32) ([]
 			P:       new(big.Int).SetBytes(params.P),
 			Q:       new(big.Int).SetBytes(params.Q),
 			GeneratedAt: time.Unix(params.GeneratedAt, 0),
+			Metadata:    lite.MetadataFromProto(params.Metadata),
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 // GetPoolStatus gets the current pool status
 func (c *PrimeServiceClient) GetPoolStatus(ctx context.Context) (*pb.PoolStatus, error) {
-	return c.client.GetPoolStatus(ctx, &pb.Empty{})
+	var resp *pb.PoolStatus
+	err := c.call(ctx, "GetPoolStatus", func(ctx context.Context, ep *endpoint) error {
+		var err error
+		resp, err = ep.client.GetPoolStatus(ctx, &pb.Empty{})
+		if err == nil {
+			ep.learnRegion(resp.Region)
+		}
+		return err
+	})
+	return resp, err
 }
\ No newline at end of file
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// statusError returns a status error with details attached
+func statusError(t *testing.T, code codes.Code, details ...*errdetails.ErrorInfo) error {
+	t.Helper()
+	st := status.New(code, "test error")
+	for _, d := range details {
+		var err error
+		if st, err = st.WithDetails(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return st.Err()
+}
+
+func TestCallRetries(t *testing.T) {
+	transient, err := status.New(codes.ResourceExhausted, "busy").WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Millisecond)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		err       error
+		wantCalls []int // Per endpoint
+		wantDown  bool  // First endpoint marked as failing
+	}{
+		{name: "unavailable", err: status.Error(codes.Unavailable, "down"), wantCalls: []int{3, 3}, wantDown: true},
+		{name: "transient resource exhausted", err: transient.Err(), wantCalls: []int{3, 3}, wantDown: true},
+		{name: "resource exhausted", err: statusError(t, codes.ResourceExhausted), wantCalls: []int{1, 0}},
+		{name: "response too large", err: statusError(t, codes.ResourceExhausted, &errdetails.ErrorInfo{Reason: lite.ResponseTooLargeReason}), wantCalls: []int{1, 0}},
+		// Another endpoint may still hold items, but this one is not retried
+		{name: "pool empty", err: statusError(t, codes.ResourceExhausted, &errdetails.ErrorInfo{Reason: lite.PoolEmptyReason}), wantCalls: []int{1, 1}},
+		{name: "quota exceeded", err: statusError(t, codes.FailedPrecondition, &errdetails.ErrorInfo{Reason: "QUOTA_EXCEEDED"}), wantCalls: []int{1, 0}},
+		{name: "invalid argument", err: status.Error(codes.InvalidArgument, "bad"), wantCalls: []int{1, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &PrimeServiceClient{
+				endpoints: []*endpoint{{address: "a"}, {address: "b"}},
+				opts:      options{maxAttempts: 3, retryBackoff: time.Millisecond},
+			}
+			calls := map[string]int{}
+			err := c.call(context.Background(), "GetPreParams", func(ctx context.Context, ep *endpoint) error {
+				calls[ep.address]++
+				return tt.err
+			})
+			if status.Code(err) != status.Code(tt.err) {
+				t.Fatalf("call() = %v, want %v", err, tt.err)
+			}
+			for i, ep := range c.endpoints {
+				if calls[ep.address] != tt.wantCalls[i] {
+					t.Fatalf("endpoint %s called %d times, want %d", ep.address, calls[ep.address], tt.wantCalls[i])
+				}
+			}
+			if down := c.endpoints[0].failedAt.Load() != 0; down != tt.wantDown {
+				t.Fatalf("endpoint marked failing = %v, want %v", down, tt.wantDown)
+			}
+		})
+	}
+}
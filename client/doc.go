@@ -0,0 +1,9 @@
+// Package client is the Go client for the prime service.
+//
+// The exported API of this package, client/lite, client/web and proto
+// follows semantic versioning: within a major version, exported identifiers,
+// struct fields and function signatures are only added, never removed or
+// changed. Errors callers should match with errors.Is are exported as Err*
+// variables; other error texts may change. scripts/api-check.sh enforces
+// this against the latest release tag.
+package client
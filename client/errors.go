@@ -0,0 +1,11 @@
+package client
+
+import "github.com/TEENet-io/prime-service/client/lite"
+
+// ErrNoParams is returned by GetPreParams when the service returned no
+// parameters (e.g. an empty pool without on-demand generation)
+var ErrNoParams = lite.ErrNoParams
+
+// ErrPoolEmpty is returned by GetPreParams for WithNoGenerate calls that no
+// endpoint could serve from its pool
+var ErrPoolEmpty = lite.ErrPoolEmpty
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/connectivity"
+)
+
+// HealthCheck queries the service health, using fallbacks and retries as configured
+func (c *PrimeServiceClient) HealthCheck(ctx context.Context) (*pb.HealthStatus, error) {
+	var resp *pb.HealthStatus
+	err := c.call(ctx, "HealthCheck", func(ctx context.Context, ep *endpoint) error {
+		var err error
+		resp, err = ep.client.HealthCheck(ctx, &pb.Empty{})
+		if err == nil {
+			ep.learnRegion(resp.Region)
+		}
+		return err
+	})
+	return resp, err
+}
+
+// IsHealthy reports whether at least one endpoint is usable. With a health
+// monitor (WithHealthCheck) this is the result of the latest verification;
+// otherwise it reflects the gRPC connectivity state.
+func (c *PrimeServiceClient) IsHealthy() bool {
+	for _, ep := range c.endpoints {
+		if c.opts.healthInterval > 0 {
+			if ep.healthy.Load() {
+				return true
+			}
+		} else if ep.conn.GetState() == connectivity.Ready {
+			return true
+		}
+	}
+	return false
+}
+
+// WaitReady blocks until an endpoint is connected and passes a HealthCheck,
+// or ctx is done. With WithRegion, endpoints in the client's region are
+// checked first.
+func (c *PrimeServiceClient) WaitReady(ctx context.Context) error {
+	delay := 100 * time.Millisecond
+	for {
+		for _, ep := range c.order(false) {
+			if c.verify(ctx, ep) {
+				return nil
+			}
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return fmt.Errorf("prime service not ready: %w", ctx.Err())
+		}
+		if delay < 2*time.Second {
+			delay *= 2
+		}
+	}
+}
+
+// verify reconnects a broken endpoint if needed and checks it with a
+// HealthCheck RPC, recording the result
+func (c *PrimeServiceClient) verify(ctx context.Context, ep *endpoint) bool {
+	switch ep.conn.GetState() {
+	case connectivity.Idle, connectivity.TransientFailure:
+		ep.conn.Connect()
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, c.opts.healthTimeout)
+	defer cancel()
+
+	resp, err := ep.client.HealthCheck(checkCtx, &pb.Empty{})
+	if err == nil {
+		ep.learnRegion(resp.Region)
+	}
+	healthy := err == nil && resp.Healthy
+	ep.healthy.Store(healthy)
+	return healthy
+}
+
+// monitor periodically verifies every endpoint until the client is closed
+func (c *PrimeServiceClient) monitor() {
+	ticker := time.NewTicker(c.opts.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, ep := range c.endpoints {
+			c.verify(context.Background(), ep)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-c.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package client
+
+import (
+	"context"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+)
+
+// WithIdempotencyKey attaches an idempotency key to ctx for GetPreParams.
+// Repeating a call with the same key (e.g. after an application crash)
+// returns the originally allocated items, even across server restarts.
+// Without it, the client generates a fresh key per call, which still makes
+// the client's own retries safe.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return lite.WithIdempotencyKey(ctx, key)
+}
@@ -0,0 +1,46 @@
+package lite
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// MinBits are the minimum bit lengths received parameters must have. Zero
+// fields are not checked.
+type MinBits struct {
+	NTilde    int // NTildei, the modulus of the range proofs
+	PaillierN int // The Paillier modulus
+}
+
+// UndersizedError is returned when a received parameter is shorter than
+// MinBits requires, e.g. from a misconfigured or downgraded service
+type UndersizedError struct {
+	Param    string // NTildei or PaillierN
+	Bits     int
+	Required int
+}
+
+func (e *UndersizedError) Error() string {
+	return fmt.Sprintf("received %s has %d bits, %d required", e.Param, e.Bits, e.Required)
+}
+
+// CheckBits returns an *UndersizedError if p is shorter than req requires
+func (p *PreParamsData) CheckBits(req MinBits) error {
+	for _, c := range []struct {
+		param    string
+		v        *big.Int
+		required int
+	}{
+		{"NTildei", p.NTildei, req.NTilde},
+		{"PaillierN", p.PaillierN, req.PaillierN},
+	} {
+		bits := 0
+		if c.v != nil {
+			bits = c.v.BitLen()
+		}
+		if bits < c.required {
+			return &UndersizedError{Param: c.param, Bits: bits, Required: c.required}
+		}
+	}
+	return nil
+}
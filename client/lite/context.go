@@ -0,0 +1,185 @@
+package lite
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDHeader carries the trace ID the service prefixes its log lines,
+// error journal entries and audit records with
+const RequestIDHeader = "x-request-id"
+
+// PoolHeader selects a named pool of a service serving several
+const PoolHeader = "x-prime-pool"
+
+// NamespaceHeader names the tenant namespace calls are charged to
+const NamespaceHeader = "x-prime-namespace"
+
+type idempotencyKeyCtx struct{}
+
+type distinctProvenanceCtx struct{}
+
+type noGenerateCtx struct{}
+
+type allowImportedCtx struct{}
+
+type distinctSecondsCtx struct{}
+
+type minAssuranceCtx struct{}
+
+type bitSizesCtx struct{}
+
+type queueUpdatesCtx struct{}
+
+// bitSizes are the sizes attached with WithBitSizes
+type bitSizes struct{ prime, paillier uint32 }
+
+// WithIdempotencyKey attaches an idempotency key to ctx for GetPreParams.
+// Repeating a call with the same key (e.g. after an application crash)
+// returns the originally allocated items, even across server restarts.
+// Without it, the client generates a fresh key per call, which still makes
+// the client's own retries safe.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// IdempotencyKey returns the key attached with WithIdempotencyKey
+func IdempotencyKey(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyCtx{}).(string)
+	return key, ok && key != ""
+}
+
+// NewIdempotencyKey returns a random idempotency key
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithDistinctProvenance marks GetPreParams calls on ctx as ceremony batches:
+// the service only returns items with mutually distinct provenance (a
+// different host or generation burst), so no two parties of one ceremony get
+// parameters from the same worker run. The service may return fewer items
+// than requested. Such calls are never split across RPCs.
+func WithDistinctProvenance(ctx context.Context) context.Context {
+	return context.WithValue(ctx, distinctProvenanceCtx{}, true)
+}
+
+// DistinctProvenance reports whether ctx was marked with WithDistinctProvenance
+func DistinctProvenance(ctx context.Context) bool {
+	distinct, _ := ctx.Value(distinctProvenanceCtx{}).(bool)
+	return distinct
+}
+
+// WithNoGenerate marks GetPreParams calls on ctx as latency-sensitive: the
+// service never generates parameters synchronously for them, and fails with
+// ErrPoolEmpty at once if its pool has nothing suitable, so the caller can
+// try another instance. The service may return fewer items than requested.
+func WithNoGenerate(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noGenerateCtx{}, true)
+}
+
+// NoGenerate reports whether ctx was marked with WithNoGenerate
+func NoGenerate(ctx context.Context) bool {
+	noGenerate, _ := ctx.Value(noGenerateCtx{}).(bool)
+	return noGenerate
+}
+
+// WithAllowImported lets GetPreParams calls on ctx also receive imported
+// items that the service demotes to lower assurance (old items restored
+// from elsewhere; their Provenance has Imported set)
+func WithAllowImported(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowImportedCtx{}, true)
+}
+
+// AllowImported reports whether ctx was marked with WithAllowImported
+func AllowImported(ctx context.Context) bool {
+	allow, _ := ctx.Value(allowImportedCtx{}).(bool)
+	return allow
+}
+
+// WithRequestID tags calls made with ctx with a caller-chosen trace ID
+// (letters, digits and "-_.:", at most 64 characters), so service logs can
+// be correlated with the caller's own. Without one the service assigns an ID
+// and returns it in the x-request-id response header.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, RequestIDHeader, id)
+}
+
+// WithPool sends calls made with ctx to the named pool of a service serving
+// several (e.g. "staging" and "production" parameter classes). The service
+// rejects unknown names with NotFound rather than falling back to another
+// pool. Without it the service's default pool answers.
+func WithPool(ctx context.Context, name string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, PoolHeader, name)
+}
+
+// WithNamespace charges the items of calls made with ctx to a tenant
+// namespace of a service shared by several clients, counting them against
+// its quotas. The service rejects unknown namespaces with NotFound, and
+// calls with an API key bound to another namespace with PermissionDenied.
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, NamespaceHeader, namespace)
+}
+
+// WithDistinctSeconds asks GetPreParams calls on ctx to spread their batch
+// across items generated in distinct seconds where the pool allows it, a
+// cheaper alternative to WithDistinctProvenance that still returns the full
+// count. Items that could not be spread have ItemMetadata.SharedSecond set.
+// Such calls are never split across RPCs.
+func WithDistinctSeconds(ctx context.Context) context.Context {
+	return context.WithValue(ctx, distinctSecondsCtx{}, true)
+}
+
+// DistinctSeconds reports whether ctx was marked with WithDistinctSeconds
+func DistinctSeconds(ctx context.Context) bool {
+	distinct, _ := ctx.Value(distinctSecondsCtx{}).(bool)
+	return distinct
+}
+
+// WithMinAssurance asks GetPreParams calls on ctx for items of at least the
+// given assurance level: "standard" (any item) or "audited" (items that also
+// passed the service's full primality validation). The service audits items
+// it generates for such calls; ItemMetadata.Assurance reports each item's
+// level.
+func WithMinAssurance(ctx context.Context, level string) context.Context {
+	return context.WithValue(ctx, minAssuranceCtx{}, level)
+}
+
+// MinAssurance returns the level attached with WithMinAssurance ("": any)
+func MinAssurance(ctx context.Context) string {
+	level, _ := ctx.Value(minAssuranceCtx{}).(string)
+	return level
+}
+
+// WithQueueUpdates asks StreamPreParams calls on ctx to pass their place in
+// the service's on-demand generation queue to fn whenever it changes while
+// they wait for a slot. GetPreParams calls cannot report it.
+func WithQueueUpdates(ctx context.Context, fn func(QueueStatus)) context.Context {
+	return context.WithValue(ctx, queueUpdatesCtx{}, fn)
+}
+
+// QueueUpdates returns the function attached with WithQueueUpdates (nil: none)
+func QueueUpdates(ctx context.Context) func(QueueStatus) {
+	fn, _ := ctx.Value(queueUpdatesCtx{}).(func(QueueStatus))
+	return fn
+}
+
+// WithBitSizes asks GetPreParams calls on ctx for parameters of the given
+// bit sizes (0 keeps the service's size). The service routes such calls to
+// the pool serving these sizes, or generates them synchronously if it allows
+// the sizes but no pool serves them; it rejects other sizes with
+// InvalidArgument.
+func WithBitSizes(ctx context.Context, primeBits, paillierBits uint32) context.Context {
+	return context.WithValue(ctx, bitSizesCtx{}, bitSizes{prime: primeBits, paillier: paillierBits})
+}
+
+// BitSizes returns the prime and Paillier bit sizes attached with
+// WithBitSizes, zero if none were
+func BitSizes(ctx context.Context) (primeBits, paillierBits uint32) {
+	sizes, _ := ctx.Value(bitSizesCtx{}).(bitSizes)
+	return sizes.prime, sizes.paillier
+}
@@ -0,0 +1,354 @@
+// Package lite is a minimal prime service client for consumers that only
+// need the parameters as plain integers. Unlike package client it does not
+// depend on tss-lib, and it has no retries, fallbacks or batch splitting.
+package lite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNoParams is returned by GetPreParams when the service returned no
+// parameters (e.g. an empty pool without on-demand generation)
+var ErrNoParams = errors.New("no parameters returned from service")
+
+// ErrPoolEmpty is returned by GetPreParams for WithNoGenerate calls the
+// service could not serve from its pool
+var ErrPoolEmpty = errors.New("service pool is empty")
+
+// PoolEmptyReason is the ErrorInfo reason of the RESOURCE_EXHAUSTED status
+// the service returns for no_generate requests it cannot serve
+const PoolEmptyReason = "POOL_EMPTY"
+
+// QuotaExceededReason is the ErrorInfo reason of the FAILED_PRECONDITION
+// status the service returns once a namespace used up its hourly or daily quota
+const QuotaExceededReason = "QUOTA_EXCEEDED"
+
+// ResponseTooLargeReason is the ErrorInfo reason of the RESOURCE_EXHAUSTED
+// status the service returns for batches too large for one message
+const ResponseTooLargeReason = "RESPONSE_TOO_LARGE"
+
+// ExceedsPoolReason is the ErrorInfo reason of the INVALID_ARGUMENT status
+// the service returns for unary batches larger than its pool can hold
+const ExceedsPoolReason = "EXCEEDS_POOL_SIZE"
+
+// Client is a single-endpoint prime service client
+type Client struct {
+	conn    *grpc.ClientConn
+	client  pb.PrimeServiceClient
+	verify  VerifyLevel
+	minBits MinBits
+}
+
+// NewClient connects to the service at address. The connection is
+// insecure unless dialOpts supply transport credentials, e.g. from
+// TLSCredentials.
+func NewClient(address string, dialOpts ...grpc.DialOption) (*Client, error) {
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, dialOpts...)
+	conn, err := grpc.NewClient(address, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	return &Client{conn: conn, client: pb.NewPrimeServiceClient(conn)}, nil
+}
+
+// SetVerifyOnReceive makes GetPreParams verify every received item at level
+// before returning it, failing with ErrInvalidParams instead of handing out
+// inconsistent parameters. Call it before the client is used.
+func (c *Client) SetVerifyOnReceive(level VerifyLevel) {
+	c.verify = level
+}
+
+// SetMinBits makes GetPreParams reject received items shorter than req
+// requires, failing with an *UndersizedError. Call it before the client is
+// used.
+func (c *Client) SetMinBits(req MinBits) {
+	c.minBits = req
+}
+
+// Close closes the client connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// GetPreParams retrieves up to count parameter sets (default 1 if 0) with a
+// single RPC, honouring WithIdempotencyKey, WithDistinctProvenance,
+// WithNoGenerate, WithAllowImported and WithMinAssurance, and verifying items as set with SetVerifyOnReceive and SetMinBits.
+// Batches too large for one message are streamed.
+func (c *Client) GetPreParams(ctx context.Context, count uint32) ([]*PreParamsData, error) {
+	if count == 0 {
+		count = 1
+	}
+
+	key, ok := IdempotencyKey(ctx)
+	if !ok {
+		key = NewIdempotencyKey()
+	}
+
+	primeBits, paillierBits := BitSizes(ctx)
+	items, err := FetchPreParams(ctx, c.client, &pb.GetPreParamsRequest{
+		Count:              count,
+		IdempotencyKey:     key,
+		DistinctProvenance: DistinctProvenance(ctx),
+		NoGenerate:         NoGenerate(ctx),
+		AllowImported:      AllowImported(ctx),
+		MinAssurance:       MinAssurance(ctx),
+		DistinctSeconds:    DistinctSeconds(ctx),
+		PrimeBitSize:       primeBits,
+		PaillierBitSize:    paillierBits,
+	})
+	if err != nil {
+		return nil, WrapPoolEmpty(fmt.Errorf("failed to get pre-params: %w", err))
+	}
+	if len(items) == 0 {
+		return nil, ErrNoParams
+	}
+
+	result := make([]*PreParamsData, len(items))
+	for i, params := range items {
+		result[i] = FromProto(params)
+	}
+	if err := verifyAll(result, c.verify, c.minBits); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamPreParams retrieves up to count parameter sets (default 1 if 0) like
+// GetPreParams, but passes each to fn as soon as the service sends it, so
+// work can start on the first items while the rest are generated. Items are
+// checked as set with SetVerifyOnReceive and SetMinBits before fn sees them. An error from
+// fn ends the stream and is returned; items the service sent after it are
+// consumed, but a retry with the same idempotency key gets them back.
+func (c *Client) StreamPreParams(ctx context.Context, count uint32, fn func(*PreParamsData) error) error {
+	if count == 0 {
+		count = 1
+	}
+
+	key, ok := IdempotencyKey(ctx)
+	if !ok {
+		key = NewIdempotencyKey()
+	}
+
+	primeBits, paillierBits := BitSizes(ctx)
+	received := 0
+	err := StreamPreParams(ctx, c.client, &pb.GetPreParamsRequest{
+		Count:              count,
+		IdempotencyKey:     key,
+		DistinctProvenance: DistinctProvenance(ctx),
+		NoGenerate:         NoGenerate(ctx),
+		AllowImported:      AllowImported(ctx),
+		MinAssurance:       MinAssurance(ctx),
+		DistinctSeconds:    DistinctSeconds(ctx),
+		PrimeBitSize:       primeBits,
+		PaillierBitSize:    paillierBits,
+		ReportQueue:        QueueUpdates(ctx) != nil,
+	}, func(params *pb.PreParamsData) error {
+		item := FromProto(params)
+		if err := checkItem(item, c.verify, c.minBits); err != nil {
+			return fmt.Errorf("item %d: %w", received, err)
+		}
+		received++
+		return fn(item)
+	})
+	if err != nil {
+		return WrapPoolEmpty(err)
+	}
+	if received == 0 {
+		return ErrNoParams
+	}
+	return nil
+}
+
+// WaitForPreParams waits server-side until the service can serve all count
+// parameter sets (default 1 if 0) from its pool and takes them at once, up to
+// timeout (0: until the ctx deadline), failing with ErrPoolEmpty otherwise.
+// It honours WithIdempotencyKey, WithDistinctProvenance, WithAllowImported,
+// WithMinAssurance and WithDistinctSeconds, and checks items as set with SetVerifyOnReceive and SetMinBits.
+func (c *Client) WaitForPreParams(ctx context.Context, count uint32, timeout time.Duration) ([]*PreParamsData, error) {
+	if count == 0 {
+		count = 1
+	}
+
+	key, ok := IdempotencyKey(ctx)
+	if !ok {
+		key = NewIdempotencyKey()
+	}
+
+	resp, err := c.client.WaitForPreParams(ctx, &pb.WaitForPreParamsRequest{
+		Count:              count,
+		TimeoutMs:          uint32(timeout.Milliseconds()),
+		IdempotencyKey:     key,
+		DistinctProvenance: DistinctProvenance(ctx),
+		AllowImported:      AllowImported(ctx),
+		MinAssurance:       MinAssurance(ctx),
+		DistinctSeconds:    DistinctSeconds(ctx),
+	})
+	if err != nil {
+		return nil, WrapPoolEmpty(fmt.Errorf("failed to wait for pre-params: %w", err))
+	}
+	if len(resp.Params) == 0 {
+		return nil, ErrNoParams
+	}
+
+	result := make([]*PreParamsData, len(resp.Params))
+	for i, params := range resp.Params {
+		result[i] = FromProto(params)
+	}
+	if err := verifyAll(result, c.verify, c.minBits); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// HealthCheck returns the service health status
+func (c *Client) HealthCheck(ctx context.Context) (*pb.HealthStatus, error) {
+	return c.client.HealthCheck(ctx, &pb.Empty{})
+}
+
+// GetPoolStatus gets the current pool status
+func (c *Client) GetPoolStatus(ctx context.Context) (*pb.PoolStatus, error) {
+	return c.client.GetPoolStatus(ctx, &pb.Empty{})
+}
+
+// FromProto converts a protobuf parameter set
+func FromProto(params *pb.PreParamsData) *PreParamsData {
+	return &PreParamsData{
+		PaillierN:       new(big.Int).SetBytes(params.PaillierN),
+		PaillierLambdaN: new(big.Int).SetBytes(params.PaillierLambdaN),
+		PaillierPhiN:    new(big.Int).SetBytes(params.PaillierPhiN),
+		PaillierP:       new(big.Int).SetBytes(params.PaillierP),
+		PaillierQ:       new(big.Int).SetBytes(params.PaillierQ),
+		NTildei:         new(big.Int).SetBytes(params.NTildei),
+		H1i:             new(big.Int).SetBytes(params.H1I),
+		H2i:             new(big.Int).SetBytes(params.H2I),
+		Alpha:           new(big.Int).SetBytes(params.Alpha),
+		Beta:            new(big.Int).SetBytes(params.Beta),
+		P:               new(big.Int).SetBytes(params.P),
+		Q:               new(big.Int).SetBytes(params.Q),
+		GeneratedAt:     time.Unix(params.GeneratedAt, 0),
+		Metadata:        MetadataFromProto(params.Metadata),
+	}
+}
+
+// MetadataFromProto converts protobuf item metadata
+func MetadataFromProto(m *pb.ItemMetadata) ItemMetadata {
+	return ItemMetadata{
+		FromPool:           m.GetSource() != pb.ItemSource_ITEM_SOURCE_GENERATED,
+		PoolAge:            time.Duration(m.GetPoolAgeMs()) * time.Millisecond,
+		GenerationDuration: time.Duration(m.GetGenerationDurationMs()) * time.Millisecond,
+		Replayed:           m.GetReplayed(),
+		Stale:              m.GetStale(),
+		SharedSecond:       m.GetSharedSecond(),
+		Assurance:          m.GetAssurance(),
+		Provenance: Provenance{
+			Seq:      m.GetProvenance().GetSeq(),
+			Instance: m.GetProvenance().GetInstance(),
+			Host:     m.GetProvenance().GetHost(),
+			Burst:    m.GetProvenance().GetBurst(),
+			Worker:   int(m.GetProvenance().GetWorker()),
+			Imported: m.GetProvenance().GetImported(),
+			Version:  m.GetProvenance().GetVersion(),
+		},
+	}
+}
+
+// FetchPreParams calls GetPreParams and, if the service finds the batch too
+// large for one message or its pool, repeats the request with
+// StreamPreParams. The service refuses oversized batches before consuming
+// anything, so nothing is lost by switching; a stream failing midway is
+// safe to retry with the same idempotency key.
+func FetchPreParams(ctx context.Context, client pb.PrimeServiceClient, req *pb.GetPreParamsRequest) ([]*pb.PreParamsData, error) {
+	resp, err := client.GetPreParams(ctx, req)
+	if err == nil {
+		return resp.Params, nil
+	}
+	if !hasReason(err, codes.ResourceExhausted, ResponseTooLargeReason) &&
+		!hasReason(err, codes.InvalidArgument, ExceedsPoolReason) {
+		return nil, err
+	}
+
+	stream, err := client.StreamPreParams(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var params []*pb.PreParamsData
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return params, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, resp.Params...)
+	}
+}
+
+// StreamPreParams calls StreamPreParams and passes each item to fn as it
+// arrives, and queue reports to the function attached with
+// WithQueueUpdates. An error from fn ends the stream and is returned
+// unchanged; RPC errors are wrapped.
+func StreamPreParams(ctx context.Context, client pb.PrimeServiceClient, req *pb.GetPreParamsRequest, fn func(*pb.PreParamsData) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.StreamPreParams(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to stream pre-params: %w", err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stream pre-params: %w", err)
+		}
+		if q := resp.Queue; q != nil {
+			if report := QueueUpdates(ctx); report != nil {
+				report(QueueStatus{Position: int(q.Position), ETA: time.Duration(q.EtaSeconds) * time.Second})
+			}
+		}
+		for _, params := range resp.Params {
+			if err := fn(params); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// WrapPoolEmpty makes err match ErrPoolEmpty with errors.Is if it carries
+// the service's POOL_EMPTY status, and returns other errors unchanged
+func WrapPoolEmpty(err error) error {
+	if hasReason(err, codes.ResourceExhausted, PoolEmptyReason) {
+		return fmt.Errorf("%w: %w", ErrPoolEmpty, err)
+	}
+	return err
+}
+
+// hasReason reports whether err is a status with code carrying an ErrorInfo
+// detail with reason
+func hasReason(err error, code codes.Code, reason string) bool {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != code {
+		return false
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
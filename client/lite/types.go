@@ -0,0 +1,58 @@
+package lite
+
+import (
+	"math/big"
+	"time"
+)
+
+// PreParamsData contains the pre-computed parameters for ECDSA DKG as plain
+// integers. Callers that need a tss-lib Paillier key can build one from the
+// Paillier* fields.
+type PreParamsData struct {
+	PaillierN       *big.Int
+	PaillierLambdaN *big.Int
+	PaillierPhiN    *big.Int
+	PaillierP       *big.Int
+	PaillierQ       *big.Int
+	NTildei         *big.Int
+	H1i             *big.Int
+	H2i             *big.Int
+	Alpha           *big.Int
+	Beta            *big.Int
+	P               *big.Int // safe prime for NTildei
+	Q               *big.Int // safe prime for NTildei
+	GeneratedAt     time.Time
+
+	// Metadata describes how the service provisioned this set
+	Metadata ItemMetadata
+}
+
+// ItemMetadata contains per-item provisioning telemetry reported by the service
+type ItemMetadata struct {
+	FromPool           bool          // Served from the pre-computed pool (false: generated on demand)
+	PoolAge            time.Duration // Time since generation when served from the pool
+	GenerationDuration time.Duration // Time the service spent generating the item
+	Replayed           bool          // Returned again for a retried idempotency key
+	Stale              bool          // Older than the service's max age (served under its serve stale policy)
+	SharedSecond       bool          // Generated in the same second as another item of a WithDistinctSeconds batch
+	Provenance         Provenance    // Generation run the item came from
+	Assurance          string        // Assurance level the item passed: standard or audited
+}
+
+// Provenance identifies the instance, host, burst and worker that generated an item
+type Provenance struct {
+	Seq      uint64 // Sequence number in the serving pool, e.g. item 4182
+	Instance string
+	Host     string
+	Burst    string
+	Worker   int
+	Imported bool   // Came from elsewhere and was fully revalidated on import
+	Version  string // Version of the generation code ("": not recorded)
+}
+
+// QueueStatus is the place of a streaming request waiting for one of the
+// service's on-demand generation slots
+type QueueStatus struct {
+	Position int           // Requests ahead plus one; 0 once generating
+	ETA      time.Duration // Estimated wait for a slot; negative if unknown
+}
@@ -0,0 +1,76 @@
+package lite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/TEENet-io/prime-service/internal/paramcheck"
+)
+
+// ErrInvalidParams is returned when received parameters fail verification
+var ErrInvalidParams = errors.New("received parameters failed verification")
+
+// VerifyLevel selects how thoroughly received parameters are checked
+type VerifyLevel int
+
+const (
+	// VerifyNone trusts the service
+	VerifyNone VerifyLevel = iota
+	// VerifyFast runs the algebraic checks the service itself applies:
+	// completeness, Paillier modulus and totients, NTildei against its safe
+	// primes, and the h1/h2/alpha/beta relations. Microseconds per item.
+	VerifyFast
+	// VerifyFull additionally tests every prime for primality, which takes
+	// tens to hundreds of milliseconds per item at production bit sizes
+	VerifyFull
+)
+
+// Verify checks p at the given level, returning an error matching
+// ErrInvalidParams if it fails
+func (p *PreParamsData) Verify(level VerifyLevel) error {
+	if level == VerifyNone {
+		return nil
+	}
+
+	params := paramcheck.Params{
+		PaillierN:       p.PaillierN,
+		PaillierP:       p.PaillierP,
+		PaillierQ:       p.PaillierQ,
+		PaillierPhiN:    p.PaillierPhiN,
+		PaillierLambdaN: p.PaillierLambdaN,
+		NTildei:         p.NTildei,
+		H1i:             p.H1i,
+		H2i:             p.H2i,
+		Alpha:           p.Alpha,
+		Beta:            p.Beta,
+		P:               p.P,
+		Q:               p.Q,
+	}
+	check := paramcheck.Check
+	if level >= VerifyFull {
+		check = paramcheck.CheckPrimality
+	}
+	if err := check(params); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParams, err)
+	}
+	return nil
+}
+
+// checkItem checks the bit lengths of item against req, then verifies it at
+// level
+func checkItem(item *PreParamsData, level VerifyLevel, req MinBits) error {
+	if err := item.CheckBits(req); err != nil {
+		return err
+	}
+	return item.Verify(level)
+}
+
+// verifyAll checks every item, naming the first one that fails
+func verifyAll(items []*PreParamsData, level VerifyLevel, req MinBits) error {
+	for i, item := range items {
+		if err := checkItem(item, level, req); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return nil
+}
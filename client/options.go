@@ -0,0 +1,149 @@
+package client
+
+import (
+	"time"
+)
+
+// Hooks are optional instrumentation callbacks invoked by the client so
+// applications can feed their own metrics/tracing setup. Any hook may be nil.
+// Hooks are called synchronously and must not block.
+type Hooks struct {
+	// OnRequest is called once per client call before the first attempt
+	OnRequest func(method string)
+	// OnRetry is called before each retry of a failed attempt
+	OnRetry func(method string, attempt int, err error)
+	// OnFallback is called when a call moves from one endpoint to another
+	OnFallback func(method string, from, to string, err error)
+	// ObserveLatency is called once per client call with its total duration
+	ObserveLatency func(method string, latency time.Duration, err error)
+}
+
+// Option configures a PrimeServiceClient
+type Option func(*options)
+
+type options struct {
+	hooks        Hooks
+	maxAttempts  int
+	retryBackoff time.Duration
+	fallbacks    []string
+	region       string
+	replicas     []replicaAddress
+
+	keepaliveTime    time.Duration
+	keepaliveTimeout time.Duration
+	healthInterval   time.Duration
+	healthTimeout    time.Duration
+
+	maxChunk uint32
+
+	verify  VerifyLevel
+	minBits MinBits
+
+	// Transport security, see WithTLS and WithMTLS
+	tls                    bool
+	tlsCA, tlsCert, tlsKey string
+
+	apiKey string
+}
+
+func defaultOptions() options {
+	return options{
+		maxAttempts:   1,
+		retryBackoff:  500 * time.Millisecond,
+		healthTimeout: 5 * time.Second,
+	}
+}
+
+// WithHooks installs instrumentation callbacks
+func WithHooks(hooks Hooks) Option {
+	return func(o *options) {
+		o.hooks = hooks
+	}
+}
+
+// WithRetry retries transient failures (Unavailable, Aborted, and
+// ResourceExhausted carrying a RetryInfo) up to maxAttempts per endpoint,
+// doubling backoff between attempts and waiting at least the delay the
+// server asks for
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		if maxAttempts > 0 {
+			o.maxAttempts = maxAttempts
+		}
+		if backoff > 0 {
+			o.retryBackoff = backoff
+		}
+	}
+}
+
+// WithFallback adds secondary service addresses tried in order when the
+// primary address keeps failing
+func WithFallback(addresses ...string) Option {
+	return func(o *options) {
+		o.fallbacks = append(o.fallbacks, addresses...)
+	}
+}
+
+// WithKeepalive sends gRPC keepalive pings every interval (even without
+// active RPCs) and drops the connection if a ping is not answered within
+// timeout, so dead connections behind NAT are detected before the next call.
+// The server permits pings as frequent as every 10 seconds.
+func WithKeepalive(interval, timeout time.Duration) Option {
+	return func(o *options) {
+		o.keepaliveTime = interval
+		o.keepaliveTimeout = timeout
+	}
+}
+
+// WithHealthCheck starts a background monitor that verifies every endpoint
+// with a HealthCheck RPC each interval, reconnecting broken connections
+func WithHealthCheck(interval time.Duration) Option {
+	return func(o *options) {
+		o.healthInterval = interval
+	}
+}
+
+// WithBatchSplitting splits GetPreParams calls that carry a context deadline
+// into RPCs of at most maxChunk items, each sized from the measured per-item
+// service latency so every RPC fits in the remaining budget
+func WithBatchSplitting(maxChunk uint32) Option {
+	return func(o *options) {
+		o.maxChunk = maxChunk
+	}
+}
+
+// WithVerifyOnReceive makes GetPreParams verify every received item before
+// returning it, protecting signers from a buggy or compromised service:
+// VerifyFast runs the service's own consistency checks, VerifyFull also
+// tests every prime for primality. A failing call returns ErrInvalidParams
+// and no items.
+func WithVerifyOnReceive(level VerifyLevel) Option {
+	return func(o *options) {
+		o.verify = level
+	}
+}
+
+// WithTLS connects to the service over TLS, verifying its certificate
+// against the PEM bundle caFile (empty: the system roots)
+func WithTLS(caFile string) Option {
+	return func(o *options) {
+		o.tls, o.tlsCA = true, caFile
+	}
+}
+
+// WithMTLS connects over TLS as WithTLS does and presents the client
+// certificate in certFile and keyFile, for services requiring mutual TLS
+func WithMTLS(caFile, certFile, keyFile string) Option {
+	return func(o *options) {
+		o.tls, o.tlsCA, o.tlsCert, o.tlsKey = true, caFile, certFile, keyFile
+	}
+}
+
+// WithAPIKey sends key with every call, for services requiring API keys.
+// Combine it with WithTLS outside trusted networks, as the key travels in
+// call metadata.
+func WithAPIKey(key string) Option {
+	return func(o *options) {
+		o.apiKey = key
+	}
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+)
+
+// WithDistinctProvenance marks GetPreParams calls on ctx as ceremony batches:
+// the service only returns items with mutually distinct provenance (a
+// different host or generation burst), so no two parties of one ceremony get
+// parameters from the same worker run. The service may return fewer items
+// than requested. Such calls are never split across RPCs.
+func WithDistinctProvenance(ctx context.Context) context.Context {
+	return lite.WithDistinctProvenance(ctx)
+}
+
+// WithNoGenerate marks GetPreParams calls on ctx as latency-sensitive: the
+// service never generates parameters synchronously for them and fails at
+// once if its pool has nothing suitable. The client then tries its fallback
+// endpoints and finally returns ErrPoolEmpty. Fewer items than requested may
+// be returned.
+func WithNoGenerate(ctx context.Context) context.Context {
+	return lite.WithNoGenerate(ctx)
+}
+
+// WithAllowImported lets GetPreParams calls on ctx also receive imported
+// items that the service demotes to lower assurance (old items restored
+// from elsewhere; their Provenance has Imported set)
+func WithAllowImported(ctx context.Context) context.Context {
+	return lite.WithAllowImported(ctx)
+}
+
+// WithDistinctSeconds asks GetPreParams calls on ctx to spread their batch
+// across items generated in distinct seconds where the pool allows it, a
+// cheaper alternative to WithDistinctProvenance that still returns the full
+// count. Items that could not be spread have ItemMetadata.SharedSecond set.
+// Such calls are never split across RPCs.
+func WithDistinctSeconds(ctx context.Context) context.Context {
+	return lite.WithDistinctSeconds(ctx)
+}
+
+// WithMinAssurance asks GetPreParams calls on ctx for items of at least the
+// given assurance level: "standard" (any item) or "audited" (items that also
+// passed the service's full primality validation). ItemMetadata.Assurance
+// reports each item's level.
+func WithMinAssurance(ctx context.Context, level string) context.Context {
+	return lite.WithMinAssurance(ctx, level)
+}
+
+// WithBitSizes asks GetPreParams calls on ctx for parameters of the given
+// bit sizes (0 keeps the service's size). The service routes such calls to
+// the pool serving these sizes or generates them synchronously; it rejects
+// sizes it does not allow with InvalidArgument.
+func WithBitSizes(ctx context.Context, primeBits, paillierBits uint32) context.Context {
+	return lite.WithBitSizes(ctx, primeBits, paillierBits)
+}
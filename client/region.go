@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// replicaAddress is a service address with the region it runs in
+type replicaAddress struct {
+	address string
+	region  string
+}
+
+// WithRegion sets the region the client runs in. Calls try the endpoints in
+// that region first, in configured order, and fail over to the others only
+// when all of them keep failing, keeping large parameter payloads off WAN
+// links. Endpoints added without a region (the primary and WithFallback
+// addresses) are placed by the region they report: NewClient asks them in
+// the background, and the health monitor (WithHealthCheck) keeps asking.
+func WithRegion(region string) Option {
+	return func(o *options) {
+		o.region = region
+	}
+}
+
+// WithReplicas adds service addresses in the given region, tried after the
+// primary and WithFallback addresses unless WithRegion prefers them
+func WithReplicas(region string, addresses ...string) Option {
+	return func(o *options) {
+		for _, addr := range addresses {
+			o.replicas = append(o.replicas, replicaAddress{address: addr, region: region})
+		}
+	}
+}
+
+// currentRegion returns the region of an endpoint: the one configured with
+// WithReplicas, else the one the service reported
+func (ep *endpoint) currentRegion() string {
+	if ep.region != "" {
+		return ep.region
+	}
+	if r := ep.reported.Load(); r != nil {
+		return *r
+	}
+	return ""
+}
+
+// learnRegion records the region a service reported
+func (ep *endpoint) learnRegion(region string) {
+	if region != "" {
+		ep.reported.Store(&region)
+	}
+}
+
+// order returns the endpoints in the order calls try them: those in the
+// client's region first, then the rest, each in configured order (with the
+// addresses given to NewClient rotated if spread), and within that those up
+// before those recently failing
+func (c *PrimeServiceClient) order(spread bool) []*endpoint {
+	endpoints := c.endpoints
+	if spread {
+		endpoints = c.spread()
+	}
+	if c.opts.region == "" {
+		return c.upFirst(endpoints)
+	}
+	local := make([]*endpoint, 0, len(endpoints))
+	var remote []*endpoint
+	for _, ep := range endpoints {
+		if ep.currentRegion() == c.opts.region {
+			local = append(local, ep)
+		} else {
+			remote = append(remote, ep)
+		}
+	}
+	return c.upFirst(append(local, remote...))
+}
+
+// discoverRegions asks the endpoints without a configured region for the
+// region they run in, until the client is closed
+func (c *PrimeServiceClient) discoverRegions() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for _, ep := range c.endpoints {
+		if ep.region != "" {
+			continue
+		}
+		wg.Add(1)
+		go func(ep *endpoint) {
+			defer wg.Done()
+			c.verify(ctx, ep)
+		}(ep)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+	pb "github.com/TEENet-io/prime-service/proto"
+)
+
+// StreamPreParams retrieves up to count parameter sets (default 1 if 0) like
+// GetPreParams, but passes each to fn as soon as the service sends it, so a
+// DKG can start on the first items while the rest are generated. Items are
+// checked as set with WithVerifyOnReceive and WithMinBits before fn sees them. A stream
+// broken midway is retried with the same idempotency key, which replays the
+// batch; fn only sees the items it has not seen yet. An error from fn ends
+// the stream and is returned. WithQueueUpdates reports the call's place in
+// the service's on-demand generation queue while it waits.
+func (c *PrimeServiceClient) StreamPreParams(ctx context.Context, count uint32, fn func(*PreParamsData) error) error {
+	if count == 0 {
+		count = 1
+	}
+	key, ok := lite.IdempotencyKey(ctx)
+	if !ok {
+		key = lite.NewIdempotencyKey()
+	}
+
+	delivered := 0
+	var fnErr error
+	primeBits, paillierBits := lite.BitSizes(ctx)
+	err := c.call(ctx, "StreamPreParams", func(ctx context.Context, ep *endpoint) error {
+		received := 0
+		return lite.StreamPreParams(ctx, ep.client, &pb.GetPreParamsRequest{
+			Count:              count,
+			IdempotencyKey:     key,
+			DistinctProvenance: lite.DistinctProvenance(ctx),
+			NoGenerate:         lite.NoGenerate(ctx),
+			AllowImported:      lite.AllowImported(ctx),
+			MinAssurance:       lite.MinAssurance(ctx),
+			DistinctSeconds:    lite.DistinctSeconds(ctx),
+			PrimeBitSize:       primeBits,
+			PaillierBitSize:    paillierBits,
+			ReportQueue:        lite.QueueUpdates(ctx) != nil,
+		}, func(params *pb.PreParamsData) error {
+			// A retry replays the batch from its first item
+			received++
+			if received <= delivered {
+				return nil
+			}
+			item := fromProto([]*pb.PreParamsData{params})[0]
+			if err := checkItem(item, c.opts.verify, c.opts.minBits); err != nil {
+				fnErr = fmt.Errorf("item %d: %w", delivered, err)
+				return fnErr
+			}
+			delivered++
+			if err := fn(item); err != nil {
+				fnErr = err
+				return err
+			}
+			return nil
+		})
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return lite.WrapPoolEmpty(err)
+	}
+	if delivered == 0 {
+		return ErrNoParams
+	}
+	return nil
+}
+
+// StreamPreParamsChan is StreamPreParams delivering the items on a channel,
+// for pipelines that hand each item to a DKG as it arrives. The items
+// channel is closed once the stream ends; the error channel then yields
+// the result of the call (nil on success) and is closed. Broken streams are
+// retried as by StreamPreParams. Cancel ctx to abandon the call early:
+// the stream waits for each item to be received.
+func (c *PrimeServiceClient) StreamPreParamsChan(ctx context.Context, count uint32) (<-chan *PreParamsData, <-chan error) {
+	items := make(chan *PreParamsData)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		err := c.StreamPreParams(ctx, count, func(item *PreParamsData) error {
+			select {
+			case items <- item:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(items)
+		errs <- err
+	}()
+	return items, errs
+}
+
+// WithQueueUpdates asks StreamPreParams calls on ctx to pass their place in
+// the service's on-demand generation queue to fn whenever it changes while
+// they wait for a slot (pool.max_on_demand). GetPreParams calls cannot
+// report it.
+func WithQueueUpdates(ctx context.Context, fn func(QueueStatus)) context.Context {
+	return lite.WithQueueUpdates(ctx, fn)
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"context"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+)
+
+// RequestIDHeader carries the trace ID the service prefixes its log lines,
+// error journal entries and audit records with
+const RequestIDHeader = lite.RequestIDHeader
+
+// PoolHeader selects a named pool of a service serving several
+const PoolHeader = lite.PoolHeader
+
+// NamespaceHeader names the tenant namespace calls are charged to
+const NamespaceHeader = lite.NamespaceHeader
+
+// WithRequestID tags calls made with ctx with a caller-chosen trace ID
+// (letters, digits and "-_.:", at most 64 characters), so service logs can
+// be correlated with the caller's own. Without one the service assigns an ID
+// and returns it in the x-request-id response header.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return lite.WithRequestID(ctx, id)
+}
+
+// WithPool sends calls made with ctx to the named pool of a service serving
+// several (e.g. "staging" and "production" parameter classes). The service
+// rejects unknown names with NotFound rather than falling back to another
+// pool. Without it the service's default pool answers.
+func WithPool(ctx context.Context, name string) context.Context {
+	return lite.WithPool(ctx, name)
+}
+
+// WithNamespace charges the items of calls made with ctx to a tenant
+// namespace of a service shared by several clients, counting them against
+// its quotas. The service rejects unknown namespaces with NotFound, and
+// calls with an API key bound to another namespace with PermissionDenied.
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return lite.WithNamespace(ctx, namespace)
+}
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/TEENet-io/prime-service/client/lite"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
 )
 
@@ -18,4 +19,17 @@ type PreParamsData struct {
 	P           *big.Int // safe prime for NTildei
 	Q           *big.Int // safe prime for NTildei
 	GeneratedAt time.Time
-}
\ No newline at end of file
+
+	// Metadata describes how the service provisioned this set
+	Metadata ItemMetadata
+}
+
+// ItemMetadata contains per-item provisioning telemetry reported by the service
+type ItemMetadata = lite.ItemMetadata
+
+// Provenance identifies the instance, host, burst and worker that generated an item
+type Provenance = lite.Provenance
+
+// QueueStatus is the place of a streaming request waiting for one of the
+// service's on-demand generation slots
+type QueueStatus = lite.QueueStatus
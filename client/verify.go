@@ -0,0 +1,69 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+)
+
+// ErrInvalidParams is returned when received parameters fail verification
+var ErrInvalidParams = lite.ErrInvalidParams
+
+// VerifyLevel selects how thoroughly received parameters are checked
+type VerifyLevel = lite.VerifyLevel
+
+// Verification levels, see WithVerifyOnReceive
+const (
+	VerifyNone = lite.VerifyNone
+	VerifyFast = lite.VerifyFast
+	VerifyFull = lite.VerifyFull
+)
+
+// Verify checks p at the given level, returning an error matching
+// ErrInvalidParams if it fails
+func (p *PreParamsData) Verify(level VerifyLevel) error {
+	return p.lite().Verify(level)
+}
+
+// lite returns the integers of p in the lightweight client's format
+func (p *PreParamsData) lite() *lite.PreParamsData {
+	l := &lite.PreParamsData{
+		NTildei: p.NTildei,
+		H1i:     p.H1i,
+		H2i:     p.H2i,
+		Alpha:   p.Alpha,
+		Beta:    p.Beta,
+		P:       p.P,
+		Q:       p.Q,
+	}
+	if p.PaillierKey != nil {
+		l.PaillierN = p.PaillierKey.N
+		l.PaillierP = p.PaillierKey.P
+		l.PaillierQ = p.PaillierKey.Q
+		l.PaillierPhiN = p.PaillierKey.PhiN
+		l.PaillierLambdaN = p.PaillierKey.LambdaN
+	}
+	return l
+}
+
+// checkItem checks the bit lengths of item against req, then verifies it at
+// level
+func checkItem(item *PreParamsData, level VerifyLevel, req MinBits) error {
+	if err := item.CheckBits(req); err != nil {
+		return err
+	}
+	return item.Verify(level)
+}
+
+// verifyAll checks every item, naming the first one that fails
+func verifyAll(items []*PreParamsData, level VerifyLevel, req MinBits) error {
+	if level == VerifyNone && req == (MinBits{}) {
+		return nil
+	}
+	for i, item := range items {
+		if err := checkItem(item, level, req); err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/TEENet-io/prime-service/internal/generator"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+)
+
+// testItem returns a freshly generated item at small bit sizes
+func testItem(t *testing.T) *PreParamsData {
+	t.Helper()
+	p, err := generator.NewGenerator().GeneratePreParams(256, 512)
+	if err != nil {
+		t.Fatalf("failed to generate test item: %v", err)
+	}
+	return &PreParamsData{
+		PaillierKey: p.PaillierKey,
+		NTildei:     p.NTildei,
+		H1i:         p.H1i,
+		H2i:         p.H2i,
+		Alpha:       p.Alpha,
+		Beta:        p.Beta,
+		P:           p.P,
+		Q:           p.Q,
+	}
+}
+
+func TestVerify(t *testing.T) {
+	valid := testItem(t)
+	tests := []struct {
+		name     string
+		tamper   func(p *PreParamsData)
+		wantFast bool // Fails VerifyFast
+		wantFull bool // Fails VerifyFull
+	}{
+		{name: "valid"},
+		{name: "missing component", tamper: func(p *PreParamsData) { p.Beta = nil }, wantFast: true, wantFull: true},
+		{name: "tampered NTildei", tamper: func(p *PreParamsData) {
+			p.NTildei = new(big.Int).Add(p.NTildei, big.NewInt(2))
+		}, wantFast: true, wantFull: true},
+		{name: "swapped h1 and h2", tamper: func(p *PreParamsData) { p.H1i, p.H2i = p.H2i, p.H1i }, wantFast: true, wantFull: true},
+		{name: "tampered paillier totient", tamper: func(p *PreParamsData) {
+			key := *p.PaillierKey
+			key.PhiN = new(big.Int).Add(key.PhiN, big.NewInt(1))
+			p.PaillierKey = &key
+		}, wantFast: true, wantFull: true},
+		// N = 1 * N passes the algebra, only the primality tests catch it
+		{name: "composite paillier factors", tamper: func(p *PreParamsData) {
+			p.PaillierKey = &paillier.PrivateKey{
+				PublicKey: p.PaillierKey.PublicKey,
+				P:         big.NewInt(1),
+				Q:         p.PaillierKey.N,
+			}
+		}, wantFull: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := *valid
+			if tt.tamper != nil {
+				tt.tamper(&item)
+			}
+			if err := item.Verify(VerifyNone); err != nil {
+				t.Fatalf("Verify(VerifyNone) = %v", err)
+			}
+			for _, c := range []struct {
+				level VerifyLevel
+				want  bool
+			}{{VerifyFast, tt.wantFast}, {VerifyFull, tt.wantFull}} {
+				err := item.Verify(c.level)
+				if (err != nil) != c.want {
+					t.Fatalf("Verify(%d) = %v, want failure %v", c.level, err, c.want)
+				}
+				if err != nil && !errors.Is(err, ErrInvalidParams) {
+					t.Fatalf("Verify(%d) = %v, want it to match ErrInvalidParams", c.level, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyAll(t *testing.T) {
+	valid := testItem(t)
+	tampered := *valid
+	tampered.Alpha = new(big.Int).Add(tampered.Alpha, big.NewInt(1))
+	items := []*PreParamsData{valid, &tampered}
+
+	if err := verifyAll(items, VerifyNone, MinBits{}); err != nil {
+		t.Fatalf("verifyAll(VerifyNone) = %v", err)
+	}
+	err := verifyAll(items, VerifyFast, MinBits{})
+	if !errors.Is(err, ErrInvalidParams) || !strings.Contains(err.Error(), "item 1") {
+		t.Fatalf("verifyAll(VerifyFast) = %v, want item 1 to fail verification", err)
+	}
+
+	var undersized *UndersizedError
+	err = verifyAll(items[:1], VerifyNone, MinBits{NTilde: 2048})
+	if !errors.As(err, &undersized) || undersized.Param != "NTildei" {
+		t.Fatalf("verifyAll() with MinBits = %v, want an undersized NTildei", err)
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+	pb "github.com/TEENet-io/prime-service/proto"
+)
+
+// WaitForPreParams waits server-side until the service can serve all count
+// parameter sets (default 1 if 0) from its pool and takes them at once, up to
+// timeout per endpoint (0: until the ctx deadline). The service never
+// generates synchronously for it. If an endpoint cannot serve them in time,
+// the fallback endpoints are tried, and finally ErrPoolEmpty is returned.
+// WithIdempotencyKey, WithDistinctProvenance, WithAllowImported,
+// WithMinAssurance and WithDistinctSeconds apply as for GetPreParams.
+func (c *PrimeServiceClient) WaitForPreParams(ctx context.Context, count uint32, timeout time.Duration) ([]*PreParamsData, error) {
+	if count == 0 {
+		count = 1
+	}
+	key, ok := lite.IdempotencyKey(ctx)
+	if !ok {
+		key = lite.NewIdempotencyKey()
+	}
+
+	var items []*pb.PreParamsData
+	err := c.call(ctx, "WaitForPreParams", func(ctx context.Context, ep *endpoint) error {
+		resp, err := ep.client.WaitForPreParams(ctx, &pb.WaitForPreParamsRequest{
+			Count:              count,
+			TimeoutMs:          uint32(timeout.Milliseconds()),
+			IdempotencyKey:     key,
+			DistinctProvenance: lite.DistinctProvenance(ctx),
+			AllowImported:      lite.AllowImported(ctx),
+			MinAssurance:       lite.MinAssurance(ctx),
+			DistinctSeconds:    lite.DistinctSeconds(ctx),
+		})
+		items = resp.GetParams()
+		return err
+	})
+	if err != nil {
+		return nil, lite.WrapPoolEmpty(fmt.Errorf("failed to wait for pre-params: %w", err))
+	}
+	if len(items) == 0 {
+		return nil, ErrNoParams
+	}
+
+	result := fromProto(items)
+	if err := verifyAll(result, c.opts.verify, c.opts.minBits); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,182 @@
+package web
+
+import (
+	"math/big"
+	"time"
+)
+
+// PreParamsData contains the pre-computed parameters for ECDSA DKG as plain
+// integers, as in package client/lite
+type PreParamsData struct {
+	PaillierN       *big.Int
+	PaillierLambdaN *big.Int
+	PaillierPhiN    *big.Int
+	PaillierP       *big.Int
+	PaillierQ       *big.Int
+	NTildei         *big.Int
+	H1i             *big.Int
+	H2i             *big.Int
+	Alpha           *big.Int
+	Beta            *big.Int
+	P               *big.Int // safe prime for NTildei
+	Q               *big.Int // safe prime for NTildei
+	GeneratedAt     time.Time
+
+	// Metadata describes how the service provisioned this set
+	Metadata ItemMetadata
+}
+
+// ItemMetadata contains per-item provisioning telemetry reported by the service
+type ItemMetadata struct {
+	FromPool           bool          // Served from the pre-computed pool (false: generated on demand)
+	PoolAge            time.Duration // Time since generation when served from the pool
+	GenerationDuration time.Duration // Time the service spent generating the item
+	Replayed           bool          // Returned again for a retried idempotency key
+	Stale              bool          // Older than the service's max age (served under its serve stale policy)
+	SharedSecond       bool          // Generated in the same second as another item of a DistinctSeconds batch
+	Provenance         Provenance    // Generation run the item came from
+	Assurance          string        // Assurance level the item passed: standard or audited
+}
+
+// Provenance identifies the instance, host, burst and worker that generated an item
+type Provenance struct {
+	Seq      uint64 `json:"seq,string"` // Sequence number in the serving pool, e.g. item 4182
+	Instance string `json:"instance"`
+	Host     string `json:"host"`
+	Burst    string `json:"burst"`
+	Worker   int    `json:"worker"`
+	Imported bool   `json:"imported"` // Came from elsewhere and was fully revalidated on import
+	Version  string `json:"version"`  // Version of the generation code ("": not recorded)
+}
+
+// PreParamsRequest selects the items GetPreParams returns
+type PreParamsRequest struct {
+	Count uint32 // Items wanted (default 1 if 0)
+
+	// IdempotencyKey makes retries return the originally allocated items
+	// (empty: a fresh key per call, see NewIdempotencyKey)
+	IdempotencyKey string
+
+	// DistinctProvenance asks for a ceremony batch: items with mutually
+	// distinct host or generation burst. Fewer items may be returned.
+	DistinctProvenance bool
+
+	// NoGenerate fails with ErrPoolEmpty at once instead of generating
+	// synchronously when the pool has nothing suitable
+	NoGenerate bool
+
+	// AllowImported also accepts imported items the service serves only on
+	// request
+	AllowImported bool
+
+	// DistinctSeconds spreads the batch across items generated in distinct
+	// seconds where possible; items that could not be spread have
+	// ItemMetadata.SharedSecond set
+	DistinctSeconds bool
+
+	// MinAssurance only accepts items of at least this assurance level:
+	// "standard" (any item, the default) or "audited"
+	MinAssurance string
+
+	// PrimeBitSize and PaillierBitSize ask for parameters of other bit
+	// sizes than the service's (0 keeps its size)
+	PrimeBitSize    uint32
+	PaillierBitSize uint32
+}
+
+// HealthStatus is the service health
+type HealthStatus struct {
+	Healthy       bool     `json:"healthy"`
+	Message       string   `json:"message"`
+	UptimeSeconds int64    `json:"uptimeSeconds,string"`
+	InstanceID    string   `json:"instanceId"`
+	Warnings      []string `json:"warnings"`
+	Region        string   `json:"region"` // Locality of the instance (empty: not configured)
+	Zone          string   `json:"zone"`
+}
+
+// PoolStatus is the status of the pool answering the call
+type PoolStatus struct {
+	Pools           map[string]PoolInfo `json:"pools"`
+	TotalGenerated  int64               `json:"totalGenerated,string"`
+	TotalServed     int64               `json:"totalServed,string"`
+	SelectionPolicy string              `json:"selectionPolicy"`
+	InstanceID      string              `json:"instanceId"`
+	Maintenance     bool                `json:"maintenance"`
+	Alarms          []Alarm             `json:"alarms"`
+	Frozen          bool                `json:"frozen"`
+	FreezeReason    string              `json:"freezeReason"`
+	Pool            string              `json:"pool"` // Name of the pool answering
+
+	// Change detection: the same epoch and version mean nothing changed
+	PoolEpoch   int64  `json:"poolEpoch,string"`
+	PoolVersion uint64 `json:"poolVersion,string"`
+
+	ConsistencyIssues    uint32        `json:"consistencyIssues"`
+	AlreadyServedDropped uint32        `json:"alreadyServedDropped"`
+	PhaseTimings         []PhaseTiming `json:"phaseTimings"`
+	GenerationCPUs       string        `json:"generationCpus"`
+
+	// Locality of the instance (empty: not configured)
+	Region string `json:"region"`
+	Zone   string `json:"zone"`
+
+	// TotalServed split into pool hits and misses generated synchronously,
+	// with the time requests spent generating the misses
+	PoolHits         int64 `json:"poolHits,string"`
+	PoolMisses       int64 `json:"poolMisses,string"`
+	MissGenerationMs int64 `json:"missGenerationMs,string"`
+
+	// Pool items by assurance level (standard, audited)
+	AssuranceLevels map[string]uint32 `json:"assuranceLevels"`
+
+	// Ages of the pool items
+	ItemAges ItemAges `json:"itemAges"`
+}
+
+// ItemAges are the age statistics of the items in a pool, all zero for an
+// empty pool
+type ItemAges struct {
+	OldestGeneratedAt int64       `json:"oldestGeneratedAt,string"` // Unix timestamp
+	NewestGeneratedAt int64       `json:"newestGeneratedAt,string"`
+	MinSeconds        int64       `json:"minSeconds,string"`
+	MedianSeconds     int64       `json:"medianSeconds,string"`
+	P90Seconds        int64       `json:"p90Seconds,string"`
+	MaxSeconds        int64       `json:"maxSeconds,string"`
+	Stale             uint32      `json:"stale"` // Older than the pool's max age
+	Buckets           []AgeBucket `json:"buckets"`
+}
+
+// AgeBucket counts the items up to an age (1h, 1d, 7d, 30d or older) and
+// older than the bucket before it
+type AgeBucket struct {
+	UpTo  string `json:"upTo"`
+	Count uint32 `json:"count"`
+}
+
+// PoolInfo describes the items of one parameter profile
+type PoolInfo struct {
+	Bits           uint32 `json:"bits"`
+	SafePrime      bool   `json:"safePrime"`
+	Available      uint32 `json:"available"`
+	TargetSize     uint32 `json:"targetSize"`
+	Generating     uint32 `json:"generating"`
+	LastRefillTime int64  `json:"lastRefillTime,string"`
+}
+
+// Alarm is a firing consumption alarm
+type Alarm struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Since   int64  `json:"since,string"` // Unix timestamp
+}
+
+// PhaseTiming is the timing of one generation phase
+type PhaseTiming struct {
+	Phase     string `json:"phase"`
+	Count     int64  `json:"count,string"`
+	TotalMs   int64  `json:"totalMs,string"`
+	AverageMs int64  `json:"averageMs,string"`
+	Failures  int64  `json:"failures,string"`
+	FailedMs  int64  `json:"failedMs,string"`
+}
@@ -0,0 +1,337 @@
+// Package web is a prime service client for browsers and other WASM hosts.
+// It speaks the Connect protocol with JSON to the service's web server
+// (server.web_address) and depends on neither grpc-go nor protobuf, so it
+// builds with GOOS=js GOARCH=wasm and stays small. TinyGo builds, whose
+// net/http has no client, pass a fetch-based HTTPDoer with WithHTTPClient.
+//
+// It covers what a ceremony coordinator needs: GetPoolStatus, HealthCheck
+// and GetPreParams, without retries, fallbacks or streaming.
+package web
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoParams is returned by GetPreParams when the service returned no
+// parameters
+var ErrNoParams = errors.New("no parameters returned from service")
+
+// ErrPoolEmpty is returned by GetPreParams for NoGenerate requests the
+// service could not serve from its pool
+var ErrPoolEmpty = errors.New("pool has no suitable parameters")
+
+// Error reasons the service reports in ErrorInfo details
+const (
+	PoolEmptyReason        = "POOL_EMPTY"
+	ResponseTooLargeReason = "RESPONSE_TOO_LARGE" // Request fewer items per call
+	ExceedsPoolReason      = "EXCEEDS_POOL_SIZE"  // Request at most max_pool_size items per call
+)
+
+// Headers of the service's tracing, pool selection and tenant namespaces
+const (
+	RequestIDHeader = "x-request-id"
+	PoolHeader      = "x-prime-pool"
+	NamespaceHeader = "x-prime-namespace"
+	APIKeyHeader    = "x-api-key"
+)
+
+// servicePath is the URL path prefix of the PrimeService procedures
+const servicePath = "/prime.PrimeService/"
+
+// HTTPDoer sends HTTP requests; *http.Client implements it
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests with doer instead of http.DefaultClient
+func WithHTTPClient(doer HTTPDoer) Option {
+	return func(c *Client) {
+		c.http = doer
+	}
+}
+
+// WithPool sends calls to the named pool of a service serving several
+func WithPool(name string) Option {
+	return func(c *Client) {
+		c.pool = name
+	}
+}
+
+// WithNamespace charges the items of every call to a tenant namespace
+func WithNamespace(namespace string) Option {
+	return func(c *Client) {
+		c.namespace = namespace
+	}
+}
+
+// WithAPIKey sends key with every call, for services requiring API keys.
+// Browsers hold the key in page memory; prefer a per-UI key that can be
+// revoked on its own.
+func WithAPIKey(key string) Option {
+	return func(c *Client) {
+		c.apiKey = key
+	}
+}
+
+// Client calls the web server of one prime service
+type Client struct {
+	baseURL   string
+	http      HTTPDoer
+	pool      string
+	namespace string
+	apiKey    string
+}
+
+// NewClient returns a client for the web server at baseURL, e.g.
+// "https://prime.example.com:8443"
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{baseURL: strings.TrimSuffix(baseURL, "/"), http: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error is a failed call, with the service's status code (e.g.
+// "resource_exhausted"), message and ErrorInfo reason if any
+type Error struct {
+	Code      string
+	Message   string
+	Reason    string
+	RequestID string // Trace ID to look the call up in the service logs
+}
+
+func (e *Error) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("%s: %s (%s)", e.Code, e.Message, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Is makes POOL_EMPTY errors match ErrPoolEmpty
+func (e *Error) Is(target error) bool {
+	return target == ErrPoolEmpty && e.Code == "resource_exhausted" && e.Reason == PoolEmptyReason
+}
+
+// NewIdempotencyKey returns a random idempotency key
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// GetPreParams retrieves up to req.Count parameter sets
+func (c *Client) GetPreParams(ctx context.Context, req PreParamsRequest) ([]*PreParamsData, error) {
+	if req.Count == 0 {
+		req.Count = 1
+	}
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = NewIdempotencyKey()
+	}
+
+	in := wireRequest{
+		Count:              req.Count,
+		IdempotencyKey:     req.IdempotencyKey,
+		DistinctProvenance: req.DistinctProvenance,
+		NoGenerate:         req.NoGenerate,
+		AllowImported:      req.AllowImported,
+		DistinctSeconds:    req.DistinctSeconds,
+		MinAssurance:       req.MinAssurance,
+		PrimeBitSize:       req.PrimeBitSize,
+		PaillierBitSize:    req.PaillierBitSize,
+	}
+	var out wireResponse
+	if err := c.call(ctx, "GetPreParams", in, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Params) == 0 {
+		return nil, ErrNoParams
+	}
+
+	result := make([]*PreParamsData, len(out.Params))
+	for i, p := range out.Params {
+		result[i] = p.params()
+	}
+	return result, nil
+}
+
+// HealthCheck returns the service health status
+func (c *Client) HealthCheck(ctx context.Context) (*HealthStatus, error) {
+	var out HealthStatus
+	if err := c.call(ctx, "HealthCheck", struct{}{}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPoolStatus gets the current pool status
+func (c *Client) GetPoolStatus(ctx context.Context) (*PoolStatus, error) {
+	var out PoolStatus
+	if err := c.call(ctx, "GetPoolStatus", struct{}{}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// call makes a Connect unary call of method with JSON messages
+func (c *Client) call(ctx context.Context, method string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+servicePath+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connect-Protocol-Version", "1")
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Header.Set("Connect-Timeout-Ms", strconv.FormatInt(max(time.Until(deadline).Milliseconds(), 1), 10))
+	}
+	if c.pool != "" {
+		req.Header.Set(PoolHeader, c.pool)
+	}
+	if c.namespace != "" {
+		req.Header.Set(NamespaceHeader, c.namespace)
+	}
+	if c.apiKey != "" {
+		req.Header.Set(APIKeyHeader, c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read %s response: %w", method, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp, data)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	return nil
+}
+
+// decodeError decodes a Connect error body
+func decodeError(resp *http.Response, data []byte) error {
+	var wire struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Details []struct {
+			Type  string `json:"type"`
+			Debug struct {
+				Reason string `json:"reason"`
+			} `json:"debug"`
+		} `json:"details"`
+	}
+	e := &Error{RequestID: resp.Header.Get(RequestIDHeader)}
+	if err := json.Unmarshal(data, &wire); err != nil || wire.Code == "" {
+		e.Code, e.Message = "unknown", fmt.Sprintf("HTTP %d", resp.StatusCode)
+		return e
+	}
+	e.Code, e.Message = wire.Code, wire.Message
+	for _, d := range wire.Details {
+		if d.Type == "google.rpc.ErrorInfo" {
+			e.Reason = d.Debug.Reason
+		}
+	}
+	return e
+}
+
+// wireRequest is the JSON form of GetPreParamsRequest
+type wireRequest struct {
+	Count              uint32 `json:"count"`
+	IdempotencyKey     string `json:"idempotencyKey,omitempty"`
+	DistinctProvenance bool   `json:"distinctProvenance,omitempty"`
+	NoGenerate         bool   `json:"noGenerate,omitempty"`
+	AllowImported      bool   `json:"allowImported,omitempty"`
+	DistinctSeconds    bool   `json:"distinctSeconds,omitempty"`
+	MinAssurance       string `json:"minAssurance,omitempty"`
+	PrimeBitSize       uint32 `json:"primeBitSize,omitempty"`
+	PaillierBitSize    uint32 `json:"paillierBitSize,omitempty"`
+}
+
+// wireResponse is the JSON form of GetPreParamsResponse
+type wireResponse struct {
+	Params []wireParams `json:"params"`
+}
+
+// wireParams is the JSON form of PreParamsData; bytes are base64-encoded
+type wireParams struct {
+	PaillierP       []byte       `json:"paillierP"`
+	PaillierQ       []byte       `json:"paillierQ"`
+	PaillierN       []byte       `json:"paillierN"`
+	PaillierPhiN    []byte       `json:"paillierPhiN"`
+	PaillierLambdaN []byte       `json:"paillierLambdaN"`
+	NTildei         []byte       `json:"nTildei"`
+	H1i             []byte       `json:"h1i"`
+	H2i             []byte       `json:"h2i"`
+	Alpha           []byte       `json:"alpha"`
+	Beta            []byte       `json:"beta"`
+	P               []byte       `json:"p"`
+	Q               []byte       `json:"q"`
+	GeneratedAt     int64        `json:"generatedAt,string"`
+	Metadata        wireMetadata `json:"metadata"`
+}
+
+// wireMetadata is the JSON form of ItemMetadata
+type wireMetadata struct {
+	Source               string     `json:"source"`
+	PoolAgeMs            int64      `json:"poolAgeMs,string"`
+	GenerationDurationMs int64      `json:"generationDurationMs,string"`
+	Replayed             bool       `json:"replayed"`
+	Provenance           Provenance `json:"provenance"`
+	Stale                bool       `json:"stale"`
+	SharedSecond         bool       `json:"sharedSecond"`
+	Assurance            string     `json:"assurance"`
+}
+
+// params converts the JSON form
+func (p *wireParams) params() *PreParamsData {
+	m := p.Metadata
+	return &PreParamsData{
+		PaillierN:       new(big.Int).SetBytes(p.PaillierN),
+		PaillierLambdaN: new(big.Int).SetBytes(p.PaillierLambdaN),
+		PaillierPhiN:    new(big.Int).SetBytes(p.PaillierPhiN),
+		PaillierP:       new(big.Int).SetBytes(p.PaillierP),
+		PaillierQ:       new(big.Int).SetBytes(p.PaillierQ),
+		NTildei:         new(big.Int).SetBytes(p.NTildei),
+		H1i:             new(big.Int).SetBytes(p.H1i),
+		H2i:             new(big.Int).SetBytes(p.H2i),
+		Alpha:           new(big.Int).SetBytes(p.Alpha),
+		Beta:            new(big.Int).SetBytes(p.Beta),
+		P:               new(big.Int).SetBytes(p.P),
+		Q:               new(big.Int).SetBytes(p.Q),
+		GeneratedAt:     time.Unix(p.GeneratedAt, 0),
+		Metadata: ItemMetadata{
+			FromPool:           m.Source != "ITEM_SOURCE_GENERATED",
+			PoolAge:            time.Duration(m.PoolAgeMs) * time.Millisecond,
+			GenerationDuration: time.Duration(m.GenerationDurationMs) * time.Millisecond,
+			Replayed:           m.Replayed,
+			Stale:              m.Stale,
+			SharedSecond:       m.SharedSecond,
+			Provenance:         m.Provenance,
+			Assurance:          m.Assurance,
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runCycles prints the latest refill cycles, newest first
+func runCycles(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("cycles", flag.ExitOnError)
+	limit := fs.Uint("limit", 20, "maximum cycles to show (0: all kept)")
+	fs.Parse(args)
+
+	resp, err := admin.ListRefillCycles(ctx, &pb.ListRefillCyclesRequest{Limit: uint32(*limit)})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "STARTED\tMODE\tPOOL\tTARGET\tGENERATED\tFAILURES\tWORKERS\tDURATION\tREASON")
+	for _, c := range resp.Cycles {
+		generated := fmt.Sprintf("%d/%d", c.Generated, c.Needed)
+		if c.Interrupted {
+			generated += " (interrupted)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%d\t%d\t%s\t%s\n",
+			time.Unix(c.Started, 0).Format(time.RFC3339),
+			c.Mode,
+			c.PoolSize,
+			c.Target,
+			generated,
+			c.Failures,
+			c.Workers,
+			(time.Duration(c.DurationMs) * time.Millisecond).Round(time.Millisecond),
+			c.Reason)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// maxBundleBytes bounds the diagnostic bundle primectl accepts; goroutine
+// dumps and logs of a busy instance can exceed gRPC's 4 MiB default
+const maxBundleBytes = 64 << 20
+
+// runDiag downloads a diagnostic bundle to attach to bug reports
+func runDiag(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	fs := flag.NewFlagSet("diag", flag.ExitOnError)
+	out := fs.String("o", "", "file to write the bundle to (default: the name the service suggests)")
+	logLines := fs.Uint("log-lines", 0, "most recent log lines to include (0: all the service keeps)")
+	history := fs.Duration("history", 0, "pool usage history to include (0: 7 days)")
+	fs.Parse(args)
+
+	bundle, err := pb.NewAdminServiceClient(conn).CollectDiagnostics(ctx, &pb.CollectDiagnosticsRequest{
+		LogLines:       uint32(*logLines),
+		HistorySeconds: int64(history.Seconds()),
+	}, grpc.MaxCallRecvMsgSize(maxBundleBytes))
+	if err != nil {
+		return err
+	}
+
+	path := *out
+	if path == "" {
+		path = bundle.Filename
+	}
+	if err := os.WriteFile(path, bundle.Archive, 0600); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	fmt.Printf("wrote %s (%d bytes)\n", path, len(bundle.Archive))
+	fmt.Println("the configuration is redacted, but logs and status may name hosts and peers; review before sharing")
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runErrors prints recent journaled errors, newest first
+func runErrors(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("errors", flag.ExitOnError)
+	severity := fs.String("severity", "", "minimum severity: warning or error")
+	component := fs.String("component", "", "only show errors from this component")
+	limit := fs.Uint("limit", 50, "maximum entries to show (0: all)")
+	fs.Parse(args)
+
+	req := &pb.GetErrorsRequest{Component: *component, Limit: uint32(*limit)}
+	switch *severity {
+	case "":
+	case "warning":
+		req.MinSeverity = pb.ErrorSeverity_ERROR_SEVERITY_WARNING
+	case "error":
+		req.MinSeverity = pb.ErrorSeverity_ERROR_SEVERITY_ERROR
+	default:
+		return fmt.Errorf("unknown severity %q (want warning or error)", *severity)
+	}
+
+	resp, err := admin.GetErrors(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tSEVERITY\tCOMPONENT\tERROR\tCONTEXT")
+	for _, e := range resp.Errors {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			time.Unix(e.Time, 0).Format(time.RFC3339),
+			severityName(e.Severity),
+			e.Component,
+			e.Error,
+			formatContext(e.Context))
+	}
+	return w.Flush()
+}
+
+// severityName returns the short name of a severity
+func severityName(s pb.ErrorSeverity) string {
+	switch s {
+	case pb.ErrorSeverity_ERROR_SEVERITY_WARNING:
+		return "warning"
+	case pb.ErrorSeverity_ERROR_SEVERITY_ERROR:
+		return "error"
+	}
+	return "-"
+}
+
+// formatContext renders context as sorted key=value pairs
+func formatContext(context map[string]string) string {
+	pairs := make([]string, 0, len(context))
+	for k, v := range context {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// fillPollInterval is how often fill -wait checks the pool size
+const fillPollInterval = 5 * time.Second
+
+// runFill starts generating up to the max pool size or a given target
+func runFill(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("fill", flag.ExitOnError)
+	target := fs.Uint("target", 0, "pool size to reach (0: max_pool_size)")
+	concurrency := fs.Uint("concurrency", 0, "generation workers (0: max_concurrent)")
+	wait := fs.Duration("wait", 0, "wait this long for the target to be reached (0: don't wait)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl fill [-target N] [-concurrency N] [-wait 2h]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	resp, err := admin.FillPool(ctx, &pb.FillPoolRequest{Target: uint32(*target), Concurrency: uint32(*concurrency)})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("fill started: %d -> %d items on %d workers\n", resp.PoolSize, resp.Target, resp.Concurrency)
+	if *wait <= 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), *wait)
+	defer cancel()
+	ticker := time.NewTicker(fillPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return fmt.Errorf("target not reached within %s", *wait)
+		}
+		p, err := admin.GetPressure(ctx, &pb.Empty{})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("pool: %d/%d (in flight: %d)\n", p.Actual, resp.Target, p.InFlight)
+		if p.Actual >= resp.Target {
+			return nil
+		}
+	}
+}
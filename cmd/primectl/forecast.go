@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runForecast prints the pool runway and the backlog of a planned event
+func runForecast(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("forecast", flag.ExitOnError)
+	items := fs.Uint("event-items", 0, "items the planned event will take (e.g. one per onboarded signer)")
+	at := fs.String("event-at", "", "when the event starts, RFC 3339 (e.g. 2026-05-08T09:00:00Z) or a duration from now (e.g. 72h)")
+	lookback := fs.Duration("lookback", 0, "usage history to take rates from (0: 7 days)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl forecast [-event-items N -event-at TIME] [-lookback 168h]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	req := &pb.ForecastPoolRequest{EventItems: uint32(*items), LookbackSeconds: int64(lookback.Seconds())}
+	if *at != "" {
+		t, err := parseEventTime(*at)
+		if err != nil {
+			return err
+		}
+		req.EventAt = t.Unix()
+	}
+
+	f, err := admin.ForecastPool(ctx, req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("pool:        %d items (min %d, max %d)\n", f.PoolSize, f.MinPoolSize, f.MaxPoolSize)
+	fmt.Printf("history:     %.1fh\n", f.HistoryHours)
+	fmt.Printf("consumption: %.1f/day (peak %d/hour)\n", f.ServedPerDay, f.PeakServedPerHour)
+	fmt.Printf("generation:  %.1f/day, capacity %s on %d workers\n", f.GeneratedPerDay, perDay(f.CapacityPerDay), f.Workers)
+	fmt.Printf("runway:      %s without generation, %s with generation\n", days(f.RunwayDays), days(f.SustainedRunwayDays))
+
+	e := f.Event
+	if e == nil {
+		return nil
+	}
+	fmt.Printf("\nevent:       %d items at %s\n", e.Items, time.Unix(e.At, 0).UTC().Format(time.RFC3339))
+	fmt.Printf("baseline:    %.1f items served while filling\n", e.BaselineServed)
+	fmt.Printf("backlog:     %d items to generate", e.Backlog)
+	if e.GenerationHours >= 0 {
+		fmt.Printf(" (%.2fh)", e.GenerationHours)
+	}
+	fmt.Println()
+	if e.StartBy > 0 {
+		fmt.Printf("start by:    %s\n", time.Unix(e.StartBy, 0).UTC().Format(time.RFC3339))
+	}
+	switch {
+	case e.Feasible:
+		fmt.Println("feasible:    yes")
+	case e.WorkersNeeded > 0:
+		fmt.Printf("feasible:    NO (needs %d workers)\n", e.WorkersNeeded)
+	default:
+		fmt.Println("feasible:    unknown")
+	}
+	for _, r := range e.Recommendations {
+		fmt.Printf("- %s\n", r)
+	}
+	return nil
+}
+
+// parseEventTime parses an RFC 3339 time or a duration from now
+func parseEventTime(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid -event-at %q: want RFC 3339 or a duration", s)
+	}
+	return t, nil
+}
+
+// days formats a runway (-1: unbounded)
+func days(d float64) string {
+	switch {
+	case d < 0:
+		return "unbounded"
+	case d < 1:
+		return fmt.Sprintf("%.1f hours", d*24)
+	}
+	return fmt.Sprintf("%.1f days", d)
+}
+
+// perDay formats a rate (0: unknown)
+func perDay(r float64) string {
+	if r <= 0 {
+		return "unknown"
+	}
+	return fmt.Sprintf("%.0f/day", r)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runFreeze shows or lifts an anomaly freeze
+func runFreeze(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("freeze", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl freeze status|unfreeze")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var resp *pb.FreezeStatus
+	var err error
+	switch fs.Arg(0) {
+	case "unfreeze":
+		resp, err = admin.Unfreeze(ctx, &pb.Empty{})
+		if err != nil {
+			return err
+		}
+		if !resp.Frozen {
+			fmt.Println("pool was not frozen")
+			return nil
+		}
+		fmt.Print("unfrozen, was: ")
+	case "status", "":
+		resp, err = admin.GetFreeze(ctx, &pb.Empty{})
+		if err != nil {
+			return err
+		}
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown action %q", fs.Arg(0))
+	}
+
+	if !resp.Frozen {
+		fmt.Println("frozen: no")
+		return nil
+	}
+	fmt.Printf("frozen since %s\nanomaly: %s\nreason: %s\n",
+		time.Unix(resp.Since, 0).Format(time.RFC3339), resp.Anomaly, resp.Reason)
+	return nil
+}
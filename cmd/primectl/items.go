@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runItems lists the pool contents, oldest first
+func runItems(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("items", flag.ExitOnError)
+	pageSize := fs.Uint("page-size", 100, "items per page")
+	pageToken := fs.String("page-token", "", "continue from a previous page")
+	all := fs.Bool("all", false, "fetch every page")
+	fs.Parse(args)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQ\tFINGERPRINT\tGENERATED\tAGE\tGEN TIME\tINSTANCE\tHOST\tBURST\tWORKER\tVERSION\tSTALE\tIMPORTED\tASSURANCE")
+	token := *pageToken
+	for {
+		resp, err := admin.ListPoolItems(ctx, &pb.ListPoolItemsRequest{PageSize: uint32(*pageSize), PageToken: token})
+		if err != nil {
+			return err
+		}
+		for _, item := range resp.Items {
+			p := item.Provenance
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%t\t%t\t%s\n",
+				p.GetSeq(), item.Fingerprint,
+				time.Unix(item.GeneratedAt, 0).Format(time.RFC3339),
+				time.Duration(item.AgeSeconds)*time.Second,
+				time.Duration(item.GenerationDurationMs)*time.Millisecond,
+				p.GetInstance(), p.GetHost(), p.GetBurst(), p.GetWorker(), p.GetVersion(),
+				item.Stale, p.GetImported(), item.Assurance)
+		}
+		token = resp.NextPageToken
+		if token == "" || !*all {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if token != "" {
+				fmt.Printf("\n%d items in pool; next page: -page-token %s\n", resp.Total, token)
+			}
+			return nil
+		}
+	}
+}
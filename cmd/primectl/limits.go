@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runClear discards every item in the pool
+func runClear(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	fs := flag.NewFlagSet("clear", flag.ExitOnError)
+	yes := fs.Bool("yes", false, "confirm discarding every item in the pool")
+	pause := fs.Bool("pause", false, "pause background generation first, so the pool stays empty")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl clear -yes [-pause]")
+		fmt.Fprintln(fs.Output(), "Discarded items are gone for good; without -pause, a refill replaces them right away.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if !*yes {
+		fs.Usage()
+		return errors.New("pass -yes to discard every item in the pool")
+	}
+	resp, err := pb.NewAdminServiceClient(conn).ClearPool(ctx, &pb.ClearPoolRequest{PauseGeneration: *pause})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("cleared: %d items\ngeneration paused: %t\n", resp.Cleared, resp.GenerationPaused)
+	return nil
+}
+
+// runLimits changes the pool sizes and refill threshold, or shows them
+func runLimits(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	fs := flag.NewFlagSet("limits", flag.ExitOnError)
+	minSize := fs.Uint("min", 0, "new min_pool_size (0: keep)")
+	maxSize := fs.Uint("max", 0, "new max_pool_size (0: keep)")
+	threshold := fs.Uint("threshold", 0, "new refill_threshold (0: keep)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl limits [-min N] [-max N] [-threshold N]")
+		fmt.Fprintln(fs.Output(), "Changes last until the next reload or restart, which restore the configured limits.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	resp, err := pb.NewAdminServiceClient(conn).SetPoolLimits(ctx, &pb.SetPoolLimitsRequest{
+		MinPoolSize:     uint32(*minSize),
+		MaxPoolSize:     uint32(*maxSize),
+		RefillThreshold: uint32(*threshold),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("pool_size: %d-%d\nrefill_threshold: %d\npool: %d items\n", resp.MinPoolSize, resp.MaxPoolSize, resp.RefillThreshold, resp.PoolSize)
+	return nil
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// loadTest collects the results of a load-test run
+type loadTest struct {
+	mu        sync.Mutex
+	requests  int
+	succeeded int
+	short     int // Succeeded with fewer items than requested
+	items     int
+	generated int // Items generated on demand rather than served from the pool
+	latencies []time.Duration
+	errors    map[string]int // By status code or ErrorInfo reason
+
+	// Pool depletion
+	minPool     int
+	firstEmpty  time.Duration // Since start; zero if the pool never ran dry
+	lastPool    int
+	poolSamples int
+}
+
+// runLoadTest drives GetPreParams traffic at a target instance and reports
+// throughput, latencies, error classes and how the pool depletes
+func runLoadTest(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	fs := flag.NewFlagSet("load-test", flag.ExitOnError)
+	rate := fs.Float64("rate", 0, "requests per second across all workers (0: as fast as the workers go)")
+	count := fs.Uint("count", 1, "items per request")
+	concurrency := fs.Int("concurrency", 4, "concurrent requests")
+	duration := fs.Duration("duration", time.Minute, "how long to run")
+	maxRequests := fs.Int("requests", 0, "stop after this many requests (0: run for -duration)")
+	noGenerate := fs.Bool("no-generate", false, "send no_generate, so an empty pool fails fast with POOL_EMPTY")
+	requestTimeout := fs.Duration("request-timeout", 2*time.Minute, "timeout of each request")
+	report := fs.Duration("report", 10*time.Second, "progress report and pool sampling interval")
+	yes := fs.Bool("yes", false, "confirm that the parameters received may be discarded")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl load-test -yes [-rate N] [-count N] [-concurrency N] [-duration 5m] [-requests N] [-no-generate]")
+		fmt.Fprintln(fs.Output(), "Every parameter set received is consumed and discarded; point it at a test instance.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if !*yes {
+		fs.Usage()
+		return errors.New("refusing to consume parameters without -yes")
+	}
+	if *concurrency < 1 || *count < 1 || *rate < 0 {
+		return errors.New("concurrency and count must be at least 1, rate must not be negative")
+	}
+
+	service := pb.NewPrimeServiceClient(conn)
+	admin := pb.NewAdminServiceClient(conn)
+	lt := &loadTest{errors: make(map[string]int), minPool: -1}
+
+	// The run outlives the admin request timeout
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), *duration)
+	defer cancel()
+	start := time.Now()
+	lt.samplePool(ctx, admin, start)
+
+	// Hand out request slots at the requested rate, or as fast as taken
+	slots := make(chan struct{})
+	go func() {
+		defer close(slots)
+		var tick <-chan time.Time
+		if *rate > 0 {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+		for sent := 0; *maxRequests == 0 || sent < *maxRequests; sent++ {
+			if tick != nil {
+				select {
+				case <-tick:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range slots {
+				lt.request(ctx, service, &pb.GetPreParamsRequest{Count: uint32(*count), NoGenerate: *noGenerate}, *requestTimeout)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	fmt.Printf("load-test: %d workers, %d items per request, rate %s, up to %s\n", *concurrency, *count, rateLabel(*rate), *duration)
+	ticker := time.NewTicker(*report)
+	defer ticker.Stop()
+	for running := true; running; {
+		select {
+		case <-ticker.C:
+			lt.samplePool(ctx, admin, start)
+			lt.progress(time.Since(start))
+		case <-done:
+			running = false
+		}
+	}
+
+	// Sample once more after the run, outside its deadline
+	sampleCtx, sampleCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer sampleCancel()
+	lt.samplePool(sampleCtx, admin, start)
+	return lt.summary(time.Since(start))
+}
+
+// request performs one GetPreParams call and records its outcome
+func (lt *loadTest) request(ctx context.Context, service pb.PrimeServiceClient, req *pb.GetPreParamsRequest, timeout time.Duration) {
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := service.GetPreParams(reqCtx, req)
+	latency := time.Since(start)
+	if ctx.Err() != nil && err != nil {
+		return // Cut off by the end of the run, not a service error
+	}
+
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	lt.requests++
+	if err != nil {
+		lt.errors[errorClass(err)]++
+		return
+	}
+	lt.succeeded++
+	lt.latencies = append(lt.latencies, latency)
+	lt.items += len(resp.Params)
+	if len(resp.Params) < int(req.Count) {
+		lt.short++
+	}
+	for _, p := range resp.Params {
+		if p.Metadata.GetSource() == pb.ItemSource_ITEM_SOURCE_GENERATED {
+			lt.generated++
+		}
+	}
+}
+
+// samplePool records the pool size for the depletion report
+func (lt *loadTest) samplePool(ctx context.Context, admin pb.AdminServiceClient, start time.Time) {
+	p, err := admin.GetPressure(ctx, &pb.Empty{})
+	if err != nil {
+		return
+	}
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	size := int(p.Actual)
+	lt.lastPool = size
+	lt.poolSamples++
+	if lt.minPool < 0 || size < lt.minPool {
+		lt.minPool = size
+	}
+	if size == 0 && lt.firstEmpty == 0 {
+		lt.firstEmpty = max(time.Since(start), time.Nanosecond)
+	}
+}
+
+// progress prints a one-line interim report
+func (lt *loadTest) progress(elapsed time.Duration) {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	failed := lt.requests - lt.succeeded
+	fmt.Printf("%6s  requests: %d (%.1f/s)  items: %d  failed: %d  pool: %d\n",
+		elapsed.Truncate(time.Second), lt.requests, float64(lt.requests)/elapsed.Seconds(), lt.items, failed, lt.lastPool)
+}
+
+// summary prints the final report
+func (lt *loadTest) summary(elapsed time.Duration) error {
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "\nduration\t%s\n", elapsed.Truncate(time.Millisecond))
+	fmt.Fprintf(w, "requests\t%d (%.2f/s)\n", lt.requests, float64(lt.requests)/elapsed.Seconds())
+	fmt.Fprintf(w, "succeeded\t%d (%d with fewer items than requested)\n", lt.succeeded, lt.short)
+	fmt.Fprintf(w, "items\t%d (%.2f/s, %d generated on demand)\n", lt.items, float64(lt.items)/elapsed.Seconds(), lt.generated)
+
+	if len(lt.latencies) > 0 {
+		sort.Slice(lt.latencies, func(i, j int) bool { return lt.latencies[i] < lt.latencies[j] })
+		fmt.Fprintf(w, "latency\tp50 %s  p90 %s  p99 %s  max %s\n",
+			percentile(lt.latencies, 0.50), percentile(lt.latencies, 0.90), percentile(lt.latencies, 0.99), percentile(lt.latencies, 1))
+	}
+
+	classes := make([]string, 0, len(lt.errors))
+	for class := range lt.errors {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		fmt.Fprintf(w, "error %s\t%d\n", class, lt.errors[class])
+	}
+
+	if lt.poolSamples > 0 {
+		fmt.Fprintf(w, "pool\tmin %d, end %d\n", lt.minPool, lt.lastPool)
+		if lt.firstEmpty > 0 {
+			fmt.Fprintf(w, "pool empty\tafter %s\n", lt.firstEmpty.Truncate(time.Second))
+		}
+	}
+	return w.Flush()
+}
+
+// errorClass names an error by its ErrorInfo reason or status code
+func errorClass(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return "transport"
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return st.Code().String()
+}
+
+// percentile returns the p-th percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	i := int(float64(len(sorted)-1) * p)
+	return sorted[i].Truncate(time.Microsecond)
+}
+
+func rateLabel(rate float64) string {
+	if rate == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%g/s", rate)
+}
@@ -0,0 +1,124 @@
+// Command primectl is the operator CLI for the prime service admin API
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// command is a primectl subcommand
+type command struct {
+	usage string
+	run   func(ctx context.Context, conn grpc.ClientConnInterface, args []string) error
+}
+
+var commands = map[string]command{
+	"clear":       {"discard every item in the pool (e.g. after a suspected compromise)", runClear},
+	"cycles":      {"show the latest refill cycles (is background generation keeping up?)", runCycles},
+	"diag":        {"download a diagnostic bundle (config, logs, status, errors, goroutines, metrics) for bug reports", runDiag},
+	"drain":       {"enter maintenance mode and wait for in-flight requests (maintenance on)", runDrain},
+	"errors":      {"show recent journaled errors", runErrors},
+	"export":      {"move items out of the pool into a file, for import elsewhere", runExport},
+	"fill":        {"generate up to the max pool size now (e.g. before an onboarding event)", runFill},
+	"forecast":    {"estimate pool runway and the generation needed for a planned event", runForecast},
+	"freeze":      {"show or lift an anomaly freeze", runFreeze},
+	"get":         {"take parameter sets like a client and write them to a file (consumes items)", runGet},
+	"import":      {"add the items of an export or get file to the pool, revalidating each", runImport},
+	"items":       {"list pool contents (fingerprints, ages, provenance), oldest first", runItems},
+	"limits":      {"change min/max pool size and refill threshold until the next reload, or show them", runLimits},
+	"load-test":   {"drive GetPreParams traffic at an instance to validate sizing (consumes items)", runLoadTest},
+	"maintenance": {"enter, leave or show maintenance mode (drains before upgrades)", runMaintenance},
+	"materialize": {"take one parameter set per party and write tss-lib LocalPreParams JSON files for keygen", runMaterialize},
+	"pause":       {"pause, resume or show background generation (within milliseconds, mid-item)", runPause},
+	"peers":       {"show the status of this instance and its configured peers", runPeers},
+	"pin":         {"set a pool item aside for an investigation, return it, or list pinned items", runPin},
+	"refill":      {"start a refill up to the max pool size now (fill)", runFill},
+	"replay":      {"replay recorded traffic against a test instance to compare pool settings (consumes items)", runReplay},
+	"slo":         {"show latency objectives, error budgets and burn rates", runSLO},
+	"snapshot":    {"list, take or restore encrypted pool snapshots in the snapshot directory", runSnapshot},
+	"status":      {"show health, pool size, traffic, item ages and alarms", runStatus},
+	"verify":      {"verify the items of an export or get file offline, including primality", runVerify},
+	"workers":     {"list, revoke or reinstate generate-only workers", runWorkers},
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:50055", "prime service gRPC address")
+	timeout := flag.Duration("timeout", 10*time.Second, "request timeout")
+	poolName := flag.String("pool", "", "named pool to manage (default: the default pool)")
+	namespace := flag.String("namespace", "", "tenant namespace the items taken by get, load-test, materialize and replay are charged to")
+	useTLS := flag.Bool("tls", false, "connect over TLS (implied by -tls-ca and -tls-cert)")
+	tlsCA := flag.String("tls-ca", "", "PEM bundle of CAs to verify the service certificate against (default: system roots)")
+	tlsCert := flag.String("tls-cert", "", "PEM client certificate, for services requiring mutual TLS")
+	tlsKey := flag.String("tls-key", "", "PEM private key of -tls-cert")
+	apiKey := flag.String("api-key", os.Getenv("PRIME_API_KEY"), "API key; an admin key for every command but get, load-test, materialize and replay (default: $PRIME_API_KEY)")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "primectl: unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	if *useTLS || *tlsCA != "" || *tlsCert != "" {
+		var err error
+		if creds, err = lite.TLSCredentials(*tlsCA, *tlsCert, *tlsKey); err != nil {
+			fatalf("%v", err)
+		}
+	}
+	conn, err := grpc.NewClient(*addr, creds)
+	if err != nil {
+		fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if *poolName != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-prime-pool", *poolName)
+	}
+	if *namespace != "" {
+		ctx = lite.WithNamespace(ctx, *namespace)
+	}
+	if *apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, lite.APIKeyHeader, *apiKey)
+	}
+
+	if err := cmd.run(ctx, conn, flag.Args()[1:]); err != nil {
+		fatalf("%s: %v", flag.Arg(0), err)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: primectl [flags] <command> [command flags]\n\nCommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "primectl: "+format+"\n", args...)
+	os.Exit(1)
+}
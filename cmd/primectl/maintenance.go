@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runMaintenance enters, leaves or shows maintenance mode
+func runMaintenance(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("maintenance", flag.ExitOnError)
+	drain := fs.Duration("drain", 30*time.Second, "when turning on, wait this long for in-flight requests (0: don't wait)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl maintenance [-drain 30s] on|off|status")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var resp *pb.MaintenanceStatus
+	var err error
+	switch fs.Arg(0) {
+	case "on":
+		// Leave room in the request timeout for the drain itself
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), *drain+10*time.Second)
+		defer cancel()
+		resp, err = admin.SetMaintenance(ctx, &pb.SetMaintenanceRequest{
+			Enabled:             true,
+			DrainTimeoutSeconds: uint32(drain.Seconds()),
+		})
+	case "off":
+		resp, err = admin.SetMaintenance(ctx, &pb.SetMaintenanceRequest{Enabled: false})
+	case "status", "":
+		resp, err = admin.GetMaintenance(ctx, &pb.Empty{})
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown action %q", fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+
+	state := "off"
+	if resp.Enabled {
+		state = "on"
+	}
+	fmt.Printf("maintenance: %s\nactive requests: %d\ndrained: %t\n", state, resp.ActiveRequests, resp.Drained)
+	return nil
+}
+
+// runDrain enters maintenance mode and waits for in-flight requests, as
+// maintenance on does
+func runDrain(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	return runMaintenance(ctx, conn, append(args, "on"))
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/dlnproof"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
+	"google.golang.org/grpc"
+)
+
+// runMaterialize takes one parameter set per party from the service and
+// writes each as a tss-lib LocalPreParams JSON file, the layout keygen
+// tooling loads with json.Unmarshal into keygen.LocalPreParams
+func runMaterialize(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	fs := flag.NewFlagSet("materialize", flag.ExitOnError)
+	parties := fs.Uint("n", 0, "parties, i.e. parameter sets to take and files to write")
+	dir := fs.String("dir", "", "directory to write the files to (created with mode 0700)")
+	pattern := fs.String("pattern", "preparams_%d.json", "file name of each party, formatted with its index")
+	first := fs.Int("first", 0, "index of the first party")
+	key := fs.String("idempotency-key", "", "idempotency key of the request; retry a failed run with the key it printed to get the same items")
+	requestTimeout := fs.Duration("request-timeout", 10*time.Minute, "timeout of the request, which may generate on demand")
+	force := fs.Bool("force", false, "overwrite existing files")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl materialize -n N -dir DIR [-pattern NAME] [-first 0] [-idempotency-key KEY] [-force]")
+		fmt.Fprintln(fs.Output(), "Every parameter set is verified in full and its DLN proofs checked before any file is written.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *parties < 1 || *dir == "" {
+		fs.Usage()
+		return errors.New("-n and -dir are required")
+	}
+
+	// Check the targets before consuming anything
+	if err := os.MkdirAll(*dir, 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", *dir, err)
+	}
+	info, err := os.Stat(*dir)
+	if err != nil {
+		return err
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 {
+		return fmt.Errorf("%s is accessible to other users (mode %04o); restrict it to its owner", *dir, perm)
+	}
+	paths := make([]string, *parties)
+	for i := range paths {
+		paths[i] = filepath.Join(*dir, fmt.Sprintf(*pattern, *first+i))
+		if _, err := os.Stat(paths[i]); err == nil && !*force {
+			return fmt.Errorf("%s exists; pass -force to overwrite it", paths[i])
+		}
+	}
+
+	if *key == "" {
+		*key = lite.NewIdempotencyKey()
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), *requestTimeout)
+	defer cancel()
+	items, err := lite.FetchPreParams(ctx, pb.NewPrimeServiceClient(conn), &pb.GetPreParamsRequest{
+		Count:          uint32(*parties),
+		IdempotencyKey: *key,
+	})
+	if err != nil {
+		return fmt.Errorf("%w (retry with -idempotency-key %s to get the same items)", err, *key)
+	}
+	if len(items) != len(paths) {
+		return fmt.Errorf("received %d parameter sets, %d requested", len(items), len(paths))
+	}
+
+	data := make([][]byte, len(items))
+	for i, item := range items {
+		params, err := materialize(lite.FromProto(item))
+		if err != nil {
+			return fmt.Errorf("item %d: %w (retry with -idempotency-key %s after investigating)", i, err, *key)
+		}
+		if data[i], err = json.Marshal(params); err != nil {
+			return err
+		}
+	}
+
+	// Write every file aside first, so a failure leaves no partial set
+	tmps := make([]string, len(paths))
+	defer func() {
+		for _, tmp := range tmps {
+			if tmp != "" {
+				os.Remove(tmp)
+			}
+		}
+	}()
+	for i, path := range paths {
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data[i], 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", tmp, err)
+		}
+		tmps[i] = tmp
+	}
+	for i, path := range paths {
+		if err := os.Rename(tmps[i], path); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		tmps[i] = ""
+		fmt.Printf("wrote %s (fingerprint %s)\n", path, fingerprint(items[i]))
+	}
+	return nil
+}
+
+// materialize verifies p in full, including the DLN proofs tss-lib keygen
+// exchanges for it, and returns it as LocalPreParams
+func materialize(p *lite.PreParamsData) (*keygen.LocalPreParams, error) {
+	if err := p.Verify(lite.VerifyFull); err != nil {
+		return nil, err
+	}
+	params := &keygen.LocalPreParams{
+		PaillierSK: &paillier.PrivateKey{
+			PublicKey: paillier.PublicKey{N: p.PaillierN},
+			LambdaN:   p.PaillierLambdaN,
+			PhiN:      p.PaillierPhiN,
+			P:         p.PaillierP,
+			Q:         p.PaillierQ,
+		},
+		NTildei: p.NTildei,
+		H1i:     p.H1i,
+		H2i:     p.H2i,
+		Alpha:   p.Alpha,
+		Beta:    p.Beta,
+		P:       p.P,
+		Q:       p.Q,
+	}
+	if !params.ValidateWithProof() {
+		return nil, errors.New("incomplete parameter set")
+	}
+
+	// Keygen round 1 proves h2 = h1^alpha and h1 = h2^beta; peers abort on
+	// proofs that fail
+	for _, d := range []struct {
+		h1, h2, x *big.Int
+	}{{p.H1i, p.H2i, p.Alpha}, {p.H2i, p.H1i, p.Beta}} {
+		proof := dlnproof.NewDLNProof(d.h1, d.h2, d.x, p.P, p.Q, p.NTildei, rand.Reader)
+		if !proof.Verify(d.h1, d.h2, p.NTildei) {
+			return nil, fmt.Errorf("%w: DLN proof does not verify", lite.ErrInvalidParams)
+		}
+	}
+	return params, nil
+}
+
+// fingerprint computes the fingerprint primectl items lists for an item
+func fingerprint(item *pb.PreParamsData) string {
+	h := sha256.New()
+	h.Write(new(big.Int).SetBytes(item.PaillierN).Bytes())
+	h.Write(new(big.Int).SetBytes(item.NTildei).Bytes())
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
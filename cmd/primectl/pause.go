@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runPause pauses, resumes or shows background generation
+func runPause(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("pause", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl pause on|off|status")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var resp *pb.GenerationPauseStatus
+	var err error
+	switch fs.Arg(0) {
+	case "on":
+		resp, err = admin.SetGenerationPause(ctx, &pb.SetGenerationPauseRequest{Paused: true})
+	case "off":
+		resp, err = admin.SetGenerationPause(ctx, &pb.SetGenerationPauseRequest{Paused: false})
+	case "status", "":
+		resp, err = admin.GetGenerationPause(ctx, &pb.Empty{})
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown action %q", fs.Arg(0))
+	}
+	if err != nil {
+		return err
+	}
+
+	state := "running"
+	if resp.Paused {
+		state = "paused"
+	}
+	fmt.Printf("background generation: %s\nin flight: %d\nparked: %d\n", state, resp.InFlight, resp.Parked)
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runPeers prints the status of the answering instance and its peers
+func runPeers(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("peers", flag.ExitOnError)
+	fs.Parse(args)
+
+	resp, err := admin.ListPeers(ctx, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "INSTANCE\tADDRESS\tLOCALITY\tSTATE\tPOOL\tGENERATED\tSERVED\tERROR")
+	for _, r := range resp.Replicas {
+		addr := r.Address
+		if r.Self {
+			addr = "(self)"
+		}
+		if !r.Reachable {
+			fmt.Fprintf(w, "-\t%s\t-\tunreachable\t-\t-\t-\t%s\n", addr, r.Error)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d/%d\t%d\t%d\t\n",
+			r.InstanceId, addr, locality(r), replicaState(r), r.PoolSize, r.TargetSize, r.TotalGenerated, r.TotalServed)
+	}
+	return w.Flush()
+}
+
+// locality formats the region and zone a replica reports
+func locality(r *pb.ReplicaStatus) string {
+	switch {
+	case r.Region == "" && r.Zone == "":
+		return "-"
+	case r.Zone == "":
+		return r.Region
+	}
+	return r.Region + "/" + r.Zone
+}
+
+// replicaState summarizes a reachable replica
+func replicaState(r *pb.ReplicaStatus) string {
+	switch {
+	case r.Maintenance:
+		return "maintenance"
+	case r.Healthy:
+		return "healthy"
+	}
+	return "unhealthy"
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runPin pins or unpins a pool item, or lists the pinned items
+func runPin(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	fs := flag.NewFlagSet("pin", flag.ExitOnError)
+	reason := fs.String("reason", "", "why the item is pinned (e.g. an incident ticket)")
+	unpin := fs.Bool("unpin", false, "return the pinned item to the pool")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl pin [-reason text | -unpin] [fingerprint | seq]")
+		fmt.Fprintln(fs.Output(), "Without an item, lists the pinned items.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	switch fs.NArg() {
+	case 0:
+		if *unpin {
+			fs.Usage()
+			return fmt.Errorf("-unpin needs a fingerprint or sequence number")
+		}
+		return listPinned(ctx, conn)
+	case 1:
+	default:
+		fs.Usage()
+		return fmt.Errorf("expected one item, got %d arguments", fs.NArg())
+	}
+
+	p, err := pb.NewAdminServiceClient(conn).PinItem(ctx, &pb.PinItemRequest{Fingerprint: fs.Arg(0), Reason: *reason, Unpin: *unpin})
+	if err != nil {
+		return err
+	}
+	seq := p.Item.GetProvenance().GetSeq()
+	if *unpin {
+		fmt.Printf("item %d (%s) returned to the pool\n", seq, p.Item.GetFingerprint())
+	} else {
+		fmt.Printf("item %d (%s) pinned: it will not be served until unpinned (primectl pin -unpin %d)\n", seq, p.Item.GetFingerprint(), seq)
+	}
+	return nil
+}
+
+// listPinned prints the pinned items, oldest pin first
+func listPinned(ctx context.Context, conn grpc.ClientConnInterface) error {
+	status, err := pb.NewPrimeServiceClient(conn).GetPoolStatus(ctx, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	if len(status.Pinned) == 0 {
+		fmt.Println("no items pinned")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SEQ\tFINGERPRINT\tPINNED\tGENERATED\tINSTANCE\tBURST\tREASON")
+	for _, p := range status.Pinned {
+		reason := p.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			p.Item.GetProvenance().GetSeq(), p.Item.GetFingerprint(),
+			time.Unix(p.PinnedAt, 0).Format(time.RFC3339),
+			time.Unix(p.Item.GetGeneratedAt(), 0).Format(time.RFC3339),
+			p.Item.GetProvenance().GetInstance(), p.Item.GetProvenance().GetBurst(),
+			reason)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/traffic"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// lateAfter is how far behind schedule a replayed call may start before it
+// counts as late
+const lateAfter = 100 * time.Millisecond
+
+// runReplay feeds a traffic recording (server.record_traffic) to a test
+// instance with the recorded timing and compares the outcome with the
+// recording, so pool settings can be tuned against real demand
+func runReplay(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	tracePath := fs.String("trace", "", "traffic recording to replay")
+	speed := fs.Float64("speed", 1, "replay speed relative to the recording (2: twice as fast)")
+	sourcePool := fs.String("source-pool", "", "only replay calls recorded for this pool (default: all)")
+	concurrency := fs.Int("concurrency", 64, "most calls in flight; later calls start late")
+	requestTimeout := fs.Duration("request-timeout", 2*time.Minute, "timeout of each request")
+	report := fs.Duration("report", 10*time.Second, "progress report and pool sampling interval")
+	yes := fs.Bool("yes", false, "confirm that the parameters received may be discarded")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl replay -yes -trace FILE [-speed N] [-source-pool NAME] [-concurrency N]")
+		fmt.Fprintln(fs.Output(), "Every parameter set received is consumed and discarded; point it at a test instance.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *tracePath == "" || !*yes {
+		fs.Usage()
+		return errors.New("-trace and -yes are required")
+	}
+	if *speed <= 0 || *concurrency < 1 {
+		return errors.New("speed must be positive and concurrency at least 1")
+	}
+
+	f, err := os.Open(*tracePath)
+	if err != nil {
+		return err
+	}
+	records, err := traffic.Read(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *tracePath, err)
+	}
+	if *sourcePool != "" {
+		kept := records[:0]
+		for _, r := range records {
+			if r.Pool == *sourcePool {
+				kept = append(kept, r)
+			}
+		}
+		records = kept
+	}
+	if len(records) == 0 {
+		return errors.New("nothing to replay")
+	}
+	first := records[0].Time
+	span := records[len(records)-1].Time.Sub(first)
+
+	service := pb.NewPrimeServiceClient(conn)
+	admin := pb.NewAdminServiceClient(conn)
+	lt := &loadTest{errors: make(map[string]int), minPool: -1}
+
+	// The run outlives the admin request timeout
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Duration(float64(span) / *speed)+*requestTimeout)
+	defer cancel()
+	start := time.Now()
+	lt.samplePool(ctx, admin, start)
+
+	fmt.Printf("replay: %d calls recorded over %s, at %gx speed\n", len(records), span.Truncate(time.Second), *speed)
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(*report)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				lt.samplePool(ctx, admin, start)
+				lt.progress(time.Since(start))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, *concurrency)
+	late := 0
+	for _, r := range records {
+		due := start.Add(time.Duration(float64(r.Time.Sub(first)) / *speed))
+		select {
+		case <-time.After(time.Until(due)):
+		case <-ctx.Done():
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if time.Since(due) > lateAfter {
+			late++
+		}
+
+		wg.Add(1)
+		go func(r traffic.Record) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			lt.request(ctx, service, &pb.GetPreParamsRequest{
+				Count:              r.Count,
+				DistinctProvenance: r.Distinct,
+				DistinctSeconds:    r.Seconds,
+				NoGenerate:         r.NoGenerate,
+			}, *requestTimeout)
+		}(r)
+	}
+	wg.Wait()
+	close(done)
+
+	// Sample once more after the run, outside its deadline
+	sampleCtx, sampleCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer sampleCancel()
+	lt.samplePool(sampleCtx, admin, start)
+
+	fmt.Printf("\nrecorded")
+	if err := recordedRun(records).summary(max(span, time.Millisecond)); err != nil {
+		return err
+	}
+	fmt.Printf("\nreplayed")
+	if late > 0 {
+		fmt.Printf(" (%d calls started over %s late; raise -concurrency or lower -speed)", late, lateAfter)
+	}
+	return lt.summary(time.Since(start))
+}
+
+// recordedRun summarizes a recording like a load-test run, for comparison
+// with its replay
+func recordedRun(records []traffic.Record) *loadTest {
+	lt := &loadTest{errors: make(map[string]int), minPool: -1}
+	first := records[0].Time
+	for _, r := range records {
+		lt.requests++
+		switch {
+		case r.Code != "OK" && r.Reason != "":
+			lt.errors[r.Reason]++
+		case r.Code != "OK":
+			lt.errors[r.Code]++
+		default:
+			lt.succeeded++
+			lt.latencies = append(lt.latencies, time.Duration(r.LatencyMs)*time.Millisecond)
+			lt.items += r.Served
+			lt.generated += r.Generated
+			if r.Served < int(r.Count) {
+				lt.short++
+			}
+		}
+
+		lt.lastPool = r.PoolSize
+		lt.poolSamples++
+		if lt.minPool < 0 || r.PoolSize < lt.minPool {
+			lt.minPool = r.PoolSize
+		}
+		if r.PoolSize == 0 && lt.firstEmpty == 0 {
+			lt.firstEmpty = max(r.Time.Sub(first), time.Nanosecond)
+		}
+	}
+	return lt
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// burnWindows are the burn rate lookbacks reported by the service, in display order
+var burnWindows = []string{"5m", "1h", "6h"}
+
+// runSLO prints the latency objectives of the answering instance
+func runSLO(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("slo", flag.ExitOnError)
+	fs.Parse(args)
+
+	resp, err := admin.GetSLOStatus(ctx, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	if len(resp.Objectives) == 0 {
+		fmt.Println("no objectives configured")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "OBJECTIVE\tMETHOD\tCLASS\tTARGET\tCALLS\tCOMPLIANCE\tBUDGET LEFT\tBURN 5m/1h/6h\tSTATE")
+	for _, o := range resp.Objectives {
+		class := o.Class
+		if class == "" {
+			class = "any"
+		}
+		state := "met"
+		if !o.Met {
+			state = "MISSED"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.2f%% < %dms\t%d\t%.3f%%\t%.1f%%\t%s\t%s\n",
+			o.Name, o.Method, class, o.Target*100, o.ThresholdMs, o.Total, o.Compliance*100, o.BudgetRemaining*100,
+			burnRates(o.BurnRates), state)
+	}
+	return w.Flush()
+}
+
+// burnRates formats the burn rates in burnWindows order
+func burnRates(rates map[string]float64) string {
+	s := ""
+	for i, name := range burnWindows {
+		if i > 0 {
+			s += "/"
+		}
+		s += fmt.Sprintf("%.1f", rates[name])
+	}
+	return s
+}
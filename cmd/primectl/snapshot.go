@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runSnapshot lists, takes or restores pool snapshots
+func runSnapshot(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl snapshot [list | take | restore <name>]")
+		fmt.Fprintln(fs.Output(), "Restoring validates every item; raise -timeout for large snapshots.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	switch fs.Arg(0) {
+	case "list", "":
+	case "take":
+		s, err := admin.TakeSnapshot(ctx, &pb.Empty{})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("snapshot %s written (%d bytes)\n", s.Name, s.Bytes)
+		if s.CommandError != "" {
+			return fmt.Errorf("%s", s.CommandError)
+		}
+		return nil
+	case "restore":
+		if fs.NArg() != 2 {
+			fs.Usage()
+			return fmt.Errorf("restore needs a snapshot name (primectl snapshot list)")
+		}
+		resp, err := admin.RestoreSnapshot(ctx, &pb.RestoreSnapshotRequest{Name: fs.Arg(1)})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("restored: %d items\nalready served: %d\nin pool: %d\nskipped: %d\npool: %d items\n",
+			resp.Restored, resp.AlreadyServed, resp.InPool, resp.Skipped, resp.PoolSize)
+		return nil
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown action %q", fs.Arg(0))
+	}
+
+	resp, err := admin.ListSnapshots(ctx, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("directory: %s\n", resp.Dir)
+	if resp.LastSnapshotAt != 0 {
+		fmt.Printf("last snapshot: %s\n", time.Unix(resp.LastSnapshotAt, 0).Format(time.RFC3339))
+	}
+	if resp.Failures > 0 {
+		fmt.Printf("failures: %d (last: %s)\n", resp.Failures, resp.LastError)
+	}
+	if len(resp.Snapshots) == 0 {
+		fmt.Println("no snapshots")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTAKEN\tBYTES")
+	for _, s := range resp.Snapshots {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", s.Name, time.Unix(s.TakenAt, 0).Format(time.RFC3339), s.Bytes)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runStatus prints the health and pool status of the answering instance
+func runStatus(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	service := pb.NewPrimeServiceClient(conn)
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	fs.Parse(args)
+
+	health, err := service.HealthCheck(ctx, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	status, err := service.GetPoolStatus(ctx, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+
+	state := "healthy"
+	if !health.Healthy {
+		state = "UNHEALTHY"
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "instance:\t%s (up %s)\n", health.InstanceId, time.Duration(health.UptimeSeconds)*time.Second)
+	fmt.Fprintf(w, "health:\t%s: %s\n", state, health.Message)
+	for _, warning := range health.Warnings {
+		fmt.Fprintf(w, "warning:\t%s\n", warning)
+	}
+	fmt.Fprintf(w, "pool:\t%s\n", status.Pool)
+	for _, info := range status.Pools {
+		fmt.Fprintf(w, "size:\t%d (min %d, generating: %t)\n", info.Available, info.TargetSize, info.Generating > 0)
+	}
+	fmt.Fprintf(w, "served:\t%d (pool hits %d, misses %d)\n", status.TotalServed, status.PoolHits, status.PoolMisses)
+	fmt.Fprintf(w, "generated:\t%d\n", status.TotalGenerated)
+	if ages := status.ItemAges; ages != nil && ages.OldestGeneratedAt != 0 {
+		fmt.Fprintf(w, "item ages:\t%s to %s (median %s, stale %d)\n",
+			time.Duration(ages.MinSeconds)*time.Second, time.Duration(ages.MaxSeconds)*time.Second,
+			time.Duration(ages.MedianSeconds)*time.Second, ages.Stale)
+	}
+	levels := make([]string, 0, len(status.AssuranceLevels))
+	for level := range status.AssuranceLevels {
+		levels = append(levels, level)
+	}
+	sort.Strings(levels)
+	for _, level := range levels {
+		fmt.Fprintf(w, "assurance %s:\t%d\n", level, status.AssuranceLevels[level])
+	}
+	fmt.Fprintf(w, "maintenance:\t%t\n", status.Maintenance)
+	if status.Frozen {
+		fmt.Fprintf(w, "FROZEN:\t%s\n", status.FreezeReason)
+	}
+	for _, a := range status.Alarms {
+		fmt.Fprintf(w, "ALARM %s:\t%s (since %s)\n", a.Name, a.Message, time.Unix(a.Since, 0).Format(time.RFC3339))
+	}
+	return w.Flush()
+}
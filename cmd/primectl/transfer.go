@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TEENet-io/prime-service/client/lite"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// maxTransfer is the most items the service exports or imports per call
+const maxTransfer = 100
+
+// runGet takes parameter sets like a client and writes them to a file in
+// the format export writes
+func runGet(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ExitOnError)
+	count := fs.Uint("n", 1, "parameter sets to take")
+	out := fs.String("o", "", "file to write the parameter sets to (required; created with mode 0600)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl get -o FILE [-n 1]")
+		fmt.Fprintln(fs.Output(), "The parameter sets are served and gone from the pool; keep the file secret.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *out == "" || *count < 1 {
+		fs.Usage()
+		return errors.New("-o and a count of at least 1 are required")
+	}
+
+	items, err := lite.FetchPreParams(ctx, pb.NewPrimeServiceClient(conn), &pb.GetPreParamsRequest{
+		Count:          uint32(*count),
+		IdempotencyKey: lite.NewIdempotencyKey(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := writeItems(*out, items); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %d parameter sets to %s\n", len(items), *out)
+	return nil
+}
+
+// runExport moves items out of the pool into a file for import elsewhere
+func runExport(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	count := fs.Uint("n", 0, "items to export, oldest first")
+	out := fs.String("o", "", "file to write the items to (required; created with mode 0600)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl export -n N -o FILE")
+		fmt.Fprintln(fs.Output(), "The items leave the pool; import the file elsewhere and keep it secret until then.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *out == "" || *count < 1 {
+		fs.Usage()
+		return errors.New("-n and -o are required")
+	}
+	// Fail before taking anything if the file cannot be created
+	if err := writeItems(*out, nil); err != nil {
+		return err
+	}
+
+	var items []*pb.PreParamsData
+	var poolSize uint32
+	for remaining := int(*count); remaining > 0; {
+		resp, err := admin.ExportItems(ctx, &pb.ExportItemsRequest{Count: uint32(min(remaining, maxTransfer))})
+		if err != nil {
+			if len(items) > 0 {
+				// Keep what has already left the pool
+				if werr := writeItems(*out, items); werr != nil {
+					return fmt.Errorf("%w (and %d exported items were lost: %v)", err, len(items), werr)
+				}
+				return fmt.Errorf("%w (%d items exported to %s before the failure)", err, len(items), *out)
+			}
+			return err
+		}
+		items = append(items, resp.Params...)
+		poolSize = resp.PoolSize
+		if len(resp.Params) == 0 {
+			break
+		}
+		remaining -= len(resp.Params)
+	}
+	if err := writeItems(*out, items); err != nil {
+		return fmt.Errorf("%w (%d exported items were lost)", err, len(items))
+	}
+	fmt.Printf("exported %d items to %s (pool size now %d)\n", len(items), *out, poolSize)
+	return nil
+}
+
+// runImport adds the items of an export or get file to the pool
+func runImport(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl import FILE")
+		fmt.Fprintln(fs.Output(), "Every item is revalidated in full by the service; items failing it are quarantined.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("a file is required")
+	}
+	items, err := readItems(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	accepted := 0
+	var poolSize uint32
+	for start := 0; start < len(items); start += maxTransfer {
+		batch := items[start:min(start+maxTransfer, len(items))]
+		resp, err := admin.ImportItems(ctx, &pb.ImportItemsRequest{Params: batch})
+		if err != nil {
+			return fmt.Errorf("%w (%d of %d items imported)", err, accepted, len(items))
+		}
+		accepted += int(resp.Accepted)
+		poolSize = resp.PoolSize
+	}
+	fmt.Printf("imported %d of %d items (pool size now %d)\n", accepted, len(items), poolSize)
+	if accepted < len(items) {
+		fmt.Println("the rest failed validation, repeated pool items or found the pool full; see primectl errors")
+	}
+	return nil
+}
+
+// runVerify checks the items of an export or get file offline
+func runVerify(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fast := fs.Bool("fast", false, "skip the primality tests")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl verify [-fast] FILE")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("a file is required")
+	}
+	items, err := readItems(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	level := lite.VerifyFull
+	if *fast {
+		level = lite.VerifyFast
+	}
+	failed := 0
+	for i, item := range items {
+		if err := lite.FromProto(item).Verify(level); err != nil {
+			fmt.Printf("item %d (fingerprint %s): %v\n", i, fingerprint(item), err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d items failed verification", failed, len(items))
+	}
+	fmt.Printf("all %d items verified\n", len(items))
+	return nil
+}
+
+// writeItems writes items to path as JSON, readable only by the owner
+func writeItems(path string, items []*pb.PreParamsData) error {
+	data, err := protojson.Marshal(&pb.ImportItemsRequest{Params: items})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// readItems reads the items written by writeItems
+func readItems(path string) ([]*pb.PreParamsData, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var batch pb.ImportItemsRequest
+	if err := protojson.Unmarshal(data, &batch); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(batch.Params) == 0 {
+		return nil, fmt.Errorf("%s holds no items", path)
+	}
+	return batch.Params, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// runWorkers lists, revokes or reinstates registered workers
+func runWorkers(ctx context.Context, conn grpc.ClientConnInterface, args []string) error {
+	admin := pb.NewAdminServiceClient(conn)
+	fs := flag.NewFlagSet("workers", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: primectl workers [list | revoke <worker-id> | reinstate <worker-id>]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	switch fs.Arg(0) {
+	case "list", "":
+	case "revoke", "reinstate":
+		if fs.NArg() != 2 {
+			fs.Usage()
+			return fmt.Errorf("%s needs a worker ID", fs.Arg(0))
+		}
+		w, err := admin.RevokeWorker(ctx, &pb.RevokeWorkerRequest{WorkerId: fs.Arg(1), Reinstate: fs.Arg(0) == "reinstate"})
+		if err != nil {
+			return err
+		}
+		if w.Revoked {
+			fmt.Printf("worker %s revoked: its token is invalid and it cannot register again until reinstated\n", w.WorkerId)
+		} else {
+			fmt.Printf("worker %s reinstated: it can register with the bootstrap token again\n", w.WorkerId)
+		}
+		return nil
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown action %q", fs.Arg(0))
+	}
+
+	resp, err := admin.ListWorkers(ctx, &pb.Empty{})
+	if err != nil {
+		return err
+	}
+	if len(resp.Workers) == 0 {
+		fmt.Println("no workers registered")
+		return nil
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "WORKER\tADDRESS\tSTATE\tTOKEN EXPIRES\tSUBMITTED")
+	for _, wk := range resp.Workers {
+		state, expires := "active", "-"
+		switch {
+		case wk.Revoked:
+			state = "REVOKED"
+		case wk.ExpiresAt == 0 || time.Unix(wk.ExpiresAt, 0).Before(now):
+			state = "expired"
+		}
+		if wk.ExpiresAt > 0 && !wk.Revoked {
+			expires = time.Unix(wk.ExpiresAt, 0).Format(time.RFC3339)
+		}
+		addr := wk.Address
+		if addr == "" {
+			addr = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", wk.WorkerId, addr, state, expires, wk.Submitted)
+	}
+	return w.Flush()
+}
@@ -2,122 +2,103 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"flag"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"github.com/TEENet-io/prime-service/internal/accesslog"
+	"github.com/TEENet-io/prime-service/internal/apikey"
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/config"
 	"github.com/TEENet-io/prime-service/internal/generator"
+	"github.com/TEENet-io/prime-service/internal/logring"
+	"github.com/TEENet-io/prime-service/internal/notify"
 	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/proxyproto"
 	"github.com/TEENet-io/prime-service/internal/server"
+	"github.com/TEENet-io/prime-service/internal/slo"
+	"github.com/TEENet-io/prime-service/internal/traffic"
+	"github.com/TEENet-io/prime-service/internal/workerauth"
 )
 
-type Config struct {
-	Server struct {
-		Address string `json:"address"`
-	} `json:"server"`
-	Pool struct {
-		MinPoolSize     int `json:"min_pool_size"`
-		MaxPoolSize     int `json:"max_pool_size"`
-		RefillThreshold int `json:"refill_threshold"`
-		PrimeBitSize    int `json:"prime_bit_size"`
-		MaxConcurrent   int `json:"max_concurrent"`
-		PoolDir         string `json:"pool_dir"`
-		AutoSave        bool   `json:"auto_save"`
-		BackgroundGen   bool   `json:"background_gen"`
-		RefillInterval  int    `json:"refill_interval"` // seconds
-	} `json:"pool"`
-	Logging struct {
-		Level string `json:"level"`
-	} `json:"logging"`
-}
+func main() {
+	var configPath string
+	flag.StringVar(&configPath, "config", "config.json", "Configuration file path")
+	config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
 
-func loadConfig(path string) (*Config, error) {
-	file, err := os.Open(path)
+	// Load configuration (defaults < file < environment < flags)
+	cfg, err := config.Resolve(configPath, flag.CommandLine)
 	if err != nil {
-		return nil, err
+		log.Fatalf("Invalid configuration: %v", err)
 	}
-	defer file.Close()
 
-	var config Config
-	if err := json.NewDecoder(file).Decode(&config); err != nil {
-		return nil, err
+	// Keys are checked on every call, so a reload can add or revoke them
+	apiKeys, err := apikey.Load(cfg.Auth.Keys, cfg.Auth.KeysFile)
+	if err != nil {
+		log.Fatalf("Failed to load API keys: %v", err)
 	}
 
-	// Set defaults if not specified
-	if config.Server.Address == "" {
-		config.Server.Address = ":50055"
-	}
-	if config.Pool.PoolDir == "" {
-		config.Pool.PoolDir = "./prime_pool"
-	}
-	if config.Pool.MinPoolSize == 0 {
-		config.Pool.MinPoolSize = 10
-	}
-	if config.Pool.MaxPoolSize == 0 {
-		config.Pool.MaxPoolSize = 20
-	}
-	if config.Pool.RefillThreshold == 0 {
-		config.Pool.RefillThreshold = 5
-	}
-	if config.Pool.PrimeBitSize == 0 {
-		config.Pool.PrimeBitSize = 1024
-	}
-	if config.Pool.MaxConcurrent == 0 {
-		config.Pool.MaxConcurrent = 2
-	}
-	if config.Pool.RefillInterval == 0 {
-		config.Pool.RefillInterval = 30
+	if err := checkKeyNamespaces(cfg, apiKeys); err != nil {
+		log.Fatalf("%v", err)
 	}
 
-	return &config, nil
-}
+	// Refuse weak configurations before any key material is generated or served
+	if issues := cfg.HardeningIssues(apiKeys.Enabled()); len(issues) > 0 {
+		for _, issue := range issues {
+			log.Printf("Hardening check failed: %s", issue)
+		}
+		if !cfg.Hardening.AllowInsecure {
+			log.Fatalf("Refusing to start with %d failed hardening checks; fix them or set hardening.allow_insecure", len(issues))
+		}
+		log.Printf("Warning: starting despite failed hardening checks (hardening.allow_insecure)")
+	}
 
-func main() {
-	var configPath string
-	flag.StringVar(&configPath, "config", "config.json", "Configuration file path")
-	flag.Parse()
+	// Keep recent log lines for diagnostic bundles
+	logs := logring.New(cfg.Logging.KeepLines)
+	log.SetOutput(io.MultiWriter(log.Writer(), logs))
+	var effective atomic.Pointer[config.Config] // Updated on reload
+	effective.Store(cfg)
 
-	// Load configuration
-	config, err := loadConfig(configPath)
-	if err != nil {
-		log.Printf("Failed to load config file, using defaults: %v", err)
-		// Use default config
-		config = &Config{}
-		config.Server.Address = ":50055"
-		config.Pool.MinPoolSize = 10
-		config.Pool.MaxPoolSize = 20
-		config.Pool.RefillThreshold = 5
-		config.Pool.PrimeBitSize = 1024
-		config.Pool.MaxConcurrent = 2
-		config.Pool.PoolDir = "./prime_pool"
-		config.Pool.AutoSave = true
-		config.Pool.BackgroundGen = true
-		config.Pool.RefillInterval = 30
-	}
-
-	log.Printf("Starting with config: server=%s, pool_size=%d-%d, storage=%s",
-		config.Server.Address, config.Pool.MinPoolSize, config.Pool.MaxPoolSize, config.Pool.PoolDir)
+	listenAddrs := cfg.Server.ListenAddresses()
+	storage := cfg.Pool.PoolDir
+	if cfg.Pool.Storage == pool.StorageMemory {
+		storage = "memory"
+	} else if cfg.Pool.Storage == pool.StorageRedis {
+		storage = "redis " + cfg.Pool.RedisAddress + " (" + cfg.Pool.PoolDir + ")"
+	}
+	log.Printf("Starting with config: server=%v, pool_size=%d-%d, storage=%s",
+		listenAddrs, cfg.Pool.MinPoolSize, cfg.Pool.MaxPoolSize, storage)
 
 	// Initialize generator
 	gen := generator.NewGenerator()
+	gen.SetConcurrency(cfg.Generator.SafePrimeWorkers, cfg.Generator.CPUBudget)
+	gen.SetWorkers(cfg.Generator.Workers)
+	if err := gen.SetAffinity(cfg.Generator.CPUs()); err != nil {
+		log.Fatalf("Invalid generator CPU affinity: %v", err)
+	}
+	if cfg.Generator.CPUAffinity != "" {
+		log.Printf("Pinning generation threads to CPUs %s", cfg.Generator.CPUAffinity)
+	}
+	if err := gen.SetSafePrimeSource(cfg.Generator.SafePrimeSource); err != nil {
+		log.Fatalf("Invalid generator safe prime source: %v", err)
+	}
+	if gen.SafePrimeSource() != generator.DefaultSafePrimeSource {
+		log.Printf("Searching safe primes with the %s source", gen.SafePrimeSource())
+	}
 
 	// Initialize pool manager with config
-	simpleConfig := pool.SimpleConfig{
-		MinPoolSize:     config.Pool.MinPoolSize,
-		MaxPoolSize:     config.Pool.MaxPoolSize,
-		RefillThreshold: config.Pool.RefillThreshold,
-		PrimeBitSize:    config.Pool.PrimeBitSize,
-		MaxConcurrent:   config.Pool.MaxConcurrent,
-		PoolDir:         config.Pool.PoolDir,
-		AutoSave:        config.Pool.AutoSave,
-		BackgroundGen:   config.Pool.BackgroundGen,
-		RefillInterval:  time.Duration(config.Pool.RefillInterval) * time.Second,
-	}
-	poolManager := pool.NewManager(gen, simpleConfig)
+	notifier := notify.New(cfg.Notify.WebhookURL)
+	poolManager := pool.NewManager(gen, cfg.Pool)
+	poolManager.SetNotifier(notifier)
 
 	// Start pool manager
 	ctx, cancel := context.WithCancel(context.Background())
@@ -128,20 +109,203 @@ func main() {
 	}
 	defer poolManager.Stop()
 
+	// Start the named pools, each with its own storage and settings
+	pools := make(map[string]*pool.Manager, len(cfg.Pools))
+	servedPools := make(map[string]server.PoolManager, len(cfg.Pools))
+	for _, p := range cfg.Pools {
+		m := pool.NewManager(gen, p.PoolConfig)
+		m.SetNotifier(notifier)
+		if err := m.Start(ctx); err != nil {
+			log.Fatalf("Failed to start pool %s: %v", p.Name, err)
+		}
+		defer m.Stop()
+		pools[p.Name] = m
+		servedPools[p.Name] = m
+		log.Printf("Serving pool %s: pool_size=%d-%d, bits=%d/%d, storage=%s",
+			p.Name, p.MinPoolSize, p.MaxPoolSize, p.PrimeBitSize, p.PaillierBitSize, p.Storage)
+	}
+
+	// Open audit log (memory storage writes nothing to disk, so no audit log)
+	var auditLog *audit.Logger
+	if cfg.Pool.Storage != pool.StorageMemory {
+		auditLog, err = audit.NewLogger(filepath.Join(cfg.Pool.PoolDir, "audit.log"), poolManager.InstanceID())
+		if err != nil {
+			log.Fatalf("Failed to open audit log: %v", err)
+		}
+		defer auditLog.Close()
+	}
+	poolManager.SetAuditLog(auditLog)
+	for _, m := range pools {
+		m.SetAuditLog(auditLog)
+	}
+
+	// Open access log
+	accessLog, err := accesslog.New(accessLogConfig(cfg.Logging.AccessLog), poolManager.InstanceID())
+	if err != nil {
+		log.Fatalf("Failed to open access log: %v", err)
+	}
+	if accessLog != nil {
+		defer accessLog.Close()
+		log.Printf("Writing access log to %s", strings.Join(accessLogTargets(cfg.Logging.AccessLog), " and "))
+	}
+
+	// Open traffic recording
+	var recorder *traffic.Recorder
+	if cfg.Server.RecordTraffic != "" {
+		recorder, err = traffic.NewRecorder(cfg.Server.RecordTraffic)
+		if err != nil {
+			log.Fatalf("Failed to open traffic recording: %v", err)
+		}
+		defer recorder.Close()
+		log.Printf("Recording GetPreParams traffic to %s", cfg.Server.RecordTraffic)
+	}
+
 	// Start gRPC server
+	serverOpts := []server.Option{
+		server.WithAuditLog(auditLog),
+		server.WithAccessLog(accessLog),
+		server.WithPools(servedPools),
+		server.WithMaxResponseBytes(cfg.Server.MaxResponseBytes),
+		server.WithMaxRequestedBitSize(cfg.Server.MaxRequestedBitSize),
+		server.WithMaxBatchSize(cfg.Server.MaxBatchSize),
+		server.WithSLO(slo.New(cfg.SLO)),
+		server.WithTrafficRecording(recorder),
+		server.WithAllowedOrigins(cfg.Server.WebAllowedOrigins),
+		server.WithDeadlines(time.Duration(cfg.Server.DefaultDeadline)*time.Second, time.Duration(cfg.Server.MaxDeadline)*time.Second),
+		server.WithLocality(cfg.Server.Region, cfg.Server.Zone),
+		server.WithDiagnostics(effective.Load, logs),
+	}
+	if cfg.Server.LoadReportInterval > 0 {
+		serverOpts = append(serverOpts, server.WithLoadReporting(time.Duration(cfg.Server.LoadReportInterval)*time.Second))
+	}
+	serverOpts = append(serverOpts, server.WithAPIKeys(apiKeys))
+	if apiKeys.Enabled() {
+		log.Printf("API key authentication enabled (%d keys, %d admin)", apiKeys.Len(), apiKeys.Admins())
+		if apiKeys.Admins() == 0 {
+			log.Printf("Warning: no admin API key configured; admin calls are refused")
+		}
+	} else {
+		log.Printf("Warning: no API keys configured; anyone reaching the service can take parameters")
+	}
+	if cfg.Server.TLS.Enabled() {
+		serverTLS, err := server.LoadTLS(cfg.Server.TLS)
+		if err != nil {
+			log.Fatalf("Failed to set up TLS: %v", err)
+		}
+		serverOpts = append(serverOpts, server.WithTLS(serverTLS))
+		log.Printf("TLS enabled (certificate: %s, client CAs: %q, client certificate required: %t)",
+			cfg.Server.TLS.CertFile, cfg.Server.TLS.ClientCAFile, cfg.Server.TLS.RequireClientCert)
+	}
+	if len(cfg.Server.ProxyProtocolTrusted) > 0 {
+		trusted, err := proxyproto.ParseTrusted(cfg.Server.ProxyProtocolTrusted)
+		if err != nil {
+			log.Fatalf("Invalid PROXY protocol configuration: %v", err)
+		}
+		serverOpts = append(serverOpts, server.WithProxyProtocol(trusted))
+	}
+	if cfg.Worker.BootstrapToken != "" {
+		// Revocations outlive restarts unless nothing may be written to disk
+		revocations := ""
+		if cfg.Pool.Storage != pool.StorageMemory {
+			revocations = filepath.Join(cfg.Pool.PoolDir, "revoked_workers.json")
+		}
+		registry, err := workerauth.New(cfg.Worker.BootstrapToken, time.Duration(cfg.Worker.TokenTTL)*time.Second, revocations)
+		if err != nil {
+			log.Fatalf("Failed to load worker registrations: %v", err)
+		}
+		serverOpts = append(serverOpts, server.WithWorkerRegistration(registry))
+	}
+	if cfg.Peer.Token != "" {
+		serverOpts = append(serverOpts, server.WithPeerSharing(cfg.Peer.Token, cfg.Peer.Peers,
+			time.Duration(cfg.Peer.SyncInterval)*time.Second, cfg.Peer.MaxTransfer))
+	}
+	if cfg.Bootstrap.Upstream != "" {
+		serverOpts = append(serverOpts, server.WithBootstrap(cfg.Bootstrap.Upstream, cfg.Bootstrap.APIKey,
+			cfg.Bootstrap.Count, time.Duration(cfg.Bootstrap.Timeout)*time.Second))
+		if cfg.Bootstrap.Proxy {
+			serverOpts = append(serverOpts, server.WithUpstreamProxy(
+				time.Duration(cfg.Bootstrap.ProxyInterval)*time.Second, cfg.Bootstrap.ProxyMaxPull))
+		}
+	}
+
 	go func() {
-		if err := server.StartGRPCServer(config.Server.Address, poolManager); err != nil {
+		if err := server.StartGRPCServer(listenAddrs, poolManager, serverOpts...); err != nil {
 			log.Fatalf("Failed to start gRPC server: %v", err)
 		}
 	}()
 
-	log.Printf("Prime service started on %s", config.Server.Address)
+	// Start admin HTTP server
+	if cfg.Server.AdminHTTPAddress != "" {
+		go func() {
+			if err := server.StartAdminHTTPServer(cfg.Server.AdminHTTPAddress, poolManager, serverOpts...); err != nil {
+				log.Fatalf("Failed to start admin HTTP server: %v", err)
+			}
+		}()
+	}
 
-	// Wait for interrupt signal
+	// Start web server for browser and WASM clients
+	if cfg.Server.WebAddress != "" {
+		go func() {
+			if err := server.StartWebServer(cfg.Server.WebAddress, poolManager, serverOpts...); err != nil {
+				log.Fatalf("Failed to start web server: %v", err)
+			}
+		}()
+	}
+
+	log.Printf("Prime service started on %v", listenAddrs)
+
+	// Wait for interrupt signal, handling control signals meanwhile
+	ctl := &controller{configPath: configPath, cfg: cfg, effective: &effective, gen: gen, poolManager: poolManager, pools: pools, apiKeys: apiKeys}
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	signal.Notify(sigChan, append([]os.Signal{syscall.SIGINT, syscall.SIGTERM}, controlSignals...)...)
+	for sig := <-sigChan; sig != syscall.SIGINT && sig != syscall.SIGTERM; sig = <-sigChan {
+		log.Printf("Received %s", sig)
+		ctl.handleControlSignal(sig)
+	}
 
 	log.Println("Shutting down prime service...")
 	cancel() // Cancel context to stop background operations
-}
\ No newline at end of file
+}
+
+// checkKeyNamespaces refuses API keys not bound to a namespace while tenant
+// quotas are enforced, since their callers could charge any namespace
+func checkKeyNamespaces(cfg *config.Config, keys *apikey.Set) error {
+	if !cfg.TenantQuotas() {
+		return nil
+	}
+	if unbound := keys.Unbound(); len(unbound) > 0 {
+		return fmt.Errorf("API keys %s are not bound to a namespace; with tenant quotas every key needs one", strings.Join(unbound, ", "))
+	}
+	return nil
+}
+
+// accessLogConfig converts the access log settings, where -1 disables a limit
+func accessLogConfig(c config.AccessLogConfig) accesslog.Config {
+	cfg := accesslog.Config{
+		Path:           c.Path,
+		MaxSize:        int64(c.MaxSizeMB) << 20,
+		RotateInterval: time.Duration(c.RotateHours) * time.Hour,
+		MaxBackups:     c.MaxBackups,
+		Syslog:         c.Syslog,
+		SyslogTag:      c.SyslogTag,
+	}
+	if c.MaxSizeMB < 0 {
+		cfg.MaxSize = 0
+	}
+	if c.MaxBackups < 0 {
+		cfg.MaxBackups = 0
+	}
+	return cfg
+}
+
+// accessLogTargets names where access entries go, for the startup log
+func accessLogTargets(c config.AccessLogConfig) []string {
+	var targets []string
+	if c.Path != "" {
+		targets = append(targets, c.Path)
+	}
+	if c.Syslog != "" {
+		targets = append(targets, "syslog "+c.Syslog)
+	}
+	return targets
+}
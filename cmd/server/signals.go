@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"reflect"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/TEENet-io/prime-service/internal/apikey"
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/generator"
+	"github.com/TEENet-io/prime-service/internal/pool"
+)
+
+// controller carries out the operations mapped to control signals, for
+// hosts where the admin API cannot be reached
+type controller struct {
+	configPath  string
+	cfg         *config.Config
+	effective   *atomic.Pointer[config.Config] // cfg, for diagnostic bundles
+	gen         *generator.Generator
+	poolManager *pool.Manager
+	pools       map[string]*pool.Manager // Named pools
+	apiKeys     *apikey.Set
+}
+
+// dumpStatus logs the pool status and all goroutine stacks
+func (c *controller) dumpStatus() {
+	status := c.poolManager.GetPoolStatus()
+	keys := make([]string, 0, len(status))
+	for k := range status {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	log.Printf("Pool status dump:")
+	for _, k := range keys {
+		log.Printf("  %s: %v", k, status[k])
+	}
+
+	log.Printf("Goroutine dump:")
+	if err := pprof.Lookup("goroutine").WriteTo(log.Writer(), 2); err != nil {
+		log.Printf("Failed to dump goroutines: %v", err)
+	}
+}
+
+// save writes the pool to disk immediately
+func (c *controller) save() {
+	log.Printf("Saving pool on signal")
+	c.poolManager.Save()
+}
+
+// reload re-resolves the configuration (file, environment and the original
+// flags) and applies the runtime-adjustable pool and generator settings
+func (c *controller) reload() {
+	cfg, err := config.Resolve(c.configPath, flag.CommandLine)
+	if err != nil {
+		log.Printf("Config reload failed, keeping current config: %v", err)
+		return
+	}
+
+	// Server settings take effect after a restart, so the hardening checks
+	// see the running listeners with the reloaded API keys
+	check := *cfg
+	check.Server = c.cfg.Server
+	keys := c.apiKeys
+	if loaded, err := apikey.Load(cfg.Auth.Keys, cfg.Auth.KeysFile); err == nil {
+		keys = loaded
+	}
+	if err := checkKeyNamespaces(cfg, keys); err != nil {
+		log.Printf("Config reload failed, keeping current config: %v", err)
+		return
+	}
+	authEnabled := keys.Enabled()
+	if issues := check.HardeningIssues(authEnabled); len(issues) > 0 && !cfg.Hardening.AllowInsecure {
+		log.Printf("Config reload failed, keeping current config: failed hardening checks: %s", strings.Join(issues, "; "))
+		return
+	}
+	if err := c.poolManager.Reload(cfg.Pool); err != nil {
+		log.Printf("Config reload failed, keeping current config: %v", err)
+		return
+	}
+	c.gen.SetConcurrency(cfg.Generator.SafePrimeWorkers, cfg.Generator.CPUBudget)
+	c.gen.SetWorkers(cfg.Generator.Workers)
+	if err := c.gen.SetAffinity(cfg.Generator.CPUs()); err != nil {
+		log.Printf("Generator CPU affinity not reloaded, keeping the current one: %v", err)
+	}
+	if err := c.gen.SetSafePrimeSource(cfg.Generator.SafePrimeSource); err != nil {
+		log.Printf("Generator safe prime source not reloaded, keeping %s: %v", c.gen.SafePrimeSource(), err)
+	}
+
+	// Named pools are reloaded individually; adding or removing one needs a restart
+	running := len(c.pools)
+	for _, p := range cfg.Pools {
+		m, ok := c.pools[p.Name]
+		if !ok {
+			log.Printf("Warning: pool %s is not running; new pools take effect after a restart", p.Name)
+			continue
+		}
+		running--
+		if err := m.Reload(p.PoolConfig); err != nil {
+			log.Printf("Config reload of pool %s failed, keeping its current config: %v", p.Name, err)
+		}
+	}
+	if running > 0 {
+		log.Printf("Warning: removed pools keep running until a restart")
+	}
+
+	if err := c.apiKeys.Update(cfg.Auth.Keys, cfg.Auth.KeysFile); err != nil {
+		log.Printf("API keys not reloaded, keeping the current ones: %v", err)
+		cfg.Auth = c.cfg.Auth
+	} else if !reflect.DeepEqual(cfg.Auth, c.cfg.Auth) || cfg.Auth.KeysFile != "" {
+		log.Printf("Reloaded API keys (%d keys, %d admin)", c.apiKeys.Len(), c.apiKeys.Admins())
+	}
+
+	if !reflect.DeepEqual(cfg.Server, c.cfg.Server) || !reflect.DeepEqual(cfg.Peer, c.cfg.Peer) || !reflect.DeepEqual(cfg.SLO, c.cfg.SLO) {
+		log.Printf("Warning: server, peer and SLO settings changed; they take effect after a restart")
+	}
+	cfg.Server, cfg.Peer, cfg.SLO = c.cfg.Server, c.cfg.Peer, c.cfg.SLO
+	c.cfg = cfg
+	c.effective.Store(cfg)
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// controlSignals is empty where SIGUSR1/SIGUSR2/SIGHUP are unavailable
+var controlSignals []os.Signal
+
+func (c *controller) handleControlSignal(sig os.Signal) {}
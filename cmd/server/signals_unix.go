@@ -0,0 +1,24 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// controlSignals are handled without shutting down:
+// SIGUSR1 dumps status and goroutines, SIGUSR2 saves the pool, SIGHUP reloads the config
+var controlSignals = []os.Signal{syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGHUP}
+
+// handleControlSignal runs the operation mapped to sig
+func (c *controller) handleControlSignal(sig os.Signal) {
+	switch sig {
+	case syscall.SIGUSR1:
+		c.dumpStatus()
+	case syscall.SIGUSR2:
+		c.save()
+	case syscall.SIGHUP:
+		c.reload()
+	}
+}
@@ -0,0 +1,238 @@
+// Package accesslog records one structured line per RPC, separately from
+// the application log, for ingestion by security operations tooling. Lines
+// go to a size and time rotated JSON lines file and, optionally, to syslog.
+package accesslog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// rotatedLayout is the timestamp suffix of rotated files, sortable by name
+const rotatedLayout = "20060102T150405.000000000Z"
+
+// Entry is a single access record
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Instance  string    `json:"instance,omitempty"`
+	Method    string    `json:"method"`             // Full gRPC method, e.g. /prime.PrimeService/GetPreParams
+	Identity  string    `json:"identity,omitempty"` // API client name or client certificate subject ("": anonymous)
+	Auth      string    `json:"auth,omitempty"`     // How the identity was established: api_key or tls_cert
+	Remote    string    `json:"remote,omitempty"`   // Network address of the caller, behind any PROXY protocol proxy
+	Pool      string    `json:"pool,omitempty"`     // Pool named by the caller
+	Requested uint32    `json:"requested,omitempty"`
+	Served    int       `json:"served,omitempty"` // Parameter sets sent
+	Outcome   string    `json:"outcome"`          // gRPC status code, e.g. OK or Unauthenticated
+	LatencyMs float64   `json:"latency_ms"`
+	TraceID   string    `json:"trace_id,omitempty"`
+}
+
+// Config selects where entries go and when the file rotates
+type Config struct {
+	Path           string        // JSON lines file ("": none)
+	MaxSize        int64         // Rotate before the file exceeds this many bytes (0: never by size)
+	RotateInterval time.Duration // Rotate files older than this (0: never by age)
+	MaxBackups     int           // Rotated files kept (0: all)
+	Syslog         string        // "local", or network://host:port, e.g. udp://siem:514 ("": none)
+	SyslogTag      string
+}
+
+// shipper forwards entries to a remote collector
+type shipper interface {
+	Info(line string) error
+	Close() error
+}
+
+// Logger writes access entries. A nil Logger discards them.
+type Logger struct {
+	cfg      Config
+	instance string
+
+	mu      sync.Mutex
+	file    *os.File
+	size    int64
+	opened  time.Time
+	shipper shipper
+}
+
+// New opens the access log configured by cfg, stamping entries with the
+// given instance ID. It returns nil if cfg names neither a file nor syslog.
+func New(cfg Config, instance string) (*Logger, error) {
+	if cfg.Path == "" && cfg.Syslog == "" {
+		return nil, nil
+	}
+	l := &Logger{cfg: cfg, instance: instance}
+	if cfg.Path != "" {
+		if err := l.open(); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Syslog != "" {
+		s, err := dialSyslog(cfg.Syslog, cfg.SyslogTag)
+		if err != nil {
+			l.Close()
+			return nil, fmt.Errorf("failed to connect to syslog %s: %w", cfg.Syslog, err)
+		}
+		l.shipper = s
+	}
+	return l, nil
+}
+
+// open opens the file for appending, taking its age from its modification
+// time so restarts do not postpone time-based rotation indefinitely.
+// Caller must hold l.mu, or own l exclusively.
+func (l *Logger) open() error {
+	file, err := os.OpenFile(l.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open access log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to open access log: %w", err)
+	}
+	l.file, l.size, l.opened = file, info.Size(), time.Now()
+	if info.Size() > 0 {
+		l.opened = info.ModTime()
+	}
+	return nil
+}
+
+// Record writes an entry, stamping the current time and instance ID if
+// unset. The file is written even if shipping to syslog fails.
+func (l *Logger) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Instance == "" {
+		e.Instance = l.instance
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal access entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var fileErr error
+	if l.file != nil {
+		fileErr = l.write(append(line, '\n'), e.Time)
+	}
+	if l.shipper != nil {
+		if err := l.shipper.Info(string(line)); err != nil && fileErr == nil {
+			return fmt.Errorf("failed to ship access entry: %w", err)
+		}
+	}
+	return fileErr
+}
+
+// write appends line to the file, rotating it first when due.
+// Caller must hold l.mu.
+func (l *Logger) write(line []byte, now time.Time) error {
+	bySize := l.cfg.MaxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.cfg.MaxSize
+	byAge := l.cfg.RotateInterval > 0 && l.size > 0 && now.Sub(l.opened) >= l.cfg.RotateInterval
+	if bySize || byAge {
+		if err := l.rotate(now); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write access entry: %w", err)
+	}
+	return nil
+}
+
+// rotate renames the file aside with a timestamp suffix, opens a new one
+// and removes rotated files beyond MaxBackups.
+// Caller must hold l.mu.
+func (l *Logger) rotate(now time.Time) error {
+	if err := l.file.Close(); err != nil {
+		return fmt.Errorf("failed to rotate access log: %w", err)
+	}
+	rotated := l.cfg.Path + "." + now.UTC().Format(rotatedLayout)
+	if err := os.Rename(l.cfg.Path, rotated); err != nil {
+		// Keep writing to the current file rather than losing entries
+		if oerr := l.open(); oerr != nil {
+			return oerr
+		}
+		return fmt.Errorf("failed to rotate access log: %w", err)
+	}
+	if err := l.open(); err != nil {
+		return err
+	}
+	l.prune()
+	return nil
+}
+
+// prune removes the oldest rotated files beyond MaxBackups.
+// Caller must hold l.mu.
+func (l *Logger) prune() {
+	if l.cfg.MaxBackups <= 0 {
+		return
+	}
+	rotated, err := filepath.Glob(l.cfg.Path + ".*")
+	if err != nil || len(rotated) <= l.cfg.MaxBackups {
+		return
+	}
+	sort.Strings(rotated)
+	for _, path := range rotated[:len(rotated)-l.cfg.MaxBackups] {
+		os.Remove(path)
+	}
+}
+
+// Close closes the file and the syslog connection
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var err error
+	if l.file != nil {
+		err = l.file.Close()
+	}
+	if l.shipper != nil {
+		l.shipper.Close()
+	}
+	return err
+}
+
+// callKey is the context key of the identity of a call
+type callKey struct{}
+
+// call collects what handlers learn about the caller
+type call struct {
+	mu             sync.Mutex
+	identity, auth string
+}
+
+// WithCall returns a context on which SetIdentity records the identity of
+// the call, and a function returning it
+func WithCall(ctx context.Context) (context.Context, func() (identity, auth string)) {
+	c := &call{}
+	return context.WithValue(ctx, callKey{}, c), func() (string, string) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.identity, c.auth
+	}
+}
+
+// SetIdentity records who made the call on ctx and how that was established
+func SetIdentity(ctx context.Context, identity, auth string) {
+	if c, ok := ctx.Value(callKey{}).(*call); ok {
+		c.mu.Lock()
+		c.identity, c.auth = identity, auth
+		c.mu.Unlock()
+	}
+}
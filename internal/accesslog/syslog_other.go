@@ -0,0 +1,10 @@
+//go:build !unix
+
+package accesslog
+
+import "errors"
+
+// dialSyslog fails: syslog is only available on Unix systems
+func dialSyslog(target, tag string) (shipper, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,25 @@
+//go:build unix
+
+package accesslog
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+)
+
+// dialSyslog connects to the local syslog daemon ("local") or to a
+// collector given as network://host:port
+func dialSyslog(target, tag string) (shipper, error) {
+	if tag == "" {
+		tag = "prime-service"
+	}
+	network, addr := "", ""
+	if target != "local" {
+		var ok bool
+		if network, addr, ok = strings.Cut(target, "://"); !ok {
+			return nil, fmt.Errorf("syslog target must be local or network://host:port")
+		}
+	}
+	return syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_AUTHPRIV, tag)
+}
@@ -0,0 +1,81 @@
+// Package affinity pins threads to CPU sets given as Linux CPU lists
+// ("0-3,6"), as in taskset and cpuset.cpus.
+package affinity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxCPU bounds CPU numbers in CPU lists
+const MaxCPU = 1024
+
+// ErrUnsupported is returned where threads cannot be pinned
+var ErrUnsupported = errors.New("CPU affinity is not supported on this platform")
+
+// Parse parses a CPU list such as "0-3,6" into sorted, distinct CPU numbers.
+// An empty list parses to nil.
+func Parse(list string) ([]int, error) {
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return nil, nil
+	}
+
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(list, ",") {
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		first, err := parseCPU(lo)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = parseCPU(hi); err != nil {
+				return nil, err
+			}
+			if last < first {
+				return nil, fmt.Errorf("invalid CPU range %q", part)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			seen[cpu] = true
+		}
+	}
+
+	cpus := make([]int, 0, len(seen))
+	for cpu := range seen {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
+
+// parseCPU parses one CPU number
+func parseCPU(s string) (int, error) {
+	cpu, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || cpu < 0 || cpu >= MaxCPU {
+		return 0, fmt.Errorf("invalid CPU %q (must be 0-%d)", s, MaxCPU-1)
+	}
+	return cpu, nil
+}
+
+// Format formats sorted CPU numbers as a CPU list, the inverse of Parse
+func Format(cpus []int) string {
+	var parts []string
+	for i := 0; i < len(cpus); {
+		j := i
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(cpus[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
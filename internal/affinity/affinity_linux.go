@@ -0,0 +1,54 @@
+//go:build linux
+
+package affinity
+
+import "golang.org/x/sys/unix"
+
+// Pin restricts the calling thread to cpus and returns a function restoring
+// its previous affinity. The caller must have locked the goroutine to its
+// thread (runtime.LockOSThread) until it has called restore.
+func Pin(cpus []int) (restore func(), err error) {
+	var old unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &old); err != nil {
+		return nil, err
+	}
+	set := cpuSet(cpus)
+	if err := unix.SchedSetaffinity(0, &set); err != nil {
+		return nil, err
+	}
+	return func() { unix.SchedSetaffinity(0, &old) }, nil
+}
+
+// PinnedTo reports whether the calling thread may run on exactly cpus
+func PinnedTo(cpus []int) bool {
+	var current unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &current); err != nil {
+		return false
+	}
+	return current == cpuSet(cpus)
+}
+
+// Allowed returns the CPUs the calling thread may run on, which threads
+// inherit from the process unless pinned
+func Allowed() ([]int, error) {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &set); err != nil {
+		return nil, err
+	}
+	var cpus []int
+	for cpu := 0; cpu < MaxCPU; cpu++ {
+		if set.IsSet(cpu) {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// cpuSet converts CPU numbers to a CPUSet
+func cpuSet(cpus []int) unix.CPUSet {
+	var set unix.CPUSet
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+	return set
+}
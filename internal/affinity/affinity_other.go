@@ -0,0 +1,18 @@
+//go:build !linux
+
+package affinity
+
+// Pin is not supported outside Linux
+func Pin(cpus []int) (restore func(), err error) {
+	return nil, ErrUnsupported
+}
+
+// Allowed is not supported outside Linux
+func Allowed() ([]int, error) {
+	return nil, ErrUnsupported
+}
+
+// PinnedTo is never true outside Linux
+func PinnedTo(cpus []int) bool {
+	return false
+}
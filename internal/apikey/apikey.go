@@ -0,0 +1,202 @@
+// Package apikey authenticates PrimeService clients by the API key they
+// send with each call
+package apikey
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// ErrInvalidKey is returned by Check for missing and unknown keys
+var ErrInvalidKey = errors.New("missing or invalid API key")
+
+// Key is one admitted client. The key itself may be given as its hex
+// SHA-256 instead, to keep it out of configuration files.
+type Key struct {
+	Name   string `json:"name"`             // Client name in logs
+	Key    string `json:"key,omitempty"`    // The key
+	SHA256 string `json:"sha256,omitempty"` // Or its hex SHA-256
+
+	// Namespace, if set, is the tenant namespace every call with the key is
+	// charged to, whatever namespace the call names
+	Namespace string `json:"namespace,omitempty"`
+
+	// Admin admits the key to the AdminService RPCs, which inspect and
+	// change the service's state
+	Admin bool `json:"admin,omitempty"`
+}
+
+// hash returns the SHA-256 the key is looked up by
+func (k Key) hash() ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+	switch {
+	case k.Name == "":
+		return sum, errors.New("API key without a name")
+	case (k.Key == "") == (k.SHA256 == ""):
+		return sum, fmt.Errorf("API key %s needs exactly one of key and sha256", k.Name)
+	case k.Key != "":
+		return sha256.Sum256([]byte(k.Key)), nil
+	}
+	raw, err := hex.DecodeString(k.SHA256)
+	if err != nil || len(raw) != sha256.Size {
+		return sum, fmt.Errorf("API key %s: sha256 must be 64 hex digits", k.Name)
+	}
+	copy(sum[:], raw)
+	return sum, nil
+}
+
+// Validate checks keys for missing names, malformed hashes and duplicates
+func Validate(keys []Key) error {
+	_, err := index(keys)
+	return err
+}
+
+// index maps the hashes of keys to their names
+func index(keys []Key) (map[[sha256.Size]byte]string, error) {
+	byHash := make(map[[sha256.Size]byte]string, len(keys))
+	for _, k := range keys {
+		sum, err := k.hash()
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := byHash[sum]; ok {
+			return nil, fmt.Errorf("API keys %s and %s are the same key", other, k.Name)
+		}
+		byHash[sum] = k.Name
+	}
+	return byHash, nil
+}
+
+// ReadFile reads a JSON array of keys, as kept in auth.keys_file
+func ReadFile(path string) ([]Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+	var keys []Key
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// Set is the set of admitted keys. Without any key, authentication is off
+// and every call is admitted.
+type Set struct {
+	mu         sync.RWMutex
+	byHash     map[[sha256.Size]byte]string
+	namespaces map[string]string // Client name to bound namespace
+	admins     map[string]bool   // Clients with admin keys
+	unbound    []string          // Clients not bound to a namespace
+}
+
+// Load builds a set from keys plus those in file (if not empty)
+func Load(keys []Key, file string) (*Set, error) {
+	s := &Set{}
+	if err := s.Update(keys, file); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update replaces the admitted keys, e.g. on a configuration reload. On
+// error the current keys stay in effect.
+func (s *Set) Update(keys []Key, file string) error {
+	all := append([]Key(nil), keys...)
+	if file != "" {
+		fromFile, err := ReadFile(file)
+		if err != nil {
+			return err
+		}
+		all = append(all, fromFile...)
+	}
+	byHash, err := index(all)
+	if err != nil {
+		return err
+	}
+	namespaces := make(map[string]string)
+	admins := make(map[string]bool)
+	var unbound []string
+	for _, k := range all {
+		if k.Namespace != "" {
+			namespaces[k.Name] = k.Namespace
+		} else {
+			unbound = append(unbound, k.Name)
+		}
+		if k.Admin {
+			admins[k.Name] = true
+		}
+	}
+
+	s.mu.Lock()
+	s.byHash, s.namespaces, s.admins, s.unbound = byHash, namespaces, admins, unbound
+	s.mu.Unlock()
+	return nil
+}
+
+// Enabled reports whether any key is configured
+func (s *Set) Enabled() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.byHash) > 0
+}
+
+// Len returns the number of admitted keys
+func (s *Set) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.byHash)
+}
+
+// Check returns the name of the client key belongs to. Keys are compared
+// by their SHA-256, so lookups reveal nothing about the keys themselves.
+func (s *Set) Check(key string) (string, error) {
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	name, ok := s.byHash[sha256.Sum256([]byte(key))]
+	if !ok {
+		return "", ErrInvalidKey
+	}
+	return name, nil
+}
+
+// Namespace returns the tenant namespace the key of client is bound to
+// ("": none)
+func (s *Set) Namespace(client string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.namespaces[client]
+}
+
+// Admin reports whether the key of client is an admin key
+func (s *Set) Admin(client string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.admins[client]
+}
+
+// Admins returns the number of admitted admin keys
+func (s *Set) Admins() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.admins)
+}
+
+// Unbound returns the names of the clients whose keys are not bound to a
+// namespace
+func (s *Set) Unbound() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.unbound...)
+}
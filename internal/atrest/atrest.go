@@ -0,0 +1,173 @@
+// Package atrest encrypts files holding parameter secrets (pool files,
+// request journals, quarantined items) at rest with AES-256-GCM
+package atrest
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// KeySize is the size of encryption keys (AES-256)
+const KeySize = 32
+
+// magic starts every encrypted file; the plaintext files are JSON and never do
+const magic = "PRIME-ENC1\n"
+
+// keyIDSize is the length of the key fingerprint stored in each file
+const keyIDSize = 8
+
+// commandTimeout bounds EncryptionKeyCommand runs
+const commandTimeout = 30 * time.Second
+
+var (
+	// ErrNoKey is returned by Open for encrypted data without a key configured
+	ErrNoKey = errors.New("file is encrypted but no encryption key is configured")
+
+	// ErrWrongKey is returned by Open for data encrypted with another key
+	ErrWrongKey = errors.New("file is encrypted with a different key")
+
+	// ErrPlaintext is returned by Open for plaintext data once a key is
+	// configured, unless AllowPlaintext was called
+	ErrPlaintext = errors.New("file is not encrypted but an encryption key is configured")
+)
+
+// Cipher encrypts and decrypts with one key. A nil *Cipher stands for
+// disabled encryption: Seal returns the plaintext and Open only accepts
+// plaintext.
+type Cipher struct {
+	aead           cipher.AEAD
+	id             []byte
+	allowPlaintext bool
+}
+
+// New returns a Cipher for a KeySize key
+func New(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("encryption key has %d bytes, want %d", len(key), KeySize)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(append([]byte("prime-service key id\x00"), key...))
+	return &Cipher{aead: aead, id: sum[:keyIDSize]}, nil
+}
+
+// KeyID returns the fingerprint identifying the key in encrypted files
+func (c *Cipher) KeyID() string {
+	return hex.EncodeToString(c.id)
+}
+
+// AllowPlaintext makes Open return plaintext data as is, for a one-time
+// migration of files written before encryption was enabled
+func (c *Cipher) AllowPlaintext() {
+	c.allowPlaintext = true
+}
+
+// Encrypted reports whether data was written by Seal
+func Encrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(magic))
+}
+
+// Seal encrypts plaintext bound to label, the kind of file it is written
+// to, so one file's contents cannot be passed off as another's
+func (c *Cipher) Seal(plaintext []byte, label string) ([]byte, error) {
+	if c == nil {
+		return plaintext, nil
+	}
+	header := make([]byte, 0, len(magic)+keyIDSize+c.aead.NonceSize())
+	header = append(header, magic...)
+	header = append(header, c.id...)
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	aad := append(header[:len(header):len(header)], label...)
+	header = append(header, nonce...)
+	return c.aead.Seal(header, nonce, plaintext, aad), nil
+}
+
+// Open decrypts data sealed with label. Plaintext data is refused with
+// ErrPlaintext, so a planted file cannot replace an encrypted one, unless
+// AllowPlaintext was called or encryption is disabled.
+func (c *Cipher) Open(data []byte, label string) ([]byte, error) {
+	if !Encrypted(data) {
+		if c != nil && !c.allowPlaintext {
+			return nil, ErrPlaintext
+		}
+		return data, nil
+	}
+	if c == nil {
+		return nil, ErrNoKey
+	}
+	if len(data) < len(magic)+keyIDSize+c.aead.NonceSize()+c.aead.Overhead() {
+		return nil, errors.New("encrypted file is truncated")
+	}
+	header := data[:len(magic)+keyIDSize]
+	if !bytes.Equal(header[len(magic):], c.id) {
+		return nil, fmt.Errorf("%w (key ID %x, configured %s)", ErrWrongKey, header[len(magic):], c.KeyID())
+	}
+	nonce := data[len(header) : len(header)+c.aead.NonceSize()]
+	aad := append(header[:len(header):len(header)], label...)
+	plaintext, err := c.aead.Open(nil, nonce, data[len(header)+len(nonce):], aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s file (corrupt or tampered): %w", label, err)
+	}
+	return plaintext, nil
+}
+
+// ParseKey decodes a key given as 64 hex digits or as base64
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if key, err := hex.DecodeString(s); err == nil && len(key) == KeySize {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(s); err == nil && len(key) == KeySize {
+		return key, nil
+	}
+	return nil, fmt.Errorf("encryption key must be %d bytes as hex or base64", KeySize)
+}
+
+// LoadKey reads the key from at most one source: the key itself, a file
+// holding it, or a shell command printing it (e.g. a KMS decrypt of a
+// wrapped data key). It returns nil if none is set.
+func LoadKey(key, file, command string) ([]byte, error) {
+	switch {
+	case key != "":
+		return ParseKey(key)
+	case file != "":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read encryption key file: %w", err)
+		}
+		return ParseKey(string(data))
+	case command != "":
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "/bin/sh", "-c", command)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("encryption key command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return ParseKey(string(out))
+	}
+	return nil, nil
+}
@@ -0,0 +1,185 @@
+package atrest
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testCipher returns a Cipher for a key of repeated b
+func testCipher(t *testing.T, b byte) *Cipher {
+	t.Helper()
+	c, err := New(bytes.Repeat([]byte{b}, KeySize))
+	if err != nil {
+		t.Fatalf("New() = %v", err)
+	}
+	return c
+}
+
+func TestOpen(t *testing.T) {
+	plaintext := []byte(`{"items":[]}`)
+	sealed := func(t *testing.T) []byte {
+		data, err := testCipher(t, 1).Seal(plaintext, "pool")
+		if err != nil {
+			t.Fatalf("Seal() = %v", err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name    string
+		open    func(t *testing.T) *Cipher
+		data    func(t *testing.T) []byte
+		label   string
+		wantErr error  // Checked with errors.Is
+		errMsg  string // Checked if wantErr is nil
+	}{
+		{
+			name:  "round trip",
+			open:  func(t *testing.T) *Cipher { return testCipher(t, 1) },
+			data:  sealed,
+			label: "pool",
+		},
+		{
+			name:    "wrong key",
+			open:    func(t *testing.T) *Cipher { return testCipher(t, 2) },
+			data:    sealed,
+			label:   "pool",
+			wantErr: ErrWrongKey,
+		},
+		{
+			name:   "other label",
+			open:   func(t *testing.T) *Cipher { return testCipher(t, 1) },
+			data:   sealed,
+			label:  "journal",
+			errMsg: "corrupt or tampered",
+		},
+		{
+			name: "tampered ciphertext",
+			open: func(t *testing.T) *Cipher { return testCipher(t, 1) },
+			data: func(t *testing.T) []byte {
+				data := sealed(t)
+				data[len(data)-1] ^= 0x01
+				return data
+			},
+			label:  "pool",
+			errMsg: "corrupt or tampered",
+		},
+		{
+			name: "tampered nonce",
+			open: func(t *testing.T) *Cipher { return testCipher(t, 1) },
+			data: func(t *testing.T) []byte {
+				data := sealed(t)
+				data[len(magic)+keyIDSize] ^= 0x01
+				return data
+			},
+			label:  "pool",
+			errMsg: "corrupt or tampered",
+		},
+		{
+			name:   "truncated",
+			open:   func(t *testing.T) *Cipher { return testCipher(t, 1) },
+			data:   func(t *testing.T) []byte { return sealed(t)[:len(magic)+keyIDSize+4] },
+			label:  "pool",
+			errMsg: "truncated",
+		},
+		{
+			name:    "encrypted without key",
+			open:    func(t *testing.T) *Cipher { return nil },
+			data:    sealed,
+			label:   "pool",
+			wantErr: ErrNoKey,
+		},
+		{
+			name:    "plaintext refused",
+			open:    func(t *testing.T) *Cipher { return testCipher(t, 1) },
+			data:    func(t *testing.T) []byte { return plaintext },
+			label:   "pool",
+			wantErr: ErrPlaintext,
+		},
+		{
+			name: "plaintext migration",
+			open: func(t *testing.T) *Cipher {
+				c := testCipher(t, 1)
+				c.AllowPlaintext()
+				return c
+			},
+			data:  func(t *testing.T) []byte { return plaintext },
+			label: "pool",
+		},
+		{
+			name:  "plaintext without key",
+			open:  func(t *testing.T) *Cipher { return nil },
+			data:  func(t *testing.T) []byte { return plaintext },
+			label: "pool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.open(t).Open(tt.data(t), tt.label)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Open() = %v, want %v", err, tt.wantErr)
+				}
+			case tt.errMsg != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("Open() = %v, want error containing %q", err, tt.errMsg)
+				}
+			case err != nil:
+				t.Fatalf("Open() = %v", err)
+			case !bytes.Equal(got, plaintext):
+				t.Fatalf("Open() = %q, want %q", got, plaintext)
+			}
+		})
+	}
+}
+
+func TestSeal(t *testing.T) {
+	plaintext := []byte("secret")
+	c := testCipher(t, 1)
+	a, err := c.Seal(plaintext, "pool")
+	if err != nil {
+		t.Fatalf("Seal() = %v", err)
+	}
+	b, err := c.Seal(plaintext, "pool")
+	if err != nil {
+		t.Fatalf("Seal() = %v", err)
+	}
+	if !Encrypted(a) || bytes.Contains(a, plaintext) {
+		t.Fatal("sealed data is not encrypted")
+	}
+	if bytes.Equal(a, b) {
+		t.Fatal("sealing twice gave the same output")
+	}
+
+	var disabled *Cipher
+	if got, _ := disabled.Seal(plaintext, "pool"); !bytes.Equal(got, plaintext) {
+		t.Fatal("disabled cipher changed the plaintext")
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"hex", strings.Repeat("ab", KeySize), false},
+		{"hex with newline", strings.Repeat("ab", KeySize) + "\n", false},
+		{"base64", "q6urq6urq6urq6urq6urq6urq6urq6urq6urq6urq6s=", false},
+		{"short hex", strings.Repeat("ab", KeySize-1), true},
+		{"garbage", "not a key", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKey() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry is a single audit record
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Instance string    `json:"instance,omitempty"` // Instance ID of the recording service
+	Event    string    `json:"event"`
+	Peer     string    `json:"peer,omitempty"`
+	Remote   string    `json:"remote,omitempty"` // Network address of the caller, behind any PROXY protocol proxy
+	Count    int       `json:"count,omitempty"`
+	Items    []uint64  `json:"items,omitempty"` // Sequence numbers of the items concerned
+	Detail   string    `json:"detail,omitempty"`
+	TraceID  string    `json:"trace_id,omitempty"` // Request that caused the event
+}
+
+// Logger appends audit entries as JSON lines to a file
+type Logger struct {
+	mu       sync.Mutex
+	file     *os.File
+	instance string
+}
+
+// NewLogger opens (or creates) an append-only audit log at path whose
+// entries are stamped with the given instance ID
+func NewLogger(path, instance string) (*Logger, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &Logger{file: file, instance: instance}, nil
+}
+
+// Record appends an entry, stamping the current time and instance ID if unset.
+// A nil Logger discards entries.
+func (l *Logger) Record(e Entry) error {
+	if l == nil {
+		return nil
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	if e.Instance == "" {
+		e.Instance = l.instance
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,1158 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/affinity"
+	"github.com/TEENet-io/prime-service/internal/apikey"
+	"github.com/TEENet-io/prime-service/internal/atrest"
+	"github.com/TEENet-io/prime-service/internal/proxyproto"
+)
+
+// Default values shared by the server binary and embedded pool users
+const (
+	DefaultAddress         = ":50055"
+	DefaultMaxResponse     = 4 << 20 // gRPC's default client receive limit
+	DefaultMaxRequestedBit = 4096    // Largest bit size GetPreParams generates on request
+	DefaultMaxBatchSize    = 100     // Most items one GetPreParams call may request
+	DefaultDeadline        = 30      // Seconds, for PrimeService calls without one
+	DefaultMaxDeadline     = 300     // Seconds, the longest PrimeService deadline honored
+	DefaultMinPoolSize     = 10
+	DefaultMaxPoolSize     = 20
+	DefaultRefillThreshold = 5
+	DefaultPrimeBitSize    = 1024
+	DefaultPaillierBitSize = 2048
+	DefaultMaxConcurrent   = 2
+	DefaultMaxOnDemand     = 2
+	DefaultPoolDir         = "./prime_pool"
+	DefaultRefillInterval  = 30 * time.Second
+	DefaultStartupDelay    = 10 * time.Second
+	DefaultThrottle        = 1 * time.Second
+	DefaultIdempotencyTTL  = 24 * time.Hour
+	DefaultErrorJournal    = 200
+	DefaultRefillHistory   = 50
+	DefaultSelectionPolicy = "oldest"
+	DefaultStalePolicy     = "regenerate"
+	DefaultDeniedPolicy    = "refuse"
+	DefaultStorage         = "file"
+	DefaultRedisKey        = "prime:pool"
+	DefaultSaveBatchItems  = 1
+	DefaultFsync           = "never"
+	DefaultFsyncInterval   = 1 * time.Second
+	DefaultAlarmWindow     = 5 * time.Minute
+	DefaultFreezeFailures  = 3
+	DefaultFreezeWindow    = 10 * time.Minute
+	DefaultSnapshotEvery   = 24 * time.Hour
+	DefaultSnapshotKeep    = 7
+	DefaultLogLevel        = "info"
+	DefaultLogKeepLines    = 2000
+	DefaultAccessLogSizeMB = 100
+	DefaultAccessLogKeep   = 10 // Rotated access log files kept
+	DefaultPeerSync        = 60
+	DefaultPeerMaxTransfer = 10
+	DefaultWorkerTokenTTL  = 3600 // Seconds a worker token is valid
+	DefaultSeedTimeout     = 120  // Seconds seeding from a bootstrap upstream may take
+	DefaultProxyInterval   = 5    // Seconds between checks of a proxying edge
+	DefaultProxyMaxPull    = 20   // Items a proxying edge pulls per check
+	DefaultMinPrimeBits    = 1024 // Hardening floor for prime_bit_size
+	DefaultMinPaillierBits = 2048 // Hardening floor for paillier_bit_size
+
+	// Default latency objective: 99% of pool-served GetPreParams calls within 100ms
+	DefaultSLOWindow    = 24 * time.Hour
+	DefaultSLOThreshold = 100 * time.Millisecond
+	DefaultSLOTarget    = 0.99
+
+	// DefaultPoolName routes to the top-level pool section
+	DefaultPoolName = "default"
+
+	// DefaultNamespace is charged for requests naming no tenant namespace
+	DefaultNamespace = "default"
+)
+
+// Config is the complete service configuration
+type Config struct {
+	Server ServerConfig `json:"server"`
+	Pool   PoolConfig   `json:"pool"`
+
+	// Pools are additional, independent pools (own storage, sizes and bit
+	// sizes) that clients select by name, e.g. to serve staging and
+	// production parameter classes from one deployment with hard separation
+	Pools []NamedPoolConfig `json:"pools,omitempty"`
+
+	Generator GeneratorConfig `json:"generator"` // Shared by all pools
+
+	Peer      PeerConfig      `json:"peer"`
+	Bootstrap BootstrapConfig `json:"bootstrap"`
+	Worker    WorkerConfig    `json:"worker"`
+	Notify    NotifyConfig    `json:"notify"`
+	SLO       SLOConfig       `json:"slo"`
+	Logging   LoggingConfig   `json:"logging"`
+	Auth      AuthConfig      `json:"auth"`
+	Hardening HardeningConfig `json:"hardening"`
+}
+
+// ServerConfig contains gRPC listener settings
+type ServerConfig struct {
+	Address string `json:"address"`
+
+	// Listeners, when set, replace Address so the service can listen on
+	// several addresses at once (e.g. IPv4 and IPv6, or multiple interfaces)
+	Listeners []ListenerConfig `json:"listeners,omitempty"`
+
+	// ProxyProtocolTrusted lists the L4 proxies (CIDR blocks or IPv4/IPv6
+	// addresses) whose connections to the gRPC and web listeners start with
+	// a PROXY protocol v1 or v2 header, so audit entries and worker records
+	// show the real client address. Other connections are served as usual
+	// (empty disables).
+	ProxyProtocolTrusted []string `json:"proxy_protocol_trusted,omitempty"`
+
+	// AdminHTTPAddress serves admin HTTP endpoints such as /pressure (empty disables)
+	AdminHTTPAddress string `json:"admin_http_address"`
+
+	// WebAddress serves the unary PrimeService RPCs with the Connect,
+	// gRPC-Web and cleartext gRPC protocols, and a REST gateway under /v1,
+	// for browsers, WASM clients, dashboards and curl scripts (empty
+	// disables). Browsers on other origins need to be listed in
+	// WebAllowedOrigins ("*": any).
+	WebAddress        string   `json:"web_address"`
+	WebAllowedOrigins []string `json:"web_allowed_origins,omitempty"`
+
+	// LoadReportInterval enables ORCA backend metrics for xDS/Envoy load
+	// balancers, refreshed every given number of seconds (0 disables)
+	LoadReportInterval int `json:"load_report_interval"`
+
+	// MaxResponseBytes bounds GetPreParams responses; larger batches are
+	// refused before any item is consumed and clients switch to
+	// StreamPreParams, which sends them in messages of at most this size
+	// (default: 4 MiB, the gRPC client default)
+	MaxResponseBytes int `json:"max_response_bytes"`
+
+	// MaxRequestedBitSize bounds the bit sizes GetPreParams generates
+	// synchronously when a client asks for sizes no pool serves (default:
+	// 4096); requests naming a pool's sizes are always routed to it
+	MaxRequestedBitSize int `json:"max_requested_bit_size"`
+
+	// MaxBatchSize bounds the items one GetPreParams, StreamPreParams or
+	// WaitForPreParams call may request (default: 100). Unary calls are
+	// further bounded by the pool's max_pool_size; larger ceremonies stream.
+	MaxBatchSize int `json:"max_batch_size"`
+
+	// PrimeService calls without a deadline get DefaultDeadline, and longer
+	// client deadlines are shortened to MaxDeadline, so on-demand generation
+	// cannot hold a worker indefinitely (seconds, 0 disables either)
+	DefaultDeadline int `json:"default_deadline"`
+	MaxDeadline     int `json:"max_deadline"`
+
+	// Region and Zone are reported in HealthCheck and GetPoolStatus so
+	// clients can prefer replicas in their own region (empty: not reported)
+	Region string `json:"region,omitempty"`
+	Zone   string `json:"zone,omitempty"`
+
+	// RecordTraffic appends an anonymized JSON-lines record of every
+	// GetPreParams call (time, count, flags, outcome, pool size) to this
+	// file for primectl replay (empty disables)
+	RecordTraffic string `json:"record_traffic"`
+
+	// TLS secures the gRPC and web listeners (default: plaintext)
+	TLS TLSConfig `json:"tls"`
+}
+
+// TLSConfig contains the server certificate and client authentication
+// settings. Certificate files are reread when they change, so rotated
+// certificates take effect without a restart.
+type TLSConfig struct {
+	CertFile string `json:"cert_file"` // PEM certificate chain (empty: plaintext)
+	KeyFile  string `json:"key_file"`  // PEM private key of CertFile
+
+	// ClientCAFile is a PEM bundle of CAs client certificates are verified
+	// against; clients presenting none are still admitted unless
+	// RequireClientCert is set (mutual TLS)
+	ClientCAFile      string `json:"client_ca_file"`
+	RequireClientCert bool   `json:"require_client_cert"`
+}
+
+// Enabled reports whether the listeners use TLS
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// validate checks that the TLS settings are complete
+func (t TLSConfig) validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("server.tls.cert_file and server.tls.key_file must be set together")
+	}
+	if t.ClientCAFile != "" && !t.Enabled() {
+		return fmt.Errorf("server.tls.client_ca_file requires server.tls.cert_file")
+	}
+	if t.RequireClientCert && t.ClientCAFile == "" {
+		return fmt.Errorf("server.tls.require_client_cert requires server.tls.client_ca_file")
+	}
+	return nil
+}
+
+// ListenerConfig is one gRPC listen address
+type ListenerConfig struct {
+	Address string `json:"address"`           // e.g. "0.0.0.0:50055" or "[::]:50055"
+	Enabled *bool  `json:"enabled,omitempty"` // Defaults to true
+}
+
+// IsEnabled reports whether the listener should be started
+func (l ListenerConfig) IsEnabled() bool {
+	return l.Enabled == nil || *l.Enabled
+}
+
+// ListenAddresses returns the enabled gRPC listen addresses: the enabled
+// Listeners if any are configured, otherwise Address
+func (s *ServerConfig) ListenAddresses() []string {
+	if len(s.Listeners) == 0 {
+		return []string{s.Address}
+	}
+	var addrs []string
+	for _, l := range s.Listeners {
+		if l.IsEnabled() {
+			addrs = append(addrs, l.Address)
+		}
+	}
+	return addrs
+}
+
+// PoolConfig contains configuration for the parameter pool
+type PoolConfig struct {
+	// Pool size limits
+	MinPoolSize     int `json:"min_pool_size"`    // Minimum items to maintain in pool
+	MaxPoolSize     int `json:"max_pool_size"`    // Maximum items in pool
+	RefillThreshold int `json:"refill_threshold"` // When to start refilling
+
+	// Generation settings
+	PrimeBitSize    int  `json:"prime_bit_size"`    // Bit size for safe primes (default: 1024)
+	PaillierBitSize int  `json:"paillier_bit_size"` // Bit size for Paillier modulus (default: 2048)
+	MaxConcurrent   int  `json:"max_concurrent"`    // Workers of housekeeping refills (default: 2)
+	OnDemandGen     bool `json:"on_demand_gen"`     // Generate synchronously when the pool cannot satisfy a request
+	MaxOnDemand     int  `json:"max_on_demand"`     // Concurrent on-demand generations; further misses queue (default: 2)
+
+	// Serving
+	SelectionPolicy string `json:"selection_policy"` // Which items to serve: oldest, newest or random (default: oldest)
+
+	// Items older than MaxAge are never served as fresh (seconds in JSON, zero
+	// disables). StalePolicy decides what happens when only stale items are
+	// left: "regenerate" discards them and generates replacements
+	// synchronously, "serve" hands them out flagged as stale. Either way a
+	// background refill is started.
+	MaxAge      time.Duration `json:"max_age"`
+	StalePolicy string        `json:"stale_policy"`
+
+	// Items generated by a generator version in DeniedVersions ("unknown"
+	// for items from before versions were recorded), e.g. one with a
+	// generation bug, are discarded and never served with
+	// DeniedVersionPolicy "refuse" (default), or served with a journaled
+	// warning with "warn"
+	DeniedVersions      []string `json:"denied_versions,omitempty"`
+	DeniedVersionPolicy string   `json:"denied_version_policy"`
+
+	// Items from the same host and burst count as distinct for DistinctProvenance
+	// requests once generated this far apart; zero requires a different burst (seconds in JSON)
+	AntiCorrelationWindow time.Duration `json:"anti_correlation_window"`
+
+	// Items from elsewhere (peer transfers, legacy files, pool files written
+	// by another instance) older than ImportRevalidateAge get full primality
+	// validation, an imported provenance flag and an audit entry (seconds in
+	// JSON, zero disables). With ImportDemote they are only served to
+	// requests that allow imported items.
+	ImportRevalidateAge time.Duration `json:"import_revalidate_age"`
+	ImportDemote        bool          `json:"import_demote"`
+
+	// With VerifyPrimalityOnLoad, standard items loaded from the pool file
+	// get full primality validation at startup (P, Q and the Paillier factors
+	// prime, 2P+1 and 2Q+1 safe primes) and are raised to audited; items
+	// failing it are quarantined. Off, only the fast algebraic checks run.
+	VerifyPrimalityOnLoad bool `json:"verify_primality_on_load"`
+
+	// Items are standard (passed the generator's checks) or audited (also
+	// passed full primality validation, like revalidated imports). Standard
+	// items are audited in the background until MinAudited pool items are
+	// (zero disables), for requests demanding the audited level.
+	MinAudited int `json:"min_audited"`
+
+	// With SecureDelete, superseded pool file and request journal contents
+	// are overwritten with zeros once replaced. Backups (archived legacy pool
+	// files) older than BackupRetention are overwritten and removed (seconds
+	// in JSON, zero keeps them).
+	SecureDelete    bool          `json:"secure_delete"`
+	BackupRetention time.Duration `json:"backup_retention"`
+
+	// Snapshots are encrypted, timestamped copies of the pool written to
+	// SnapshotDir, a secondary location such as another disk or a network
+	// mount (empty disables), every SnapshotInterval (seconds in JSON,
+	// default: a day), keeping the newest SnapshotKeep (default: 7).
+	// SnapshotCommand runs after each snapshot with its path in
+	// PRIME_SNAPSHOT_FILE, e.g. to copy it to an object store. Snapshots
+	// need file storage and an encryption key.
+	SnapshotDir      string        `json:"snapshot_dir,omitempty"`
+	SnapshotInterval time.Duration `json:"snapshot_interval"`
+	SnapshotKeep     int           `json:"snapshot_keep"`
+	SnapshotCommand  string        `json:"snapshot_command,omitempty"`
+
+	// The pool file, request journal and quarantined items are encrypted
+	// with AES-256-GCM under a 32-byte key (hex or base64) given by at most
+	// one of: EncryptionKey itself (best from PRIME_POOL_ENCRYPTION_KEY),
+	// EncryptionKeyFile, or EncryptionKeyCommand, a shell command printing
+	// it, e.g. a KMS decrypt of a wrapped data key. None leaves them plaintext.
+	EncryptionKey        string `json:"encryption_key,omitempty"`
+	EncryptionKeyFile    string `json:"encryption_key_file,omitempty"`
+	EncryptionKeyCommand string `json:"encryption_key_command,omitempty"`
+
+	// With an encryption key, plaintext pool files, journals and snapshots
+	// are refused unless AllowPlaintextMigration is set for the one start
+	// that encrypts the files written before encryption was enabled
+	AllowPlaintextMigration bool `json:"allow_plaintext_migration,omitempty"`
+
+	// Stable instance identity recorded in provenance, audit entries and
+	// status; generated and persisted in <pool_dir>/instance_id if empty
+	InstanceID string `json:"instance_id"`
+
+	// Persistence
+	Storage  string `json:"storage"`   // file (default), memory: nothing is written to disk, or redis
+	PoolDir  string `json:"pool_dir"`  // Directory to store pool data
+	AutoSave bool   `json:"auto_save"` // Auto save pool to disk
+
+	// With redis storage the pool is a list in the Redis server at
+	// RedisAddress, shared by every replica using the same server, database
+	// and key prefix; journals and history stay under PoolDir. The list of a
+	// profile is <RedisKey>:<prime bits>-<paillier bits>. Needs Redis 6.2+.
+	RedisAddress  string `json:"redis_address,omitempty"`
+	RedisPassword string `json:"redis_password,omitempty"`
+	RedisDB       int    `json:"redis_db,omitempty"`
+	RedisKey      string `json:"redis_key,omitempty"` // Key prefix (default: prime:pool)
+	RedisTLS      bool   `json:"redis_tls,omitempty"`
+
+	// With AutoSave, items entering the pool are saved in group commits: once
+	// SaveBatchItems (default 1) are unsaved, or SaveBatchDelay after the
+	// first of them (seconds in JSON, zero: when the refill ends or the pool
+	// is next saved). Fsync decides when saved pool files and ledgers reach
+	// stable storage: "always" on every write, "interval" at most
+	// FsyncInterval later (seconds in JSON), "never" when the OS flushes them
+	// (default).
+	SaveBatchItems int           `json:"save_batch_items"`
+	SaveBatchDelay time.Duration `json:"save_batch_delay"`
+	Fsync          string        `json:"fsync"`
+	FsyncInterval  time.Duration `json:"fsync_interval"`
+
+	// Background generation
+	BackgroundGen  bool          `json:"background_gen"`  // Enable background generation
+	RefillInterval time.Duration `json:"refill_interval"` // How often to check and refill (seconds in JSON)
+
+	// Idempotency-key allocations are replayed for this long (seconds in JSON)
+	IdempotencyTTL time.Duration `json:"idempotency_ttl"`
+
+	// Number of recent errors kept in <pool_dir>/errors.json (default: 200)
+	ErrorJournalSize int `json:"error_journal_size"`
+
+	// Number of recent refill cycles kept in <profile>.cycles.json next to
+	// the pool file (default: 50)
+	RefillHistory int `json:"refill_history"`
+
+	// Consumption alarms (zero disables): more than AlarmServedLimit items
+	// served within AlarmServedWindow, or the pool emptying within
+	// AlarmEmptyWithin at the current net consumption rate (seconds in JSON)
+	AlarmServedLimit  int           `json:"alarm_served_limit"`
+	AlarmServedWindow time.Duration `json:"alarm_served_window"`
+	AlarmEmptyWithin  time.Duration `json:"alarm_empty_within"`
+
+	// Anomaly freeze: stop serving (generation continues) after duplicate
+	// parameters, FreezeFailureLimit validation failures within
+	// FreezeFailureWindow, or an audit write failure, until an admin unfreezes
+	FreezeOnAnomaly     bool          `json:"freeze_on_anomaly"`
+	FreezeFailureLimit  int           `json:"freeze_failure_limit"`
+	FreezeFailureWindow time.Duration `json:"freeze_failure_window"`
+
+	// Warmup and throttling (default: 10s and 1s, negative disables)
+	StartupDelay       time.Duration `json:"startup_delay"`       // No generation until this long after start (seconds in JSON)
+	GenerationThrottle time.Duration `json:"generation_throttle"` // Pause between items per housekeeping worker (seconds in JSON)
+
+	// Emergency refills, started when a request finds too few items in the
+	// pool, run on EmergencyConcurrent workers (zero: one per CPU) with
+	// EmergencyThrottle between items (seconds in JSON)
+	EmergencyConcurrent int           `json:"emergency_concurrent"`
+	EmergencyThrottle   time.Duration `json:"emergency_throttle"`
+
+	// Tenants are the namespaces requests are charged to when several
+	// clients (e.g. TEE nodes) share the pool, each with optional quotas.
+	// Requests naming no namespace are charged to "default", which needs no
+	// entry; other namespaces must be listed.
+	Tenants []TenantConfig `json:"tenants,omitempty"`
+}
+
+// TenantConfig is a namespace and the items its requests may take per
+// clock hour and per day (UTC; zero: unlimited)
+type TenantConfig struct {
+	Name        string `json:"name"`
+	HourlyQuota int    `json:"hourly_quota,omitempty"`
+	DailyQuota  int    `json:"daily_quota,omitempty"`
+}
+
+// hasQuotas reports whether any tenant of the pool has a quota
+func (p *PoolConfig) hasQuotas() bool {
+	for _, t := range p.Tenants {
+		if t.HourlyQuota > 0 || t.DailyQuota > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// TenantQuotas reports whether any pool enforces tenant quotas, which need
+// every API key bound to a namespace
+func (c *Config) TenantQuotas() bool {
+	if c.Pool.hasQuotas() {
+		return true
+	}
+	for _, p := range c.Pools {
+		if p.hasQuotas() {
+			return true
+		}
+	}
+	return false
+}
+
+// NamedPoolConfig is an additional pool selected by its name. The pool
+// settings are given next to the name and default like the pool section;
+// environment variables and flags only apply to the pool section.
+type NamedPoolConfig struct {
+	Name string
+	PoolConfig
+}
+
+// MarshalJSON encodes the name alongside the pool settings
+func (n NamedPoolConfig) MarshalJSON() ([]byte, error) {
+	data, err := n.PoolConfig.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	raw["name"], _ = json.Marshal(n.Name)
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON decodes the name and the pool settings, starting from the
+// pool section defaults
+func (n *NamedPoolConfig) UnmarshalJSON(data []byte) error {
+	var named struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &named); err != nil {
+		return err
+	}
+	*n = NamedPoolConfig{Name: named.Name, PoolConfig: Default().Pool}
+	return n.PoolConfig.UnmarshalJSON(data)
+}
+
+// PeerConfig contains pool sharing settings between replicas
+type PeerConfig struct {
+	Token        string   `json:"token"`         // Shared secret; empty disables sharing
+	Peers        []string `json:"peers"`         // Peer addresses to pull surplus items from
+	SyncInterval int      `json:"sync_interval"` // Seconds between pulls while under-filled
+	MaxTransfer  int      `json:"max_transfer"`  // Maximum items pulled per sync
+}
+
+// BootstrapConfig seeds a brand-new instance from a trusted upstream
+// prime-service, so it serves at once instead of waiting for local
+// generation. With Proxy set the instance keeps pulling from the upstream
+// whenever its pool runs low, acting as a regional cache of a central
+// generation farm. Pulled items are fully validated and flagged as imported.
+type BootstrapConfig struct {
+	Upstream string `json:"upstream"`          // gRPC address of the upstream service; empty disables seeding
+	APIKey   string `json:"api_key,omitempty"` // Sent in x-api-key if the upstream requires API keys
+	Count    int    `json:"count"`             // Items to seed (0: pool.min_pool_size)
+	Timeout  int    `json:"timeout"`           // Seconds before seeding gives up
+
+	Proxy         bool `json:"proxy"`          // Top up from the upstream while below pool.min_pool_size
+	ProxyInterval int  `json:"proxy_interval"` // Seconds between checks of the pool
+	ProxyMaxPull  int  `json:"proxy_max_pull"` // Maximum items pulled per check
+}
+
+// WorkerConfig enables parameter submission from generate-only workers.
+// Workers register with the bootstrap token and get a short-lived token of
+// their own to submit parameters with.
+type WorkerConfig struct {
+	BootstrapToken string `json:"bootstrap_token"` // Registration secret; empty disables submission
+	TokenTTL       int    `json:"token_ttl"`       // Seconds an issued worker token is valid
+}
+
+// SLOConfig defines per-RPC latency objectives, tracked in memory over Window
+type SLOConfig struct {
+	Window     time.Duration  `json:"window"` // Compliance window (seconds in JSON, default: 24h)
+	Objectives []SLOObjective `json:"objectives"`
+}
+
+// SLOObjective requires Target of the calls to Method (optionally only those
+// of Class) to complete within Threshold
+type SLOObjective struct {
+	Name      string        `json:"name"`
+	Method    string        `json:"method"`    // RPC name, e.g. GetPreParams
+	Class     string        `json:"class"`     // pool_hit, generated, pool_miss or empty for every call
+	Threshold time.Duration `json:"threshold"` // Seconds in JSON (e.g. 0.1) or a duration string ("100ms")
+	Target    float64       `json:"target"`    // e.g. 0.99
+}
+
+// sloConfigJSON and sloObjectiveJSON are the on-disk shapes (durations in seconds)
+type (
+	sloConfigJSON    SLOConfig
+	sloObjectiveJSON SLOObjective
+)
+
+// MarshalJSON encodes durations as seconds
+func (c SLOConfig) MarshalJSON() ([]byte, error) {
+	plain := sloConfigJSON(c)
+	return marshalSeconds(&plain)
+}
+
+// UnmarshalJSON decodes durations given as seconds or duration strings
+func (c *SLOConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalSeconds(data, (*sloConfigJSON)(c))
+}
+
+// MarshalJSON encodes durations as seconds
+func (o SLOObjective) MarshalJSON() ([]byte, error) {
+	plain := sloObjectiveJSON(o)
+	return marshalSeconds(&plain)
+}
+
+// UnmarshalJSON decodes durations given as seconds or duration strings.
+// Configured objectives replace the default one rather than inheriting
+// its fields.
+func (o *SLOObjective) UnmarshalJSON(data []byte) error {
+	*o = SLOObjective{}
+	return unmarshalSeconds(data, (*sloObjectiveJSON)(o))
+}
+
+// GeneratorConfig contains settings of the parameter generator, which all
+// pools share
+type GeneratorConfig struct {
+	// SafePrimeWorkers is the number of workers searching for the
+	// safe primes of one item (0: automatic, at most 4). Either way an item
+	// gets no more than its share of CPUBudget among the items generating.
+	SafePrimeWorkers int `json:"safe_prime_workers"`
+
+	// CPUBudget is the number of CPUs generation may use across all pools
+	// (0: all CPUs, or all of CPUAffinity)
+	CPUBudget int `json:"cpu_budget"`
+
+	// Workers is the number of items generated at once across all pools
+	// (0: one per CPU of CPUBudget, at least 2). Requests generating on
+	// demand, emergency refills and background refills and fills share
+	// these slots in that order of priority: a refill item gives its slot
+	// up to a waiting request and resumes once one is free.
+	Workers int `json:"workers"`
+
+	// CPUAffinity pins the threads generating parameters to a Linux CPU
+	// list such as "2-3" (empty: no pinning), keeping the other cores for
+	// serving and co-located processes
+	CPUAffinity string `json:"cpu_affinity"`
+
+	// SafePrimeSource selects the safe prime search algorithm by name:
+	// "tss-lib" (default); others can be registered with
+	// generator.RegisterSafePrimeSource
+	SafePrimeSource string `json:"safe_prime_source"`
+}
+
+// CPUs returns the CPUs of CPUAffinity (nil: no pinning); Validate has
+// checked the list
+func (g *GeneratorConfig) CPUs() []int {
+	cpus, _ := affinity.Parse(g.CPUAffinity)
+	return cpus
+}
+
+// NotifyConfig contains alarm notification settings
+type NotifyConfig struct {
+	WebhookURL string `json:"webhook_url"` // Alarm events are POSTed here as JSON (empty: log only)
+}
+
+// AuthConfig lists the API keys clients must send to get parameters
+// (GetPreParams, StreamPreParams and WaitForPreParams). Without any key in
+// Keys or KeysFile, calls are not authenticated. Both are reloadable, so
+// keys can be added and revoked without a restart.
+type AuthConfig struct {
+	Keys     []apikey.Key `json:"keys,omitempty"`
+	KeysFile string       `json:"keys_file,omitempty"` // JSON array of keys in the same form
+}
+
+// LoggingConfig contains logging settings
+type LoggingConfig struct {
+	Level     string          `json:"level"`
+	KeepLines int             `json:"keep_lines"` // Recent log lines kept in memory for CollectDiagnostics (default: 2000)
+	AccessLog AccessLogConfig `json:"access_log"`
+}
+
+// AccessLogConfig configures the access log: one JSON line per RPC with the
+// method, caller identity, counts, outcome and latency, kept apart from the
+// application log. It is written if Path or Syslog is set.
+type AccessLogConfig struct {
+	Path        string `json:"path,omitempty"`         // JSON lines file, rotated to path.<UTC timestamp>
+	MaxSizeMB   int    `json:"max_size_mb,omitempty"`  // Rotate before the file exceeds this size (default: 100, -1: never)
+	RotateHours int    `json:"rotate_hours,omitempty"` // Rotate files older than this many hours (0: never)
+	MaxBackups  int    `json:"max_backups,omitempty"`  // Rotated files kept (default: 10, -1: all)
+	Syslog      string `json:"syslog,omitempty"`       // Also ship entries to syslog: "local" or network://host:port, e.g. udp://siem:514
+	SyslogTag   string `json:"syslog_tag,omitempty"`   // Default: prime-service
+}
+
+// Default returns a configuration with every field set to its default
+func Default() *Config {
+	config := &Config{}
+	config.Server.DefaultDeadline = DefaultDeadline
+	config.Server.MaxDeadline = DefaultMaxDeadline
+	config.Pool.AutoSave = true
+	config.Pool.SecureDelete = true
+	config.Pool.VerifyPrimalityOnLoad = true
+	config.Pool.BackgroundGen = true
+	config.ApplyDefaults()
+	return config
+}
+
+// Load reads a JSON or YAML configuration file, fills in defaults and validates the result
+func Load(path string) (*Config, error) {
+	config := Default()
+	if err := config.loadFile(path); err != nil {
+		return nil, err
+	}
+
+	config.ApplyDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Resolve builds the effective configuration from defaults, the config file
+// (skipped if missing), PRIME_* environment variables and explicitly set
+// flags, in increasing order of precedence
+func Resolve(path string, fs *flag.FlagSet) (*Config, error) {
+	config := Default()
+	if err := config.loadFile(path); errors.Is(err, os.ErrNotExist) {
+		log.Printf("Config file %s not found, using defaults", path)
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := config.ApplyEnv(); err != nil {
+		return nil, err
+	}
+	if fs != nil {
+		if err := config.ApplyFlags(fs); err != nil {
+			return nil, err
+		}
+	}
+
+	config.ApplyDefaults()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// loadFile decodes a JSON or, for .yaml and .yml files, YAML configuration
+// file over the current values
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if isYAML(path) {
+		if data, err = yamlToJSON(data); err != nil {
+			return fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	return nil
+}
+
+// ApplyDefaults fills in zero-valued fields with their defaults
+func (c *Config) ApplyDefaults() {
+	if c.Server.Address == "" {
+		c.Server.Address = DefaultAddress
+	}
+	if c.Server.MaxResponseBytes == 0 {
+		c.Server.MaxResponseBytes = DefaultMaxResponse
+	}
+	if c.Server.MaxRequestedBitSize == 0 {
+		c.Server.MaxRequestedBitSize = DefaultMaxRequestedBit
+	}
+	if c.Server.MaxBatchSize == 0 {
+		c.Server.MaxBatchSize = DefaultMaxBatchSize
+	}
+	if c.Logging.Level == "" {
+		c.Logging.Level = DefaultLogLevel
+	}
+	if c.Logging.KeepLines == 0 {
+		c.Logging.KeepLines = DefaultLogKeepLines
+	}
+	if c.Logging.AccessLog.MaxSizeMB == 0 {
+		c.Logging.AccessLog.MaxSizeMB = DefaultAccessLogSizeMB
+	}
+	if c.Logging.AccessLog.MaxBackups == 0 {
+		c.Logging.AccessLog.MaxBackups = DefaultAccessLogKeep
+	}
+	c.Pool.ApplyDefaults()
+	for i := range c.Pools {
+		c.Pools[i].ApplyDefaults()
+	}
+	if c.SLO.Window == 0 {
+		c.SLO.Window = DefaultSLOWindow
+	}
+	if len(c.SLO.Objectives) == 0 {
+		c.SLO.Objectives = []SLOObjective{{
+			Name:      "get_pre_params_pool_hit",
+			Method:    "GetPreParams",
+			Class:     "pool_hit",
+			Threshold: DefaultSLOThreshold,
+			Target:    DefaultSLOTarget,
+		}}
+	}
+	if c.Peer.SyncInterval == 0 {
+		c.Peer.SyncInterval = DefaultPeerSync
+	}
+	if c.Peer.MaxTransfer == 0 {
+		c.Peer.MaxTransfer = DefaultPeerMaxTransfer
+	}
+	if c.Worker.TokenTTL == 0 {
+		c.Worker.TokenTTL = DefaultWorkerTokenTTL
+	}
+	if c.Bootstrap.Timeout == 0 {
+		c.Bootstrap.Timeout = DefaultSeedTimeout
+	}
+	if c.Bootstrap.ProxyInterval == 0 {
+		c.Bootstrap.ProxyInterval = DefaultProxyInterval
+	}
+	if c.Bootstrap.ProxyMaxPull == 0 {
+		c.Bootstrap.ProxyMaxPull = DefaultProxyMaxPull
+	}
+	if c.Hardening.MinPrimeBitSize == 0 {
+		c.Hardening.MinPrimeBitSize = DefaultMinPrimeBits
+	}
+	if c.Hardening.MinPaillierBitSize == 0 {
+		c.Hardening.MinPaillierBitSize = DefaultMinPaillierBits
+	}
+}
+
+// Validate checks the configuration for inconsistent values
+func (c *Config) Validate() error {
+	if c.Server.Address == "" {
+		return fmt.Errorf("server.address must not be empty")
+	}
+	if err := c.Server.validateListeners(); err != nil {
+		return err
+	}
+	if err := c.Server.TLS.validate(); err != nil {
+		return err
+	}
+	if c.Server.LoadReportInterval < 0 {
+		return fmt.Errorf("server.load_report_interval must not be negative")
+	}
+	if c.Server.DefaultDeadline < 0 || c.Server.MaxDeadline < 0 {
+		return fmt.Errorf("server.default_deadline and server.max_deadline must not be negative")
+	}
+	if c.Server.MaxResponseBytes < 64<<10 {
+		return fmt.Errorf("server.max_response_bytes must be at least 64 KiB, got %d", c.Server.MaxResponseBytes)
+	}
+	if _, err := proxyproto.ParseTrusted(c.Server.ProxyProtocolTrusted); err != nil {
+		return fmt.Errorf("server.proxy_protocol_trusted: %w", err)
+	}
+	if c.Server.MaxRequestedBitSize < 0 {
+		return fmt.Errorf("server.max_requested_bit_size must not be negative, got %d", c.Server.MaxRequestedBitSize)
+	}
+	if c.Server.MaxBatchSize < 1 {
+		return fmt.Errorf("server.max_batch_size must be at least 1, got %d", c.Server.MaxBatchSize)
+	}
+	if err := c.Pool.Validate(); err != nil {
+		return fmt.Errorf("invalid pool config: %w", err)
+	}
+	if err := c.validatePools(); err != nil {
+		return err
+	}
+	if err := apikey.Validate(c.Auth.Keys); err != nil {
+		return fmt.Errorf("invalid auth config: %w", err)
+	}
+	if err := c.SLO.validate(); err != nil {
+		return err
+	}
+	if len(c.Peer.Peers) > 0 && c.Peer.Token == "" {
+		return fmt.Errorf("peer.token is required when peer.peers is set")
+	}
+	if c.Peer.SyncInterval < 0 || c.Peer.MaxTransfer < 0 {
+		return fmt.Errorf("peer.sync_interval and peer.max_transfer must not be negative")
+	}
+	if c.Bootstrap.Count < 0 || c.Bootstrap.Timeout < 0 || c.Bootstrap.ProxyInterval < 0 || c.Bootstrap.ProxyMaxPull < 0 {
+		return fmt.Errorf("bootstrap.count, bootstrap.timeout, bootstrap.proxy_interval and bootstrap.proxy_max_pull must not be negative")
+	}
+	if c.Bootstrap.Proxy && c.Bootstrap.Upstream == "" {
+		return fmt.Errorf("bootstrap.upstream is required when bootstrap.proxy is set")
+	}
+	if c.Worker.TokenTTL < 0 {
+		return fmt.Errorf("worker.token_ttl must not be negative")
+	}
+	if c.Logging.KeepLines < 0 {
+		return fmt.Errorf("logging.keep_lines must not be negative")
+	}
+	if c.Hardening.MinPrimeBitSize < 0 || c.Hardening.MinPaillierBitSize < 0 {
+		return fmt.Errorf("hardening.min_prime_bit_size and hardening.min_paillier_bit_size must not be negative")
+	}
+	if c.Logging.AccessLog.MaxSizeMB < -1 || c.Logging.AccessLog.MaxBackups < -1 || c.Logging.AccessLog.RotateHours < 0 {
+		return fmt.Errorf("logging.access_log.max_size_mb and max_backups must be positive or -1, rotate_hours must not be negative")
+	}
+	if s := c.Logging.AccessLog.Syslog; s != "" && s != "local" && !strings.Contains(s, "://") {
+		return fmt.Errorf("logging.access_log.syslog must be local or network://host:port")
+	}
+	if c.Generator.SafePrimeWorkers < 0 || c.Generator.CPUBudget < 0 || c.Generator.Workers < 0 {
+		return fmt.Errorf("generator.safe_prime_workers, generator.cpu_budget and generator.workers must not be negative")
+	}
+	if _, err := affinity.Parse(c.Generator.CPUAffinity); err != nil {
+		return fmt.Errorf("generator.cpu_affinity: %w", err)
+	}
+	return nil
+}
+
+// validatePools checks that named pools have unique, valid names and that
+// no two file-backed pools share a directory
+func (c *Config) validatePools() error {
+	names := make(map[string]bool)
+	dirs := make(map[string]string)
+	if c.Pool.Storage != "memory" {
+		dirs[filepath.Clean(c.Pool.PoolDir)] = DefaultPoolName
+	}
+	for _, p := range c.Pools {
+		if !validPoolName(p.Name) {
+			return fmt.Errorf("pools: invalid name %q (letters, digits, \"-\" and \"_\", at most 64 characters)", p.Name)
+		}
+		if p.Name == DefaultPoolName || names[p.Name] {
+			return fmt.Errorf("pools: duplicate name %s", p.Name)
+		}
+		names[p.Name] = true
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("invalid config of pool %s: %w", p.Name, err)
+		}
+		if p.Storage == "memory" {
+			continue
+		}
+		dir := filepath.Clean(p.PoolDir)
+		if other, ok := dirs[dir]; ok {
+			return fmt.Errorf("pools: %s and %s share pool_dir %s", other, p.Name, p.PoolDir)
+		}
+		dirs[dir] = p.Name
+	}
+	return nil
+}
+
+// validate checks that objectives are named uniquely and achievable
+func (c *SLOConfig) validate() error {
+	if c.Window < time.Minute {
+		return fmt.Errorf("slo.window must be at least a minute")
+	}
+	names := make(map[string]bool)
+	for _, o := range c.Objectives {
+		if o.Name == "" || names[o.Name] {
+			return fmt.Errorf("slo.objectives: names must be set and unique, got %q", o.Name)
+		}
+		names[o.Name] = true
+		if o.Method == "" {
+			return fmt.Errorf("slo objective %s: method must be set", o.Name)
+		}
+		switch o.Class {
+		case "", "pool_hit", "generated", "pool_miss":
+		default:
+			return fmt.Errorf("slo objective %s: class must be pool_hit, generated, pool_miss or empty, got %q", o.Name, o.Class)
+		}
+		if o.Threshold <= 0 {
+			return fmt.Errorf("slo objective %s: threshold must be positive", o.Name)
+		}
+		if o.Target <= 0 || o.Target >= 1 {
+			return fmt.Errorf("slo objective %s: target must be between 0 and 1 (exclusive), got %g", o.Name, o.Target)
+		}
+	}
+	return nil
+}
+
+// validPoolName accepts short names usable as routing keys
+func validPoolName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validateListeners checks that listeners have unique addresses and at
+// least one is enabled
+func (s *ServerConfig) validateListeners() error {
+	if len(s.Listeners) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, l := range s.Listeners {
+		if l.Address == "" {
+			return fmt.Errorf("server.listeners: address must not be empty")
+		}
+		if seen[l.Address] {
+			return fmt.Errorf("server.listeners: duplicate address %s", l.Address)
+		}
+		seen[l.Address] = true
+	}
+	if len(s.ListenAddresses()) == 0 {
+		return fmt.Errorf("server.listeners: at least one listener must be enabled")
+	}
+	return nil
+}
+
+// ApplyDefaults fills in zero-valued pool fields with their defaults
+func (p *PoolConfig) ApplyDefaults() {
+	if p.MinPoolSize == 0 {
+		p.MinPoolSize = DefaultMinPoolSize
+	}
+	if p.MaxPoolSize == 0 {
+		p.MaxPoolSize = DefaultMaxPoolSize
+	}
+	if p.RefillThreshold == 0 {
+		p.RefillThreshold = DefaultRefillThreshold
+	}
+	if p.PrimeBitSize == 0 {
+		p.PrimeBitSize = DefaultPrimeBitSize
+	}
+	if p.PaillierBitSize == 0 {
+		p.PaillierBitSize = DefaultPaillierBitSize
+	}
+	if p.MaxConcurrent == 0 {
+		p.MaxConcurrent = DefaultMaxConcurrent
+	}
+	if p.MaxOnDemand == 0 {
+		p.MaxOnDemand = DefaultMaxOnDemand
+	}
+	if p.PoolDir == "" {
+		p.PoolDir = DefaultPoolDir
+	}
+	if p.RefillInterval == 0 {
+		p.RefillInterval = DefaultRefillInterval
+	}
+	if p.StartupDelay == 0 {
+		p.StartupDelay = DefaultStartupDelay
+	}
+	if p.GenerationThrottle == 0 {
+		p.GenerationThrottle = DefaultThrottle
+	}
+	if p.SelectionPolicy == "" {
+		p.SelectionPolicy = DefaultSelectionPolicy
+	}
+	if p.Storage == "" {
+		p.Storage = DefaultStorage
+	}
+	if p.RedisKey == "" {
+		p.RedisKey = DefaultRedisKey
+	}
+	if p.StalePolicy == "" {
+		p.StalePolicy = DefaultStalePolicy
+	}
+	if p.DeniedVersionPolicy == "" {
+		p.DeniedVersionPolicy = DefaultDeniedPolicy
+	}
+	if p.SaveBatchItems == 0 {
+		p.SaveBatchItems = DefaultSaveBatchItems
+	}
+	if p.Fsync == "" {
+		p.Fsync = DefaultFsync
+	}
+	if p.FsyncInterval == 0 {
+		p.FsyncInterval = DefaultFsyncInterval
+	}
+	if p.IdempotencyTTL == 0 {
+		p.IdempotencyTTL = DefaultIdempotencyTTL
+	}
+	if p.ErrorJournalSize == 0 {
+		p.ErrorJournalSize = DefaultErrorJournal
+	}
+	if p.RefillHistory == 0 {
+		p.RefillHistory = DefaultRefillHistory
+	}
+	if p.AlarmServedWindow == 0 {
+		p.AlarmServedWindow = DefaultAlarmWindow
+	}
+	if p.FreezeFailureLimit == 0 {
+		p.FreezeFailureLimit = DefaultFreezeFailures
+	}
+	if p.FreezeFailureWindow == 0 {
+		p.FreezeFailureWindow = DefaultFreezeWindow
+	}
+	if p.SnapshotInterval == 0 {
+		p.SnapshotInterval = DefaultSnapshotEvery
+	}
+	if p.SnapshotKeep == 0 {
+		p.SnapshotKeep = DefaultSnapshotKeep
+	}
+}
+
+// Validate checks the pool configuration for inconsistent values
+func (p *PoolConfig) Validate() error {
+	if p.MinPoolSize < 0 || p.MaxPoolSize < 0 || p.RefillThreshold < 0 || p.ErrorJournalSize < 0 || p.RefillHistory < 0 || p.AlarmServedLimit < 0 || p.FreezeFailureLimit < 0 || p.SaveBatchItems < 0 || p.MinAudited < 0 {
+		return fmt.Errorf("pool and error journal sizes, save batches, alarm limits and min_audited must not be negative")
+	}
+	if p.MinPoolSize > p.MaxPoolSize {
+		return fmt.Errorf("min_pool_size (%d) must not exceed max_pool_size (%d)", p.MinPoolSize, p.MaxPoolSize)
+	}
+	if p.RefillThreshold > p.MinPoolSize {
+		return fmt.Errorf("refill_threshold (%d) must not exceed min_pool_size (%d)", p.RefillThreshold, p.MinPoolSize)
+	}
+	if p.MinAudited > p.MaxPoolSize {
+		return fmt.Errorf("min_audited (%d) must not exceed max_pool_size (%d)", p.MinAudited, p.MaxPoolSize)
+	}
+	if p.PrimeBitSize < 3 {
+		return fmt.Errorf("prime_bit_size must be at least 3 bits, got %d", p.PrimeBitSize)
+	}
+	if p.PaillierBitSize < 2 {
+		return fmt.Errorf("paillier_bit_size must be at least 2 bits, got %d", p.PaillierBitSize)
+	}
+	switch p.SelectionPolicy {
+	case "oldest", "newest", "random":
+	default:
+		return fmt.Errorf("selection_policy must be oldest, newest or random, got %q", p.SelectionPolicy)
+	}
+	switch p.Storage {
+	case "file", "memory":
+	case "redis":
+		if p.RedisAddress == "" {
+			return fmt.Errorf("redis storage needs redis_address")
+		}
+		if p.RedisDB < 0 {
+			return fmt.Errorf("redis_db must not be negative, got %d", p.RedisDB)
+		}
+	default:
+		return fmt.Errorf("storage must be file, memory or redis, got %q", p.Storage)
+	}
+	switch p.StalePolicy {
+	case "regenerate", "serve":
+	default:
+		return fmt.Errorf("stale_policy must be regenerate or serve, got %q", p.StalePolicy)
+	}
+	switch p.DeniedVersionPolicy {
+	case "refuse", "warn":
+	default:
+		return fmt.Errorf("denied_version_policy must be refuse or warn, got %q", p.DeniedVersionPolicy)
+	}
+	switch p.Fsync {
+	case "always", "interval", "never":
+	default:
+		return fmt.Errorf("fsync must be always, interval or never, got %q", p.Fsync)
+	}
+	if p.MaxConcurrent < 0 || p.EmergencyConcurrent < 0 || p.MaxOnDemand < 0 {
+		return fmt.Errorf("max_concurrent, emergency_concurrent and max_on_demand must not be negative")
+	}
+	if sources := countSet(p.EncryptionKey, p.EncryptionKeyFile, p.EncryptionKeyCommand); sources > 1 {
+		return fmt.Errorf("set at most one of encryption_key, encryption_key_file and encryption_key_command")
+	}
+	if p.EncryptionKey != "" {
+		if _, err := atrest.ParseKey(p.EncryptionKey); err != nil {
+			return err
+		}
+	}
+	if p.SnapshotDir != "" {
+		if p.Storage != "file" {
+			return fmt.Errorf("snapshot_dir needs file storage, got %q", p.Storage)
+		}
+		if countSet(p.EncryptionKey, p.EncryptionKeyFile, p.EncryptionKeyCommand) == 0 {
+			return fmt.Errorf("snapshot_dir needs an encryption key, so snapshots are never stored in plaintext")
+		}
+	}
+	if p.Storage == "redis" && countSet(p.EncryptionKey, p.EncryptionKeyFile, p.EncryptionKeyCommand) == 0 && (!p.RedisTLS || p.RedisPassword == "") {
+		// Items in the list hold the Paillier secret keys
+		return fmt.Errorf("redis storage needs an encryption key, or redis_tls with redis_password, so secrets never reach redis in plaintext")
+	}
+	if p.Storage == "redis" && p.hasQuotas() {
+		// Usage is counted per replica, so replicas sharing the pool would
+		// each grant the full quota
+		return fmt.Errorf("tenant quotas need file or memory storage; with redis storage every replica would enforce its own")
+	}
+	if p.SnapshotKeep < 0 {
+		return fmt.Errorf("snapshot_keep must not be negative, got %d", p.SnapshotKeep)
+	}
+	tenants := make(map[string]bool, len(p.Tenants))
+	for _, t := range p.Tenants {
+		if !validPoolName(t.Name) {
+			return fmt.Errorf("tenants: invalid name %q (letters, digits, \"-\" and \"_\", at most 64 characters)", t.Name)
+		}
+		if tenants[t.Name] {
+			return fmt.Errorf("tenants: duplicate name %s", t.Name)
+		}
+		tenants[t.Name] = true
+		if t.HourlyQuota < 0 || t.DailyQuota < 0 {
+			return fmt.Errorf("tenants: quotas of %s must not be negative", t.Name)
+		}
+	}
+	// Negative startup_delay and generation_throttle disable them
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"refill_interval", p.RefillInterval},
+		{"emergency_throttle", p.EmergencyThrottle},
+		{"idempotency_ttl", p.IdempotencyTTL},
+		{"anti_correlation_window", p.AntiCorrelationWindow},
+		{"max_age", p.MaxAge},
+		{"import_revalidate_age", p.ImportRevalidateAge},
+		{"backup_retention", p.BackupRetention},
+		{"snapshot_interval", p.SnapshotInterval},
+		{"save_batch_delay", p.SaveBatchDelay},
+		{"fsync_interval", p.FsyncInterval},
+		{"alarm_served_window", p.AlarmServedWindow},
+		{"alarm_empty_within", p.AlarmEmptyWithin},
+		{"freeze_failure_window", p.FreezeFailureWindow},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			return fmt.Errorf("%s must not be negative, got %s", d.name, d.value)
+		}
+	}
+	return nil
+}
+
+// countSet returns how many of values are non-empty
+func countSet(values ...string) int {
+	n := 0
+	for _, v := range values {
+		if v != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// poolConfigJSON is the on-disk shape of PoolConfig (durations in seconds)
+type poolConfigJSON PoolConfig
+
+// MarshalJSON encodes durations as seconds
+func (p PoolConfig) MarshalJSON() ([]byte, error) {
+	plain := poolConfigJSON(p)
+	return marshalSeconds(&plain)
+}
+
+// UnmarshalJSON decodes durations given as seconds or duration strings
+func (p *PoolConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalSeconds(data, (*poolConfigJSON)(p))
+}
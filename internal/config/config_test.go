@@ -0,0 +1,148 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig returns the defaults, which must pass validation
+func validConfig(t *testing.T) *Config {
+	t.Helper()
+	c := Default()
+	c.ApplyDefaults()
+	if err := c.Validate(); err != nil {
+		t.Fatalf("default config is invalid: %v", err)
+	}
+	return c
+}
+
+func TestValidate(t *testing.T) {
+	const key = "0000000000000000000000000000000000000000000000000000000000000000"
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		errMsg string // empty: valid
+	}{
+		{"defaults", func(c *Config) {}, ""},
+		{"empty address", func(c *Config) { c.Server.Address = "" }, "server.address"},
+		{"min above max", func(c *Config) { c.Pool.MinPoolSize, c.Pool.MaxPoolSize = 10, 5 }, "must not exceed max_pool_size"},
+		{"threshold above min", func(c *Config) { c.Pool.RefillThreshold = c.Pool.MinPoolSize + 1 }, "refill_threshold"},
+		{"unknown selection policy", func(c *Config) { c.Pool.SelectionPolicy = "fifo" }, "selection_policy"},
+		{"unknown storage", func(c *Config) { c.Pool.Storage = "s3" }, "storage must be"},
+		{"redis without address", func(c *Config) { c.Pool.Storage = "redis" }, "redis_address"},
+		{"redis without key", func(c *Config) {
+			c.Pool.Storage, c.Pool.RedisAddress = "redis", "localhost:6379"
+		}, "redis storage needs an encryption key"},
+		{"redis with tls without password", func(c *Config) {
+			c.Pool.Storage, c.Pool.RedisAddress, c.Pool.RedisTLS = "redis", "localhost:6379", true
+		}, "redis storage needs an encryption key"},
+		{"redis with tls and password", func(c *Config) {
+			c.Pool.Storage, c.Pool.RedisAddress, c.Pool.RedisTLS, c.Pool.RedisPassword = "redis", "localhost:6379", true, "secret"
+		}, ""},
+		{"redis with key", func(c *Config) {
+			c.Pool.Storage, c.Pool.RedisAddress, c.Pool.EncryptionKey = "redis", "localhost:6379", key
+		}, ""},
+		{"negative refill interval", func(c *Config) { c.Pool.RefillInterval = -time.Second }, "refill_interval must not be negative"},
+		{"negative max age", func(c *Config) { c.Pool.MaxAge = -time.Minute }, "max_age must not be negative"},
+		{"negative startup delay disables", func(c *Config) { c.Pool.StartupDelay = -1 }, ""},
+		{"negative generation throttle disables", func(c *Config) { c.Pool.GenerationThrottle = -1 }, ""},
+		{"two key sources", func(c *Config) {
+			c.Pool.EncryptionKey, c.Pool.EncryptionKeyFile = key, "/etc/prime/key"
+		}, "at most one"},
+		{"snapshots without key", func(c *Config) { c.Pool.SnapshotDir = "/tmp/snapshots" }, "needs an encryption key"},
+		{"snapshots with memory storage", func(c *Config) {
+			c.Pool.SnapshotDir, c.Pool.EncryptionKey, c.Pool.Storage = "/tmp/snapshots", key, "memory"
+		}, "needs file storage"},
+		{"snapshots with key", func(c *Config) {
+			c.Pool.SnapshotDir, c.Pool.EncryptionKey = "/tmp/snapshots", key
+		}, ""},
+		{"tenant quotas", func(c *Config) {
+			c.Pool.Tenants = []TenantConfig{{Name: "team-a", HourlyQuota: 10}}
+		}, ""},
+		{"duplicate tenant", func(c *Config) {
+			c.Pool.Tenants = []TenantConfig{{Name: "team-a"}, {Name: "team-a"}}
+		}, "duplicate name"},
+		{"invalid tenant name", func(c *Config) {
+			c.Pool.Tenants = []TenantConfig{{Name: "team a"}}
+		}, "invalid name"},
+		{"negative quota", func(c *Config) {
+			c.Pool.Tenants = []TenantConfig{{Name: "team-a", DailyQuota: -1}}
+		}, "must not be negative"},
+		{"quotas with redis", func(c *Config) {
+			c.Pool.Storage, c.Pool.RedisAddress, c.Pool.EncryptionKey = "redis", "localhost:6379", key
+			c.Pool.Tenants = []TenantConfig{{Name: "team-a", DailyQuota: 100}}
+		}, "tenant quotas need file or memory storage"},
+		{"tenants without quotas with redis", func(c *Config) {
+			c.Pool.Storage, c.Pool.RedisAddress, c.Pool.EncryptionKey = "redis", "localhost:6379", key
+			c.Pool.Tenants = []TenantConfig{{Name: "team-a"}}
+		}, ""},
+		{"peers without token", func(c *Config) { c.Peer.Peers = []string{"10.0.0.2:50051"} }, "peer.token"},
+		{"proxy without upstream", func(c *Config) { c.Bootstrap.Proxy = true }, "bootstrap.upstream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig(t)
+			tt.modify(c)
+			err := c.Validate()
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestHardeningIssues(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(c *Config)
+		authEnabled bool
+		want        string // empty: no issues
+	}{
+		{"loopback without auth", func(c *Config) { c.Server.Address = "127.0.0.1:50051" }, false, ""},
+		{"public without tls or auth", func(c *Config) { c.Server.Address = "0.0.0.0:50051" }, false, "neither TLS nor API keys"},
+		{"weak prime bit size", func(c *Config) {
+			c.Server.Address = "127.0.0.1:50051"
+			c.Pool.PrimeBitSize = c.Hardening.MinPrimeBitSize - 1
+		}, false, "prime_bit_size"},
+		{"quotas without auth", func(c *Config) {
+			c.Server.Address = "127.0.0.1:50051"
+			c.Pool.Tenants = []TenantConfig{{Name: "team-a", HourlyQuota: 1}}
+		}, false, "tenant quotas"},
+		{"quotas with auth", func(c *Config) {
+			c.Server.Address = "127.0.0.1:50051"
+			c.Pool.Tenants = []TenantConfig{{Name: "team-a", HourlyQuota: 1}}
+		}, true, ""},
+		{"public admin http", func(c *Config) {
+			c.Server.Address = "127.0.0.1:50051"
+			c.Server.AdminHTTPAddress = ":9090"
+		}, true, "admin HTTP listener"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig(t)
+			tt.modify(c)
+			issues := c.HardeningIssues(tt.authEnabled)
+			if tt.want == "" {
+				if len(issues) != 0 {
+					t.Fatalf("HardeningIssues() = %q, want none", issues)
+				}
+				return
+			}
+			for _, issue := range issues {
+				if strings.Contains(issue, tt.want) {
+					return
+				}
+			}
+			t.Fatalf("HardeningIssues() = %q, want an issue containing %q", issues, tt.want)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// durationFields maps the JSON names of a struct's time.Duration fields to
+// their field indexes
+func durationFields(t reflect.Type) map[string]int {
+	fields := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type != durationType {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = i
+	}
+	return fields
+}
+
+// marshalSeconds encodes the struct pointed to by v with every
+// time.Duration field written as (fractional) seconds
+func marshalSeconds(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	for name, i := range durationFields(rv.Type()) {
+		d := time.Duration(rv.Field(i).Int())
+		raw[name], _ = json.Marshal(d.Seconds())
+	}
+	return json.Marshal(raw)
+}
+
+// unmarshalSeconds decodes data into the struct pointed to by v, accepting
+// time.Duration fields as seconds (30) or Go duration strings ("30s")
+func unmarshalSeconds(data []byte, v interface{}) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	durations := make(map[int]time.Duration)
+	for name, i := range durationFields(rv.Type()) {
+		value, ok := raw[name]
+		if !ok {
+			continue
+		}
+		delete(raw, name)
+
+		var secs float64
+		var str string
+		switch {
+		case json.Unmarshal(value, &secs) == nil:
+			durations[i] = seconds(secs)
+		case json.Unmarshal(value, &str) == nil:
+			d, err := time.ParseDuration(str)
+			if err != nil {
+				return fmt.Errorf("%s: invalid duration %q", name, str)
+			}
+			durations[i] = d
+		default:
+			return fmt.Errorf("%s: expected seconds or duration string", name)
+		}
+	}
+
+	rest, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(rest, v); err != nil {
+		return err
+	}
+	for i, d := range durations {
+		rv.Field(i).SetInt(int64(d))
+	}
+	return nil
+}
+
+func seconds(v float64) time.Duration {
+	return time.Duration(v * float64(time.Second))
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"net"
+)
+
+// HardeningConfig parameterizes the startup self-check that refuses
+// configurations unfit for a key-material service: pools with bit sizes
+// below the floors, listeners reachable beyond the loopback interface with
+// neither TLS nor API keys, AdminService reachable beyond it without
+// authentication, an admin HTTP server reachable beyond it at all, or
+// tenant quotas without API keys.
+// AllowInsecure starts the service anyway, logging every failed check.
+type HardeningConfig struct {
+	MinPrimeBitSize    int  `json:"min_prime_bit_size"`    // Floor for every pool's prime_bit_size (default: 1024)
+	MinPaillierBitSize int  `json:"min_paillier_bit_size"` // Floor for every pool's paillier_bit_size (default: 2048)
+	AllowInsecure      bool `json:"allow_insecure"`        // Start despite failed checks, e.g. for local experiments
+}
+
+// HardeningIssues lists the hardening checks the configuration fails.
+// authEnabled reports whether API keys are configured, counting those read
+// from auth.keys_file.
+func (c *Config) HardeningIssues(authEnabled bool) []string {
+	var issues []string
+	pools := []NamedPoolConfig{{Name: DefaultPoolName, PoolConfig: c.Pool}}
+	pools = append(pools, c.Pools...)
+	for _, p := range pools {
+		if p.PrimeBitSize < c.Hardening.MinPrimeBitSize {
+			issues = append(issues, fmt.Sprintf("pool %s: prime_bit_size %d is below hardening.min_prime_bit_size %d",
+				p.Name, p.PrimeBitSize, c.Hardening.MinPrimeBitSize))
+		}
+		if p.PaillierBitSize < c.Hardening.MinPaillierBitSize {
+			issues = append(issues, fmt.Sprintf("pool %s: paillier_bit_size %d is below hardening.min_paillier_bit_size %d",
+				p.Name, p.PaillierBitSize, c.Hardening.MinPaillierBitSize))
+		}
+	}
+
+	// Without keys bound to namespaces, callers pick whose quota they use
+	if c.TenantQuotas() && !authEnabled {
+		issues = append(issues, "tenant quotas are configured without API keys binding callers to their namespaces")
+	}
+
+	// The admin HTTP server has neither TLS nor authentication
+	if addr := c.Server.AdminHTTPAddress; addr != "" && !isLoopback(addr) {
+		issues = append(issues, fmt.Sprintf("admin HTTP listener %s, serving /diagnostics, /items and /errors without TLS or authentication, is reachable beyond the loopback interface", addr))
+	}
+
+	tls := c.Server.TLS.Enabled()
+	for _, addr := range c.Server.ListenAddresses() {
+		switch {
+		case isLoopback(addr):
+		case !tls && !authEnabled:
+			issues = append(issues, fmt.Sprintf("listener %s is reachable beyond the loopback interface with neither TLS nor API keys", addr))
+		case !authEnabled && !c.Server.TLS.RequireClientCert:
+			// TLS alone encrypts, but admits anyone to AdminService
+			issues = append(issues, fmt.Sprintf("listener %s serves AdminService beyond the loopback interface without authentication; configure an admin API key or require client certificates", addr))
+		}
+	}
+	if addr := c.Server.WebAddress; addr != "" && !tls && !authEnabled && !isLoopback(addr) {
+		issues = append(issues, fmt.Sprintf("listener %s is reachable beyond the loopback interface with neither TLS nor API keys", addr))
+	}
+	return issues
+}
+
+// isLoopback reports whether a listen address only accepts connections
+// from this host. Wildcard and empty hosts listen on every interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,344 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setting describes a config field that can be overridden from the
+// environment or the command line
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	set   func(c *Config, v string) error
+}
+
+var settings = []setting{
+	{"address", "PRIME_SERVER_ADDRESS", "gRPC listen address", func(c *Config, v string) error {
+		c.Server.Address = v
+		return nil
+	}},
+	{"listen", "PRIME_SERVER_LISTEN", "comma-separated gRPC listen addresses (replaces address)", func(c *Config, v string) error {
+		c.Server.Listeners = nil
+		for _, addr := range splitList(v) {
+			c.Server.Listeners = append(c.Server.Listeners, ListenerConfig{Address: addr})
+		}
+		return nil
+	}},
+	{"proxy-protocol-trusted", "PRIME_SERVER_PROXY_PROTOCOL_TRUSTED", "comma-separated proxy networks or addresses sending PROXY protocol headers (empty disables)", func(c *Config, v string) error {
+		c.Server.ProxyProtocolTrusted = splitList(v)
+		return nil
+	}},
+	{"tls-cert-file", "PRIME_SERVER_TLS_CERT_FILE", "PEM certificate chain for the gRPC and web listeners (empty: plaintext)", func(c *Config, v string) error {
+		c.Server.TLS.CertFile = v
+		return nil
+	}},
+	{"tls-key-file", "PRIME_SERVER_TLS_KEY_FILE", "PEM private key of the TLS certificate", func(c *Config, v string) error {
+		c.Server.TLS.KeyFile = v
+		return nil
+	}},
+	{"tls-client-ca-file", "PRIME_SERVER_TLS_CLIENT_CA_FILE", "PEM bundle of CAs client certificates are verified against", func(c *Config, v string) error {
+		c.Server.TLS.ClientCAFile = v
+		return nil
+	}},
+	{"tls-require-client-cert", "PRIME_SERVER_TLS_REQUIRE_CLIENT_CERT", "admit only clients with a certificate from the client CAs (mutual TLS)", boolSetter(func(c *Config) *bool { return &c.Server.TLS.RequireClientCert })},
+	{"admin-http-address", "PRIME_SERVER_ADMIN_HTTP_ADDRESS", "admin HTTP listen address (empty disables)", func(c *Config, v string) error {
+		c.Server.AdminHTTPAddress = v
+		return nil
+	}},
+	{"web-address", "PRIME_SERVER_WEB_ADDRESS", "Connect/gRPC-Web listen address for browser clients and scripts (empty disables)", func(c *Config, v string) error {
+		c.Server.WebAddress = v
+		return nil
+	}},
+	{"web-allowed-origins", "PRIME_SERVER_WEB_ALLOWED_ORIGINS", "comma-separated browser origins admitted to the web server (* for any)", func(c *Config, v string) error {
+		c.Server.WebAllowedOrigins = splitList(v)
+		return nil
+	}},
+	{"load-report-interval", "PRIME_SERVER_LOAD_REPORT_INTERVAL", "ORCA load report interval in seconds (0 disables)", intSetter(func(c *Config) *int { return &c.Server.LoadReportInterval })},
+	{"default-deadline", "PRIME_SERVER_DEFAULT_DEADLINE", "deadline in seconds for PrimeService calls without one (0 disables)", intSetter(func(c *Config) *int { return &c.Server.DefaultDeadline })},
+	{"max-deadline", "PRIME_SERVER_MAX_DEADLINE", "longest PrimeService deadline in seconds; longer ones are shortened (0 disables)", intSetter(func(c *Config) *int { return &c.Server.MaxDeadline })},
+	{"max-response-bytes", "PRIME_SERVER_MAX_RESPONSE_BYTES", "largest GetPreParams response; bigger batches are streamed", intSetter(func(c *Config) *int { return &c.Server.MaxResponseBytes })},
+	{"max-requested-bit-size", "PRIME_SERVER_MAX_REQUESTED_BIT_SIZE", "largest bit size generated for GetPreParams requests no pool serves", intSetter(func(c *Config) *int { return &c.Server.MaxRequestedBitSize })},
+	{"max-batch-size", "PRIME_SERVER_MAX_BATCH_SIZE", "most items one GetPreParams call may request", intSetter(func(c *Config) *int { return &c.Server.MaxBatchSize })},
+	{"record-traffic", "PRIME_SERVER_RECORD_TRAFFIC", "file to record anonymized GetPreParams traffic to for primectl replay (empty disables)", func(c *Config, v string) error {
+		c.Server.RecordTraffic = v
+		return nil
+	}},
+	{"region", "PRIME_SERVER_REGION", "region reported to clients preferring nearby replicas (e.g. eu-west-1)", func(c *Config, v string) error {
+		c.Server.Region = v
+		return nil
+	}},
+	{"zone", "PRIME_SERVER_ZONE", "zone reported alongside region (e.g. eu-west-1a)", func(c *Config, v string) error {
+		c.Server.Zone = v
+		return nil
+	}},
+	{"min-pool-size", "PRIME_POOL_MIN_SIZE", "minimum items to maintain in pool", intSetter(func(c *Config) *int { return &c.Pool.MinPoolSize })},
+	{"max-pool-size", "PRIME_POOL_MAX_SIZE", "maximum items in pool", intSetter(func(c *Config) *int { return &c.Pool.MaxPoolSize })},
+	{"refill-threshold", "PRIME_POOL_REFILL_THRESHOLD", "pool size that triggers a refill", intSetter(func(c *Config) *int { return &c.Pool.RefillThreshold })},
+	{"prime-bit-size", "PRIME_POOL_PRIME_BIT_SIZE", "bit size of NTildei safe primes", intSetter(func(c *Config) *int { return &c.Pool.PrimeBitSize })},
+	{"paillier-bit-size", "PRIME_POOL_PAILLIER_BIT_SIZE", "bit size of the Paillier modulus", intSetter(func(c *Config) *int { return &c.Pool.PaillierBitSize })},
+	{"max-concurrent", "PRIME_POOL_MAX_CONCURRENT", "workers of housekeeping refills", intSetter(func(c *Config) *int { return &c.Pool.MaxConcurrent })},
+	{"emergency-concurrent", "PRIME_POOL_EMERGENCY_CONCURRENT", "workers of emergency refills after a request miss (0: one per CPU)", intSetter(func(c *Config) *int { return &c.Pool.EmergencyConcurrent })},
+	{"on-demand-gen", "PRIME_POOL_ON_DEMAND_GEN", "generate synchronously when the pool cannot satisfy a request", boolSetter(func(c *Config) *bool { return &c.Pool.OnDemandGen })},
+	{"max-on-demand", "PRIME_POOL_MAX_ON_DEMAND", "concurrent on-demand generations; further misses queue", intSetter(func(c *Config) *int { return &c.Pool.MaxOnDemand })},
+	{"selection-policy", "PRIME_POOL_SELECTION_POLICY", "which items to serve: oldest, newest or random", func(c *Config, v string) error {
+		c.Pool.SelectionPolicy = v
+		return nil
+	}},
+	{"storage", "PRIME_POOL_STORAGE", "storage backend: file, memory (nothing written to disk) or redis (pool shared by replicas)", func(c *Config, v string) error {
+		c.Pool.Storage = v
+		return nil
+	}},
+	{"redis-address", "PRIME_POOL_REDIS_ADDRESS", "host:port of the Redis server holding the shared pool", func(c *Config, v string) error {
+		c.Pool.RedisAddress = v
+		return nil
+	}},
+	{"redis-password", "PRIME_POOL_REDIS_PASSWORD", "password of the Redis server", func(c *Config, v string) error {
+		c.Pool.RedisPassword = v
+		return nil
+	}},
+	{"redis-db", "PRIME_POOL_REDIS_DB", "Redis database of the shared pool", intSetter(func(c *Config) *int { return &c.Pool.RedisDB })},
+	{"redis-key", "PRIME_POOL_REDIS_KEY", "prefix of the shared pool's Redis keys", func(c *Config, v string) error {
+		c.Pool.RedisKey = v
+		return nil
+	}},
+	{"redis-tls", "PRIME_POOL_REDIS_TLS", "connect to Redis over TLS", boolSetter(func(c *Config) *bool { return &c.Pool.RedisTLS })},
+	{"max-age", "PRIME_POOL_MAX_AGE", "maximum age of served items (e.g. 720h, 0 disables)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.MaxAge })},
+	{"stale-policy", "PRIME_POOL_STALE_POLICY", "when only items older than max-age are left: regenerate or serve", func(c *Config, v string) error {
+		c.Pool.StalePolicy = v
+		return nil
+	}},
+	{"denied-versions", "PRIME_POOL_DENIED_VERSIONS", "comma-separated generator versions whose items are not served as usual", func(c *Config, v string) error {
+		c.Pool.DeniedVersions = splitList(v)
+		return nil
+	}},
+	{"denied-version-policy", "PRIME_POOL_DENIED_VERSION_POLICY", "items of denied versions: refuse (discard) or warn (serve with a warning)", func(c *Config, v string) error {
+		c.Pool.DeniedVersionPolicy = v
+		return nil
+	}},
+	{"anti-correlation-window", "PRIME_POOL_ANTI_CORRELATION_WINDOW", "generation gap that makes same-burst items distinct (e.g. 10m)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.AntiCorrelationWindow })},
+	{"import-revalidate-age", "PRIME_POOL_IMPORT_REVALIDATE_AGE", "fully revalidate and flag imported items older than this (e.g. 168h, 0 disables)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.ImportRevalidateAge })},
+	{"import-demote", "PRIME_POOL_IMPORT_DEMOTE", "serve revalidated imported items only to requests allowing them", boolSetter(func(c *Config) *bool { return &c.Pool.ImportDemote })},
+	{"verify-primality-on-load", "PRIME_POOL_VERIFY_PRIMALITY_ON_LOAD", "fully validate standard items when loading the pool", boolSetter(func(c *Config) *bool { return &c.Pool.VerifyPrimalityOnLoad })},
+	{"min-audited", "PRIME_POOL_MIN_AUDITED", "pool items kept audited by background full validation (0 disables)", intSetter(func(c *Config) *int { return &c.Pool.MinAudited })},
+	{"secure-delete", "PRIME_POOL_SECURE_DELETE", "overwrite superseded pool and journal contents", boolSetter(func(c *Config) *bool { return &c.Pool.SecureDelete })},
+	{"encryption-key", "PRIME_POOL_ENCRYPTION_KEY", "key encrypting pool files and journals at rest (32 bytes, hex or base64)", func(c *Config, v string) error {
+		c.Pool.EncryptionKey = v
+		return nil
+	}},
+	{"encryption-key-file", "PRIME_POOL_ENCRYPTION_KEY_FILE", "file holding the at-rest encryption key", func(c *Config, v string) error {
+		c.Pool.EncryptionKeyFile = v
+		return nil
+	}},
+	{"encryption-key-command", "PRIME_POOL_ENCRYPTION_KEY_COMMAND", "shell command printing the at-rest encryption key (e.g. a KMS decrypt)", func(c *Config, v string) error {
+		c.Pool.EncryptionKeyCommand = v
+		return nil
+	}},
+	{"allow-plaintext-migration", "PRIME_POOL_ALLOW_PLAINTEXT_MIGRATION", "accept plaintext pool files and journals once, to encrypt them under the configured key", boolSetter(func(c *Config) *bool { return &c.Pool.AllowPlaintextMigration })},
+	{"backup-retention", "PRIME_POOL_BACKUP_RETENTION", "shred archived pool files older than this (e.g. 720h, 0 keeps them)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.BackupRetention })},
+	{"snapshot-dir", "PRIME_POOL_SNAPSHOT_DIR", "secondary directory for scheduled encrypted pool snapshots (empty disables)", func(c *Config, v string) error {
+		c.Pool.SnapshotDir = v
+		return nil
+	}},
+	{"snapshot-interval", "PRIME_POOL_SNAPSHOT_INTERVAL", "time between pool snapshots (e.g. 24h)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.SnapshotInterval })},
+	{"snapshot-keep", "PRIME_POOL_SNAPSHOT_KEEP", "pool snapshots kept in the snapshot directory", intSetter(func(c *Config) *int { return &c.Pool.SnapshotKeep })},
+	{"snapshot-command", "PRIME_POOL_SNAPSHOT_COMMAND", "shell command run after each snapshot with its path in PRIME_SNAPSHOT_FILE, e.g. an object store upload", func(c *Config, v string) error {
+		c.Pool.SnapshotCommand = v
+		return nil
+	}},
+	{"instance-id", "PRIME_POOL_INSTANCE_ID", "stable instance identity (generated and persisted if empty)", func(c *Config, v string) error {
+		c.Pool.InstanceID = v
+		return nil
+	}},
+	{"pool-dir", "PRIME_POOL_DIR", "directory to store pool data", func(c *Config, v string) error {
+		c.Pool.PoolDir = v
+		return nil
+	}},
+	{"auto-save", "PRIME_POOL_AUTO_SAVE", "auto save pool to disk", boolSetter(func(c *Config) *bool { return &c.Pool.AutoSave })},
+	{"save-batch-items", "PRIME_POOL_SAVE_BATCH_ITEMS", "new items saved together in one pool save", intSetter(func(c *Config) *int { return &c.Pool.SaveBatchItems })},
+	{"save-batch-delay", "PRIME_POOL_SAVE_BATCH_DELAY", "longest a new item waits for its pool save (e.g. 500ms, 0: until the refill ends)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.SaveBatchDelay })},
+	{"fsync", "PRIME_POOL_FSYNC", "when pool saves reach stable storage: always, interval or never", func(c *Config, v string) error {
+		c.Pool.Fsync = v
+		return nil
+	}},
+	{"fsync-interval", "PRIME_POOL_FSYNC_INTERVAL", "longest a pool save stays unsynced with fsync interval (e.g. 1s)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.FsyncInterval })},
+	{"background-gen", "PRIME_POOL_BACKGROUND_GEN", "enable background generation", boolSetter(func(c *Config) *bool { return &c.Pool.BackgroundGen })},
+	{"refill-interval", "PRIME_POOL_REFILL_INTERVAL", "how often to check and refill (e.g. 30s)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.RefillInterval })},
+	{"idempotency-ttl", "PRIME_POOL_IDEMPOTENCY_TTL", "how long idempotency-key allocations are replayed (e.g. 24h)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.IdempotencyTTL })},
+	{"error-journal-size", "PRIME_POOL_ERROR_JOURNAL_SIZE", "number of recent errors kept for GetErrors", intSetter(func(c *Config) *int { return &c.Pool.ErrorJournalSize })},
+	{"refill-history", "PRIME_POOL_REFILL_HISTORY", "number of recent refill cycles kept for ListRefillCycles", intSetter(func(c *Config) *int { return &c.Pool.RefillHistory })},
+	{"startup-delay", "PRIME_POOL_STARTUP_DELAY", "warmup before generation starts (e.g. 10s, negative disables)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.StartupDelay })},
+	{"generation-throttle", "PRIME_POOL_GENERATION_THROTTLE", "pause between items per housekeeping worker (e.g. 1s, negative disables)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.GenerationThrottle })},
+	{"emergency-throttle", "PRIME_POOL_EMERGENCY_THROTTLE", "pause between items per emergency worker (default 0)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.EmergencyThrottle })},
+	{"alarm-served-limit", "PRIME_POOL_ALARM_SERVED_LIMIT", "alarm when more items are served within alarm-served-window (0 disables)", intSetter(func(c *Config) *int { return &c.Pool.AlarmServedLimit })},
+	{"alarm-served-window", "PRIME_POOL_ALARM_SERVED_WINDOW", "window for alarm-served-limit (e.g. 5m)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.AlarmServedWindow })},
+	{"alarm-empty-within", "PRIME_POOL_ALARM_EMPTY_WITHIN", "alarm when the pool will empty within this time at the current rate (e.g. 30m, 0 disables)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.AlarmEmptyWithin })},
+	{"freeze-on-anomaly", "PRIME_POOL_FREEZE_ON_ANOMALY", "stop serving after duplicate params, validation failures or audit write failures until unfrozen", boolSetter(func(c *Config) *bool { return &c.Pool.FreezeOnAnomaly })},
+	{"freeze-failure-limit", "PRIME_POOL_FREEZE_FAILURE_LIMIT", "validation failures within freeze-failure-window that freeze the pool", intSetter(func(c *Config) *int { return &c.Pool.FreezeFailureLimit })},
+	{"freeze-failure-window", "PRIME_POOL_FREEZE_FAILURE_WINDOW", "window for freeze-failure-limit (e.g. 10m)", durationSetter(func(c *Config) *time.Duration { return &c.Pool.FreezeFailureWindow })},
+	{"safe-prime-source", "PRIME_GENERATOR_SAFE_PRIME_SOURCE", "safe prime search algorithm (tss-lib, or one registered with generator.RegisterSafePrimeSource)", func(c *Config, v string) error {
+		c.Generator.SafePrimeSource = v
+		return nil
+	}},
+	{"safe-prime-workers", "PRIME_GENERATOR_SAFE_PRIME_WORKERS", "safe prime workers per item (0: automatic)", intSetter(func(c *Config) *int { return &c.Generator.SafePrimeWorkers })},
+	{"generation-cpu-budget", "PRIME_GENERATOR_CPU_BUDGET", "CPUs generation may use across all pools (0: all)", intSetter(func(c *Config) *int { return &c.Generator.CPUBudget })},
+	{"generation-workers", "PRIME_GENERATOR_WORKERS", "items generated at once across all pools, requests ahead of refills (0: one per CPU, at least 2)", intSetter(func(c *Config) *int { return &c.Generator.Workers })},
+	{"generation-cpu-affinity", "PRIME_GENERATOR_CPU_AFFINITY", "CPU list to pin generation threads to, e.g. 2-3 (Linux; empty disables)", func(c *Config, v string) error {
+		c.Generator.CPUAffinity = v
+		return nil
+	}},
+	{"notify-webhook-url", "PRIME_NOTIFY_WEBHOOK_URL", "URL alarm events are POSTed to as JSON", func(c *Config, v string) error {
+		c.Notify.WebhookURL = v
+		return nil
+	}},
+	{"peer-token", "PRIME_PEER_TOKEN", "shared secret for pool sharing between replicas", func(c *Config, v string) error {
+		c.Peer.Token = v
+		return nil
+	}},
+	{"peers", "PRIME_PEER_ADDRESSES", "comma-separated peer addresses to pull surplus items from", func(c *Config, v string) error {
+		c.Peer.Peers = splitList(v)
+		return nil
+	}},
+	{"peer-sync-interval", "PRIME_PEER_SYNC_INTERVAL", "seconds between peer pulls", intSetter(func(c *Config) *int { return &c.Peer.SyncInterval })},
+	{"peer-max-transfer", "PRIME_PEER_MAX_TRANSFER", "maximum items pulled from peers per sync", intSetter(func(c *Config) *int { return &c.Peer.MaxTransfer })},
+	{"bootstrap-upstream", "PRIME_BOOTSTRAP_UPSTREAM", "upstream service a brand-new instance seeds its pool from", func(c *Config, v string) error {
+		c.Bootstrap.Upstream = v
+		return nil
+	}},
+	{"bootstrap-api-key", "PRIME_BOOTSTRAP_API_KEY", "API key presented to the bootstrap upstream", func(c *Config, v string) error {
+		c.Bootstrap.APIKey = v
+		return nil
+	}},
+	{"bootstrap-count", "PRIME_BOOTSTRAP_COUNT", "items seeded from upstream (0: min pool size)", intSetter(func(c *Config) *int { return &c.Bootstrap.Count })},
+	{"bootstrap-timeout", "PRIME_BOOTSTRAP_TIMEOUT", "seconds seeding from upstream may take", intSetter(func(c *Config) *int { return &c.Bootstrap.Timeout })},
+	{"bootstrap-proxy", "PRIME_BOOTSTRAP_PROXY", "keep topping up the pool from the bootstrap upstream while it runs low", boolSetter(func(c *Config) *bool { return &c.Bootstrap.Proxy })},
+	{"bootstrap-proxy-interval", "PRIME_BOOTSTRAP_PROXY_INTERVAL", "seconds between upstream top-up checks", intSetter(func(c *Config) *int { return &c.Bootstrap.ProxyInterval })},
+	{"bootstrap-proxy-max-pull", "PRIME_BOOTSTRAP_PROXY_MAX_PULL", "maximum items pulled from upstream per check", intSetter(func(c *Config) *int { return &c.Bootstrap.ProxyMaxPull })},
+	{"worker-bootstrap-token", "PRIME_WORKER_BOOTSTRAP_TOKEN", "secret generate-only workers register with (empty disables worker submission)", func(c *Config, v string) error {
+		c.Worker.BootstrapToken = v
+		return nil
+	}},
+	{"worker-token-ttl", "PRIME_WORKER_TOKEN_TTL", "seconds an issued worker token is valid", intSetter(func(c *Config) *int { return &c.Worker.TokenTTL })},
+	{"log-level", "PRIME_LOG_LEVEL", "log level", func(c *Config, v string) error {
+		c.Logging.Level = v
+		return nil
+	}},
+	{"log-keep-lines", "PRIME_LOGGING_KEEP_LINES", "recent log lines kept in memory for diagnostic bundles", intSetter(func(c *Config) *int { return &c.Logging.KeepLines })},
+	{"access-log-path", "PRIME_LOGGING_ACCESS_LOG_PATH", "JSON lines file recording every RPC (empty: none)", func(c *Config, v string) error {
+		c.Logging.AccessLog.Path = v
+		return nil
+	}},
+	{"access-log-max-size-mb", "PRIME_LOGGING_ACCESS_LOG_MAX_SIZE_MB", "size in MB at which the access log is rotated (-1: never)", intSetter(func(c *Config) *int { return &c.Logging.AccessLog.MaxSizeMB })},
+	{"access-log-rotate-hours", "PRIME_LOGGING_ACCESS_LOG_ROTATE_HOURS", "hours after which the access log is rotated (0: never)", intSetter(func(c *Config) *int { return &c.Logging.AccessLog.RotateHours })},
+	{"access-log-max-backups", "PRIME_LOGGING_ACCESS_LOG_MAX_BACKUPS", "rotated access log files kept (-1: all)", intSetter(func(c *Config) *int { return &c.Logging.AccessLog.MaxBackups })},
+	{"access-log-syslog", "PRIME_LOGGING_ACCESS_LOG_SYSLOG", "also ship access entries to syslog: local or network://host:port", func(c *Config, v string) error {
+		c.Logging.AccessLog.Syslog = v
+		return nil
+	}},
+	{"access-log-syslog-tag", "PRIME_LOGGING_ACCESS_LOG_SYSLOG_TAG", "syslog tag of access entries", func(c *Config, v string) error {
+		c.Logging.AccessLog.SyslogTag = v
+		return nil
+	}},
+	{"auth-keys-file", "PRIME_AUTH_KEYS_FILE", "JSON file of API keys clients must send to get parameters (no keys: unauthenticated)", func(c *Config, v string) error {
+		c.Auth.KeysFile = v
+		return nil
+	}},
+	{"min-prime-bit-size", "PRIME_HARDENING_MIN_PRIME_BIT_SIZE", "smallest prime_bit_size a pool may be configured with", intSetter(func(c *Config) *int { return &c.Hardening.MinPrimeBitSize })},
+	{"min-paillier-bit-size", "PRIME_HARDENING_MIN_PAILLIER_BIT_SIZE", "smallest paillier_bit_size a pool may be configured with", intSetter(func(c *Config) *int { return &c.Hardening.MinPaillierBitSize })},
+	{"allow-insecure", "PRIME_HARDENING_ALLOW_INSECURE", "start despite failed hardening checks (weak bit sizes, plaintext unauthenticated listeners)", boolSetter(func(c *Config) *bool { return &c.Hardening.AllowInsecure })},
+}
+
+// RegisterFlags registers a command-line flag for every overridable setting
+func RegisterFlags(fs *flag.FlagSet) {
+	for _, s := range settings {
+		fs.String(s.flag, "", fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+}
+
+// ApplyFlags applies the flags that were explicitly set on the command line
+func (c *Config) ApplyFlags(fs *flag.FlagSet) error {
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		for _, s := range settings {
+			if s.flag == f.Name {
+				if setErr := s.set(c, f.Value.String()); setErr != nil {
+					err = fmt.Errorf("flag -%s: %w", s.flag, setErr)
+				}
+				return
+			}
+		}
+	})
+	return err
+}
+
+// ApplyEnv applies overrides from PRIME_* environment variables
+func (c *Config) ApplyEnv() error {
+	for _, s := range settings {
+		v, ok := os.LookupEnv(s.env)
+		if !ok {
+			continue
+		}
+		if err := s.set(c, v); err != nil {
+			return fmt.Errorf("env %s: %w", s.env, err)
+		}
+	}
+	return nil
+}
+
+func intSetter(field func(c *Config) *int) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", v)
+		}
+		*field(c) = n
+		return nil
+	}
+}
+
+func boolSetter(field func(c *Config) *bool) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", v)
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+// durationSetter accepts Go durations ("30s") or plain seconds ("30")
+func durationSetter(field func(c *Config) *time.Duration) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		v = strings.TrimSpace(v)
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			*field(c) = seconds(f)
+			return nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", v)
+		}
+		*field(c) = d
+		return nil
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
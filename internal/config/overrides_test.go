@@ -0,0 +1,132 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		check  func(c *Config) bool
+		errMsg string
+	}{
+		{"int", map[string]string{"PRIME_POOL_MIN_SIZE": "7"}, func(c *Config) bool { return c.Pool.MinPoolSize == 7 }, ""},
+		{"bool", map[string]string{"PRIME_POOL_ON_DEMAND_GEN": "true"}, func(c *Config) bool { return c.Pool.OnDemandGen }, ""},
+		{"duration", map[string]string{"PRIME_POOL_REFILL_INTERVAL": "45s"}, func(c *Config) bool { return c.Pool.RefillInterval == 45*time.Second }, ""},
+		{"duration in seconds", map[string]string{"PRIME_POOL_REFILL_INTERVAL": "90"}, func(c *Config) bool { return c.Pool.RefillInterval == 90*time.Second }, ""},
+		{"string", map[string]string{"PRIME_POOL_SELECTION_POLICY": "random"}, func(c *Config) bool { return c.Pool.SelectionPolicy == "random" }, ""},
+		{"invalid int", map[string]string{"PRIME_POOL_MIN_SIZE": "seven"}, nil, "env PRIME_POOL_MIN_SIZE"},
+		{"invalid bool", map[string]string{"PRIME_POOL_ON_DEMAND_GEN": "sometimes"}, nil, "invalid boolean"},
+		{"invalid duration", map[string]string{"PRIME_POOL_REFILL_INTERVAL": "soon"}, nil, "invalid duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			c := Default()
+			err := c.ApplyEnv()
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("ApplyEnv() = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyEnv() = %v", err)
+			}
+			if !tt.check(c) {
+				t.Fatalf("ApplyEnv() did not apply %v", tt.env)
+			}
+		})
+	}
+}
+
+func TestApplyFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		check  func(c *Config) bool
+		errMsg string
+	}{
+		{"none", nil, func(c *Config) bool { return c.Pool.MinPoolSize == Default().Pool.MinPoolSize }, ""},
+		{"int", []string{"-max-pool-size", "50"}, func(c *Config) bool { return c.Pool.MaxPoolSize == 50 }, ""},
+		{"bool", []string{"-allow-insecure", "true"}, func(c *Config) bool { return c.Hardening.AllowInsecure }, ""},
+		{"negative duration", []string{"-startup-delay", "-1s"}, func(c *Config) bool { return c.Pool.StartupDelay == -time.Second }, ""},
+		{"invalid int", []string{"-max-pool-size", "many"}, nil, "flag -max-pool-size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse(%q) = %v", tt.args, err)
+			}
+			c := Default()
+			err := c.ApplyFlags(fs)
+			if tt.errMsg != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Fatalf("ApplyFlags() = %v, want error containing %q", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyFlags() = %v", err)
+			}
+			if !tt.check(c) {
+				t.Fatalf("ApplyFlags() did not apply %q", tt.args)
+			}
+		})
+	}
+}
+
+func TestResolvePrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"pool": {"min_pool_size": 3, "max_pool_size": 30, "refill_threshold": 1}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		args    []string
+		wantMin int
+		wantMax int
+	}{
+		{"file", nil, nil, 3, 30},
+		{"env over file", map[string]string{"PRIME_POOL_MAX_SIZE": "40"}, nil, 3, 40},
+		{"flag over env", map[string]string{"PRIME_POOL_MAX_SIZE": "40"}, []string{"-max-pool-size", "60"}, 3, 60},
+		{"flag over file", nil, []string{"-min-pool-size", "5"}, 5, 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			RegisterFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatalf("Parse(%q) = %v", tt.args, err)
+			}
+			c, err := Resolve(path, fs)
+			if err != nil {
+				t.Fatalf("Resolve() = %v", err)
+			}
+			if c.Pool.MinPoolSize != tt.wantMin || c.Pool.MaxPoolSize != tt.wantMax {
+				t.Fatalf("pool size = %d..%d, want %d..%d", c.Pool.MinPoolSize, c.Pool.MaxPoolSize, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
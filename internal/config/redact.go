@@ -0,0 +1,59 @@
+package config
+
+import (
+	"net/url"
+
+	"github.com/TEENet-io/prime-service/internal/apikey"
+)
+
+// redacted replaces secrets in Redacted
+const redacted = "REDACTED"
+
+// Redacted returns a copy of c with secrets replaced, safe to share in bug
+// reports: the peer and worker bootstrap tokens, API keys (including the
+// bootstrap upstream's), pool encryption keys, and everything but the scheme and host of the webhook URL (webhook
+// paths often embed tokens)
+func (c *Config) Redacted() Config {
+	r := *c
+	r.Auth.Keys = make([]apikey.Key, len(c.Auth.Keys))
+	for i, k := range c.Auth.Keys {
+		if k.Key != "" {
+			k.Key = redacted
+		}
+		r.Auth.Keys[i] = k
+	}
+	r.Pool = c.Pool.redacted()
+	r.Pools = make([]NamedPoolConfig, len(c.Pools))
+	for i, p := range c.Pools {
+		p.PoolConfig = p.PoolConfig.redacted()
+		r.Pools[i] = p
+	}
+	if r.Peer.Token != "" {
+		r.Peer.Token = redacted
+	}
+	if r.Bootstrap.APIKey != "" {
+		r.Bootstrap.APIKey = redacted
+	}
+	if r.Worker.BootstrapToken != "" {
+		r.Worker.BootstrapToken = redacted
+	}
+	if r.Notify.WebhookURL != "" {
+		r.Notify.WebhookURL = redacted
+		if u, err := url.Parse(c.Notify.WebhookURL); err == nil && u.Host != "" {
+			r.Notify.WebhookURL = u.Scheme + "://" + u.Host + "/" + redacted
+		}
+	}
+	return r
+}
+
+// redacted returns a copy of p with its encryption key and Redis password
+// replaced
+func (p PoolConfig) redacted() PoolConfig {
+	if p.EncryptionKey != "" {
+		p.EncryptionKey = redacted
+	}
+	if p.RedisPassword != "" {
+		p.RedisPassword = redacted
+	}
+	return p
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// isYAML reports whether path names a YAML config file
+func isYAML(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// yamlToJSON converts a YAML document to JSON, so YAML files go through the
+// same decoding (durations, named pools, defaults) as JSON ones. An empty
+// document converts to an empty object.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return []byte("{}"), nil
+	}
+	value, err := jsonValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// jsonValue converts a decoded YAML value to one encoding/json can marshal,
+// rejecting mappings with keys that are not scalars
+func jsonValue(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			v[k] = converted
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			switch k.(type) {
+			case map[string]interface{}, map[interface{}]interface{}, []interface{}:
+				return nil, fmt.Errorf("unsupported mapping key %v", k)
+			}
+			key := fmt.Sprint(k)
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			m[key] = converted
+		}
+		return m, nil
+	case []interface{}:
+		for i, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			v[i] = converted
+		}
+		return v, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,163 @@
+package errjournal
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Severity ranks journal entries
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning" // Degraded but recovered (e.g. a peer pull failed)
+	SeverityError   Severity = "error"   // An operation failed (e.g. generation or a save)
+)
+
+// rank orders severities for filtering; unknown severities rank lowest
+func (s Severity) rank() int {
+	switch s {
+	case SeverityWarning:
+		return 1
+	case SeverityError:
+		return 2
+	}
+	return 0
+}
+
+// ParseSeverity parses a severity name; empty means no filter
+func ParseSeverity(v string) (Severity, error) {
+	switch s := Severity(v); s {
+	case "", SeverityWarning, SeverityError:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown severity %q (want warning or error)", v)
+}
+
+// Entry is a single recorded error
+type Entry struct {
+	Time      time.Time         `json:"time"`
+	Severity  Severity          `json:"severity"`
+	Component string            `json:"component"`
+	Error     string            `json:"error"`
+	Context   map[string]string `json:"context,omitempty"`
+}
+
+// Journal keeps the most recent errors in memory and on disk so operators can
+// retrieve them after the fact instead of digging through stdout logs
+type Journal struct {
+	mu      sync.Mutex
+	path    string
+	limit   int
+	entries []Entry // oldest first
+}
+
+// New creates a journal bounded to limit entries, restoring any entries
+// previously saved at path. An empty path keeps the journal in memory only.
+func New(path string, limit int) *Journal {
+	j := &Journal{path: path, limit: limit}
+	j.load()
+	return j
+}
+
+// Record stores an error and persists the journal. Persistence failures are
+// only logged, so callers never fail because of the journal itself.
+// A nil Journal discards entries.
+func (j *Journal) Record(severity Severity, component string, err error, context map[string]string) {
+	if j == nil || err == nil {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = append(j.entries, Entry{
+		Time:      time.Now(),
+		Severity:  severity,
+		Component: component,
+		Error:     err.Error(),
+		Context:   context,
+	})
+	if over := len(j.entries) - j.limit; over > 0 {
+		j.entries = append(j.entries[:0:0], j.entries[over:]...)
+	}
+
+	if err := j.saveLocked(); err != nil {
+		log.Printf("Failed to save error journal: %v", err)
+	}
+}
+
+// Entries returns up to limit entries (all if limit <= 0), newest first,
+// with at least minSeverity and, if set, from the given component
+func (j *Journal) Entries(minSeverity Severity, component string, limit int) []Entry {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	result := make([]Entry, 0)
+	for i := len(j.entries) - 1; i >= 0; i-- {
+		e := j.entries[i]
+		if e.Severity.rank() < minSeverity.rank() {
+			continue
+		}
+		if component != "" && e.Component != component {
+			continue
+		}
+		result = append(result, e)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result
+}
+
+// saveLocked writes the journal atomically (temp file + rename)
+func (j *Journal) saveLocked() error {
+	if j.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(j.entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal error journal: %w", err)
+	}
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write error journal: %w", err)
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return fmt.Errorf("failed to replace error journal: %w", err)
+	}
+	return nil
+}
+
+// load restores entries from disk, keeping the newest limit entries
+func (j *Journal) load() {
+	if j.path == "" {
+		return
+	}
+	data, err := os.ReadFile(j.path)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to read error journal: %v", err)
+		return
+	}
+
+	if err := json.Unmarshal(data, &j.entries); err != nil {
+		log.Printf("Failed to unmarshal error journal: %v", err)
+		j.entries = nil
+		return
+	}
+	if over := len(j.entries) - j.limit; over > 0 {
+		j.entries = j.entries[over:]
+	}
+}
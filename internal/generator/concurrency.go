@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+
+	"github.com/TEENet-io/prime-service/internal/affinity"
+	"github.com/TEENet-io/prime-service/internal/scheduler"
+)
+
+// defaultSafePrimeWorkers is the per-item safe prime concurrency tss-lib
+// callers traditionally use, and the automatic setting's ceiling
+const defaultSafePrimeWorkers = 4
+
+// SetConcurrency sets the workers searching for the safe primes of one item
+// (0: automatic) and the CPUs generation may use across all pools (0: all).
+// Items started afterwards use the new settings.
+func (g *Generator) SetConcurrency(safePrimeWorkers, cpuBudget int) {
+	g.safePrimeWorkers.Store(int32(safePrimeWorkers))
+	g.cpuBudget.Store(int32(cpuBudget))
+	g.scheduler.SetLimit(g.Workers())
+}
+
+// SetWorkers sets the items generated at once across all pools (0: one per
+// CPU of the budget, at least 2). Requests generating on demand, emergency
+// refills and background refills and fills share these slots, in that
+// order of priority.
+func (g *Generator) SetWorkers(workers int) {
+	g.workers.Store(int32(workers))
+	g.scheduler.SetLimit(g.Workers())
+}
+
+// Workers returns the items generated at once across all pools
+func (g *Generator) Workers() int {
+	if workers := int(g.workers.Load()); workers > 0 {
+		return workers
+	}
+	return max(g.budget(), 2)
+}
+
+// Scheduler returns the scheduler granting the generation slots
+func (g *Generator) Scheduler() *scheduler.Scheduler {
+	return g.scheduler
+}
+
+// budget returns the CPUs generation may use: the configured budget, or
+// all CPUs of the affinity list or the machine
+func (g *Generator) budget() int {
+	if budget := int(g.cpuBudget.Load()); budget > 0 {
+		return budget
+	}
+	if cpus := g.Affinity(); len(cpus) > 0 {
+		return len(cpus)
+	}
+	return runtime.NumCPU()
+}
+
+// SafePrimeWorkers returns the safe prime workers an item started now gets:
+// the configured count, or defaultSafePrimeWorkers, but no more than its
+// share of the CPU budget among the items generating
+func (g *Generator) SafePrimeWorkers() int {
+	share := max(g.budget()/max(int(g.active.Load()), 1), 1)
+
+	workers := int(g.safePrimeWorkers.Load())
+	if workers <= 0 {
+		workers = defaultSafePrimeWorkers
+	}
+	return min(workers, share)
+}
+
+// SetAffinity pins the threads generating parameters to cpus (nil: no
+// pinning), which must be CPUs the process may run on
+func (g *Generator) SetAffinity(cpus []int) error {
+	if len(cpus) > 0 {
+		allowed, err := affinity.Allowed()
+		if err != nil {
+			return err
+		}
+		for _, cpu := range cpus {
+			if !slices.Contains(allowed, cpu) {
+				return fmt.Errorf("CPU %d is not available to the process (allowed: %s)", cpu, affinity.Format(allowed))
+			}
+		}
+	}
+	g.affinity.Store(&cpus)
+	g.scheduler.SetLimit(g.Workers())
+	return nil
+}
+
+// Affinity returns the CPUs generation is pinned to (nil: not pinned)
+func (g *Generator) Affinity() []int {
+	if cpus := g.affinity.Load(); cpus != nil {
+		return *cpus
+	}
+	return nil
+}
+
+// pinThread locks the calling goroutine to its thread and pins the thread
+// to the configured CPUs, returning the function that undoes both. Threads
+// the Go runtime creates meanwhile do not inherit the pinning.
+func (g *Generator) pinThread() (unpin func()) {
+	cpus := g.Affinity()
+	if len(cpus) == 0 {
+		return func() {}
+	}
+
+	runtime.LockOSThread()
+	restore, err := affinity.Pin(cpus)
+	if err != nil {
+		g.pinFailures.Add(1)
+		return runtime.UnlockOSThread
+	}
+	return func() {
+		restore()
+		runtime.UnlockOSThread()
+	}
+}
+
+// random returns the randomness source of one item. With an affinity set it
+// also pins the goroutines the safe prime source starts, which read from it
+// before searching: each is locked to its thread and the thread
+// pinned on its first read. The goroutines exit still locked, so the
+// runtime discards their pinned threads instead of reusing them.
+func (g *Generator) random() io.Reader {
+	cpus := g.Affinity()
+	if len(cpus) == 0 {
+		return rand.Reader
+	}
+	return &pinningReader{g: g, cpus: cpus}
+}
+
+// pinningReader reads from crypto/rand, pinning the reading thread first
+type pinningReader struct {
+	g    *Generator
+	cpus []int
+}
+
+func (r *pinningReader) Read(p []byte) (int, error) {
+	runtime.LockOSThread()
+	if affinity.PinnedTo(r.cpus) {
+		// Pinned (and locked) by an earlier read or pinThread
+		runtime.UnlockOSThread()
+	} else if _, err := affinity.Pin(r.cpus); err != nil {
+		r.g.pinFailures.Add(1)
+		runtime.UnlockOSThread()
+	}
+	// Otherwise the thread stays locked and is discarded with its goroutine
+	return rand.Read(p)
+}
+
+// PinFailures returns how often a generation thread could not be pinned
+func (g *Generator) PinFailures() int64 {
+	return g.pinFailures.Load()
+}
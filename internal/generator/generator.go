@@ -5,9 +5,13 @@ import (
 	"crypto/rand"
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/TEENet-io/prime-service/internal/paramcheck"
+	"github.com/TEENet-io/prime-service/internal/scheduler"
 	"github.com/bnb-chain/tss-lib/v2/common"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
 	"github.com/bnb-chain/tss-lib/v2/ecdsa/keygen"
@@ -17,6 +21,34 @@ type Generator struct {
 	mu              sync.Mutex
 	generationCount int64
 	totalTime       time.Duration
+
+	// Legacy GeneratePrime calls, kept apart so they do not skew the
+	// parameter generation average used for pool pressure estimates
+	legacyCount int64
+	legacyTime  time.Duration
+
+	// Safe prime workers per item and the CPUs shared by all items, see
+	// SetConcurrency
+	safePrimeWorkers atomic.Int32
+	cpuBudget        atomic.Int32
+	active           atomic.Int32 // GeneratePreParams calls running
+
+	// Safe prime search algorithm, see SetSafePrimeSource
+	safePrimes atomic.Pointer[namedSource]
+
+	// Generation slots shared by requests and refills of all pools, see
+	// SetWorkers
+	scheduler *scheduler.Scheduler
+	workers   atomic.Int32
+
+	// CPUs generation threads are pinned to, see SetAffinity
+	affinity    atomic.Pointer[[]int]
+	pinFailures atomic.Int64
+
+	// Time spent in each phase of GeneratePreParams
+	paillierPhase  phaseTimer
+	safePrimePhase phaseTimer
+	pedersenPhase  phaseTimer
 }
 
 // PreParamsData represents complete pre-computed parameters for ECDSA DKG
@@ -31,16 +63,39 @@ type PreParamsData struct {
 	P           *big.Int             `json:"p"` // safe prime
 	Q           *big.Int             `json:"q"` // safe prime
 	GeneratedAt time.Time            `json:"generated_at"`
+
+	// GenerationDuration is the wall time spent generating this set
+	GenerationDuration time.Duration `json:"generation_duration"`
 }
 
 func NewGenerator() *Generator {
-	return &Generator{}
+	g := &Generator{}
+	g.scheduler = scheduler.New(g.Workers())
+	return g
 }
 
 // GeneratePreParams generates complete pre-computed parameters for ECDSA DKG
 // This is the exact implementation from TEE DAO's generateSinglePreParams
 func (g *Generator) GeneratePreParams(primeBitSize, paillierBitSize int) (*PreParamsData, error) {
+	return g.GeneratePreParamsYielding(primeBitSize, paillierBitSize, nil)
+}
+
+// GeneratePreParamsYielding is GeneratePreParams calling yield (nil: none)
+// every few candidates of its prime searches. An error from yield abandons
+// the item and is returned; time parked in yield does not count towards the
+// phase timeouts.
+func (g *Generator) GeneratePreParamsYielding(primeBitSize, paillierBitSize int, yield YieldFunc) (*PreParamsData, error) {
 	start := time.Now()
+	g.active.Add(1)
+	defer g.active.Add(-1)
+	defer g.pinThread()()
+	ctx, y, cancel := withYield(context.Background(), yield)
+	defer cancel()
+	base := g.random()
+	random := base
+	if y != nil {
+		random = &yieldingReader{r: base, y: y}
+	}
 	defer func() {
 		g.mu.Lock()
 		g.generationCount++
@@ -49,36 +104,54 @@ func (g *Generator) GeneratePreParams(primeBitSize, paillierBitSize int) (*PrePa
 	}()
 
 	// Generate Paillier key pair (exact same as TEE DAO)
-	ctx1, cancel1 := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx1, cancel1 := y.phase(ctx, 5*time.Minute)
 	defer cancel1()
 
-	paillierSK, _, err := paillier.GenerateKeyPair(ctx1, rand.Reader, paillierBitSize, 4)
+	phaseStart := time.Now()
+	paillierSK, _, err := paillier.GenerateKeyPair(ctx1, random, paillierBitSize, 4)
+	g.paillierPhase.record(phaseStart, err)
+	if yerr := y.err(); yerr != nil {
+		return nil, yerr
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate Paillier key: %w", err)
+		return nil, fmt.Errorf("failed to generate Paillier key: %w", phaseErr(ctx1, err))
 	}
 
-	// Generate safe primes for NTildei (exact same as TEE DAO)
-	ctx2, cancel2 := context.WithTimeout(context.Background(), 5*time.Minute)
+	// Generate safe primes for NTildei (with tss-lib, exact same as TEE DAO)
+	ctx2, cancel2 := y.phase(ctx, 5*time.Minute)
 	defer cancel2()
 
-	sgps, err := common.GetRandomSafePrimesConcurrent(ctx2, primeBitSize, 2, 4, rand.Reader)
+	phaseStart = time.Now()
+	source := g.safePrimeSource()
+	sgps, err := source.source.SafePrimes(ctx2, primeBitSize, 2, g.SafePrimeWorkers(), random)
+	g.safePrimePhase.record(phaseStart, err)
+	if yerr := y.err(); yerr != nil {
+		return nil, yerr
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate safe primes: %w", err)
+		return nil, fmt.Errorf("failed to generate safe primes (%s): %w", source.name, phaseErr(ctx2, err))
+	}
+	if len(sgps) != 2 {
+		return nil, fmt.Errorf("safe prime source %s returned %d safe primes, want 2", source.name, len(sgps))
 	}
 
 	// Calculate NTildei from the safe primes
-	nTildei := new(big.Int).Mul(sgps[0].SafePrime(), sgps[1].SafePrime())
+	phaseStart = time.Now()
+	nTildei := new(big.Int).Mul(sgps[0].P, sgps[1].P)
 
 	// Generate h1, h2 in Z*_NTildei (exact same as TEE DAO)
-	primeP, primeQ := sgps[0].Prime(), sgps[1].Prime()
+	primeP, primeQ := sgps[0].Q, sgps[1].Q
 	modPQ := common.ModInt(new(big.Int).Mul(primeP, primeQ))
 	modNTildeI := common.ModInt(nTildei)
 
-	f1 := common.GetRandomPositiveRelativelyPrimeInt(rand.Reader, nTildei)
-	alpha := common.GetRandomPositiveRelativelyPrimeInt(rand.Reader, nTildei)
+	// Short enough to run without yielding; the tss-lib helpers panic on
+	// the read errors an abandoned generation returns
+	f1 := common.GetRandomPositiveRelativelyPrimeInt(base, nTildei)
+	alpha := common.GetRandomPositiveRelativelyPrimeInt(base, nTildei)
 	beta := modPQ.ModInverse(alpha)
 	h1 := modNTildeI.Mul(f1, f1)
 	h2 := modNTildeI.Exp(h1, alpha)
+	g.pedersenPhase.record(phaseStart, nil)
 
 	return &PreParamsData{
 		PaillierKey: paillierSK,
@@ -90,6 +163,8 @@ func (g *Generator) GeneratePreParams(primeBitSize, paillierBitSize int) (*PrePa
 		P:           primeP,
 		Q:           primeQ,
 		GeneratedAt: time.Now(),
+
+		GenerationDuration: time.Since(start),
 	}, nil
 }
 
@@ -108,12 +183,42 @@ func (p *PreParamsData) ConvertToLocalPreParams() *keygen.LocalPreParams {
 	}
 }
 
-// GeneratePreParamsBatch generates multiple PreParamsData concurrently
+// BatchError reports the items of a batch that failed to generate
+type BatchError struct {
+	Count  int         // Items requested
+	Failed []ItemError // In item order
+}
+
+// ItemError is the failure of one batch item
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Failed))
+	for i, f := range e.Failed {
+		msgs[i] = fmt.Sprintf("item %d: %v", f.Index, f.Err)
+	}
+	return fmt.Sprintf("batch generation failed for %d of %d items: %s", len(e.Failed), e.Count, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the item errors for errors.Is and errors.As
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// GeneratePreParamsBatch generates multiple PreParamsData concurrently. If
+// some items fail, the others are still returned, together with a
+// *BatchError naming the failed items.
 func (g *Generator) GeneratePreParamsBatch(primeBitSize, paillierBitSize int, count uint32) ([]*PreParamsData, error) {
-	var params []*PreParamsData
-	var mu sync.Mutex
+	results := make([]*PreParamsData, count)
+	errs := make([]error, count)
 	var wg sync.WaitGroup
-	errCh := make(chan error, count)
 
 	// Use limited concurrency to avoid overwhelming the system
 	semaphore := make(chan struct{}, 2) // Max 2 concurrent generations (heavy operation)
@@ -126,28 +231,25 @@ func (g *Generator) GeneratePreParamsBatch(primeBitSize, paillierBitSize int, co
 			semaphore <- struct{}{}        // Acquire
 			defer func() { <-semaphore }() // Release
 
-			param, err := g.GeneratePreParams(primeBitSize, paillierBitSize)
-			if err != nil {
-				errCh <- err
-				return
-			}
-
-			mu.Lock()
-			params = append(params, param)
-			mu.Unlock()
+			results[i], errs[i] = g.GeneratePreParams(primeBitSize, paillierBitSize)
 		}()
 	}
 
 	wg.Wait()
-	close(errCh)
 
-	// Check for errors
-	for err := range errCh {
+	// Keep the successes, collect the failures
+	params := make([]*PreParamsData, 0, count)
+	batchErr := &BatchError{Count: int(count)}
+	for i, err := range errs {
 		if err != nil {
-			return nil, fmt.Errorf("batch generation failed: %w", err)
+			batchErr.Failed = append(batchErr.Failed, ItemError{Index: i, Err: err})
+			continue
 		}
+		params = append(params, results[i])
+	}
+	if len(batchErr.Failed) > 0 {
+		return params, batchErr
 	}
-
 	return params, nil
 }
 
@@ -158,6 +260,14 @@ func (g *Generator) GetStatistics() (int64, time.Duration) {
 	return g.generationCount, g.totalTime
 }
 
+// GetLegacyStatistics returns the count and total time of GeneratePrime
+// calls, which GetStatistics excludes
+func (g *Generator) GetLegacyStatistics() (int64, time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.legacyCount, g.legacyTime
+}
+
 // GetAverageGenerationTime returns the average time to generate parameters
 func (g *Generator) GetAverageGenerationTime() time.Duration {
 	g.mu.Lock()
@@ -171,20 +281,35 @@ func (g *Generator) GetAverageGenerationTime() time.Duration {
 }
 
 // GeneratePrime generates a prime number with the specified number of bits
-// (kept for backward compatibility)
+// (kept for backward compatibility). The result is validated before it is
+// returned.
+//
+// Deprecated: primes from GeneratePrime bypass the pool and its safeguards
+// (persistence, duplicate detection, freezing, alarms); use GeneratePreParams
+// through a pool.Manager.
 func (g *Generator) GeneratePrime(bits uint32, safePrime bool) (*big.Int, error) {
 	start := time.Now()
 	defer func() {
 		g.mu.Lock()
-		g.generationCount++
-		g.totalTime += time.Since(start)
+		g.legacyCount++
+		g.legacyTime += time.Since(start)
 		g.mu.Unlock()
 	}()
 
+	var prime *big.Int
+	var err error
 	if safePrime {
-		return g.generateSafePrime(bits)
+		prime, err = g.generateSafePrime(bits)
+	} else {
+		prime, err = g.generateRegularPrime(bits)
 	}
-	return g.generateRegularPrime(bits)
+	if err != nil {
+		return nil, err
+	}
+	if err := paramcheck.CheckPrime(prime, int(bits), safePrime); err != nil {
+		return nil, fmt.Errorf("generated prime failed validation: %w", err)
+	}
+	return prime, nil
 }
 
 // generateRegularPrime generates a regular prime number
@@ -201,7 +326,7 @@ func (g *Generator) generateRegularPrime(bits uint32) (*big.Int, error) {
 	return prime, nil
 }
 
-// generateSafePrime generates a safe prime using tss-lib
+// generateSafePrime generates a safe prime with the selected source
 func (g *Generator) generateSafePrime(bits uint32) (*big.Int, error) {
 	if bits < 3 {
 		return nil, fmt.Errorf("safe prime size must be at least 3-bits")
@@ -210,15 +335,20 @@ func (g *Generator) generateSafePrime(bits uint32) (*big.Int, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
-	sgps, err := common.GetRandomSafePrimesConcurrent(ctx, int(bits), 1, 4, rand.Reader)
+	sgps, err := g.safePrimeSource().source.SafePrimes(ctx, int(bits), 1, 4, rand.Reader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate safe prime: %w", err)
 	}
+	if len(sgps) == 0 {
+		return nil, fmt.Errorf("safe prime source returned no safe prime")
+	}
 
-	return sgps[0].SafePrime(), nil
+	return sgps[0].P, nil
 }
 
 // GenerateBatch generates multiple primes concurrently
+//
+// Deprecated: see GeneratePrime.
 func (g *Generator) GenerateBatch(bits uint32, safePrime bool, count uint32) ([]*big.Int, error) {
 	var primes []*big.Int
 	var mu sync.Mutex
@@ -0,0 +1,83 @@
+package generator
+
+import (
+	"sync"
+	"time"
+)
+
+// histogramBounds are the bucket upper bounds of phase histograms, from
+// small test bit sizes up to slow 2048-bit safe prime searches
+var histogramBounds = []time.Duration{
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+	30 * time.Second, time.Minute, 2 * time.Minute, 5 * time.Minute,
+}
+
+// Histogram counts durations in fixed buckets
+type Histogram struct {
+	mu     sync.Mutex
+	counts []int64 // Per bucket, the last one unbounded
+	count  int64
+	sum    time.Duration
+}
+
+// HistogramSnapshot is a point-in-time copy of a Histogram. Bucket counts
+// are cumulative, as in Prometheus.
+type HistogramSnapshot struct {
+	Count      int64             `json:"count"`
+	SumSeconds float64           `json:"sum_seconds"`
+	Buckets    []HistogramBucket `json:"buckets"`
+}
+
+// HistogramBucket counts the observations of at most LESeconds (0: unbounded)
+type HistogramBucket struct {
+	LESeconds float64 `json:"le_seconds,omitempty"`
+	Count     int64   `json:"count"`
+}
+
+// Observe records one duration
+func (h *Histogram) Observe(d time.Duration) {
+	i := 0
+	for i < len(histogramBounds) && d > histogramBounds[i] {
+		i++
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.counts == nil {
+		h.counts = make([]int64, len(histogramBounds)+1)
+	}
+	h.counts[i]++
+	h.count++
+	h.sum += d
+}
+
+// totals returns the number and sum of the observations
+func (h *Histogram) totals() (int64, time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count, h.sum
+}
+
+// Snapshot returns the current counts
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := HistogramSnapshot{
+		Count:      h.count,
+		SumSeconds: h.sum.Seconds(),
+		Buckets:    make([]HistogramBucket, len(histogramBounds)+1),
+	}
+	var cumulative int64
+	for i := range s.Buckets {
+		if h.counts != nil {
+			cumulative += h.counts[i]
+		}
+		s.Buckets[i].Count = cumulative
+		if i < len(histogramBounds) {
+			s.Buckets[i].LESeconds = histogramBounds[i].Seconds()
+		}
+	}
+	return s
+}
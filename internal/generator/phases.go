@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Phases of GeneratePreParams, timed separately
+const (
+	PhasePaillier   = "paillier"    // Paillier key pair
+	PhaseSafePrimes = "safe_primes" // Safe primes for NTildei
+	PhasePedersen   = "pedersen"    // NTildei, h1, h2, alpha and beta from the safe primes
+)
+
+// PhaseStatistics summarizes the runs of one generation phase
+type PhaseStatistics struct {
+	Count      int64         `json:"count"`       // Successful runs
+	Total      time.Duration `json:"total"`       // Time spent in successful runs
+	Failures   int64         `json:"failures"`    // Failed runs (errors or timeouts)
+	FailedTime time.Duration `json:"failed_time"` // Time spent in failed runs
+}
+
+// Average returns the average time of a successful run
+func (s PhaseStatistics) Average() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// phaseTimer times the runs of one phase
+type phaseTimer struct {
+	time       Histogram // Successful runs
+	failures   atomic.Int64
+	failedTime atomic.Int64 // Nanoseconds
+}
+
+// record records a run that started at start and ended with err
+func (p *phaseTimer) record(start time.Time, err error) {
+	elapsed := time.Since(start)
+	if err != nil {
+		p.failures.Add(1)
+		p.failedTime.Add(int64(elapsed))
+		return
+	}
+	p.time.Observe(elapsed)
+}
+
+// statistics returns the phase summary
+func (p *phaseTimer) statistics() PhaseStatistics {
+	count, total := p.time.totals()
+	return PhaseStatistics{
+		Count:      count,
+		Total:      total,
+		Failures:   p.failures.Load(),
+		FailedTime: time.Duration(p.failedTime.Load()),
+	}
+}
+
+// phases returns the timer of each phase by name
+func (g *Generator) phases() map[string]*phaseTimer {
+	return map[string]*phaseTimer{
+		PhasePaillier:   &g.paillierPhase,
+		PhaseSafePrimes: &g.safePrimePhase,
+		PhasePedersen:   &g.pedersenPhase,
+	}
+}
+
+// GetPhaseStatistics returns count and time of each generation phase, so
+// tuning can target the phase that dominates
+func (g *Generator) GetPhaseStatistics() map[string]PhaseStatistics {
+	stats := make(map[string]PhaseStatistics)
+	for name, p := range g.phases() {
+		stats[name] = p.statistics()
+	}
+	return stats
+}
+
+// GetPhaseHistograms returns the timing histograms of successful runs of
+// each generation phase
+func (g *Generator) GetPhaseHistograms() map[string]HistogramSnapshot {
+	hists := make(map[string]HistogramSnapshot)
+	for name, p := range g.phases() {
+		hists[name] = p.time.Snapshot()
+	}
+	return hists
+}
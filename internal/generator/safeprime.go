@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"sync"
+
+	"github.com/bnb-chain/tss-lib/v2/common"
+)
+
+// DefaultSafePrimeSource is the safe prime source used unless configured
+// otherwise
+const DefaultSafePrimeSource = "tss-lib"
+
+// SafePrime is a safe prime P = 2Q + 1, Q being its Sophie Germain prime
+type SafePrime struct {
+	P *big.Int
+	Q *big.Int
+}
+
+// SafePrimeSource finds the safe primes of NTildei. Implementations must be
+// safe for concurrent use, return count distinct safe primes of exactly bits
+// bits whose products have 2*bits bits, draw all randomness from random, and
+// give up once ctx is done. Sources that do not draw randomness for every
+// candidate should call Yield(ctx) every few candidates.
+type SafePrimeSource interface {
+	SafePrimes(ctx context.Context, bits, count, workers int, random io.Reader) ([]SafePrime, error)
+}
+
+var (
+	sourcesMu         sync.RWMutex
+	registeredSources = map[string]SafePrimeSource{
+		DefaultSafePrimeSource: tssLibSource{},
+	}
+)
+
+// RegisterSafePrimeSource makes a safe prime source selectable by name, e.g.
+// from an experiment's init function, replacing any source of that name
+func RegisterSafePrimeSource(name string, source SafePrimeSource) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	registeredSources[name] = source
+}
+
+// SafePrimeSources returns the names of the registered sources, sorted
+func SafePrimeSources() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+	names := make([]string, 0, len(registeredSources))
+	for name := range registeredSources {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// namedSource is a safe prime source and the name it was selected by
+type namedSource struct {
+	name   string
+	source SafePrimeSource
+}
+
+// SetSafePrimeSource selects the registered safe prime source items started
+// afterwards use ("": DefaultSafePrimeSource)
+func (g *Generator) SetSafePrimeSource(name string) error {
+	if name == "" {
+		name = DefaultSafePrimeSource
+	}
+	sourcesMu.RLock()
+	source, ok := registeredSources[name]
+	sourcesMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unknown safe prime source %q (registered: %v)", name, SafePrimeSources())
+	}
+	g.safePrimes.Store(&namedSource{name: name, source: source})
+	return nil
+}
+
+// SafePrimeSource returns the name of the selected safe prime source
+func (g *Generator) SafePrimeSource() string {
+	return g.safePrimeSource().name
+}
+
+// safePrimeSource returns the selected source, by default tss-lib's
+func (g *Generator) safePrimeSource() *namedSource {
+	if s := g.safePrimes.Load(); s != nil {
+		return s
+	}
+	return &namedSource{name: DefaultSafePrimeSource, source: tssLibSource{}}
+}
+
+// tssLibSource is tss-lib's concurrent safe prime search, as TEE DAO runs it
+type tssLibSource struct{}
+
+func (tssLibSource) SafePrimes(ctx context.Context, bits, count, workers int, random io.Reader) ([]SafePrime, error) {
+	sgps, err := common.GetRandomSafePrimesConcurrent(ctx, bits, count, workers, random)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]SafePrime, len(sgps))
+	for i, sgp := range sgps {
+		result[i] = SafePrime{P: sgp.SafePrime(), Q: sgp.Prime()}
+	}
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// Version of the generation code, recorded in the provenance of every item.
+// Set it at build time with
+//
+//	-ldflags "-X github.com/TEENet-io/prime-service/internal/generator.Version=v1.4.2"
+//
+// Unset, the module version or VCS revision of the build is used.
+var Version string
+
+// UnknownVersion is reported when the build carries no version
+const UnknownVersion = "unknown"
+
+// CodeVersion returns the version of the generation code
+func CodeVersion() string {
+	if Version != "" {
+		return Version
+	}
+	return buildVersion()
+}
+
+// buildVersion returns the main module version, else the VCS revision
+// (with "-dirty" for modified trees), of the running binary
+var buildVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return UnknownVersion
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	revision, dirty := "", false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if revision == "" {
+		return UnknownVersion
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if dirty {
+		revision += "-dirty"
+	}
+	return revision
+})
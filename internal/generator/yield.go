@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// yieldEvery is how many prime candidates a generation tests between calls
+// to its yield function. tss-lib draws fresh randomness for every candidate,
+// so reads of the item's random source count them.
+const yieldEvery = 16
+
+// YieldFunc is called by a running generation every few candidates of its
+// prime searches, so generation can be paused or stopped within
+// milliseconds instead of between items. It may block, e.g. while
+// generation is paused, and returns an error to abandon the item, e.g. on
+// shutdown. It must be safe for concurrent use: the safe prime workers of
+// an item call it independently.
+type YieldFunc func() error
+
+// yieldKey is the context key of the yielder of a generation
+type yieldKey struct{}
+
+// yielder calls the yield function of one generation, cancelling the
+// generation's context once it fails
+type yielder struct {
+	fn     YieldFunc
+	cancel context.CancelFunc
+	count  atomic.Int64
+
+	mu       sync.Mutex
+	failed   error
+	deadline time.Time               // Of the running phase, moved on by time parked in fn
+	expire   context.CancelCauseFunc // Ends the running phase
+}
+
+// withYield returns the cancellable context of a generation and, with fn,
+// the yielder calling it
+func withYield(ctx context.Context, fn YieldFunc) (context.Context, *yielder, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	if fn == nil {
+		return ctx, nil, cancel
+	}
+	y := &yielder{fn: fn, cancel: cancel}
+	return context.WithValue(ctx, yieldKey{}, y), y, cancel
+}
+
+// yield calls the yield function, returning the error that abandoned the
+// generation, if any
+func (y *yielder) yield() error {
+	if err := y.err(); err != nil {
+		return err
+	}
+	start := time.Now()
+	y.mu.Lock()
+	remaining := y.deadline.Sub(start)
+	y.mu.Unlock()
+
+	err := y.fn()
+
+	// Time parked in fn does not count towards the phase timeout
+	now := time.Now()
+	y.mu.Lock()
+	defer y.mu.Unlock()
+	if err != nil {
+		if y.failed == nil {
+			y.failed = err
+		}
+		y.cancel()
+		return err
+	}
+	if y.expire != nil {
+		if y.deadline.Before(now.Add(remaining)) {
+			y.deadline = now.Add(remaining)
+		}
+		if !now.Before(y.deadline) {
+			y.expire(context.DeadlineExceeded)
+		}
+	}
+	return nil
+}
+
+// phase returns the context of a generation phase timing out after timeout,
+// not counting time parked in the yield function
+func (y *yielder) phase(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if y == nil {
+		return context.WithTimeout(ctx, timeout)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	y.mu.Lock()
+	y.deadline, y.expire = time.Now().Add(timeout), cancel
+	y.mu.Unlock()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// phaseErr returns why a phase failed: the cause of its context ending,
+// e.g. its timeout, else err
+func phaseErr(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); cause != nil {
+		return cause
+	}
+	return err
+}
+
+// candidate counts a candidate, yielding every yieldEvery of them, and
+// returns the error that abandoned the generation, if any
+func (y *yielder) candidate() error {
+	if y == nil {
+		return nil
+	}
+	if y.count.Add(1)%yieldEvery == 0 {
+		return y.yield()
+	}
+	return y.err()
+}
+
+// err returns the error that abandoned the generation (nil: none, or no
+// yielder)
+func (y *yielder) err() error {
+	if y == nil {
+		return nil
+	}
+	y.mu.Lock()
+	defer y.mu.Unlock()
+	return y.failed
+}
+
+// Yield lets a safe prime source yield within a generation; sources with
+// their own search loops call it every few candidates. It returns an error
+// once the generation is abandoned, by which time ctx is done too.
+func Yield(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if y, _ := ctx.Value(yieldKey{}).(*yielder); y != nil {
+		return y.yield()
+	}
+	return nil
+}
+
+// yieldingReader counts the candidates of a generation by its reads of the
+// random source. Once the generation is abandoned, reads fail, so the
+// workers searching for primes stop at their next candidate.
+type yieldingReader struct {
+	r io.Reader
+	y *yielder
+}
+
+func (r *yieldingReader) Read(p []byte) (int, error) {
+	if err := r.y.candidate(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errStopped = errors.New("stopped")
+
+func TestYieldingReader(t *testing.T) {
+	var calls atomic.Int32
+	_, y, cancel := withYield(context.Background(), func() error {
+		if calls.Add(1) > 1 {
+			return errStopped
+		}
+		return nil
+	})
+	defer cancel()
+	r := &yieldingReader{r: bytes.NewReader(make([]byte, 1024)), y: y}
+
+	buf := make([]byte, 1)
+	for i := 1; i <= 3*yieldEvery; i++ {
+		_, err := r.Read(buf)
+		switch {
+		case i < 2*yieldEvery && err != nil:
+			t.Fatalf("read %d = %v, want nil before the yield function fails", i, err)
+		case i >= 2*yieldEvery && !errors.Is(err, errStopped):
+			t.Fatalf("read %d = %v, want %v once the yield function failed", i, err, errStopped)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("yield function called %d times, want 2", got)
+	}
+}
+
+func TestGenerationYield(t *testing.T) {
+	gen := NewGenerator()
+
+	t.Run("abandoned mid-item", func(t *testing.T) {
+		start := time.Now()
+		_, err := gen.GeneratePreParamsYielding(512, 1024, func() error { return errStopped })
+		if !errors.Is(err, errStopped) {
+			t.Fatalf("GeneratePreParamsYielding() = %v, want %v", err, errStopped)
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Fatalf("abandoned generation returned after %s", elapsed)
+		}
+	})
+
+	t.Run("paused mid-item", func(t *testing.T) {
+		var paused atomic.Bool
+		paused.Store(true)
+		release := make(chan struct{})
+		parked := make(chan struct{}, 1)
+		done := make(chan error, 1)
+		go func() {
+			_, err := gen.GeneratePreParamsYielding(256, 512, func() error {
+				if paused.Load() {
+					select {
+					case parked <- struct{}{}:
+					default:
+					}
+					<-release
+				}
+				return nil
+			})
+			done <- err
+		}()
+
+		select {
+		case <-parked:
+		case <-time.After(10 * time.Second):
+			t.Fatal("generation never yielded")
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("generation finished while paused: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		paused.Store(false)
+		close(release)
+		if err := <-done; err != nil {
+			t.Fatalf("GeneratePreParamsYielding() after resuming = %v", err)
+		}
+	})
+}
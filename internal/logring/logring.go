@@ -0,0 +1,90 @@
+// Package logring keeps the most recent log lines in memory, so they can be
+// collected into diagnostic bundles without access to the host's log files
+package logring
+
+import (
+	"bytes"
+	"sync"
+)
+
+// DefaultLines is how many lines a ring keeps unless told otherwise
+const DefaultLines = 2000
+
+// maxLineBytes bounds a single kept line, so one huge write (e.g. a
+// goroutine dump) cannot push the ring's memory far beyond its line budget
+const maxLineBytes = 16 << 10
+
+// Ring is an io.Writer keeping the last lines written to it, for use with
+// log.SetOutput alongside the regular output
+type Ring struct {
+	mu      sync.Mutex
+	lines   []string
+	next    int  // Slot the next complete line goes to
+	full    bool // Every slot holds a line
+	partial []byte
+}
+
+// New creates a ring keeping the last n lines (DefaultLines if n <= 0)
+func New(n int) *Ring {
+	if n <= 0 {
+		n = DefaultLines
+	}
+	return &Ring{lines: make([]string, n)}
+}
+
+// Write appends p, splitting it into lines. It never fails.
+func (r *Ring) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rest := p
+	for len(rest) > 0 {
+		i := bytes.IndexByte(rest, '\n')
+		if i < 0 {
+			r.partial = appendBounded(r.partial, rest)
+			break
+		}
+		line := appendBounded(r.partial, rest[:i])
+		r.partial = r.partial[:0]
+		r.add(string(line))
+		rest = rest[i+1:]
+	}
+	return len(p), nil
+}
+
+// add stores a complete line
+// Caller must hold r.mu.
+func (r *Ring) add(line string) {
+	r.lines[r.next] = line
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// appendBounded appends p to b, dropping what exceeds maxLineBytes
+func appendBounded(b, p []byte) []byte {
+	if room := maxLineBytes - len(b); len(p) > room {
+		p = p[:max(room, 0)]
+	}
+	return append(b, p...)
+}
+
+// Lines returns up to n of the kept lines (all if n <= 0), oldest first
+func (r *Ring) Lines(n int) []string {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var lines []string
+	if r.full {
+		lines = append(lines, r.lines[r.next:]...)
+	}
+	lines = append(lines, r.lines[:r.next]...)
+	if n > 0 && len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
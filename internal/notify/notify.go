@@ -0,0 +1,79 @@
+// Package notify delivers operational events such as alarms to the log and,
+// if configured, to an HTTP webhook.
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Alarm states
+const (
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// webhookTimeout bounds a single webhook delivery
+const webhookTimeout = 10 * time.Second
+
+// Event is a notification about an alarm changing state
+type Event struct {
+	Time     time.Time `json:"time"`
+	Instance string    `json:"instance"`
+	Alarm    string    `json:"alarm"`
+	State    string    `json:"state"` // firing or resolved
+	Message  string    `json:"message"`
+}
+
+// Notifier logs events and posts them as JSON to a webhook
+type Notifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// New creates a notifier; an empty webhookURL only logs events
+func New(webhookURL string) *Notifier {
+	return &Notifier{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Notify logs the event and delivers it to the webhook in the background.
+// A nil Notifier only logs.
+func (n *Notifier) Notify(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	log.Printf("Alarm %s %s: %s", e.Alarm, e.State, e.Message)
+
+	if n == nil || n.webhookURL == "" {
+		return
+	}
+	go func() {
+		if err := n.post(e); err != nil {
+			log.Printf("Failed to deliver alarm %s to webhook: %v", e.Alarm, err)
+		}
+	}()
+}
+
+// post sends one event to the webhook
+func (n *Notifier) post(e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	resp, err := n.client.Post(n.webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
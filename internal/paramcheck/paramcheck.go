@@ -0,0 +1,136 @@
+// Package paramcheck verifies the mathematical consistency of pre-computed
+// parameters. The service runs it on every item it stores, receives or
+// serves, and clients can run it on every item they receive.
+package paramcheck
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// primalityRounds is the Miller-Rabin round count, as used by tss-lib
+const primalityRounds = 30
+
+// Params are the integers of one parameter set
+type Params struct {
+	PaillierN       *big.Int
+	PaillierP       *big.Int
+	PaillierQ       *big.Int
+	PaillierPhiN    *big.Int // Optional: checked if set
+	PaillierLambdaN *big.Int // Optional: checked if set
+	NTildei         *big.Int
+	H1i             *big.Int
+	H2i             *big.Int
+	Alpha           *big.Int
+	Beta            *big.Int
+	P               *big.Int // NTildei = (2P+1)(2Q+1)
+	Q               *big.Int
+}
+
+// Check runs the fast algebraic checks: every component is present, the
+// moduli, Paillier totients and h1/h2/alpha/beta relations match and h1/h2
+// are distinct units modulo NTildei
+func Check(p Params) error {
+	if p.PaillierN == nil || p.PaillierP == nil || p.PaillierQ == nil ||
+		p.NTildei == nil || p.H1i == nil || p.H2i == nil ||
+		p.Alpha == nil || p.Beta == nil || p.P == nil || p.Q == nil {
+		return fmt.Errorf("incomplete parameter set")
+	}
+
+	if new(big.Int).Mul(p.PaillierP, p.PaillierQ).Cmp(p.PaillierN) != 0 {
+		return fmt.Errorf("paillier modulus does not match its factors")
+	}
+
+	// PhiN = (P-1)(Q-1) and LambdaN = lcm(P-1, Q-1)
+	one := big.NewInt(1)
+	pm1 := new(big.Int).Sub(p.PaillierP, one)
+	qm1 := new(big.Int).Sub(p.PaillierQ, one)
+	phi := new(big.Int).Mul(pm1, qm1)
+	if p.PaillierPhiN != nil && p.PaillierPhiN.Cmp(phi) != 0 {
+		return fmt.Errorf("paillier phi(N) does not match its factors")
+	}
+	if p.PaillierLambdaN != nil {
+		lambda := new(big.Int).Div(phi, new(big.Int).GCD(nil, nil, pm1, qm1))
+		if p.PaillierLambdaN.Cmp(lambda) != 0 {
+			return fmt.Errorf("paillier lambda(N) does not match its factors")
+		}
+	}
+
+	// NTildei is the product of the safe primes 2P+1 and 2Q+1
+	safeP := new(big.Int).Add(new(big.Int).Lsh(p.P, 1), one)
+	safeQ := new(big.Int).Add(new(big.Int).Lsh(p.Q, 1), one)
+	if new(big.Int).Mul(safeP, safeQ).Cmp(p.NTildei) != 0 {
+		return fmt.Errorf("NTildei does not match its safe primes")
+	}
+
+	// H1 and H2 are distinct units of Z*_NTildei other than 1
+	for _, h := range []struct {
+		name string
+		v    *big.Int
+	}{{"h1", p.H1i}, {"h2", p.H2i}} {
+		if h.v.Cmp(one) <= 0 || h.v.Cmp(p.NTildei) >= 0 {
+			return fmt.Errorf("%s is out of range", h.name)
+		}
+		if new(big.Int).GCD(nil, nil, h.v, p.NTildei).Cmp(one) != 0 {
+			return fmt.Errorf("%s is not a unit modulo NTildei", h.name)
+		}
+	}
+	if p.H1i.Cmp(p.H2i) == 0 {
+		return fmt.Errorf("h1 and h2 are equal")
+	}
+
+	// Beta is the inverse of Alpha modulo P*Q, and H2 = H1^Alpha mod NTildei
+	pq := new(big.Int).Mul(p.P, p.Q)
+	if new(big.Int).Mod(new(big.Int).Mul(p.Alpha, p.Beta), pq).Cmp(one) != 0 {
+		return fmt.Errorf("beta is not the inverse of alpha")
+	}
+	if new(big.Int).Exp(p.H1i, p.Alpha, p.NTildei).Cmp(p.H2i) != 0 {
+		return fmt.Errorf("h2 does not match h1 and alpha")
+	}
+	return nil
+}
+
+// CheckPrimality runs Check and additionally tests that the Paillier factors,
+// P, Q and the safe primes 2P+1 and 2Q+1 are (probably) prime. It is much
+// slower than Check.
+func CheckPrimality(p Params) error {
+	if err := Check(p); err != nil {
+		return err
+	}
+
+	one := big.NewInt(1)
+	for _, c := range []struct {
+		name string
+		v    *big.Int
+	}{
+		{"paillier P", p.PaillierP},
+		{"paillier Q", p.PaillierQ},
+		{"P", p.P},
+		{"Q", p.Q},
+		{"safe prime 2P+1", new(big.Int).Add(new(big.Int).Lsh(p.P, 1), one)},
+		{"safe prime 2Q+1", new(big.Int).Add(new(big.Int).Lsh(p.Q, 1), one)},
+	} {
+		if !c.v.ProbablyPrime(primalityRounds) {
+			return fmt.Errorf("%s is not prime", c.name)
+		}
+	}
+	return nil
+}
+
+// CheckPrime tests that v is a (probably) prime of exactly bits bits and,
+// if safe is set, that (v-1)/2 is prime too
+func CheckPrime(v *big.Int, bits int, safe bool) error {
+	if v == nil {
+		return fmt.Errorf("missing prime")
+	}
+	if v.BitLen() != bits {
+		return fmt.Errorf("prime has %d bits, want %d", v.BitLen(), bits)
+	}
+	if !v.ProbablyPrime(primalityRounds) {
+		return fmt.Errorf("value is not prime")
+	}
+	if safe && !new(big.Int).Rsh(v, 1).ProbablyPrime(primalityRounds) {
+		return fmt.Errorf("value is not a safe prime")
+	}
+	return nil
+}
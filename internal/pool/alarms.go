@@ -0,0 +1,126 @@
+package pool
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/notify"
+)
+
+// alarmCheckInterval is how often consumption alarms are evaluated
+const alarmCheckInterval = 15 * time.Second
+
+// Consumption alarms
+const (
+	AlarmServedRate = "served_rate"     // More than AlarmServedLimit items served within AlarmServedWindow
+	AlarmEmptySoon  = "pool_empty_soon" // Pool will empty within AlarmEmptyWithin at the current rate
+)
+
+// Alarm is a firing consumption alarm
+type Alarm struct {
+	Name    string    `json:"name"`
+	Message string    `json:"message"`
+	Since   time.Time `json:"since"`
+}
+
+// alarmState tracks which alarms are firing
+type alarmState struct {
+	mu       sync.Mutex
+	active   map[string]*Alarm
+	notifier *notify.Notifier
+}
+
+// SetNotifier sets where alarm state changes are delivered; without one
+// they are only logged
+func (m *Manager) SetNotifier(n *notify.Notifier) {
+	m.alarms.mu.Lock()
+	defer m.alarms.mu.Unlock()
+	m.alarms.notifier = n
+}
+
+// Alarms returns the firing alarms sorted by name
+func (m *Manager) Alarms() []Alarm {
+	m.alarms.mu.Lock()
+	defer m.alarms.mu.Unlock()
+
+	result := make([]Alarm, 0, len(m.alarms.active))
+	for _, a := range m.alarms.active {
+		result = append(result, *a)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// alarmLoop evaluates the alarms until the manager stops
+func (m *Manager) alarmLoop() {
+	ticker := time.NewTicker(alarmCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.checkAlarms()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// checkAlarms raises or clears each configured alarm
+func (m *Manager) checkAlarms() {
+	m.mu.RLock()
+	limit := m.config.AlarmServedLimit
+	window := m.config.AlarmServedWindow
+	within := m.config.AlarmEmptyWithin
+	m.mu.RUnlock()
+
+	if limit > 0 {
+		served := m.served.count(window)
+		m.setAlarm(AlarmServedRate, served > limit,
+			fmt.Sprintf("%d items served in the last %s (limit: %d)", served, window, limit))
+	} else {
+		m.setAlarm(AlarmServedRate, false, "disabled")
+	}
+
+	if within > 0 {
+		r := m.Pressure()
+		drain := -r.NetRatePerMin
+		firing := drain > 0 && float64(r.Actual)/drain < within.Minutes()
+		message := "pool is not draining"
+		if drain > 0 {
+			eta := time.Duration(float64(r.Actual) / drain * float64(time.Minute)).Round(time.Second)
+			message = fmt.Sprintf("pool of %d items will empty in %s at %.1f items/min net consumption (threshold: %s)", r.Actual, eta, drain, within)
+		}
+		m.setAlarm(AlarmEmptySoon, firing, message)
+	} else {
+		m.setAlarm(AlarmEmptySoon, false, "disabled")
+	}
+}
+
+// setAlarm records an alarm's state and notifies on changes
+func (m *Manager) setAlarm(name string, firing bool, message string) {
+	m.alarms.mu.Lock()
+	defer m.alarms.mu.Unlock()
+
+	active, wasFiring := m.alarms.active[name]
+	switch {
+	case firing && wasFiring:
+		active.Message = message
+		return
+	case firing:
+		if m.alarms.active == nil {
+			m.alarms.active = make(map[string]*Alarm)
+		}
+		m.alarms.active[name] = &Alarm{Name: name, Message: message, Since: time.Now()}
+		m.changed()
+		m.errors.Record(errjournal.SeverityWarning, "alarm", fmt.Errorf("%s: %s", name, message), nil)
+		m.alarms.notifier.Notify(notify.Event{Instance: m.instanceID, Alarm: name, State: notify.StateFiring, Message: message})
+	case wasFiring:
+		delete(m.alarms.active, name)
+		m.changed()
+		m.alarms.notifier.Notify(notify.Event{Instance: m.instanceID, Alarm: name, State: notify.StateResolved, Message: message})
+	}
+}
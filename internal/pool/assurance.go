@@ -0,0 +1,169 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/paramcheck"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// Assurance levels of pool items, lowest first
+const (
+	AssuranceStandard = "standard" // Passed the generator's own checks (fast generation)
+	AssuranceAudited  = "audited"  // Also passed the full primality validation of every prime
+)
+
+// AssuranceLevels lists the assurance levels, lowest first
+var AssuranceLevels = []string{AssuranceStandard, AssuranceAudited}
+
+// auditCheckInterval is how often the pool is topped up with audited items
+const auditCheckInterval = 10 * time.Second
+
+// ValidAssurance reports whether level names an assurance level ("" stands
+// for standard)
+func ValidAssurance(level string) bool {
+	return level == "" || level == AssuranceStandard || level == AssuranceAudited
+}
+
+// AssuranceOf returns the assurance level of an item
+func AssuranceOf(item *PreParamsData) string {
+	if item.Assurance == "" {
+		return AssuranceStandard
+	}
+	return item.Assurance
+}
+
+// meetsAssurance reports whether item is at least at level ("": any item)
+func meetsAssurance(item *PreParamsData, level string) bool {
+	return level != AssuranceAudited || AssuranceOf(item) == AssuranceAudited
+}
+
+// auditItem runs the full primality validation on item and raises it to
+// the audited level
+func auditItem(item *PreParamsData) error {
+	if err := paramcheck.CheckPrimality(itemParams(item)); err != nil {
+		return fmt.Errorf("item failed full validation: %w", err)
+	}
+	item.Assurance = AssuranceAudited
+	return nil
+}
+
+// assure audits an item generated for a request demanding the audited level
+func (m *Manager) assure(ctx context.Context, item *PreParamsData, req Request) error {
+	if meetsAssurance(item, req.MinAssurance) {
+		return nil
+	}
+	start := time.Now()
+	if err := auditItem(item); err != nil {
+		return fmt.Errorf("parameter set %d generated on demand: %w", item.Provenance.Seq, err)
+	}
+	trace.Logf(ctx, "Audited parameter set %d generated on demand (took: %s)", item.Provenance.Seq, time.Since(start))
+	return nil
+}
+
+// assuranceLocked counts the items in the pool by assurance level
+// Caller must hold m.mu.
+func (m *Manager) assuranceLocked() map[string]int {
+	counts := make(map[string]int, len(AssuranceLevels))
+	for _, level := range AssuranceLevels {
+		counts[level] = 0
+	}
+	for _, item := range m.preParams {
+		counts[AssuranceOf(item)]++
+	}
+	return counts
+}
+
+// auditLoop keeps MinAudited pool items audited until the manager stops
+func (m *Manager) auditLoop() {
+	ticker := time.NewTicker(auditCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.auditPool()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// auditPool audits standard pool items, oldest first, until MinAudited are
+// audited. Items failing validation are quarantined. Validation runs
+// without holding m.mu, so items served meanwhile are skipped.
+func (m *Manager) auditPool() {
+	m.mu.RLock()
+	target := m.config.MinAudited
+	missing := target - m.assuranceLocked()[AssuranceAudited]
+	var candidates []*PreParamsData
+	for _, item := range m.preParams {
+		if len(candidates) >= missing {
+			break
+		}
+		if AssuranceOf(item) == AssuranceStandard {
+			candidates = append(candidates, item)
+		}
+	}
+	m.mu.RUnlock()
+	if len(candidates) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	audited, failed := 0, 0
+	for _, item := range candidates {
+		if m.stopping() {
+			break
+		}
+		err := paramcheck.CheckPrimality(itemParams(item))
+
+		m.mu.Lock()
+		idx := m.indexLocked(item)
+		switch {
+		case idx < 0:
+		case err == nil:
+			item.Assurance = AssuranceAudited
+			audited++
+			m.changed()
+		default:
+			m.preParams = append(m.preParams[:idx], m.preParams[idx+1:]...)
+			failed++
+			m.changed()
+		}
+		m.mu.Unlock()
+
+		if idx >= 0 && err != nil {
+			m.quarantine(ctx, item, FailureInvalid, fmt.Errorf("item failed full validation: %w", err), "audit")
+		}
+	}
+	if audited == 0 && failed == 0 {
+		return
+	}
+
+	log.Printf("Audited pool items (audited: %d, failed: %d, target: %d)", audited, failed, target)
+	if audited > 0 {
+		m.recordAudit(audit.Entry{
+			Time:   time.Now(),
+			Event:  "items_audited",
+			Count:  audited,
+			Detail: fmt.Sprintf("failed=%d min_audited=%d", failed, target),
+		})
+	}
+	m.saveToDisk(ctx)
+}
+
+// indexLocked returns the index of item in the pool, or -1 once it left
+// Caller must hold m.mu.
+func (m *Manager) indexLocked(item *PreParamsData) int {
+	for i, p := range m.preParams {
+		if p == item {
+			return i
+		}
+	}
+	return -1
+}
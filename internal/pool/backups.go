@@ -0,0 +1,70 @@
+package pool
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/shred"
+)
+
+// backupCheckInterval is how often expired backups are looked for
+const backupCheckInterval = time.Hour
+
+// backupTimeFormat is the timestamp suffix of archived legacy pool files
+const backupTimeFormat = "20060102-150405"
+
+// backupRetentionLoop shreds expired backups until the manager stops
+func (m *Manager) backupRetentionLoop() {
+	ticker := time.NewTicker(backupCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.expireBackups()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// expireBackups overwrites and removes archived legacy pool files older
+// than BackupRetention; their age comes from the archive timestamp, or the
+// modification time if the name carries none
+func (m *Manager) expireBackups() {
+	retention := m.config.BackupRetention
+	if retention <= 0 || m.poolFilePath == "" {
+		return
+	}
+
+	prefix := filepath.Join(m.config.PoolDir, legacyPoolFile) + ".migrated-"
+	paths, _ := filepath.Glob(prefix + "*")
+	now := time.Now()
+	for _, path := range paths {
+		archived, err := time.ParseInLocation(backupTimeFormat, strings.TrimPrefix(path, prefix), time.Local)
+		if err != nil {
+			info, statErr := os.Stat(path)
+			if statErr != nil {
+				continue
+			}
+			archived = info.ModTime()
+		}
+		if now.Sub(archived) <= retention {
+			continue
+		}
+
+		if err := shred.File(path); err != nil {
+			if os.IsNotExist(err) {
+				continue // Expired by another pool sharing the directory
+			}
+			log.Printf("Failed to remove expired backup %s: %v", path, err)
+			m.errors.Record(errjournal.SeverityWarning, "persistence", err, map[string]string{"op": "expire_backup", "file": path})
+			continue
+		}
+		log.Printf("Removed expired backup %s (archived: %s, retention: %s)", path, archived.Format(time.RFC3339), retention)
+	}
+}
@@ -0,0 +1,169 @@
+package pool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+)
+
+// refillCheckpoint records a fill interrupted by shutdown, so the next start
+// resumes it without waiting for the refill threshold or the ticker
+type refillCheckpoint struct {
+	SavedAt  time.Time `json:"saved_at"`
+	Mode     string    `json:"mode"`   // refill, emergency or fill
+	Reason   string    `json:"reason"` // Why the fill was running
+	Target   int       `json:"target"`
+	PoolSize int       `json:"pool_size"` // Items in the pool at shutdown
+	Needed   int       `json:"needed"`    // Items still missing at shutdown
+}
+
+// fillReasons describe why fills of each mode run
+var fillReasons = map[string]string{
+	"refill":    "pool below its refill threshold or minimum size",
+	"emergency": "a request found too few items in the pool",
+	"fill":      "admin fill",
+}
+
+// fillTracker tracks running fills, so Stop can checkpoint the interrupted ones
+type fillTracker struct {
+	wg          sync.WaitGroup
+	mu          sync.Mutex
+	interrupted []refillCheckpoint
+
+	path    string            // "" with memory storage
+	resumed *refillCheckpoint // Loaded at startup, resumed by Start
+}
+
+// checkpointPath returns the refill checkpoint stored next to a pool file
+func checkpointPath(poolFile string) string {
+	return strings.TrimSuffix(poolFile, ".json") + ".refill.json"
+}
+
+// beginFill registers a fill, returning false once the manager is stopping
+func (m *Manager) beginFill() bool {
+	m.generatingMu.Lock()
+	defer m.generatingMu.Unlock()
+	if m.stopping() {
+		return false
+	}
+	m.fills.wg.Add(1)
+	return true
+}
+
+// endFill unregisters a fill, recording it as interrupted if the manager
+// stopped before it reached target
+func (m *Manager) endFill(mode string, target int) {
+	defer m.fills.wg.Done()
+	if !m.stopping() {
+		return
+	}
+	m.fills.mu.Lock()
+	defer m.fills.mu.Unlock()
+	m.fills.interrupted = append(m.fills.interrupted, refillCheckpoint{Mode: mode, Reason: fillReasons[mode], Target: target})
+}
+
+// writeCheckpoint records the largest interrupted fill still short of its
+// target once the running fills have returned. Called by Stop.
+func (m *Manager) writeCheckpoint() {
+	m.fills.wg.Wait()
+	if m.fills.path == "" {
+		return
+	}
+
+	m.mu.RLock()
+	size := m.sizeLocked()
+	m.mu.RUnlock()
+
+	m.fills.mu.Lock()
+	var cp *refillCheckpoint
+	for i, c := range m.fills.interrupted {
+		if c.Target > size && (cp == nil || c.Target > cp.Target) {
+			cp = &m.fills.interrupted[i]
+		}
+	}
+	m.fills.mu.Unlock()
+	if cp == nil {
+		return
+	}
+	cp.SavedAt, cp.PoolSize, cp.Needed = time.Now(), size, cp.Target-size
+
+	data, err := json.Marshal(cp)
+	if err == nil {
+		err = os.WriteFile(m.fills.path, data, 0600)
+	}
+	if err != nil {
+		log.Printf("Failed to record interrupted pool %s: %v", cp.Mode, err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "checkpoint", "file": m.fills.path})
+		return
+	}
+	log.Printf("Recorded interrupted pool %s for the next start (needed: %d, target: %d)", cp.Mode, cp.Needed, cp.Target)
+}
+
+// loadCheckpoint reads and removes the checkpoint of the previous run, if any
+func (m *Manager) loadCheckpoint() error {
+	if m.fills.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(m.fills.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read refill checkpoint: %w", err)
+	}
+	// A checkpoint is resumed once; a resume interrupted again writes a new one
+	if err := os.Remove(m.fills.path); err != nil {
+		return fmt.Errorf("failed to remove refill checkpoint: %w", err)
+	}
+	var cp refillCheckpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("failed to parse refill checkpoint %s: %w", m.fills.path, err)
+	}
+	m.fills.resumed = &cp
+	return nil
+}
+
+// resumeCheckpoint restarts the fill interrupted by the previous shutdown
+// once the startup delay ends. Fills above MinPoolSize resume as admin fills
+// (capped at MaxPoolSize), others as refills of their original kind.
+func (m *Manager) resumeCheckpoint() {
+	cp := m.fills.resumed
+	if cp == nil {
+		return
+	}
+	log.Printf("Resuming pool %s interrupted at shutdown (%s; needed: %d, target: %d, interrupted: %s)",
+		cp.Mode, cp.Reason, cp.Needed, cp.Target, cp.SavedAt.Format(time.RFC3339))
+
+	m.mu.RLock()
+	minSize, maxSize := m.config.MinPoolSize, m.config.MaxPoolSize
+	m.mu.RUnlock()
+
+	switch {
+	case cp.Target > minSize:
+		resume := func() {
+			if m.stopping() {
+				return
+			}
+			if _, err := m.FillPool(min(cp.Target, maxSize), 0); err != nil {
+				// A refill got there first; it tops the pool up to MinPoolSize
+				log.Printf("Could not resume pool fill: %v", err)
+			}
+		}
+		if wait := m.config.StartupDelay - time.Since(m.startTime); wait > 0 {
+			time.AfterFunc(wait, resume)
+		} else {
+			go resume()
+		}
+	case cp.Mode == "emergency":
+		go m.emergencyRefill()
+	default:
+		go m.refillPool()
+	}
+}
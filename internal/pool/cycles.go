@@ -0,0 +1,119 @@
+package pool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+)
+
+// RefillCycle summarizes one refill, emergency refill or fill
+type RefillCycle struct {
+	Started     time.Time     `json:"started"`
+	Duration    time.Duration `json:"duration"`
+	Mode        string        `json:"mode"`   // refill, emergency or fill
+	Reason      string        `json:"reason"` // Why it ran
+	Target      int           `json:"target"`
+	PoolSize    int           `json:"pool_size"` // Items in the pool when it started
+	Needed      int           `json:"needed"`    // Items it set out to generate
+	Generated   int           `json:"generated"` // Items it added to the pool
+	Failures    int           `json:"failures"`
+	Workers     int           `json:"workers"`
+	Interrupted bool          `json:"interrupted,omitempty"` // Cut short by shutdown
+}
+
+// cycleLog keeps the latest RefillHistory cycles, persisted next to the
+// pool file
+type cycleLog struct {
+	mu     sync.Mutex
+	path   string        // "" with memory storage
+	cycles []RefillCycle // Oldest first
+}
+
+// cyclesPath returns the refill cycle log stored next to a pool file
+func cyclesPath(poolFile string) string {
+	return strings.TrimSuffix(poolFile, ".json") + ".cycles.json"
+}
+
+// load reads the cycle log, if any
+func (l *cycleLog) load() error {
+	if l.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := json.Unmarshal(data, &l.cycles); err != nil {
+		return fmt.Errorf("failed to parse refill cycles %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// add appends a cycle, keeping the latest keep, and saves the log
+// atomically (temp file + rename)
+func (l *cycleLog) add(c RefillCycle, keep int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cycles = append(l.cycles, c)
+	if n := len(l.cycles) - keep; n > 0 {
+		l.cycles = append([]RefillCycle(nil), l.cycles[n:]...)
+	}
+	if l.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(l.cycles)
+	if err != nil {
+		return fmt.Errorf("failed to marshal refill cycles: %w", err)
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write refill cycles: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to replace refill cycles: %w", err)
+	}
+	return nil
+}
+
+// recordCycle adds a finished cycle to the log
+func (m *Manager) recordCycle(c RefillCycle) {
+	m.mu.RLock()
+	keep := m.config.RefillHistory
+	m.mu.RUnlock()
+
+	if err := m.cycles.add(c, keep); err != nil {
+		log.Printf("Failed to save refill cycles: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "cycles", "file": m.cycles.path})
+	}
+}
+
+// RefillCycles returns up to limit of the latest refill cycles, newest
+// first (all kept if limit is 0)
+func (m *Manager) RefillCycles(limit int) []RefillCycle {
+	m.cycles.mu.Lock()
+	defer m.cycles.mu.Unlock()
+
+	n := len(m.cycles.cycles)
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	result := make([]RefillCycle, 0, limit)
+	for i := n - 1; i >= n-limit; i-- {
+		result = append(result, m.cycles.cycles[i])
+	}
+	return result
+}
@@ -0,0 +1,115 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/generator"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// Policies for items generated by a version in DeniedVersions
+const (
+	DeniedRefuse = "refuse" // Never serve them; discard them from the pool
+	DeniedWarn   = "warn"   // Serve them, journaling a warning
+)
+
+// itemVersion returns the generator version recorded in an item's
+// provenance ("unknown" for items from before versions were recorded)
+func itemVersion(item *PreParamsData) string {
+	if item.Provenance.Version == "" {
+		return generator.UnknownVersion
+	}
+	return item.Provenance.Version
+}
+
+// deniedLocked reports whether item was generated by a denied version
+// Caller must hold m.mu (read or write).
+func (m *Manager) deniedLocked(item *PreParamsData) bool {
+	return len(m.config.DeniedVersions) > 0 && slices.Contains(m.config.DeniedVersions, itemVersion(item))
+}
+
+// refuseDeniedLocked reports whether denied items are discarded rather than
+// served
+// Caller must hold m.mu (read or write).
+func (m *Manager) refuseDeniedLocked() bool {
+	return m.config.DeniedVersionPolicy != DeniedWarn
+}
+
+// noteDeniedLocked counts, logs and journals denied items that were
+// discarded or served
+// Caller must hold m.mu.
+func (m *Manager) noteDeniedLocked(ctx context.Context, items []*PreParamsData, discarded bool) {
+	if len(items) == 0 {
+		return
+	}
+	versions := make(map[string]bool)
+	for _, item := range items {
+		versions[itemVersion(item)] = true
+	}
+	list := make([]string, 0, len(versions))
+	for v := range versions {
+		list = append(list, v)
+	}
+	sort.Strings(list)
+	detail := map[string]string{"versions": strings.Join(list, ","), "request_id": trace.ID(ctx)}
+
+	if discarded {
+		m.deniedDropped += int64(len(items))
+		trace.Logf(ctx, "Discarded %d parameters generated by denied versions %v", len(items), list)
+		m.errors.Record(errjournal.SeverityWarning, "pool", fmt.Errorf("discarded %d parameters generated by denied versions", len(items)), detail)
+		go m.refillPool()
+		return
+	}
+	m.deniedServed += int64(len(items))
+	trace.Logf(ctx, "Warning: serving %d parameters generated by denied versions %v", len(items), list)
+	m.errors.Record(errjournal.SeverityWarning, "pool", fmt.Errorf("served %d parameters generated by denied versions", len(items)), detail)
+}
+
+// purgeDenied discards the pool items generated by denied versions under
+// the refuse policy, e.g. right after a version was added to the deny-list,
+// and starts a refill to replace them
+func (m *Manager) purgeDenied() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.config.DeniedVersions) == 0 || !m.refuseDeniedLocked() {
+		return
+	}
+
+	kept := m.preParams[:0]
+	var removed []*PreParamsData
+	for _, item := range m.preParams {
+		if m.deniedLocked(item) {
+			removed = append(removed, item)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+	clear(m.preParams[len(kept):])
+	m.preParams = kept
+	m.changed()
+
+	ctx := context.Background()
+	m.writeLedgerLocked(ctx, removed, false)
+	m.noteDeniedLocked(ctx, removed, true)
+	if m.config.AutoSave {
+		go m.saveToDisk(ctx)
+	}
+}
+
+// itemVersionsLocked counts the pool items by generator version
+// Caller must hold m.mu (read or write).
+func (m *Manager) itemVersionsLocked() map[string]int {
+	counts := make(map[string]int)
+	for _, item := range m.preParams {
+		counts[itemVersion(item)]++
+	}
+	return counts
+}
@@ -0,0 +1,57 @@
+package pool
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/TEENet-io/prime-service/internal/atrest"
+)
+
+// Labels binding encrypted contents to the kind of file holding them
+const (
+	sealPool       = "pool"
+	sealJournal    = "request-journal"
+	sealQuarantine = "quarantine"
+	sealSnapshot   = "snapshot"
+)
+
+// loadCipher returns the cipher of the configured at-rest encryption key
+// (nil: no key, or memory storage, which writes nothing)
+func loadCipher(cfg *SimpleConfig) (*atrest.Cipher, error) {
+	if cfg.Storage == StorageMemory {
+		return nil, nil
+	}
+	key, err := atrest.LoadKey(cfg.EncryptionKey, cfg.EncryptionKeyFile, cfg.EncryptionKeyCommand)
+	if err != nil || key == nil {
+		return nil, err
+	}
+	c, err := atrest.New(key)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Encrypting pool files and journals at rest (key ID: %s)", c.KeyID())
+	if cfg.AllowPlaintextMigration {
+		c.AllowPlaintext()
+		log.Printf("Warning: accepting plaintext pool files, journals and snapshots to encrypt them; unset allow_plaintext_migration once they are rewritten")
+	}
+	return c, nil
+}
+
+// openFile decrypts the contents of a file read from disk, reporting whether
+// they were plaintext that encryption now requires to be rewritten
+func (m *Manager) openFile(data []byte, label, path string) ([]byte, bool, error) {
+	plaintext, err := m.cipher.Open(data, label)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+	return plaintext, m.cipher != nil && !atrest.Encrypted(data), nil
+}
+
+// EncryptionKeyID returns the fingerprint of the at-rest encryption key
+// ("": files are not encrypted)
+func (m *Manager) EncryptionKeyID() string {
+	if m.cipher == nil {
+		return ""
+	}
+	return m.cipher.KeyID()
+}
@@ -0,0 +1,53 @@
+package pool
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+)
+
+// ErrGenerating is returned by FillPool while a refill or fill is running
+var ErrGenerating = errors.New("generation already in progress")
+
+// ErrFillTarget is returned by FillPool for a target above MaxPoolSize
+var ErrFillTarget = errors.New("fill target exceeds max pool size")
+
+// FillResult describes a started fill
+type FillResult struct {
+	Target   int // Pool size to reach
+	Workers  int // Generation workers
+	PoolSize int // Items in the pool when the fill started
+}
+
+// FillPool starts generating until the pool holds target items (MaxPoolSize
+// if zero) on the given number of workers (the refill concurrency if zero,
+// at most one per CPU), regardless of refill threshold and startup delay.
+// Generation runs in the background.
+func (m *Manager) FillPool(target, workers int) (FillResult, error) {
+	m.mu.RLock()
+	maxSize := m.config.MaxPoolSize
+	size := m.sizeLocked()
+	m.mu.RUnlock()
+
+	if target == 0 {
+		target = maxSize
+	}
+	if target > maxSize {
+		return FillResult{}, fmt.Errorf("%w: %d > %d", ErrFillTarget, target, maxSize)
+	}
+	if workers == 0 {
+		workers = m.effectiveConcurrency()
+	}
+	workers = min(workers, runtime.NumCPU())
+
+	if !m.beginGeneration() {
+		return FillResult{}, ErrGenerating
+	}
+	log.Printf("Pool fill requested (target: %d, workers: %d)", target, workers)
+	go func() {
+		defer m.endGeneration()
+		m.fill(target, workers, m.config.GenerationThrottle, "fill")
+	}()
+	return FillResult{Target: target, Workers: workers, PoolSize: size}, nil
+}
@@ -0,0 +1,194 @@
+package pool
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"time"
+)
+
+// defaultForecastLookback is the usage history consumption rates are taken from
+const defaultForecastLookback = 7 * 24 * time.Hour
+
+// ForecastRequest describes the forecast wanted
+type ForecastRequest struct {
+	Lookback   time.Duration // History to take rates from (default: 7 days)
+	EventItems int           // Items a planned event will take (0: runway only)
+	EventAt    time.Time     // When the event starts (zero: now)
+}
+
+// Forecast is the pool runway at recent consumption and, for a planned
+// event, the generation it needs
+type Forecast struct {
+	InstanceID  string `json:"instance_id"`
+	PoolSize    int    `json:"pool_size"`
+	MinPoolSize int    `json:"min_pool_size"`
+	MaxPoolSize int    `json:"max_pool_size"`
+
+	HistoryHours      float64 `json:"history_hours"`        // History the rates are based on, at most the lookback
+	ServedPerDay      float64 `json:"served_per_day"`       // Average consumption
+	PeakServedPerHour int64   `json:"peak_served_per_hour"` // Busiest hour
+	GeneratedPerDay   float64 `json:"generated_per_day"`    // Average supply
+
+	Workers             int     `json:"workers"`               // Housekeeping generation concurrency
+	AverageGenerationMs int64   `json:"average_generation_ms"` // 0 if nothing was generated yet
+	CapacityPerDay      float64 `json:"capacity_per_day"`      // Generation capacity (0 if unknown)
+
+	// Days the pool lasts at ServedPerDay without generation, and with
+	// generation at full capacity (-1: never runs out)
+	RunwayDays          float64 `json:"runway_days"`
+	SustainedRunwayDays float64 `json:"sustained_runway_days"`
+
+	Event *EventForecast `json:"event,omitempty"`
+}
+
+// EventForecast is the generation backlog of a planned event
+type EventForecast struct {
+	Items int       `json:"items"`
+	At    time.Time `json:"at"`
+
+	// Items expected to be served at the recent rate while the backlog is
+	// generated (before that, refills cover regular consumption)
+	BaselineServed float64 `json:"baseline_served"`
+
+	// Items to generate beyond the current pool so it holds the event's
+	// items on top of the baseline
+	Backlog int `json:"backlog"`
+
+	// Time to generate the backlog at the configured concurrency (-1 if
+	// unknown), and the latest start to finish before the event
+	GenerationHours float64   `json:"generation_hours"`
+	StartBy         time.Time `json:"start_by,omitempty"`
+
+	Feasible        bool     `json:"feasible"`       // The backlog can be generated in time at the configured concurrency
+	WorkersNeeded   int      `json:"workers_needed"` // Concurrency needed to be ready in time (0 if unknown)
+	Recommendations []string `json:"recommendations,omitempty"`
+}
+
+// Forecast estimates the pool runway from the usage history and the
+// generation backlog of a planned event
+func (m *Manager) Forecast(req ForecastRequest) Forecast {
+	now := time.Now()
+	lookback := req.Lookback
+	if lookback <= 0 {
+		lookback = defaultForecastLookback
+	}
+
+	// Generation timing of this process, else of the pooled items (e.g.
+	// right after a restart with a full pool)
+	avg := m.generator.GetAverageGenerationTime()
+	m.mu.RLock()
+	poolSize := m.sizeLocked()
+	size := len(m.preParams)
+	if avg <= 0 && size > 0 {
+		var total time.Duration
+		for _, item := range m.preParams {
+			total += item.GenerationDuration
+		}
+		avg = total / time.Duration(size)
+	}
+	m.mu.RUnlock()
+
+	f := Forecast{
+		InstanceID:          m.instanceID,
+		PoolSize:            poolSize,
+		MinPoolSize:         m.config.MinPoolSize,
+		MaxPoolSize:         m.config.MaxPoolSize,
+		Workers:             m.effectiveConcurrency(),
+		RunwayDays:          -1,
+		SustainedRunwayDays: -1,
+	}
+
+	// Rates over the history there is, at most the lookback and at least an hour
+	buckets := m.UsageHistory(now.Add(-lookback))
+	var served, generated int64
+	for _, b := range buckets {
+		served += b.Served
+		generated += b.Generated
+		f.PeakServedPerHour = max(f.PeakServedPerHour, b.Served)
+	}
+	span := time.Hour
+	if len(buckets) > 0 {
+		span = max(now.Sub(buckets[0].Hour), time.Hour)
+	}
+	days := span.Hours() / 24
+	f.HistoryHours = math.Round(span.Hours()*10) / 10
+	f.ServedPerDay = float64(served) / days
+	f.GeneratedPerDay = float64(generated) / days
+
+	f.AverageGenerationMs = avg.Milliseconds()
+	if avg > 0 {
+		f.CapacityPerDay = float64(f.Workers) * float64(24*time.Hour) / float64(avg)
+	}
+
+	if f.ServedPerDay > 0 {
+		f.RunwayDays = float64(size) / f.ServedPerDay
+		if drain := f.ServedPerDay - f.CapacityPerDay; drain > 0 {
+			f.SustainedRunwayDays = float64(size) / drain
+		}
+	}
+
+	if req.EventItems > 0 {
+		f.Event = m.forecastEvent(&f, req, now, avg)
+	}
+	return f
+}
+
+// forecastEvent computes the backlog of a planned event
+func (m *Manager) forecastEvent(f *Forecast, req ForecastRequest, now time.Time, avg time.Duration) *EventForecast {
+	at := req.EventAt
+	if at.Before(now) {
+		at = now
+	}
+	e := &EventForecast{
+		Items:           req.EventItems,
+		At:              at,
+		GenerationHours: -1,
+	}
+	e.Backlog = max(req.EventItems-f.PoolSize, 0)
+	if e.Backlog > 0 && avg > 0 {
+		// Items served while filling must be generated too
+		fill := time.Duration(e.Backlog) * avg / time.Duration(f.Workers)
+		e.BaselineServed = math.Round(f.ServedPerDay*fill.Hours()/24*10) / 10
+		e.Backlog += int(math.Ceil(e.BaselineServed))
+	}
+	need := f.PoolSize + e.Backlog
+
+	if need > f.MaxPoolSize {
+		e.Recommendations = append(e.Recommendations, fmt.Sprintf(
+			"raise pool.max_pool_size from %d to at least %d so the pool can hold the event's items", f.MaxPoolSize, need))
+	}
+
+	switch {
+	case e.Backlog == 0:
+		e.GenerationHours = 0
+		e.Feasible = true
+		return e
+	case avg <= 0:
+		e.Recommendations = append(e.Recommendations, "no generation timing yet, so capacity is unknown; generate a few items first")
+		return e
+	}
+
+	work := time.Duration(e.Backlog) * avg
+	generation := work / time.Duration(f.Workers)
+	e.GenerationHours = math.Round(generation.Hours()*100) / 100
+	e.StartBy = at.Add(-generation)
+	e.Feasible = !e.StartBy.Before(now)
+	if until := at.Sub(now); until > 0 {
+		e.WorkersNeeded = int(math.Ceil(float64(work) / float64(until)))
+	}
+
+	// A fill uses at most one worker per CPU
+	switch {
+	case e.Feasible:
+		e.Recommendations = append(e.Recommendations, fmt.Sprintf(
+			"start filling by %s: primectl fill -target %d", e.StartBy.UTC().Format(time.RFC3339), need))
+	case e.WorkersNeeded > 0 && e.WorkersNeeded <= runtime.NumCPU():
+		e.Recommendations = append(e.Recommendations, fmt.Sprintf(
+			"start filling now on more workers: primectl fill -target %d -concurrency %d", need, e.WorkersNeeded))
+	default:
+		e.Recommendations = append(e.Recommendations, fmt.Sprintf(
+			"this instance cannot generate %d items in time; start filling now (primectl fill -target %d) and add generating replicas", e.Backlog, need))
+	}
+	return e
+}
@@ -0,0 +1,215 @@
+package pool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+)
+
+// ErrFrozen is returned by GetPreParams while the pool is frozen after an anomaly
+var ErrFrozen = errors.New("pool is frozen after an anomaly")
+
+// Anomalies that freeze the pool when FreezeOnAnomaly is set
+const (
+	AnomalyDuplicate  = "duplicate_params"    // Generated or received parameters repeat ones in the pool
+	AnomalyValidation = "validation_failures" // FreezeFailureLimit items failed verification within FreezeFailureWindow
+	AnomalyAudit      = "audit_failure"       // An audit entry could not be written
+)
+
+// AlarmFrozen is raised while the pool is frozen
+const AlarmFrozen = "pool_frozen"
+
+// FreezeStatus describes whether and why the pool is frozen
+type FreezeStatus struct {
+	Frozen  bool      `json:"frozen"`
+	Anomaly string    `json:"anomaly,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Since   time.Time `json:"since,omitempty"`
+}
+
+// freezeState holds the freeze settings and state. It has its own lock
+// because anomalies are reported from code that holds m.mu.
+type freezeState struct {
+	mu     sync.Mutex
+	path   string // Persisted so a restart does not lift the freeze (empty: memory storage)
+	status FreezeStatus
+
+	enabled bool
+	limit   int
+	window  time.Duration
+
+	// Recent verification failures, for the validation failure rate
+	failures eventWindow
+}
+
+// configureFreeze applies the freeze settings of cfg
+func (m *Manager) configureFreeze(cfg *SimpleConfig) {
+	f := &m.freeze
+	f.mu.Lock()
+	f.enabled = cfg.FreezeOnAnomaly
+	f.limit = cfg.FreezeFailureLimit
+	f.window = cfg.FreezeFailureWindow
+	f.mu.Unlock()
+	f.failures.setHorizon(cfg.FreezeFailureWindow)
+}
+
+// loadFreeze restores a freeze persisted by a previous run
+func (m *Manager) loadFreeze() {
+	f := &m.freeze
+	if f.path == "" {
+		return
+	}
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &f.status)
+	}
+	if err != nil {
+		// Fail closed: an unreadable freeze file still means someone froze the pool
+		log.Printf("Failed to read freeze state, staying frozen: %v", err)
+		f.status = FreezeStatus{Frozen: true, Anomaly: "unknown", Reason: fmt.Sprintf("unreadable freeze state: %v", err), Since: time.Now()}
+	}
+	if f.status.Frozen {
+		m.frozen.Store(true)
+		log.Printf("Warning: pool is frozen since %s (%s: %s), serving stays off until unfrozen",
+			f.status.Since.Format(time.RFC3339), f.status.Anomaly, f.status.Reason)
+	}
+}
+
+// ReportAnomaly journals an anomaly and, with FreezeOnAnomaly set, freezes
+// the pool. Safe to call with or without m.mu held.
+func (m *Manager) ReportAnomaly(anomaly string, err error) {
+	log.Printf("Anomaly detected (%s): %v", anomaly, err)
+	m.errors.Record(errjournal.SeverityError, "anomaly", err, map[string]string{"anomaly": anomaly})
+
+	f := &m.freeze
+	f.mu.Lock()
+	if !f.enabled || f.status.Frozen {
+		f.mu.Unlock()
+		return
+	}
+	f.status = FreezeStatus{Frozen: true, Anomaly: anomaly, Reason: err.Error(), Since: time.Now()}
+	m.frozen.Store(true)
+	m.saveFreezeLocked()
+	f.mu.Unlock()
+	m.changed()
+
+	log.Printf("Pool frozen after %s anomaly, refusing requests until unfrozen (generation continues)", anomaly)
+	m.setAlarm(AlarmFrozen, true, fmt.Sprintf("%s: %v", anomaly, err))
+}
+
+// Unfreeze lifts a freeze and returns the state before it was lifted
+func (m *Manager) Unfreeze() FreezeStatus {
+	f := &m.freeze
+	f.mu.Lock()
+	prev := f.status
+	if !prev.Frozen {
+		f.mu.Unlock()
+		return prev
+	}
+	f.status = FreezeStatus{}
+	m.frozen.Store(false)
+	m.saveFreezeLocked()
+	f.mu.Unlock()
+	m.changed()
+
+	log.Printf("Pool unfrozen (was frozen since %s after %s)", prev.Since.Format(time.RFC3339), prev.Anomaly)
+	m.setAlarm(AlarmFrozen, false, "unfrozen by operator")
+	return prev
+}
+
+// Freeze returns the freeze state
+func (m *Manager) Freeze() FreezeStatus {
+	m.freeze.mu.Lock()
+	defer m.freeze.mu.Unlock()
+	return m.freeze.status
+}
+
+// Frozen reports whether serving is stopped after an anomaly
+func (m *Manager) Frozen() bool {
+	return m.frozen.Load()
+}
+
+// saveFreezeLocked persists the freeze state, removing the file when unfrozen
+// Caller must hold m.freeze.mu.
+func (m *Manager) saveFreezeLocked() {
+	f := &m.freeze
+	if f.path == "" {
+		return // memory storage
+	}
+
+	var err error
+	if !f.status.Frozen {
+		if err = os.Remove(f.path); errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	} else {
+		var data []byte
+		if data, err = json.Marshal(f.status); err == nil {
+			err = os.WriteFile(f.path, data, 0600)
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to persist freeze state: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "freeze", "file": f.path})
+	}
+}
+
+// noteVerificationFailure counts a failed item and reports an anomaly once
+// the failure rate reaches the limit
+func (m *Manager) noteVerificationFailure() {
+	f := &m.freeze
+	f.failures.add(1)
+
+	f.mu.Lock()
+	limit, window := f.limit, f.window
+	f.mu.Unlock()
+
+	if limit > 0 {
+		if n := f.failures.count(window); n >= limit {
+			m.ReportAnomaly(AnomalyValidation, fmt.Errorf("%d items failed verification within %s (limit: %d)", n, window, limit))
+		}
+	}
+}
+
+// duplicateLocked reports whether item repeats the Paillier modulus or
+// NTilde of an item already in the pool, which points at a broken random
+// source or a replayed transfer
+// Caller must hold m.mu.
+func (m *Manager) duplicateLocked(item *PreParamsData) bool {
+	for _, p := range m.preParams {
+		if sameInt(p.PaillierKey.N, item.PaillierKey.N) || sameInt(p.NTildei, item.NTildei) {
+			return true
+		}
+	}
+	return false
+}
+
+// findDuplicates returns the indexes of items repeating the Paillier modulus
+// or NTilde of an earlier item
+func findDuplicates(items []*PreParamsData) []int {
+	seen := make(map[string]bool, 2*len(items))
+	var dups []int
+	for i, item := range items {
+		n, nt := "n:"+string(item.PaillierKey.N.Bytes()), "t:"+string(item.NTildei.Bytes())
+		if seen[n] || seen[nt] {
+			dups = append(dups, i)
+			continue
+		}
+		seen[n], seen[nt] = true, true
+	}
+	return dups
+}
+
+func sameInt(a, b *big.Int) bool {
+	return a != nil && b != nil && a.Cmp(b) == 0
+}
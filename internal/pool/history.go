@@ -0,0 +1,187 @@
+package pool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+)
+
+// Usage history is sampled from the running totals every historySample
+// into hourly buckets, kept for historyRetention
+const (
+	historySample    = 10 * time.Minute
+	historyRetention = 90 * 24 * time.Hour
+)
+
+// UsageBucket counts the items served and generated in one hour. Served
+// items split into pool hits and misses generated synchronously; buckets
+// recorded before the split have neither.
+type UsageBucket struct {
+	Hour      time.Time `json:"hour"`
+	Served    int64     `json:"served"`
+	Generated int64     `json:"generated"`
+
+	Hits                  int64   `json:"hits,omitempty"`
+	Misses                int64   `json:"misses,omitempty"`
+	MissGenerationSeconds float64 `json:"miss_generation_seconds,omitempty"` // Time requests spent generating misses
+}
+
+// usageTotals are the running totals usage history is sampled from
+type usageTotals struct {
+	served, generated int64
+	hits, misses      int64
+	missGenTime       time.Duration
+}
+
+// usageHistory is the persisted hourly usage of a pool, for forecasts
+type usageHistory struct {
+	mu      sync.Mutex
+	path    string
+	buckets []UsageBucket // Oldest first
+
+	// Totals of this process at the last sample
+	last usageTotals
+}
+
+// historyPath returns the usage history stored next to a pool file
+func historyPath(poolFile string) string {
+	return strings.TrimSuffix(poolFile, ".json") + ".history.json"
+}
+
+// load reads the history, if any
+func (h *usageHistory) load() error {
+	if h.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := json.Unmarshal(data, &h.buckets); err != nil {
+		return fmt.Errorf("failed to parse usage history %s: %w", h.path, err)
+	}
+	return nil
+}
+
+// record adds the totals' growth since the last sample to the bucket of
+// now's hour, returning whether anything changed
+func (h *usageHistory) record(now time.Time, totals usageTotals) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	dServed, dGenerated := totals.served-h.last.served, totals.generated-h.last.generated
+	dHits, dMisses := totals.hits-h.last.hits, totals.misses-h.last.misses
+	dMissGenTime := totals.missGenTime - h.last.missGenTime
+	h.last = totals
+
+	cutoff := now.Add(-historyRetention)
+	i := 0
+	for i < len(h.buckets) && h.buckets[i].Hour.Before(cutoff) {
+		i++
+	}
+	h.buckets = h.buckets[i:]
+	if dServed == 0 && dGenerated == 0 {
+		return i > 0
+	}
+
+	hour := now.Truncate(time.Hour)
+	if n := len(h.buckets); n == 0 || !h.buckets[n-1].Hour.Equal(hour) {
+		h.buckets = append(h.buckets, UsageBucket{Hour: hour})
+	}
+	b := &h.buckets[len(h.buckets)-1]
+	b.Served += dServed
+	b.Generated += dGenerated
+	b.Hits += dHits
+	b.Misses += dMisses
+	b.MissGenerationSeconds += dMissGenTime.Seconds()
+	return true
+}
+
+// save writes the history atomically (temp file + rename)
+func (h *usageHistory) save() error {
+	h.mu.Lock()
+	data, err := json.Marshal(h.buckets)
+	h.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage history: %w", err)
+	}
+
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write usage history: %w", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return fmt.Errorf("failed to replace usage history: %w", err)
+	}
+	return nil
+}
+
+// since returns the buckets from the hour of from on
+func (h *usageHistory) since(from time.Time) []UsageBucket {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	from = from.Truncate(time.Hour)
+	var result []UsageBucket
+	for _, b := range h.buckets {
+		if !b.Hour.Before(from) {
+			result = append(result, b)
+		}
+	}
+	return result
+}
+
+// UsageHistory returns the hourly usage since from, oldest first
+func (m *Manager) UsageHistory(from time.Time) []UsageBucket {
+	m.sampleHistory()
+	return m.history.since(from)
+}
+
+// sampleHistory records usage since the last sample and persists it
+func (m *Manager) sampleHistory() {
+	m.mu.RLock()
+	totals := usageTotals{
+		served:      m.totalServed,
+		generated:   m.totalGenerated,
+		hits:        m.poolHits,
+		misses:      m.poolMisses,
+		missGenTime: m.missGenTime,
+	}
+	m.mu.RUnlock()
+
+	if !m.history.record(time.Now(), totals) || m.history.path == "" {
+		return
+	}
+	if err := m.history.save(); err != nil {
+		log.Printf("Failed to save usage history: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "history", "file": m.history.path})
+	}
+}
+
+// historyLoop samples usage until the manager stops
+func (m *Manager) historyLoop() {
+	ticker := time.NewTicker(historySample)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.sampleHistory()
+		case <-m.stopCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// hookQueueSize is how many events may wait for a slow hook before new
+// events for it are dropped
+const hookQueueSize = 1024
+
+// Hook is notified after items are generated and consumed, e.g. to push a
+// copy to cold backup, update an external inventory or stamp items into a
+// ledger. Each hook runs on its own goroutine and sees events in order; a
+// hook falling more than hookQueueSize events behind misses events, which is
+// logged and journaled. Hooks must not modify the items.
+type Hook interface {
+	// Generated is called after an item was generated, both for the pool
+	// and on demand for a request
+	Generated(item *PreParamsData)
+
+	// Consumed is called after items were handed out by GetPreParams.
+	// Replays of an idempotent request are not reported again.
+	Consumed(items []*ServedParams, requestID string)
+}
+
+// hookEvent is a queued Generated (item set) or Consumed (served set) call
+type hookEvent struct {
+	item      *PreParamsData
+	served    []*ServedParams
+	requestID string
+}
+
+// hookRunner delivers events to one hook
+type hookRunner struct {
+	hook   Hook
+	events chan hookEvent
+}
+
+// hookSet holds the registered hooks
+type hookSet struct {
+	mu      sync.RWMutex
+	runners []*hookRunner
+}
+
+// AddHook registers h for generation and consumption events. Events before
+// registration are not replayed, so register hooks before Start to see the
+// initial fill.
+func (m *Manager) AddHook(h Hook) {
+	r := &hookRunner{hook: h, events: make(chan hookEvent, hookQueueSize)}
+	m.hooks.mu.Lock()
+	m.hooks.runners = append(m.hooks.runners, r)
+	m.hooks.mu.Unlock()
+	go m.runHook(r)
+}
+
+// runHook calls the hook for each event until the manager stops, then
+// delivers the events still queued
+func (m *Manager) runHook(r *hookRunner) {
+	for {
+		select {
+		case ev := <-r.events:
+			m.callHook(r.hook, ev)
+		case <-m.stopCh:
+			for {
+				select {
+				case ev := <-r.events:
+					m.callHook(r.hook, ev)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// callHook delivers one event, recovering from a panicking hook
+func (m *Manager) callHook(h Hook, ev hookEvent) {
+	defer func() {
+		if p := recover(); p != nil {
+			err := fmt.Errorf("hook %T panicked: %v", h, p)
+			log.Printf("%v", err)
+			m.errors.Record(errjournal.SeverityError, "hook", err, nil)
+		}
+	}()
+	if ev.item != nil {
+		h.Generated(ev.item)
+	} else {
+		h.Consumed(ev.served, ev.requestID)
+	}
+}
+
+// notifyHooks queues ev for every hook without blocking the caller
+func (m *Manager) notifyHooks(ev hookEvent) {
+	m.hooks.mu.RLock()
+	defer m.hooks.mu.RUnlock()
+	for _, r := range m.hooks.runners {
+		select {
+		case r.events <- ev:
+		default:
+			err := fmt.Errorf("hook %T is %d events behind, dropping event", r.hook, hookQueueSize)
+			log.Printf("%v", err)
+			m.errors.Record(errjournal.SeverityWarning, "hook", err, nil)
+		}
+	}
+}
+
+// hookGenerated reports a generated item to the hooks
+func (m *Manager) hookGenerated(item *PreParamsData) {
+	m.notifyHooks(hookEvent{item: item})
+}
+
+// hookConsumed reports items handed out for a request to the hooks
+func (m *Manager) hookConsumed(ctx context.Context, served []*ServedParams) {
+	if len(served) == 0 {
+		return
+	}
+	// Copy, since replays of the same allocation mark the items replayed
+	items := make([]*ServedParams, len(served))
+	for i, s := range served {
+		c := *s
+		items[i] = &c
+	}
+	m.notifyHooks(hookEvent{served: items, requestID: trace.ID(ctx)})
+}
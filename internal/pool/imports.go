@@ -0,0 +1,132 @@
+package pool
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+)
+
+// Where revalidated imports came from, recorded in audit entries
+const (
+	ImportSourceRestore   = "restore"   // Pool file written by another instance, or a legacy pool file
+	ImportSourceTransfer  = "transfer"  // Peer replica
+	ImportSourceBootstrap = "bootstrap" // Upstream service seeding a new instance
+	ImportSourceUpstream  = "upstream"  // Upstream service topping up an edge instance
+	ImportSourceAdmin     = "admin"     // Items exported from another instance, see ImportItems
+	ImportSourceSnapshot  = "snapshot"  // Items restored from a pool snapshot, see RestoreSnapshot
+	ImportSourceWorker    = "worker"    // Items submitted by a registered worker, see AddWorkerPreParams
+)
+
+// needsRevalidation reports whether item was generated elsewhere, is older
+// than ImportRevalidateAge and has not been revalidated yet
+func (m *Manager) needsRevalidation(item *PreParamsData, now time.Time) bool {
+	age := m.config.ImportRevalidateAge
+	return age > 0 && !item.Provenance.Imported && item.Provenance.Instance != m.instanceID &&
+		now.Sub(item.GeneratedAt) > age
+}
+
+// revalidateImport runs the full primality validation on an imported item
+// and flags it as imported; having passed it, the item is audited
+func revalidateImport(item *PreParamsData) error {
+	if err := auditItem(item); err != nil {
+		return fmt.Errorf("imported %w", err)
+	}
+	item.Provenance.Imported = true
+	return nil
+}
+
+// importAudit holds audit entries of imports until an audit log is set,
+// since the pool is loaded before the log is opened
+type importAudit struct {
+	mu      sync.Mutex
+	set     bool
+	log     *audit.Logger // A nil log discards entries
+	pending []audit.Entry
+}
+
+// SetAuditLog records revalidated imports in l (nil: none), including those
+// found while loading the pool
+func (m *Manager) SetAuditLog(l *audit.Logger) {
+	a := &m.importAudit
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.set, a.log = true, l
+	for _, e := range a.pending {
+		m.writeImportAudit(l, e)
+	}
+	a.pending = nil
+}
+
+// auditImport records that count items from source were revalidated and
+// flagged as imported
+func (m *Manager) auditImport(source string, count int) {
+	if count == 0 {
+		return
+	}
+	detail := fmt.Sprintf("source=%s older_than=%s demoted=%t", source, m.config.ImportRevalidateAge, m.config.ImportDemote)
+	switch source {
+	case ImportSourceBootstrap, ImportSourceUpstream:
+		// Items from the upstream are revalidated whatever their age
+		log.Printf("Validated %d parameter sets pulled from upstream (source: %s, demoted: %t)", count, source, m.config.ImportDemote)
+		detail = fmt.Sprintf("source=%s demoted=%t", source, m.config.ImportDemote)
+	case ImportSourceAdmin, ImportSourceSnapshot:
+		// So are items imported or restored by an operator; imported ones
+		// are always demoted
+		demoted := m.config.ImportDemote || source == ImportSourceAdmin
+		log.Printf("Validated %d imported parameter sets (source: %s, demoted: %t)", count, source, demoted)
+		detail = fmt.Sprintf("source=%s demoted=%t", source, demoted)
+	default:
+		log.Printf("Revalidated %d imported parameter sets older than %s (source: %s, demoted: %t)",
+			count, m.config.ImportRevalidateAge, source, m.config.ImportDemote)
+	}
+	e := audit.Entry{
+		Time:   time.Now(),
+		Event:  "items_imported",
+		Count:  count,
+		Detail: detail,
+	}
+	m.recordAudit(e)
+}
+
+// recordAudit writes an audit entry, holding it until an audit log is set
+func (m *Manager) recordAudit(e audit.Entry) {
+	a := &m.importAudit
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.set {
+		a.pending = append(a.pending, e)
+		return
+	}
+	m.writeImportAudit(a.log, e)
+}
+
+// writeImportAudit writes an import entry, freezing on failure like other
+// audit writes
+func (m *Manager) writeImportAudit(l *audit.Logger, e audit.Entry) {
+	if err := l.Record(e); err != nil {
+		log.Printf("Failed to record audit entry: %v", err)
+		m.ReportAnomaly(AnomalyAudit, fmt.Errorf("failed to record audit entry: %w", err))
+	}
+}
+
+// importedLocked counts the imported items in the pool
+// Caller must hold m.mu.
+func (m *Manager) importedLocked() int {
+	n := 0
+	for _, item := range m.preParams {
+		if item.Provenance.Imported {
+			n++
+		}
+	}
+	return n
+}
+
+// demotedLocked reports whether item is only served to requests allowing
+// imported items
+// Caller must hold m.mu.
+func (m *Manager) demotedLocked(item *PreParamsData) bool {
+	return item.Provenance.AdminImport || (m.config.ImportDemote && item.Provenance.Imported)
+}
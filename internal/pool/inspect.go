@@ -0,0 +1,134 @@
+package pool
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Page sizes of ListItems
+const (
+	DefaultItemPageSize = 100
+	MaxItemPageSize     = 1000
+)
+
+// ErrInvalidPageToken is returned by ListItems for tokens it did not issue
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// ItemInfo describes a pooled item without any of its secret material
+type ItemInfo struct {
+	Fingerprint        string        `json:"fingerprint"`
+	GeneratedAt        time.Time     `json:"generated_at"`
+	GenerationDuration time.Duration `json:"generation_duration"`
+	Provenance         Provenance    `json:"provenance"`
+	Stale              bool          `json:"stale,omitempty"` // Older than MaxAge
+	Assurance          string        `json:"assurance"`
+}
+
+// ItemPage is one page of ListItems
+type ItemPage struct {
+	Items         []ItemInfo `json:"items"`
+	NextPageToken string     `json:"next_page_token,omitempty"` // Empty on the last page
+	Total         int        `json:"total"`                     // Items in the pool
+}
+
+// fingerprintLen is the length of a fingerprint in hex digits
+const fingerprintLen = 32
+
+// Fingerprint identifies an item by its public moduli: the hex-encoded
+// first 16 bytes of the SHA-256 over the big-endian bytes of the Paillier
+// modulus followed by NTildei. Clients can compute it for items they
+// received.
+func Fingerprint(item *PreParamsData) string {
+	h := sha256.New()
+	if item.PaillierKey != nil && item.PaillierKey.N != nil {
+		h.Write(item.PaillierKey.N.Bytes())
+	}
+	if item.NTildei != nil {
+		h.Write(item.NTildei.Bytes())
+	}
+	return hex.EncodeToString(h.Sum(nil)[:fingerprintLen/2])
+}
+
+// ListItems lists the pool oldest first (ties broken by fingerprint), up to
+// pageSize items (0: DefaultItemPageSize) after the position in pageToken.
+// Tokens name a position in that order rather than an offset, so items
+// served or added between pages neither shift nor repeat the listing.
+func (m *Manager) ListItems(pageToken string, pageSize int) (ItemPage, error) {
+	after, err := parsePageToken(pageToken)
+	if err != nil {
+		return ItemPage{}, err
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultItemPageSize
+	}
+	pageSize = min(pageSize, MaxItemPageSize)
+
+	now := time.Now()
+	m.mu.RLock()
+	all := make([]ItemInfo, len(m.preParams))
+	for i, item := range m.preParams {
+		all[i] = ItemInfo{
+			Fingerprint:        Fingerprint(item),
+			GeneratedAt:        item.GeneratedAt,
+			GenerationDuration: item.GenerationDuration,
+			Provenance:         item.Provenance,
+			Stale:              m.isStale(item, now),
+			Assurance:          AssuranceOf(item),
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(all, func(i, j int) bool { return itemBefore(all[i], all[j]) })
+	start := 0
+	if after != nil {
+		start = sort.Search(len(all), func(i int) bool { return itemBefore(*after, all[i]) })
+	}
+	end := min(start+pageSize, len(all))
+
+	page := ItemPage{Items: all[start:end], Total: len(all)}
+	if end < len(all) {
+		page.NextPageToken = pageTokenFor(all[end-1])
+	}
+	return page, nil
+}
+
+// itemBefore orders items by generation time, then fingerprint
+func itemBefore(a, b ItemInfo) bool {
+	if !a.GeneratedAt.Equal(b.GeneratedAt) {
+		return a.GeneratedAt.Before(b.GeneratedAt)
+	}
+	return a.Fingerprint < b.Fingerprint
+}
+
+// pageTokenFor encodes the position after item
+func pageTokenFor(item ItemInfo) string {
+	pos := fmt.Sprintf("%d/%s", item.GeneratedAt.UnixNano(), item.Fingerprint)
+	return base64.RawURLEncoding.EncodeToString([]byte(pos))
+}
+
+// parsePageToken decodes a position; an empty token starts from the beginning
+func parsePageToken(token string) (*ItemInfo, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	nanos, fingerprint, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return nil, ErrInvalidPageToken
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	return &ItemInfo{GeneratedAt: time.Unix(0, n), Fingerprint: fingerprint}, nil
+}
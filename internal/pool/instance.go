@@ -0,0 +1,48 @@
+package pool
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// loadInstanceID returns the configured instance ID or, if none is
+// configured, the ID persisted at path, generating and saving one on first
+// start so the instance keeps its identity across restarts. With an empty
+// path a new ID is generated on every start.
+func loadInstanceID(configured, path string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err == nil {
+			if id := strings.TrimSpace(string(data)); id != "" {
+				return id, nil
+			}
+		} else if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to read instance ID: %w", err)
+		}
+	}
+
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate instance ID: %w", err)
+	}
+	id := "prime-" + hex.EncodeToString(b)
+	if path == "" {
+		return id, nil
+	}
+	if err := os.WriteFile(path, []byte(id+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to persist instance ID: %w", err)
+	}
+	return id, nil
+}
+
+// InstanceID returns the stable identity of this service instance
+func (m *Manager) InstanceID() string {
+	return m.instanceID
+}
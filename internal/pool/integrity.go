@@ -0,0 +1,130 @@
+package pool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// Failure classes of items that fail verification
+const (
+	FailureCorruption = "corruption" // Stored bytes changed since the checksum was taken (disk/storage fault)
+	FailureInvalid    = "invalid"    // Checksum matches but the parameters are inconsistent (generation/logic bug)
+)
+
+// checksum returns the SHA-256 over the canonical encoding of an item: every
+// parameter as a length-prefixed big-endian integer in a fixed order,
+// followed by the generation time in Unix nanoseconds
+func checksum(item *PreParamsData) string {
+	h := sha256.New()
+	var buf [8]byte
+	for _, v := range []*big.Int{
+		item.PaillierKey.N, item.PaillierKey.LambdaN, item.PaillierKey.PhiN,
+		item.PaillierKey.P, item.PaillierKey.Q,
+		item.NTildei, item.H1i, item.H2i, item.Alpha, item.Beta, item.P, item.Q,
+	} {
+		var b []byte
+		if v != nil {
+			b = v.Bytes()
+		}
+		binary.BigEndian.PutUint64(buf[:], uint64(len(b)))
+		h.Write(buf[:])
+		h.Write(b)
+	}
+	binary.BigEndian.PutUint64(buf[:], uint64(item.GeneratedAt.UnixNano()))
+	h.Write(buf[:])
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// sealItem records the checksum of an item entering the pool
+func sealItem(item *PreParamsData) {
+	item.Checksum = checksum(item)
+}
+
+// verifyItem checks an item's checksum and consistency, returning the
+// failure class if it must not be served
+func verifyItem(item *PreParamsData) (string, error) {
+	if item == nil || item.PaillierKey == nil {
+		return FailureCorruption, fmt.Errorf("missing parameter set")
+	}
+	if item.Checksum != "" && checksum(item) != item.Checksum {
+		return FailureCorruption, fmt.Errorf("checksum mismatch")
+	}
+	if err := validateItem(item); err != nil {
+		return FailureInvalid, err
+	}
+	return "", nil
+}
+
+// quarantineEntry is one line of the quarantine file
+type quarantineEntry struct {
+	Time   time.Time      `json:"time"`
+	Class  string         `json:"class"`
+	Error  string         `json:"error"`
+	Source string         `json:"source"` // Where the failure was detected: load or serve
+	Item   *PreParamsData `json:"item,omitempty"`
+
+	// SealedItem replaces Item when files are encrypted at rest
+	SealedItem []byte `json:"sealed_item,omitempty"`
+}
+
+// quarantineFile keeps failed items out of the pool for later analysis
+type quarantineFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+// quarantine removes a failed item from circulation: it is appended to the
+// quarantine file and the failure is journaled with its class
+func (m *Manager) quarantine(ctx context.Context, item *PreParamsData, class string, err error, source string) {
+	m.quarantined.Add(1)
+	trace.Logf(ctx, "Quarantined parameter set (class: %s, detected at: %s): %v", class, source, err)
+	m.errors.Record(errjournal.SeverityError, "integrity", err, map[string]string{"class": class, "source": source, "request_id": trace.ID(ctx)})
+	m.noteVerificationFailure()
+
+	if m.quarantineFile.path == "" {
+		return // memory storage
+	}
+
+	entry := quarantineEntry{Time: time.Now(), Class: class, Error: err.Error(), Source: source, Item: item}
+	if m.cipher != nil {
+		data, merr := json.Marshal(item)
+		if merr == nil {
+			entry.SealedItem, merr = m.cipher.Seal(data, sealQuarantine)
+		}
+		if merr != nil {
+			log.Printf("Failed to encrypt quarantined item: %v", merr)
+			return
+		}
+		entry.Item = nil
+	}
+	line, merr := json.Marshal(entry)
+	if merr != nil {
+		log.Printf("Failed to marshal quarantine entry: %v", merr)
+		return
+	}
+
+	q := &m.quarantineFile
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	file, ferr := os.OpenFile(q.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if ferr != nil {
+		log.Printf("Failed to open quarantine file: %v", ferr)
+		return
+	}
+	defer file.Close()
+	if _, werr := file.Write(append(line, '\n')); werr != nil {
+		log.Printf("Failed to write quarantine entry: %v", werr)
+	}
+}
@@ -0,0 +1,75 @@
+package pool
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/TEENet-io/prime-service/internal/generator"
+)
+
+func TestVerifyItem(t *testing.T) {
+	tests := []struct {
+		name      string
+		item      func(item *PreParamsData) *PreParamsData
+		wantClass string
+	}{
+		{name: "sealed", item: func(item *PreParamsData) *PreParamsData { sealItem(item); return item }},
+		{name: "unsealed", item: func(item *PreParamsData) *PreParamsData { return item }},
+		{name: "changed after sealing", item: func(item *PreParamsData) *PreParamsData {
+			sealItem(item)
+			item.Alpha = new(big.Int).Add(item.Alpha, big.NewInt(1))
+			return item
+		}, wantClass: FailureCorruption},
+		{name: "inconsistent when sealed", item: func(item *PreParamsData) *PreParamsData {
+			item.NTildei = new(big.Int).Add(item.NTildei, big.NewInt(2))
+			sealItem(item)
+			return item
+		}, wantClass: FailureInvalid},
+		{name: "missing", item: func(item *PreParamsData) *PreParamsData { return nil }, wantClass: FailureCorruption},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, err := verifyItem(tt.item(testItems(t)[0]))
+			if class != tt.wantClass || (err == nil) != (tt.wantClass == "") {
+				t.Fatalf("verifyItem() = %q, %v, want class %q", class, err, tt.wantClass)
+			}
+		})
+	}
+}
+
+func TestCorruptItemsQuarantined(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	m := newTestManager(t, fileConfig(t, dir), testItems(t))
+	m.writePoolFile(ctx)
+
+	// Changed in memory after sealing: refused when served
+	m.mu.Lock()
+	m.preParams[0].Beta = new(big.Int).Add(m.preParams[0].Beta, big.NewInt(1))
+	m.mu.Unlock()
+	served, err := m.GetPreParams(ctx, Request{Count: 1, NoGenerate: true})
+	if err != nil {
+		t.Fatalf("GetPreParams() = %v", err)
+	}
+	if class, err := verifyItem(served[0].PreParamsData); err != nil {
+		t.Fatalf("served a corrupt item (%s: %v)", class, err)
+	}
+	if got := m.quarantined.Load(); got != 1 {
+		t.Fatalf("quarantined %d items when serving, want 1", got)
+	}
+
+	// Changed in the pool file: refused when loaded
+	m.mu.Lock()
+	m.preParams[0].Alpha = new(big.Int).Add(m.preParams[0].Alpha, big.NewInt(1))
+	m.mu.Unlock()
+	m.writePoolFile(ctx)
+	reloaded := NewManager(generator.NewGenerator(), fileConfig(t, dir))
+	if reloaded.storageErr != nil {
+		t.Fatalf("NewManager() = %v", reloaded.storageErr)
+	}
+	if reloaded.Size() != 0 || reloaded.quarantined.Load() != 1 {
+		t.Fatalf("reloaded pool holds %d items with %d quarantined, want 0 and 1", reloaded.Size(), reloaded.quarantined.Load())
+	}
+}
@@ -0,0 +1,198 @@
+package pool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/atrest"
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/shred"
+)
+
+// requestJournal persists the items allocated to each idempotency key so a
+// client retry, even one spanning a server restart, gets the original
+// allocation back instead of consuming more items from the pool. Each pool
+// has its own journal, and keys are scoped to the caller, see journalKey.
+type requestJournal struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	shred   bool           // Overwrite replaced journal contents (SecureDelete)
+	cipher  *atrest.Cipher // At-rest encryption (nil: plaintext)
+	entries map[string]*journalEntry
+	pending map[string]*pendingKey // serializes requests sharing a key
+}
+
+// pendingKey is the lock of a key in use, dropped with its last waiter
+type pendingKey struct {
+	mu      sync.Mutex
+	waiters int // Requests holding or waiting for mu, guarded by requestJournal.mu
+}
+
+type journalEntry struct {
+	Count     uint32        `json:"count"`
+	Items     []journalItem `json:"items"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type journalItem struct {
+	Params  *PreParamsData `json:"params"`
+	Source  ItemSource     `json:"source"`
+	PoolAge time.Duration  `json:"pool_age"`
+	Stale   bool           `json:"stale,omitempty"`
+}
+
+// newRequestJournal returns an empty journal; load restores the saved one
+func newRequestJournal(path string, ttl time.Duration, shred bool, cipher *atrest.Cipher) *requestJournal {
+	return &requestJournal{
+		path:    path,
+		ttl:     ttl,
+		shred:   shred,
+		cipher:  cipher,
+		entries: make(map[string]*journalEntry),
+		pending: make(map[string]*pendingKey),
+	}
+}
+
+// journalKey scopes an idempotency key to the API client and namespace of
+// the request, so no other caller can replay its allocation
+func journalKey(req Request) string {
+	namespace := req.Namespace
+	if namespace == "" {
+		namespace = config.DefaultNamespace
+	}
+	return req.Client + "\x00" + namespace + "\x00" + req.IdempotencyKey
+}
+
+// lockKey serializes requests with the same key and returns the unlock
+// function, which forgets the key once no request waits for it
+func (j *requestJournal) lockKey(key string) func() {
+	j.mu.Lock()
+	p, ok := j.pending[key]
+	if !ok {
+		p = &pendingKey{}
+		j.pending[key] = p
+	}
+	p.waiters++
+	j.mu.Unlock()
+
+	p.mu.Lock()
+	return func() {
+		p.mu.Unlock()
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		if p.waiters--; p.waiters == 0 {
+			delete(j.pending, key)
+		}
+	}
+}
+
+// lookup returns the recorded allocation for key, if still valid
+func (j *requestJournal) lookup(key string) ([]*ServedParams, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry, ok := j.entries[key]
+	if !ok || time.Since(entry.CreatedAt) > j.ttl {
+		return nil, false
+	}
+
+	result := make([]*ServedParams, len(entry.Items))
+	for i, item := range entry.Items {
+		result[i] = &ServedParams{PreParamsData: item.Params, Source: item.Source, PoolAge: item.PoolAge, Stale: item.Stale}
+	}
+	return result, true
+}
+
+// record durably stores the allocation for key before it is returned
+func (j *requestJournal) record(key string, count uint32, served []*ServedParams) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry := &journalEntry{Count: count, CreatedAt: time.Now()}
+	for _, s := range served {
+		entry.Items = append(entry.Items, journalItem{Params: s.PreParamsData, Source: s.Source, PoolAge: s.PoolAge, Stale: s.Stale})
+	}
+	j.entries[key] = entry
+	j.expireLocked()
+
+	return j.saveLocked()
+}
+
+// expireLocked drops entries older than the TTL
+func (j *requestJournal) expireLocked() {
+	for key, entry := range j.entries {
+		if time.Since(entry.CreatedAt) > j.ttl {
+			delete(j.entries, key)
+		}
+	}
+}
+
+// saveLocked writes the journal atomically (temp file + rename)
+func (j *requestJournal) saveLocked() error {
+	if j.path == "" {
+		return nil // memory storage
+	}
+
+	data, err := json.Marshal(j.entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request journal: %w", err)
+	}
+	if data, err = j.cipher.Seal(data, sealJournal); err != nil {
+		return fmt.Errorf("failed to encrypt request journal: %w", err)
+	}
+
+	err = shred.Replace(j.path, data, 0600, j.shred)
+	if errors.Is(err, shred.ErrNotOverwritten) {
+		// The new journal is in place; only the old contents may linger
+		log.Printf("Request journal saved, but %v", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save request journal: %w", err)
+	}
+	return nil
+}
+
+// load restores unexpired entries from disk. It only fails for a journal
+// that cannot be decrypted, which must not be replaced by an empty one.
+func (j *requestJournal) load() error {
+	if j.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(j.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("Failed to read request journal: %v", err)
+		return nil
+	}
+
+	encrypted := atrest.Encrypted(data)
+	if data, err = j.cipher.Open(data, sealJournal); err != nil {
+		return fmt.Errorf("failed to decrypt request journal %s: %w", j.path, err)
+	}
+	if err := json.Unmarshal(data, &j.entries); err != nil {
+		log.Printf("Failed to unmarshal request journal: %v", err)
+		j.entries = make(map[string]*journalEntry)
+		return nil
+	}
+	j.expireLocked()
+
+	log.Printf("Request journal loaded (file: %s, entries: %d)", j.path, len(j.entries))
+	if j.cipher != nil && !encrypted {
+		log.Printf("Encrypting request journal saved in plaintext")
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		if err := j.saveLocked(); err != nil {
+			log.Printf("Failed to encrypt request journal: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/generator"
+)
+
+func TestIdempotentReplay(t *testing.T) {
+	first := Request{Count: 1, NoGenerate: true, IdempotencyKey: "key-1", Client: "client-a", Namespace: "team-a"}
+	tests := []struct {
+		name       string
+		retry      func(r Request) Request
+		wantReplay bool
+		wantErr    error
+	}{
+		{name: "same caller", retry: func(r Request) Request { return r }, wantReplay: true},
+		{name: "other client", retry: func(r Request) Request { r.Client = "client-b"; return r }},
+		{name: "other namespace", retry: func(r Request) Request { r.Namespace = "team-b"; return r }},
+		{name: "anonymous", retry: func(r Request) Request { r.Client = ""; return r }},
+		{name: "other key", retry: func(r Request) Request { r.IdempotencyKey = "key-2"; return r }},
+		{name: "unknown namespace", retry: func(r Request) Request { r.Namespace = "team-c"; return r }, wantErr: ErrUnknownNamespace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cfg := testConfig(t)
+			cfg.Tenants = []config.TenantConfig{{Name: "team-a"}, {Name: "team-b"}}
+			m := newTestManager(t, cfg, testItems(t))
+
+			original, err := m.GetPreParams(ctx, first)
+			if err != nil {
+				t.Fatalf("GetPreParams() = %v", err)
+			}
+			retried, err := m.GetPreParams(ctx, tt.retry(first))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("retry: GetPreParams() = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			replayed := retried[0].PreParamsData == original[0].PreParamsData
+			if replayed != tt.wantReplay || retried[0].Replayed != tt.wantReplay {
+				t.Fatalf("retry replayed = %v (flag %v), want %v", replayed, retried[0].Replayed, tt.wantReplay)
+			}
+			wantSize := testItemCount - 2
+			if tt.wantReplay {
+				wantSize = testItemCount - 1
+			}
+			if m.Size() != wantSize {
+				t.Fatalf("pool holds %d items after retry, want %d", m.Size(), wantSize)
+			}
+		})
+	}
+}
+
+func TestIdempotentReplayAfterRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	req := Request{Count: 2, NoGenerate: true, IdempotencyKey: "key-1", Client: "client-a"}
+
+	m := newTestManager(t, fileConfig(t, dir), testItems(t))
+	original, err := m.GetPreParams(ctx, req)
+	if err != nil {
+		t.Fatalf("GetPreParams() = %v", err)
+	}
+	m.Stop()
+
+	restarted := NewManager(generator.NewGenerator(), fileConfig(t, dir))
+	if restarted.storageErr != nil {
+		t.Fatalf("NewManager() = %v", restarted.storageErr)
+	}
+	retried, err := restarted.GetPreParams(ctx, req)
+	if err != nil {
+		t.Fatalf("retry: GetPreParams() = %v", err)
+	}
+	if len(retried) != len(original) {
+		t.Fatalf("replayed %d items, want %d", len(retried), len(original))
+	}
+	for i := range retried {
+		if !retried[i].Replayed || retried[i].Checksum != original[i].Checksum {
+			t.Fatalf("item %d was not replayed", i)
+		}
+	}
+	if restarted.Size() != testItemCount-len(original) {
+		t.Fatalf("pool holds %d items after replay, want %d", restarted.Size(), testItemCount-len(original))
+	}
+}
+
+func TestJournalKeyLocks(t *testing.T) {
+	j := newRequestJournal("", 0, false, nil)
+	unlock := j.lockKey("key-1")
+	done := make(chan struct{})
+	go func() {
+		j.lockKey("key-1")()
+		close(done)
+	}()
+	unlock()
+	<-done
+
+	// Keys never recorded, e.g. of failed allocations, are not kept
+	if n := len(j.pending); n != 0 {
+		t.Fatalf("journal keeps %d key locks, want 0", n)
+	}
+}
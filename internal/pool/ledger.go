@@ -0,0 +1,223 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// ledger is written synchronously whenever items leave the pool, while the
+// pool file itself is saved lazily. After a crash it tells which stored items
+// were already handed out, so they are never served twice.
+type ledger struct {
+	SavedAt        time.Time `json:"saved_at"`
+	PoolSize       int       `json:"pool_size"`       // Items the pool held when written
+	TotalGenerated int64     `json:"total_generated"` // Lifetime totals
+	TotalServed    int64     `json:"total_served"`
+	Removed        []string  `json:"removed"` // Checksums of items served or transferred since the last pool save
+
+	// Checksums of items removed between the pool backup and the last pool
+	// save, dropped when the backup has to be loaded
+	BackupRemoved []string `json:"backup_removed,omitempty"`
+}
+
+// ledgerFile tracks removals since the last pool save and since the backup
+type ledgerFile struct {
+	mu            sync.Mutex
+	path          string
+	removed       []string
+	backupRemoved []string
+	saving        int // Removals already left out of the pool file being saved
+
+	// Lifetime totals before this process started
+	baseGenerated int64
+	baseServed    int64
+}
+
+// ConsistencyReport describes discrepancies found at startup between the
+// ledger and the stored pool
+type ConsistencyReport struct {
+	Checked       bool     `json:"checked"`        // A ledger existed to compare against
+	Issues        []string `json:"issues"`         // Human-readable discrepancies
+	AlreadyServed int      `json:"already_served"` // Stored items recorded as served, dropped from the pool
+}
+
+// ledgerPath returns the ledger stored next to a pool file
+func ledgerPath(poolFile string) string {
+	return strings.TrimSuffix(poolFile, ".json") + ".ledger.json"
+}
+
+// readLedger loads the ledger, returning nil if there is none yet (or no path)
+func readLedger(path string) (*ledger, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var l ledger
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("failed to parse ledger %s: %w", path, err)
+	}
+	return &l, nil
+}
+
+// writeLedgerLocked records the current pool size and totals plus the
+// checksums of removed items. With saved set, the pool file taken at
+// markSavingLocked has just been written and the removals before it are no
+// longer in it, only in the backup.
+// Caller must hold m.mu (read or write).
+func (m *Manager) writeLedgerLocked(ctx context.Context, removed []*PreParamsData, saved bool) {
+	lf := &m.ledgerFile
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	if saved {
+		// Items removed while the file was written are still in it
+		lf.backupRemoved = lf.removed[:lf.saving:lf.saving]
+		lf.removed = append([]string(nil), lf.removed[lf.saving:]...)
+		lf.saving = 0
+	}
+	for _, item := range removed {
+		lf.removed = append(lf.removed, item.Checksum)
+	}
+	m.logSnapshotRemovals(ctx, removed)
+
+	l := ledger{
+		SavedAt:        time.Now(),
+		PoolSize:       len(m.preParams),
+		TotalGenerated: lf.baseGenerated + m.totalGenerated,
+		TotalServed:    lf.baseServed + m.totalServed,
+		Removed:        lf.removed,
+		BackupRemoved:  lf.backupRemoved,
+	}
+	if l.Removed == nil {
+		l.Removed = []string{}
+	}
+
+	if lf.path == "" {
+		return // memory storage
+	}
+	if err := l.save(lf.path); err != nil {
+		trace.Logf(ctx, "Failed to write pool ledger: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "ledger", "file": lf.path})
+		return
+	}
+	m.syncWritten(ctx, lf.path)
+}
+
+// markSavingLocked notes the removals recorded when the pool is taken for
+// a pool file, which the file will not hold
+// Caller must hold m.mu (read or write).
+func (m *Manager) markSavingLocked() {
+	m.ledgerFile.mu.Lock()
+	defer m.ledgerFile.mu.Unlock()
+	m.ledgerFile.saving = len(m.ledgerFile.removed)
+}
+
+// save writes the ledger atomically (temp file + rename)
+func (l *ledger) save(path string) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace ledger: %w", err)
+	}
+	return nil
+}
+
+// lifetimeTotalsLocked returns generated and served totals including
+// previous runs
+// Caller must hold m.mu.
+func (m *Manager) lifetimeTotalsLocked() (generated, served int64) {
+	m.ledgerFile.mu.Lock()
+	defer m.ledgerFile.mu.Unlock()
+	return m.ledgerFile.baseGenerated + m.totalGenerated, m.ledgerFile.baseServed + m.totalServed
+}
+
+// checkConsistency compares the loaded pool file with the ledger, drops
+// items the ledger records as already served and reports discrepancies.
+// With recovered set, data is the pool backup, which may also hold items
+// removed before the last save.
+func (m *Manager) checkConsistency(data *poolFile, recovered bool) {
+	l, err := readLedger(m.ledgerFile.path)
+	if err != nil {
+		log.Printf("Failed to read pool ledger, skipping consistency check: %v", err)
+		m.errors.Record(errjournal.SeverityWarning, "consistency", err, nil)
+		return
+	}
+	if l == nil {
+		m.ledgerFile.baseGenerated, m.ledgerFile.baseServed = data.TotalGenerated, data.TotalServed
+		return
+	}
+	m.ledgerFile.baseGenerated, m.ledgerFile.baseServed = l.TotalGenerated, l.TotalServed
+
+	report := ConsistencyReport{Checked: true}
+	if recovered {
+		report.Issues = append(report.Issues, fmt.Sprintf("pool file was missing or unreadable, loaded the backup saved at %s", data.SavedAt.Format(time.RFC3339)))
+	}
+
+	// Removals stay in the ledger until the stored file no longer holds them
+	m.ledgerFile.removed, m.ledgerFile.backupRemoved = l.Removed, l.BackupRemoved
+	if recovered {
+		m.ledgerFile.removed = append(append([]string(nil), l.Removed...), l.BackupRemoved...)
+		m.ledgerFile.backupRemoved = m.ledgerFile.removed
+	}
+	removed := make(map[string]bool, len(m.ledgerFile.removed))
+	for _, sum := range m.ledgerFile.removed {
+		removed[sum] = true
+	}
+	stored := len(data.PreParams)
+	kept := data.PreParams[:0]
+	for _, item := range data.PreParams {
+		if item != nil && item.Checksum != "" && removed[item.Checksum] {
+			report.AlreadyServed++
+			continue
+		}
+		kept = append(kept, item)
+	}
+	data.PreParams = kept
+
+	if report.AlreadyServed > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d stored items were already served or transferred (dropped)", report.AlreadyServed))
+	}
+	if len(kept) != l.PoolSize {
+		report.Issues = append(report.Issues, fmt.Sprintf("pool file holds %d items (%d before dropping served ones), ledger expects %d", len(kept), stored, l.PoolSize))
+	}
+	if data.TotalServed != l.TotalServed {
+		report.Issues = append(report.Issues, fmt.Sprintf("pool file was saved at %d served, ledger records %d", data.TotalServed, l.TotalServed))
+	}
+	if data.TotalGenerated != l.TotalGenerated {
+		report.Issues = append(report.Issues, fmt.Sprintf("pool file was saved at %d generated, ledger records %d", data.TotalGenerated, l.TotalGenerated))
+	}
+
+	for _, issue := range report.Issues {
+		log.Printf("Warning: pool consistency: %s", issue)
+		m.errors.Record(errjournal.SeverityWarning, "consistency", errors.New(issue), map[string]string{"file": m.poolFilePath})
+	}
+	m.consistency = report
+}
+
+// Consistency returns the result of the startup consistency check
+func (m *Manager) Consistency() ConsistencyReport {
+	return m.consistency
+}
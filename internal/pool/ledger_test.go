@@ -0,0 +1,76 @@
+package pool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/TEENet-io/prime-service/internal/generator"
+)
+
+func TestConsistencyCheck(t *testing.T) {
+	tests := []struct {
+		name          string
+		saveAfter     bool // Save the pool after serving, as a clean shutdown does
+		wantServed    int
+		wantIssues    []string
+		wantSize      int
+		wantTotServed int64
+	}{
+		{name: "saved after serving", saveAfter: true, wantSize: 2, wantTotServed: 1},
+		{
+			name:          "crash after serving",
+			wantServed:    1,
+			wantIssues:    []string{"already served", "saved at 0 served, ledger records 1"},
+			wantSize:      2,
+			wantTotServed: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			m := newTestManager(t, fileConfig(t, dir), testItems(t))
+			m.writePoolFile(ctx)
+			served, err := m.GetPreParams(ctx, Request{Count: 1, NoGenerate: true})
+			if err != nil {
+				t.Fatalf("GetPreParams() = %v", err)
+			}
+			if tt.saveAfter {
+				m.writePoolFile(ctx)
+			}
+
+			reloaded := NewManager(generator.NewGenerator(), fileConfig(t, dir))
+			if reloaded.storageErr != nil {
+				t.Fatalf("NewManager() = %v", reloaded.storageErr)
+			}
+			report := reloaded.Consistency()
+			if !report.Checked || report.AlreadyServed != tt.wantServed {
+				t.Fatalf("consistency = %+v, want checked with %d already served", report, tt.wantServed)
+			}
+			if len(report.Issues) != len(tt.wantIssues) {
+				t.Fatalf("consistency issues = %q, want %d", report.Issues, len(tt.wantIssues))
+			}
+			for i, want := range tt.wantIssues {
+				if !strings.Contains(report.Issues[i], want) {
+					t.Fatalf("issue %d = %q, want it to mention %q", i, report.Issues[i], want)
+				}
+			}
+
+			if reloaded.Size() != tt.wantSize {
+				t.Fatalf("reloaded pool holds %d items, want %d", reloaded.Size(), tt.wantSize)
+			}
+			reloaded.mu.RLock()
+			defer reloaded.mu.RUnlock()
+			for _, item := range reloaded.preParams {
+				if item.Checksum == served[0].Checksum {
+					t.Fatal("served item is back in the pool")
+				}
+			}
+			if _, total := reloaded.lifetimeTotalsLocked(); total != tt.wantTotServed {
+				t.Fatalf("lifetime served = %d, want %d", total, tt.wantTotServed)
+			}
+		})
+	}
+}
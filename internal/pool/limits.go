@@ -0,0 +1,97 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// ErrInvalidLimits is returned by SetLimits for inconsistent pool sizes
+var ErrInvalidLimits = errors.New("invalid pool limits")
+
+// ErrSharedPool is returned by ClearPool for pools kept in shared storage,
+// which other replicas serve from as well
+var ErrSharedPool = errors.New("pool is shared with other replicas")
+
+// Limits are the pool sizes and refill threshold adjustable at runtime
+type Limits struct {
+	MinPoolSize     int
+	MaxPoolSize     int
+	RefillThreshold int
+}
+
+// Limits returns the current pool sizes and refill threshold
+func (m *Manager) Limits() Limits {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return Limits{MinPoolSize: m.config.MinPoolSize, MaxPoolSize: m.config.MaxPoolSize, RefillThreshold: m.config.RefillThreshold}
+}
+
+// SetLimits replaces the pool sizes and refill threshold without a restart;
+// zero fields keep their current value. The change lasts until the next
+// Reload, which restores the configured limits. Lowering MaxPoolSize
+// discards nothing: the pool shrinks as its items are served. A pool at or
+// below the new threshold starts refilling right away.
+func (m *Manager) SetLimits(ctx context.Context, l Limits) (Limits, error) {
+	if l == (Limits{}) {
+		return m.Limits(), nil
+	}
+	m.mu.Lock()
+	cfg := *m.config
+	if l.MinPoolSize != 0 {
+		cfg.MinPoolSize = l.MinPoolSize
+	}
+	if l.MaxPoolSize != 0 {
+		cfg.MaxPoolSize = l.MaxPoolSize
+	}
+	if l.RefillThreshold != 0 {
+		cfg.RefillThreshold = l.RefillThreshold
+	}
+	if err := cfg.Validate(); err != nil {
+		m.mu.Unlock()
+		return Limits{}, fmt.Errorf("%w: %v", ErrInvalidLimits, err)
+	}
+	old := Limits{MinPoolSize: m.config.MinPoolSize, MaxPoolSize: m.config.MaxPoolSize, RefillThreshold: m.config.RefillThreshold}
+	m.config.MinPoolSize = cfg.MinPoolSize
+	m.config.MaxPoolSize = cfg.MaxPoolSize
+	m.config.RefillThreshold = cfg.RefillThreshold
+	l = Limits{MinPoolSize: cfg.MinPoolSize, MaxPoolSize: cfg.MaxPoolSize, RefillThreshold: cfg.RefillThreshold}
+	size := m.sizeLocked()
+	m.changed()
+	m.mu.Unlock()
+
+	trace.Logf(ctx, "Pool limits set (pool_size: %d-%d -> %d-%d, threshold: %d -> %d)",
+		old.MinPoolSize, old.MaxPoolSize, l.MinPoolSize, l.MaxPoolSize, old.RefillThreshold, l.RefillThreshold)
+	if size <= l.RefillThreshold {
+		go m.refillPool()
+	}
+	return l, nil
+}
+
+// ClearPool discards every item in the pool, e.g. after the host may have
+// been compromised, and returns how many were removed. Pinned items stay
+// set aside. Unless generation is paused, a refill replaces the items right
+// away. Pools in shared storage are refused, as clearing them would empty
+// every replica's pool.
+func (m *Manager) ClearPool(ctx context.Context) (int, error) {
+	if m.shared != nil {
+		return 0, ErrSharedPool
+	}
+
+	m.mu.Lock()
+	removed := m.preParams
+	m.preParams = make([]*PreParamsData, 0)
+	m.cleared += int64(len(removed))
+	if len(removed) > 0 {
+		m.changed()
+		m.writeLedgerLocked(ctx, removed, false)
+	}
+	m.mu.Unlock()
+
+	trace.Logf(ctx, "Cleared %d parameters from the pool", len(removed))
+	m.saveToDisk(ctx)
+	go m.refillPool()
+	return len(removed), nil
+}
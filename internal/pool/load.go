@@ -0,0 +1,138 @@
+package pool
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// loadProgressInterval is how often a slow pool load logs its progress
+const loadProgressInterval = 2 * time.Second
+
+// rawPoolFile is the on-disk pool format with items left undecoded, so they
+// can be decoded in parallel
+type rawPoolFile struct {
+	PreParams      []json.RawMessage `json:"pre_params"`
+	SavedAt        time.Time         `json:"saved_at"`
+	TotalGenerated int64             `json:"total_generated"`
+	TotalServed    int64             `json:"total_served"`
+	Pinned         []*pinnedItem     `json:"pinned,omitempty"`
+}
+
+// loadResult is the verification outcome of one loaded item
+type loadResult struct {
+	class    string
+	err      error
+	source   string // Check that failed: load, or import for revalidation
+	sealed   bool
+	imported bool // Revalidated as an import
+	audited  bool // Raised to audited by the full validation
+}
+
+// decodePoolFile parses a pool file, decoding items on all CPUs
+func decodePoolFile(data []byte) (*poolFile, error) {
+	var raw rawPoolFile
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	items := make([]*PreParamsData, len(raw.PreParams))
+	errs := make([]error, len(raw.PreParams))
+	forEachParallel(len(items), "decoded", func(i int) {
+		errs[i] = json.Unmarshal(raw.PreParams[i], &items[i])
+	})
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+
+	return &poolFile{
+		PreParams:      items,
+		SavedAt:        raw.SavedAt,
+		TotalGenerated: raw.TotalGenerated,
+		TotalServed:    raw.TotalServed,
+		Pinned:         raw.Pinned,
+	}, nil
+}
+
+// verifyLoaded verifies every item on all CPUs, sealing items saved before
+// checksums were introduced and revalidating (and flagging) items for which
+// revalidate reports true. With full, standard items also get the full
+// primality validation and are raised to audited. Results are in item order.
+func verifyLoaded(items []*PreParamsData, revalidate func(*PreParamsData, time.Time) bool, full bool) []loadResult {
+	now := time.Now()
+	results := make([]loadResult, len(items))
+	forEachParallel(len(items), "verified", func(i int) {
+		class, err := verifyItem(items[i])
+		if err != nil {
+			results[i] = loadResult{class: class, err: err, source: "load"}
+			return
+		}
+		if revalidate(items[i], now) {
+			if err := revalidateImport(items[i]); err != nil {
+				results[i] = loadResult{class: FailureInvalid, err: err, source: "import"}
+				return
+			}
+			results[i].imported = true
+		}
+		if full && AssuranceOf(items[i]) != AssuranceAudited {
+			if err := auditItem(items[i]); err != nil {
+				results[i] = loadResult{class: FailureInvalid, err: err, source: "load"}
+				return
+			}
+			results[i].audited = true
+		}
+		if items[i].Checksum == "" {
+			sealItem(items[i])
+			results[i].sealed = true
+		}
+	})
+	return results
+}
+
+// forEachParallel calls fn for indexes 0..n-1 on GOMAXPROCS workers, logging
+// progress while it takes longer than loadProgressInterval
+func forEachParallel(n int, stage string, fn func(i int)) {
+	workers := min(runtime.GOMAXPROCS(0), n)
+	if workers == 0 {
+		return
+	}
+
+	var next, completed atomic.Int64
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(loadProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				log.Printf("Loading pool: %s %d/%d items", stage, completed.Load(), n)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				fn(i)
+				completed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+}
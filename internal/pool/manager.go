@@ -3,17 +3,25 @@ package pool
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"math/big"
 	"os"
 	"path/filepath"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/TEENet-io/prime-service/internal/affinity"
+	"github.com/TEENet-io/prime-service/internal/atrest"
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/errjournal"
 	"github.com/TEENet-io/prime-service/internal/generator"
+	"github.com/TEENet-io/prime-service/internal/scheduler"
+	"github.com/TEENet-io/prime-service/internal/shred"
+	"github.com/TEENet-io/prime-service/internal/trace"
 	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
 )
 
@@ -28,29 +36,137 @@ type PreParamsData struct {
 	P           *big.Int             `json:"p"` // safe prime for NTildei
 	Q           *big.Int             `json:"q"` // safe prime for NTildei
 	GeneratedAt time.Time            `json:"generated_at"`
+
+	// GenerationDuration is the wall time spent generating this set
+	GenerationDuration time.Duration `json:"generation_duration"`
+
+	// Provenance records where and in which burst the set was generated
+	Provenance Provenance `json:"provenance"`
+
+	// Checksum is the SHA-256 of the canonical encoding, verified at load and before serving
+	Checksum string `json:"checksum,omitempty"`
+
+	// Assurance is the validation level the set passed ("": standard)
+	Assurance string `json:"assurance,omitempty"`
 }
 
-// SimpleConfig contains configuration for the pool
-type SimpleConfig struct {
-	// Pool size limits
-	MinPoolSize     int `json:"min_pool_size"`    // Minimum items to maintain in pool
-	MaxPoolSize     int `json:"max_pool_size"`    // Maximum items in pool
-	RefillThreshold int `json:"refill_threshold"` // When to start refilling
+// Provenance identifies the generation context of an item. Items sharing a
+// host and burst were produced back to back and may share RNG failures.
+type Provenance struct {
+	Seq      uint64 `json:"seq,omitempty"`      // Sequence number in this pool, e.g. item 4182
+	Instance string `json:"instance,omitempty"` // Instance ID of the generating service
+	Host     string `json:"host,omitempty"`
+	Burst    string `json:"burst,omitempty"`  // Refill cycle or on-demand generation ID
+	Worker   int    `json:"worker,omitempty"` // Worker index within the burst
+
+	// Version of the generation code, see generator.CodeVersion
+	Version string `json:"version,omitempty"`
+
+	// Imported marks items from elsewhere that were fully revalidated on
+	// import for exceeding ImportRevalidateAge
+	Imported bool `json:"imported,omitempty"`
+
+	// AdminImport marks items put in by ImportItems, which are only served
+	// to requests allowing imported items
+	AdminImport bool `json:"admin_import,omitempty"`
+}
 
-	// Generation settings
-	PrimeBitSize    int `json:"prime_bit_size"`     // Bit size for safe primes (default: 1024)
-	PaillierBitSize int `json:"paillier_bit_size"` // Bit size for Paillier modulus (default: 2048)
-	MaxConcurrent   int `json:"max_concurrent"`   // Maximum concurrent parameter generation (default: 4)
+// ItemSource describes where a served parameter set came from
+type ItemSource int
 
-	// Persistence
-	PoolDir  string `json:"pool_dir"`  // Directory to store pool data
-	AutoSave bool   `json:"auto_save"` // Auto save pool to disk
+const (
+	// SourcePool means the item was taken from the pre-computed pool
+	SourcePool ItemSource = iota
+	// SourceGenerated means the item was generated on demand for the request
+	SourceGenerated
+)
 
-	// Background generation
-	BackgroundGen  bool          `json:"background_gen"`  // Enable background generation
-	RefillInterval time.Duration `json:"refill_interval"` // How often to check and refill
+// ServedParams is a parameter set handed out by GetPreParams together with
+// metadata about how it was provisioned
+type ServedParams struct {
+	*PreParamsData
+	Source   ItemSource
+	PoolAge  time.Duration // Time since generation when served from the pool
+	Replayed bool          // Returned again for a retried idempotency key
+	Stale    bool          // Older than MaxAge, served under the serve stale policy
+
+	// Generated in the same second as another item of a DistinctSeconds
+	// batch the pool could not spread
+	SharedSecond bool
+}
+
+// Request describes a GetPreParams call
+type Request struct {
+	Count uint32 // Number of items wanted (default 1)
+
+	// DistinctProvenance only returns items from different hosts/bursts (or
+	// generated at least AntiCorrelationWindow apart), e.g. for all parties
+	// of one DKG ceremony. Fewer items are returned if not enough qualify.
+	DistinctProvenance bool
+
+	// IdempotencyKey makes retries return the original allocation, even
+	// across server restarts, instead of consuming more items
+	IdempotencyKey string
+
+	// NoGenerate never generates synchronously, so the call returns at once;
+	// an empty result fails with ErrPoolEmpty
+	NoGenerate bool
+
+	// AllowImported also serves imported items demoted by ImportDemote and
+	// items put in by ImportItems
+	AllowImported bool
+
+	// MinAssurance only serves items of at least this assurance level ("":
+	// any); items generated on demand are audited to reach it
+	MinAssurance string
+
+	// DistinctSeconds spreads the batch across items generated in distinct
+	// seconds where the pool allows it, a cheaper alternative to
+	// DistinctProvenance that never returns fewer items. Items still sharing
+	// a second are flagged with SharedSecond.
+	DistinctSeconds bool
+
+	// OnServed, if set, receives items as soon as they are taken from the
+	// pool or generated, before the call returns them all, e.g. to stream
+	// them. Replayed items and DistinctSeconds batches, which are flagged
+	// once complete, are only returned.
+	OnServed func([]*ServedParams)
+
+	// OnQueued, if set, receives the request's place whenever it changes
+	// while it waits for an on-demand generation slot (MaxOnDemand), and a
+	// zero position once it starts generating
+	OnQueued func(QueueStatus)
+
+	// Namespace is the tenant the items are charged to ("": the default
+	// namespace); it must be one of the configured tenants
+	Namespace string
+
+	// Client identifies the caller, e.g. by the name of its API key ("":
+	// anonymous). An IdempotencyKey only replays allocations made for the
+	// same client and namespace.
+	Client string
 }
 
+// served passes newly allocated items to OnServed
+func (r Request) served(items ...*ServedParams) {
+	if r.OnServed != nil && !r.DistinctSeconds && len(items) > 0 {
+		r.OnServed(items)
+	}
+}
+
+// queued passes the request's queue position to OnQueued
+func (r Request) queued(s QueueStatus) {
+	if r.OnQueued != nil {
+		r.OnQueued(s)
+	}
+}
+
+// ErrPoolEmpty is returned for NoGenerate requests the pool cannot serve
+var ErrPoolEmpty = errors.New("pool has no parameters available")
+
+// SimpleConfig contains configuration for the pool
+type SimpleConfig = config.PoolConfig
+
 // Manager manages a pool of pre-generated cryptographic parameters
 type Manager struct {
 	mu        sync.RWMutex
@@ -58,78 +174,275 @@ type Manager struct {
 	generator *generator.Generator
 
 	// Pool storage
-	preParams []*PreParamsData `json:"pre_params"`
+	preParams []*PreParamsData
+	pinned    []*pinnedItem // Set aside by PinItem, not part of the pool
+
+	// Advances on every observable state change, see Version
+	version atomic.Uint64
+	waiters waitQueue // Woken on every version change
 
 	// Background generation
 	stopCh       chan struct{}
+	stopCtx      context.Context // Canceled with stopCh, for generation slots
+	cancelStop   context.CancelFunc
 	ticker       *time.Ticker
 	tickerMu     sync.Mutex
 	generatingMu sync.Mutex
-	isGenerating bool
+	isGenerating bool // Housekeeping refill or admin fill running
+	isEmergency  bool // Emergency refill running
+
+	// Refill triggers waiting for the running pass, see generationDemand
+	refillDemand    generationDemand
+	emergencyDemand generationDemand
+
+	// On-demand generations running and queued, see MaxOnDemand; they
+	// share the generator's slots with refills and the other pools
+	onDemand *scheduler.Group
+
+	// Running fills, checkpointed on Stop and resumed on the next start
+	fills fillTracker
+
+	// Latest finished fills, see RefillCycles
+	cycles cycleLog
 
 	// Save state
-	savingMu sync.Mutex
-	isSaving bool
+	savingMu    sync.Mutex
+	isSaving    bool
+	savePending bool // Requested while saving; the running save repeats
+
+	// Group commits of new items and the fsync policy
+	saveBatch saveBatch
+	syncer    fileSyncer
 
 	// File paths
 	poolFilePath string
 
+	// With redis storage, the pool shared by replicas (nil: local pool only)
+	shared *sharedPool
+
+	// At-rest encryption of the pool file, request journal and quarantined
+	// items (nil: plaintext); storageErr fails Start when the key cannot be
+	// loaded or the stored files cannot be decrypted with it
+	cipher     *atrest.Cipher
+	storageErr error
+
+	// Idempotency-key allocations
+	journal *requestJournal
+
+	// Recent generation and persistence errors
+	errors *errjournal.Journal
+
+	// Removals not yet reflected in the pool file, and the startup check
+	ledgerFile  ledgerFile
+	consistency ConsistencyReport
+
+	// Items that failed integrity verification
+	quarantineFile quarantineFile
+	quarantined    atomic.Int64
+
+	// Maintenance mode refuses new GetPreParams calls
+	maintenance    atomic.Bool
+	activeRequests atomic.Int32
+
+	// Pausing parks background generation at its next yield point
+	pause generationPause
+
+	// Anomaly freeze refuses GetPreParams calls until an admin unfreezes
+	frozen atomic.Bool
+	freeze freezeState
+
 	// Startup delay
 	startTime time.Time
 
+	// Instance ID and host name recorded in item provenance
+	instanceID string
+	hostname   string
+
+	// Sequence numbers of items entering the pool
+	seq seqCounter
+
+	// Audit entries for revalidated imports
+	importAudit importAudit
+
+	// Pulls from the upstream service (bootstrap seeding and proxying)
+	upstream UpstreamStats
+
 	// Statistics
 	totalGenerated int64
 	totalServed    int64
+	transferredIn  int64        // items received from peer replicas
+	transferredOut int64        // items handed to peer replicas
+	seeded         int64        // items received from the bootstrap upstream
+	firstBoot      bool         // the pool started empty, with nothing generated or served before
+	expired        int64        // items discarded for exceeding max age
+	staleServed    int64        // items served despite exceeding max age
+	deniedDropped  int64        // items discarded for a denied generator version
+	deniedServed   int64        // items served despite a denied generator version
+	cleared        int64        // items discarded by ClearPool
+	inFlight       atomic.Int32 // items currently being generated
+
+	// Served items split into pool hits and misses generated synchronously,
+	// with the time requests spent generating the misses
+	poolHits    int64
+	poolMisses  int64
+	missGenTime time.Duration
+
+	// Workers of running fills, for generation metrics
+	runs fillRuns
+
+	// Recent supply/consumption for pressure reporting
+	supplied eventWindow
+	consumed eventWindow
+
+	// Items served to clients, for the served-rate alarm
+	served eventWindow
+	alarms alarmState
+
+	// Embedder callbacks for generated and consumed items
+	hooks hookSet
+
+	// Hourly usage for forecasts
+	history usageHistory
+
+	// Panics recovered in request handlers, see RecordPanic
+	panics atomic.Int64
+
+	// Scheduled snapshots to SnapshotDir
+	snapshots snapshotState
+
+	// Items served per tenant namespace, and their quotas
+	tenants tenantAccounts
 }
 
 // NewManager creates a new pool manager
-func NewManager(gen *generator.Generator, config SimpleConfig) *Manager {
+func NewManager(gen *generator.Generator, cfg SimpleConfig) *Manager {
 	// Set defaults
-	if config.MinPoolSize == 0 {
-		config.MinPoolSize = 10
+	cfg.ApplyDefaults()
+
+	cipher, keyErr := loadCipher(&cfg)
+	pool := &Manager{
+		config:    &cfg,
+		generator: gen,
+		preParams: make([]*PreParamsData, 0),
+		stopCh:    make(chan struct{}),
+		journal:   newRequestJournal(dataPath(&cfg, "request_journal.json"), cfg.IdempotencyTTL, cfg.SecureDelete, cipher),
+		errors:    errjournal.New(dataPath(&cfg, "errors.json"), cfg.ErrorJournalSize),
+		startTime: time.Now(),
+		cipher:    cipher,
+		onDemand:  gen.Scheduler().NewGroup(cfg.MaxOnDemand),
 	}
-	if config.MaxPoolSize == 0 {
-		config.MaxPoolSize = 20
+	pool.stopCtx, pool.cancelStop = context.WithCancel(context.Background())
+	if keyErr != nil {
+		// Nothing is loaded, so nothing can be overwritten; Start fails
+		pool.storageErr = fmt.Errorf("failed to load pool encryption key: %w", keyErr)
+		log.Printf("%v", pool.storageErr)
+		return pool
 	}
-	if config.RefillThreshold == 0 {
-		config.RefillThreshold = 5
+	if err := pool.journal.load(); err != nil {
+		pool.storageErr = err
+		log.Printf("Failed to load request journal: %v", err)
+		return pool
 	}
-	if config.PrimeBitSize == 0 {
-		config.PrimeBitSize = 1024
+
+	// Memory storage leaves every path empty and never touches PoolDir;
+	// redis storage keeps everything but the pool itself there
+	if cfg.Storage != StorageMemory {
+		os.MkdirAll(filepath.Join(cfg.PoolDir, "pools"), 0755)
+		profilePath := poolFilePath(cfg.PoolDir, cfg.PrimeBitSize, cfg.PaillierBitSize)
+		if cfg.Storage == StorageFile {
+			pool.poolFilePath = profilePath
+			pool.ledgerFile.path = ledgerPath(profilePath)
+		}
+		pool.history.path = historyPath(profilePath)
+		pool.tenants.path = tenantsPath(profilePath)
+		pool.seq.path = seqPath(profilePath)
+		pool.fills.path = checkpointPath(profilePath)
+		pool.cycles.path = cyclesPath(profilePath)
 	}
-	if config.PaillierBitSize == 0 {
-		config.PaillierBitSize = 2048
+	pool.quarantineFile.path = dataPath(&cfg, "quarantine.jsonl")
+	pool.freeze.path = dataPath(&cfg, "freeze.json")
+	pool.configureFreeze(&cfg)
+	pool.loadFreeze()
+	pool.served.horizon = cfg.AlarmServedWindow
+	pool.tenants.configure(cfg.Tenants)
+
+	if host, err := os.Hostname(); err == nil {
+		pool.hostname = host
 	}
-	if config.MaxConcurrent == 0 {
-		config.MaxConcurrent = 4
+
+	instanceID, err := loadInstanceID(cfg.InstanceID, dataPath(&cfg, "instance_id"))
+	if err != nil {
+		// Fall back to an identity that is at least stable per host
+		log.Printf("Failed to load instance ID, using host name: %v", err)
+		pool.errors.Record(errjournal.SeverityWarning, "identity", err, nil)
+		instanceID = pool.hostname
 	}
-	if config.PoolDir == "" {
-		config.PoolDir = "./prime_pool"
+	pool.instanceID = instanceID
+	log.Printf("Instance ID: %s", instanceID)
+
+	if err := pool.seq.load(); err != nil {
+		// Items already in the pool file still push numbering past theirs
+		log.Printf("Failed to load item sequence, continuing after the pool's items: %v", err)
+		pool.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "seq", "file": pool.seq.path})
 	}
-	if config.RefillInterval == 0 {
-		config.RefillInterval = 30 * time.Second
+
+	if cfg.Storage == StorageMemory {
+		log.Printf("Memory storage: pool, journals and instance ID are not persisted")
+		return pool
 	}
 
-	// Ensure pool directory exists
-	os.MkdirAll(config.PoolDir, 0755)
+	if cfg.Storage == StorageRedis {
+		// The shared pool outlives any one replica, so none boots it first
+		if cipher == nil && (!cfg.RedisTLS || cfg.RedisPassword == "") {
+			err = fmt.Errorf("refusing to keep secrets in redis in plaintext: set an encryption key, or redis_tls with redis_password")
+		} else {
+			pool.shared, err = openShared(&cfg)
+		}
+		if err != nil {
+			pool.storageErr = err
+			log.Printf("%v", err)
+			return pool
+		}
+	} else {
+		// Import a pool file from before per-profile storage
+		if err := migrateLegacyPool(cfg.PoolDir, cfg.SecureDelete, cipher); err != nil {
+			log.Printf("Legacy pool migration failed, will retry on next start: %v", err)
+			pool.errors.Record(errjournal.SeverityError, "migration", err, nil)
+		}
+		pool.expireBackups()
 
-	pool := &Manager{
-		config:       &config,
-		generator:    gen,
-		preParams:    make([]*PreParamsData, 0),
-		stopCh:       make(chan struct{}),
-		poolFilePath: filepath.Join(config.PoolDir, "prime_pool.json"),
-		startTime:    time.Now(),
-	}
+		log.Printf("Pool saves: batches of %d items (delay: %s), fsync: %s", cfg.SaveBatchItems, cfg.SaveBatchDelay, cfg.Fsync)
 
-	// Load existing pool data
-	pool.loadFromDisk()
+		// Load existing pool data
+		pool.loadFromDisk()
+		if pool.storageErr != nil {
+			return pool
+		}
+		generated, served := pool.lifetimeTotalsLocked()
+		pool.firstBoot = len(pool.preParams) == 0 && generated == 0 && served == 0
+	}
+	if err := pool.loadCheckpoint(); err != nil {
+		log.Printf("Failed to load refill checkpoint, not resuming: %v", err)
+		pool.errors.Record(errjournal.SeverityWarning, "persistence", err, map[string]string{"op": "checkpoint", "file": pool.fills.path})
+	}
+	if err := pool.history.load(); err != nil {
+		log.Printf("Failed to load usage history, forecasts start afresh: %v", err)
+		pool.errors.Record(errjournal.SeverityWarning, "persistence", err, map[string]string{"op": "history", "file": pool.history.path})
+	}
+	if err := pool.cycles.load(); err != nil {
+		log.Printf("Failed to load refill cycles, starting afresh: %v", err)
+		pool.errors.Record(errjournal.SeverityWarning, "persistence", err, map[string]string{"op": "cycles", "file": pool.cycles.path})
+	}
+	pool.loadTenants()
 
 	return pool
 }
 
 // Start starts the pool manager
 func (m *Manager) Start(ctx context.Context) error {
+	if m.storageErr != nil {
+		return m.storageErr
+	}
 	log.Println("Starting prime pool manager...")
 
 	// Start background generation if enabled
@@ -137,8 +450,33 @@ func (m *Manager) Start(ctx context.Context) error {
 		go m.backgroundGeneration()
 	}
 
-	// Initial fill if pool is empty
-	if len(m.preParams) < m.config.RefillThreshold {
+	go m.alarmLoop()
+	go m.historyLoop()
+	go m.auditLoop()
+	if m.shared != nil {
+		go m.sharedLoop()
+	}
+
+	if m.poolFilePath != "" && m.config.BackupRetention > 0 {
+		go m.backupRetentionLoop()
+	}
+	if m.config.SnapshotDir != "" {
+		log.Printf("Writing pool snapshots to %s every %s (keeping %d)", m.config.SnapshotDir, m.config.SnapshotInterval, m.config.SnapshotKeep)
+		go m.snapshotLoop()
+	}
+
+	// Re-raise the alarm for a freeze that survived a restart
+	if f := m.Freeze(); f.Frozen {
+		m.setAlarm(AlarmFrozen, true, fmt.Sprintf("%s: %s", f.Anomaly, f.Reason))
+	}
+
+	// Items a version newly denied since the last run never reach a request
+	m.purgeDenied()
+
+	// Resume a fill interrupted by the last shutdown, or fill an empty pool
+	if m.fills.resumed != nil {
+		m.resumeCheckpoint()
+	} else if m.sizeLocked() < m.config.RefillThreshold {
 		go m.refillPool()
 	}
 
@@ -149,8 +487,11 @@ func (m *Manager) Start(ctx context.Context) error {
 func (m *Manager) Stop() {
 	log.Println("Stopping prime pool manager")
 
-	// Stop background generation
+	// Stop background generation; no fill starts after this
+	m.generatingMu.Lock()
 	close(m.stopCh)
+	m.cancelStop()
+	m.generatingMu.Unlock()
 
 	// Stop ticker
 	m.tickerMu.Lock()
@@ -160,57 +501,278 @@ func (m *Manager) Stop() {
 	}
 	m.tickerMu.Unlock()
 
-	// Save current state
-	m.saveToDisk()
+	// Record interrupted fills and save current state, syncing it
+	// regardless of FsyncInterval
+	m.writeCheckpoint()
+	m.saveToDisk(context.Background())
+	m.saveTenants()
+	if m.config.SnapshotDir != "" {
+		m.flushSnapshotRemovals(context.Background())
+	}
+	m.flushSyncs()
+	m.sampleHistory()
+	if m.shared != nil {
+		m.shared.client.Close()
+	}
 }
 
 // GetPreParams retrieves and consumes pre-computed parameters from the pool
 // Returns whatever is available in the pool (may be less than requested or even empty)
-func (m *Manager) GetPreParams(ctx context.Context, count uint32) ([]*PreParamsData, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+// unless on-demand generation is enabled, in which case the shortfall is generated synchronously
+func (m *Manager) GetPreParams(ctx context.Context, req Request) ([]*ServedParams, error) {
+	return m.serve(ctx, req, m.allocate)
+}
 
-	// Check if we need to trigger background refill
-	if len(m.preParams) <= m.config.RefillThreshold {
-		log.Printf("Prime pool running low (size: %d), triggering background generation", len(m.preParams))
-		go m.refillPool()
+// serve runs a request through maintenance and freeze checks and
+// idempotency-key replay and journaling, allocating new items with allocate
+func (m *Manager) serve(ctx context.Context, req Request, allocate func(ctx context.Context, count uint32, req Request) ([]*ServedParams, error)) ([]*ServedParams, error) {
+	done, err := m.beginRequest()
+	if err != nil {
+		return nil, err
 	}
+	defer done()
 
 	// Default count to 1 if not specified
+	count := req.Count
 	if count == 0 {
 		count = 1
 	}
 
-	result := make([]*PreParamsData, 0, count)
+	if req.IdempotencyKey == "" {
+		served, err := m.allocateForTenant(ctx, count, req, allocate)
+		m.noteSharedSeconds(ctx, req, served)
+		return served, err
+	}
+
+	// Replays are refused like new allocations to callers that may not
+	// use the namespace
+	if err := m.tenants.authorize(req.Namespace); err != nil {
+		trace.Logf(ctx, "Refused request for %d parameters: %v", count, err)
+		return nil, err
+	}
+	key := journalKey(req)
+	unlock := m.journal.lockKey(key)
+	defer unlock()
+
+	if served, ok := m.journal.lookup(key); ok {
+		for _, s := range served {
+			s.Replayed = true
+		}
+		trace.Logf(ctx, "Replaying %d parameters for idempotency key (requested: %d)", len(served), count)
+		m.noteSharedSeconds(ctx, req, served)
+		return served, nil
+	}
+
+	served, err := m.allocateForTenant(ctx, count, req, allocate)
+	m.noteSharedSeconds(ctx, req, served)
+	if len(served) > 0 {
+		// The items are already consumed, so hand them out even if journaling fails
+		if jerr := m.journal.record(key, count, served); jerr != nil {
+			trace.Logf(ctx, "Failed to journal idempotent allocation: %v", jerr)
+			m.errors.Record(errjournal.SeverityError, "idempotency", jerr, map[string]string{"count": fmt.Sprint(len(served)), "request_id": trace.ID(ctx)})
+		}
+	}
+	return served, err
+}
+
+// noteSharedSeconds flags the items of a DistinctSeconds batch that could
+// not be spread across distinct generation seconds
+func (m *Manager) noteSharedSeconds(ctx context.Context, req Request, served []*ServedParams) {
+	if !req.DistinctSeconds {
+		return
+	}
+	if shared := markSharedSeconds(served); shared > 0 {
+		trace.Logf(ctx, "Could not spread batch across generation seconds (%d of %d items share a second)", shared, len(served))
+	}
+}
+
+// allocate takes count items from the pool, generating any shortfall on
+// demand if enabled, and replacing items discarded for exceeding max age,
+// unless the request opted out of generation
+func (m *Manager) allocate(ctx context.Context, count uint32, req Request) (result []*ServedParams, err error) {
+	// Items are consumed even when generating the rest fails
+	defer func() { m.hookConsumed(ctx, result) }()
+
+	result, expired := m.takeFromPool(ctx, count, req, false)
+	req.served(result...)
+
+	if req.NoGenerate {
+		if len(result) == 0 {
+			return nil, ErrPoolEmpty
+		}
+		if len(result) < int(count) {
+			trace.Logf(ctx, "Only %d parameters available (requested: %d), not generating on request", len(result), count)
+		}
+		return result, nil
+	}
+
+	// Stale items are regenerated even without on-demand generation, so the
+	// request gets as many items as the pool held
+	regenerate := min(expired, int(count)-len(result))
+
+	// Generate the shortfall synchronously if enabled
+	for len(result) < int(count) && (m.config.OnDemandGen || regenerate > 0) {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		// Misses queue for MaxOnDemand slots, taking items refills add meanwhile
+		release, taken, err := m.awaitGeneration(ctx, int(count)-len(result), req, true)
+		result = append(result, taken...)
+		if err != nil {
+			trace.Logf(ctx, "Request ended while queued for on-demand generation (%d/%d ready): %v", len(result), count, err)
+			return result, err
+		}
+		if release == nil {
+			continue
+		}
+
+		genStart := time.Now()
+		params, err := m.generateForRequest(ctx, Provenance{
+			Instance: m.instanceID,
+			Host:     m.hostname,
+			Burst:    fmt.Sprintf("ondemand-%d", time.Now().UnixNano()),
+		}, m.config.PrimeBitSize, m.config.PaillierBitSize, release)
+		if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			trace.Logf(ctx, "Request ended while generating on demand (%d/%d ready): %v", len(result), count, err)
+			return result, err
+		}
+		if err != nil {
+			m.errors.Record(errjournal.SeverityError, "generator", err, map[string]string{"mode": "on-demand", "request_id": trace.ID(ctx)})
+			return result, err
+		}
+
+		if err := m.assure(ctx, params, req); err != nil {
+			m.errors.Record(errjournal.SeverityError, "generator", err, map[string]string{"mode": "on-demand", "request_id": trace.ID(ctx)})
+			return result, err
+		}
+
+		m.noteMiss(time.Since(genStart))
+		m.consumed.add(1)
+		m.hookGenerated(params)
+
+		regenerate--
+		served := &ServedParams{PreParamsData: params, Source: SourceGenerated}
+		result = append(result, served)
+		req.served(served)
+		trace.Logf(ctx, "Generated parameter set %d on demand (%d/%d, duration: %s)", params.Provenance.Seq, len(result), count, params.GenerationDuration)
+	}
+
+	// Note: without on-demand generation the client gets whatever is available
+	// (may be less than requested or empty)
+	if len(result) < int(count) {
+		trace.Logf(ctx, "Warning: Only %d parameters available (requested: %d). Background generation in progress.", len(result), count)
+	}
+
+	return result, nil
+}
+
+// noteMiss counts an item generated synchronously for a request, which
+// spent elapsed waiting for it, as served
+func (m *Manager) noteMiss(elapsed time.Duration) {
+	m.mu.Lock()
+	m.totalServed++
+	m.poolMisses++
+	m.missGenTime += elapsed
+	m.mu.Unlock()
+	m.changed()
+	m.served.add(1)
+}
+
+// takeFromPool removes up to count items from the pool (with all set,
+// count items or none), returning them and the number of items discarded
+// for exceeding max age
+func (m *Manager) takeFromPool(ctx context.Context, count uint32, req Request, all bool) ([]*ServedParams, int) {
+	if m.shared != nil {
+		m.mu.RLock()
+		short := int(count) - len(m.preParams)
+		m.mu.RUnlock()
+		if short > 0 {
+			m.popShared(ctx, short)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	before := len(m.preParams)
+	defer func() {
+		if len(m.preParams) != before {
+			m.changed()
+		}
+	}()
+
+	// A request the pool cannot satisfy triggers an emergency refill, a low
+	// pool a housekeeping one
+	if size := m.sizeLocked(); size < int(count) {
+		trace.Logf(ctx, "Prime pool cannot satisfy request (size: %d, requested: %d), triggering emergency generation", size, count)
+		go m.emergencyRefill()
+	} else if size <= m.config.RefillThreshold {
+		trace.Logf(ctx, "Prime pool running low (size: %d), triggering background generation", size)
+		go m.refillPool()
+	}
+
+	result := make([]*ServedParams, 0, count)
+	expired, stale := 0, 0
 
-	// Return whatever we have in the pool (may be less than requested)
+	// Return whatever we have in the pool (may be less than requested),
+	// or with all set nothing unless every item can be served
 	available := len(m.preParams)
-	if available > 0 {
+	if all && available < int(count) {
+		trace.Logf(ctx, "Prime pool cannot serve all %d parameters yet (size: %d)", count, available)
+	} else if available > 0 {
 		take := int(count)
 		if take > available {
 			take = available
 		}
-		result = m.preParams[:take]
-		m.preParams = m.preParams[take:]
-		log.Printf("Retrieved %d pre-computed parameters from pool (requested: %d, remaining: %d)", take, count, len(m.preParams))
+		now := time.Now()
+		var selected []*PreParamsData
+		selected, expired = m.selectLocked(ctx, take, req, all)
+		take = len(selected)
+		for _, params := range selected {
+			served := &ServedParams{
+				PreParamsData: params,
+				Source:        SourcePool,
+				PoolAge:       now.Sub(params.GeneratedAt),
+				Stale:         m.isStale(params, now),
+			}
+			if served.Stale {
+				stale++
+			}
+			result = append(result, served)
+		}
+		trace.Logf(ctx, "Retrieved %d pre-computed parameters from pool (requested: %d, remaining: %d, policy: %s, items: %v)", take, count, m.sizeLocked(), m.config.SelectionPolicy, itemSeqs(selected))
 	} else {
-		log.Printf("Prime pool is empty, returning 0 parameters (requested: %d)", count)
+		trace.Logf(ctx, "Prime pool is empty, returning 0 parameters (requested: %d)", count)
 	}
 
 	m.totalServed += int64(len(result))
-
-	// Note: We do NOT generate synchronously anymore
-	// Client will get whatever is available (may be less than requested or empty)
-	if len(result) < int(count) {
-		log.Printf("Warning: Only %d parameters available (requested: %d). Background generation in progress.", len(result), count)
+	m.poolHits += int64(len(result))
+	m.consumed.add(len(result))
+	m.served.add(len(result))
+	m.noteStaleLocked(ctx, expired, stale)
+
+	// Record served items before handing them out, so they are never served
+	// again from a pool file saved before this call
+	if len(result) > 0 {
+		removed := make([]*PreParamsData, len(result))
+		for i, served := range result {
+			removed[i] = served.PreParamsData
+		}
+		m.writeLedgerLocked(ctx, removed, false)
 	}
 
 	// Save updated pool if auto-save is enabled
-	if m.config.AutoSave {
-		go m.saveToDisk()
+	if m.config.AutoSave && (len(result) > 0 || expired > 0) {
+		go m.saveToDisk(ctx)
 	}
 
-	return result, nil
+	// Items taken from the shared pool but not served go back to it
+	if m.shared != nil {
+		m.noteUnsavedLocked(len(m.preParams))
+	}
+
+	return result, expired
 }
 
 // GetPoolStatus returns current pool statistics
@@ -218,34 +780,96 @@ func (m *Manager) GetPoolStatus() map[string]interface{} {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	var oldestGenTime time.Time
-	var newestGenTime time.Time
-
-	if len(m.preParams) > 0 {
-		oldestGenTime = m.preParams[0].GeneratedAt
-		newestGenTime = m.preParams[len(m.preParams)-1].GeneratedAt
-	}
+	ages := m.ageDistributionLocked(time.Now())
 
 	return map[string]interface{}{
-		"pool_size":        len(m.preParams),
-		"min_size":         m.config.MinPoolSize,
-		"max_size":         m.config.MaxPoolSize,
-		"refill_threshold": m.config.RefillThreshold,
-		"is_generating":    m.isGenerating,
-		"oldest_item":      oldestGenTime,
-		"newest_item":      newestGenTime,
-		"pool_file":        m.poolFilePath,
-		"total_generated":  m.totalGenerated,
-		"total_served":     m.totalServed,
+		"instance_id":       m.instanceID,
+		"pool_size":         m.sizeLocked(),
+		"min_size":          m.config.MinPoolSize,
+		"max_size":          m.config.MaxPoolSize,
+		"refill_threshold":  m.config.RefillThreshold,
+		"selection_policy":  m.config.SelectionPolicy,
+		"is_generating":     m.isGenerating || m.isEmergency,
+		"emergency_refill":  m.isEmergency,
+		"refill_pending":    m.refillDemand.pending + m.emergencyDemand.pending,
+		"pinned":            m.pinnedLocked(),
+		"in_flight":         int(m.inFlight.Load()),
+		"on_demand":         m.onDemandStatus(),
+		"phases":            m.generator.GetPhaseStatistics(),
+		"generation_cpus":   affinity.Format(m.generator.Affinity()),
+		"pin_failures":      m.generator.PinFailures(),
+		"handler_panics":    m.panics.Load(),
+		"max_concurrent":    m.config.MaxConcurrent,
+		"oldest_item":       ages.Oldest,
+		"newest_item":       ages.Newest,
+		"storage":           m.config.Storage,
+		"pool_file":         m.poolFilePath,
+		"encryption_key_id": m.EncryptionKeyID(),
+		"unsaved_items":     m.saveBatch.pending(),
+		"total_generated":   m.totalGenerated,
+		"total_served":      m.totalServed,
+		"pool_hits":         m.poolHits,
+		"pool_misses":       m.poolMisses,
+		"miss_gen_time":     m.missGenTime,
+		"transferred_in":    m.transferredIn,
+		"transferred_out":   m.transferredOut,
+		"seeded":            m.seeded,
+		"upstream":          m.upstreamStatsLocked(),
+		"shared":            m.SharedStats(),
+		"quarantined":       m.quarantined.Load(),
+		"imported":          m.importedLocked(),
+		"assurance":         m.assuranceLocked(),
+		"min_audited":       m.config.MinAudited,
+		"consistency":       m.consistency,
+		"alarms":            m.Alarms(),
+		"max_age":           m.config.MaxAge.String(),
+		"stale_policy":      m.config.StalePolicy,
+		"expired":           m.expired,
+		"stale_served":      m.staleServed,
+		"age_distribution":  ages,
+		"generator_version": generator.CodeVersion(),
+		"item_versions":     m.itemVersionsLocked(),
+		"denied_versions":   m.config.DeniedVersions,
+		"denied_policy":     m.config.DeniedVersionPolicy,
+		"denied_discarded":  m.deniedDropped,
+		"denied_served":     m.deniedServed,
+		"cleared":           m.cleared,
+		"snapshots":         m.SnapshotStatus(),
+		"tenants":           m.TenantUsage(),
+		"maintenance":       m.maintenance.Load(),
+		"generation_paused": m.pause.wait() != nil,
+		"generation_parked": int(m.pause.parked.Load()),
+		"active_requests":   int(m.activeRequests.Load()),
+		"freeze":            m.Freeze(),
+		"version":           m.Version(),
 	}
 }
 
+// Errors returns the journal of recent generation and persistence errors,
+// which other components may also record into
+func (m *Manager) Errors() *errjournal.Journal {
+	return m.errors
+}
+
 // generateSinglePreParams generates a single set of pre-computed parameters
-func (m *Manager) generateSinglePreParams() (*PreParamsData, error) {
+// on the generation slot of job
+func (m *Manager) generateSinglePreParams(prov Provenance, job *scheduler.Job) (*PreParamsData, error) {
+	return m.generateSized(prov, m.config.PrimeBitSize, m.config.PaillierBitSize, func() error { return m.backgroundYield(job) })
+}
+
+// generateSized generates one item at the given bit sizes, calling yield
+// every few candidates of its prime searches
+func (m *Manager) generateSized(prov Provenance, primeBits, paillierBits int, yield generator.YieldFunc) (*PreParamsData, error) {
 	start := time.Now()
 	log.Println("Generating single pre-computed parameters")
 
-	params, err := m.generator.GeneratePreParams(m.config.PrimeBitSize, m.config.PaillierBitSize)
+	m.inFlight.Add(1)
+	defer m.inFlight.Add(-1)
+
+	params, err := m.generator.GeneratePreParamsYielding(primeBits, paillierBits, yield)
+	if errors.Is(err, errGenerationStopped) {
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate parameters: %w", err)
 	}
@@ -253,9 +877,11 @@ func (m *Manager) generateSinglePreParams() (*PreParamsData, error) {
 	elapsed := time.Since(start)
 	log.Printf("Generated single pre-computed parameters (duration: %s)", elapsed)
 
+	m.mu.Lock()
 	m.totalGenerated++
+	m.mu.Unlock()
 
-	return &PreParamsData{
+	item := &PreParamsData{
 		PaillierKey: params.PaillierKey,
 		NTildei:     params.NTildei,
 		H1i:         params.H1i,
@@ -265,103 +891,379 @@ func (m *Manager) generateSinglePreParams() (*PreParamsData, error) {
 		P:           params.P,
 		Q:           params.Q,
 		GeneratedAt: params.GeneratedAt,
-	}, nil
+
+		GenerationDuration: params.GenerationDuration,
+		Provenance:         prov,
+	}
+	item.Provenance.Version = generator.CodeVersion()
+	sealItem(item)
+	m.assignSeq(item)
+	return item, nil
+}
+
+// generateForRequest generates one item at the given bit sizes for a
+// request, returning ctx's error once it ends. An item of the pool's sizes
+// finished after that goes to the pool, so the work is not lost. release
+// returns the on-demand slot once generation ends either way.
+func (m *Manager) generateForRequest(ctx context.Context, prov Provenance, primeBits, paillierBits int, release func()) (*PreParamsData, error) {
+	type outcome struct {
+		params *PreParamsData
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		params, err := m.generateSized(prov, primeBits, paillierBits, m.requestYield)
+		// The slot is held until generation ends, even for an ended request
+		release()
+		done <- outcome{params, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.params, o.err
+	case <-ctx.Done():
+		go func() {
+			if o := <-done; o.err == nil && primeBits == m.config.PrimeBitSize && paillierBits == m.config.PaillierBitSize {
+				m.keepAbandoned(ctx, o.params)
+			}
+		}()
+		return nil, ctx.Err()
+	}
 }
 
-// refillPool fills the pool to minimum size
+// keepAbandoned adds an item generated for a request that ended meanwhile
+// to the pool, if there is room
+func (m *Manager) keepAbandoned(ctx context.Context, item *PreParamsData) {
+	m.mu.Lock()
+	if m.duplicateLocked(item) {
+		m.mu.Unlock()
+		m.ReportAnomaly(AnomalyDuplicate, fmt.Errorf("generated parameter set repeats one in the pool (burst: %s)", item.Provenance.Burst))
+		return
+	}
+	if m.sizeLocked() >= m.config.MaxPoolSize {
+		m.mu.Unlock()
+		trace.Logf(ctx, "Discarding parameter set generated for an ended request, pool is full")
+		return
+	}
+	m.preParams = append(m.preParams, item)
+	m.supplied.add(1)
+	m.changed()
+	m.hookGenerated(item)
+	m.noteUnsavedLocked(1)
+	size := m.sizeLocked()
+	m.mu.Unlock()
+
+	trace.Logf(ctx, "Added parameter set generated for an ended request to the pool (pool size: %d)", size)
+}
+
+// generationDemand counts refill triggers of one kind. A trigger arriving
+// while a pass of its kind runs is not dropped: once the pass ends, one more
+// pass serves every trigger counted meanwhile. Each trigger is thus followed
+// by exactly one complete pass started after it, and concurrent triggers share
+// that pass instead of generating twice. Guarded by Manager.generatingMu.
+type generationDemand struct {
+	pending  int  // Triggers not yet served by a started pass
+	deferred bool // A trigger waits for the startup delay to end
+}
+
+// refillPool fills the pool to minimum size at housekeeping concurrency and
+// throttle. Triggers during a running refill or fill are merged into one more
+// refill after it.
 func (m *Manager) refillPool() {
-	// Check if still in startup delay period (10 seconds for testing)
-	if time.Since(m.startTime) < 10*time.Second {
-		log.Println("Skipping prime generation during startup delay")
+	if m.deferForStartup(&m.refillDemand, m.refillPool) {
 		return
 	}
+	if !m.trigger(&m.refillDemand, &m.isGenerating) {
+		return
+	}
+	m.runRefills()
+}
 
-	m.generatingMu.Lock()
-	if m.isGenerating {
-		m.generatingMu.Unlock()
+// runRefills runs housekeeping refills until no trigger is pending, then
+// releases the generation slot. The caller must hold the slot.
+func (m *Manager) runRefills() {
+	m.runPasses(&m.refillDemand, &m.isGenerating, "refill", func() {
+		// Use limited concurrent generation to avoid CPU overload
+		maxConcurrent := m.effectiveConcurrency()
+		if maxConcurrent < m.config.MaxConcurrent {
+			log.Println("Limiting prime generation to 1 concurrent worker for CPU-limited system")
+		}
+		m.fill(m.config.MinPoolSize, maxConcurrent, m.config.GenerationThrottle, "refill")
+	})
+}
+
+// emergencyRefill fills the pool to minimum size after a request miss, on
+// EmergencyConcurrent workers (one per CPU by default) with EmergencyThrottle.
+// It runs alongside a housekeeping refill already in progress, whose workers
+// keep generating at their own pace. Misses during an emergency refill are
+// merged into one more emergency refill after it.
+func (m *Manager) emergencyRefill() {
+	if m.deferForStartup(&m.emergencyDemand, m.emergencyRefill) {
 		return
 	}
-	m.isGenerating = true
-	m.generatingMu.Unlock()
+	if !m.trigger(&m.emergencyDemand, &m.isEmergency) {
+		return
+	}
+	m.runPasses(&m.emergencyDemand, &m.isEmergency, "emergency refill", func() {
+		m.mu.RLock()
+		target := m.config.MinPoolSize
+		workers := m.config.EmergencyConcurrent
+		throttle := m.config.EmergencyThrottle
+		m.mu.RUnlock()
+
+		if workers <= 0 {
+			workers = runtime.NumCPU()
+		}
+		m.fill(target, workers, throttle, "emergency")
+	})
+}
 
-	defer func() {
+// trigger counts a trigger of d and claims running if no pass runs, returning
+// whether the caller now runs the passes
+func (m *Manager) trigger(d *generationDemand, running *bool) bool {
+	m.generatingMu.Lock()
+	defer m.generatingMu.Unlock()
+	d.pending++
+	if *running {
+		return false
+	}
+	*running = true
+	m.changed()
+	return true
+}
+
+// runPasses runs pass until no trigger of d is pending or the manager stops,
+// then clears running
+func (m *Manager) runPasses(d *generationDemand, running *bool, kind string, pass func()) {
+	for {
 		m.generatingMu.Lock()
-		m.isGenerating = false
+		merged := d.pending
+		d.pending = 0
+		if merged == 0 || m.stopping() {
+			*running = false
+			m.generatingMu.Unlock()
+			m.changed()
+			return
+		}
 		m.generatingMu.Unlock()
-	}()
+
+		if merged > 1 {
+			log.Printf("Pool %s serving %d merged triggers", kind, merged)
+		}
+		pass()
+	}
+}
+
+// deferForStartup postpones a trigger arriving during the startup delay until
+// the delay ends, returning whether it did. Triggers deferred together run
+// once.
+func (m *Manager) deferForStartup(d *generationDemand, retrigger func()) bool {
+	wait := m.config.StartupDelay - time.Since(m.startTime)
+	if wait <= 0 {
+		return false
+	}
+	m.generatingMu.Lock()
+	defer m.generatingMu.Unlock()
+	if !d.deferred {
+		d.deferred = true
+		log.Printf("Deferring prime generation until the startup delay ends (in %s)", wait.Round(time.Second))
+		time.AfterFunc(wait, func() {
+			m.generatingMu.Lock()
+			d.deferred = false
+			m.generatingMu.Unlock()
+			if !m.stopping() {
+				retrigger()
+			}
+		})
+	}
+	return true
+}
+
+// stopping reports whether Stop was called
+func (m *Manager) stopping() bool {
+	select {
+	case <-m.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// beginGeneration claims the single generation slot, returning false if a
+// refill or fill is already running
+func (m *Manager) beginGeneration() bool {
+	m.generatingMu.Lock()
+	defer m.generatingMu.Unlock()
+	if m.isGenerating {
+		return false
+	}
+	m.isGenerating = true
+	m.changed()
+	return true
+}
+
+// endGeneration releases the generation slot, after running a refill first if
+// refills were triggered while it was held
+func (m *Manager) endGeneration() {
+	m.runRefills()
+}
+
+// A fill worker retries a failed item after itemRetryDelay and stops after
+// maxItemAttempts failures in a row. Other workers are unaffected, so one
+// failed item (e.g. a safe prime search timing out) does not end the fill.
+const (
+	maxItemAttempts = 3
+	itemRetryDelay  = time.Second
+)
+
+// fill generates items on maxConcurrent workers until the pool holds target
+// items. mode names the run in logs, burst IDs and the error journal.
+// Caller must hold the generation slot.
+func (m *Manager) fill(target, maxConcurrent int, throttle time.Duration, mode string) {
+	if !m.beginFill() {
+		return
+	}
+	defer m.endFill(mode, target)
 
 	m.mu.RLock()
-	currentSize := len(m.preParams)
+	currentSize := m.sizeLocked()
 	m.mu.RUnlock()
 
-	if currentSize >= m.config.MinPoolSize {
+	if currentSize >= target {
 		return
 	}
 
-	needed := m.config.MinPoolSize - currentSize
-	log.Printf("Starting pool refill (current: %d, needed: %d, min: %d)",
-		currentSize, needed, m.config.MinPoolSize)
+	needed := target - currentSize
+	log.Printf("Starting pool %s (current: %d, needed: %d, target: %d, workers: %d)",
+		mode, currentSize, needed, target, maxConcurrent)
 
 	start := time.Now()
-	generated := 0
+	generated, failures := 0, 0
 
-	// Use limited concurrent generation to avoid CPU overload
-	maxConcurrent := m.config.MaxConcurrent
 	if maxConcurrent <= 0 {
-		maxConcurrent = 1 // Default to single thread for CPU-limited systems
-	}
-	// For 3 CPU cores, limit to 1 concurrent generation to leave resources for other tasks
-	if maxConcurrent > 1 && runtime.NumCPU() <= 3 {
 		maxConcurrent = 1
-		log.Println("Limiting prime generation to 1 concurrent worker for CPU-limited system")
 	}
 	if needed < maxConcurrent {
 		maxConcurrent = needed
 	}
 
+	defer func() {
+		m.recordCycle(RefillCycle{
+			Started:     start,
+			Duration:    time.Since(start),
+			Mode:        mode,
+			Reason:      fillReasons[mode],
+			Target:      target,
+			PoolSize:    currentSize,
+			Needed:      needed,
+			Generated:   generated,
+			Failures:    failures,
+			Workers:     maxConcurrent,
+			Interrupted: m.stopping() && generated < needed,
+		})
+	}()
+
+	// Emergency refills get generation slots ahead of other refills and
+	// fills, requests ahead of both
+	priority := scheduler.Background
+	if mode == "emergency" {
+		priority = scheduler.Emergency
+	}
+
 	// Channel to collect generated parameters
 	paramsCh := make(chan *PreParamsData, needed)
 	errorCh := make(chan error, needed)
 
 	// WaitGroup to track concurrent generation
 	var genWg sync.WaitGroup
+	var claimed atomic.Int32
+
+	// All items of this run share a burst ID for anti-correlation
+	burst := fmt.Sprintf("%s-%d", mode, start.UnixNano())
+
+	run := m.runs.start(mode, burst, maxConcurrent, func() int {
+		return max(needed-int(claimed.Load()), 0)
+	})
+	defer m.runs.end(run)
 
 	// Start concurrent parameter generation with semaphore control
 	for i := 0; i < maxConcurrent; i++ {
 		genWg.Add(1)
-		go func() {
+		go func(worker int) {
 			defer genWg.Done()
+			defer m.runs.exit(run, worker)
 
 			// Lower the priority of this goroutine to reduce impact on other tasks
 			runtime.LockOSThread()
 			defer runtime.UnlockOSThread()
 
+			attempts := 0 // Failed in a row
 			for {
-				select {
-				case <-m.stopCh:
+				// Start no new item while generation is paused
+				if m.backgroundYield(nil) != nil {
+					return
+				}
+
+				// Wait for a generation slot, which requests and other
+				// pools draw on as well
+				job := m.generator.Scheduler().Enqueue(priority, nil)
+				if job.Wait(m.stopCtx) != nil {
+					job.Done()
 					return
-				default:
 				}
 
-				// Check if we have enough parameters
+				// Check if we have enough parameters, counting items other
+				// runs are generating, and claim one of this run's items
 				m.mu.RLock()
-				currentSize := len(m.preParams)
+				currentSize := m.sizeLocked()
 				m.mu.RUnlock()
 
-				if currentSize >= m.config.MinPoolSize {
+				if currentSize+int(m.inFlight.Load()) >= target || claimed.Add(1) > int32(needed) {
+					job.Done()
 					return // Pool has enough parameters
 				}
 
-				params, err := m.generateSinglePreParams()
+				m.runs.setJob(run, worker, time.Now())
+				params, err := m.generateSinglePreParams(Provenance{Instance: m.instanceID, Host: m.hostname, Burst: burst, Worker: worker}, job)
+				job.Done()
+				m.runs.setJob(run, worker, time.Time{})
 
-				if err != nil {
-					errorCh <- err
+				if errors.Is(err, errGenerationStopped) {
+					claimed.Add(-1)
 					return
 				}
-
-				// Add significant delay to minimize CPU impact
-				// 1s delay ensures prime generation has minimal impact on other tasks
-				time.Sleep(1 * time.Second)
+				if err != nil {
+					// Release the item for another attempt; only this
+					// worker backs off, and it gives up after
+					// maxItemAttempts failures in a row
+					claimed.Add(-1)
+					attempts++
+					select {
+					case errorCh <- err:
+					case <-m.stopCh:
+						return
+					}
+					if attempts >= maxItemAttempts {
+						log.Printf("Pool %s worker %d giving up after %d failures in a row", mode, worker, attempts)
+						return
+					}
+					select {
+					case <-time.After(itemRetryDelay):
+					case <-m.stopCh:
+						return
+					}
+					continue
+				}
+				attempts = 0
+
+				// Throttle between items to minimize CPU impact on other tasks
+				if throttle > 0 {
+					select {
+					case <-time.After(throttle):
+					case <-m.stopCh:
+						return
+					}
+				}
 
 				select {
 				case paramsCh <- params:
@@ -369,7 +1271,7 @@ func (m *Manager) refillPool() {
 					return
 				}
 			}
-		}()
+		}(i)
 	}
 
 	// Goroutine to close channels when generation is done
@@ -385,11 +1287,15 @@ func (m *Manager) refillPool() {
 		case <-m.stopCh:
 			log.Println("Pool generation stopped")
 			return
-		case err := <-errorCh:
-			if err != nil {
-				log.Printf("Failed to generate parameters during concurrent refill: %v", err)
-				return // Stop generation on error
+		case err, ok := <-errorCh:
+			if !ok {
+				errorCh = nil
+				continue
 			}
+			// The failed item is retried; other workers carry on
+			failures++
+			log.Printf("Failed to generate parameters during pool %s: %v", mode, err)
+			m.errors.Record(errjournal.SeverityError, "generator", err, map[string]string{"mode": mode, "burst": burst})
 		case preParamsData, ok := <-paramsCh:
 			if !ok {
 				// Channel closed, generation complete
@@ -397,17 +1303,20 @@ func (m *Manager) refillPool() {
 			}
 
 			m.mu.Lock()
-			if len(m.preParams) < m.config.MaxPoolSize {
+			if m.duplicateLocked(preParamsData) {
+				m.mu.Unlock()
+				m.ReportAnomaly(AnomalyDuplicate, fmt.Errorf("generated parameter set repeats one in the pool (burst: %s)", burst))
+			} else if m.sizeLocked() < m.config.MaxPoolSize {
 				m.preParams = append(m.preParams, preParamsData)
+				m.supplied.add(1)
+				m.changed()
+				m.hookGenerated(preParamsData)
+				m.noteUnsavedLocked(1)
 				generated++
-				currentSize := len(m.preParams)
+				currentSize := m.sizeLocked()
 				m.mu.Unlock()
 
-				log.Printf("Generated parameter set %d/%d (pool size: %d)", generated, needed, currentSize)
-
-				if m.config.AutoSave {
-					go m.saveToDisk()
-				}
+				log.Printf("Generated parameter set %d/%d (item %d, pool size: %d)", generated, needed, preParamsData.Provenance.Seq, currentSize)
 
 				// Continue collecting until all goroutines are done
 			} else {
@@ -419,12 +1328,12 @@ func (m *Manager) refillPool() {
 
 done:
 	elapsed := time.Since(start)
-	log.Printf("Pool refill completed (generated: %d, duration: %s, avg: %s)",
-		generated, elapsed, elapsed/time.Duration(generated))
+	log.Printf("Pool %s completed (generated: %d, failed: %d, duration: %s, avg: %s)",
+		mode, generated, failures, elapsed, elapsed/time.Duration(max(generated, 1)))
 
 	// Save updated pool
 	if m.config.AutoSave {
-		m.saveToDisk()
+		m.saveToDisk(context.Background())
 	}
 }
 
@@ -448,8 +1357,11 @@ func (m *Manager) backgroundGeneration() {
 	for {
 		select {
 		case <-m.ticker.C:
+			m.expireStale()
+			m.purgeDenied()
+
 			m.mu.RLock()
-			currentSize := len(m.preParams)
+			currentSize := m.sizeLocked()
 			m.mu.RUnlock()
 
 			if currentSize <= m.config.RefillThreshold {
@@ -464,87 +1376,185 @@ func (m *Manager) backgroundGeneration() {
 	}
 }
 
-// saveToDisk saves the pool to disk
-func (m *Manager) saveToDisk() {
+// saveToDisk saves the pool to disk; ctx only carries the trace ID of the
+// request that triggered the save
+func (m *Manager) saveToDisk(ctx context.Context) {
+	if m.poolFilePath == "" && m.shared == nil {
+		return // memory storage
+	}
+
 	m.savingMu.Lock()
 	if m.isSaving {
+		m.savePending = true // Picked up by the running save
 		m.savingMu.Unlock()
-		return // Already saving, skip this call
+		return
 	}
 	m.isSaving = true
 	m.savingMu.Unlock()
 
-	defer func() {
+	for {
+		if m.shared != nil {
+			m.pushShared(ctx)
+		} else {
+			m.writePoolFile(ctx)
+		}
+
 		m.savingMu.Lock()
-		m.isSaving = false
+		if !m.savePending {
+			m.isSaving = false
+			m.savingMu.Unlock()
+			return
+		}
+		m.savePending = false
 		m.savingMu.Unlock()
-	}()
+	}
+}
 
+// writePoolFile writes the pool file and resets the ledger. Only taking
+// the items holds m.mu; requests are served while the file is encoded,
+// written and read back.
+func (m *Manager) writePoolFile(ctx context.Context) {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	data := struct {
-		PreParams []*PreParamsData `json:"pre_params"`
-		SavedAt   time.Time        `json:"saved_at"`
-		Config    *SimpleConfig    `json:"config"`
-	}{
-		PreParams: m.preParams,
+	m.saveBatch.startedLocked()
+	cfg := *m.config
+	cfg.EncryptionKey = "" // Never stored next to what it protects
+	data := poolFile{
+		PreParams: make([]*PreParamsData, len(m.preParams)),
 		SavedAt:   time.Now(),
-		Config:    m.config,
+		Config:    &cfg,
+		Pinned:    append([]*pinnedItem(nil), m.pinned...),
+	}
+	for i, item := range m.preParams {
+		c := *item
+		data.PreParams[i] = &c
 	}
+	data.TotalGenerated, data.TotalServed = m.lifetimeTotalsLocked()
+	m.markSavingLocked()
+	m.mu.RUnlock()
 
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		log.Printf("Failed to marshal pool data: %v", err)
+		trace.Logf(ctx, "Failed to marshal pool data: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "marshal"})
+		return
+	}
+	if jsonData, err = m.cipher.Seal(jsonData, sealPool); err != nil {
+		trace.Logf(ctx, "Failed to encrypt pool data: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "encrypt", "file": m.poolFilePath})
 		return
 	}
 
-	if err := ioutil.WriteFile(m.poolFilePath, jsonData, 0600); err != nil {
-		log.Printf("Failed to save pool to disk: %v", err)
+	err = m.replacePoolFile(jsonData, len(data.PreParams))
+	if errors.Is(err, shred.ErrNotOverwritten) {
+		// The new pool file is in place; only the superseded backup may linger
+		trace.Logf(ctx, "Pool saved, but %v", err)
+		m.errors.Record(errjournal.SeverityWarning, "persistence", err, map[string]string{"op": "shred", "file": m.poolFilePath})
+	} else if err != nil {
+		trace.Logf(ctx, "Failed to save pool to disk: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "save", "file": m.poolFilePath})
 		return
 	}
+	m.syncWritten(ctx, m.poolFilePath)
+	m.mu.RLock()
+	m.writeLedgerLocked(ctx, nil, true)
+	m.mu.RUnlock()
 
-	log.Printf("Pool saved to disk (file: %s, size: %d)", m.poolFilePath, len(m.preParams))
+	trace.Logf(ctx, "Pool saved to disk (file: %s, size: %d)", m.poolFilePath, len(data.PreParams))
 }
 
-// loadFromDisk loads the pool from disk
+// loadFromDisk loads the pool from disk. If the pool file is missing or
+// unreadable, the backup of the previous save is loaded instead and the
+// unreadable file is set aside; a key that cannot decrypt the file fails
+// startup rather than replacing the pool.
 func (m *Manager) loadFromDisk() {
-	if _, err := os.Stat(m.poolFilePath); os.IsNotExist(err) {
-		log.Printf("Pool file does not exist, starting with empty pool: %s", m.poolFilePath)
-		return
-	}
+	start := time.Now()
+	m.removeInterruptedSave()
 
-	data, err := ioutil.ReadFile(m.poolFilePath)
-	if err != nil {
-		log.Printf("Failed to read pool file: %v", err)
+	poolData, plaintext, err := m.readPoolFile(m.poolFilePath)
+	if errors.Is(err, atrest.ErrNoKey) || errors.Is(err, atrest.ErrWrongKey) || errors.Is(err, atrest.ErrPlaintext) {
+		m.storageErr = fmt.Errorf("failed to decrypt pool file: %w", err)
+		log.Printf("%v", m.storageErr)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "decrypt", "file": m.poolFilePath})
 		return
 	}
 
-	var poolData struct {
-		PreParams []*PreParamsData `json:"pre_params"`
-		SavedAt   time.Time        `json:"saved_at"`
-		Config    *SimpleConfig    `json:"config"`
-	}
-
-	if err := json.Unmarshal(data, &poolData); err != nil {
-		log.Printf("Failed to unmarshal pool data: %v", err)
-		return
+	recovered := false
+	if err != nil {
+		backup := poolBackupPath(m.poolFilePath)
+		backupData, backupPlaintext, backupErr := m.readPoolFile(backup)
+		if errors.Is(err, os.ErrNotExist) && errors.Is(backupErr, os.ErrNotExist) {
+			log.Printf("Pool file does not exist, starting with empty pool: %s", m.poolFilePath)
+			m.checkConsistency(&poolFile{}, false)
+			return
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("Failed to load pool file: %v", err)
+			m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "load", "file": m.poolFilePath})
+			setAside(m.poolFilePath)
+		}
+		if backupErr != nil {
+			if !errors.Is(backupErr, os.ErrNotExist) {
+				log.Printf("Failed to load pool backup: %v", backupErr)
+				m.errors.Record(errjournal.SeverityError, "persistence", backupErr, map[string]string{"op": "load", "file": backup})
+			}
+			log.Printf("No readable pool file or backup, starting with empty pool: %s", m.poolFilePath)
+			return
+		}
+		log.Printf("Recovered pool from backup %s (saved: %s, items: %d)", backup, backupData.SavedAt, len(backupData.PreParams))
+		m.errors.Record(errjournal.SeverityWarning, "persistence", fmt.Errorf("pool recovered from backup saved at %s", backupData.SavedAt.Format(time.RFC3339)), map[string]string{"op": "recover", "file": backup})
+		poolData, plaintext, recovered = backupData, backupPlaintext, true
 	}
 
-	m.preParams = poolData.PreParams
-	if m.preParams == nil {
-		m.preParams = make([]*PreParamsData, 0)
+	// Drop items already served before an unsaved shutdown
+	m.checkConsistency(poolData, recovered)
+
+	// Verify every item in parallel, quarantining corrupt or inconsistent
+	// ones (including, with VerifyPrimalityOnLoad, items whose primes are
+	// not safe primes) and sealing items saved before checksums were
+	// introduced
+	results := verifyLoaded(poolData.PreParams, m.needsRevalidation, m.config.VerifyPrimalityOnLoad)
+	validParams := make([]*PreParamsData, 0, len(poolData.PreParams))
+	sealed, imported, audited := 0, 0, 0
+	for i, param := range poolData.PreParams {
+		if results[i].err != nil {
+			m.quarantine(context.Background(), param, results[i].class, results[i].err, results[i].source)
+			continue
+		}
+		if results[i].sealed {
+			sealed++
+		}
+		if results[i].imported {
+			imported++
+		}
+		if results[i].audited {
+			audited++
+		}
+		validParams = append(validParams, param)
 	}
-
-	// Remove any nil entries (data corruption protection)
-	validParams := make([]*PreParamsData, 0, len(m.preParams))
-	for _, param := range m.preParams {
-		if param != nil && param.PaillierKey != nil {
-			validParams = append(validParams, param)
+	m.auditImport(ImportSourceRestore, imported)
+	if dups := findDuplicates(validParams); len(dups) > 0 {
+		for i := len(dups) - 1; i >= 0; i-- {
+			validParams = append(validParams[:dups[i]], validParams[dups[i]+1:]...)
 		}
+		m.ReportAnomaly(AnomalyDuplicate, fmt.Errorf("pool file holds %d parameter sets repeating earlier ones (dropped)", len(dups)))
 	}
 	m.preParams = validParams
+	m.pinned = poolData.Pinned
+	numbered := m.numberLoaded()
+
+	// Persist the imported flags, audited levels and new sequence numbers,
+	// so the items are not revalidated or renumbered again, encrypt a plaintext file and
+	// replace a pool file recovered from the backup
+	if numbered > 0 {
+		log.Printf("Numbered %d items saved without a sequence number", numbered)
+	}
+	if plaintext {
+		log.Printf("Encrypting pool file saved in plaintext: %s", m.poolFilePath)
+	}
+	if imported > 0 || audited > 0 || numbered > 0 || plaintext || recovered {
+		m.saveToDisk(context.Background())
+	}
 
-	log.Printf("Pool loaded from disk (file: %s, size: %d, saved: %s)",
-		m.poolFilePath, len(m.preParams), poolData.SavedAt)
+	log.Printf("Pool loaded from disk (file: %s, size: %d, sealed: %d, audited: %d, quarantined: %d, saved: %s, took: %s)",
+		m.poolFilePath, len(m.preParams), sealed, audited, m.quarantined.Load(), poolData.SavedAt, time.Since(start).Round(time.Millisecond))
 }
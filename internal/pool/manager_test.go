@@ -0,0 +1,92 @@
+package pool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/generator"
+)
+
+// Test items use small bit sizes so they generate in well under a second
+const (
+	testPrimeBits    = 256
+	testPaillierBits = 512
+	testItemCount    = 3
+)
+
+var (
+	testItemsOnce sync.Once
+	testItemsData []*PreParamsData
+	testItemsErr  error
+)
+
+// testItems returns testItemCount distinct valid items, generated once per
+// test binary. Each call returns fresh copies, generated a minute apart in
+// pool order, so tests may change and serve them.
+func testItems(t *testing.T) []*PreParamsData {
+	t.Helper()
+	testItemsOnce.Do(func() {
+		gen := generator.NewGenerator()
+		base := time.Now().Add(-time.Hour).Truncate(time.Second)
+		for i := 0; i < testItemCount; i++ {
+			p, err := gen.GeneratePreParams(testPrimeBits, testPaillierBits)
+			if err != nil {
+				testItemsErr = err
+				return
+			}
+			testItemsData = append(testItemsData, &PreParamsData{
+				PaillierKey: p.PaillierKey,
+				NTildei:     p.NTildei,
+				H1i:         p.H1i,
+				H2i:         p.H2i,
+				Alpha:       p.Alpha,
+				Beta:        p.Beta,
+				P:           p.P,
+				Q:           p.Q,
+				GeneratedAt: base.Add(time.Duration(i) * time.Minute),
+			})
+		}
+	})
+	if testItemsErr != nil {
+		t.Fatalf("failed to generate test items: %v", testItemsErr)
+	}
+	items := make([]*PreParamsData, len(testItemsData))
+	for i, item := range testItemsData {
+		c := *item
+		items[i] = &c
+	}
+	return items
+}
+
+// testConfig returns a pool configuration for testItems that never
+// generates or saves on its own, in a temporary directory
+func testConfig(t *testing.T) SimpleConfig {
+	t.Helper()
+	cfg := config.Default().Pool
+	cfg.Storage = StorageMemory
+	cfg.PoolDir = t.TempDir()
+	cfg.InstanceID = "test-instance"
+	cfg.PrimeBitSize, cfg.PaillierBitSize = testPrimeBits, testPaillierBits
+	cfg.MinPoolSize, cfg.RefillThreshold, cfg.MaxPoolSize = 0, 0, 10
+	cfg.BackgroundGen, cfg.OnDemandGen, cfg.AutoSave = false, false, false
+	return cfg
+}
+
+// newTestManager returns an unstarted manager holding items in order
+func newTestManager(t *testing.T, cfg SimpleConfig, items []*PreParamsData) *Manager {
+	t.Helper()
+	m := NewManager(generator.NewGenerator(), cfg)
+	if m.storageErr != nil {
+		t.Fatalf("NewManager() = %v", m.storageErr)
+	}
+	m.mu.Lock()
+	n := m.insertLocked(context.Background(), items, ImportSourceTransfer)
+	m.mu.Unlock()
+	if n != len(items) {
+		t.Fatalf("inserted %d of %d items", n, len(items))
+	}
+	return m
+}
@@ -0,0 +1,163 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// Stale policies for when only items older than MaxAge are left
+const (
+	StaleRegenerate = "regenerate" // Discard stale items and generate replacements synchronously
+	StaleServe      = "serve"      // Serve stale items flagged as stale
+)
+
+// isStale reports whether item is older than the configured MaxAge
+func (m *Manager) isStale(item *PreParamsData, now time.Time) bool {
+	return m.config.MaxAge > 0 && now.Sub(item.GeneratedAt) > m.config.MaxAge
+}
+
+// partitionStaleLocked splits candidate indexes into fresh and stale ones,
+// keeping their order
+// Caller must hold m.mu.
+func (m *Manager) partitionStaleLocked(order []int, now time.Time) (fresh, stale []int) {
+	if m.config.MaxAge <= 0 {
+		return order, nil
+	}
+	fresh = make([]int, 0, len(order))
+	for _, idx := range order {
+		if m.isStale(m.preParams[idx], now) {
+			stale = append(stale, idx)
+		} else {
+			fresh = append(fresh, idx)
+		}
+	}
+	return fresh, stale
+}
+
+// noteStaleLocked logs and journals stale items that were discarded or
+// served, and starts a background refill to replace them
+// Caller must hold m.mu.
+func (m *Manager) noteStaleLocked(ctx context.Context, expired, served int) {
+	if expired == 0 && served == 0 {
+		return
+	}
+	m.expired += int64(expired)
+	m.staleServed += int64(served)
+
+	if expired > 0 {
+		trace.Logf(ctx, "Discarded %d parameters older than max age %s", expired, m.config.MaxAge)
+	}
+	if served > 0 {
+		trace.Logf(ctx, "Warning: serving %d parameters older than max age %s", served, m.config.MaxAge)
+		m.errors.Record(errjournal.SeverityWarning, "pool", fmt.Errorf("served %d parameters older than max age %s", served, m.config.MaxAge),
+			map[string]string{"request_id": trace.ID(ctx)})
+	}
+
+	go m.refillPool()
+}
+
+// ageBuckets are the upper bounds of the age distribution in the status
+var ageBuckets = []struct {
+	label string
+	upTo  time.Duration
+}{
+	{"1h", time.Hour},
+	{"1d", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// AgeBucket counts pool items up to an age, and older than the bucket
+// before it ("older": beyond the last bound)
+type AgeBucket struct {
+	UpTo  string `json:"up_to"`
+	Count int    `json:"count"`
+}
+
+// AgeDistribution describes the ages of the items in the pool
+type AgeDistribution struct {
+	Buckets       []AgeBucket `json:"buckets"`
+	MinSeconds    int64       `json:"min_seconds"`
+	MedianSeconds int64       `json:"median_seconds"`
+	P90Seconds    int64       `json:"p90_seconds"`
+	MaxSeconds    int64       `json:"max_seconds"`
+	Stale         int         `json:"stale"`  // Older than MaxAge
+	Oldest        time.Time   `json:"oldest"` // Generation time of the oldest item (zero: empty pool)
+	Newest        time.Time   `json:"newest"`
+}
+
+// ageDistributionLocked returns the age distribution of the pool at now
+// Caller must hold m.mu (read or write).
+func (m *Manager) ageDistributionLocked(now time.Time) AgeDistribution {
+	d := AgeDistribution{Buckets: make([]AgeBucket, len(ageBuckets)+1)}
+	for i, b := range ageBuckets {
+		d.Buckets[i].UpTo = b.label
+	}
+	d.Buckets[len(ageBuckets)].UpTo = "older"
+	if len(m.preParams) == 0 {
+		return d
+	}
+
+	ages := make([]time.Duration, len(m.preParams))
+	for i, item := range m.preParams {
+		ages[i] = now.Sub(item.GeneratedAt)
+		if d.Oldest.IsZero() || item.GeneratedAt.Before(d.Oldest) {
+			d.Oldest = item.GeneratedAt
+		}
+		if item.GeneratedAt.After(d.Newest) {
+			d.Newest = item.GeneratedAt
+		}
+		b := sort.Search(len(ageBuckets), func(j int) bool { return ages[i] <= ageBuckets[j].upTo })
+		d.Buckets[b].Count++
+		if m.isStale(item, now) {
+			d.Stale++
+		}
+	}
+	sort.Slice(ages, func(i, j int) bool { return ages[i] < ages[j] })
+	d.MinSeconds = int64(ages[0].Seconds())
+	d.MedianSeconds = int64(ages[len(ages)/2].Seconds())
+	d.P90Seconds = int64(ages[len(ages)*9/10].Seconds())
+	d.MaxSeconds = int64(ages[len(ages)-1].Seconds())
+	return d
+}
+
+// expireStale discards pool items older than MaxAge and starts a refill to
+// replace them, so stale items are rotated out before a request meets them.
+// With the serve stale policy they are kept for when nothing fresher is
+// left.
+func (m *Manager) expireStale() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.config.MaxAge <= 0 || m.config.StalePolicy != StaleRegenerate {
+		return
+	}
+
+	now := time.Now()
+	kept := m.preParams[:0]
+	var removed []*PreParamsData
+	for _, item := range m.preParams {
+		if m.isStale(item, now) {
+			removed = append(removed, item)
+		} else {
+			kept = append(kept, item)
+		}
+	}
+	if len(removed) == 0 {
+		return
+	}
+	clear(m.preParams[len(kept):])
+	m.preParams = kept
+	m.changed()
+
+	ctx := context.Background()
+	m.writeLedgerLocked(ctx, removed, false)
+	m.noteStaleLocked(ctx, len(removed), 0)
+	if m.config.AutoSave {
+		go m.saveToDisk(ctx)
+	}
+}
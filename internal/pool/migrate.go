@@ -0,0 +1,108 @@
+package pool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/atrest"
+	"github.com/TEENet-io/prime-service/internal/shred"
+)
+
+// legacyPoolFile is the single pool file written before per-profile storage
+const legacyPoolFile = "prime_pool.json"
+
+// migrateLegacyPool imports a legacy prime_pool.json into the per-profile
+// pool files, once: valid items are appended to the pool matching their bit
+// sizes, invalid ones are dropped, and the legacy file is archived so the
+// migration does not run again. On failure the legacy file is left in place.
+// With shredPools, superseded pool file contents are overwritten; with a
+// cipher, the pool files are encrypted.
+func migrateLegacyPool(dir string, shredPools bool, cipher *atrest.Cipher) error {
+	legacyPath := filepath.Join(dir, legacyPoolFile)
+	data, err := os.ReadFile(legacyPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read legacy pool file: %w", err)
+	}
+
+	var legacy poolFile
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return fmt.Errorf("failed to parse legacy pool file: %w", err)
+	}
+
+	// Group valid items by the pool they belong to
+	groups := make(map[string][]*PreParamsData)
+	invalid := 0
+	for i, item := range legacy.PreParams {
+		if err := validateItem(item); err != nil {
+			log.Printf("Dropping invalid legacy item %d: %v", i, err)
+			invalid++
+			continue
+		}
+		sealItem(item)
+		primeBits, paillierBits := itemProfile(item)
+		path := poolFilePath(dir, primeBits, paillierBits)
+		groups[path] = append(groups[path], item)
+	}
+
+	for path, items := range groups {
+		if err := appendToPoolFile(path, items, shredPools, cipher); err != nil {
+			return err
+		}
+		log.Printf("Migrated %d legacy parameters into %s", len(items), path)
+	}
+
+	archive := fmt.Sprintf("%s.migrated-%s", legacyPath, time.Now().Format(backupTimeFormat))
+	if err := os.Rename(legacyPath, archive); err != nil {
+		return fmt.Errorf("failed to archive legacy pool file: %w", err)
+	}
+
+	log.Printf("Legacy pool migration completed (items: %d, invalid: %d, archived as: %s)",
+		len(legacy.PreParams), invalid, archive)
+	return nil
+}
+
+// appendToPoolFile adds items to the pool file at path, creating it if needed
+func appendToPoolFile(path string, items []*PreParamsData, overwrite bool, cipher *atrest.Cipher) error {
+	var existing poolFile
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if data, err = cipher.Open(data, sealPool); err != nil {
+			return fmt.Errorf("failed to decrypt pool file %s: %w", path, err)
+		}
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return fmt.Errorf("failed to parse pool file %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read pool file %s: %w", path, err)
+	}
+
+	existing.PreParams = append(existing.PreParams, items...)
+	existing.SavedAt = time.Now()
+
+	out, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal pool file %s: %w", path, err)
+	}
+	if out, err = cipher.Seal(out, sealPool); err != nil {
+		return fmt.Errorf("failed to encrypt pool file %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create pool directory: %w", err)
+	}
+
+	// Write atomically so a crash mid-migration never truncates a pool
+	err = shred.Replace(path, out, 0600, overwrite)
+	if errors.Is(err, shred.ErrNotOverwritten) {
+		log.Printf("Pool file migrated, but %v", err)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,98 @@
+package pool
+
+import (
+	"context"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/scheduler"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// QueueStatus is the place of a request waiting to generate on demand
+type QueueStatus struct {
+	Position int           // Requests ahead plus one; 0 once generating
+	ETA      time.Duration // Estimated wait for a generation slot (0: unknown or none)
+}
+
+// awaitGeneration waits for a slot to generate one of the need items a
+// request is still short of, reporting its queue position to
+// req.OnQueued. Requests get the generator's slots ahead of refills, whose
+// items give theirs up at the next yield point, but hold no more than
+// MaxOnDemand of them per pool. With fromPool set, items the pool gains
+// meanwhile are taken instead; once they cover need, no slot is taken and
+// release is nil. Otherwise release must be called once generation ends.
+func (m *Manager) awaitGeneration(ctx context.Context, need int, req Request, fromPool bool) (release func(), taken []*ServedParams, err error) {
+	limit := min(m.maxOnDemand(), m.generator.Workers())
+	job := m.generator.Scheduler().Enqueue(scheduler.Request, m.onDemand)
+	release = job.Done
+
+	reported := -1
+	for {
+		// Subscribe before looking, so no change in between is missed
+		wake := m.waiters.wait()
+		pos, moved := job.Position()
+		if pos == 0 {
+			if reported > 0 {
+				req.queued(QueueStatus{})
+			}
+			return release, taken, nil
+		}
+
+		if fromPool && m.Size() > 0 {
+			got, _ := m.takeFromPool(ctx, uint32(need-len(taken)), req, false)
+			req.served(got...)
+			taken = append(taken, got...)
+			if len(taken) >= need {
+				release()
+				return nil, taken, nil
+			}
+		}
+
+		if pos != reported {
+			status := QueueStatus{Position: pos, ETA: m.queueETA(pos, limit)}
+			if reported < 0 {
+				running, waiting := m.onDemand.Counts()
+				trace.Logf(ctx, "Queued for on-demand generation (position: %d, running: %d, waiting: %d, eta: %s)", pos, running, waiting, status.ETA)
+			}
+			reported = pos
+			req.queued(status)
+		}
+
+		select {
+		case <-moved:
+		case <-wake:
+		case <-ctx.Done():
+			release()
+			return nil, taken, ctx.Err()
+		case <-m.stopCh:
+			release()
+			return nil, taken, ErrStopped
+		}
+	}
+}
+
+// onDemandStatus reports the running and queued on-demand generations
+// Caller must hold m.mu.
+func (m *Manager) onDemandStatus() map[string]int {
+	running, waiting := m.onDemand.Counts()
+	return map[string]int{"running": running, "queued": waiting, "max": m.config.MaxOnDemand}
+}
+
+// maxOnDemand returns the configured on-demand concurrency
+func (m *Manager) maxOnDemand() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config.MaxOnDemand
+}
+
+// queueETA estimates the wait at position from the average on-demand
+// generation time so far (0: none generated yet)
+func (m *Manager) queueETA(position, limit int) time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.poolMisses == 0 || limit <= 0 {
+		return 0
+	}
+	rounds := (position + limit - 1) / limit
+	return time.Duration(rounds) * (m.missGenTime / time.Duration(m.poolMisses))
+}
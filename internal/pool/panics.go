@@ -0,0 +1,32 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// maxPanicStack bounds the stack trace kept per journal entry
+const maxPanicStack = 16 << 10
+
+// RecordPanic counts a panic recovered while handling method and journals
+// it with its stack trace, so it can be diagnosed after the fact
+func (m *Manager) RecordPanic(ctx context.Context, method string, value interface{}, stack []byte) {
+	m.panics.Add(1)
+	if len(stack) > maxPanicStack {
+		stack = stack[:maxPanicStack]
+	}
+	trace.Logf(ctx, "Recovered panic in %s: %v\n%s", method, value, stack)
+	m.errors.Record(errjournal.SeverityError, "panic", fmt.Errorf("panic in %s: %v", method, value), map[string]string{
+		"method":     method,
+		"request_id": trace.ID(ctx),
+		"stack":      string(stack),
+	})
+}
+
+// Panics returns the number of panics recovered in request handlers
+func (m *Manager) Panics() int64 {
+	return m.panics.Load()
+}
@@ -0,0 +1,255 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/shred"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// Fsync policies, see SimpleConfig.Fsync
+const (
+	FsyncAlways   = "always"   // Sync every pool save and ledger write
+	FsyncInterval = "interval" // Sync at most FsyncInterval after a write
+	FsyncNever    = "never"    // Leave flushing to the OS
+)
+
+// saveBatch collects items entering the pool into group commits
+type saveBatch struct {
+	mu      sync.Mutex
+	unsaved int         // Items added since the last save started
+	timer   *time.Timer // Pending SaveBatchDelay save
+}
+
+// noteUnsavedLocked records n items added to the pool and saves it once a
+// batch is complete, or schedules a save SaveBatchDelay after the first
+// unsaved item. With redis storage they are pushed to the shared pool at
+// once, whatever AutoSave.
+// Caller must hold m.mu.
+func (m *Manager) noteUnsavedLocked(n int) {
+	if m.shared != nil && n > 0 {
+		// Other replicas only see items once pushed
+		go m.saveToDisk(context.Background())
+		return
+	}
+	if !m.config.AutoSave || m.poolFilePath == "" || n <= 0 {
+		return
+	}
+	b := &m.saveBatch
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.unsaved += n
+	if b.unsaved >= m.config.SaveBatchItems {
+		go m.saveToDisk(context.Background())
+		return
+	}
+	if delay := m.config.SaveBatchDelay; delay > 0 && b.timer == nil {
+		b.timer = time.AfterFunc(delay, func() { m.saveToDisk(context.Background()) })
+	}
+}
+
+// startedLocked marks the unsaved items as part of the save about to be
+// written
+// Caller must hold m.mu (read or write).
+func (b *saveBatch) startedLocked() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unsaved = 0
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
+
+// pending returns the number of items not yet in a save
+func (b *saveBatch) pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.unsaved
+}
+
+// fileSyncer applies the fsync policy to the pool file and ledger
+type fileSyncer struct {
+	mu    sync.Mutex
+	dirty map[string]bool // Written but not yet synced, with FsyncInterval
+	last  time.Time       // Of the last sync
+	timer *time.Timer
+}
+
+// syncWritten makes a file just written durable as the fsync policy demands
+func (m *Manager) syncWritten(ctx context.Context, path string) {
+	switch m.config.Fsync {
+	case FsyncAlways:
+		if err := syncFile(path); err != nil {
+			m.syncFailed(ctx, path, err)
+		}
+	case FsyncInterval:
+		m.scheduleSync(path)
+	}
+}
+
+// scheduleSync syncs path once FsyncInterval has passed since the last
+// sync, together with anything else written meanwhile
+func (m *Manager) scheduleSync(path string) {
+	s := &m.syncer
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dirty == nil {
+		s.dirty = make(map[string]bool)
+	}
+	s.dirty[path] = true
+	if s.timer == nil {
+		wait := max(0, m.config.FsyncInterval-time.Since(s.last))
+		s.timer = time.AfterFunc(wait, m.flushSyncs)
+	}
+}
+
+// flushSyncs syncs every file written since the last sync, also on Stop
+func (m *Manager) flushSyncs() {
+	s := &m.syncer
+	s.mu.Lock()
+	dirty := s.dirty
+	s.dirty, s.timer, s.last = nil, nil, time.Now()
+	s.mu.Unlock()
+
+	for path := range dirty {
+		if err := syncFile(path); err != nil {
+			m.syncFailed(context.Background(), path, err)
+		}
+	}
+}
+
+// syncFailed reports a failed sync
+func (m *Manager) syncFailed(ctx context.Context, path string, err error) {
+	trace.Logf(ctx, "Failed to sync %s: %v", path, err)
+	m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "fsync", "file": path})
+}
+
+// syncFile flushes a file and its directory entry to stable storage, so a
+// completed rename survives a crash
+func syncFile(path string) error {
+	for _, p := range []string{path, filepath.Dir(path)} {
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		err = f.Sync()
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// poolBackupPath returns the rolling backup kept next to a pool file: the
+// previous save, loaded if the pool file is missing or unreadable
+func poolBackupPath(poolFile string) string {
+	return strings.TrimSuffix(poolFile, ".json") + ".backup.json"
+}
+
+// replacePoolFile replaces the pool file without ever leaving it partially
+// written. The new contents go to a temporary file that must read back
+// with all want items before the pool file is moved to the backup and the
+// temporary file takes its place. A crash between the two renames leaves
+// only the backup, which loadFromDisk then recovers. With SecureDelete, the
+// superseded backup is overwritten with zeros.
+func (m *Manager) replacePoolFile(data []byte, want int) error {
+	path, backup := m.poolFilePath, poolBackupPath(m.poolFilePath)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	written, _, err := m.readPoolFile(tmp)
+	if err == nil && len(written.PreParams) != want {
+		err = fmt.Errorf("holds %d items, want %d", len(written.PreParams), want)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("pool file written to %s does not read back, keeping the previous one: %w", tmp, err)
+	}
+
+	var old *os.File
+	if m.config.SecureDelete {
+		if f, err := os.OpenFile(backup, os.O_WRONLY, 0); err == nil {
+			old = f
+			defer old.Close()
+		}
+	}
+	if err := os.Rename(path, backup); err != nil && !errors.Is(err, os.ErrNotExist) {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	if old != nil {
+		if err := shred.Overwrite(old); err != nil {
+			return fmt.Errorf("%w: %s: %v", shred.ErrNotOverwritten, backup, err)
+		}
+	}
+	return nil
+}
+
+// readPoolFile reads, decrypts and decodes a pool file, reporting whether
+// it was stored in plaintext that encryption now requires to be rewritten
+func (m *Manager) readPoolFile(path string) (*poolFile, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false, err
+	}
+	data, plaintext, err := m.openFile(data, sealPool, path)
+	if err != nil {
+		return nil, false, err
+	}
+	f, err := decodePoolFile(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return f, plaintext, nil
+}
+
+// setAside renames an unreadable pool file out of the way, so saves do not
+// replace it and it stays available for analysis
+func setAside(path string) {
+	aside := fmt.Sprintf("%s.unreadable-%s", path, time.Now().Format(backupTimeFormat))
+	if err := os.Rename(path, aside); err != nil {
+		log.Printf("Failed to set aside unreadable pool file %s: %v", path, err)
+		return
+	}
+	log.Printf("Set aside unreadable pool file as %s", aside)
+}
+
+// removeInterruptedSave removes the temporary file of a save interrupted by
+// a crash, overwriting it first with SecureDelete
+func (m *Manager) removeInterruptedSave() {
+	tmp := m.poolFilePath + ".tmp"
+	if _, err := os.Stat(tmp); err != nil {
+		return
+	}
+	var err error
+	if m.config.SecureDelete {
+		err = shred.File(tmp)
+	} else {
+		err = os.Remove(tmp)
+	}
+	if err != nil {
+		log.Printf("Failed to remove %s left by an interrupted save: %v", tmp, err)
+		return
+	}
+	log.Printf("Removed %s left by an interrupted save", tmp)
+}
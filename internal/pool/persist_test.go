@@ -0,0 +1,88 @@
+package pool
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/TEENet-io/prime-service/internal/generator"
+)
+
+// fileConfig returns a file-backed pool configuration in dir
+func fileConfig(t *testing.T, dir string) SimpleConfig {
+	t.Helper()
+	cfg := testConfig(t)
+	cfg.Storage = StorageFile
+	cfg.PoolDir = dir
+	return cfg
+}
+
+func TestPoolFileRecovery(t *testing.T) {
+	tests := []struct {
+		name     string
+		damage   func(t *testing.T, path string)
+		wantSize int
+	}{
+		{name: "pool file intact", damage: func(t *testing.T, path string) {}, wantSize: 2},
+		// The backup still holds the item served before the last save
+		{name: "pool file corrupt", damage: func(t *testing.T, path string) { writeFile(t, path, []byte("{")) }, wantSize: 2},
+		{name: "pool file missing", damage: func(t *testing.T, path string) { os.Remove(path) }, wantSize: 2},
+		{name: "interrupted save", damage: func(t *testing.T, path string) { writeFile(t, path+".tmp", []byte("{")) }, wantSize: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			m := newTestManager(t, fileConfig(t, dir), testItems(t))
+			m.writePoolFile(ctx)
+			served, err := m.GetPreParams(ctx, Request{Count: 1, NoGenerate: true})
+			if err != nil {
+				t.Fatalf("GetPreParams() = %v", err)
+			}
+			m.writePoolFile(ctx)
+			if _, err := os.Stat(poolBackupPath(m.poolFilePath)); err != nil {
+				t.Fatalf("no backup after the second save: %v", err)
+			}
+
+			tt.damage(t, m.poolFilePath)
+			reloaded := NewManager(generator.NewGenerator(), fileConfig(t, dir))
+			if reloaded.storageErr != nil {
+				t.Fatalf("NewManager() = %v", reloaded.storageErr)
+			}
+			if got := reloaded.Size(); got != tt.wantSize {
+				t.Fatalf("reloaded pool holds %d items, want %d", got, tt.wantSize)
+			}
+			reloaded.mu.RLock()
+			defer reloaded.mu.RUnlock()
+			for _, item := range reloaded.preParams {
+				if item.Checksum == served[0].Checksum {
+					t.Fatal("served item is back in the pool")
+				}
+			}
+		})
+	}
+}
+
+func TestPoolFileKeepsRemovalsDuringSave(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(t, fileConfig(t, t.TempDir()), testItems(t))
+
+	// An item served after the pool was taken for the file is still in it
+	m.mu.RLock()
+	m.markSavingLocked()
+	m.mu.RUnlock()
+	if _, err := m.GetPreParams(ctx, Request{Count: 1, NoGenerate: true}); err != nil {
+		t.Fatalf("GetPreParams() = %v", err)
+	}
+	m.mu.RLock()
+	m.writeLedgerLocked(ctx, nil, true)
+	m.mu.RUnlock()
+
+	if n := len(m.ledgerFile.removed); n != 1 {
+		t.Fatalf("ledger lists %d removals since the save, want 1", n)
+	}
+	if n := len(m.ledgerFile.backupRemoved); n != 0 {
+		t.Fatalf("ledger lists %d removals before the save, want 0", n)
+	}
+}
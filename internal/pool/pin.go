@@ -0,0 +1,150 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// ErrItemNotFound is returned by PinItem and UnpinItem for items not in the
+// pool or not pinned
+var ErrItemNotFound = errors.New("no such item")
+
+// pinnedItem is an item set aside by PinItem, persisted with the pool
+type pinnedItem struct {
+	Item     *PreParamsData `json:"item"`
+	PinnedAt time.Time      `json:"pinned_at"`
+	Reason   string         `json:"reason,omitempty"`
+}
+
+// PinnedInfo describes a pinned item without any of its secret material
+type PinnedInfo struct {
+	ItemInfo
+	PinnedAt time.Time `json:"pinned_at"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// PinItem sets the pool item with the given reference (its fingerprint or
+// sequence number, see matchesRef) aside, e.g. to
+// preserve it for an incident investigation. A pinned item is never served,
+// transferred or expired, and does not count towards MinPoolSize, so the
+// pool refills around it. Pinning an already pinned item returns it
+// unchanged.
+func (m *Manager) PinItem(ctx context.Context, ref, reason string) (PinnedInfo, error) {
+	m.mu.Lock()
+	for _, p := range m.pinned {
+		if matchesRef(p.Item, ref) {
+			defer m.mu.Unlock()
+			return m.pinnedInfo(p), nil
+		}
+	}
+	idx := -1
+	for i, item := range m.preParams {
+		if matchesRef(item, ref) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		m.mu.Unlock()
+		return PinnedInfo{}, fmt.Errorf("%w in the pool: %s", ErrItemNotFound, ref)
+	}
+	p := &pinnedItem{Item: m.preParams[idx], PinnedAt: time.Now(), Reason: reason}
+	m.preParams = append(m.preParams[:idx], m.preParams[idx+1:]...)
+	m.pinned = append(m.pinned, p)
+	info, size := m.pinnedInfo(p), len(m.preParams)
+	m.changed()
+	m.mu.Unlock()
+
+	trace.Logf(ctx, "Pinned item %d (%s, reason: %q, pool size: %d)", info.Provenance.Seq, info.Fingerprint, reason, size)
+	m.saveToDisk(ctx)
+	if size <= m.config.RefillThreshold {
+		go m.refillPool()
+	}
+	return info, nil
+}
+
+// UnpinItem returns a pinned item to the pool, or quarantines it if it no
+// longer verifies
+func (m *Manager) UnpinItem(ctx context.Context, ref string) (PinnedInfo, error) {
+	m.mu.Lock()
+	idx := -1
+	for i, p := range m.pinned {
+		if matchesRef(p.Item, ref) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		m.mu.Unlock()
+		return PinnedInfo{}, fmt.Errorf("%w pinned: %s", ErrItemNotFound, ref)
+	}
+	p := m.pinned[idx]
+	info := m.pinnedInfo(p)
+	m.pinned = append(m.pinned[:idx], m.pinned[idx+1:]...)
+
+	class, err := verifyItem(p.Item)
+	if err == nil {
+		m.preParams = append(m.preParams, p.Item)
+	}
+	m.changed()
+	m.mu.Unlock()
+
+	if err != nil {
+		m.quarantine(ctx, p.Item, class, err, "unpin")
+		m.saveToDisk(ctx)
+		return info, fmt.Errorf("pinned item %d failed verification and was quarantined: %w", info.Provenance.Seq, err)
+	}
+	trace.Logf(ctx, "Returned pinned item %d (%s) to the pool", info.Provenance.Seq, info.Fingerprint)
+	m.saveToDisk(ctx)
+	return info, nil
+}
+
+// matchesRef reports whether ref names item: a string of decimal digits
+// shorter than a fingerprint is a sequence number, anything else a
+// fingerprint
+func matchesRef(item *PreParamsData, ref string) bool {
+	if len(ref) < fingerprintLen {
+		seq, err := strconv.ParseUint(ref, 10, 64)
+		return err == nil && seq != 0 && seq == item.Provenance.Seq
+	}
+	return Fingerprint(item) == ref
+}
+
+// PinnedItems lists the pinned items in the order they were pinned
+func (m *Manager) PinnedItems() []PinnedInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.pinnedLocked()
+}
+
+// pinnedLocked lists the pinned items
+// Caller must hold m.mu.
+func (m *Manager) pinnedLocked() []PinnedInfo {
+	infos := make([]PinnedInfo, len(m.pinned))
+	for i, p := range m.pinned {
+		infos[i] = m.pinnedInfo(p)
+	}
+	return infos
+}
+
+// pinnedInfo describes a pinned item
+// Caller must hold m.mu.
+func (m *Manager) pinnedInfo(p *pinnedItem) PinnedInfo {
+	return PinnedInfo{
+		ItemInfo: ItemInfo{
+			Fingerprint:        Fingerprint(p.Item),
+			GeneratedAt:        p.Item.GeneratedAt,
+			GenerationDuration: p.Item.GenerationDuration,
+			Provenance:         p.Item.Provenance,
+			Stale:              m.isStale(p.Item, time.Now()),
+			Assurance:          AssuranceOf(p.Item),
+		},
+		PinnedAt: p.PinnedAt,
+		Reason:   p.Reason,
+	}
+}
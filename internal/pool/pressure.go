@@ -0,0 +1,152 @@
+package pool
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// pressureWindow is the lookback used for supply/consumption rates
+const pressureWindow = 15 * time.Minute
+
+// PressureReport is a machine-readable scaling signal for autoscalers
+type PressureReport struct {
+	InstanceID             string  `json:"instance_id"`
+	Desired                int     `json:"desired"`                  // MinPoolSize
+	Actual                 int     `json:"actual"`                   // Current pool size
+	Deficit                int     `json:"deficit"`                  // Items missing to reach Desired (generation backlog)
+	FillRatio              float64 `json:"fill_ratio"`               // Actual / Desired
+	InFlight               int     `json:"in_flight"`                // Items currently being generated
+	SupplyRatePerMin       float64 `json:"supply_rate_per_min"`      // Items added per minute (recent window)
+	ConsumptionRatePerMin  float64 `json:"consumption_rate_per_min"` // Items removed per minute (recent window)
+	NetRatePerMin          float64 `json:"net_rate_per_min"`         // Supply minus consumption; negative means the pool is draining
+	CapacityPerMin         float64 `json:"capacity_per_min"`         // Generation capacity at the configured concurrency (0 if unknown)
+	ETASeconds             int64   `json:"eta_seconds"`              // Time to clear the deficit at current capacity (-1 if unknown)
+	Pressure               float64 `json:"pressure"`                 // Deficit/Desired plus draining rate relative to capacity
+	AverageGenerationMilli int64   `json:"average_generation_ms"`    // Average time to generate one item
+}
+
+// Pressure returns the current scaling signal
+func (m *Manager) Pressure() PressureReport {
+	m.mu.RLock()
+	actual := m.sizeLocked()
+	m.mu.RUnlock()
+
+	r := PressureReport{
+		InstanceID:            m.instanceID,
+		Desired:               m.config.MinPoolSize,
+		Actual:                actual,
+		InFlight:              int(m.inFlight.Load()),
+		SupplyRatePerMin:      m.supplied.ratePerMinute(pressureWindow),
+		ConsumptionRatePerMin: m.consumed.ratePerMinute(pressureWindow),
+		ETASeconds:            -1,
+	}
+	r.NetRatePerMin = r.SupplyRatePerMin - r.ConsumptionRatePerMin
+
+	if r.Desired > actual {
+		r.Deficit = r.Desired - actual
+	}
+	if r.Desired > 0 {
+		r.FillRatio = float64(actual) / float64(r.Desired)
+		r.Pressure = float64(r.Deficit) / float64(r.Desired)
+	}
+
+	avg := m.generator.GetAverageGenerationTime()
+	r.AverageGenerationMilli = avg.Milliseconds()
+	if avg > 0 {
+		workers := m.effectiveConcurrency()
+		r.CapacityPerMin = float64(workers) * float64(time.Minute) / float64(avg)
+		r.ETASeconds = int64(math.Ceil(float64(r.Deficit) / float64(workers) * avg.Seconds()))
+		if r.NetRatePerMin < 0 {
+			r.Pressure += -r.NetRatePerMin / r.CapacityPerMin
+		}
+	}
+
+	return r
+}
+
+// effectiveConcurrency mirrors the worker count used by refillPool
+func (m *Manager) effectiveConcurrency() int {
+	workers := m.config.MaxConcurrent
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > 1 && runtime.NumCPU() <= 3 {
+		workers = 1
+	}
+	return workers
+}
+
+// eventWindow counts events over a sliding time window
+type eventWindow struct {
+	mu      sync.Mutex
+	events  []windowEvent
+	horizon time.Duration // How long events are kept (default: pressureWindow)
+}
+
+type windowEvent struct {
+	at time.Time
+	n  int
+}
+
+// add records n events now
+func (w *eventWindow) add(n int) {
+	if n <= 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.events = append(w.events, windowEvent{at: time.Now(), n: n})
+	horizon := w.horizon
+	if horizon <= 0 {
+		horizon = pressureWindow
+	}
+	w.trim(time.Now().Add(-horizon))
+}
+
+// setHorizon changes how long events are kept
+func (w *eventWindow) setHorizon(horizon time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.horizon = horizon
+}
+
+// count returns the number of events within the window
+func (w *eventWindow) count(window time.Duration) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cutoff := time.Now().Add(-window)
+	total := 0
+	for _, e := range w.events {
+		if !e.at.Before(cutoff) {
+			total += e.n
+		}
+	}
+	return total
+}
+
+// ratePerMinute returns the average events per minute over the window
+func (w *eventWindow) ratePerMinute(window time.Duration) float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	cutoff := time.Now().Add(-window)
+	w.trim(cutoff)
+
+	total := 0
+	for _, e := range w.events {
+		total += e.n
+	}
+	return float64(total) / window.Minutes()
+}
+
+// trim drops events older than cutoff
+func (w *eventWindow) trim(cutoff time.Time) {
+	i := 0
+	for i < len(w.events) && w.events[i].at.Before(cutoff) {
+		i++
+	}
+	w.events = w.events[i:]
+}
@@ -0,0 +1,92 @@
+package pool
+
+import (
+	"context"
+	"log"
+)
+
+// Reload applies the runtime-adjustable settings of cfg: pool sizes and
+// threshold, serving and stale policies, max age, denied versions, import revalidation and
+// demotion, audited items, alarm and freeze thresholds, on-demand generation
+// and its concurrency, housekeeping and emergency concurrency
+// and throttling, refill interval and cycle history, save batching and fsync
+// policy.
+// Settings that need a restart (instance ID, storage, pool directory, bit
+// sizes, idempotency TTL, error journal size, background generation, startup
+// delay, load validation, secure delete, backup retention, snapshots,
+// encryption key) are kept and logged if they differ.
+func (m *Manager) Reload(cfg SimpleConfig) error {
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	old := *m.config
+	m.config.MinPoolSize = cfg.MinPoolSize
+	m.config.MaxPoolSize = cfg.MaxPoolSize
+	m.config.RefillThreshold = cfg.RefillThreshold
+	m.config.MaxConcurrent = cfg.MaxConcurrent
+	m.config.OnDemandGen = cfg.OnDemandGen
+	m.config.MaxOnDemand = cfg.MaxOnDemand
+	m.config.SelectionPolicy = cfg.SelectionPolicy
+	m.config.AntiCorrelationWindow = cfg.AntiCorrelationWindow
+	m.config.ImportRevalidateAge = cfg.ImportRevalidateAge
+	m.config.ImportDemote = cfg.ImportDemote
+	m.config.MinAudited = cfg.MinAudited
+	m.config.MaxAge = cfg.MaxAge
+	m.config.StalePolicy = cfg.StalePolicy
+	m.config.DeniedVersions = cfg.DeniedVersions
+	m.config.DeniedVersionPolicy = cfg.DeniedVersionPolicy
+	m.config.AlarmServedLimit = cfg.AlarmServedLimit
+	m.config.AlarmServedWindow = cfg.AlarmServedWindow
+	m.config.AlarmEmptyWithin = cfg.AlarmEmptyWithin
+	m.config.FreezeOnAnomaly = cfg.FreezeOnAnomaly
+	m.config.FreezeFailureLimit = cfg.FreezeFailureLimit
+	m.config.FreezeFailureWindow = cfg.FreezeFailureWindow
+	m.config.AutoSave = cfg.AutoSave
+	m.config.SaveBatchItems = cfg.SaveBatchItems
+	m.config.SaveBatchDelay = cfg.SaveBatchDelay
+	m.config.Fsync = cfg.Fsync
+	m.config.FsyncInterval = cfg.FsyncInterval
+	m.config.RefillInterval = cfg.RefillInterval
+	m.config.RefillHistory = cfg.RefillHistory
+	m.config.GenerationThrottle = cfg.GenerationThrottle
+	m.config.EmergencyConcurrent = cfg.EmergencyConcurrent
+	m.config.EmergencyThrottle = cfg.EmergencyThrottle
+	m.mu.Unlock()
+	m.changed()
+	m.onDemand.SetCap(cfg.MaxOnDemand)
+	m.purgeDenied()
+	m.served.setHorizon(cfg.AlarmServedWindow)
+	m.configureFreeze(&cfg)
+	m.config.Tenants = cfg.Tenants
+	m.tenants.configure(cfg.Tenants)
+
+	if cfg.RefillInterval != old.RefillInterval {
+		m.tickerMu.Lock()
+		if m.ticker != nil {
+			m.ticker.Reset(cfg.RefillInterval)
+		}
+		m.tickerMu.Unlock()
+	}
+
+	if cfg.InstanceID != old.InstanceID || cfg.Storage != old.Storage || cfg.PoolDir != old.PoolDir || cfg.PrimeBitSize != old.PrimeBitSize || cfg.PaillierBitSize != old.PaillierBitSize ||
+		cfg.IdempotencyTTL != old.IdempotencyTTL || cfg.ErrorJournalSize != old.ErrorJournalSize ||
+		cfg.BackgroundGen != old.BackgroundGen || cfg.StartupDelay != old.StartupDelay || cfg.VerifyPrimalityOnLoad != old.VerifyPrimalityOnLoad ||
+		cfg.SecureDelete != old.SecureDelete || cfg.BackupRetention != old.BackupRetention ||
+		cfg.SnapshotDir != old.SnapshotDir || cfg.SnapshotInterval != old.SnapshotInterval || cfg.SnapshotKeep != old.SnapshotKeep || cfg.SnapshotCommand != old.SnapshotCommand ||
+		cfg.EncryptionKey != old.EncryptionKey || cfg.EncryptionKeyFile != old.EncryptionKeyFile || cfg.EncryptionKeyCommand != old.EncryptionKeyCommand ||
+		cfg.RedisAddress != old.RedisAddress || cfg.RedisPassword != old.RedisPassword || cfg.RedisDB != old.RedisDB || cfg.RedisKey != old.RedisKey || cfg.RedisTLS != old.RedisTLS {
+		log.Printf("Warning: some changed pool settings (instance ID, storage, directory, bit sizes, TTLs, journal size, background generation, startup delay, load validation, secure delete, backup retention, snapshots, encryption key, Redis connection) only take effect after a restart")
+	}
+
+	log.Printf("Pool config reloaded (pool_size: %d-%d, threshold: %d, policy: %s, max_concurrent: %d, interval: %s)",
+		cfg.MinPoolSize, cfg.MaxPoolSize, cfg.RefillThreshold, cfg.SelectionPolicy, cfg.MaxConcurrent, cfg.RefillInterval)
+	return nil
+}
+
+// Save writes the pool to disk immediately
+func (m *Manager) Save() {
+	m.saveToDisk(context.Background())
+}
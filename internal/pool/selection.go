@@ -0,0 +1,201 @@
+package pool
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// Selection policies for which pool items GetPreParams hands out
+const (
+	SelectOldest = "oldest" // FIFO: rotate stock, keeps the pool fresh
+	SelectNewest = "newest" // LIFO: maximize remaining shelf life of the rest
+	SelectRandom = "random" // Uniform: reduce correlation between consecutive requests
+)
+
+// selectLocked removes up to take items from the pool according to the
+// configured selection policy. With req.DistinctProvenance, only items with
+// mutually distinct provenance are chosen, so fewer may be returned.
+// Candidates that fail verification are quarantined instead. Items older
+// than MaxAge are only chosen after all fresh ones under the serve stale
+// policy, and are otherwise discarded; the number discarded is returned.
+// Imported items demoted by ImportDemote and items put in by ImportItems
+// are skipped unless req.AllowImported is set, and items below req.MinAssurance always. With
+// req.DistinctSeconds, items generated in distinct seconds are preferred.
+// Items of denied generator versions are discarded under the refuse policy.
+// With all set, nothing is chosen unless take items qualify.
+// Caller must hold m.mu.
+func (m *Manager) selectLocked(ctx context.Context, take int, req Request, all bool) ([]*PreParamsData, int) {
+	order, stale := m.partitionStaleLocked(m.candidateOrderLocked(), time.Now())
+	if req.DistinctSeconds {
+		order, stale = m.spreadSecondsLocked(order), m.spreadSecondsLocked(stale)
+	}
+	var expired []int
+	if m.config.StalePolicy == StaleServe {
+		order = append(order, stale...)
+	} else {
+		expired = stale
+	}
+
+	chosen := make([]int, 0, take)
+	var failed []int
+	var denied, warned []*PreParamsData
+	for _, idx := range order {
+		if len(chosen) == take {
+			break
+		}
+		if m.deniedLocked(m.preParams[idx]) && m.refuseDeniedLocked() {
+			denied = append(denied, m.preParams[idx])
+			failed = append(failed, idx)
+			continue
+		}
+		if req.DistinctProvenance && !m.distinctFromLocked(idx, chosen) {
+			continue
+		}
+		if !req.AllowImported && m.demotedLocked(m.preParams[idx]) {
+			continue
+		}
+		if !meetsAssurance(m.preParams[idx], req.MinAssurance) {
+			continue
+		}
+		// Never serve an item that fails verification
+		if class, err := verifyItem(m.preParams[idx]); err != nil {
+			m.quarantine(ctx, m.preParams[idx], class, err, "serve")
+			failed = append(failed, idx)
+			continue
+		}
+		chosen = append(chosen, idx)
+	}
+	if all && len(chosen) < take {
+		chosen = chosen[:0]
+	}
+	for _, idx := range chosen {
+		if m.deniedLocked(m.preParams[idx]) {
+			warned = append(warned, m.preParams[idx])
+		}
+	}
+	m.noteDeniedLocked(ctx, denied, true)
+	m.noteDeniedLocked(ctx, warned, false)
+
+	result := make([]*PreParamsData, len(chosen))
+	for i, idx := range chosen {
+		result[i] = m.preParams[idx]
+	}
+
+	// Remove chosen, quarantined, denied and expired items, preserving the order of the rest
+	removed := append(append(append([]int(nil), chosen...), failed...), expired...)
+	sort.Ints(removed)
+	remaining := m.preParams[:0]
+	next := 0
+	for i, item := range m.preParams {
+		if next < len(removed) && removed[next] == i {
+			next++
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	for i := len(remaining); i < len(m.preParams); i++ {
+		m.preParams[i] = nil
+	}
+	m.preParams = remaining
+
+	return result, len(expired)
+}
+
+// candidateOrderLocked returns pool indexes in policy preference order
+func (m *Manager) candidateOrderLocked() []int {
+	n := len(m.preParams)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	switch m.config.SelectionPolicy {
+	case SelectNewest:
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	case SelectRandom:
+		for i := n - 1; i > 0; i-- {
+			j := randomIndex(i + 1)
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	return order
+}
+
+// spreadSecondsLocked reorders candidate pool indexes so the first of each
+// generation second comes before any item sharing a second with an earlier
+// one, keeping policy order within both groups
+func (m *Manager) spreadSecondsLocked(order []int) []int {
+	seen := make(map[int64]bool, len(order))
+	spread := make([]int, 0, len(order))
+	var repeats []int
+	for _, idx := range order {
+		second := m.preParams[idx].GeneratedAt.Unix()
+		if seen[second] {
+			repeats = append(repeats, idx)
+			continue
+		}
+		seen[second] = true
+		spread = append(spread, idx)
+	}
+	return append(spread, repeats...)
+}
+
+// markSharedSeconds flags the items generated in the same second as another
+// item of the batch, returning how many were flagged
+func markSharedSeconds(served []*ServedParams) int {
+	seconds := make(map[int64]int, len(served))
+	for _, s := range served {
+		seconds[s.GeneratedAt.Unix()]++
+	}
+	shared := 0
+	for _, s := range served {
+		s.SharedSecond = seconds[s.GeneratedAt.Unix()] > 1
+		if s.SharedSecond {
+			shared++
+		}
+	}
+	return shared
+}
+
+// distinctFromLocked reports whether the item at idx has provenance distinct
+// from every already chosen item
+func (m *Manager) distinctFromLocked(idx int, chosen []int) bool {
+	item := m.preParams[idx]
+	for _, c := range chosen {
+		if correlated(item, m.preParams[c], m.config.AntiCorrelationWindow) {
+			return false
+		}
+	}
+	return true
+}
+
+// correlated reports whether two items come from the same instance, host and burst
+// and were generated within window of each other (any time if window is 0)
+func correlated(a, b *PreParamsData, window time.Duration) bool {
+	if a.Provenance.Instance != b.Provenance.Instance || a.Provenance.Host != b.Provenance.Host || a.Provenance.Burst != b.Provenance.Burst {
+		return false
+	}
+	if window <= 0 {
+		return true
+	}
+	gap := a.GeneratedAt.Sub(b.GeneratedAt)
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap < window
+}
+
+// randomIndex returns a uniform random index in [0, n)
+func randomIndex(n int) int {
+	idx, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0
+	}
+	return int(idx.Int64())
+}
@@ -0,0 +1,63 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSelectionPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        string
+		count         uint32
+		adminImported []int // Items put in by ImportItems
+		allowImported bool
+		want          []int // Indexes of the served items in order; nil: any
+		wantCount     int
+		wantErr       error
+	}{
+		{name: "oldest", policy: SelectOldest, count: 2, want: []int{0, 1}, wantCount: 2},
+		{name: "newest", policy: SelectNewest, count: 2, want: []int{2, 1}, wantCount: 2},
+		{name: "random", policy: SelectRandom, count: 2, wantCount: 2},
+		{name: "more than held", policy: SelectOldest, count: 5, want: []int{0, 1, 2}, wantCount: 3},
+		{name: "imports skipped", policy: SelectOldest, count: 2, adminImported: []int{0}, want: []int{1, 2}, wantCount: 2},
+		{name: "imports allowed", policy: SelectOldest, count: 2, adminImported: []int{0}, allowImported: true, want: []int{0, 1}, wantCount: 2},
+		{name: "newest skips imports", policy: SelectNewest, count: 3, adminImported: []int{2}, want: []int{1, 0}, wantCount: 2},
+		{name: "only imports", policy: SelectRandom, count: 1, adminImported: []int{0, 1, 2}, wantErr: ErrPoolEmpty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := testItems(t)
+			for _, i := range tt.adminImported {
+				items[i].Provenance.AdminImport = true
+			}
+			cfg := testConfig(t)
+			cfg.SelectionPolicy = tt.policy
+			m := newTestManager(t, cfg, items)
+
+			served, err := m.GetPreParams(context.Background(), Request{Count: tt.count, NoGenerate: true, AllowImported: tt.allowImported})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetPreParams() = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetPreParams() = %v", err)
+			}
+			if len(served) != tt.wantCount {
+				t.Fatalf("served %d items, want %d", len(served), tt.wantCount)
+			}
+			for i, idx := range tt.want {
+				if served[i].PreParamsData != items[idx] {
+					t.Fatalf("served item %d is not pool item %d", i, idx)
+				}
+			}
+			if got, want := m.Size(), len(items)-len(served); got != want {
+				t.Fatalf("pool holds %d items after serving, want %d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package pool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+)
+
+// seqBlock is how many sequence numbers are reserved on disk at a time, so
+// the sequence file is written once per block rather than per item
+const seqBlock = 1000
+
+// seqCounter hands out the sequence numbers items are known by in this
+// pool (item 4182), persisting a reservation ahead of use so numbers never
+// repeat across restarts or crashes. Numbers reserved but not used before a
+// restart are skipped.
+type seqCounter struct {
+	mu       sync.Mutex
+	path     string // "" with memory storage: numbers restart at 1
+	next     uint64
+	reserved uint64 // Highest number reserved on disk
+}
+
+// seqFileData is the on-disk format of the sequence file
+type seqFileData struct {
+	Reserved uint64 `json:"reserved"`
+}
+
+// seqPath returns the sequence file stored next to a pool file
+func seqPath(poolFile string) string {
+	return strings.TrimSuffix(poolFile, ".json") + ".seq.json"
+}
+
+// load reads the reservation, continuing after it
+func (s *seqCounter) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next = 1
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read sequence file: %w", err)
+	}
+	var f seqFileData
+	if err := json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("failed to parse sequence file %s: %w", s.path, err)
+	}
+	s.next, s.reserved = f.Reserved+1, f.Reserved
+	return nil
+}
+
+// skipPast makes sure numbers handed out later are above seq, e.g. that of
+// an item loaded from a pool file whose sequence file was lost
+func (s *seqCounter) skipPast(seq uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if seq >= s.next {
+		s.next = seq + 1
+	}
+}
+
+// assign gives item the next sequence number unless it has one. The number
+// is used even if its reservation cannot be persisted, which is reported.
+func (s *seqCounter) assign(item *PreParamsData) error {
+	if item.Provenance.Seq != 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.path != "" && s.next > s.reserved {
+		err = s.reserve(s.next + seqBlock - 1)
+	}
+	item.Provenance.Seq = s.next
+	s.next++
+	return err
+}
+
+// reserve persists the reservation up to upTo atomically (temp file + rename)
+// Caller must hold s.mu.
+func (s *seqCounter) reserve(upTo uint64) error {
+	data, err := json.Marshal(seqFileData{Reserved: upTo})
+	if err != nil {
+		return fmt.Errorf("failed to marshal sequence file: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write sequence file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace sequence file: %w", err)
+	}
+	s.reserved = upTo
+	return nil
+}
+
+// assignSeq numbers an item entering the pool. Without a persisted
+// reservation its number may repeat after a crash.
+func (m *Manager) assignSeq(item *PreParamsData) {
+	if err := m.seq.assign(item); err != nil {
+		log.Printf("Failed to reserve item sequence numbers: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "seq", "file": m.seq.path})
+	}
+}
+
+// numberLoaded numbers loaded items saved before sequence numbers were
+// introduced, in pool order, returning how many it numbered
+func (m *Manager) numberLoaded() int {
+	items := append([]*PreParamsData(nil), m.preParams...)
+	for _, p := range m.pinned {
+		items = append(items, p.Item)
+	}
+	for _, item := range items {
+		m.seq.skipPast(item.Provenance.Seq)
+	}
+
+	numbered := 0
+	for _, item := range items {
+		if item.Provenance.Seq == 0 {
+			m.assignSeq(item)
+			numbered++
+		}
+	}
+	return numbered
+}
+
+// itemSeqs returns the sequence numbers of items, for logs
+func itemSeqs(items []*PreParamsData) []uint64 {
+	seqs := make([]uint64, len(items))
+	for i, item := range items {
+		seqs[i] = item.Provenance.Seq
+	}
+	return seqs
+}
@@ -0,0 +1,261 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/redis"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// sealShared binds encrypted items in the shared pool to it
+const sealShared = "shared-pool"
+
+// sharedPollInterval is how often the length of the shared pool is read, to
+// notice items other replicas added or took
+const sharedPollInterval = 5 * time.Second
+
+// sharedPool is a pool kept in a Redis list by every replica configured with
+// the same server, database and key. Items are pushed to the tail once
+// generated and popped from the head when served, atomically in Redis, so
+// no two replicas serve the same item. The local pool only holds items on
+// their way into or out of the list.
+type sharedPool struct {
+	client *redis.Client
+	key    string
+	size   atomic.Int64 // Items in the list when last seen
+
+	mu    sync.Mutex
+	stats SharedStats
+}
+
+// SharedStats describes the shared pool and this instance's use of it
+type SharedStats struct {
+	Address   string `json:"address"`
+	Key       string `json:"key"`
+	Size      int64  `json:"size"`
+	Pushed    int64  `json:"pushed"`
+	Popped    int64  `json:"popped"`
+	Dropped   int64  `json:"dropped"`  // Popped items that could not be decoded
+	Failures  int64  `json:"failures"` // Commands that failed
+	LastError string `json:"last_error,omitempty"`
+}
+
+// openShared connects to the shared pool of cfg's profile and reads its
+// length
+func openShared(cfg *SimpleConfig) (*sharedPool, error) {
+	s := &sharedPool{
+		client: redis.New(redis.Options{
+			Address:  cfg.RedisAddress,
+			Password: cfg.RedisPassword,
+			DB:       cfg.RedisDB,
+			TLS:      cfg.RedisTLS,
+		}),
+		key: fmt.Sprintf("%s:%d-%d", cfg.RedisKey, cfg.PrimeBitSize, cfg.PaillierBitSize),
+	}
+	s.stats.Address, s.stats.Key = cfg.RedisAddress, s.key
+
+	ctx, cancel := context.WithTimeout(context.Background(), redis.DefaultTimeout)
+	defer cancel()
+	n, err := s.client.LLen(ctx, s.key)
+	if err != nil {
+		s.client.Close()
+		return nil, fmt.Errorf("failed to reach shared pool at %s: %w", cfg.RedisAddress, err)
+	}
+	s.size.Store(n)
+	log.Printf("Shared pool: Redis list %s at %s (size: %d)", s.key, cfg.RedisAddress, n)
+	return s, nil
+}
+
+// len returns the items in the shared pool when last seen (0 without one)
+func (s *sharedPool) len() int {
+	if s == nil {
+		return 0
+	}
+	return int(s.size.Load())
+}
+
+// failed counts a failed command
+func (s *sharedPool) failed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Failures++
+	s.stats.LastError = err.Error()
+}
+
+// count adds to the push, pop and drop counters
+func (s *sharedPool) count(pushed, popped, dropped int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.Pushed += int64(pushed)
+	s.stats.Popped += int64(popped)
+	s.stats.Dropped += int64(dropped)
+}
+
+// sizeLocked returns the items available to this instance: the local pool
+// plus, with redis storage, the shared pool
+// Caller must hold m.mu (read or write).
+func (m *Manager) sizeLocked() int {
+	return len(m.preParams) + m.shared.len()
+}
+
+// pushShared moves the items of the local pool to the shared pool. Items
+// that cannot be pushed go back to the local pool for the next attempt.
+func (m *Manager) pushShared(ctx context.Context) {
+	m.mu.Lock()
+	m.saveBatch.startedLocked()
+	items := m.preParams
+	m.preParams = make([]*PreParamsData, 0)
+	// Counted as shared meanwhile, so refills do not replace them
+	m.shared.size.Add(int64(len(items)))
+	m.mu.Unlock()
+	if len(items) == 0 {
+		return
+	}
+
+	values := make([]string, 0, len(items))
+	var err error
+	for _, item := range items {
+		var data []byte
+		if data, err = json.Marshal(item); err != nil {
+			break
+		}
+		if data, err = m.cipher.Seal(data, sealShared); err != nil {
+			break
+		}
+		values = append(values, string(data))
+	}
+	var n int64
+	if err == nil {
+		n, err = m.shared.client.RPush(ctx, m.shared.key, values...)
+	}
+
+	if err != nil {
+		m.mu.Lock()
+		m.shared.size.Add(-int64(len(items)))
+		m.preParams = append(items, m.preParams...)
+		m.mu.Unlock()
+		m.shared.failed(err)
+		trace.Logf(ctx, "Failed to push %d parameters to the shared pool, keeping them locally: %v", len(items), err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "shared_push", "key": m.shared.key, "count": fmt.Sprint(len(items))})
+		return
+	}
+	m.shared.size.Store(n)
+	m.shared.count(len(items), 0, 0)
+	m.changed()
+	trace.Logf(ctx, "Pushed %d parameters to the shared pool (size: %d)", len(items), n)
+}
+
+// popShared atomically takes up to count items from the shared pool into
+// the local pool, from which the request is then served. An item taken is
+// never handed to another replica, even if this one fails before serving
+// it.
+func (m *Manager) popShared(ctx context.Context, count int) {
+	values, err := m.shared.client.LPop(ctx, m.shared.key, count)
+	if err != nil {
+		m.shared.failed(err)
+		trace.Logf(ctx, "Failed to take parameters from the shared pool: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "shared_pop", "key": m.shared.key, "request_id": trace.ID(ctx)})
+		return
+	}
+
+	items := make([]*PreParamsData, 0, len(values))
+	dropped := 0
+	for _, v := range values {
+		item, err := m.decodeShared(v)
+		if err != nil {
+			dropped++
+			log.Printf("Dropping parameter set from the shared pool: %v", err)
+			m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "shared_decode", "key": m.shared.key, "request_id": trace.ID(ctx)})
+			// Counted like items failing verification, so a replica
+			// writing with another key or a tampered list raises an anomaly
+			m.noteVerificationFailure()
+			continue
+		}
+		items = append(items, item)
+	}
+	m.shared.count(0, len(values), dropped)
+	if dropped > 0 {
+		trace.Logf(ctx, "Dropped %d of %d parameters taken from the shared pool", dropped, len(values))
+	}
+
+	m.mu.Lock()
+	m.preParams = append(m.preParams, items...)
+	m.shared.size.Add(-int64(len(values)))
+	m.mu.Unlock()
+
+	// Other replicas may have taken or added items too
+	m.refreshShared(ctx)
+}
+
+// decodeShared opens and parses an item of the shared pool, which must be
+// of this pool's profile
+func (m *Manager) decodeShared(data []byte) (*PreParamsData, error) {
+	plaintext, err := m.cipher.Open(data, sealShared)
+	if err != nil {
+		return nil, err
+	}
+	item := new(PreParamsData)
+	if err := json.Unmarshal(plaintext, item); err != nil {
+		return nil, err
+	}
+	if item.PaillierKey == nil || item.P == nil {
+		return nil, fmt.Errorf("incomplete parameter set")
+	}
+	if err := m.checkProfile(item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// refreshShared reads the length of the shared pool
+func (m *Manager) refreshShared(ctx context.Context) {
+	n, err := m.shared.client.LLen(ctx, m.shared.key)
+	if err != nil {
+		m.shared.failed(err)
+		return
+	}
+	if m.shared.size.Swap(n) != n {
+		m.changed()
+	}
+}
+
+// sharedLoop keeps the length of the shared pool current, refilling it
+// when other replicas drained it
+func (m *Manager) sharedLoop() {
+	ticker := time.NewTicker(sharedPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.refreshShared(context.Background())
+			m.mu.RLock()
+			low := m.sizeLocked() <= m.config.RefillThreshold
+			m.mu.RUnlock()
+			if low {
+				go m.refillPool()
+			}
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// SharedStats returns the state of the shared pool, or nil without redis
+// storage
+func (m *Manager) SharedStats() *SharedStats {
+	if m.shared == nil {
+		return nil
+	}
+	m.shared.mu.Lock()
+	defer m.shared.mu.Unlock()
+	s := m.shared.stats
+	s.Size = m.shared.size.Load()
+	return &s
+}
@@ -0,0 +1,205 @@
+package pool
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/TEENet-io/prime-service/internal/generator"
+)
+
+// fakeRedis serves the list commands of the shared pool from memory
+type fakeRedis struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	lists map[string][]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{ln: ln, lists: make(map[string][]string)}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		reply := f.handle(args)
+		f.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) handle(args []string) string {
+	switch strings.ToUpper(args[0]) {
+	case "LLEN":
+		return ":" + strconv.Itoa(len(f.lists[args[1]])) + "\r\n"
+	case "RPUSH":
+		f.lists[args[1]] = append(f.lists[args[1]], args[2:]...)
+		return ":" + strconv.Itoa(len(f.lists[args[1]])) + "\r\n"
+	case "LPOP":
+		n, _ := strconv.Atoi(args[2])
+		list := f.lists[args[1]]
+		if len(list) == 0 {
+			return "*-1\r\n"
+		}
+		if n > len(list) {
+			n = len(list)
+		}
+		reply := "*" + strconv.Itoa(n) + "\r\n"
+		for _, v := range list[:n] {
+			reply += "$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"
+		}
+		f.lists[args[1]] = list[n:]
+		return reply
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+// readCommand reads one command sent as an array of bulk strings
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+// list returns a copy of the list at key
+func (f *fakeRedis) list(key string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.lists[key]...)
+}
+
+// push appends values to the list at key
+func (f *fakeRedis) push(key string, values ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lists[key] = append(f.lists[key], values...)
+}
+
+// sharedConfig returns a redis-backed pool configuration for f
+func sharedConfig(t *testing.T, f *fakeRedis) SimpleConfig {
+	t.Helper()
+	cfg := testConfig(t)
+	cfg.Storage = StorageRedis
+	cfg.RedisAddress = f.ln.Addr().String()
+	cfg.EncryptionKey = testEncryptionKey
+	return cfg
+}
+
+func TestSharedPoolRefusesPlaintext(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *SimpleConfig)
+		wantErr bool
+	}{
+		{"encryption key", func(cfg *SimpleConfig) {}, false},
+		{"no key", func(cfg *SimpleConfig) { cfg.EncryptionKey = "" }, true},
+		{"tls without password", func(cfg *SimpleConfig) { cfg.EncryptionKey, cfg.RedisTLS = "", true }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := sharedConfig(t, newFakeRedis(t))
+			tt.modify(&cfg)
+			m := NewManager(generator.NewGenerator(), cfg)
+			if got := m.storageErr != nil; got != tt.wantErr {
+				t.Fatalf("NewManager() storage error = %v, want error: %v", m.storageErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSharedPool(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRedis(t)
+	m := newTestManager(t, sharedConfig(t, f), nil)
+	key := m.shared.key
+
+	items := testItems(t)
+	m.mu.Lock()
+	m.preParams = append(m.preParams, items...)
+	m.mu.Unlock()
+	m.pushShared(ctx)
+
+	list := f.list(key)
+	if len(list) != len(items) || m.Size() != len(items) {
+		t.Fatalf("shared list holds %d items, pool size %d, want %d", len(list), m.Size(), len(items))
+	}
+	for _, v := range list {
+		if strings.Contains(v, "paillier_key") {
+			t.Fatal("item stored in redis in plaintext")
+		}
+	}
+
+	// Entries that cannot be decoded are dropped and counted
+	f.push(key, "garbage")
+	served, err := m.GetPreParams(ctx, Request{Count: uint32(len(items)), NoGenerate: true})
+	if err != nil {
+		t.Fatalf("GetPreParams() = %v", err)
+	}
+	if len(served) != len(items) {
+		t.Fatalf("served %d items, want %d", len(served), len(items))
+	}
+	for i, s := range served {
+		if s.PaillierKey.N.Cmp(items[i].PaillierKey.N) != 0 {
+			t.Fatalf("served item %d is not pushed item %d", i, i)
+		}
+	}
+
+	if _, err := m.GetPreParams(ctx, Request{Count: 1, NoGenerate: true}); err == nil {
+		t.Fatal("GetPreParams() served an undecodable item")
+	}
+	if stats := m.SharedStats(); stats.Dropped != 1 || stats.Popped != int64(len(items))+1 {
+		t.Fatalf("shared stats = %+v, want 1 dropped of %d popped", stats, len(items)+1)
+	}
+}
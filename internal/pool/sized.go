@@ -0,0 +1,63 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// GetPreParamsAtSize generates count items at bit sizes no pool serves,
+// synchronously and without touching the pool. Maintenance, freeze and
+// idempotency keys apply as for GetPreParams; with req.NoGenerate it fails
+// with ErrPoolEmpty.
+func (m *Manager) GetPreParamsAtSize(ctx context.Context, req Request, primeBits, paillierBits int) ([]*ServedParams, error) {
+	return m.serve(ctx, req, func(ctx context.Context, count uint32, req Request) ([]*ServedParams, error) {
+		if req.NoGenerate {
+			return nil, ErrPoolEmpty
+		}
+
+		trace.Logf(ctx, "Generating %d parameter sets at %d/%d bits, which no pool serves", count, primeBits, paillierBits)
+		burst := fmt.Sprintf("sized-%d", time.Now().UnixNano())
+		result := make([]*ServedParams, 0, count)
+		for len(result) < int(count) {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			// The pool holds no items of these sizes, so only a slot ends the wait
+			release, _, err := m.awaitGeneration(ctx, int(count)-len(result), req, false)
+			if err != nil {
+				trace.Logf(ctx, "Request ended while queued to generate at %d/%d bits (%d/%d ready): %v", primeBits, paillierBits, len(result), count, err)
+				return result, err
+			}
+
+			genStart := time.Now()
+			params, err := m.generateForRequest(ctx, Provenance{Instance: m.instanceID, Host: m.hostname, Burst: burst, Worker: len(result)}, primeBits, paillierBits, release)
+			if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				trace.Logf(ctx, "Request ended while generating at %d/%d bits (%d/%d ready): %v", primeBits, paillierBits, len(result), count, err)
+				return result, err
+			}
+			if err != nil {
+				m.errors.Record(errjournal.SeverityError, "generator", err, map[string]string{"mode": "sized", "request_id": trace.ID(ctx)})
+				return result, err
+			}
+
+			if err := m.assure(ctx, params, req); err != nil {
+				m.errors.Record(errjournal.SeverityError, "generator", err, map[string]string{"mode": "sized", "request_id": trace.ID(ctx)})
+				return result, err
+			}
+
+			m.noteMiss(time.Since(genStart))
+
+			served := &ServedParams{PreParamsData: params, Source: SourceGenerated}
+			result = append(result, served)
+			req.served(served)
+			trace.Logf(ctx, "Generated parameter set %d at %d/%d bits (%d/%d, duration: %s)", params.Provenance.Seq, primeBits, paillierBits, len(result), count, params.GenerationDuration)
+		}
+		return result, nil
+	})
+}
@@ -0,0 +1,542 @@
+package pool
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/shred"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+const (
+	// Snapshot files are <instance>.<prime bits>-<paillier bits>.<UTC time>.snapshot
+	snapshotExt        = ".snapshot"
+	snapshotTimeFormat = "20060102T150405Z"
+
+	// snapshotCommandTimeout bounds SnapshotCommand, e.g. a slow upload
+	snapshotCommandTimeout = 10 * time.Minute
+)
+
+// ErrSnapshotsDisabled is returned for snapshot operations without SnapshotDir
+var ErrSnapshotsDisabled = errors.New("pool snapshots are not configured")
+
+// ErrSnapshotNotFound is returned by RestoreSnapshot for unknown snapshots
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// ErrSnapshotUncovered is returned by RestoreSnapshot for snapshots taken
+// before the oldest entry kept in the removal log, which could serve items
+// twice
+var ErrSnapshotUncovered = errors.New("snapshot is older than the removal log")
+
+// ErrSnapshotCommand is returned by TakeSnapshot when the snapshot was
+// written but SnapshotCommand failed
+var ErrSnapshotCommand = errors.New("snapshot command failed")
+
+// Snapshot describes a pool snapshot in the snapshot directory
+type Snapshot struct {
+	Name    string    `json:"name"`
+	TakenAt time.Time `json:"taken_at"`
+	Bytes   int64     `json:"bytes"`
+}
+
+// SnapshotRestore reports what became of the items of a restored snapshot
+type SnapshotRestore struct {
+	Restored      int `json:"restored"`       // Added to the pool
+	AlreadyServed int `json:"already_served"` // Left the pool after the snapshot was taken
+	InPool        int `json:"in_pool"`        // Still in the pool
+	Skipped       int `json:"skipped"`        // Failed validation, or the pool was full
+}
+
+// SnapshotStatus describes scheduled snapshots for the pool status
+type SnapshotStatus struct {
+	Dir       string    `json:"dir,omitempty"`
+	Last      time.Time `json:"last"`
+	Failures  int64     `json:"failures"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// snapshotFile is the content of a snapshot, sealed with the at-rest key
+type snapshotFile struct {
+	TakenAt   time.Time        `json:"taken_at"`
+	Instance  string           `json:"instance"`
+	PreParams []*PreParamsData `json:"pre_params"`
+}
+
+// snapshotState serializes snapshots and tracks their outcome. The outcome
+// and the removals not yet logged have mutexes of their own, taken while
+// m.mu is held, so they are never held while waiting for m.mu. logMu
+// serializes the removal log file and is never taken while m.mu is held.
+type snapshotState struct {
+	mu sync.Mutex
+
+	statMu    sync.Mutex
+	last      time.Time
+	failures  int64
+	lastError string
+
+	pendingMu sync.Mutex
+	pending   strings.Builder // Removal log lines not yet written
+
+	logMu sync.Mutex
+}
+
+// record notes the outcome of a snapshot
+func (s *snapshotState) record(taken time.Time, err error) {
+	if err != nil {
+		s.fail(err)
+		return
+	}
+	s.statMu.Lock()
+	defer s.statMu.Unlock()
+	s.last, s.lastError = taken, ""
+}
+
+// fail counts a failed snapshot operation, such as a snapshot, an expired
+// snapshot that could not be removed or a removal log write
+func (s *snapshotState) fail(err error) {
+	s.statMu.Lock()
+	defer s.statMu.Unlock()
+	s.failures++
+	s.lastError = err.Error()
+}
+
+// snapshotProfile names the bit sizes of the pool in snapshot file names
+func (m *Manager) snapshotProfile() string {
+	return fmt.Sprintf("%d-%d", m.config.PrimeBitSize, m.config.PaillierBitSize)
+}
+
+// parseSnapshotName splits a snapshot file name into the instance that took
+// it, its profile and when it was taken
+func parseSnapshotName(name string) (instance, profile string, taken time.Time, ok bool) {
+	rest, found := strings.CutSuffix(name, snapshotExt)
+	if !found {
+		return "", "", time.Time{}, false
+	}
+	i := strings.LastIndexByte(rest, '.')
+	if i < 0 {
+		return "", "", time.Time{}, false
+	}
+	taken, err := time.Parse(snapshotTimeFormat, rest[i+1:])
+	if err != nil {
+		return "", "", time.Time{}, false
+	}
+	rest = rest[:i]
+	j := strings.LastIndexByte(rest, '.')
+	if j <= 0 {
+		return "", "", time.Time{}, false
+	}
+	return rest[:j], rest[j+1:], taken, true
+}
+
+// removalLogPath returns the log of items that left the pool of instance,
+// kept next to its snapshots
+func (m *Manager) removalLogPath(instance string) string {
+	return filepath.Join(m.config.SnapshotDir, instance+"."+m.snapshotProfile()+".removed")
+}
+
+// snapshotLoop takes a snapshot every SnapshotInterval until the manager
+// stops. The first one is due an interval after the newest snapshot of this
+// instance, or after start if there is none.
+func (m *Manager) snapshotLoop() {
+	interval := m.config.SnapshotInterval
+	wait := interval
+	if own, _ := m.ownSnapshots(); len(own) > 0 {
+		wait = max(time.Until(own[len(own)-1].TakenAt.Add(interval)), 0)
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			m.TakeSnapshot(trace.WithID(context.Background(), trace.NewID()))
+			timer.Reset(interval)
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// TakeSnapshot writes an encrypted snapshot of the pool to SnapshotDir,
+// removes the oldest beyond SnapshotKeep and runs SnapshotCommand. If only
+// the command fails, the snapshot is returned with ErrSnapshotCommand.
+func (m *Manager) TakeSnapshot(ctx context.Context) (Snapshot, error) {
+	if m.config.SnapshotDir == "" {
+		return Snapshot{}, ErrSnapshotsDisabled
+	}
+	s := &m.snapshots
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap, items, err := m.writeSnapshot()
+	s.record(snap.TakenAt, err)
+	if err != nil {
+		trace.Logf(ctx, "Failed to write pool snapshot: %v", err)
+		m.errors.Record(errjournal.SeverityError, "snapshot", err, map[string]string{"op": "write", "dir": m.config.SnapshotDir})
+		return Snapshot{}, err
+	}
+	trace.Logf(ctx, "Pool snapshot written (file: %s, items: %d)", snap.Name, items)
+	m.expireSnapshots(ctx)
+
+	if m.config.SnapshotCommand != "" {
+		if err := m.runSnapshotCommand(filepath.Join(m.config.SnapshotDir, snap.Name), snap.Name); err != nil {
+			s.record(snap.TakenAt, err)
+			trace.Logf(ctx, "Pool snapshot %s: %v", snap.Name, err)
+			m.errors.Record(errjournal.SeverityError, "snapshot", err, map[string]string{"op": "command", "file": snap.Name})
+			return snap, err
+		}
+	}
+	return snap, nil
+}
+
+// writeSnapshot seals the pool items and writes them to a new snapshot
+// file, returning it and the number of items it holds
+func (m *Manager) writeSnapshot() (Snapshot, int, error) {
+	m.mu.RLock()
+	f := snapshotFile{TakenAt: time.Now().UTC(), Instance: m.instanceID, PreParams: m.preParams}
+	data, err := json.Marshal(f)
+	m.mu.RUnlock()
+	if err != nil {
+		return Snapshot{}, 0, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if data, err = m.cipher.Seal(data, sealSnapshot); err != nil {
+		return Snapshot{}, 0, fmt.Errorf("failed to encrypt snapshot: %w", err)
+	}
+
+	name := fmt.Sprintf("%s.%s.%s%s", f.Instance, m.snapshotProfile(), f.TakenAt.Format(snapshotTimeFormat), snapshotExt)
+	path := filepath.Join(m.config.SnapshotDir, name)
+	if err := os.MkdirAll(m.config.SnapshotDir, 0700); err != nil {
+		return Snapshot{}, 0, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		os.Remove(tmp)
+		return Snapshot{}, 0, fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := syncFile(tmp); err != nil {
+		os.Remove(tmp)
+		return Snapshot{}, 0, fmt.Errorf("failed to sync %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return Snapshot{}, 0, fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return Snapshot{Name: name, TakenAt: f.TakenAt, Bytes: int64(len(data))}, len(f.PreParams), nil
+}
+
+// runSnapshotCommand runs SnapshotCommand for a new snapshot
+func (m *Manager) runSnapshotCommand(path, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotCommandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", m.config.SnapshotCommand)
+	cmd.Env = append(os.Environ(), "PRIME_SNAPSHOT_FILE="+path, "PRIME_SNAPSHOT_NAME="+name)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %v: %s", ErrSnapshotCommand, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ListSnapshots returns the snapshots of the pool's bit sizes in
+// SnapshotDir, newest first, including those of other instances
+func (m *Manager) ListSnapshots() ([]Snapshot, error) {
+	if m.config.SnapshotDir == "" {
+		return nil, ErrSnapshotsDisabled
+	}
+	snaps, err := m.readSnapshotDir(func(string) bool { return true })
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].TakenAt.After(snaps[j].TakenAt) })
+	return snaps, err
+}
+
+// ownSnapshots returns the snapshots this instance took, oldest first
+func (m *Manager) ownSnapshots() ([]Snapshot, error) {
+	snaps, err := m.readSnapshotDir(func(instance string) bool { return instance == m.instanceID })
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].TakenAt.Before(snaps[j].TakenAt) })
+	return snaps, err
+}
+
+// readSnapshotDir returns the snapshots of the pool's profile taken by the
+// instances keep accepts
+func (m *Manager) readSnapshotDir(keep func(instance string) bool) ([]Snapshot, error) {
+	entries, err := os.ReadDir(m.config.SnapshotDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snaps []Snapshot
+	for _, e := range entries {
+		instance, profile, taken, ok := parseSnapshotName(e.Name())
+		if !ok || e.IsDir() || profile != m.snapshotProfile() || !keep(instance) {
+			continue
+		}
+		snap := Snapshot{Name: e.Name(), TakenAt: taken}
+		if info, err := e.Info(); err == nil {
+			snap.Bytes = info.Size()
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, nil
+}
+
+// expireSnapshots removes this instance's snapshots beyond SnapshotKeep and
+// the removal log entries older than the oldest one kept, which no kept
+// snapshot holds
+func (m *Manager) expireSnapshots(ctx context.Context) {
+	own, err := m.ownSnapshots()
+	if err != nil || len(own) == 0 {
+		return
+	}
+	if extra := len(own) - m.config.SnapshotKeep; extra > 0 {
+		for _, snap := range own[:extra] {
+			path := filepath.Join(m.config.SnapshotDir, snap.Name)
+			var err error
+			if m.config.SecureDelete {
+				err = shred.File(path)
+			} else {
+				err = os.Remove(path)
+			}
+			if err != nil && !os.IsNotExist(err) {
+				err = fmt.Errorf("failed to remove expired snapshot %s: %w", snap.Name, err)
+				trace.Logf(ctx, "%v", err)
+				m.snapshots.fail(err)
+				m.errors.Record(errjournal.SeverityError, "snapshot", err, map[string]string{"op": "expire", "file": snap.Name})
+			}
+		}
+		own = own[extra:]
+	}
+
+	if err := m.pruneRemovalLog(own[0].TakenAt); err != nil {
+		trace.Logf(ctx, "Failed to prune snapshot removal log: %v", err)
+		m.errors.Record(errjournal.SeverityWarning, "snapshot", err, map[string]string{"op": "prune", "file": m.removalLogPath(m.instanceID)})
+	}
+}
+
+// logSnapshotRemovals queues items leaving the pool for the removal log, so
+// a restored snapshot never brings back an item served after it was taken.
+// The log is written by flushSnapshotRemovals once m.mu is released.
+// Caller must hold m.mu.
+func (m *Manager) logSnapshotRemovals(ctx context.Context, removed []*PreParamsData) {
+	if m.config.SnapshotDir == "" || len(removed) == 0 {
+		return
+	}
+	s := &m.snapshots
+	now := time.Now().UnixNano()
+	s.pendingMu.Lock()
+	for _, item := range removed {
+		fmt.Fprintf(&s.pending, "%d %s\n", now, checksum(item))
+	}
+	s.pendingMu.Unlock()
+	go m.flushSnapshotRemovals(ctx)
+}
+
+// flushSnapshotRemovals appends the queued removals to the removal log,
+// keeping them queued if the write fails
+func (m *Manager) flushSnapshotRemovals(ctx context.Context) {
+	s := &m.snapshots
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	if err := m.flushSnapshotRemovalsLocked(); err != nil {
+		trace.Logf(ctx, "Failed to record removed items for snapshots: %v", err)
+		m.errors.Record(errjournal.SeverityError, "snapshot", err, map[string]string{"op": "removal_log", "file": m.removalLogPath(m.instanceID)})
+	}
+}
+
+// flushSnapshotRemovalsLocked appends the queued removals to the removal
+// log. Failures are counted in the snapshot status.
+// Caller must hold m.snapshots.logMu.
+func (m *Manager) flushSnapshotRemovalsLocked() error {
+	s := &m.snapshots
+	s.pendingMu.Lock()
+	lines := s.pending.String()
+	s.pending.Reset()
+	s.pendingMu.Unlock()
+	if lines == "" {
+		return nil
+	}
+
+	err := os.MkdirAll(m.config.SnapshotDir, 0700)
+	if err == nil {
+		var f *os.File
+		if f, err = os.OpenFile(m.removalLogPath(m.instanceID), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600); err == nil {
+			_, err = f.WriteString(lines)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+	}
+	if err != nil {
+		// Keep them for the next attempt, ahead of removals queued meanwhile
+		s.pendingMu.Lock()
+		rest := s.pending.String()
+		s.pending.Reset()
+		s.pending.WriteString(lines + rest)
+		s.pendingMu.Unlock()
+		err = fmt.Errorf("failed to write snapshot removal log: %w", err)
+		s.fail(err)
+	}
+	return err
+}
+
+// readRemovalLog returns the checksums in a removal log with the time each
+// item left the pool, and the time (Unix nanoseconds) since which the log
+// is complete: 0 unless pruned
+func readRemovalLog(path string) (map[string]int64, int64, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	removed := make(map[string]int64)
+	var since int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		at, sum, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue // Torn by a crash mid-append
+		}
+		if at == "since" {
+			at, sum = sum, ""
+		}
+		nanos, err := strconv.ParseInt(at, 10, 64)
+		if err != nil {
+			continue
+		}
+		if sum == "" {
+			since = nanos
+			continue
+		}
+		removed[sum] = nanos
+	}
+	return removed, since, scanner.Err()
+}
+
+// pruneRemovalLog drops removal log entries from before since, noting that
+// older snapshots can no longer be restored safely
+func (m *Manager) pruneRemovalLog(since time.Time) error {
+	path := m.removalLogPath(m.instanceID)
+	s := &m.snapshots
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	if err := m.flushSnapshotRemovalsLocked(); err != nil {
+		return err
+	}
+
+	removed, _, err := readRemovalLog(path)
+	if err != nil || removed == nil {
+		return err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "since %d\n", since.UnixNano())
+	for sum, nanos := range removed {
+		if nanos >= since.UnixNano() {
+			fmt.Fprintf(&b, "%d %s\n", nanos, sum)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RestoreSnapshot adds the items of a snapshot in SnapshotDir back to the
+// pool up to MaxPoolSize, e.g. after the pool directory was lost. Items
+// that left the pool after the snapshot was taken, as recorded in the
+// removal log next to it, and items still in the pool are skipped. The
+// rest run the full primality validation and are flagged imported, like
+// ImportItems. Snapshots older than the removal log (e.g. copied back from
+// an object store after expiring) are refused.
+func (m *Manager) RestoreSnapshot(ctx context.Context, name string) (SnapshotRestore, error) {
+	if m.config.SnapshotDir == "" {
+		return SnapshotRestore{}, ErrSnapshotsDisabled
+	}
+	instance, profile, _, ok := parseSnapshotName(name)
+	if !ok || name != filepath.Base(name) || profile != m.snapshotProfile() {
+		return SnapshotRestore{}, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+	path := filepath.Join(m.config.SnapshotDir, name)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return SnapshotRestore{}, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+	if err != nil {
+		return SnapshotRestore{}, err
+	}
+	if data, _, err = m.openFile(data, sealSnapshot, path); err != nil {
+		return SnapshotRestore{}, err
+	}
+	var f snapshotFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return SnapshotRestore{}, fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+
+	// Removals still queued must be in the log before it is read
+	m.snapshots.logMu.Lock()
+	err = m.flushSnapshotRemovalsLocked()
+	var removed map[string]int64
+	var since int64
+	if err == nil {
+		removed, since, err = readRemovalLog(m.removalLogPath(instance))
+	}
+	m.snapshots.logMu.Unlock()
+	if err != nil {
+		return SnapshotRestore{}, fmt.Errorf("failed to read removal log: %w", err)
+	}
+	if f.TakenAt.UnixNano() < since {
+		return SnapshotRestore{}, fmt.Errorf("%w: it only records items served since %s", ErrSnapshotUncovered, time.Unix(0, since).UTC().Format(time.RFC3339))
+	}
+
+	var res SnapshotRestore
+	var candidates []*PreParamsData
+	for _, item := range f.PreParams {
+		if _, gone := removed[checksum(item)]; gone {
+			res.AlreadyServed++
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+	// Items still in the pool need no validation
+	m.mu.RLock()
+	var fresh []*PreParamsData
+	for _, item := range candidates {
+		if m.duplicateLocked(item) {
+			res.InPool++
+			continue
+		}
+		fresh = append(fresh, item)
+	}
+	m.mu.RUnlock()
+	res.Restored = m.addItems(ctx, fresh, ImportSourceSnapshot, true, nil)
+	res.Skipped = len(fresh) - res.Restored
+
+	log.Printf("Restored %d parameters from snapshot %s (already served: %d, in pool: %d, skipped: %d)",
+		res.Restored, name, res.AlreadyServed, res.InPool, res.Skipped)
+	return res, nil
+}
+
+// SnapshotStatus describes the scheduled snapshots since start
+func (m *Manager) SnapshotStatus() SnapshotStatus {
+	s := &m.snapshots
+	s.statMu.Lock()
+	defer s.statMu.Unlock()
+	return SnapshotStatus{Dir: m.config.SnapshotDir, Last: s.last, Failures: s.failures, LastError: s.lastError}
+}
@@ -0,0 +1,154 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TEENet-io/prime-service/internal/atrest"
+)
+
+const testEncryptionKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+// snapshotConfig returns a file-backed pool configuration writing
+// encrypted snapshots to dir
+func snapshotConfig(t *testing.T, dir string) SimpleConfig {
+	t.Helper()
+	cfg := testConfig(t)
+	cfg.Storage = StorageFile
+	cfg.SnapshotDir = dir
+	cfg.EncryptionKey = testEncryptionKey
+	return cfg
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	tests := []struct {
+		name   string
+		served uint32 // Items served after the snapshot was taken
+		fresh  bool   // Restore into a replacement manager with an empty pool
+		want   SnapshotRestore
+	}{
+		{name: "unchanged pool", want: SnapshotRestore{InPool: 3}},
+		{name: "items served", served: 1, want: SnapshotRestore{AlreadyServed: 1, InPool: 2}},
+		{name: "all served", served: 3, want: SnapshotRestore{AlreadyServed: 3}},
+		{name: "replacement", fresh: true, want: SnapshotRestore{Restored: 3}},
+		{name: "replacement after serving", served: 2, fresh: true, want: SnapshotRestore{Restored: 1, AlreadyServed: 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			m := newTestManager(t, snapshotConfig(t, dir), testItems(t))
+			snap, err := m.TakeSnapshot(ctx)
+			if err != nil {
+				t.Fatalf("TakeSnapshot() = %v", err)
+			}
+
+			if tt.served > 0 {
+				if _, err := m.GetPreParams(ctx, Request{Count: tt.served, NoGenerate: true}); err != nil {
+					t.Fatalf("GetPreParams() = %v", err)
+				}
+				// Removals reach the log when the pool is saved
+				m.saveToDisk(ctx)
+			}
+			if tt.fresh {
+				m.Stop()
+				m = newTestManager(t, snapshotConfig(t, dir), nil)
+			}
+
+			got, err := m.RestoreSnapshot(ctx, snap.Name)
+			if err != nil {
+				t.Fatalf("RestoreSnapshot() = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("RestoreSnapshot() = %+v, want %+v", got, tt.want)
+			}
+			if want := tt.want.Restored + tt.want.InPool; m.Size() != want {
+				t.Fatalf("pool holds %d items after restore, want %d", m.Size(), want)
+			}
+		})
+	}
+}
+
+func TestRestoreSnapshotRefused(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    func(t *testing.T, dir, name string) string // Returns the name to restore
+		wantErr error
+		errMsg  string
+	}{
+		{
+			name:    "unknown snapshot",
+			file:    func(t *testing.T, dir, name string) string { return strings.Replace(name, "test-instance", "other", 1) },
+			wantErr: ErrSnapshotNotFound,
+		},
+		{
+			name: "other profile",
+			file: func(t *testing.T, dir, name string) string {
+				return strings.Replace(name, ".256-512.", ".512-1024.", 1)
+			},
+			wantErr: ErrSnapshotNotFound,
+		},
+		{
+			name:    "path outside the directory",
+			file:    func(t *testing.T, dir, name string) string { return "../" + name },
+			wantErr: ErrSnapshotNotFound,
+		},
+		{
+			name: "plaintext snapshot",
+			file: func(t *testing.T, dir, name string) string {
+				writeFile(t, filepath.Join(dir, name), []byte(`{"pre_params":[]}`))
+				return name
+			},
+			wantErr: atrest.ErrPlaintext,
+		},
+		{
+			name: "tampered snapshot",
+			file: func(t *testing.T, dir, name string) string {
+				path := filepath.Join(dir, name)
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				data[len(data)-1] ^= 0x01
+				writeFile(t, path, data)
+				return name
+			},
+			errMsg: "corrupt or tampered",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			m := newTestManager(t, snapshotConfig(t, dir), testItems(t)[:1])
+			snap, err := m.TakeSnapshot(ctx)
+			if err != nil {
+				t.Fatalf("TakeSnapshot() = %v", err)
+			}
+
+			_, err = m.RestoreSnapshot(ctx, tt.file(t, dir, snap.Name))
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RestoreSnapshot() = %v, want %v", err, tt.wantErr)
+				}
+			case err == nil || !strings.Contains(err.Error(), tt.errMsg):
+				t.Fatalf("RestoreSnapshot() = %v, want error containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+// writeFile replaces the contents of path
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
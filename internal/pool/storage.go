@@ -0,0 +1,95 @@
+package pool
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/paramcheck"
+)
+
+// Storage backends
+const (
+	StorageFile   = "file"   // Persist the pool and journals under PoolDir
+	StorageMemory = "memory" // Keep everything in process; nothing is written to disk
+	StorageRedis  = "redis"  // Keep the pool in a Redis list shared by replicas, the rest under PoolDir
+)
+
+// dataPath returns the path of a file under the pool directory, or "" with
+// memory storage, which every persisting component treats as "don't persist"
+func dataPath(cfg *SimpleConfig, name string) string {
+	if cfg.Storage == StorageMemory {
+		return ""
+	}
+	return filepath.Join(cfg.PoolDir, name)
+}
+
+// poolFile is the on-disk format of a pool
+type poolFile struct {
+	PreParams []*PreParamsData `json:"pre_params"`
+	SavedAt   time.Time        `json:"saved_at"`
+	Config    *SimpleConfig    `json:"config"`
+
+	// Items set aside by PinItem, never served
+	Pinned []*pinnedItem `json:"pinned,omitempty"`
+
+	// Lifetime totals when saved, compared with the ledger at startup
+	TotalGenerated int64 `json:"total_generated"`
+	TotalServed    int64 `json:"total_served"`
+}
+
+// poolFilePath returns where the pool for a parameter profile is stored.
+// Each combination of prime and Paillier bit sizes has its own pool file, so
+// changing the bit sizes never mixes incompatible items.
+func poolFilePath(dir string, primeBits, paillierBits int) string {
+	return filepath.Join(dir, "pools", fmt.Sprintf("%d-%d.json", primeBits, paillierBits))
+}
+
+// BitSizes returns the prime and Paillier bit sizes of the items the pool
+// holds and generates
+func (m *Manager) BitSizes() (primeBits, paillierBits int) {
+	return m.config.PrimeBitSize, m.config.PaillierBitSize
+}
+
+// itemProfile returns the prime and Paillier bit sizes an item was generated with
+func itemProfile(item *PreParamsData) (primeBits, paillierBits int) {
+	safePrime := new(big.Int).Lsh(item.P, 1)
+	safePrime.Add(safePrime, big.NewInt(1))
+	return safePrime.BitLen(), 2 * item.PaillierKey.P.BitLen()
+}
+
+// checkProfile refuses an item generated with other bit sizes than the
+// pool's, which would be served as if it had the pool's
+func (m *Manager) checkProfile(item *PreParamsData) error {
+	if p, q := itemProfile(item); p != m.config.PrimeBitSize || q != m.config.PaillierBitSize {
+		return fmt.Errorf("parameter set has profile %d-%d, expected %d-%d", p, q, m.config.PrimeBitSize, m.config.PaillierBitSize)
+	}
+	return nil
+}
+
+// validateItem checks that an item is complete and internally consistent
+func validateItem(item *PreParamsData) error {
+	if item == nil || item.PaillierKey == nil {
+		return fmt.Errorf("incomplete parameter set")
+	}
+	return paramcheck.Check(itemParams(item))
+}
+
+// itemParams returns the integers of an item for paramcheck
+func itemParams(item *PreParamsData) paramcheck.Params {
+	return paramcheck.Params{
+		PaillierN:       item.PaillierKey.N,
+		PaillierP:       item.PaillierKey.P,
+		PaillierQ:       item.PaillierKey.Q,
+		PaillierPhiN:    item.PaillierKey.PhiN,
+		PaillierLambdaN: item.PaillierKey.LambdaN,
+		NTildei:         item.NTildei,
+		H1i:             item.H1i,
+		H2i:             item.H2i,
+		Alpha:           item.Alpha,
+		Beta:            item.Beta,
+		P:               item.P,
+		Q:               item.Q,
+	}
+}
@@ -0,0 +1,282 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// ErrUnknownNamespace is returned for requests naming a namespace that is
+// not one of the configured tenants
+var ErrUnknownNamespace = errors.New("unknown tenant namespace")
+
+// ErrQuotaExceeded is returned for requests that would take their
+// namespace past its hourly or daily quota
+var ErrQuotaExceeded = errors.New("tenant quota exceeded")
+
+// tenantSaveDelay batches the usage writes of the requests served within
+// it into one; Stop writes what is pending
+const tenantSaveDelay = 5 * time.Second
+
+// TenantUsage is the accounting of one namespace. The hourly and daily
+// counts cover the current UTC hour and day and include items reserved by
+// requests still in progress.
+type TenantUsage struct {
+	Namespace   string    `json:"namespace"`
+	Served      int64     `json:"served"` // Lifetime items served
+	Hour        time.Time `json:"hour"`
+	HourlyUsed  int       `json:"hourly_used"`
+	HourlyQuota int       `json:"hourly_quota,omitempty"` // Zero: unlimited
+	Day         time.Time `json:"day"`
+	DailyUsed   int       `json:"daily_used"`
+	DailyQuota  int       `json:"daily_quota,omitempty"`
+}
+
+// tenantAccounts partitions the items served by a pool per namespace and
+// enforces the quotas of the configured tenants. Usage is persisted next to
+// the pool file, so restarts do not reset the quotas.
+type tenantAccounts struct {
+	mu     sync.Mutex
+	quotas map[string]config.TenantConfig
+	usage  map[string]*TenantUsage
+
+	saveMu sync.Mutex // Serializes writes of path
+	path   string
+	timer  *time.Timer // Pending tenantSaveDelay save, guarded by mu
+}
+
+// tenantsPath returns the tenant usage stored next to a pool file
+func tenantsPath(poolFile string) string {
+	return strings.TrimSuffix(poolFile, ".json") + ".tenants.json"
+}
+
+// configure replaces the tenants and their quotas; usage of namespaces no
+// longer configured is kept, so they resume their counts if added back
+func (t *tenantAccounts) configure(tenants []config.TenantConfig) {
+	quotas := make(map[string]config.TenantConfig, len(tenants))
+	for _, tenant := range tenants {
+		quotas[tenant.Name] = tenant
+	}
+	t.mu.Lock()
+	t.quotas = quotas
+	t.mu.Unlock()
+}
+
+// load reads the persisted usage, if any
+func (t *tenantAccounts) load() error {
+	if t.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var usage []*TenantUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return fmt.Errorf("failed to parse tenant usage %s: %w", t.path, err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage = make(map[string]*TenantUsage, len(usage))
+	for _, u := range usage {
+		t.usage[u.Namespace] = u
+	}
+	return nil
+}
+
+// save writes the usage atomically (temp file + rename)
+func (t *tenantAccounts) save() error {
+	t.saveMu.Lock()
+	defer t.saveMu.Unlock()
+
+	data, err := json.Marshal(t.snapshot(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to marshal tenant usage: %w", err)
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write tenant usage: %w", err)
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		return fmt.Errorf("failed to replace tenant usage: %w", err)
+	}
+	return nil
+}
+
+// accountLocked returns the usage of namespace with its windows rolled
+// over to now. Caller must hold t.mu.
+func (t *tenantAccounts) accountLocked(namespace string, now time.Time) *TenantUsage {
+	u, ok := t.usage[namespace]
+	if !ok {
+		if t.usage == nil {
+			t.usage = make(map[string]*TenantUsage)
+		}
+		u = &TenantUsage{Namespace: namespace}
+		t.usage[namespace] = u
+	}
+	hour, day := now.UTC().Truncate(time.Hour), now.UTC().Truncate(24*time.Hour)
+	if !u.Hour.Equal(hour) {
+		u.Hour, u.HourlyUsed = hour, 0
+	}
+	if !u.Day.Equal(day) {
+		u.Day, u.DailyUsed = day, 0
+	}
+	return u
+}
+
+// authorize refuses a namespace that is not configured, without charging
+// it anything
+func (t *tenantAccounts) authorize(namespace string) error {
+	if namespace == "" || namespace == config.DefaultNamespace {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.quotas[namespace]; !ok {
+		return fmt.Errorf("%w %q", ErrUnknownNamespace, namespace)
+	}
+	return nil
+}
+
+// reserve charges count items to namespace ("": DefaultNamespace) up front,
+// so concurrent requests cannot overrun its quotas together. The returned
+// settle must be called with the number of items actually served, which
+// gives back the rest.
+func (t *tenantAccounts) reserve(namespace string, count int, now time.Time) (settle func(served int), err error) {
+	if namespace == "" {
+		namespace = config.DefaultNamespace
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	quota, ok := t.quotas[namespace]
+	if !ok && namespace != config.DefaultNamespace {
+		return nil, fmt.Errorf("%w %q", ErrUnknownNamespace, namespace)
+	}
+	u := t.accountLocked(namespace, now)
+	if quota.HourlyQuota > 0 && u.HourlyUsed+count > quota.HourlyQuota {
+		return nil, fmt.Errorf("%w: namespace %s has %d of %d items left this hour, requested %d",
+			ErrQuotaExceeded, namespace, max(quota.HourlyQuota-u.HourlyUsed, 0), quota.HourlyQuota, count)
+	}
+	if quota.DailyQuota > 0 && u.DailyUsed+count > quota.DailyQuota {
+		return nil, fmt.Errorf("%w: namespace %s has %d of %d items left today, requested %d",
+			ErrQuotaExceeded, namespace, max(quota.DailyQuota-u.DailyUsed, 0), quota.DailyQuota, count)
+	}
+	u.HourlyUsed += count
+	u.DailyUsed += count
+	hour, day := u.Hour, u.Day
+
+	return func(served int) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		u := t.usage[namespace]
+		u.Served += int64(served)
+		// Windows that rolled over meanwhile no longer hold the reservation
+		if u.Hour.Equal(hour) {
+			u.HourlyUsed -= count - served
+		}
+		if u.Day.Equal(day) {
+			u.DailyUsed -= count - served
+		}
+	}, nil
+}
+
+// snapshot returns the usage of every namespace seen or configured, by name
+func (t *tenantAccounts) snapshot(now time.Time) []TenantUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	names := make(map[string]bool, len(t.usage)+len(t.quotas))
+	for name := range t.usage {
+		names[name] = true
+	}
+	for name := range t.quotas {
+		names[name] = true
+	}
+	result := make([]TenantUsage, 0, len(names))
+	for name := range names {
+		u := *t.accountLocked(name, now)
+		quota := t.quotas[name]
+		u.HourlyQuota, u.DailyQuota = quota.HourlyQuota, quota.DailyQuota
+		result = append(result, u)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Namespace < result[j].Namespace })
+	return result
+}
+
+// TenantUsage returns the usage and quotas of every namespace the pool has
+// served or has configured
+func (m *Manager) TenantUsage() []TenantUsage {
+	return m.tenants.snapshot(time.Now())
+}
+
+// allocateForTenant allocates a request within the quotas of its namespace
+// and charges the items served to it
+func (m *Manager) allocateForTenant(ctx context.Context, count uint32, req Request, allocate func(ctx context.Context, count uint32, req Request) ([]*ServedParams, error)) ([]*ServedParams, error) {
+	settle, err := m.tenants.reserve(req.Namespace, int(count), time.Now())
+	if err != nil {
+		trace.Logf(ctx, "Refused request for %d parameters: %v", count, err)
+		return nil, err
+	}
+
+	served, err := allocate(ctx, count, req)
+	settle(len(served))
+	if len(served) > 0 && m.tenants.path != "" {
+		m.scheduleTenantSave()
+	}
+	return served, err
+}
+
+// scheduleTenantSave writes the tenant usage tenantSaveDelay after the
+// first change since the last write
+func (m *Manager) scheduleTenantSave() {
+	t := &m.tenants
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.timer == nil {
+		t.timer = time.AfterFunc(tenantSaveDelay, m.saveTenants)
+	}
+}
+
+// saveTenants writes the tenant usage, including a pending save on Stop
+func (m *Manager) saveTenants() {
+	t := &m.tenants
+	t.mu.Lock()
+	pending := t.timer != nil
+	if pending {
+		t.timer.Stop()
+		t.timer = nil
+	}
+	t.mu.Unlock()
+	if !pending {
+		return
+	}
+
+	if err := t.save(); err != nil {
+		log.Printf("Failed to save tenant usage: %v", err)
+		m.errors.Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "tenants", "file": t.path})
+	}
+}
+
+// loadTenants restores the persisted usage of the tenants
+func (m *Manager) loadTenants() {
+	if err := m.tenants.load(); err != nil {
+		log.Printf("Failed to load tenant usage, quotas start afresh: %v", err)
+		m.errors.Record(errjournal.SeverityWarning, "persistence", err, map[string]string{"op": "tenants", "file": m.tenants.path})
+	}
+}
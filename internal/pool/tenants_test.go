@@ -0,0 +1,144 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+)
+
+func TestTenantReserve(t *testing.T) {
+	type step struct {
+		namespace string
+		count     int
+		at        time.Duration // After the start of a UTC day
+		served    int           // Passed to settle
+		wantErr   error
+	}
+	tests := []struct {
+		name    string
+		tenants []config.TenantConfig
+		steps   []step
+	}{
+		{
+			name:  "default namespace unlimited",
+			steps: []step{{"", 1000, 0, 1000, nil}, {"", 1000, 0, 1000, nil}},
+		},
+		{
+			name:    "unknown namespace",
+			tenants: []config.TenantConfig{{Name: "team-a", HourlyQuota: 5}},
+			steps:   []step{{"team-b", 1, 0, 1, ErrUnknownNamespace}},
+		},
+		{
+			name:    "tenant without quota",
+			tenants: []config.TenantConfig{{Name: "team-a"}},
+			steps:   []step{{"team-a", 1000, 0, 1000, nil}},
+		},
+		{
+			name:    "hourly quota",
+			tenants: []config.TenantConfig{{Name: "team-a", HourlyQuota: 5}},
+			steps: []step{
+				{"team-a", 3, 0, 3, nil},
+				{"team-a", 3, time.Minute, 0, ErrQuotaExceeded},
+				{"team-a", 2, 2 * time.Minute, 2, nil},
+				{"team-a", 1, 3 * time.Minute, 0, ErrQuotaExceeded},
+			},
+		},
+		{
+			name:    "hourly quota rolls over",
+			tenants: []config.TenantConfig{{Name: "team-a", HourlyQuota: 5}},
+			steps: []step{
+				{"team-a", 5, 0, 5, nil},
+				{"team-a", 1, 59 * time.Minute, 0, ErrQuotaExceeded},
+				{"team-a", 5, time.Hour, 5, nil},
+			},
+		},
+		{
+			name:    "daily quota across hours",
+			tenants: []config.TenantConfig{{Name: "team-a", HourlyQuota: 5, DailyQuota: 8}},
+			steps: []step{
+				{"team-a", 5, 0, 5, nil},
+				{"team-a", 4, time.Hour, 0, ErrQuotaExceeded},
+				{"team-a", 3, time.Hour, 3, nil},
+				{"team-a", 1, 23 * time.Hour, 0, ErrQuotaExceeded},
+				{"team-a", 5, 24 * time.Hour, 5, nil},
+			},
+		},
+		{
+			name:    "unserved items are given back",
+			tenants: []config.TenantConfig{{Name: "team-a", HourlyQuota: 5}},
+			steps: []step{
+				{"team-a", 5, 0, 1, nil},
+				{"team-a", 4, time.Minute, 4, nil},
+				{"team-a", 1, 2 * time.Minute, 0, ErrQuotaExceeded},
+			},
+		},
+		{
+			name: "quotas are per namespace",
+			tenants: []config.TenantConfig{
+				{Name: "team-a", HourlyQuota: 2},
+				{Name: "team-b", HourlyQuota: 2},
+			},
+			steps: []step{
+				{"team-a", 2, 0, 2, nil},
+				{"team-b", 2, 0, 2, nil},
+				{"team-a", 1, 0, 0, ErrQuotaExceeded},
+			},
+		},
+	}
+
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var accounts tenantAccounts
+			accounts.configure(tt.tenants)
+			for i, s := range tt.steps {
+				settle, err := accounts.reserve(s.namespace, s.count, day.Add(s.at))
+				if !errors.Is(err, s.wantErr) {
+					t.Fatalf("step %d: reserve() = %v, want %v", i, err, s.wantErr)
+				}
+				if err == nil {
+					settle(s.served)
+				}
+			}
+		})
+	}
+}
+
+func TestTenantQuotaRequests(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Tenants = []config.TenantConfig{{Name: "team-a", HourlyQuota: 2}}
+	m := newTestManager(t, cfg, testItems(t))
+
+	tests := []struct {
+		namespace string
+		count     uint32
+		want      int
+		wantErr   error
+	}{
+		{"team-a", 3, 0, ErrQuotaExceeded},
+		{"team-b", 1, 0, ErrUnknownNamespace},
+		{"team-a", 2, 2, nil},
+		{"team-a", 1, 0, ErrQuotaExceeded},
+		{"", 1, 1, nil},
+	}
+	for i, tt := range tests {
+		served, err := m.GetPreParams(context.Background(), Request{Count: tt.count, NoGenerate: true, Namespace: tt.namespace})
+		if !errors.Is(err, tt.wantErr) || len(served) != tt.want {
+			t.Fatalf("request %d: served %d, %v, want %d, %v", i, len(served), err, tt.want, tt.wantErr)
+		}
+	}
+
+	for _, u := range m.TenantUsage() {
+		if u.Namespace != "team-a" {
+			continue
+		}
+		if u.Served != 2 || u.HourlyUsed != 2 {
+			t.Fatalf("team-a usage: served %d, hourly %d, want 2, 2", u.Served, u.HourlyUsed)
+		}
+		return
+	}
+	t.Fatal("no usage recorded for team-a")
+}
@@ -0,0 +1,107 @@
+package pool
+
+import (
+	"errors"
+	"log"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/TEENet-io/prime-service/internal/scheduler"
+)
+
+// errGenerationStopped abandons the items being generated when the manager
+// stops
+var errGenerationStopped = errors.New("generation stopped")
+
+// generationPause holds background generation while paused
+type generationPause struct {
+	mu     sync.Mutex
+	resume chan struct{} // Closed when generation resumes; nil unless paused
+	parked atomic.Int32  // Generations waiting at a yield point
+}
+
+// wait returns the channel closed on resuming, or nil if not paused
+func (p *generationPause) wait() chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.resume
+}
+
+// PauseGeneration pauses or resumes background generation. Paused, the
+// workers of refills and fills stop within milliseconds at the next yield
+// point of the item they generate, keeping its progress, and start no new
+// items; on-demand generation for requests carries on. The pause is not
+// persisted across restarts.
+func (m *Manager) PauseGeneration(paused bool) {
+	p := &m.pause
+	p.mu.Lock()
+	switch {
+	case paused && p.resume == nil:
+		p.resume = make(chan struct{})
+		log.Printf("Background generation paused (in flight: %d)", m.inFlight.Load())
+	case !paused && p.resume != nil:
+		close(p.resume)
+		p.resume = nil
+		log.Printf("Background generation resumed")
+	default:
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+	m.changed()
+}
+
+// GenerationPaused reports whether background generation is paused and how
+// many generations are parked at a yield point
+func (m *Manager) GenerationPaused() (paused bool, parked int) {
+	return m.pause.wait() != nil, int(m.pause.parked.Load())
+}
+
+// InFlight returns the number of items being generated, including
+// on-demand ones
+func (m *Manager) InFlight() int {
+	return int(m.inFlight.Load())
+}
+
+// backgroundYield is the yield function of items generated by refill and
+// fill workers holding job (nil: between items): it hands the slot of job
+// to waiting requests, parks the item while generation is paused, without
+// holding a slot, and abandons it once the manager stops
+func (m *Manager) backgroundYield(job *scheduler.Job) error {
+	if m.stopping() {
+		return errGenerationStopped
+	}
+	resume := m.pause.wait()
+	if resume == nil {
+		// Let serving goroutines run between slices of the search
+		runtime.Gosched()
+		if job != nil && job.Yield(m.stopCtx) != nil {
+			return errGenerationStopped
+		}
+		return nil
+	}
+	if job != nil {
+		job.Release()
+	}
+	m.pause.parked.Add(1)
+	defer m.pause.parked.Add(-1)
+	select {
+	case <-resume:
+	case <-m.stopCh:
+		return errGenerationStopped
+	}
+	if job != nil && job.Wait(m.stopCtx) != nil {
+		return errGenerationStopped
+	}
+	return nil
+}
+
+// requestYield is the yield function of items generated for requests,
+// which are abandoned once the manager stops but never paused
+func (m *Manager) requestYield() error {
+	if m.stopping() {
+		return errGenerationStopped
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/trace"
+)
+
+// insertBatch is how many validated items are inserted per hold of m.mu
+const insertBatch = 10
+
+// TakeSurplus removes up to max items above MinPoolSize from the pool so they
+// can be handed to an under-filled peer replica. A frozen pool gives nothing.
+func (m *Manager) TakeSurplus(ctx context.Context, max int) []*PreParamsData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	surplus := len(m.preParams) - m.config.MinPoolSize
+	if surplus <= 0 || max <= 0 || m.frozen.Load() {
+		return nil
+	}
+	if max > surplus {
+		max = surplus
+	}
+
+	// Give away the newest items, keeping the oldest for local serving
+	cut := len(m.preParams) - max
+	result := append([]*PreParamsData(nil), m.preParams[cut:]...)
+	m.preParams = m.preParams[:cut]
+	m.transferredOut += int64(len(result))
+	m.changed()
+	m.consumed.add(len(result))
+	m.writeLedgerLocked(ctx, result, false)
+
+	trace.Logf(ctx, "Transferred %d surplus parameters to peer (remaining: %d)", len(result), len(m.preParams))
+
+	if m.config.AutoSave {
+		go m.saveToDisk(ctx)
+	}
+
+	return result
+}
+
+// ExportItems removes up to max items from the pool, oldest first, so an
+// operator can move them to another instance. A frozen pool gives nothing.
+func (m *Manager) ExportItems(ctx context.Context, max int) []*PreParamsData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if max <= 0 || m.frozen.Load() {
+		return nil
+	}
+	if max > len(m.preParams) {
+		max = len(m.preParams)
+	}
+
+	result := append([]*PreParamsData(nil), m.preParams[:max]...)
+	m.preParams = append(m.preParams[:0:0], m.preParams[max:]...)
+	m.transferredOut += int64(len(result))
+	m.changed()
+	m.consumed.add(len(result))
+	m.writeLedgerLocked(ctx, result, false)
+
+	trace.Logf(ctx, "Exported %d parameters (remaining: %d)", len(result), len(m.preParams))
+
+	if m.config.AutoSave {
+		go m.saveToDisk(ctx)
+	}
+
+	return result
+}
+
+// ImportItems inserts items exported from another instance into the pool
+// up to MaxPoolSize, revalidating every one, and returns how many were
+// accepted. The items are tagged as admin imports, only served to requests
+// allowing imported items.
+func (m *Manager) ImportItems(ctx context.Context, items []*PreParamsData) int {
+	items = m.capToRoom(items)
+	var valid []*PreParamsData
+	for _, item := range items {
+		if m.validateAdded(ctx, item, ImportSourceAdmin, true) {
+			item.Provenance.AdminImport = true
+			valid = append(valid, item)
+		}
+	}
+	m.auditImport(ImportSourceAdmin, len(valid))
+	return m.insertBatches(ctx, valid, ImportSourceAdmin, &m.transferredIn)
+}
+
+// AddPreParams inserts items pulled from a peer replica into the pool up to
+// MaxPoolSize and returns how many were accepted
+func (m *Manager) AddPreParams(ctx context.Context, items []*PreParamsData) int {
+	return m.addItems(ctx, items, ImportSourceTransfer, false, &m.transferredIn)
+}
+
+// AddWorkerPreParams inserts items submitted by a registered worker into the
+// pool up to MaxPoolSize and returns how many were accepted. Every item is
+// fully validated, and none may claim to be generated after it was received.
+func (m *Manager) AddWorkerPreParams(ctx context.Context, items []*PreParamsData) int {
+	received := time.Now()
+	items = m.capToRoom(items)
+	var valid []*PreParamsData
+	for _, item := range items {
+		if item.GeneratedAt.After(received) {
+			item.GeneratedAt = received
+		}
+		if !m.validateAdded(ctx, item, ImportSourceWorker, false) {
+			continue
+		}
+		if err := auditItem(item); err != nil {
+			m.quarantine(ctx, item, FailureInvalid, fmt.Errorf("submitted %w", err), ImportSourceWorker)
+			continue
+		}
+		valid = append(valid, item)
+	}
+	return m.insertBatches(ctx, valid, ImportSourceWorker, &m.transferredIn)
+}
+
+// addItems validates items obtained from source outside m.mu, revalidating
+// those needsRevalidation picks (all of them with revalidateAll), inserts
+// the valid ones up to MaxPoolSize and returns how many were accepted,
+// adding them to counter (nil: none)
+func (m *Manager) addItems(ctx context.Context, items []*PreParamsData, source string, revalidateAll bool, counter *int64) int {
+	now := time.Now()
+	items = m.capToRoom(items)
+	var valid []*PreParamsData
+	imported := 0
+	for _, item := range items {
+		revalidate := revalidateAll || m.needsRevalidation(item, now)
+		if !m.validateAdded(ctx, item, source, revalidate) {
+			continue
+		}
+		if revalidate {
+			imported++
+		}
+		valid = append(valid, item)
+	}
+	m.auditImport(source, imported)
+	return m.insertBatches(ctx, valid, source, counter)
+}
+
+// capToRoom drops the items the pool has no room for, so they are not
+// validated in vain
+func (m *Manager) capToRoom(items []*PreParamsData) []*PreParamsData {
+	if room := m.MaxSize() - m.Size(); len(items) > room {
+		return items[:max(room, 0)]
+	}
+	return items
+}
+
+// validateAdded validates an item obtained from source, with revalidate
+// also running the full primality validation, and quarantines it on
+// failure. Items of another profile than the pool's are refused. It does
+// not need m.mu.
+func (m *Manager) validateAdded(ctx context.Context, item *PreParamsData, source string, revalidate bool) bool {
+	// Checksums do not survive the transfer encoding, so validate and reseal
+	item.Checksum = ""
+	if class, err := verifyItem(item); err != nil {
+		m.quarantine(ctx, item, class, err, source)
+		return false
+	}
+	if err := m.checkProfile(item); err != nil {
+		m.quarantine(ctx, item, FailureInvalid, err, source)
+		return false
+	}
+	if revalidate {
+		if err := revalidateImport(item); err != nil {
+			m.quarantine(ctx, item, FailureInvalid, err, "import")
+			return false
+		}
+	}
+	return true
+}
+
+// insertBatches inserts validated items obtained from source insertBatch at
+// a time, so adding many items never holds m.mu for long, and returns how
+// many were accepted, adding them to counter (nil: none)
+func (m *Manager) insertBatches(ctx context.Context, items []*PreParamsData, source string, counter *int64) int {
+	accepted := 0
+	for start := 0; start < len(items); start += insertBatch {
+		m.mu.Lock()
+		n := m.insertLocked(ctx, items[start:min(start+insertBatch, len(items))], source)
+		if counter != nil {
+			*counter += int64(n)
+		}
+		m.mu.Unlock()
+		accepted += n
+	}
+	return accepted
+}
+
+// insertLocked inserts validated items obtained from source up to
+// MaxPoolSize, skipping duplicates, and returns how many were accepted
+// Caller must hold m.mu.
+func (m *Manager) insertLocked(ctx context.Context, items []*PreParamsData, source string) int {
+	accepted := 0
+	for _, item := range items {
+		if len(m.preParams) >= m.config.MaxPoolSize {
+			break
+		}
+		if m.duplicateLocked(item) {
+			m.ReportAnomaly(AnomalyDuplicate, fmt.Errorf("parameter set received by %s repeats one in the pool", source))
+			continue
+		}
+		sealItem(item)
+		// Sequence numbers are local to a pool; the sender's do not apply
+		item.Provenance.Seq = 0
+		m.assignSeq(item)
+		m.preParams = append(m.preParams, item)
+		accepted++
+	}
+	m.supplied.add(accepted)
+	if accepted > 0 {
+		m.changed()
+	}
+	m.noteUnsavedLocked(accepted)
+
+	return accepted
+}
+
+// Deficit returns how many items the pool is below MinPoolSize
+func (m *Manager) Deficit() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if d := m.config.MinPoolSize - m.sizeLocked(); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// MaxSize returns the most items the pool holds, MaxPoolSize
+func (m *Manager) MaxSize() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config.MaxPoolSize
+}
+
+// Size returns the number of items in the pool
+func (m *Manager) Size() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sizeLocked()
+}
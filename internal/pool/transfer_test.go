@@ -0,0 +1,85 @@
+package pool
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestAddPreParams(t *testing.T) {
+	tests := []struct {
+		name            string
+		modify          func(cfg *SimpleConfig, items []*PreParamsData)
+		held            int // Items of the batch already in the pool
+		wantAccepted    int
+		wantQuarantined int64
+	}{
+		{name: "valid items", wantAccepted: 3},
+		{name: "other profile", modify: func(cfg *SimpleConfig, items []*PreParamsData) { cfg.PaillierBitSize = 2 * testPaillierBits }, wantQuarantined: 3},
+		{name: "corrupt item", modify: func(cfg *SimpleConfig, items []*PreParamsData) {
+			items[1].NTildei = new(big.Int).Add(items[1].NTildei, big.NewInt(2))
+		}, wantAccepted: 2, wantQuarantined: 1},
+		{name: "duplicates", held: 1, wantAccepted: 2},
+		{name: "no room", modify: func(cfg *SimpleConfig, items []*PreParamsData) { cfg.MaxPoolSize = 2 }, wantAccepted: 2},
+	}
+
+	for _, tt := range tests {
+		for _, worker := range []bool{false, true} {
+			name := tt.name + "/peer"
+			if worker {
+				name = tt.name + "/worker"
+			}
+			t.Run(name, func(t *testing.T) {
+				cfg, items := testConfig(t), testItems(t)
+				if tt.modify != nil {
+					tt.modify(&cfg, items)
+				}
+				held := testItems(t)[:tt.held]
+				m := newTestManager(t, cfg, held)
+
+				var accepted int
+				if worker {
+					accepted = m.AddWorkerPreParams(context.Background(), items)
+				} else {
+					accepted = m.AddPreParams(context.Background(), items)
+				}
+				if accepted != tt.wantAccepted {
+					t.Fatalf("accepted %d items, want %d", accepted, tt.wantAccepted)
+				}
+				if got := m.quarantined.Load(); got != tt.wantQuarantined {
+					t.Fatalf("quarantined %d items, want %d", got, tt.wantQuarantined)
+				}
+				if m.Size() != tt.held+tt.wantAccepted {
+					t.Fatalf("pool holds %d items, want %d", m.Size(), tt.held+tt.wantAccepted)
+				}
+			})
+		}
+	}
+}
+
+func TestAddWorkerPreParams(t *testing.T) {
+	m := newTestManager(t, testConfig(t), nil)
+	items := testItems(t)
+	future := time.Now().Add(time.Hour)
+	items[0].GeneratedAt = future
+
+	start := time.Now()
+	if n := m.AddWorkerPreParams(context.Background(), items); n != len(items) {
+		t.Fatalf("accepted %d items, want %d", n, len(items))
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, item := range m.preParams {
+		if item.Assurance != AssuranceAudited {
+			t.Fatalf("submitted item has assurance %q, want %q", item.Assurance, AssuranceAudited)
+		}
+		if item.Checksum == "" {
+			t.Fatal("submitted item was not sealed with a checksum")
+		}
+		if item.GeneratedAt.After(time.Now()) || item.GeneratedAt.Before(start.Add(-2*time.Hour)) {
+			t.Fatalf("submitted item claims to be generated at %s", item.GeneratedAt)
+		}
+	}
+}
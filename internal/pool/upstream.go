@@ -0,0 +1,77 @@
+package pool
+
+import (
+	"context"
+	"time"
+)
+
+// UpstreamStats counts the pulls of an instance from its upstream service,
+// kept apart from the instance's own serving and generation counters
+type UpstreamStats struct {
+	Address   string    `json:"address"`
+	Pulls     int64     `json:"pulls"`    // Completed pulls, seeding included
+	Failures  int64     `json:"failures"` // Pulls that ended with an error
+	Received  int64     `json:"received"`
+	Accepted  int64     `json:"accepted"`
+	LastPull  time.Time `json:"last_pull"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// FirstBoot reports whether the pool started brand-new: empty, with nothing
+// generated or served by earlier runs. Memory storage starts so every time.
+func (m *Manager) FirstBoot() bool {
+	return m.firstBoot
+}
+
+// SeedPreParams inserts items pulled from a trusted upstream service into a
+// new pool up to MaxPoolSize and returns how many were accepted. Unlike
+// peer transfers, every item is fully validated and flagged as imported,
+// whatever its age.
+func (m *Manager) SeedPreParams(ctx context.Context, items []*PreParamsData) int {
+	return m.addItems(ctx, items, ImportSourceBootstrap, true, &m.seeded)
+}
+
+// ProxyPreParams inserts items an edge instance pulled from its upstream
+// to top up its pool, validated like SeedPreParams, and returns how many
+// were accepted
+func (m *Manager) ProxyPreParams(ctx context.Context, items []*PreParamsData) int {
+	return m.addItems(ctx, items, ImportSourceUpstream, true, nil)
+}
+
+// RecordUpstreamPull counts a pull from the upstream at address that
+// received and accepted the given numbers of items before failing with err
+// (nil: it succeeded)
+func (m *Manager) RecordUpstreamPull(address string, received, accepted int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := &m.upstream
+	s.Address = address
+	s.Pulls++
+	s.Received += int64(received)
+	s.Accepted += int64(accepted)
+	s.LastPull = time.Now()
+	s.LastError = ""
+	if err != nil {
+		s.Failures++
+		s.LastError = err.Error()
+	}
+}
+
+// UpstreamStats returns the pull counters, or nil if nothing was pulled
+func (m *Manager) UpstreamStats() *UpstreamStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.upstreamStatsLocked()
+}
+
+// upstreamStatsLocked returns a copy of the pull counters, or nil if
+// nothing was pulled
+// Caller must hold m.mu.
+func (m *Manager) upstreamStatsLocked() *UpstreamStats {
+	if m.upstream.Pulls == 0 {
+		return nil
+	}
+	s := m.upstream
+	return &s
+}
@@ -0,0 +1,29 @@
+package pool
+
+import "fmt"
+
+// Version identifies a state of the pool for cheap change detection. Epoch
+// changes whenever the process restarts; Counter increases on every change to
+// the pool contents or totals, generation activity, alarms, configuration or
+// serving mode.
+type Version struct {
+	Epoch   int64  `json:"epoch"`   // Manager start time in Unix nanoseconds
+	Counter uint64 `json:"counter"` // Changes since start
+}
+
+// ETag returns a weak HTTP entity tag for the version. It is weak because
+// in-flight counts and item ages may move without a version change.
+func (v Version) ETag() string {
+	return fmt.Sprintf(`W/"%x-%d"`, v.Epoch, v.Counter)
+}
+
+// Version returns the current pool version
+func (m *Manager) Version() Version {
+	return Version{Epoch: m.startTime.UnixNano(), Counter: m.version.Load()}
+}
+
+// changed advances the pool version and wakes waiting requests
+func (m *Manager) changed() {
+	m.version.Add(1)
+	m.waiters.wake()
+}
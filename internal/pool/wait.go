@@ -0,0 +1,109 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrStopped is returned to requests still waiting when the manager stops
+var ErrStopped = errors.New("pool manager stopped")
+
+// ErrExceedsCapacity is returned for waits on more items than MaxPoolSize,
+// which could never be served
+var ErrExceedsCapacity = errors.New("request exceeds the pool capacity")
+
+// waitRetryInterval is how often a waiting request tries the pool even
+// without a change, restarting an emergency refill that was skipped (e.g.
+// during the startup delay) or ended short
+const waitRetryInterval = 5 * time.Second
+
+// waitQueue wakes requests waiting for the pool to change
+type waitQueue struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+// wait returns a channel closed at the next wake
+func (q *waitQueue) wait() <-chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.ch == nil {
+		q.ch = make(chan struct{})
+	}
+	return q.ch
+}
+
+// wake releases every waiting request
+func (q *waitQueue) wake() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.ch != nil {
+		close(q.ch)
+		q.ch = nil
+	}
+}
+
+// WaitForPreParams waits until the pool can serve all req.Count items and
+// takes them at once, without generating synchronously; a pool too small
+// triggers an emergency refill. After timeout (zero: only ctx bounds the
+// wait) it fails with ErrPoolEmpty. NoGenerate is implied, and idempotency
+// keys replay as for GetPreParams.
+func (m *Manager) WaitForPreParams(ctx context.Context, req Request, timeout time.Duration) ([]*ServedParams, error) {
+	return m.serve(ctx, req, func(ctx context.Context, count uint32, req Request) ([]*ServedParams, error) {
+		return m.waitAllocate(ctx, count, req, timeout)
+	})
+}
+
+// waitAllocate takes count items once the pool holds them, retrying on
+// every pool change
+func (m *Manager) waitAllocate(ctx context.Context, count uint32, req Request, timeout time.Duration) (result []*ServedParams, err error) {
+	if int(count) > m.config.MaxPoolSize {
+		return nil, fmt.Errorf("%w: cannot wait for %d parameters, the pool holds at most %d", ErrExceedsCapacity, count, m.config.MaxPoolSize)
+	}
+	defer func() { m.hookConsumed(ctx, result) }()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	retry := time.NewTicker(waitRetryInterval)
+	defer retry.Stop()
+
+	for force := true; ; {
+		// Subscribe before looking, so no change in between is missed
+		wake := m.waiters.wait()
+
+		// Forced attempts take the pool lock regardless, which also starts
+		// an emergency refill if the pool is short
+		if force || m.Size() >= int(count) {
+			if result, _ = m.takeFromPool(ctx, count, req, true); len(result) > 0 {
+				return result, nil
+			}
+		}
+		if m.maintenance.Load() {
+			return nil, ErrMaintenance
+		}
+		if m.frozen.Load() {
+			return nil, ErrFrozen
+		}
+
+		force = false
+		select {
+		case <-wake:
+		case <-retry.C:
+			force = true
+		case <-expired:
+			return nil, ErrPoolEmpty
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-m.stopCh:
+			return nil, ErrStopped
+		}
+	}
+}
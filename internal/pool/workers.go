@@ -0,0 +1,166 @@
+package pool
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/generator"
+	"github.com/TEENet-io/prime-service/internal/scheduler"
+)
+
+// WorkerState describes one worker of a running fill
+type WorkerState struct {
+	Run           string  `json:"run"`  // Burst ID of the fill
+	Mode          string  `json:"mode"` // refill, emergency or fill
+	Worker        int     `json:"worker"`
+	Busy          bool    `json:"busy"`                      // Generating an item, rather than throttling or handing one over
+	JobAgeSeconds float64 `json:"job_age_seconds,omitempty"` // Time spent on the current item
+}
+
+// GenerationMetrics shows how much generation work is waiting and whether
+// the workers are actually busy with it
+type GenerationMetrics struct {
+	JobsQueued  int           `json:"jobs_queued"` // Items of running fills no worker has started
+	WorkersBusy int           `json:"workers_busy"`
+	WorkersIdle int           `json:"workers_idle"`
+	InFlight    int           `json:"in_flight"` // Items being generated, including on-demand ones
+	Workers     []WorkerState `json:"workers"`
+
+	// On-demand generations for requests, and requests queued for a slot
+	// beyond MaxOnDemand
+	OnDemandRunning int `json:"on_demand_running"`
+	OnDemandQueued  int `json:"on_demand_queued"`
+
+	// Background generation paused, and generations parked at a yield
+	// point of their prime searches
+	Paused bool `json:"paused"`
+	Parked int  `json:"parked"`
+
+	// Generation slots shared by requests and refills of all pools
+	Scheduler scheduler.Stats `json:"scheduler"`
+
+	// Safe prime workers an item started now would get
+	SafePrimeWorkers int `json:"safe_prime_workers"`
+
+	// Safe prime search algorithm items started now use
+	SafePrimeSource string `json:"safe_prime_source"`
+
+	// Phase timing histograms of the generator, which all pools share
+	Phases map[string]generator.HistogramSnapshot `json:"phases"`
+
+	// Items served from the pool and generated synchronously for requests,
+	// with the time requests spent generating the misses
+	PoolHits              int64   `json:"pool_hits"`
+	PoolMisses            int64   `json:"pool_misses"`
+	MissGenerationSeconds float64 `json:"miss_generation_seconds"`
+}
+
+// fillRun is the worker state of one running fill
+type fillRun struct {
+	mode    string
+	burst   string
+	queued  func() int  // Items not yet claimed by a worker
+	jobs    []time.Time // Start of each worker's current item, zero when idle
+	running []bool      // Workers that have not exited
+}
+
+// fillRuns tracks the running fills
+type fillRuns struct {
+	mu   sync.Mutex
+	runs map[*fillRun]struct{}
+}
+
+// start registers a fill with workers workers
+func (f *fillRuns) start(mode, burst string, workers int, queued func() int) *fillRun {
+	run := &fillRun{
+		mode:    mode,
+		burst:   burst,
+		queued:  queued,
+		jobs:    make([]time.Time, workers),
+		running: make([]bool, workers),
+	}
+	for i := range run.running {
+		run.running[i] = true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.runs == nil {
+		f.runs = make(map[*fillRun]struct{})
+	}
+	f.runs[run] = struct{}{}
+	return run
+}
+
+// end unregisters a fill
+func (f *fillRuns) end(run *fillRun) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.runs, run)
+}
+
+// setJob records that worker started an item at start, or is idle if start is zero
+func (f *fillRuns) setJob(run *fillRun, worker int, start time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	run.jobs[worker] = start
+}
+
+// exit records that worker stopped
+func (f *fillRuns) exit(run *fillRun, worker int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	run.jobs[worker] = time.Time{}
+	run.running[worker] = false
+}
+
+// GenerationMetrics returns the generation queue and worker state
+func (m *Manager) GenerationMetrics() GenerationMetrics {
+	now := time.Now()
+	metrics := GenerationMetrics{
+		InFlight: int(m.inFlight.Load()),
+		Workers:  []WorkerState{},
+		Phases:   m.generator.GetPhaseHistograms(),
+
+		SafePrimeWorkers: m.generator.SafePrimeWorkers(),
+		SafePrimeSource:  m.generator.SafePrimeSource(),
+	}
+	metrics.OnDemandRunning, metrics.OnDemandQueued = m.onDemand.Counts()
+	metrics.Scheduler = m.generator.Scheduler().Stats()
+	metrics.Paused, metrics.Parked = m.GenerationPaused()
+
+	m.mu.RLock()
+	metrics.PoolHits, metrics.PoolMisses = m.poolHits, m.poolMisses
+	metrics.MissGenerationSeconds = m.missGenTime.Seconds()
+	m.mu.RUnlock()
+
+	m.runs.mu.Lock()
+	for run := range m.runs.runs {
+		metrics.JobsQueued += run.queued()
+		for i, running := range run.running {
+			if !running {
+				continue
+			}
+			w := WorkerState{Run: run.burst, Mode: run.mode, Worker: i}
+			if start := run.jobs[i]; !start.IsZero() {
+				w.Busy = true
+				w.JobAgeSeconds = now.Sub(start).Seconds()
+				metrics.WorkersBusy++
+			} else {
+				metrics.WorkersIdle++
+			}
+			metrics.Workers = append(metrics.Workers, w)
+		}
+	}
+	m.runs.mu.Unlock()
+
+	sort.Slice(metrics.Workers, func(i, j int) bool {
+		a, b := metrics.Workers[i], metrics.Workers[j]
+		if a.Run != b.Run {
+			return a.Run < b.Run
+		}
+		return a.Worker < b.Worker
+	})
+	return metrics
+}
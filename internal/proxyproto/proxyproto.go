@@ -0,0 +1,239 @@
+// Package proxyproto accepts connections relayed by L4 load balancers that
+// speak the PROXY protocol (v1 and v2), reporting the original client
+// address as the connection's remote address
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// headerTimeout bounds how long a trusted proxy may take to send the header
+const headerTimeout = 5 * time.Second
+
+// v2Signature starts every PROXY protocol v2 header
+var v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+// ErrNoHeader is returned for connections from a trusted proxy that do not
+// start with a PROXY protocol header
+var ErrNoHeader = errors.New("missing PROXY protocol header")
+
+// ParseTrusted parses proxy addresses given as CIDR blocks or single IPv4
+// or IPv6 addresses
+func ParseTrusted(specs []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(specs))
+	for _, spec := range specs {
+		if strings.Contains(spec, "/") {
+			prefix, err := netip.ParsePrefix(spec)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy network %q: %w", spec, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address %q: %w", spec, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// Listener reads a PROXY protocol header from connections coming from a
+// trusted proxy, which must send one, and passes other connections through
+// unchanged, so clients inside the network can still connect directly
+type Listener struct {
+	net.Listener
+	trusted []netip.Prefix
+}
+
+// NewListener wraps inner, trusting PROXY protocol headers only from the
+// given networks
+func NewListener(inner net.Listener, trusted []netip.Prefix) *Listener {
+	return &Listener{Listener: inner, trusted: trusted}
+}
+
+// Accept returns the next connection. The header of a proxied connection is
+// read on first use rather than here, so a slow proxy cannot stall Accept.
+func (l *Listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.isTrusted(conn.RemoteAddr()) {
+		return conn, nil
+	}
+	return &Conn{Conn: conn, reader: bufio.NewReader(conn)}, nil
+}
+
+// isTrusted reports whether addr belongs to a trusted proxy
+func (l *Listener) isTrusted(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	ip, ok := netip.AddrFromSlice(tcp.IP)
+	if !ok {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, prefix := range l.trusted {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Conn is a connection from a trusted proxy
+type Conn struct {
+	net.Conn
+	reader *bufio.Reader
+
+	once   sync.Once
+	source net.Addr // Original client, nil for LOCAL and unknown-family headers
+	err    error
+}
+
+// readHeader reads the PROXY protocol header, once
+func (c *Conn) readHeader() {
+	c.once.Do(func() {
+		c.Conn.SetReadDeadline(time.Now().Add(headerTimeout))
+		c.source, c.err = readHeader(c.reader)
+		c.Conn.SetReadDeadline(time.Time{})
+		if c.err != nil {
+			log.Printf("Rejecting connection from proxy %s: %v", c.Conn.RemoteAddr(), c.err)
+			c.Conn.Close()
+		}
+	})
+}
+
+// Read reads from the connection after the header
+func (c *Conn) Read(p []byte) (int, error) {
+	c.readHeader()
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.reader.Read(p)
+}
+
+// RemoteAddr returns the original client address the proxy reported, or
+// the proxy's own address for health checks and unknown address families
+func (c *Conn) RemoteAddr() net.Addr {
+	c.readHeader()
+	if c.source != nil {
+		return c.source
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// ProxyAddr returns the address of the proxy the connection came through
+func (c *Conn) ProxyAddr() net.Addr {
+	return c.Conn.RemoteAddr()
+}
+
+// readHeader reads a v1 or v2 header, returning the source address it
+// carries
+func readHeader(r *bufio.Reader) (net.Addr, error) {
+	start, err := r.Peek(len(v2Signature))
+	if err != nil {
+		if bytes.HasPrefix(start, []byte("PROXY ")) {
+			return readV1(r)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrNoHeader, err)
+	}
+	switch {
+	case bytes.Equal(start, v2Signature):
+		return readV2(r)
+	case bytes.HasPrefix(start, []byte("PROXY ")):
+		return readV1(r)
+	}
+	return nil, ErrNoHeader
+}
+
+// readV1 reads a text header such as "PROXY TCP6 ::1 ::1 51234 50055\r\n"
+func readV1(r *bufio.Reader) (net.Addr, error) {
+	var line []byte
+	for len(line) < 107 { // Longest valid header, CRLF included
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read PROXY v1 header: %w", err)
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, errors.New("PROXY v1 header too long or not CRLF-terminated")
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, fmt.Errorf("malformed PROXY v1 header %q", line[:len(line)-2])
+	}
+	ip, err := netip.ParseAddr(fields[2])
+	if err != nil || ip.Is4() != (fields[1] == "TCP4") {
+		return nil, fmt.Errorf("invalid source address %q in PROXY v1 header", fields[2])
+	}
+	port, err := strconv.ParseUint(fields[4], 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid source port %q in PROXY v1 header", fields[4])
+	}
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, uint16(port))), nil
+}
+
+// readV2 reads a binary header, skipping any TLVs
+func readV2(r *bufio.Reader) (net.Addr, error) {
+	var fixed [16]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return nil, fmt.Errorf("failed to read PROXY v2 header: %w", err)
+	}
+	if fixed[12]>>4 != 2 {
+		return nil, fmt.Errorf("unsupported PROXY protocol version %d", fixed[12]>>4)
+	}
+	body := make([]byte, binary.BigEndian.Uint16(fixed[14:16]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, fmt.Errorf("failed to read PROXY v2 addresses: %w", err)
+	}
+
+	switch fixed[12] & 0x0f {
+	case 0x0: // LOCAL: the proxy's own connection, e.g. a health check
+		return nil, nil
+	case 0x1: // PROXY
+	default:
+		return nil, fmt.Errorf("unsupported PROXY v2 command %d", fixed[12]&0x0f)
+	}
+
+	switch fixed[13] {
+	case 0x11: // TCP over IPv4: source, destination, source port, destination port
+		if len(body) < 12 {
+			return nil, errors.New("truncated PROXY v2 IPv4 addresses")
+		}
+		ip := netip.AddrFrom4([4]byte(body[0:4]))
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, binary.BigEndian.Uint16(body[8:10]))), nil
+	case 0x21: // TCP over IPv6
+		if len(body) < 36 {
+			return nil, errors.New("truncated PROXY v2 IPv6 addresses")
+		}
+		ip := netip.AddrFrom16([16]byte(body[0:16])).Unmap()
+		return net.TCPAddrFromAddrPort(netip.AddrPortFrom(ip, binary.BigEndian.Uint16(body[32:34]))), nil
+	}
+	return nil, nil // Other families keep the proxy's address
+}
@@ -0,0 +1,268 @@
+// Package redis is a minimal Redis client speaking RESP2, just enough for a
+// pool shared through Redis lists: one connection, one command at a time,
+// redialed after a network or protocol error
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds dialing and each command without a context deadline
+const DefaultTimeout = 5 * time.Second
+
+// Error is an error reply of the server, e.g. WRONGTYPE or NOAUTH. The
+// connection stays usable.
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Options are the connection settings
+type Options struct {
+	Address  string // host:port
+	Password string // AUTH password ("": none)
+	DB       int    // Database selected after connecting
+	TLS      bool   // Connect over TLS, verifying the server against system roots
+	Timeout  time.Duration
+}
+
+// Client is a connection to a Redis server, safe for concurrent use
+type Client struct {
+	opts Options
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// New returns a client for opts; it connects on the first command
+func New(opts Options) *Client {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	return &Client{opts: opts}
+}
+
+// Address returns the address of the server
+func (c *Client) Address() string {
+	return c.opts.Address
+}
+
+// Do sends a command and returns its reply: a string for simple strings,
+// int64 for integers, []byte or nil for bulk strings and []interface{} or
+// nil for arrays. Error replies are returned as Error.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if err := c.dialLocked(ctx); err != nil {
+			return nil, err
+		}
+	}
+	reply, err := c.roundTripLocked(ctx, args)
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) {
+		// The stream may be out of step; start over on the next command
+		c.closeLocked()
+	}
+	return reply, err
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closeLocked()
+}
+
+// Ping checks that the server is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// RPush appends values to the list at key, returning its new length
+func (c *Client) RPush(ctx context.Context, key string, values ...string) (int64, error) {
+	return c.Int(ctx, append([]string{"RPUSH", key}, values...)...)
+}
+
+// LPop removes and returns up to count values from the head of the list at
+// key (Redis 6.2 or later), none if it is empty or missing
+func (c *Client) LPop(ctx context.Context, key string, count int) ([][]byte, error) {
+	reply, err := c.Do(ctx, "LPOP", key, strconv.Itoa(count))
+	if err != nil || reply == nil {
+		return nil, err
+	}
+	items, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected LPOP reply %T", reply)
+	}
+	values := make([][]byte, 0, len(items))
+	for _, item := range items {
+		if v, ok := item.([]byte); ok {
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+// LLen returns the length of the list at key (0 if missing)
+func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
+	return c.Int(ctx, "LLEN", key)
+}
+
+// Int sends a command expecting an integer reply
+func (c *Client) Int(ctx context.Context, args ...string) (int64, error) {
+	reply, err := c.Do(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected %s reply %T", args[0], reply)
+	}
+	return n, nil
+}
+
+// dialLocked connects, authenticates and selects the database
+// Caller must hold c.mu.
+func (c *Client) dialLocked(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: c.opts.Timeout}
+	var conn net.Conn
+	var err error
+	if c.opts.TLS {
+		host, _, _ := net.SplitHostPort(c.opts.Address)
+		td := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		conn, err = td.DialContext(ctx, "tcp", c.opts.Address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", c.opts.Address)
+	}
+	if err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	c.conn, c.r = conn, bufio.NewReader(conn)
+
+	if c.opts.Password != "" {
+		if _, err := c.roundTripLocked(ctx, []string{"AUTH", c.opts.Password}); err != nil {
+			c.closeLocked()
+			return err
+		}
+	}
+	if c.opts.DB != 0 {
+		if _, err := c.roundTripLocked(ctx, []string{"SELECT", strconv.Itoa(c.opts.DB)}); err != nil {
+			c.closeLocked()
+			return err
+		}
+	}
+	return nil
+}
+
+// closeLocked closes the connection, if any
+// Caller must hold c.mu.
+func (c *Client) closeLocked() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn, c.r = nil, nil
+	return err
+}
+
+// roundTripLocked writes a command and reads its reply
+// Caller must hold c.mu.
+func (c *Client) roundTripLocked(ctx context.Context, args []string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(c.opts.Timeout)
+	}
+	c.conn.SetDeadline(deadline)
+
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	if _, err := c.conn.Write(buf); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	return c.readReplyLocked()
+}
+
+// readReplyLocked reads one reply, recursing into arrays
+// Caller must hold c.mu.
+func (c *Client) readReplyLocked() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("redis: malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, Error(body)
+	case ':':
+		n, err := strconv.ParseInt(body, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("redis: malformed integer reply %q", body)
+		}
+		return n, nil
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < -1 {
+			return nil, fmt.Errorf("redis: malformed bulk length %q", body)
+		}
+		if n == -1 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n < -1 {
+			return nil, fmt.Errorf("redis: malformed array length %q", body)
+		}
+		if n == -1 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			// An error reply inside an array is kept as an element
+			item, err := c.readReplyLocked()
+			var replyErr Error
+			if errors.As(err, &replyErr) {
+				item = replyErr
+			} else if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unknown reply type %q", kind)
+	}
+}
@@ -0,0 +1,356 @@
+// Package scheduler hands out generation slots from one concurrency budget
+// to everything that generates parameters: requests generating on demand,
+// emergency refills, and background refills and fills, across all pools.
+// Waiting jobs get slots by priority, first come first served within one,
+// and background jobs give their slot up to a waiting job of higher
+// priority at their next yield point, resuming once a slot is free again.
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Priority orders jobs waiting for a slot
+type Priority int
+
+const (
+	Background Priority = iota // Housekeeping refills and operator fills
+	Emergency                  // Refills after a request found the pool short
+	Request                    // On-demand generation a caller waits for
+
+	numPriorities = iota
+)
+
+// String names the priority in metrics and logs
+func (p Priority) String() string {
+	switch p {
+	case Background:
+		return "background"
+	case Emergency:
+		return "emergency"
+	case Request:
+		return "request"
+	}
+	return "unknown"
+}
+
+// Scheduler grants generation slots up to a limit
+type Scheduler struct {
+	mu      sync.Mutex
+	limit   int
+	running int
+	waiting []*Job        // By priority, then arrival
+	moved   chan struct{} // Closed when positions change
+	classes [numPriorities]classStats
+}
+
+// classStats counts the jobs of one priority
+type classStats struct {
+	running   int
+	granted   int64
+	preempted int64
+	waited    time.Duration
+}
+
+// Group caps the slots a set of jobs may hold at once, e.g. the on-demand
+// generations of one pool
+type Group struct {
+	s       *Scheduler
+	cap     int // 0: no cap
+	running int
+	waiting int
+}
+
+// Job is one generation's claim on a slot, from Enqueue until Done
+type Job struct {
+	s        *Scheduler
+	priority Priority
+	group    *Group
+	granted  chan struct{} // Closed once the job holds a slot
+	holding  bool
+	queued   bool
+	resumed  bool // Held a slot before, so it queues ahead of its priority
+	since    time.Time
+}
+
+// New returns a scheduler granting up to limit slots at once (at least 1)
+func New(limit int) *Scheduler {
+	return &Scheduler{limit: max(limit, 1)}
+}
+
+// SetLimit changes the number of slots, granting waiting jobs if it grew.
+// Jobs holding slots beyond a lowered limit keep them until they finish or
+// yield.
+func (s *Scheduler) SetLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.limit = max(limit, 1)
+	s.dispatchLocked()
+}
+
+// Limit returns the number of slots
+func (s *Scheduler) Limit() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.limit
+}
+
+// NewGroup returns a group whose jobs hold at most cap slots (0: no cap)
+func (s *Scheduler) NewGroup(cap int) *Group {
+	return &Group{s: s, cap: cap}
+}
+
+// SetCap changes the cap of g, granting its waiting jobs if it grew
+func (g *Group) SetCap(cap int) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+	g.cap = cap
+	g.s.dispatchLocked()
+}
+
+// Counts returns the jobs of g holding and waiting for slots
+func (g *Group) Counts() (running, waiting int) {
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+	return g.running, g.waiting
+}
+
+// full reports whether g holds as many slots as it may
+// Caller must hold s.mu.
+func (g *Group) full() bool {
+	return g != nil && g.cap > 0 && g.running >= g.cap
+}
+
+// Enqueue queues a job of priority p in group g (nil: none). It is granted
+// a slot at once if one is free and no job it must wait behind waits.
+func (s *Scheduler) Enqueue(p Priority, g *Group) *Job {
+	j := &Job{s: s, priority: p, group: g}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queueLocked(j)
+	s.dispatchLocked()
+	return j
+}
+
+// queueLocked inserts j behind the jobs of its priority, or ahead of them
+// if it is resuming after giving up its slot
+// Caller must hold s.mu.
+func (s *Scheduler) queueLocked(j *Job) {
+	j.granted = make(chan struct{})
+	j.queued = true
+	j.since = time.Now()
+	if j.group != nil {
+		j.group.waiting++
+	}
+	i := 0
+	for i < len(s.waiting) && (s.waiting[i].priority > j.priority || (s.waiting[i].priority == j.priority && !j.resumed)) {
+		i++
+	}
+	s.waiting = append(s.waiting, nil)
+	copy(s.waiting[i+1:], s.waiting[i:])
+	s.waiting[i] = j
+	s.movedLocked()
+}
+
+// unqueueLocked removes j from the waiting jobs
+// Caller must hold s.mu.
+func (s *Scheduler) unqueueLocked(j *Job) {
+	for i, w := range s.waiting {
+		if w == j {
+			s.waiting = append(s.waiting[:i], s.waiting[i+1:]...)
+			break
+		}
+	}
+	j.queued = false
+	if j.group != nil {
+		j.group.waiting--
+	}
+	s.movedLocked()
+}
+
+// dispatchLocked grants waiting jobs in order while slots are free,
+// skipping jobs whose group is full
+// Caller must hold s.mu.
+func (s *Scheduler) dispatchLocked() {
+	for i := 0; i < len(s.waiting) && s.running < s.limit; {
+		j := s.waiting[i]
+		if j.group.full() {
+			i++
+			continue
+		}
+		s.unqueueLocked(j)
+		s.grantLocked(j)
+	}
+}
+
+// grantLocked gives j a slot
+// Caller must hold s.mu.
+func (s *Scheduler) grantLocked(j *Job) {
+	j.holding = true
+	s.running++
+	c := &s.classes[j.priority]
+	c.running++
+	c.granted++
+	c.waited += time.Since(j.since)
+	if j.group != nil {
+		j.group.running++
+	}
+	close(j.granted)
+}
+
+// releaseLocked returns the slot j holds
+// Caller must hold s.mu.
+func (s *Scheduler) releaseLocked(j *Job) {
+	j.holding = false
+	j.resumed = true
+	s.running--
+	s.classes[j.priority].running--
+	if j.group != nil {
+		j.group.running--
+	}
+}
+
+// movedLocked wakes callers watching their position
+// Caller must hold s.mu.
+func (s *Scheduler) movedLocked() {
+	if s.moved != nil {
+		close(s.moved)
+		s.moved = nil
+	}
+}
+
+// Wait blocks until j holds a slot, queueing it again if it gave its slot
+// up with Release. It returns ctx's error if ctx ends first; j keeps its
+// place in the queue until Done.
+func (j *Job) Wait(ctx context.Context) error {
+	j.s.mu.Lock()
+	if !j.holding && !j.queued {
+		j.s.queueLocked(j)
+		j.s.dispatchLocked()
+	}
+	granted := j.granted
+	j.s.mu.Unlock()
+
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Position returns the jobs ahead of j plus one (0: j holds a slot or is
+// neither), and a channel closed when positions change
+func (j *Job) Position() (int, <-chan struct{}) {
+	j.s.mu.Lock()
+	defer j.s.mu.Unlock()
+	if j.s.moved == nil {
+		j.s.moved = make(chan struct{})
+	}
+	for i, w := range j.s.waiting {
+		if w == j {
+			return i + 1, j.s.moved
+		}
+	}
+	return 0, j.s.moved
+}
+
+// Release gives up the slot j holds without ending it, e.g. while its
+// generation is paused; Wait takes a slot again
+func (j *Job) Release() {
+	j.s.mu.Lock()
+	defer j.s.mu.Unlock()
+	if j.holding {
+		j.s.releaseLocked(j)
+		j.s.dispatchLocked()
+	}
+}
+
+// Yield gives the slot j holds to a waiting job of higher priority that
+// could not get one otherwise, and waits for a slot again. It returns
+// ctx's error if ctx ends while waiting.
+func (j *Job) Yield(ctx context.Context) error {
+	j.s.mu.Lock()
+	preempt := j.holding && j.s.running >= j.s.limit && j.s.waitingAboveLocked(j.priority)
+	if preempt {
+		j.s.classes[j.priority].preempted++
+		j.s.releaseLocked(j)
+		j.s.dispatchLocked()
+	}
+	j.s.mu.Unlock()
+	if !preempt {
+		return nil
+	}
+	return j.Wait(ctx)
+}
+
+// waitingAboveLocked reports whether a job of higher priority than p waits
+// and its group would let it run
+// Caller must hold s.mu.
+func (s *Scheduler) waitingAboveLocked(p Priority) bool {
+	for _, w := range s.waiting {
+		if w.priority <= p {
+			return false
+		}
+		if !w.group.full() {
+			return true
+		}
+	}
+	return false
+}
+
+// Done returns the slot of j, or gives up its place in the queue
+func (j *Job) Done() {
+	j.s.mu.Lock()
+	defer j.s.mu.Unlock()
+	switch {
+	case j.holding:
+		j.s.releaseLocked(j)
+	case j.queued:
+		j.s.unqueueLocked(j)
+	default:
+		return
+	}
+	j.s.dispatchLocked()
+}
+
+// ClassStats describes the jobs of one priority
+type ClassStats struct {
+	Running       int     `json:"running"`
+	Queued        int     `json:"queued"`
+	Granted       int64   `json:"granted"`   // Slots granted, including after preemption
+	Preempted     int64   `json:"preempted"` // Slots given up to higher priorities
+	WaitedSeconds float64 `json:"waited_seconds"`
+}
+
+// Stats describes the slots and the jobs holding and waiting for them
+type Stats struct {
+	Limit   int                   `json:"limit"`
+	Running int                   `json:"running"`
+	Queued  int                   `json:"queued"`
+	Classes map[string]ClassStats `json:"classes"`
+}
+
+// Stats returns the current slot usage
+func (s *Scheduler) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := Stats{Limit: s.limit, Running: s.running, Queued: len(s.waiting), Classes: make(map[string]ClassStats, numPriorities)}
+	queued := make([]int, numPriorities)
+	for _, j := range s.waiting {
+		queued[j.priority]++
+	}
+	for p := Priority(0); p < numPriorities; p++ {
+		c := s.classes[p]
+		stats.Classes[p.String()] = ClassStats{
+			Running:       c.running,
+			Queued:        queued[p],
+			Granted:       c.granted,
+			Preempted:     c.preempted,
+			WaitedSeconds: c.waited.Seconds(),
+		}
+	}
+	return stats
+}
@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+)
+
+// granted reports whether j holds a slot without blocking
+func granted(j *Job) bool {
+	select {
+	case <-j.granted:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestGrantOrder(t *testing.T) {
+	type job struct {
+		priority Priority
+		group    int // Index into caps, -1: none
+	}
+	tests := []struct {
+		name  string
+		limit int
+		caps  []int
+		jobs  []job
+		steps [][]int // Jobs newly granted after each round of Done
+	}{
+		{
+			name:  "first come first served",
+			limit: 1,
+			jobs:  []job{{Background, -1}, {Background, -1}, {Background, -1}},
+			steps: [][]int{{0}, {1}, {2}},
+		},
+		{
+			name:  "priority before arrival",
+			limit: 1,
+			jobs:  []job{{Background, -1}, {Background, -1}, {Emergency, -1}, {Request, -1}},
+			steps: [][]int{{0}, {3}, {2}, {1}},
+		},
+		{
+			name:  "fifo within priority",
+			limit: 1,
+			jobs:  []job{{Background, -1}, {Request, -1}, {Emergency, -1}, {Request, -1}, {Emergency, -1}},
+			steps: [][]int{{0}, {1}, {3}, {2}, {4}},
+		},
+		{
+			name:  "several slots",
+			limit: 2,
+			jobs:  []job{{Background, -1}, {Background, -1}, {Background, -1}, {Request, -1}},
+			steps: [][]int{{0, 1}, {3, 2}},
+		},
+		{
+			name:  "group cap skips to the next job",
+			limit: 2,
+			caps:  []int{1},
+			jobs:  []job{{Request, 0}, {Request, 0}, {Background, -1}},
+			steps: [][]int{{0, 2}, {1}},
+		},
+		{
+			name:  "uncapped group",
+			limit: 2,
+			caps:  []int{0},
+			jobs:  []job{{Request, 0}, {Request, 0}, {Request, 0}},
+			steps: [][]int{{0, 1}, {2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.limit)
+			groups := make([]*Group, len(tt.caps))
+			for i, c := range tt.caps {
+				groups[i] = s.NewGroup(c)
+			}
+			jobs := make([]*Job, len(tt.jobs))
+			for i, j := range tt.jobs {
+				var g *Group
+				if j.group >= 0 {
+					g = groups[j.group]
+				}
+				jobs[i] = s.Enqueue(j.priority, g)
+			}
+
+			seen := make([]bool, len(jobs))
+			for step, want := range tt.steps {
+				var got []int
+				for i, j := range jobs {
+					if !seen[i] && granted(j) {
+						got = append(got, i)
+					}
+				}
+				// Jobs granted in the same round are compared as a set
+				if !sameSet(got, want) {
+					t.Fatalf("step %d: granted %v, want %v", step, got, want)
+				}
+				for _, i := range got {
+					seen[i] = true
+					jobs[i].Done()
+				}
+			}
+			if stats := s.Stats(); stats.Running != 0 || stats.Queued != 0 {
+				t.Fatalf("after all jobs: running %d, queued %d", stats.Running, stats.Queued)
+			}
+		})
+	}
+}
+
+// sameSet reports whether a and b hold the same elements
+func sameSet(a, b []int) bool {
+	count := make(map[int]int)
+	for _, v := range a {
+		count[v]++
+	}
+	for _, v := range b {
+		count[v]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+func TestYield(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		holder      Priority
+		waiter      Priority
+		waiterCap   int // Cap of the waiter's group, which already holds one slot when non-zero
+		wantPreempt bool
+	}{
+		{"request preempts background", 1, Background, Request, 0, true},
+		{"emergency preempts background", 1, Background, Emergency, 0, true},
+		{"same priority waits", 1, Background, Background, 0, false},
+		{"lower priority waits", 1, Request, Background, 0, false},
+		{"free slot", 2, Background, Request, 0, false},
+		{"waiter group full", 2, Background, Request, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.limit)
+			var g *Group
+			if tt.waiterCap > 0 {
+				g = s.NewGroup(tt.waiterCap)
+				// Fill the group, taking a slot
+				s.Enqueue(tt.waiter, g)
+			}
+			holder := s.Enqueue(tt.holder, nil)
+			if !granted(holder) {
+				t.Fatal("holder was not granted a slot")
+			}
+			waiter := s.Enqueue(tt.waiter, g)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := holder.Yield(ctx)
+			preempted := err != nil
+			if preempted != tt.wantPreempt {
+				t.Fatalf("Yield() = %v, want preempted %v", err, tt.wantPreempt)
+			}
+			if tt.wantPreempt {
+				if !granted(waiter) {
+					t.Fatal("waiter was not granted the yielded slot")
+				}
+				if pos, _ := holder.Position(); pos != 1 {
+					t.Fatalf("yielded holder at position %d, want 1", pos)
+				}
+				if got := s.Stats().Classes[tt.holder.String()].Preempted; got != 1 {
+					t.Fatalf("preempted = %d, want 1", got)
+				}
+			}
+		})
+	}
+}
+
+func TestResumedQueuesAhead(t *testing.T) {
+	s := New(1)
+	first := s.Enqueue(Background, nil)
+	second := s.Enqueue(Background, nil)
+	urgent := s.Enqueue(Request, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := first.Yield(ctx); err == nil {
+		t.Fatal("Yield() did not preempt")
+	}
+
+	for i, j := range []*Job{urgent, first, second} {
+		if !granted(j) {
+			t.Fatalf("job %d not granted in turn", i)
+		}
+		j.Done()
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/accesslog"
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// accessInterceptor writes an access log entry for every call. It runs
+// before authInterceptor, so rejected calls are recorded too; write failures
+// are journaled by poolManager rather than failing the call.
+func accessInterceptor(l *accesslog.Logger, poolManager PoolManager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if l == nil {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		ctx, identity := accesslog.WithCall(ctx)
+		resp, err := handler(ctx, req)
+		served := 0
+		if r, ok := resp.(interface{ GetParams() []*pb.PreParamsData }); ok && err == nil {
+			served = len(r.GetParams())
+		}
+		recordAccess(ctx, l, poolManager, info.FullMethod, identity, req, served, err, start)
+		return resp, err
+	}
+}
+
+// accessStreamInterceptor is accessInterceptor for streaming RPCs, recording
+// one entry per stream with the parameter sets sent before it ended
+func accessStreamInterceptor(l *accesslog.Logger, poolManager PoolManager) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if l == nil {
+			return handler(srv, ss)
+		}
+		start := time.Now()
+		ctx, identity := accesslog.WithCall(ss.Context())
+		stream := &accessStream{contextStream: contextStream{ServerStream: ss, ctx: ctx}}
+		err := handler(srv, stream)
+		recordAccess(ctx, l, poolManager, info.FullMethod, identity, stream.req, stream.served, err, start)
+		return err
+	}
+}
+
+// accessStream keeps the request of a stream and counts the parameter sets
+// sent on it
+type accessStream struct {
+	contextStream
+	req    interface{}
+	served int
+}
+
+func (s *accessStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.req == nil {
+		s.req = m
+	}
+	return err
+}
+
+func (s *accessStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if r, ok := m.(interface{ GetParams() []*pb.PreParamsData }); ok && err == nil {
+		s.served += len(r.GetParams())
+	}
+	return err
+}
+
+// recordAccess writes the entry of a finished call
+func recordAccess(ctx context.Context, l *accesslog.Logger, poolManager PoolManager, method string,
+	identity func() (string, string), req interface{}, served int, err error, start time.Time) {
+	e := accesslog.Entry{
+		Time:      start,
+		Method:    method,
+		Remote:    remoteAddr(ctx),
+		Pool:      metadataValue(ctx, PoolHeader),
+		Served:    served,
+		Outcome:   status.Code(err).String(),
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		TraceID:   trace.ID(ctx),
+	}
+	e.Identity, e.Auth = identity()
+	if e.Identity == "" {
+		e.Identity, e.Auth = certSubject(ctx)
+	}
+	if r, ok := req.(interface{ GetCount() uint32 }); ok {
+		e.Requested = r.GetCount()
+	}
+	if err := l.Record(e); err != nil {
+		trace.Logf(ctx, "Failed to write access log: %v", err)
+		poolManager.Errors().Record(errjournal.SeverityWarning, "access_log", err, map[string]string{"method": method})
+	}
+}
+
+// certSubject identifies a caller by its verified TLS client certificate
+func certSubject(ctx context.Context) (identity, auth string) {
+	pr, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", ""
+	}
+	info, ok := pr.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 {
+		return "", ""
+	}
+	return info.State.VerifiedChains[0][0].Subject.String(), "tls_cert"
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/slo"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	"github.com/TEENet-io/prime-service/internal/workerauth"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// AdminServer implements the operator/automation API
+type AdminServer struct {
+	pb.UnimplementedAdminServiceServer
+	poolManager PoolManager
+	peers       []string // Replica addresses reported by ListPeers
+	peerCreds   grpc.DialOption
+	slo         *slo.Tracker
+
+	// Locality of this instance, see WithLocality
+	region, zone string
+
+	// Worker registrations (nil: disabled), see WithWorkerRegistration
+	workers  *workerauth.Registry
+	auditLog *audit.Logger
+
+	diag diagnosticSources // See WithDiagnostics
+}
+
+// NewAdminServer creates an admin API server
+func NewAdminServer(poolManager PoolManager) *AdminServer {
+	return &AdminServer{poolManager: poolManager, peerCreds: grpc.WithTransportCredentials(insecure.NewCredentials())}
+}
+
+// pool returns the pool the request was routed to
+func (a *AdminServer) pool(ctx context.Context) PoolManager {
+	return poolFor(ctx, a.poolManager)
+}
+
+// GetPressure returns the pool scaling signal
+func (a *AdminServer) GetPressure(ctx context.Context, req *pb.Empty) (*pb.PoolPressure, error) {
+	r := a.pool(ctx).Pressure()
+	return &pb.PoolPressure{
+		Desired:               uint32(r.Desired),
+		Actual:                uint32(r.Actual),
+		Deficit:               uint32(r.Deficit),
+		FillRatio:             r.FillRatio,
+		InFlight:              uint32(r.InFlight),
+		SupplyRatePerMin:      r.SupplyRatePerMin,
+		ConsumptionRatePerMin: r.ConsumptionRatePerMin,
+		NetRatePerMin:         r.NetRatePerMin,
+		CapacityPerMin:        r.CapacityPerMin,
+		EtaSeconds:            r.ETASeconds,
+		Pressure:              r.Pressure,
+		AverageGenerationMs:   r.AverageGenerationMilli,
+		InstanceId:            r.InstanceID,
+	}, nil
+}
+
+// GetErrors returns recent journaled errors, newest first
+func (a *AdminServer) GetErrors(ctx context.Context, req *pb.GetErrorsRequest) (*pb.GetErrorsResponse, error) {
+	entries := a.pool(ctx).Errors().Entries(fromPBSeverity(req.MinSeverity), req.Component, int(req.Limit))
+
+	resp := &pb.GetErrorsResponse{Errors: make([]*pb.ErrorEntry, len(entries))}
+	for i, e := range entries {
+		resp.Errors[i] = &pb.ErrorEntry{
+			Time:      e.Time.Unix(),
+			Severity:  toPBSeverity(e.Severity),
+			Component: e.Component,
+			Error:     e.Error,
+			Context:   e.Context,
+		}
+	}
+	return resp, nil
+}
+
+// SetMaintenance enters or leaves maintenance mode, optionally waiting for
+// in-flight requests to drain
+func (a *AdminServer) SetMaintenance(ctx context.Context, req *pb.SetMaintenanceRequest) (*pb.MaintenanceStatus, error) {
+	a.pool(ctx).SetMaintenance(req.Enabled)
+
+	if req.Enabled && req.DrainTimeoutSeconds > 0 {
+		drainCtx, cancel := context.WithTimeout(ctx, time.Duration(req.DrainTimeoutSeconds)*time.Second)
+		defer cancel()
+		if err := a.pool(ctx).Drain(drainCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+	}
+
+	return a.maintenanceStatus(ctx), nil
+}
+
+// GetMaintenance reports the maintenance mode state
+func (a *AdminServer) GetMaintenance(ctx context.Context, req *pb.Empty) (*pb.MaintenanceStatus, error) {
+	return a.maintenanceStatus(ctx), nil
+}
+
+func (a *AdminServer) maintenanceStatus(ctx context.Context) *pb.MaintenanceStatus {
+	enabled := a.pool(ctx).InMaintenance()
+	active := a.pool(ctx).ActiveRequests()
+	return &pb.MaintenanceStatus{
+		Enabled:        enabled,
+		ActiveRequests: uint32(active),
+		Drained:        enabled && active == 0,
+	}
+}
+
+// SetGenerationPause pauses or resumes background generation
+func (a *AdminServer) SetGenerationPause(ctx context.Context, req *pb.SetGenerationPauseRequest) (*pb.GenerationPauseStatus, error) {
+	a.pool(ctx).PauseGeneration(req.Paused)
+	return a.generationPauseStatus(ctx), nil
+}
+
+// GetGenerationPause reports whether background generation is paused
+func (a *AdminServer) GetGenerationPause(ctx context.Context, req *pb.Empty) (*pb.GenerationPauseStatus, error) {
+	return a.generationPauseStatus(ctx), nil
+}
+
+func (a *AdminServer) generationPauseStatus(ctx context.Context) *pb.GenerationPauseStatus {
+	paused, parked := a.pool(ctx).GenerationPaused()
+	return &pb.GenerationPauseStatus{
+		Paused:   paused,
+		InFlight: uint32(a.pool(ctx).InFlight()),
+		Parked:   uint32(parked),
+	}
+}
+
+// FillPool starts generating up to the requested pool size
+func (a *AdminServer) FillPool(ctx context.Context, req *pb.FillPoolRequest) (*pb.FillPoolResponse, error) {
+	fill, err := a.pool(ctx).FillPool(int(req.Target), int(req.Concurrency))
+	switch {
+	case errors.Is(err, pool.ErrFillTarget):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, pool.ErrGenerating):
+		return nil, status.Error(codes.FailedPrecondition, "a refill is already running, retry when it completes")
+	case err != nil:
+		return nil, err
+	}
+	return &pb.FillPoolResponse{Target: uint32(fill.Target), Concurrency: uint32(fill.Workers), PoolSize: uint32(fill.PoolSize)}, nil
+}
+
+// GetFreeze reports whether the pool is frozen after an anomaly
+func (a *AdminServer) GetFreeze(ctx context.Context, req *pb.Empty) (*pb.FreezeStatus, error) {
+	return toPBFreeze(a.pool(ctx).Freeze()), nil
+}
+
+// Unfreeze lifts an anomaly freeze and returns the state it replaced
+func (a *AdminServer) Unfreeze(ctx context.Context, req *pb.Empty) (*pb.FreezeStatus, error) {
+	prev := a.pool(ctx).Unfreeze()
+	if prev.Frozen {
+		trace.Logf(ctx, "Pool unfrozen by admin request (anomaly: %s)", prev.Anomaly)
+	}
+	return toPBFreeze(prev), nil
+}
+
+// ListPoolItems pages through the pool contents without secret material
+func (a *AdminServer) ListPoolItems(ctx context.Context, req *pb.ListPoolItemsRequest) (*pb.ListPoolItemsResponse, error) {
+	page, err := a.pool(ctx).ListItems(req.PageToken, int(req.PageSize))
+	if errors.Is(err, pool.ErrInvalidPageToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resp := &pb.ListPoolItemsResponse{
+		Items:         make([]*pb.PoolItem, len(page.Items)),
+		NextPageToken: page.NextPageToken,
+		Total:         uint32(page.Total),
+	}
+	for i, item := range page.Items {
+		resp.Items[i] = toPBPoolItem(item, now)
+	}
+	return resp, nil
+}
+
+// toPBPoolItem converts an item description to protobuf format
+func toPBPoolItem(item pool.ItemInfo, now time.Time) *pb.PoolItem {
+	return &pb.PoolItem{
+		Fingerprint:          item.Fingerprint,
+		GeneratedAt:          item.GeneratedAt.Unix(),
+		AgeSeconds:           int64(now.Sub(item.GeneratedAt).Seconds()),
+		GenerationDurationMs: item.GenerationDuration.Milliseconds(),
+		Provenance:           toPBProvenance(item.Provenance),
+		Stale:                item.Stale,
+		Assurance:            item.Assurance,
+	}
+}
+
+// toPBFreeze converts a freeze state to protobuf format
+func toPBFreeze(f pool.FreezeStatus) *pb.FreezeStatus {
+	s := &pb.FreezeStatus{Frozen: f.Frozen, Anomaly: f.Anomaly, Reason: f.Reason}
+	if !f.Since.IsZero() {
+		s.Since = f.Since.Unix()
+	}
+	return s
+}
+
+// toPBSeverity converts a journal severity to protobuf format
+func toPBSeverity(s errjournal.Severity) pb.ErrorSeverity {
+	switch s {
+	case errjournal.SeverityWarning:
+		return pb.ErrorSeverity_ERROR_SEVERITY_WARNING
+	case errjournal.SeverityError:
+		return pb.ErrorSeverity_ERROR_SEVERITY_ERROR
+	}
+	return pb.ErrorSeverity_ERROR_SEVERITY_UNSPECIFIED
+}
+
+// fromPBSeverity converts a protobuf severity filter to a journal severity
+func fromPBSeverity(s pb.ErrorSeverity) errjournal.Severity {
+	switch s {
+	case pb.ErrorSeverity_ERROR_SEVERITY_WARNING:
+		return errjournal.SeverityWarning
+	case pb.ErrorSeverity_ERROR_SEVERITY_ERROR:
+		return errjournal.SeverityError
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/TEENet-io/prime-service/internal/accesslog"
+	"github.com/TEENet-io/prime-service/internal/apikey"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyHeader carries the client's API key, see WithAPIKeys
+const APIKeyHeader = "x-api-key"
+
+// keyedMethods hand out parameters and need an API key once keys are
+// configured; health and status calls stay open to load balancers and
+// monitoring
+var keyedMethods = map[string]bool{
+	pb.PrimeService_GetPreParams_FullMethodName:     true,
+	pb.PrimeService_StreamPreParams_FullMethodName:  true,
+	pb.PrimeService_WaitForPreParams_FullMethodName: true,
+}
+
+// adminServicePrefix prefixes every AdminService method. They inspect or
+// change the service's state and need an admin API key once keys are
+// configured.
+var adminServicePrefix = "/" + pb.AdminService_ServiceDesc.ServiceName + "/"
+
+// authInterceptor rejects calls to keyedMethods without a valid API key,
+// and AdminService calls without a valid admin key
+func authInterceptor(keys *apikey.Set) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, keys, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor is authInterceptor for streaming RPCs
+func authStreamInterceptor(keys *apikey.Set) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), keys, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate checks the API key of a call to method, attaching the
+// key's name and the namespace it is bound to
+func authenticate(ctx context.Context, keys *apikey.Set, method string) (context.Context, error) {
+	admin := strings.HasPrefix(method, adminServicePrefix)
+	if !keyedMethods[method] && !admin || !keys.Enabled() {
+		return ctx, nil
+	}
+	client, err := keys.Check(metadataValue(ctx, APIKeyHeader))
+	if err != nil {
+		trace.Logf(ctx, "Rejected %s from %s: %v", method, remoteAddr(ctx), err)
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if admin && !keys.Admin(client) {
+		trace.Logf(ctx, "Rejected %s from API client %s: not an admin key", method, client)
+		return nil, status.Errorf(codes.PermissionDenied, "%s needs an admin API key", method)
+	}
+	trace.Logf(ctx, "Authenticated API client %s", client)
+	accesslog.SetIdentity(ctx, client, "api_key")
+	ctx = context.WithValue(ctx, apiClientKey{}, client)
+	return withKeyNamespace(ctx, keys.Namespace(client)), nil
+}
+
+type apiClientKey struct{}
+
+// apiClientFor returns the name of the API key a call was made with ("":
+// none), which scopes its idempotency keys
+func apiClientFor(ctx context.Context) string {
+	client, _ := ctx.Value(apiClientKey{}).(string)
+	return client
+}
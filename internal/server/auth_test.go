@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TEENet-io/prime-service/internal/apikey"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthenticate(t *testing.T) {
+	keys, err := apikey.Load([]apikey.Key{
+		{Name: "user", Key: "user-key"},
+		{Name: "team-a", Key: "team-a-key", Namespace: "team-a"},
+		{Name: "ops", Key: "ops-key", Admin: true},
+	}, "")
+	if err != nil {
+		t.Fatalf("apikey.Load() = %v", err)
+	}
+	noKeys, err := apikey.Load(nil, "")
+	if err != nil {
+		t.Fatalf("apikey.Load() = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		keys          *apikey.Set
+		method        string
+		key           string
+		namespace     string // Sent in NamespaceHeader
+		wantCode      codes.Code
+		wantNamespace string // Charged namespace, checked when the call is admitted
+	}{
+		{"auth disabled", noKeys, pb.PrimeService_GetPreParams_FullMethodName, "", "", codes.OK, ""},
+		{"auth disabled admin", noKeys, pb.AdminService_CollectDiagnostics_FullMethodName, "", "", codes.OK, ""},
+		{"health stays open", keys, pb.PrimeService_HealthCheck_FullMethodName, "", "", codes.OK, ""},
+		{"status stays open", keys, pb.PrimeService_GetPoolStatus_FullMethodName, "", "", codes.OK, ""},
+		{"missing key", keys, pb.PrimeService_GetPreParams_FullMethodName, "", "", codes.Unauthenticated, ""},
+		{"unknown key", keys, pb.PrimeService_StreamPreParams_FullMethodName, "other-key", "", codes.Unauthenticated, ""},
+		{"valid key", keys, pb.PrimeService_WaitForPreParams_FullMethodName, "user-key", "", codes.OK, ""},
+		{"unbound key names namespace", keys, pb.PrimeService_GetPreParams_FullMethodName, "user-key", "team-b", codes.OK, "team-b"},
+		{"bound key", keys, pb.PrimeService_GetPreParams_FullMethodName, "team-a-key", "", codes.OK, "team-a"},
+		{"bound key names own namespace", keys, pb.PrimeService_GetPreParams_FullMethodName, "team-a-key", "team-a", codes.OK, "team-a"},
+		{"bound key names other namespace", keys, pb.PrimeService_GetPreParams_FullMethodName, "team-a-key", "team-b", codes.PermissionDenied, ""},
+		{"admin without key", keys, pb.AdminService_SetGenerationPause_FullMethodName, "", "", codes.Unauthenticated, ""},
+		{"admin with user key", keys, pb.AdminService_SetGenerationPause_FullMethodName, "user-key", "", codes.PermissionDenied, ""},
+		{"admin with admin key", keys, pb.AdminService_SetGenerationPause_FullMethodName, "ops-key", "", codes.OK, ""},
+		{"admin key on keyed method", keys, pb.PrimeService_GetPreParams_FullMethodName, "ops-key", "", codes.OK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			if tt.key != "" {
+				md.Set(APIKeyHeader, tt.key)
+			}
+			if tt.namespace != "" {
+				md.Set(NamespaceHeader, tt.namespace)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+
+			ctx, err := authenticate(ctx, tt.keys, tt.method)
+			if err == nil {
+				var namespace string
+				if namespace, err = namespaceFor(ctx); err == nil && namespace != tt.wantNamespace {
+					t.Fatalf("namespace = %q, want %q", namespace, tt.wantNamespace)
+				}
+			}
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %s (%v), want %s", got, err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	keys, err := apikey.Load([]apikey.Key{{Name: "team-a", Key: "team-a-key", Namespace: "team-a"}}, "")
+	if err != nil {
+		t.Fatalf("apikey.Load() = %v", err)
+	}
+	intercept := authInterceptor(keys)
+	info := &grpc.UnaryServerInfo{FullMethod: pb.PrimeService_GetPreParams_FullMethodName}
+
+	tests := []struct {
+		name       string
+		key        string
+		wantCalled bool
+	}{
+		{"rejected", "", false},
+		{"admitted", "team-a-key", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(APIKeyHeader, tt.key))
+			called := false
+			_, err := intercept(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				if namespace, err := namespaceFor(ctx); err != nil || namespace != "team-a" {
+					t.Errorf("handler namespace = %q, %v, want team-a", namespace, err)
+				}
+				if client := apiClientFor(ctx); client != "team-a" {
+					t.Errorf("handler API client = %q, want team-a", client)
+				}
+				return nil, nil
+			})
+			if called != tt.wantCalled {
+				t.Fatalf("handler called = %v (%v), want %v", called, err, tt.wantCalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestedBitSizes returns the bit sizes a request asks for, those of the
+// pool it was routed to where unset
+func (s *Server) requestedBitSizes(ctx context.Context, req *pb.GetPreParamsRequest) (int, int) {
+	primeBits, paillierBits := s.pool(ctx).BitSizes()
+	if req.PrimeBitSize > 0 {
+		primeBits = int(req.PrimeBitSize)
+	}
+	if req.PaillierBitSize > 0 {
+		paillierBits = int(req.PaillierBitSize)
+	}
+	return primeBits, paillierBits
+}
+
+// routeBitSizes checks a request asking for specific bit sizes against the
+// pool it was routed to: the pool named in PoolHeader, else the default
+// pool. A request is never moved to another pool, which may be set aside
+// for other callers. If the pool serves other sizes, routeBitSizes returns
+// the requested ones for synchronous generation, provided the request does
+// not name its pool and they are at least those of the pool and at most
+// maxRequestedBits; sized is false when the request can be served from the
+// pool in ctx.
+func (s *Server) routeBitSizes(ctx context.Context, req *pb.GetPreParamsRequest) (_ context.Context, primeBits, paillierBits int, sized bool, err error) {
+	if req.PrimeBitSize == 0 && req.PaillierBitSize == 0 {
+		return ctx, 0, 0, false, nil
+	}
+	primeBits, paillierBits = s.requestedBitSizes(ctx, req)
+	routedPrime, routedPaillier := s.pool(ctx).BitSizes()
+	if primeBits == routedPrime && paillierBits == routedPaillier {
+		return ctx, 0, 0, false, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(PoolHeader); len(values) > 0 && values[0] != "" {
+		return ctx, 0, 0, false, status.Errorf(codes.InvalidArgument, "pool %q serves %d/%d-bit parameters, not the requested %d/%d bits",
+			poolName(ctx), routedPrime, routedPaillier, primeBits, paillierBits)
+	}
+	if primeBits < routedPrime || paillierBits < routedPaillier || max(primeBits, paillierBits) > s.maxRequestedBits {
+		return ctx, 0, 0, false, status.Errorf(codes.InvalidArgument, "pool %q serves %d/%d-bit parameters, not the requested %d/%d bits; name a pool serving them in %s, or request sizes between %d/%d and %d bits for synchronous generation",
+			poolName(ctx), routedPrime, routedPaillier, primeBits, paillierBits, PoolHeader, routedPrime, routedPaillier, s.maxRequestedBits)
+	}
+	trace.Logf(ctx, "Generating %d/%d-bit parameters on request, pool %q serves %d/%d bits", primeBits, paillierBits, poolName(ctx), routedPrime, routedPaillier)
+	return ctx, primeBits, paillierBits, true, nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRouteBitSizes(t *testing.T) {
+	pools := map[string]PoolManager{"large": testPool(t, 512, 1024)}
+	s := NewServer(testPool(t, 256, 512))
+	s.maxRequestedBits = 2048
+
+	tests := []struct {
+		name         string
+		pool         string // Sent in PoolHeader
+		prime        uint32
+		paillier     uint32
+		wantCode     codes.Code
+		wantPool     string
+		wantSized    bool
+		wantPrime    int
+		wantPaillier int
+	}{
+		{name: "no sizes", wantPool: config.DefaultPoolName},
+		{name: "sizes of the default pool", prime: 256, paillier: 512, wantPool: config.DefaultPoolName},
+		{name: "prime size only", prime: 256, wantPool: config.DefaultPoolName},
+		// Another pool serving the sizes is not drawn from without naming it
+		{name: "sizes of a named pool", prime: 512, paillier: 1024, wantPool: config.DefaultPoolName, wantSized: true, wantPrime: 512, wantPaillier: 1024},
+		{name: "larger sizes generated", prime: 1024, paillier: 2048, wantPool: config.DefaultPoolName, wantSized: true, wantPrime: 1024, wantPaillier: 2048},
+		{name: "smaller sizes", prime: 128, paillier: 256, wantCode: codes.InvalidArgument},
+		{name: "sizes beyond the limit", prime: 2048, paillier: 4096, wantCode: codes.InvalidArgument},
+		{name: "named pool", pool: "large", prime: 512, paillier: 1024, wantPool: "large"},
+		{name: "named pool other sizes", pool: "large", prime: 1024, paillier: 2048, wantCode: codes.InvalidArgument},
+		{name: "named pool default sizes", pool: "large", prime: 256, paillier: 512, wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.MD{}
+			if tt.pool != "" {
+				md.Set(PoolHeader, tt.pool)
+			}
+			ctx, err := selectPool(metadata.NewIncomingContext(context.Background(), md), pools)
+			if err != nil {
+				t.Fatalf("selectPool() = %v", err)
+			}
+
+			ctx, prime, paillier, sized, err := s.routeBitSizes(ctx, &pb.GetPreParamsRequest{PrimeBitSize: tt.prime, PaillierBitSize: tt.paillier})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %s (%v), want %s", got, err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if got := poolName(ctx); got != tt.wantPool {
+				t.Fatalf("routed to pool %q, want %q", got, tt.wantPool)
+			}
+			if sized != tt.wantSized || prime != tt.wantPrime || paillier != tt.wantPaillier {
+				t.Fatalf("generate = %v %d/%d, want %v %d/%d", sized, prime, paillier, tt.wantSized, tt.wantPrime, tt.wantPaillier)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// bootstrapBatch is how many items one upstream call asks for, keeping
+	// responses well below gRPC's default receive limit
+	bootstrapBatch = 10
+
+	// bootstrapRetry is the pause before retrying a failed upstream call
+	bootstrapRetry = 5 * time.Second
+
+	// proxyPullTimeout bounds one top-up pull of a proxying edge
+	proxyPullTimeout = 30 * time.Second
+)
+
+// errUpstreamEmpty is returned by callUpstream when the upstream pool has
+// nothing to hand out
+var errUpstreamEmpty = errors.New("upstream pool is empty")
+
+// upstream pulls pooled items from the bootstrap upstream service, seeding
+// a brand-new pool and, in proxy mode, topping it up while it runs low
+type upstream struct {
+	poolManager PoolManager
+	address     string
+	apiKey      string
+	auditLog    *audit.Logger
+	conn        *grpc.ClientConn
+	client      pb.PrimeServiceClient
+}
+
+func newUpstream(poolManager PoolManager, o *options) (*upstream, error) {
+	conn, err := grpc.NewClient(o.bootstrapUpstream, o.dialCredentials())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to bootstrap upstream %s: %w", o.bootstrapUpstream, err)
+	}
+	return &upstream{
+		poolManager: poolManager,
+		address:     o.bootstrapUpstream,
+		apiKey:      o.bootstrapAPIKey,
+		auditLog:    o.auditLog,
+		conn:        conn,
+		client:      pb.NewPrimeServiceClient(conn),
+	}, nil
+}
+
+// run seeds a brand-new pool, then, with a proxy interval, tops the pool up
+// every interval until stop is closed
+func (u *upstream) run(o *options, stop <-chan struct{}) {
+	defer u.conn.Close()
+
+	if u.poolManager.FirstBoot() {
+		u.seed(o.bootstrapCount, o.bootstrapTimeout)
+	}
+	if o.proxyInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(o.proxyInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if needed := min(u.poolManager.Deficit(), o.proxyMaxPull); needed > 0 {
+				traceID := trace.NewID()
+				ctx, cancel := context.WithTimeout(trace.WithID(context.Background(), traceID), proxyPullTimeout)
+				u.pull(ctx, traceID, needed, "upstream_pull", u.poolManager.ProxyPreParams)
+				cancel()
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// seed fills a brand-new pool with count items (0: its deficit). Seeding
+// stops once the count is reached, the upstream runs dry or rejects the
+// calls, the local pool is full or the timeout passes; local generation
+// fills the rest as usual.
+func (u *upstream) seed(count int, timeout time.Duration) {
+	if count == 0 {
+		count = u.poolManager.Deficit()
+	}
+	if count <= 0 {
+		return
+	}
+
+	traceID := trace.NewID()
+	ctx, cancel := context.WithTimeout(trace.WithID(context.Background(), traceID), timeout)
+	defer cancel()
+
+	trace.Logf(ctx, "Seeding new pool with %d parameters from upstream %s", count, u.address)
+	start := time.Now()
+	received, accepted := u.pull(ctx, traceID, count, "bootstrap_seed", u.poolManager.SeedPreParams)
+	trace.Logf(ctx, "Seeded pool from upstream %s (received: %d, accepted: %d, took: %s)",
+		u.address, received, accepted, time.Since(start).Round(time.Millisecond))
+	if accepted < count {
+		log.Printf("Bootstrap seeding fell %d short of %d parameters; local generation makes up the rest", count-accepted, count)
+	}
+}
+
+// pull asks the upstream for count items in no_generate calls, so it hands
+// out pooled items only, and adds them to the pool with add. It stops early
+// when the upstream runs dry or rejects a call, or the pool takes fewer
+// items than it received. Each batch is audited as event.
+func (u *upstream) pull(ctx context.Context, traceID string, count int, event string, add func(context.Context, []*pool.PreParamsData) int) (received, accepted int) {
+	var err error
+	for batch := 1; accepted < count; batch++ {
+		want := min(count-accepted, bootstrapBatch)
+		// A retried batch reuses its key, so the upstream does not hand out
+		// a second set of items for a response that was lost
+		req := &pb.GetPreParamsRequest{
+			Count:          uint32(want),
+			NoGenerate:     true,
+			AllowImported:  true,
+			IdempotencyKey: fmt.Sprintf("%s-%s-%d", event, traceID, batch),
+		}
+		var resp *pb.GetPreParamsResponse
+		resp, err = callUpstream(ctx, u.client, req, u.apiKey, traceID)
+		if err != nil {
+			if errors.Is(err, errUpstreamEmpty) {
+				trace.Logf(ctx, "Upstream %s has no parameters to hand out", u.address)
+			} else {
+				trace.Logf(ctx, "Pulling from upstream %s stopped: %v", u.address, err)
+				u.poolManager.Errors().Record(errjournal.SeverityWarning, "upstream", err, map[string]string{"upstream": u.address, "request_id": traceID})
+			}
+			break
+		}
+
+		items := make([]*pool.PreParamsData, len(resp.Params))
+		for i, params := range resp.Params {
+			items[i] = fromPBParams(params)
+		}
+		n := add(ctx, items)
+		received += len(items)
+		accepted += n
+
+		if n > 0 {
+			if err := u.auditLog.Record(audit.Entry{
+				Event:   event,
+				Peer:    u.address,
+				Count:   n,
+				Items:   acceptedSeqs(items),
+				Detail:  fmt.Sprintf("received %d", len(items)),
+				TraceID: traceID,
+			}); err != nil {
+				trace.Logf(ctx, "Failed to record audit entry: %v", err)
+				u.poolManager.ReportAnomaly(pool.AnomalyAudit, fmt.Errorf("failed to record audit entry: %w", err))
+			}
+		}
+		if n < len(items) || len(items) < want {
+			// The pool is full, items failed validation or the upstream ran low
+			break
+		}
+	}
+
+	if errors.Is(err, errUpstreamEmpty) {
+		err = nil
+	}
+	u.poolManager.RecordUpstreamPull(u.address, received, accepted, err)
+	return received, accepted
+}
+
+// callUpstream calls GetPreParams on the upstream, retrying transient
+// failures until ctx is done
+func callUpstream(ctx context.Context, client pb.PrimeServiceClient, req *pb.GetPreParamsRequest, apiKey, traceID string) (*pb.GetPreParamsResponse, error) {
+	md := []string{trace.Header, traceID}
+	if apiKey != "" {
+		md = append(md, APIKeyHeader, apiKey)
+	}
+	callCtx := metadata.AppendToOutgoingContext(ctx, md...)
+
+	for {
+		resp, err := client.GetPreParams(callCtx, req)
+		if err == nil {
+			return resp, nil
+		}
+		st := status.Convert(err)
+		switch st.Code() {
+		case codes.ResourceExhausted:
+			for _, d := range st.Details() {
+				if info, ok := d.(*errdetails.ErrorInfo); ok && info.Reason == poolEmptyReason {
+					return nil, errUpstreamEmpty
+				}
+			}
+		case codes.Unavailable, codes.Aborted, codes.Internal:
+		default:
+			return nil, err
+		}
+
+		trace.Logf(ctx, "Upstream call failed, retrying in %s: %v", bootstrapRetry, err)
+		select {
+		case <-time.After(bootstrapRetry):
+		case <-ctx.Done():
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// responseTooLargeReason is the ErrorInfo reason telling clients to use StreamPreParams
+const responseTooLargeReason = "RESPONSE_TOO_LARGE"
+
+// itemOverhead bounds the encoded size of an item beyond its integers: field
+// tags, lengths, the timestamp and provisioning metadata
+const itemOverhead = 512
+
+// itemSizeEstimate is an upper bound of the encoded size of one item
+func itemSizeEstimate(primeBits, paillierBits int) int {
+	paillier := (paillierBits + 7) / 8 // N, PhiN, LambdaN; P and Q are half as long
+	ntilde := (2*primeBits + 7) / 8    // NTildei, H1i, H2i, Alpha, Beta
+	prime := (primeBits + 7) / 8       // P, Q
+	return 3*paillier + 2*(paillier/2+1) + 5*ntilde + 2*prime + itemOverhead
+}
+
+// checkResponseSize refuses requests whose response could exceed
+// maxResponseBytes, before any item is consumed, so the caller can switch
+// to StreamPreParams without losing items
+func (s *Server) checkResponseSize(ctx context.Context, req *pb.GetPreParamsRequest) error {
+	count := max(int(req.Count), 1)
+	itemSize := itemSizeEstimate(s.requestedBitSizes(ctx, req))
+	if count*itemSize <= s.maxResponseBytes {
+		return nil
+	}
+
+	maxCount := max(s.maxResponseBytes/itemSize, 1)
+	st := status.Newf(codes.ResourceExhausted, "%s: %d items need up to %d bytes, over the %d byte response limit; use StreamPreParams or at most %d items",
+		responseTooLargeReason, count, count*itemSize, s.maxResponseBytes, maxCount)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   responseTooLargeReason,
+		Domain:   errorDomain,
+		Metadata: map[string]string{"max_count": strconv.Itoa(maxCount)},
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// StreamPreParams serves a GetPreParams request in as many messages as
+// needed to keep each within maxResponseBytes. Items are sent as soon as
+// they are taken from the pool or generated, so clients can start on the
+// first ones while the rest are generated. With report_queue, messages
+// without items report the request's place in the on-demand queue.
+func (s *Server) StreamPreParams(req *pb.GetPreParamsRequest, stream pb.PrimeService_StreamPreParamsServer) error {
+	start := time.Now()
+	ctx := stream.Context()
+
+	// The items are consumed; a broken stream leaves them to an idempotent retry
+	sent := 0
+	var sendErr error
+	var onQueued func(*pb.QueueStatus)
+	if req.ReportQueue {
+		onQueued = func(q *pb.QueueStatus) {
+			if sendErr == nil {
+				sendErr = s.sendChunk(stream, &pb.GetPreParamsResponse{Queue: q}, start)
+			}
+		}
+	}
+	pbParams, err := s.preParams(ctx, req, func(items []*pb.PreParamsData) {
+		if sendErr == nil {
+			sendErr = s.sendItems(stream, items, start)
+			sent += len(items)
+		}
+	}, onQueued)
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// Replayed and DistinctSeconds items are only known once allocated; an
+	// empty result still gets its one message
+	if rest := pbParams[sent:]; len(rest) > 0 || sent == 0 {
+		return s.sendItems(stream, rest, start)
+	}
+	return nil
+}
+
+// sendItems sends items in messages within maxResponseBytes
+func (s *Server) sendItems(stream pb.PrimeService_StreamPreParamsServer, items []*pb.PreParamsData, start time.Time) error {
+	chunk := &pb.GetPreParamsResponse{}
+	for _, params := range items {
+		if len(chunk.Params) > 0 && proto.Size(chunk)+proto.Size(params)+16 > s.maxResponseBytes {
+			if err := s.sendChunk(stream, chunk, start); err != nil {
+				return err
+			}
+			chunk = &pb.GetPreParamsResponse{}
+		}
+		chunk.Params = append(chunk.Params, params)
+	}
+	return s.sendChunk(stream, chunk, start)
+}
+
+// sendChunk sends one message of a StreamPreParams response
+func (s *Server) sendChunk(stream pb.PrimeService_StreamPreParamsServer, chunk *pb.GetPreParamsResponse, start time.Time) error {
+	chunk.GenerationTimeMs = time.Since(start).Milliseconds()
+	if err := stream.Send(chunk); err != nil {
+		return fmt.Errorf("failed to send pre-params: %w", err)
+	}
+	return nil
+}
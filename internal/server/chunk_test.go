@@ -0,0 +1,105 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/generator"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// testPool returns an unstarted in-memory pool of the given bit sizes
+func testPool(t *testing.T, primeBits, paillierBits int) *pool.Manager {
+	t.Helper()
+	cfg := config.Default().Pool
+	cfg.Storage = pool.StorageMemory
+	cfg.PoolDir = t.TempDir()
+	cfg.PrimeBitSize, cfg.PaillierBitSize = primeBits, paillierBits
+	cfg.BackgroundGen, cfg.OnDemandGen, cfg.AutoSave = false, false, false
+	return pool.NewManager(generator.NewGenerator(), cfg)
+}
+
+// recordingStream keeps the messages of a StreamPreParams response
+type recordingStream struct {
+	grpc.ServerStream
+	sent []*pb.GetPreParamsResponse
+}
+
+func (r *recordingStream) Send(m *pb.GetPreParamsResponse) error {
+	r.sent = append(r.sent, m)
+	return nil
+}
+
+func TestCheckResponseSize(t *testing.T) {
+	s := NewServer(testPool(t, 256, 512))
+	s.maxResponseBytes = 10 * itemSizeEstimate(256, 512)
+
+	for _, count := range []uint32{0, 1, 10} {
+		if err := s.checkResponseSize(context.Background(), &pb.GetPreParamsRequest{Count: count}); err != nil {
+			t.Fatalf("checkResponseSize(%d items) = %v", count, err)
+		}
+	}
+
+	err := s.checkResponseSize(context.Background(), &pb.GetPreParamsRequest{Count: 11})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("checkResponseSize(11 items) = %v, want %s", err, codes.ResourceExhausted)
+	}
+	var info *errdetails.ErrorInfo
+	for _, d := range status.Convert(err).Details() {
+		if i, ok := d.(*errdetails.ErrorInfo); ok {
+			info = i
+		}
+	}
+	if info == nil || info.Reason != responseTooLargeReason || info.Metadata["max_count"] != "10" {
+		t.Fatalf("error details = %v, want %s with max_count 10", info, responseTooLargeReason)
+	}
+}
+
+func TestSendItems(t *testing.T) {
+	s := NewServer(testPool(t, 256, 512))
+	s.maxResponseBytes = 4096
+	items := make([]*pb.PreParamsData, 25)
+	for i := range items {
+		items[i] = &pb.PreParamsData{NTildei: bytes.Repeat([]byte{byte(i)}, 1000), GeneratedAt: int64(i)}
+	}
+	// An item over the limit on its own is still sent, in a message of its own
+	items[7].PaillierN = make([]byte, s.maxResponseBytes)
+
+	stream := &recordingStream{}
+	if err := s.sendItems(stream, items, time.Now()); err != nil {
+		t.Fatalf("sendItems() = %v", err)
+	}
+	var received []*pb.PreParamsData
+	for i, m := range stream.sent {
+		if len(m.Params) == 0 {
+			t.Fatalf("message %d carries no items", i)
+		}
+		if size := proto.Size(m); size > s.maxResponseBytes && len(m.Params) > 1 {
+			t.Fatalf("message %d of %d items takes %d bytes, over the %d byte limit", i, len(m.Params), size, s.maxResponseBytes)
+		}
+		received = append(received, m.Params...)
+	}
+	if len(stream.sent) < 2 || len(received) != len(items) {
+		t.Fatalf("sent %d items in %d messages, want %d items split up", len(received), len(stream.sent), len(items))
+	}
+	for i := range items {
+		if received[i] != items[i] {
+			t.Fatalf("item %d was sent out of order", i)
+		}
+	}
+
+	// An empty response still gets its one message
+	stream = &recordingStream{}
+	if err := s.sendItems(stream, nil, time.Now()); err != nil || len(stream.sent) != 1 {
+		t.Fatalf("sendItems(nil) = %v in %d messages, want 1", err, len(stream.sent))
+	}
+}
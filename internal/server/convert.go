@@ -0,0 +1,137 @@
+package server
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/pool"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"github.com/bnb-chain/tss-lib/v2/crypto/paillier"
+)
+
+// toPBParams converts a pool parameter set to protobuf format
+func toPBParams(params *pool.PreParamsData) *pb.PreParamsData {
+	return &pb.PreParamsData{
+		PaillierP:       params.PaillierKey.P.Bytes(),
+		PaillierQ:       params.PaillierKey.Q.Bytes(),
+		PaillierN:       params.PaillierKey.N.Bytes(),
+		PaillierPhiN:    params.PaillierKey.PhiN.Bytes(),
+		PaillierLambdaN: params.PaillierKey.LambdaN.Bytes(),
+		NTildei:         params.NTildei.Bytes(),
+		H1I:             params.H1i.Bytes(),
+		H2I:             params.H2i.Bytes(),
+		Alpha:           params.Alpha.Bytes(),
+		Beta:            params.Beta.Bytes(),
+		P:               params.P.Bytes(),
+		Q:               params.Q.Bytes(),
+		GeneratedAt:     params.GeneratedAt.Unix(),
+	}
+}
+
+// fromPBParams converts a protobuf parameter set back to pool format
+func fromPBParams(params *pb.PreParamsData) *pool.PreParamsData {
+	return &pool.PreParamsData{
+		PaillierKey: &paillier.PrivateKey{
+			PublicKey: paillier.PublicKey{
+				N: new(big.Int).SetBytes(params.PaillierN),
+			},
+			LambdaN: new(big.Int).SetBytes(params.PaillierLambdaN),
+			PhiN:    new(big.Int).SetBytes(params.PaillierPhiN),
+			P:       new(big.Int).SetBytes(params.PaillierP),
+			Q:       new(big.Int).SetBytes(params.PaillierQ),
+		},
+		NTildei:     new(big.Int).SetBytes(params.NTildei),
+		H1i:         new(big.Int).SetBytes(params.H1I),
+		H2i:         new(big.Int).SetBytes(params.H2I),
+		Alpha:       new(big.Int).SetBytes(params.Alpha),
+		Beta:        new(big.Int).SetBytes(params.Beta),
+		P:           new(big.Int).SetBytes(params.P),
+		Q:           new(big.Int).SetBytes(params.Q),
+		GeneratedAt: time.Unix(params.GeneratedAt, 0),
+
+		GenerationDuration: time.Duration(params.GetMetadata().GetGenerationDurationMs()) * time.Millisecond,
+		Provenance:         fromPBProvenance(params.GetMetadata().GetProvenance()),
+	}
+}
+
+// toPBQueue converts a request's place in the on-demand queue to protobuf
+// format, rounding the ETA up to whole seconds
+func toPBQueue(q pool.QueueStatus) *pb.QueueStatus {
+	eta := int64(-1)
+	if q.ETA > 0 {
+		eta = int64((q.ETA + time.Second - 1) / time.Second)
+	} else if q.Position == 0 {
+		eta = 0
+	}
+	return &pb.QueueStatus{Position: uint32(q.Position), EtaSeconds: eta}
+}
+
+// toPBServed converts a served item and its serving metadata to protobuf format
+func toPBServed(params *pool.ServedParams) *pb.PreParamsData {
+	data := toPBParams(params.PreParamsData)
+	data.Metadata = toPBMetadata(params)
+	return data
+}
+
+// toPBMetadata converts serving metadata to protobuf format
+func toPBMetadata(params *pool.ServedParams) *pb.ItemMetadata {
+	source := pb.ItemSource_ITEM_SOURCE_POOL
+	if params.Source == pool.SourceGenerated {
+		source = pb.ItemSource_ITEM_SOURCE_GENERATED
+	}
+
+	return &pb.ItemMetadata{
+		Source:               source,
+		PoolAgeMs:            params.PoolAge.Milliseconds(),
+		GenerationDurationMs: params.GenerationDuration.Milliseconds(),
+		Replayed:             params.Replayed,
+		Stale:                params.Stale,
+		SharedSecond:         params.SharedSecond,
+		Provenance:           toPBProvenance(params.Provenance),
+		Assurance:            pool.AssuranceOf(params.PreParamsData),
+	}
+}
+
+// toPBProvenance converts item provenance to protobuf format
+func toPBProvenance(p pool.Provenance) *pb.Provenance {
+	return &pb.Provenance{Seq: p.Seq, Instance: p.Instance, Host: p.Host, Burst: p.Burst, Worker: int32(p.Worker), Imported: p.Imported, Version: p.Version}
+}
+
+// toPBItemAges converts the age distribution of a pool to protobuf format
+func toPBItemAges(d pool.AgeDistribution) *pb.ItemAges {
+	ages := &pb.ItemAges{
+		MinSeconds:    d.MinSeconds,
+		MedianSeconds: d.MedianSeconds,
+		P90Seconds:    d.P90Seconds,
+		MaxSeconds:    d.MaxSeconds,
+		Stale:         uint32(d.Stale),
+		Buckets:       make([]*pb.AgeBucket, len(d.Buckets)),
+	}
+	if !d.Oldest.IsZero() {
+		ages.OldestGeneratedAt = d.Oldest.Unix()
+		ages.NewestGeneratedAt = d.Newest.Unix()
+	}
+	for i, b := range d.Buckets {
+		ages.Buckets[i] = &pb.AgeBucket{UpTo: b.UpTo, Count: uint32(b.Count)}
+	}
+	return ages
+}
+
+// acceptedSeqs returns the sequence numbers of the items AddPreParams or
+// AddWorkerPreParams accepted; the others have none
+func acceptedSeqs(items []*pool.PreParamsData) []uint64 {
+	var seqs []uint64
+	for _, item := range items {
+		if item.Provenance.Seq != 0 {
+			seqs = append(seqs, item.Provenance.Seq)
+		}
+	}
+	return seqs
+}
+
+// fromPBProvenance converts protobuf provenance back to pool format. The
+// imported flag and sequence number are not taken over: the receiver
+// revalidates by its own rules and numbers items in its own pool.
+func fromPBProvenance(p *pb.Provenance) pool.Provenance {
+	return pool.Provenance{Instance: p.GetInstance(), Host: p.GetHost(), Burst: p.GetBurst(), Worker: int(p.GetWorker()), Version: p.GetVersion()}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// exceedsPoolReason is the ErrorInfo reason telling clients to stream
+// batches larger than the pool
+const exceedsPoolReason = "EXCEEDS_POOL_SIZE"
+
+// checkCount refuses requests for more than maxBatchSize items
+func (s *Server) checkCount(count uint32) error {
+	if int(count) > s.maxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "count must be between 1 and %d (server.max_batch_size); split larger ceremonies into several requests", s.maxBatchSize)
+	}
+	return nil
+}
+
+// checkCapacity refuses unary requests for more items than the pool they
+// were routed to can hold, which it could only serve by generating most of
+// them while the caller waits for the whole batch. StreamPreParams delivers
+// such batches item by item instead.
+func (s *Server) checkCapacity(ctx context.Context, count uint32) error {
+	capacity := s.pool(ctx).MaxSize()
+	if int(count) <= capacity {
+		return nil
+	}
+
+	st := status.Newf(codes.InvalidArgument, "%s: count %d exceeds the pool's max_pool_size of %d; use StreamPreParams, which sends items as they are taken or generated, or at most %d items per call",
+		exceedsPoolReason, count, capacity, capacity)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   exceedsPoolReason,
+		Domain:   errorDomain,
+		Metadata: map[string]string{"max_count": strconv.Itoa(capacity)},
+	}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
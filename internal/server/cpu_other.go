@@ -0,0 +1,10 @@
+//go:build !unix
+
+package server
+
+import "time"
+
+// processCPUTime is not available on this platform
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"context"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+)
+
+// ListRefillCycles returns the latest refill cycles, newest first
+func (a *AdminServer) ListRefillCycles(ctx context.Context, req *pb.ListRefillCyclesRequest) (*pb.RefillCycleList, error) {
+	cycles := a.pool(ctx).RefillCycles(int(req.Limit))
+	resp := &pb.RefillCycleList{Cycles: make([]*pb.RefillCycle, len(cycles))}
+	for i, c := range cycles {
+		resp.Cycles[i] = &pb.RefillCycle{
+			Started:     c.Started.Unix(),
+			DurationMs:  c.Duration.Milliseconds(),
+			Mode:        c.Mode,
+			Reason:      c.Reason,
+			Target:      uint32(c.Target),
+			PoolSize:    uint32(c.PoolSize),
+			Needed:      uint32(c.Needed),
+			Generated:   uint32(c.Generated),
+			Failures:    uint32(c.Failures),
+			Workers:     uint32(c.Workers),
+			Interrupted: c.Interrupted,
+		}
+	}
+	return resp, nil
+}
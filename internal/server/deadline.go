@@ -0,0 +1,54 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// deadlineMethodPrefix selects the calls deadlines are enforced on; admin
+// calls such as prefills may legitimately run for a long time
+var deadlineMethodPrefix = "/" + pb.PrimeService_ServiceDesc.ServiceName + "/"
+
+// boundDeadline gives ctx the default deadline if it has none and shortens
+// it to max if it is further away (zero disables either)
+func boundDeadline(ctx context.Context, def, max time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	switch {
+	case !ok && def > 0:
+		return context.WithTimeout(ctx, def)
+	case max > 0 && (!ok || time.Until(deadline) > max):
+		return context.WithTimeout(ctx, max)
+	}
+	return ctx, func() {}
+}
+
+// deadlineInterceptor bounds the deadlines of PrimeService calls, so a
+// client without one cannot hold a worker in on-demand generation
+// indefinitely
+func deadlineInterceptor(def, max time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, deadlineMethodPrefix) {
+			return handler(ctx, req)
+		}
+		ctx, cancel := boundDeadline(ctx, def, max)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// deadlineStreamInterceptor is deadlineInterceptor for streaming RPCs,
+// bounding the whole stream
+func deadlineStreamInterceptor(def, max time.Duration) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, deadlineMethodPrefix) {
+			return handler(srv, ss)
+		}
+		ctx, cancel := boundDeadline(ss.Context(), def, max)
+		defer cancel()
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
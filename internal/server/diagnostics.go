@@ -0,0 +1,160 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/logring"
+	"github.com/TEENet-io/prime-service/internal/slo"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultDiagnosticsHistory is the usage history a bundle includes unless
+// asked otherwise
+const defaultDiagnosticsHistory = 7 * 24 * time.Hour
+
+// diagnosticSources are what a diagnostic bundle is built from besides the
+// pool, see WithDiagnostics
+type diagnosticSources struct {
+	config func() *config.Config // Effective configuration (nil: not included)
+	logs   *logring.Ring         // Recent log lines (nil: not included)
+	slo    *slo.Tracker
+}
+
+// CollectDiagnostics returns a diagnostic bundle for bug reports
+func (a *AdminServer) CollectDiagnostics(ctx context.Context, req *pb.CollectDiagnosticsRequest) (*pb.DiagnosticsBundle, error) {
+	history := time.Duration(req.HistorySeconds) * time.Second
+	if history < 0 {
+		return nil, status.Error(codes.InvalidArgument, "history_seconds must not be negative")
+	}
+
+	var buf bytes.Buffer
+	name, err := a.diag.write(&buf, a.pool(ctx), poolName(ctx), int(req.LogLines), history)
+	if err != nil {
+		trace.Logf(ctx, "Failed to collect diagnostics: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to collect diagnostics: %v", err)
+	}
+	trace.Logf(ctx, "Collected diagnostic bundle %s (%d bytes)", name, buf.Len())
+	return &pb.DiagnosticsBundle{Archive: buf.Bytes(), Filename: name}, nil
+}
+
+// write writes the diagnostic bundle of pool m as a gzipped tar archive to
+// w, returning its suggested file name. Every file sits in a directory named
+// after the bundle, so bundles of several instances unpack side by side.
+func (d diagnosticSources) write(w io.Writer, m PoolManager, poolName string, logLines int, history time.Duration) (string, error) {
+	if history == 0 {
+		history = defaultDiagnosticsHistory
+	}
+	now := time.Now().UTC()
+	base := fmt.Sprintf("prime-diag-%s-%s", m.InstanceID(), now.Format("20060102T150405Z"))
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: base + "/" + name, Mode: 0600, Size: int64(len(data)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", name, err)
+		}
+		return add(name, append(data, '\n'))
+	}
+
+	host, _ := os.Hostname()
+	files := []struct {
+		name string
+		fill func() error
+	}{
+		{"info.json", func() error {
+			return addJSON("info.json", map[string]any{
+				"instance_id":   m.InstanceID(),
+				"pool":          poolName,
+				"collected_at":  now,
+				"hostname":      host,
+				"pid":           os.Getpid(),
+				"go_version":    runtime.Version(),
+				"os_arch":       runtime.GOOS + "/" + runtime.GOARCH,
+				"num_cpu":       runtime.NumCPU(),
+				"gomaxprocs":    runtime.GOMAXPROCS(0),
+				"num_goroutine": runtime.NumGoroutine(),
+			})
+		}},
+		{"config.json", func() error {
+			if d.config == nil {
+				return nil
+			}
+			return addJSON("config.json", d.config().Redacted())
+		}},
+		{"logs.txt", func() error {
+			if d.logs == nil {
+				return nil
+			}
+			lines := d.logs.Lines(logLines)
+			return add("logs.txt", []byte(strings.Join(lines, "\n")+"\n"))
+		}},
+		{"status.json", func() error {
+			return addJSON("status.json", m.GetPoolStatus())
+		}},
+		{"history.json", func() error {
+			return addJSON("history.json", m.UsageHistory(now.Add(-history)))
+		}},
+		{"errors.json", func() error {
+			return addJSON("errors.json", m.Errors().Entries("", "", 0))
+		}},
+		{"goroutines.txt", func() error {
+			var dump bytes.Buffer
+			if err := pprof.Lookup("goroutine").WriteTo(&dump, 2); err != nil {
+				return fmt.Errorf("failed to dump goroutines: %w", err)
+			}
+			return add("goroutines.txt", dump.Bytes())
+		}},
+		{"metrics.json", func() error {
+			var mem runtime.MemStats
+			runtime.ReadMemStats(&mem)
+			objectives := []slo.Status{}
+			if d.slo != nil {
+				objectives = d.slo.Status()
+			}
+			return addJSON("metrics.json", map[string]any{
+				"generation":     m.GenerationMetrics(),
+				"pressure":       m.Pressure(),
+				"handler_panics": m.Panics(),
+				"slo":            objectives,
+				"memory":         mem,
+			})
+		}},
+	}
+	for _, f := range files {
+		if err := f.fill(); err != nil {
+			return "", fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	return base + ".tar.gz", nil
+}
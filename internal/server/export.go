@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxExport bounds the items of one ExportItems or ImportItems call
+const maxExport = 100
+
+// ExportItems moves items out of the pool for an operator to import elsewhere
+func (a *AdminServer) ExportItems(ctx context.Context, req *pb.ExportItemsRequest) (*pb.ExportItemsResponse, error) {
+	if req.Count == 0 || req.Count > maxExport {
+		return nil, status.Errorf(codes.InvalidArgument, "count must be between 1 and %d", maxExport)
+	}
+
+	m := a.pool(ctx)
+	items := m.ExportItems(ctx, int(req.Count))
+	resp := &pb.ExportItemsResponse{Params: make([]*pb.PreParamsData, len(items)), PoolSize: uint32(m.Size())}
+	seqs := make([]uint64, len(items))
+	for i, item := range items {
+		seqs[i] = item.Provenance.Seq
+		resp.Params[i] = toPBParams(item)
+		resp.Params[i].Metadata = &pb.ItemMetadata{
+			Source:               pb.ItemSource_ITEM_SOURCE_POOL,
+			GenerationDurationMs: item.GenerationDuration.Milliseconds(),
+			Provenance:           toPBProvenance(item.Provenance),
+		}
+	}
+
+	if len(items) > 0 {
+		a.audit(ctx, audit.Entry{Event: "admin_export", Remote: remoteAddr(ctx), Count: len(items), Items: seqs, TraceID: trace.ID(ctx)})
+	}
+	return resp, nil
+}
+
+// ImportItems adds items exported from another instance to the pool
+func (a *AdminServer) ImportItems(ctx context.Context, req *pb.ImportItemsRequest) (*pb.ImportItemsResponse, error) {
+	if len(req.Params) == 0 || len(req.Params) > maxExport {
+		return nil, status.Errorf(codes.InvalidArgument, "between 1 and %d items per call", maxExport)
+	}
+
+	items := make([]*pool.PreParamsData, len(req.Params))
+	for i, params := range req.Params {
+		items[i] = fromPBParams(params)
+	}
+	m := a.pool(ctx)
+	accepted := m.ImportItems(ctx, items)
+
+	trace.Logf(ctx, "Imported %d of %d parameters by admin request", accepted, len(items))
+	a.audit(ctx, audit.Entry{Event: "admin_import", Remote: remoteAddr(ctx), Count: accepted, Items: acceptedSeqs(items), Detail: fmt.Sprintf("received %d", len(items)), TraceID: trace.ID(ctx)})
+	return &pb.ImportItemsResponse{Accepted: uint32(accepted), PoolSize: uint32(m.Size())}, nil
+}
+
+// audit records an admin event, reporting audit failures as anomalies
+func (a *AdminServer) audit(ctx context.Context, e audit.Entry) {
+	if err := a.auditLog.Record(e); err != nil {
+		trace.Logf(ctx, "Failed to record audit entry: %v", err)
+		a.poolManager.ReportAnomaly(pool.AnomalyAudit, fmt.Errorf("failed to record audit entry: %w", err))
+	}
+}
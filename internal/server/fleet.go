@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+)
+
+// fleetQueryTimeout bounds how long ListPeers waits for each peer
+const fleetQueryTimeout = 5 * time.Second
+
+// ListPeers reports this instance followed by every configured peer
+func (a *AdminServer) ListPeers(ctx context.Context, req *pb.Empty) (*pb.FleetStatus, error) {
+	replicas := make([]*pb.ReplicaStatus, 1+len(a.peers))
+	replicas[0] = a.selfStatus()
+
+	var wg sync.WaitGroup
+	for i, addr := range a.peers {
+		wg.Add(1)
+		go func(i int, addr string) {
+			defer wg.Done()
+			replicas[i+1] = queryReplica(ctx, addr, a.peerCreds)
+		}(i, addr)
+	}
+	wg.Wait()
+
+	return &pb.FleetStatus{Replicas: replicas}, nil
+}
+
+// selfStatus describes the local instance
+func (a *AdminServer) selfStatus() *pb.ReplicaStatus {
+	status := a.poolManager.GetPoolStatus()
+	poolSize, _ := status["pool_size"].(int)
+	minSize, _ := status["min_size"].(int)
+	totalGenerated, _ := status["total_generated"].(int64)
+	totalServed, _ := status["total_served"].(int64)
+	maintenance := a.poolManager.InMaintenance()
+
+	return &pb.ReplicaStatus{
+		InstanceId:     a.poolManager.InstanceID(),
+		Self:           true,
+		Reachable:      true,
+		Healthy:        !maintenance,
+		PoolSize:       uint32(poolSize),
+		TargetSize:     uint32(minSize),
+		TotalGenerated: totalGenerated,
+		TotalServed:    totalServed,
+		Maintenance:    maintenance,
+		Region:         a.region,
+		Zone:           a.zone,
+	}
+}
+
+// queryReplica fetches the health and pool status of a peer
+func queryReplica(ctx context.Context, addr string, creds grpc.DialOption) *pb.ReplicaStatus {
+	replica := &pb.ReplicaStatus{Address: addr}
+
+	conn, err := grpc.NewClient(addr, creds)
+	if err != nil {
+		replica.Error = err.Error()
+		return replica
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, fleetQueryTimeout)
+	defer cancel()
+
+	client := pb.NewPrimeServiceClient(conn)
+	health, err := client.HealthCheck(ctx, &pb.Empty{})
+	if err != nil {
+		replica.Error = err.Error()
+		return replica
+	}
+	status, err := client.GetPoolStatus(ctx, &pb.Empty{})
+	if err != nil {
+		replica.Error = err.Error()
+		return replica
+	}
+
+	replica.Reachable = true
+	replica.InstanceId = health.InstanceId
+	replica.Healthy = health.Healthy
+	replica.TotalGenerated = status.TotalGenerated
+	replica.TotalServed = status.TotalServed
+	replica.Maintenance = status.Maintenance
+	replica.Region = status.Region
+	replica.Zone = status.Zone
+	for _, info := range status.Pools {
+		replica.PoolSize += info.Available
+		replica.TargetSize += info.TargetSize
+	}
+	return replica
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/pool"
+	pb "github.com/TEENet-io/prime-service/proto"
+)
+
+// ForecastPool reports the pool runway and the backlog of a planned event
+func (a *AdminServer) ForecastPool(ctx context.Context, req *pb.ForecastPoolRequest) (*pb.PoolForecast, error) {
+	fr := pool.ForecastRequest{
+		Lookback:   time.Duration(req.LookbackSeconds) * time.Second,
+		EventItems: int(req.EventItems),
+	}
+	if req.EventAt > 0 {
+		fr.EventAt = time.Unix(req.EventAt, 0)
+	}
+	return toPBForecast(a.pool(ctx).Forecast(fr)), nil
+}
+
+// toPBForecast converts a forecast to protobuf format
+func toPBForecast(f pool.Forecast) *pb.PoolForecast {
+	resp := &pb.PoolForecast{
+		InstanceId:          f.InstanceID,
+		PoolSize:            uint32(f.PoolSize),
+		MinPoolSize:         uint32(f.MinPoolSize),
+		MaxPoolSize:         uint32(f.MaxPoolSize),
+		HistoryHours:        f.HistoryHours,
+		ServedPerDay:        f.ServedPerDay,
+		PeakServedPerHour:   f.PeakServedPerHour,
+		GeneratedPerDay:     f.GeneratedPerDay,
+		Workers:             uint32(f.Workers),
+		AverageGenerationMs: f.AverageGenerationMs,
+		CapacityPerDay:      f.CapacityPerDay,
+		RunwayDays:          f.RunwayDays,
+		SustainedRunwayDays: f.SustainedRunwayDays,
+	}
+	if e := f.Event; e != nil {
+		resp.Event = &pb.EventForecast{
+			Items:           uint32(e.Items),
+			At:              e.At.Unix(),
+			BaselineServed:  e.BaselineServed,
+			Backlog:         uint32(e.Backlog),
+			GenerationHours: e.GenerationHours,
+			Feasible:        e.Feasible,
+			WorkersNeeded:   uint32(e.WorkersNeeded),
+			Recommendations: e.Recommendations,
+		}
+		if !e.StartBy.IsZero() {
+			resp.Event.StartBy = e.StartBy.Unix()
+		}
+	}
+	return resp
+}
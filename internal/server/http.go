@@ -0,0 +1,233 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/slo"
+)
+
+// StartAdminHTTPServer serves machine-readable admin endpoints over HTTP:
+//
+//	GET /pressure  pool scaling signal as JSON (for KEDA metrics-api / HPA adapters)
+//	GET /ready     200 when serving, 503 in maintenance mode or frozen (readiness probe)
+//	GET /errors    recent journaled errors, newest first (?severity=error&component=generator&limit=50)
+//	GET /status    pool status as JSON, with an ETag for conditional polling (If-None-Match)
+//	GET /alarms    firing alarms
+//	GET /metrics   generation queue, worker state, phase timing histograms, handler panics and upstream pulls
+//	GET /items     pool contents without secret material, oldest first (?page_size=100&page_token=...)
+//	GET /slo       latency objectives with compliance and burn rates (WithSLO)
+//	GET /forecast  pool runway and planned event backlog (?event_items=40&event_at=2026-01-02T09:00:00Z&lookback=168h)
+//	GET /cycles    latest refill cycles, newest first (?limit=20)
+//	GET /diagnostics  diagnostic bundle (.tar.gz) for bug reports (?log_lines=500&history=24h)
+//
+// Every pool endpoint answers for the default pool, or for a named pool
+// (WithPools) given with ?pool=<name>.
+func StartAdminHTTPServer(addr string, poolManager PoolManager, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	pools := o.pools
+	defaultManager := poolManager // Handlers receive the pool asked for
+
+	mux := http.NewServeMux()
+	handle := func(pattern string, fn func(w http.ResponseWriter, r *http.Request, poolManager PoolManager)) {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			m := poolManager
+			if name := r.URL.Query().Get("pool"); name != "" && name != config.DefaultPoolName {
+				if m = pools[name]; m == nil {
+					http.Error(w, "unknown pool", http.StatusNotFound)
+					return
+				}
+			}
+			fn(w, r, m)
+		})
+	}
+
+	handle("GET /pressure", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		writeJSON(w, poolManager.Pressure())
+	})
+	handle("GET /metrics", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		writeJSON(w, struct {
+			pool.GenerationMetrics
+			HandlerPanics int64               `json:"handler_panics"`     // Server-wide, see recoveryInterceptor
+			Upstream      *pool.UpstreamStats `json:"upstream,omitempty"` // Pulls from the bootstrap upstream
+		}{poolManager.GenerationMetrics(), defaultManager.Panics(), poolManager.UpstreamStats()})
+	})
+	handle("GET /ready", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		if poolManager.InMaintenance() {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		if poolManager.Frozen() {
+			http.Error(w, "frozen", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok\n"))
+	})
+	handle("GET /errors", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		query := r.URL.Query()
+		severity, err := errjournal.ParseSeverity(query.Get("severity"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		limit := 0
+		if v := query.Get("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+		}
+		writeJSON(w, poolManager.Errors().Entries(severity, query.Get("component"), limit))
+	})
+	handle("GET /status", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		// Take the version first: a change racing with the status snapshot
+		// then only makes the next poll fetch again
+		etag := poolManager.Version().ETag()
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "no-cache")
+		if etagMatch(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		writeJSON(w, poolManager.GetPoolStatus())
+	})
+	handle("GET /alarms", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		writeJSON(w, poolManager.Alarms())
+	})
+
+	handle("GET /items", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		query := r.URL.Query()
+		pageSize := 0
+		if v := query.Get("page_size"); v != "" {
+			var err error
+			if pageSize, err = strconv.Atoi(v); err != nil {
+				http.Error(w, "invalid page_size", http.StatusBadRequest)
+				return
+			}
+		}
+		page, err := poolManager.ListItems(query.Get("page_token"), pageSize)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, page)
+	})
+	handle("GET /cycles", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		limit := 0
+		if v := r.URL.Query().Get("limit"); v != "" {
+			var err error
+			if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+		}
+		writeJSON(w, poolManager.RefillCycles(limit))
+	})
+	handle("GET /forecast", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		query := r.URL.Query()
+		var req pool.ForecastRequest
+		var err error
+		if v := query.Get("event_items"); v != "" {
+			if req.EventItems, err = strconv.Atoi(v); err != nil || req.EventItems < 0 {
+				http.Error(w, "invalid event_items", http.StatusBadRequest)
+				return
+			}
+		}
+		if v := query.Get("event_at"); v != "" {
+			if req.EventAt, err = time.Parse(time.RFC3339, v); err != nil {
+				http.Error(w, "invalid event_at (want RFC 3339)", http.StatusBadRequest)
+				return
+			}
+		}
+		if v := query.Get("lookback"); v != "" {
+			if req.Lookback, err = time.ParseDuration(v); err != nil {
+				http.Error(w, "invalid lookback", http.StatusBadRequest)
+				return
+			}
+		}
+		writeJSON(w, poolManager.Forecast(req))
+	})
+	handle("GET /diagnostics", func(w http.ResponseWriter, r *http.Request, poolManager PoolManager) {
+		query := r.URL.Query()
+		logLines := 0
+		var history time.Duration
+		var err error
+		if v := query.Get("log_lines"); v != "" {
+			if logLines, err = strconv.Atoi(v); err != nil || logLines < 0 {
+				http.Error(w, "invalid log_lines", http.StatusBadRequest)
+				return
+			}
+		}
+		if v := query.Get("history"); v != "" {
+			if history, err = time.ParseDuration(v); err != nil || history < 0 {
+				http.Error(w, "invalid history", http.StatusBadRequest)
+				return
+			}
+		}
+		name := query.Get("pool")
+		if name == "" {
+			name = config.DefaultPoolName
+		}
+
+		// Built in memory first, so a failure can still be reported as an error
+		var buf bytes.Buffer
+		file, err := o.diagnostics().write(&buf, poolManager, name, logLines, history)
+		if err != nil {
+			log.Printf("Failed to collect diagnostics: %v", err)
+			http.Error(w, "failed to collect diagnostics", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file))
+		w.Write(buf.Bytes())
+	})
+
+	mux.HandleFunc("GET /slo", func(w http.ResponseWriter, r *http.Request) {
+		if o.slo == nil {
+			writeJSON(w, []slo.Status{})
+			return
+		}
+		writeJSON(w, o.slo.Status())
+	})
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("Starting admin HTTP server on %s", addr)
+	return srv.ListenAndServe()
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write JSON response: %v", err)
+	}
+}
+
+// etagMatch reports whether an If-None-Match header matches etag, using the
+// weak comparison HTTP prescribes for If-None-Match
+func etagMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ClearPool discards every item in the pool, optionally pausing background
+// generation first so it stays empty
+func (a *AdminServer) ClearPool(ctx context.Context, req *pb.ClearPoolRequest) (*pb.ClearPoolResponse, error) {
+	m := a.pool(ctx)
+	if req.PauseGeneration {
+		m.PauseGeneration(true)
+	}
+	cleared, err := m.ClearPool(ctx)
+	if errors.Is(err, pool.ErrSharedPool) {
+		return nil, status.Error(codes.FailedPrecondition, "the pool is kept in shared storage with other replicas; clear it there")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	paused, _ := m.GenerationPaused()
+	a.audit(ctx, audit.Entry{Event: "admin_clear", Remote: remoteAddr(ctx), Count: cleared, Detail: fmt.Sprintf("generation paused: %t", paused), TraceID: trace.ID(ctx)})
+	return &pb.ClearPoolResponse{Cleared: uint32(cleared), GenerationPaused: paused}, nil
+}
+
+// SetPoolLimits changes the pool sizes and refill threshold until the next
+// reload
+func (a *AdminServer) SetPoolLimits(ctx context.Context, req *pb.SetPoolLimitsRequest) (*pb.PoolLimits, error) {
+	m := a.pool(ctx)
+	old := m.Limits()
+	limits, err := m.SetLimits(ctx, pool.Limits{
+		MinPoolSize:     int(req.MinPoolSize),
+		MaxPoolSize:     int(req.MaxPoolSize),
+		RefillThreshold: int(req.RefillThreshold),
+	})
+	if errors.Is(err, pool.ErrInvalidLimits) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if limits != old {
+		a.audit(ctx, audit.Entry{Event: "admin_set_limits", Remote: remoteAddr(ctx), TraceID: trace.ID(ctx),
+			Detail: fmt.Sprintf("pool_size %d-%d -> %d-%d, refill_threshold %d -> %d",
+				old.MinPoolSize, old.MaxPoolSize, limits.MinPoolSize, limits.MaxPoolSize, old.RefillThreshold, limits.RefillThreshold)})
+	}
+	return &pb.PoolLimits{
+		MinPoolSize:     uint32(limits.MinPoolSize),
+		MaxPoolSize:     uint32(limits.MaxPoolSize),
+		RefillThreshold: uint32(limits.RefillThreshold),
+		PoolSize:        uint32(m.Size()),
+	}, nil
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/pool"
+)
+
+// PoolManager is what the gRPC, web and admin servers need from a pool.
+// *pool.Manager implements it; embedders can pass another implementation
+// (e.g. a database-backed, proxying or sharded pool, or a test double) to
+// StartGRPCServer, StartWebServer, StartAdminHTTPServer and WithPools
+// without changing the servers. It is composed of the smaller interfaces below.
+type PoolManager interface {
+	PoolSource
+	PoolReporter
+	PoolAdmin
+	PoolTransfer
+	PoolLifecycle
+}
+
+// PoolLifecycle starts and stops the background work of a pool. The servers
+// never call it; it lets embedders manage every pool through PoolManager.
+type PoolLifecycle interface {
+	Start(ctx context.Context) error
+	Stop()
+}
+
+// PoolSource serves parameter sets to clients
+type PoolSource interface {
+	GetPreParams(ctx context.Context, req pool.Request) ([]*pool.ServedParams, error)
+	GetPreParamsAtSize(ctx context.Context, req pool.Request, primeBits, paillierBits int) ([]*pool.ServedParams, error)
+	WaitForPreParams(ctx context.Context, req pool.Request, timeout time.Duration) ([]*pool.ServedParams, error)
+	Size() int
+	MaxSize() int
+	BitSizes() (primeBits, paillierBits int)
+	InstanceID() string
+	Version() pool.Version
+	ActiveRequests() int
+	InFlight() int
+}
+
+// PoolReporter describes the state and health of a pool, and records the
+// problems the servers run into
+type PoolReporter interface {
+	GetPoolStatus() map[string]interface{}
+	Pressure() pool.PressureReport
+	Deficit() int
+	Alarms() []pool.Alarm
+	Consistency() pool.ConsistencyReport
+	GenerationMetrics() pool.GenerationMetrics
+	UsageHistory(from time.Time) []pool.UsageBucket
+	Forecast(req pool.ForecastRequest) pool.Forecast
+	RefillCycles(limit int) []pool.RefillCycle
+	ListItems(pageToken string, pageSize int) (pool.ItemPage, error)
+	UpstreamStats() *pool.UpstreamStats
+	FirstBoot() bool
+	Panics() int64
+	Errors() *errjournal.Journal
+	ReportAnomaly(anomaly string, err error)
+	RecordPanic(ctx context.Context, method string, value interface{}, stack []byte)
+	AddHook(h pool.Hook)
+}
+
+// PoolAdmin carries out the operator actions of the admin service
+type PoolAdmin interface {
+	SetMaintenance(enabled bool)
+	InMaintenance() bool
+	Drain(ctx context.Context) error
+	PauseGeneration(paused bool)
+	GenerationPaused() (paused bool, parked int)
+	Freeze() pool.FreezeStatus
+	Frozen() bool
+	Unfreeze() pool.FreezeStatus
+	FillPool(target, workers int) (pool.FillResult, error)
+	PinItem(ctx context.Context, ref, reason string) (pool.PinnedInfo, error)
+	UnpinItem(ctx context.Context, ref string) (pool.PinnedInfo, error)
+	ClearPool(ctx context.Context) (int, error)
+	Limits() pool.Limits
+	SetLimits(ctx context.Context, l pool.Limits) (pool.Limits, error)
+	ListSnapshots() ([]pool.Snapshot, error)
+	SnapshotStatus() pool.SnapshotStatus
+	TakeSnapshot(ctx context.Context) (pool.Snapshot, error)
+	RestoreSnapshot(ctx context.Context, name string) (pool.SnapshotRestore, error)
+}
+
+// PoolTransfer moves parameter sets between a pool and its peers, upstreams
+// and export files
+type PoolTransfer interface {
+	AddPreParams(ctx context.Context, items []*pool.PreParamsData) int
+	AddWorkerPreParams(ctx context.Context, items []*pool.PreParamsData) int
+	TakeSurplus(ctx context.Context, max int) []*pool.PreParamsData
+	SeedPreParams(ctx context.Context, items []*pool.PreParamsData) int
+	ProxyPreParams(ctx context.Context, items []*pool.PreParamsData) int
+	ExportItems(ctx context.Context, max int) []*pool.PreParamsData
+	ImportItems(ctx context.Context, items []*pool.PreParamsData) int
+	RecordUpstreamPull(address string, received, accepted int, err error)
+}
+
+var _ PoolManager = (*pool.Manager)(nil)
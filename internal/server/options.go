@@ -0,0 +1,250 @@
+package server
+
+import (
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/accesslog"
+	"github.com/TEENet-io/prime-service/internal/apikey"
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/logring"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/proxyproto"
+	"github.com/TEENet-io/prime-service/internal/slo"
+	"github.com/TEENet-io/prime-service/internal/traffic"
+	"github.com/TEENet-io/prime-service/internal/workerauth"
+)
+
+// Option configures the servers started by StartGRPCServer, StartAdminHTTPServer
+// and StartWebServer
+type Option func(*options)
+
+type options struct {
+	loadReportInterval time.Duration
+	maxResponseBytes   int
+	maxRequestedBits   int
+	maxBatchSize       int
+	auditLog           *audit.Logger
+	accessLog          *accesslog.Logger
+	hooks              []pool.Hook
+	pools              map[string]PoolManager
+	slo                *slo.Tracker
+	traffic            *traffic.Recorder
+	allowedOrigins     []string
+	defaultDeadline    time.Duration
+	maxDeadline        time.Duration
+	region             string
+	zone               string
+
+	workers *workerauth.Registry
+
+	proxyTrusted []netip.Prefix
+	tls          *TLS
+	apiKeys      *apikey.Set
+
+	config func() *config.Config
+	logs   *logring.Ring
+
+	peerToken        string
+	peers            []string
+	peerSyncInterval time.Duration
+	peerMaxTransfer  int
+
+	bootstrapUpstream string
+	bootstrapAPIKey   string
+	bootstrapCount    int
+	bootstrapTimeout  time.Duration
+	proxyInterval     time.Duration
+	proxyMaxPull      int
+}
+
+// WithLoadReporting enables ORCA backend metric reporting (per-call trailers
+// and the out-of-band OpenRcaService), refreshed every interval
+func WithLoadReporting(interval time.Duration) Option {
+	return func(o *options) {
+		o.loadReportInterval = interval
+	}
+}
+
+// WithMaxResponseBytes bounds GetPreParams responses (default: 4 MiB);
+// larger batches are sent with StreamPreParams
+func WithMaxResponseBytes(n int) Option {
+	return func(o *options) {
+		o.maxResponseBytes = n
+	}
+}
+
+// WithMaxBatchSize bounds the items one call may request (default: 100)
+func WithMaxBatchSize(n int) Option {
+	return func(o *options) {
+		o.maxBatchSize = n
+	}
+}
+
+// WithMaxRequestedBitSize bounds the bit sizes GetPreParams generates on
+// request when no pool serves the sizes a client asks for (default: 4096)
+func WithMaxRequestedBitSize(n int) Option {
+	return func(o *options) {
+		o.maxRequestedBits = n
+	}
+}
+
+// WithAPIKeys requires an API key from the set in APIKeyHeader on calls
+// handing out parameters, once the set holds any key
+func WithAPIKeys(keys *apikey.Set) Option {
+	return func(o *options) {
+		o.apiKeys = keys
+	}
+}
+
+// WithProxyProtocol reads PROXY protocol headers from connections to the
+// gRPC and web listeners coming from the trusted proxies, so callers are
+// identified by their real address rather than the proxy's
+func WithProxyProtocol(trusted []netip.Prefix) Option {
+	return func(o *options) {
+		o.proxyTrusted = trusted
+	}
+}
+
+// wrapListener applies WithProxyProtocol to lis
+func (o *options) wrapListener(lis net.Listener) net.Listener {
+	if len(o.proxyTrusted) == 0 {
+		return lis
+	}
+	return proxyproto.NewListener(lis, o.proxyTrusted)
+}
+
+// WithDeadlines gives PrimeService calls without a deadline the default one
+// and shortens longer client deadlines to max (zero disables either)
+func WithDeadlines(def, max time.Duration) Option {
+	return func(o *options) {
+		o.defaultDeadline = def
+		o.maxDeadline = max
+	}
+}
+
+// WithLocality reports the region and zone of this instance in HealthCheck,
+// GetPoolStatus and ListPeers, so clients can prefer nearby replicas
+func WithLocality(region, zone string) Option {
+	return func(o *options) {
+		o.region = region
+		o.zone = zone
+	}
+}
+
+// WithAllowedOrigins admits browsers on these origins ("*": any) to the web
+// server; without it only same-origin and non-browser clients are served
+func WithAllowedOrigins(origins []string) Option {
+	return func(o *options) {
+		o.allowedOrigins = origins
+	}
+}
+
+// WithAuditLog records security-relevant events (e.g. peer transfers)
+func WithAuditLog(l *audit.Logger) Option {
+	return func(o *options) {
+		o.auditLog = l
+	}
+}
+
+// WithAccessLog writes an access log entry for every call, including
+// calls rejected for a missing or invalid API key
+func WithAccessLog(l *accesslog.Logger) Option {
+	return func(o *options) {
+		o.accessLog = l
+	}
+}
+
+// WithHooks registers hooks notified after each generated and consumed
+// item (see pool.Hook). Items generated before the server starts are not
+// reported; embedders needing those call Manager.AddHook before Start.
+func WithHooks(hooks ...pool.Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
+// WithPools serves additional named pools, selected per request with the
+// PoolHeader metadata key. Load reporting and peer sharing only cover the
+// default pool.
+func WithPools(pools map[string]PoolManager) Option {
+	return func(o *options) {
+		o.pools = pools
+	}
+}
+
+// WithSLO times calls against the latency objectives of t and serves their
+// status with GetSLOStatus and the admin HTTP /slo endpoint
+func WithSLO(t *slo.Tracker) Option {
+	return func(o *options) {
+		o.slo = t
+	}
+}
+
+// WithTrafficRecording appends an anonymized record of every GetPreParams
+// and StreamPreParams call to r, for replay with primectl replay
+func WithTrafficRecording(r *traffic.Recorder) Option {
+	return func(o *options) {
+		o.traffic = r
+	}
+}
+
+// WithPeerSharing serves surplus items to peers presenting token and, if
+// peers are given, pulls up to maxTransfer items from them every interval
+// while the local pool is below MinPoolSize
+func WithPeerSharing(token string, peers []string, interval time.Duration, maxTransfer int) Option {
+	return func(o *options) {
+		o.peerToken = token
+		o.peers = peers
+		o.peerSyncInterval = interval
+		o.peerMaxTransfer = maxTransfer
+	}
+}
+
+// WithBootstrap seeds a brand-new pool with count items (0: its deficit)
+// pulled from the upstream service at address, presenting apiKey if set,
+// and gives up after timeout
+func WithBootstrap(address, apiKey string, count int, timeout time.Duration) Option {
+	return func(o *options) {
+		o.bootstrapUpstream = address
+		o.bootstrapAPIKey = apiKey
+		o.bootstrapCount = count
+		o.bootstrapTimeout = timeout
+	}
+}
+
+// WithUpstreamProxy makes the instance a caching proxy of the WithBootstrap
+// upstream: every interval, while the pool is below MinPoolSize, it pulls
+// up to maxPull items from there
+func WithUpstreamProxy(interval time.Duration, maxPull int) Option {
+	return func(o *options) {
+		o.proxyInterval = interval
+		o.proxyMaxPull = maxPull
+	}
+}
+
+// WithWorkerRegistration serves WorkerService: generate-only workers
+// register with r's bootstrap token and submit parameters with the
+// short-lived tokens r issues
+func WithWorkerRegistration(r *workerauth.Registry) Option {
+	return func(o *options) {
+		o.workers = r
+	}
+}
+
+// WithDiagnostics includes the effective configuration returned by cfg
+// (secrets redacted) and the log lines kept by logs in CollectDiagnostics
+// bundles; without it bundles hold only the pool and runtime state
+func WithDiagnostics(cfg func() *config.Config, logs *logring.Ring) Option {
+	return func(o *options) {
+		o.config = cfg
+		o.logs = logs
+	}
+}
+
+// diagnostics returns the sources of diagnostic bundles
+func (o *options) diagnostics() diagnosticSources {
+	return diagnosticSources{config: o.config, logs: o.logs, slo: o.slo}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"runtime"
+	"time"
+
+	"google.golang.org/grpc/orca"
+)
+
+// loadReporter periodically publishes ORCA backend metrics so xDS/Envoy load
+// balancers can steer GetPreParams traffic to the replica with the fullest pool
+type loadReporter struct {
+	recorder    orca.ServerMetricsRecorder
+	poolManager PoolManager
+
+	lastCPU  time.Duration
+	lastWall time.Time
+}
+
+func newLoadReporter(poolManager PoolManager) *loadReporter {
+	r := &loadReporter{
+		recorder:    orca.NewServerMetricsRecorder(),
+		poolManager: poolManager,
+	}
+	r.lastCPU, _ = processCPUTime()
+	r.lastWall = time.Now()
+	r.update()
+	return r
+}
+
+// run refreshes the metrics every interval until stop is closed
+func (r *loadReporter) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.update()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// update samples pool fullness, in-flight generation and process CPU load
+func (r *loadReporter) update() {
+	status := r.poolManager.GetPoolStatus()
+
+	poolSize, _ := status["pool_size"].(int)
+	maxSize, _ := status["max_size"].(int)
+	inFlight, _ := status["in_flight"].(int)
+	maxConcurrent, _ := status["max_concurrent"].(int)
+
+	fullness := 0.0
+	if maxSize > 0 {
+		fullness = float64(poolSize) / float64(maxSize)
+	}
+
+	// Balancers prefer low utilization, so an emptier pool reports as busier
+	r.recorder.SetApplicationUtilization(1 - fullness)
+	r.recorder.SetNamedUtilization("pool_fullness", fullness)
+	if maxConcurrent > 0 {
+		r.recorder.SetNamedUtilization("generation", float64(inFlight)/float64(maxConcurrent))
+	}
+
+	if cpu, ok := processCPUTime(); ok {
+		now := time.Now()
+		wall := now.Sub(r.lastWall)
+		if wall > 0 {
+			util := float64(cpu-r.lastCPU) / float64(wall) / float64(runtime.NumCPU())
+			r.recorder.SetCPUUtilization(util)
+		}
+		r.lastCPU, r.lastWall = cpu, now
+	}
+}
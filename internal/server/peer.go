@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// peerTokenHeader carries the shared peer token
+const peerTokenHeader = "x-peer-token"
+
+// PeerServer hands surplus pool items to authenticated peer replicas
+type PeerServer struct {
+	pb.UnimplementedPeerServiceServer
+	poolManager PoolManager
+	token       string
+	auditLog    *audit.Logger
+}
+
+// PullSurplus returns up to the requested number of items above MinPoolSize
+func (p *PeerServer) PullSurplus(ctx context.Context, req *pb.PullSurplusRequest) (*pb.PullSurplusResponse, error) {
+	if !p.authorized(ctx) {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid peer token")
+	}
+
+	count := int(req.Count)
+	if count > 100 {
+		count = 100
+	}
+
+	items := p.poolManager.TakeSurplus(ctx, count)
+	pbParams := make([]*pb.PreParamsData, len(items))
+	seqs := make([]uint64, len(items))
+	for i, item := range items {
+		seqs[i] = item.Provenance.Seq
+		pbParams[i] = toPBParams(item)
+		pbParams[i].Metadata = &pb.ItemMetadata{
+			Source:               pb.ItemSource_ITEM_SOURCE_POOL,
+			GenerationDurationMs: item.GenerationDuration.Milliseconds(),
+			Provenance:           toPBProvenance(item.Provenance),
+		}
+	}
+
+	if len(items) > 0 {
+		if err := p.auditLog.Record(audit.Entry{Event: "peer_transfer_out", Peer: remoteAddr(ctx), Count: len(items), Items: seqs, TraceID: trace.ID(ctx)}); err != nil {
+			trace.Logf(ctx, "Failed to record audit entry: %v", err)
+			p.poolManager.ReportAnomaly(pool.AnomalyAudit, fmt.Errorf("failed to record audit entry: %w", err))
+		}
+	}
+
+	return &pb.PullSurplusResponse{Params: pbParams}, nil
+}
+
+// authorized checks the peer token in constant time
+func (p *PeerServer) authorized(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	values := md.Get(peerTokenHeader)
+	if len(values) != 1 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(values[0]), []byte(p.token)) == 1
+}
+
+// peerPuller tops up an under-filled pool from peer replicas
+type peerPuller struct {
+	poolManager PoolManager
+	token       string
+	maxTransfer int
+	auditLog    *audit.Logger
+	peers       []*peerConn
+}
+
+type peerConn struct {
+	address string
+	conn    *grpc.ClientConn
+	client  pb.PeerServiceClient
+}
+
+func newPeerPuller(poolManager PoolManager, o *options) (*peerPuller, error) {
+	p := &peerPuller{
+		poolManager: poolManager,
+		token:       o.peerToken,
+		maxTransfer: o.peerMaxTransfer,
+		auditLog:    o.auditLog,
+	}
+	for _, addr := range o.peers {
+		conn, err := grpc.NewClient(addr, o.dialCredentials())
+		if err != nil {
+			p.close()
+			return nil, fmt.Errorf("failed to connect to peer %s: %w", addr, err)
+		}
+		p.peers = append(p.peers, &peerConn{address: addr, conn: conn, client: pb.NewPeerServiceClient(conn)})
+	}
+	return p, nil
+}
+
+// run pulls from peers every interval until stop is closed
+func (p *peerPuller) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer p.close()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.pull()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// pull requests the pool deficit from each peer in turn
+func (p *peerPuller) pull() {
+	needed := p.poolManager.Deficit()
+	if needed > p.maxTransfer {
+		needed = p.maxTransfer
+	}
+
+	for _, pc := range p.peers {
+		if needed <= 0 {
+			return
+		}
+
+		// Both sides log and audit the transfer under the same trace ID
+		traceID := trace.NewID()
+		ctx := trace.WithID(context.Background(), traceID)
+		callCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		callCtx = metadata.AppendToOutgoingContext(callCtx, peerTokenHeader, p.token, trace.Header, traceID)
+		resp, err := pc.client.PullSurplus(callCtx, &pb.PullSurplusRequest{Count: uint32(needed)})
+		cancel()
+		if err != nil {
+			trace.Logf(ctx, "Failed to pull surplus from peer %s: %v", pc.address, err)
+			p.poolManager.Errors().Record(errjournal.SeverityWarning, "peer", err, map[string]string{"peer": pc.address, "request_id": traceID})
+			continue
+		}
+		if len(resp.Params) == 0 {
+			continue
+		}
+
+		items := make([]*pool.PreParamsData, len(resp.Params))
+		for i, params := range resp.Params {
+			items[i] = fromPBParams(params)
+		}
+		accepted := p.poolManager.AddPreParams(ctx, items)
+		needed -= accepted
+
+		trace.Logf(ctx, "Received %d parameters from peer %s (accepted: %d)", len(items), pc.address, accepted)
+		if err := p.auditLog.Record(audit.Entry{
+			Event:   "peer_transfer_in",
+			Peer:    pc.address,
+			Count:   accepted,
+			Items:   acceptedSeqs(items),
+			Detail:  fmt.Sprintf("received %d", len(items)),
+			TraceID: traceID,
+		}); err != nil {
+			trace.Logf(ctx, "Failed to record audit entry: %v", err)
+			p.poolManager.ReportAnomaly(pool.AnomalyAudit, fmt.Errorf("failed to record audit entry: %w", err))
+		}
+	}
+}
+
+func (p *peerPuller) close() {
+	for _, pc := range p.peers {
+		pc.conn.Close()
+	}
+}
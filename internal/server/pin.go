@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PinItem sets a pool item aside, or returns a pinned one to the pool
+func (a *AdminServer) PinItem(ctx context.Context, req *pb.PinItemRequest) (*pb.PinnedItem, error) {
+	if req.Fingerprint == "" {
+		return nil, status.Error(codes.InvalidArgument, "fingerprint or sequence number is required")
+	}
+
+	var info pool.PinnedInfo
+	var err error
+	event := "item_pinned"
+	if req.Unpin {
+		event = "item_unpinned"
+		info, err = a.pool(ctx).UnpinItem(ctx, req.Fingerprint)
+	} else {
+		info, err = a.pool(ctx).PinItem(ctx, req.Fingerprint, req.Reason)
+	}
+	if errors.Is(err, pool.ErrItemNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		// Only an unpinned item failing verification ends here; it is gone
+		event = "item_quarantined"
+	}
+
+	detail := fmt.Sprintf("fingerprint=%s", info.Fingerprint)
+	if req.Reason != "" {
+		detail += fmt.Sprintf(" reason=%q", req.Reason)
+	}
+	items := []uint64{info.Provenance.Seq}
+	if aerr := a.auditLog.Record(audit.Entry{Event: event, Remote: remoteAddr(ctx), Count: 1, Items: items, Detail: detail, TraceID: trace.ID(ctx)}); aerr != nil {
+		trace.Logf(ctx, "Failed to record audit entry: %v", aerr)
+		a.poolManager.ReportAnomaly(pool.AnomalyAudit, fmt.Errorf("failed to record audit entry: %w", aerr))
+	}
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return toPBPinned(info, time.Now()), nil
+}
+
+// toPBPinned converts a pinned item description to protobuf format
+func toPBPinned(info pool.PinnedInfo, now time.Time) *pb.PinnedItem {
+	return &pb.PinnedItem{
+		Item:     toPBPoolItem(info.ItemInfo, now),
+		PinnedAt: info.PinnedAt.Unix(),
+		Reason:   info.Reason,
+	}
+}
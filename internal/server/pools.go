@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// PoolHeader selects a named pool; requests without it (or naming
+// "default") use the default pool
+const PoolHeader = "x-prime-pool"
+
+// selectedPool is the named pool a request was routed to
+type selectedPool struct {
+	name    string
+	manager PoolManager
+}
+
+type selectedPoolKey struct{}
+
+// poolInterceptor routes requests carrying PoolHeader to the named pool and
+// rejects unknown names, so a misconfigured client never silently draws from
+// another parameter class
+func poolInterceptor(pools map[string]PoolManager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := selectPool(ctx, pools)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// poolStreamInterceptor is poolInterceptor for streaming RPCs
+func poolStreamInterceptor(pools map[string]PoolManager) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := selectPool(ss.Context(), pools)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// selectPool attaches the pool named in the request metadata to ctx
+func selectPool(ctx context.Context, pools map[string]PoolManager) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(PoolHeader)
+	if len(values) == 0 || values[0] == "" || values[0] == config.DefaultPoolName {
+		return ctx, nil
+	}
+
+	m, ok := pools[values[0]]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown pool %q", values[0])
+	}
+	return context.WithValue(ctx, selectedPoolKey{}, selectedPool{name: values[0], manager: m}), nil
+}
+
+// poolFor returns the pool a request was routed to, or def for the default pool
+func poolFor(ctx context.Context, def PoolManager) PoolManager {
+	if p, ok := ctx.Value(selectedPoolKey{}).(selectedPool); ok {
+		return p.manager
+	}
+	return def
+}
+
+// poolName returns the name of the pool a request was routed to
+func poolName(ctx context.Context) string {
+	if p, ok := ctx.Value(selectedPoolKey{}).(selectedPool); ok {
+		return p.name
+	}
+	return config.DefaultPoolName
+}
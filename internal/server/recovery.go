@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/TEENet-io/prime-service/internal/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// recoveryInterceptor turns a panic in the handler into an Internal error,
+// so it fails one call instead of the process and the unsaved pool state.
+// Panics are counted and journaled with their stack by poolManager.
+func recoveryInterceptor(poolManager PoolManager) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(ctx, poolManager, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// recoveryStreamInterceptor is recoveryInterceptor for streaming RPCs
+func recoveryStreamInterceptor(poolManager PoolManager) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoveredError(ss.Context(), poolManager, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recoveredError records a recovered panic and returns the error sent to
+// the client, which names the request ID but not the panic
+func recoveredError(ctx context.Context, poolManager PoolManager, method string, r interface{}) error {
+	poolManager.RecordPanic(ctx, method, r, debug.Stack())
+	return status.Errorf(codes.Internal, "internal error (request %s)", trace.ID(ctx))
+}
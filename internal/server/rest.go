@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"connectrpc.com/connect"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// restJSON encodes REST responses with the proto field names, like the
+// query parameters
+var restJSON = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// registerREST adds the REST gateway to the web server mux, for consumers
+// without a gRPC or Connect client:
+//
+//	POST /v1/preparams   GetPreParams (?count=2&idempotency_key=...&no_generate=true&pool=...)
+//	GET /v1/pool/status  GetPoolStatus (?pool=...)
+//	GET /v1/healthz      HealthCheck, 503 when unhealthy
+//
+// Taking parameters consumes them, so it is a POST: caches, prefetchers and
+// crawlers never replay it. Calls pass the same interceptors as the RPCs
+// they map to.
+func registerREST(mux *http.ServeMux, server *Server, interceptors []grpc.UnaryServerInterceptor) {
+	mux.Handle("POST /v1/preparams", restUnary(pb.PrimeService_GetPreParams_FullMethodName, interceptors, server.GetPreParams, preParamsQuery, nil))
+	mux.Handle("GET /v1/pool/status", restUnary(pb.PrimeService_GetPoolStatus_FullMethodName, interceptors, server.GetPoolStatus, emptyQuery, nil))
+	mux.Handle("GET /v1/healthz", restUnary(pb.PrimeService_HealthCheck_FullMethodName, interceptors, server.HealthCheck, emptyQuery, func(h *pb.HealthStatus) int {
+		if !h.Healthy {
+			return http.StatusServiceUnavailable
+		}
+		return http.StatusOK
+	}))
+}
+
+// restUnary adapts a gRPC unary method to a REST handler taking its request
+// from the query string and answering with JSON. statusOf, if set, picks
+// the HTTP status of successful calls.
+func restUnary[Req, Res any](fullMethod string, interceptors []grpc.UnaryServerInterceptor, fn func(context.Context, *Req) (*Res, error), parse func(url.Values) (*Req, error), statusOf func(*Res) int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		header := r.Header.Clone()
+		// The pool is chosen with ?pool=, as on the admin endpoints
+		if name := query.Get("pool"); name != "" {
+			header.Set(PoolHeader, name)
+			query.Del("pool")
+		}
+		if namespace := query.Get("namespace"); namespace != "" {
+			header.Set(NamespaceHeader, namespace)
+			query.Del("namespace")
+		}
+
+		req, err := parse(query)
+		if err != nil {
+			writeRESTError(w, status.Error(codes.InvalidArgument, err.Error()))
+			return
+		}
+
+		resp, traceID, err := runUnary(r.Context(), header, r.RemoteAddr, fullMethod, interceptors, fn, req)
+		if traceID != "" {
+			w.Header().Set(trace.Header, traceID)
+		}
+		if err != nil {
+			writeRESTError(w, err)
+			return
+		}
+
+		data, err := restJSON.Marshal(any(resp).(proto.Message))
+		if err != nil {
+			writeRESTError(w, status.Errorf(codes.Internal, "failed to encode response: %v", err))
+			return
+		}
+		code := http.StatusOK
+		if statusOf != nil {
+			code = statusOf(resp)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		w.Write(append(data, '\n'))
+	})
+}
+
+// restError is the JSON body of a failed REST call
+type restError struct {
+	Code     string            `json:"code"` // gRPC code name, e.g. resource_exhausted
+	Message  string            `json:"message"`
+	Reason   string            `json:"reason,omitempty"` // ErrorInfo reason, e.g. POOL_EMPTY
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// writeRESTError answers with the HTTP status matching a gRPC status error
+func writeRESTError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	body := restError{Code: connect.Code(st.Code()).String(), Message: st.Message()}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			body.Reason, body.Metadata = info.Reason, info.Metadata
+		}
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(st.Code()))
+	json.NewEncoder(w).Encode(body)
+}
+
+// httpStatus maps gRPC codes to HTTP statuses as grpc-gateway does
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client closed request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// preParamsQuery parses the GetPreParams fields from a query string
+func preParamsQuery(query url.Values) (*pb.GetPreParamsRequest, error) {
+	req := &pb.GetPreParamsRequest{}
+	for name, values := range query {
+		v := values[len(values)-1]
+		var err error
+		switch name {
+		case "count":
+			req.Count, err = parseUint32(v)
+		case "idempotency_key":
+			req.IdempotencyKey = v
+		case "distinct_provenance":
+			req.DistinctProvenance, err = strconv.ParseBool(v)
+		case "no_generate":
+			req.NoGenerate, err = strconv.ParseBool(v)
+		case "allow_imported":
+			req.AllowImported, err = strconv.ParseBool(v)
+		case "distinct_seconds":
+			req.DistinctSeconds, err = strconv.ParseBool(v)
+		case "prime_bit_size":
+			req.PrimeBitSize, err = parseUint32(v)
+		case "paillier_bit_size":
+			req.PaillierBitSize, err = parseUint32(v)
+		case "min_assurance":
+			req.MinAssurance = v
+		default:
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", name)
+		}
+	}
+	return req, nil
+}
+
+// emptyQuery accepts only an empty query string, for methods taking no fields
+func emptyQuery(query url.Values) (*pb.Empty, error) {
+	for name := range query {
+		return nil, fmt.Errorf("unknown parameter %q", name)
+	}
+	return &pb.Empty{}, nil
+}
+
+// parseUint32 parses a decimal uint32
+func parseUint32(v string) (uint32, error) {
+	n, err := strconv.ParseUint(v, 10, 32)
+	return uint32(n), err
+}
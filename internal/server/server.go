@@ -2,35 +2,92 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"time"
 
 	pb "github.com/TEENet-io/prime-service/proto"
+	"github.com/TEENet-io/prime-service/internal/config"
+	"github.com/TEENet-io/prime-service/internal/generator"
 	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/slo"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	"github.com/TEENet-io/prime-service/internal/traffic"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/orca"
 	"google.golang.org/grpc/status"
 )
 
 type Server struct {
 	pb.UnimplementedPrimeServiceServer
-	poolManager *pool.Manager
+	poolManager PoolManager
 	startTime   time.Time
+
+	// Largest GetPreParams response, see checkResponseSize
+	maxResponseBytes int
+
+	// Most items one call may request, see checkCount
+	maxBatchSize int
+
+	// Largest bit size generated on request, see routeBitSizes
+	maxRequestedBits int
+
+	// Anonymized recording of GetPreParams calls (nil disables)
+	traffic *traffic.Recorder
+
+	// Locality reported to clients, see WithLocality
+	region, zone string
 }
 
-func NewServer(poolManager *pool.Manager) *Server {
+func NewServer(poolManager PoolManager) *Server {
 	return &Server{
-		poolManager: poolManager,
-		startTime:   time.Now(),
+		poolManager:      poolManager,
+		startTime:        time.Now(),
+		maxResponseBytes: config.DefaultMaxResponse,
+		maxRequestedBits: config.DefaultMaxRequestedBit,
+		maxBatchSize:     config.DefaultMaxBatchSize,
 	}
 }
 
+// pool returns the pool the request was routed to
+func (s *Server) pool(ctx context.Context) PoolManager {
+	return poolFor(ctx, s.poolManager)
+}
+
 // GetPreParams returns PreParamsData for ECDSA DKG (single or batch)
 func (s *Server) GetPreParams(ctx context.Context, req *pb.GetPreParamsRequest) (*pb.GetPreParamsResponse, error) {
 	start := time.Now()
 
+	// Refuse batches too large for one message before consuming anything
+	if err := s.checkResponseSize(ctx, req); err != nil {
+		return nil, err
+	}
+
+	pbParams, err := s.preParams(ctx, req, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetPreParamsResponse{
+		Params:           pbParams,
+		GenerationTimeMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// preParams validates a request, takes the items from the pool and converts
+// them to protobuf format. If set, onServed receives the items as they are
+// taken or generated; they lead the result in the same order, which may
+// also hold items never passed to onServed (replays, DistinctSeconds). If
+// set, onQueued receives the request's place in the on-demand queue.
+func (s *Server) preParams(ctx context.Context, req *pb.GetPreParamsRequest, onServed func([]*pb.PreParamsData), onQueued func(*pb.QueueStatus)) (result []*pb.PreParamsData, err error) {
+	defer s.recordTraffic(ctx, req, time.Now(), &result, &err)
+
 	// Default to 1 if count not specified
 	count := req.Count
 	if count == 0 {
@@ -38,56 +95,169 @@ func (s *Server) GetPreParams(ctx context.Context, req *pb.GetPreParamsRequest)
 	}
 
 	// Validate count
-	if count > 100 {
-		return nil, status.Errorf(codes.InvalidArgument, "count must be between 1 and 100")
+	if err := s.checkCount(count); err != nil {
+		return nil, err
+	}
+	if !pool.ValidAssurance(req.MinAssurance) {
+		return nil, status.Errorf(codes.InvalidArgument, "min_assurance must be one of %v", pool.AssuranceLevels)
+	}
+
+	// Route requests for other bit sizes to the pool serving them
+	ctx, primeBits, paillierBits, sized, err := s.routeBitSizes(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !sized && onServed == nil {
+		if err := s.checkCapacity(ctx, count); err != nil {
+			return nil, err
+		}
+	}
+
+	namespace, err := namespaceFor(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get parameters from pool manager
-	paramsList, err := s.poolManager.GetPreParams(ctx, count)
+	poolReq := pool.Request{
+		Namespace:          namespace,
+		Client:             apiClientFor(ctx),
+		Count:              count,
+		DistinctProvenance: req.DistinctProvenance,
+		IdempotencyKey:     req.IdempotencyKey,
+		NoGenerate:         req.NoGenerate,
+		AllowImported:      req.AllowImported,
+		DistinctSeconds:    req.DistinctSeconds,
+		MinAssurance:       req.MinAssurance,
+	}
+	var early []*pb.PreParamsData
+	if onServed != nil {
+		poolReq.OnServed = func(items []*pool.ServedParams) {
+			converted := make([]*pb.PreParamsData, len(items))
+			for i, params := range items {
+				converted[i] = toPBServed(params)
+			}
+			early = append(early, converted...)
+			onServed(converted)
+		}
+	}
+	if onQueued != nil {
+		poolReq.OnQueued = func(q pool.QueueStatus) {
+			onQueued(toPBQueue(q))
+		}
+	}
+	var paramsList []*pool.ServedParams
+	if sized {
+		paramsList, err = s.pool(ctx).GetPreParamsAtSize(ctx, poolReq, primeBits, paillierBits)
+	} else {
+		paramsList, err = s.pool(ctx).GetPreParams(ctx, poolReq)
+	}
 	if err != nil {
-		log.Printf("Failed to get pre-params: %v", err)
-		return nil, status.Errorf(codes.Internal, "failed to get pre-params: %v", err)
+		return nil, s.allocationError(ctx, err)
 	}
 
 	// Convert to protobuf format
+	generated := false
 	pbParams := make([]*pb.PreParamsData, len(paramsList))
 	for i, params := range paramsList {
-		pbParams[i] = &pb.PreParamsData{
-			PaillierP:       params.PaillierKey.P.Bytes(),
-			PaillierQ:       params.PaillierKey.Q.Bytes(),
-			PaillierN:       params.PaillierKey.N.Bytes(),
-			PaillierPhiN:    params.PaillierKey.PhiN.Bytes(),
-			PaillierLambdaN: params.PaillierKey.LambdaN.Bytes(),
-			NTildei:         params.NTildei.Bytes(),
-			H1I:             params.H1i.Bytes(),
-			H2I:             params.H2i.Bytes(),
-			Alpha:           params.Alpha.Bytes(),
-			Beta:            params.Beta.Bytes(),
-			P:               params.P.Bytes(),
-			Q:               params.Q.Bytes(),
-			GeneratedAt:     params.GeneratedAt.Unix(),
+		if i < len(early) {
+			pbParams[i] = early[i]
+		} else {
+			pbParams[i] = toPBServed(params)
 		}
+		generated = generated || params.Source == pool.SourceGenerated
 	}
+	slo.SetClass(ctx, servedClass(len(paramsList), count, generated))
+	return pbParams, nil
+}
 
-	return &pb.GetPreParamsResponse{
-		Params:           pbParams,
-		GenerationTimeMs: time.Since(start).Milliseconds(),
-	}, nil
+// allocationError converts a pool allocation error to a status
+func (s *Server) allocationError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, pool.ErrMaintenance):
+		return status.Errorf(codes.Unavailable, "service is in maintenance mode")
+	case errors.Is(err, pool.ErrPoolEmpty):
+		return poolEmptyError()
+	case errors.Is(err, pool.ErrFrozen):
+		f := s.pool(ctx).Freeze()
+		return status.Errorf(codes.Unavailable, "pool is frozen after %s anomaly, awaiting operator unfreeze", f.Anomaly)
+	case errors.Is(err, pool.ErrExceedsCapacity):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, pool.ErrUnknownNamespace):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, pool.ErrQuotaExceeded):
+		return quotaExceededError(err)
+	case errors.Is(err, pool.ErrStopped):
+		return status.Errorf(codes.Unavailable, "service is shutting down")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Errorf(codes.DeadlineExceeded, "deadline exceeded before the parameters were ready; items still being generated go to the pool")
+	case errors.Is(err, context.Canceled):
+		return status.Errorf(codes.Canceled, "request canceled")
+	}
+	trace.Logf(ctx, "Failed to get pre-params: %v", err)
+	return status.Errorf(codes.Internal, "failed to get pre-params: %v", err)
 }
 
+// ErrorInfo details attached to errors clients handle programmatically
+const (
+	errorDomain     = "prime-service"
+	poolEmptyReason = "POOL_EMPTY"
+)
+
+// poolEmptyError tells a no_generate caller to try elsewhere
+func poolEmptyError() error {
+	st := status.New(codes.ResourceExhausted, "POOL_EMPTY: no parameters available and generation was not allowed")
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: poolEmptyReason, Domain: errorDomain}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
 
 func (s *Server) HealthCheck(ctx context.Context, req *pb.Empty) (*pb.HealthStatus, error) {
 	uptime := time.Since(s.startTime).Seconds()
 
+	if s.pool(ctx).InMaintenance() {
+		return &pb.HealthStatus{
+			Healthy:       false,
+			Message:       "Prime service is in maintenance mode",
+			UptimeSeconds: int64(uptime),
+			InstanceId:    s.pool(ctx).InstanceID(),
+			Region:        s.region,
+			Zone:          s.zone,
+		}, nil
+	}
+
+	if f := s.pool(ctx).Freeze(); f.Frozen {
+		return &pb.HealthStatus{
+			Healthy:       false,
+			Message:       fmt.Sprintf("Prime service is frozen after %s anomaly: %s", f.Anomaly, f.Reason),
+			UptimeSeconds: int64(uptime),
+			InstanceId:    s.pool(ctx).InstanceID(),
+			Region:        s.region,
+			Zone:          s.zone,
+		}, nil
+	}
+
+	// Storage inconsistencies are reported but do not fail the check
+	message := "Prime service is running"
+	warnings := s.pool(ctx).Consistency().Issues
+	if len(warnings) > 0 {
+		message = fmt.Sprintf("Prime service is running with %d storage consistency warnings", len(warnings))
+	}
+
 	return &pb.HealthStatus{
-		Healthy:        true,
-		Message:        "Prime service is running",
-		UptimeSeconds:  int64(uptime),
+		Healthy:       true,
+		Message:       message,
+		UptimeSeconds: int64(uptime),
+		InstanceId:    s.pool(ctx).InstanceID(),
+		Warnings:      warnings,
+		Region:        s.region,
+		Zone:          s.zone,
 	}, nil
 }
 
 func (s *Server) GetPoolStatus(ctx context.Context, req *pb.Empty) (*pb.PoolStatus, error) {
-	status := s.poolManager.GetPoolStatus()
+	status := s.pool(ctx).GetPoolStatus()
 
 	// Create pools map
 	pools := make(map[string]*pb.PoolInfo)
@@ -140,24 +310,247 @@ func (s *Server) GetPoolStatus(ctx context.Context, req *pb.Empty) (*pb.PoolStat
 		totalServed = v
 	}
 
+	poolHits, _ := status["pool_hits"].(int64)
+	poolMisses, _ := status["pool_misses"].(int64)
+	missGenTime, _ := status["miss_gen_time"].(time.Duration)
+
+	selectionPolicy, _ := status["selection_policy"].(string)
+	instanceID, _ := status["instance_id"].(string)
+	maintenance, _ := status["maintenance"].(bool)
+	consistency, _ := status["consistency"].(pool.ConsistencyReport)
+	alarms, _ := status["alarms"].([]pool.Alarm)
+	freeze, _ := status["freeze"].(pool.FreezeStatus)
+	version, _ := status["version"].(pool.Version)
+	pbAlarms := make([]*pb.Alarm, len(alarms))
+	for i, a := range alarms {
+		pbAlarms[i] = &pb.Alarm{Name: a.Name, Message: a.Message, Since: a.Since.Unix()}
+	}
+	generationCPUs, _ := status["generation_cpus"].(string)
+	ages, _ := status["age_distribution"].(pool.AgeDistribution)
+	assurance, _ := status["assurance"].(map[string]int)
+	assuranceLevels := make(map[string]uint32, len(assurance))
+	for level, n := range assurance {
+		assuranceLevels[level] = uint32(n)
+	}
+	pinned, _ := status["pinned"].([]pool.PinnedInfo)
+	pbPinned := make([]*pb.PinnedItem, len(pinned))
+	for i, p := range pinned {
+		pbPinned[i] = toPBPinned(p, time.Now())
+	}
+	phases, _ := status["phases"].(map[string]generator.PhaseStatistics)
+	phaseTimings := make([]*pb.PhaseTiming, 0, len(phases))
+	for _, name := range []string{generator.PhasePaillier, generator.PhaseSafePrimes, generator.PhasePedersen} {
+		p, ok := phases[name]
+		if !ok {
+			continue
+		}
+		phaseTimings = append(phaseTimings, &pb.PhaseTiming{
+			Phase:     name,
+			Count:     p.Count,
+			TotalMs:   p.Total.Milliseconds(),
+			AverageMs: p.Average().Milliseconds(),
+			Failures:  p.Failures,
+			FailedMs:  p.FailedTime.Milliseconds(),
+		})
+	}
+
 	return &pb.PoolStatus{
-		Pools:          pools,
-		TotalGenerated: totalGenerated,
-		TotalServed:    totalServed,
-		GenerationRate: 0, // Not calculated in new structure
+		Pools:           pools,
+		TotalGenerated:  totalGenerated,
+		TotalServed:     totalServed,
+		GenerationRate:  0, // Not calculated in new structure
+		SelectionPolicy: selectionPolicy,
+		InstanceId:      instanceID,
+		Maintenance:     maintenance,
+
+		ConsistencyIssues:    uint32(len(consistency.Issues)),
+		AlreadyServedDropped: uint32(consistency.AlreadyServed),
+		Alarms:               pbAlarms,
+		Frozen:               freeze.Frozen,
+		FreezeReason:         freeze.Reason,
+		PoolEpoch:            version.Epoch,
+		PoolVersion:          version.Counter,
+		Pool:                 poolName(ctx),
+		PhaseTimings:         phaseTimings,
+		GenerationCpus:       generationCPUs,
+		Region:               s.region,
+		Zone:                 s.zone,
+		Pinned:               pbPinned,
+		PoolHits:             poolHits,
+		PoolMisses:           poolMisses,
+		MissGenerationMs:     missGenTime.Milliseconds(),
+		AssuranceLevels:      assuranceLevels,
+		ItemAges:             toPBItemAges(ages),
 	}, nil
 }
 
-func StartGRPCServer(addr string, poolManager *pool.Manager) error {
-	lis, err := net.Listen("tcp", addr)
+// newPrimeServer creates the PrimeService implementation shared by the gRPC
+// and web servers
+func newPrimeServer(poolManager PoolManager, o *options) *Server {
+	server := NewServer(poolManager)
+	if o.maxResponseBytes > 0 {
+		server.maxResponseBytes = o.maxResponseBytes
+	}
+	if o.maxRequestedBits > 0 {
+		server.maxRequestedBits = o.maxRequestedBits
+	}
+	if o.maxBatchSize > 0 {
+		server.maxBatchSize = o.maxBatchSize
+	}
+	server.traffic = o.traffic
+	server.region, server.zone = o.region, o.zone
+	return server
+}
+
+// StartGRPCServer serves the gRPC services on every address until a listener fails
+func StartGRPCServer(addrs []string, poolManager PoolManager, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	listeners, err := listenAll(addrs)
 	if err != nil {
-		return fmt.Errorf("failed to listen: %w", err)
+		return err
 	}
 
-	grpcServer := grpc.NewServer()
-	server := NewServer(poolManager)
-	pb.RegisterPrimeServiceServer(grpcServer, server)
+	for _, h := range o.hooks {
+		poolManager.AddHook(h)
+		for _, m := range o.pools {
+			m.AddHook(h)
+		}
+	}
+
+	// Permit client keepalive pings so long-lived clients can detect dead connections
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(traceInterceptor, accessInterceptor(o.accessLog, poolManager), authInterceptor(o.apiKeys), sloInterceptor(o.slo), recoveryInterceptor(poolManager),
+			deadlineInterceptor(o.defaultDeadline, o.maxDeadline), poolInterceptor(o.pools)),
+		grpc.ChainStreamInterceptor(traceStreamInterceptor, accessStreamInterceptor(o.accessLog, poolManager), authStreamInterceptor(o.apiKeys), sloStreamInterceptor(o.slo), recoveryStreamInterceptor(poolManager),
+			deadlineStreamInterceptor(o.defaultDeadline, o.maxDeadline), poolStreamInterceptor(o.pools)),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
+	}
+
+	if o.tls != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(o.tls.serverConfig())))
+	}
+
+	var reporter *loadReporter
+	if o.loadReportInterval > 0 {
+		reporter = newLoadReporter(poolManager)
+		serverOpts = append(serverOpts, orca.CallMetricsServerOption(reporter.recorder))
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	pb.RegisterPrimeServiceServer(grpcServer, newPrimeServer(poolManager, &o))
+	admin := NewAdminServer(poolManager)
+	admin.peers, admin.peerCreds = o.peers, o.dialCredentials()
+	admin.slo = o.slo
+	admin.region, admin.zone = o.region, o.zone
+	admin.workers, admin.auditLog = o.workers, o.auditLog
+	admin.diag = o.diagnostics()
+	pb.RegisterAdminServiceServer(grpcServer, admin)
 
-	log.Printf("Starting gRPC server on %s", addr)
-	return grpcServer.Serve(lis)
-}
\ No newline at end of file
+	if o.workers != nil {
+		pb.RegisterWorkerServiceServer(grpcServer, &WorkerServer{
+			poolManager: poolManager,
+			registry:    o.workers,
+			auditLog:    o.auditLog,
+		})
+		log.Printf("Worker registration enabled")
+	}
+
+	if o.peerToken != "" {
+		pb.RegisterPeerServiceServer(grpcServer, &PeerServer{
+			poolManager: poolManager,
+			token:       o.peerToken,
+			auditLog:    o.auditLog,
+		})
+
+		if len(o.peers) > 0 {
+			puller, err := newPeerPuller(poolManager, &o)
+			if err != nil {
+				return err
+			}
+			stop := make(chan struct{})
+			defer close(stop)
+			go puller.run(o.peerSyncInterval, stop)
+			log.Printf("Peer pool sharing enabled (peers: %v, interval: %s)", o.peers, o.peerSyncInterval)
+		}
+	}
+
+	if o.bootstrapUpstream != "" && (poolManager.FirstBoot() || o.proxyInterval > 0) {
+		up, err := newUpstream(poolManager, &o)
+		if err != nil {
+			return err
+		}
+		stop := make(chan struct{})
+		defer close(stop)
+		go up.run(&o, stop)
+		if o.proxyInterval > 0 {
+			log.Printf("Proxying upstream %s (interval: %s, max pull: %d)", o.bootstrapUpstream, o.proxyInterval, o.proxyMaxPull)
+		}
+	}
+
+	if reporter != nil {
+		if err := orca.Register(grpcServer, orca.ServiceOptions{ServerMetricsProvider: reporter.recorder}); err != nil {
+			return fmt.Errorf("failed to register ORCA service: %w", err)
+		}
+		stop := make(chan struct{})
+		defer close(stop)
+		go reporter.run(o.loadReportInterval, stop)
+		log.Printf("ORCA load reporting enabled (interval: %s)", o.loadReportInterval)
+	}
+
+	// Serve on every listener; the first failure stops the server
+	errCh := make(chan error, len(listeners))
+	for _, lis := range listeners {
+		log.Printf("Starting gRPC server on %s", lis.Addr())
+		go func(lis net.Listener) {
+			errCh <- grpcServer.Serve(o.wrapListener(lis))
+		}(lis)
+	}
+	if len(o.proxyTrusted) > 0 {
+		log.Printf("PROXY protocol enabled for connections from %v", o.proxyTrusted)
+	}
+	err = <-errCh
+	grpcServer.Stop()
+	return err
+}
+
+// listenAll opens a listener for each address, closing them all on failure
+func listenAll(addrs []string) ([]net.Listener, error) {
+	var listeners []net.Listener
+	for _, addr := range addrs {
+		lis, err := net.Listen(listenNetwork(addr), addr)
+		if err != nil {
+			for _, l := range listeners {
+				l.Close()
+			}
+			return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		listeners = append(listeners, lis)
+	}
+	return listeners, nil
+}
+
+// listenNetwork pins literal IPv4 and IPv6 hosts to their address family, so
+// "0.0.0.0:port" and "[::]:port" can be bound side by side; host names and an
+// empty host use the default dual-stack listener
+func listenNetwork(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "tcp"
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return "tcp"
+	case ip.To4() != nil:
+		return "tcp4"
+	default:
+		return "tcp6"
+	}
+}
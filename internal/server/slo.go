@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/slo"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sloInterceptor times every call for the latency objectives
+func sloInterceptor(t *slo.Tracker) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if t == nil {
+			return handler(ctx, req)
+		}
+		start := time.Now()
+		ctx, class := slo.WithCall(ctx)
+		resp, err := handler(ctx, req)
+		t.Record(path.Base(info.FullMethod), class(), time.Since(start), serverFault(err))
+		return resp, err
+	}
+}
+
+// sloStreamInterceptor is sloInterceptor for streaming RPCs, timing the
+// whole stream
+func sloStreamInterceptor(t *slo.Tracker) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if t == nil {
+			return handler(srv, ss)
+		}
+		start := time.Now()
+		ctx, class := slo.WithCall(ss.Context())
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		t.Record(path.Base(info.FullMethod), class(), time.Since(start), serverFault(err))
+		return err
+	}
+}
+
+// serverFault reports whether err is the service's fault and so counts
+// against the objectives. Refusals by design (maintenance, freeze, empty
+// pool for no_generate, invalid requests) do not.
+func serverFault(err error) bool {
+	switch status.Code(err) {
+	case codes.Internal, codes.Unknown, codes.DeadlineExceeded, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// servedClass classifies a GetPreParams allocation for class-specific objectives
+func servedClass(served int, count uint32, generated bool) string {
+	switch {
+	case generated:
+		return slo.ClassGenerated
+	case served < int(count):
+		return slo.ClassPoolMiss
+	}
+	return slo.ClassPoolHit
+}
+
+// GetSLOStatus reports the latency objectives with compliance and burn rates
+func (a *AdminServer) GetSLOStatus(ctx context.Context, req *pb.Empty) (*pb.SLOStatus, error) {
+	resp := &pb.SLOStatus{}
+	if a.slo == nil {
+		return resp, nil
+	}
+	for _, s := range a.slo.Status() {
+		resp.Objectives = append(resp.Objectives, &pb.SLOObjectiveStatus{
+			Name:            s.Name,
+			Method:          s.Method,
+			Class:           s.Class,
+			ThresholdMs:     s.ThresholdMs,
+			Target:          s.Target,
+			WindowSeconds:   s.WindowSeconds,
+			Total:           s.Total,
+			Good:            s.Good,
+			Compliance:      s.Compliance,
+			BudgetRemaining: s.BudgetRemaining,
+			BurnRates:       s.BurnRates,
+			Met:             s.Met,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListSnapshots lists the pool snapshots in the snapshot directory
+func (a *AdminServer) ListSnapshots(ctx context.Context, _ *pb.Empty) (*pb.SnapshotList, error) {
+	m := a.pool(ctx)
+	snaps, err := m.ListSnapshots()
+	if err != nil {
+		return nil, snapshotError(err)
+	}
+	st := m.SnapshotStatus()
+	resp := &pb.SnapshotList{Dir: st.Dir, Failures: st.Failures, LastError: st.LastError}
+	if !st.Last.IsZero() {
+		resp.LastSnapshotAt = st.Last.Unix()
+	}
+	for _, snap := range snaps {
+		resp.Snapshots = append(resp.Snapshots, toPBSnapshot(snap))
+	}
+	return resp, nil
+}
+
+// TakeSnapshot takes a pool snapshot outside the schedule
+func (a *AdminServer) TakeSnapshot(ctx context.Context, _ *pb.Empty) (*pb.SnapshotInfo, error) {
+	snap, err := a.pool(ctx).TakeSnapshot(ctx)
+	if err != nil && !errors.Is(err, pool.ErrSnapshotCommand) {
+		return nil, snapshotError(err)
+	}
+
+	resp := toPBSnapshot(snap)
+	if err != nil {
+		resp.CommandError = err.Error()
+	}
+	a.audit(ctx, audit.Entry{Event: "admin_snapshot", Remote: remoteAddr(ctx), Detail: snap.Name, TraceID: trace.ID(ctx)})
+	return resp, nil
+}
+
+// RestoreSnapshot adds the items of a snapshot back to the pool
+func (a *AdminServer) RestoreSnapshot(ctx context.Context, req *pb.RestoreSnapshotRequest) (*pb.RestoreSnapshotResponse, error) {
+	m := a.pool(ctx)
+	res, err := m.RestoreSnapshot(ctx, req.Name)
+	if err != nil {
+		return nil, snapshotError(err)
+	}
+
+	a.audit(ctx, audit.Entry{Event: "admin_restore_snapshot", Remote: remoteAddr(ctx), Count: res.Restored, TraceID: trace.ID(ctx),
+		Detail: fmt.Sprintf("snapshot %s, already served %d, in pool %d, skipped %d", req.Name, res.AlreadyServed, res.InPool, res.Skipped)})
+	return &pb.RestoreSnapshotResponse{
+		Restored:      uint32(res.Restored),
+		AlreadyServed: uint32(res.AlreadyServed),
+		InPool:        uint32(res.InPool),
+		Skipped:       uint32(res.Skipped),
+		PoolSize:      uint32(m.Size()),
+	}, nil
+}
+
+// snapshotError maps snapshot errors to gRPC status codes
+func snapshotError(err error) error {
+	switch {
+	case errors.Is(err, pool.ErrSnapshotsDisabled):
+		return status.Error(codes.FailedPrecondition, "pool snapshots need pool.snapshot_dir")
+	case errors.Is(err, pool.ErrSnapshotNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, pool.ErrSnapshotUncovered):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return err
+}
+
+// toPBSnapshot converts a snapshot to its protobuf representation
+func toPBSnapshot(snap pool.Snapshot) *pb.SnapshotInfo {
+	return &pb.SnapshotInfo{Name: snap.Name, TakenAt: snap.TakenAt.Unix(), Bytes: uint64(snap.Bytes)}
+}
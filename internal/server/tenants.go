@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NamespaceHeader names the tenant namespace a call's items are charged
+// to; calls without it are charged to the default namespace. API keys bound
+// to a namespace are always charged to theirs.
+const NamespaceHeader = "x-prime-namespace"
+
+// quotaExceededReason tells clients their namespace is out of quota
+const quotaExceededReason = "QUOTA_EXCEEDED"
+
+type keyNamespaceKey struct{}
+
+// withKeyNamespace records the namespace the caller's API key is bound to
+func withKeyNamespace(ctx context.Context, namespace string) context.Context {
+	if namespace == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, keyNamespaceKey{}, namespace)
+}
+
+// namespaceFor returns the namespace a call is charged to, refusing calls
+// naming another namespace than the one their API key is bound to
+func namespaceFor(ctx context.Context) (string, error) {
+	named := metadataValue(ctx, NamespaceHeader)
+	bound, _ := ctx.Value(keyNamespaceKey{}).(string)
+	if bound == "" {
+		return named, nil
+	}
+	if named != "" && named != bound {
+		return "", status.Errorf(codes.PermissionDenied, "API key is bound to namespace %s, not %s", bound, named)
+	}
+	return bound, nil
+}
+
+// quotaExceededError tells a caller its namespace is out of quota. It is
+// FailedPrecondition rather than ResourceExhausted, which clients retry:
+// retrying cannot help before the quota window rolls over.
+func quotaExceededError(err error) error {
+	st := status.New(codes.FailedPrecondition, err.Error())
+	if detailed, derr := st.WithDetails(&errdetails.ErrorInfo{Reason: quotaExceededReason, Domain: errorDomain}); derr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
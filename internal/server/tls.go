@@ -0,0 +1,161 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// TLS is the transport security of the gRPC and web listeners, see LoadTLS
+type TLS struct {
+	cert              *certReloader
+	clientCAs         *x509.CertPool // nil: client certificates are not verified
+	requireClientCert bool
+}
+
+// LoadTLS loads the server certificate and client CA bundle configured in
+// cfg, returning nil if TLS is disabled
+func LoadTLS(cfg config.TLSConfig) (*TLS, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+
+	cert := &certReloader{certFile: cfg.CertFile, keyFile: cfg.KeyFile}
+	if err := cert.load(); err != nil {
+		return nil, err
+	}
+	t := &TLS{cert: cert, requireClientCert: cfg.RequireClientCert}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA bundle: %w", err)
+		}
+		t.clientCAs = x509.NewCertPool()
+		if !t.clientCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA bundle %s", cfg.ClientCAFile)
+		}
+	}
+	return t, nil
+}
+
+// serverConfig returns the TLS configuration of the listeners
+func (t *TLS) serverConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: t.cert.getCertificate,
+	}
+	if t.clientCAs != nil {
+		cfg.ClientCAs = t.clientCAs
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		if t.requireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return cfg
+}
+
+// dialConfig returns the TLS configuration for calls to peer replicas,
+// which are assumed to share the deployment's certificate authority: the
+// client CA bundle (or the system roots) verifies them, and the server
+// certificate authenticates this instance to them
+func (t *TLS) dialConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    t.clientCAs,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return t.cert.getCertificate(nil)
+		},
+	}
+}
+
+// WithTLS serves the gRPC and web listeners over TLS and dials peer
+// replicas with TLS (nil: plaintext)
+func WithTLS(t *TLS) Option {
+	return func(o *options) {
+		o.tls = t
+	}
+}
+
+// dialCredentials returns the transport credentials for calls to peer
+// replicas
+func (o *options) dialCredentials() grpc.DialOption {
+	if o.tls == nil {
+		return grpc.WithTransportCredentials(insecure.NewCredentials())
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(o.tls.dialConfig()))
+}
+
+// certReloadInterval is how often the certificate files are checked for
+// changes, at most
+const certReloadInterval = 30 * time.Second
+
+// certReloader serves a certificate, rereading its files when they change
+// so rotated certificates take effect without a restart
+type certReloader struct {
+	certFile, keyFile string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time // Of the newer of both files when loaded
+	checked time.Time
+}
+
+// load reads the certificate and key
+func (r *certReloader) load() error {
+	modTime, err := r.filesModTime()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	r.cert, r.modTime, r.checked = &cert, modTime, time.Now()
+	return nil
+}
+
+// filesModTime returns the modification time of the newer of both files
+func (r *certReloader) filesModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to read TLS certificate: %w", err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// getCertificate returns the current certificate, reloading it if its
+// files changed. A broken replacement keeps the previous certificate.
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checked) < certReloadInterval {
+		return r.cert, nil
+	}
+	r.checked = time.Now()
+	if modTime, err := r.filesModTime(); err != nil || !modTime.After(r.modTime) {
+		return r.cert, nil
+	}
+	if err := r.load(); err != nil {
+		log.Printf("Keeping the previous TLS certificate: %v", err)
+		return r.cert, nil
+	}
+	log.Printf("Reloaded TLS certificate from %s", r.certFile)
+	return r.cert, nil
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+
+	"github.com/TEENet-io/prime-service/internal/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// maxTraceIDLength bounds caller-supplied trace IDs
+const maxTraceIDLength = 64
+
+// traceInterceptor attaches the caller's trace ID (or a new one) to the
+// request context and echoes it in the response header
+func traceInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(trace.Header); len(values) > 0 && validTraceID(values[0]) {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = trace.NewID()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(trace.Header, id))
+	return handler(trace.WithID(ctx, id), req)
+}
+
+// traceStreamInterceptor is traceInterceptor for streaming RPCs
+func traceStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ss.Context()); ok {
+		if values := md.Get(trace.Header); len(values) > 0 && validTraceID(values[0]) {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = trace.NewID()
+	}
+
+	ss.SetHeader(metadata.Pairs(trace.Header, id))
+	return handler(srv, &contextStream{ServerStream: ss, ctx: trace.WithID(ss.Context(), id)})
+}
+
+// contextStream replaces the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// validTraceID accepts short IDs of safe characters, so caller-supplied IDs
+// cannot inject content into log lines
+func validTraceID(id string) bool {
+	if id == "" || len(id) > maxTraceIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
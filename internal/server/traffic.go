@@ -0,0 +1,48 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/trace"
+	"github.com/TEENet-io/prime-service/internal/traffic"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// recordTraffic appends the outcome of a call started at start to the
+// traffic recording, if enabled
+func (s *Server) recordTraffic(ctx context.Context, req *pb.GetPreParamsRequest, start time.Time, result *[]*pb.PreParamsData, err *error) {
+	if s.traffic == nil {
+		return
+	}
+
+	st := status.Convert(*err)
+	r := traffic.Record{
+		Time:       start,
+		Pool:       poolName(ctx),
+		Count:      max(req.Count, 1),
+		Distinct:   req.DistinctProvenance,
+		Seconds:    req.DistinctSeconds,
+		NoGenerate: req.NoGenerate,
+		Served:     len(*result),
+		Code:       st.Code().String(),
+		LatencyMs:  time.Since(start).Milliseconds(),
+		PoolSize:   s.pool(ctx).Size(),
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			r.Reason = info.Reason
+		}
+	}
+	for _, p := range *result {
+		if p.Metadata.GetSource() == pb.ItemSource_ITEM_SOURCE_GENERATED {
+			r.Generated++
+		}
+	}
+
+	if err := s.traffic.Record(r); err != nil {
+		trace.Logf(ctx, "Failed to record traffic: %v", err)
+	}
+}
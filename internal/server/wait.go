@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/slo"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// WaitForPreParams waits until the pool can serve the whole request and
+// takes the items at once, so clients need no poll and backoff loop of
+// their own. The wait is bounded by timeout_ms and the call deadline.
+func (s *Server) WaitForPreParams(ctx context.Context, req *pb.WaitForPreParamsRequest) (*pb.GetPreParamsResponse, error) {
+	start := time.Now()
+
+	count := req.Count
+	if count == 0 {
+		count = 1
+	}
+	if err := s.checkCount(count); err != nil {
+		return nil, err
+	}
+	// The pool must hold the whole batch at once
+	if err := s.checkCapacity(ctx, count); err != nil {
+		return nil, err
+	}
+	if !pool.ValidAssurance(req.MinAssurance) {
+		return nil, status.Errorf(codes.InvalidArgument, "min_assurance must be one of %v", pool.AssuranceLevels)
+	}
+
+	// Streaming is not offered for waits, so batches too large for one
+	// message are refused up front
+	if err := s.checkResponseSize(ctx, &pb.GetPreParamsRequest{Count: count}); err != nil {
+		return nil, err
+	}
+
+	namespace, err := namespaceFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paramsList, err := s.pool(ctx).WaitForPreParams(ctx, pool.Request{
+		Namespace:          namespace,
+		Client:             apiClientFor(ctx),
+		Count:              count,
+		DistinctProvenance: req.DistinctProvenance,
+		IdempotencyKey:     req.IdempotencyKey,
+		NoGenerate:         true,
+		AllowImported:      req.AllowImported,
+		DistinctSeconds:    req.DistinctSeconds,
+		MinAssurance:       req.MinAssurance,
+	}, time.Duration(req.TimeoutMs)*time.Millisecond)
+	if err != nil {
+		return nil, s.allocationError(ctx, err)
+	}
+
+	pbParams := make([]*pb.PreParamsData, len(paramsList))
+	for i, params := range paramsList {
+		pbParams[i] = toPBServed(params)
+	}
+	slo.SetClass(ctx, servedClass(len(paramsList), count, false))
+
+	return &pb.GetPreParamsResponse{
+		Params:           pbParams,
+		GenerationTimeMs: time.Since(start).Milliseconds(),
+	}, nil
+}
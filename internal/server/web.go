@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Headers browsers may send to and read from the web server, besides the
+// CORS-safelisted ones
+var (
+	webRequestHeaders = []string{
+		"Content-Type", "Connect-Protocol-Version", "Connect-Timeout-Ms",
+		"X-Grpc-Web", "X-User-Agent", "Grpc-Timeout", trace.Header, PoolHeader, NamespaceHeader, APIKeyHeader,
+	}
+	webResponseHeaders = []string{
+		trace.Header, "Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin",
+	}
+)
+
+// StartWebServer serves the unary PrimeService RPCs (GetPreParams,
+// WaitForPreParams, HealthCheck, GetPoolStatus) with the Connect and
+// gRPC-Web protocols over HTTP/1.1, and with gRPC over cleartext HTTP/2, for
+// browsers, WASM clients, dashboards and curl scripts that cannot speak gRPC,
+// plus a plain REST gateway under /v1 (see registerREST). HealthCheck and
+// GetPoolStatus have no side effects and also answer Connect GET requests:
+//
+//	curl 'http://host:port/prime.PrimeService/GetPoolStatus?encoding=json&message={}'
+//
+// Calls pass the same tracing, access log, API key, SLO, panic recovery, deadline and
+// pool selection interceptors as over gRPC. Browsers on other origins are only admitted if listed with
+// WithAllowedOrigins.
+func StartWebServer(addr string, poolManager PoolManager, opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	server := newPrimeServer(poolManager, &o)
+	interceptors := []grpc.UnaryServerInterceptor{traceInterceptor, accessInterceptor(o.accessLog, poolManager), authInterceptor(o.apiKeys), sloInterceptor(o.slo), recoveryInterceptor(poolManager),
+		deadlineInterceptor(o.defaultDeadline, o.maxDeadline), poolInterceptor(o.pools)}
+
+	mux := http.NewServeMux()
+	mux.Handle(webUnary(pb.PrimeService_GetPreParams_FullMethodName, interceptors, server.GetPreParams))
+	mux.Handle(webUnary(pb.PrimeService_WaitForPreParams_FullMethodName, interceptors, server.WaitForPreParams))
+	readOnly := connect.WithIdempotency(connect.IdempotencyNoSideEffects)
+	mux.Handle(webUnary(pb.PrimeService_HealthCheck_FullMethodName, interceptors, server.HealthCheck, readOnly))
+	mux.Handle(webUnary(pb.PrimeService_GetPoolStatus_FullMethodName, interceptors, server.GetPoolStatus, readOnly))
+	registerREST(mux, server, interceptors)
+
+	// Cleartext HTTP/2 (h2c) lets gRPC clients such as grpcurl in as well;
+	// over TLS, HTTP/2 is negotiated
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(o.tls == nil)
+	protocols.SetHTTP2(o.tls != nil)
+	httpServer := &http.Server{
+		Addr:      addr,
+		Handler:   withCORS(mux, o.allowedOrigins),
+		Protocols: &protocols,
+	}
+
+	lis, err := net.Listen(listenNetwork(addr), addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	log.Printf("Starting web server on %s", addr)
+	if o.tls != nil {
+		httpServer.TLSConfig = o.tls.serverConfig()
+		return httpServer.ServeTLS(o.wrapListener(lis), "", "")
+	}
+	return httpServer.Serve(o.wrapListener(lis))
+}
+
+// webUnary adapts a gRPC unary method to a Connect handler that runs the
+// gRPC interceptors, returning the path to register it on and the handler
+func webUnary[Req, Res any](fullMethod string, interceptors []grpc.UnaryServerInterceptor, fn func(context.Context, *Req) (*Res, error), handlerOpts ...connect.HandlerOption) (string, http.Handler) {
+	return fullMethod, connect.NewUnaryHandler(fullMethod, func(ctx context.Context, req *connect.Request[Req]) (*connect.Response[Res], error) {
+		resp, traceID, err := runUnary(ctx, req.Header(), req.Peer().Addr, fullMethod, interceptors, fn, req.Msg)
+		if err != nil {
+			cerr := connectError(err)
+			if traceID != "" {
+				cerr.Meta().Set(trace.Header, traceID)
+			}
+			return nil, cerr
+		}
+		res := connect.NewResponse(resp)
+		res.Header().Set(trace.Header, traceID)
+		return res, nil
+	}, handlerOpts...)
+}
+
+// runUnary calls fn through the gRPC interceptors for an HTTP request,
+// returning its response and trace ID
+func runUnary[Req, Res any](ctx context.Context, header http.Header, peerAddr, fullMethod string, interceptors []grpc.UnaryServerInterceptor, fn func(context.Context, *Req) (*Res, error), req *Req) (*Res, string, error) {
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+
+	// Request headers become incoming metadata, as gRPC would deliver them
+	md := metadata.MD{}
+	for key, values := range header {
+		md.Append(strings.ToLower(key), values...)
+	}
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	// The caller's address, as gRPC would report it for audit entries
+	if addr, err := netip.ParseAddrPort(peerAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: net.TCPAddrFromAddrPort(addr)})
+	}
+
+	var traceID string
+	handler := func(ctx context.Context, msg interface{}) (interface{}, error) {
+		traceID = trace.ID(ctx)
+		return fn(ctx, msg.(*Req))
+	}
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, msg interface{}) (interface{}, error) {
+			return interceptor(ctx, msg, info, next)
+		}
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, traceID, err
+	}
+	return resp.(*Res), traceID, nil
+}
+
+// connectError converts a gRPC status error, keeping its code, message and
+// details (e.g. the ErrorInfo reason POOL_EMPTY)
+func connectError(err error) *connect.Error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return connect.NewError(connect.CodeUnknown, err)
+	}
+	cerr := connect.NewError(connect.Code(st.Code()), errors.New(st.Message()))
+	for _, d := range st.Details() {
+		msg, ok := d.(proto.Message)
+		if !ok {
+			continue
+		}
+		if detail, err := connect.NewErrorDetail(msg); err == nil {
+			cerr.AddDetail(detail)
+		}
+	}
+	return cerr
+}
+
+// withCORS admits cross-origin requests from the allowed origins ("*": any)
+func withCORS(next http.Handler, allowedOrigins []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !slices.Contains(allowedOrigins, "*") && !slices.Contains(allowedOrigins, origin) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		h := w.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Add("Vary", "Origin")
+		h.Set("Access-Control-Expose-Headers", strings.Join(webResponseHeaders, ", "))
+		if r.Method == http.MethodOptions {
+			h.Set("Access-Control-Allow-Methods", "POST, GET")
+			h.Set("Access-Control-Allow-Headers", strings.Join(webRequestHeaders, ", "))
+			h.Set("Access-Control-Max-Age", "7200")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TEENet-io/prime-service/internal/audit"
+	"github.com/TEENet-io/prime-service/internal/errjournal"
+	"github.com/TEENet-io/prime-service/internal/pool"
+	"github.com/TEENet-io/prime-service/internal/trace"
+	"github.com/TEENet-io/prime-service/internal/workerauth"
+	pb "github.com/TEENet-io/prime-service/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Metadata headers of the worker registration flow
+const (
+	workerBootstrapHeader = "x-worker-bootstrap-token"
+	workerTokenHeader     = "x-worker-token"
+)
+
+// maxSubmission bounds the items of one SubmitPreParams call
+const maxSubmission = 100
+
+// WorkerServer accepts parameters from registered generate-only workers
+type WorkerServer struct {
+	pb.UnimplementedWorkerServiceServer
+	poolManager PoolManager
+	registry    *workerauth.Registry
+	auditLog    *audit.Logger
+}
+
+// RegisterWorker issues a short-lived token to a worker presenting the
+// bootstrap token or its current token
+func (w *WorkerServer) RegisterWorker(ctx context.Context, req *pb.RegisterWorkerRequest) (*pb.WorkerToken, error) {
+	credential, renewal := metadataValue(ctx, workerTokenHeader), true
+	if credential == "" {
+		credential, renewal = metadataValue(ctx, workerBootstrapHeader), false
+	}
+
+	token, expires, err := w.registry.Register(req.WorkerId, credential, remoteAddr(ctx))
+	switch {
+	case errors.Is(err, workerauth.ErrInvalidCredential):
+		return nil, status.Errorf(codes.Unauthenticated, "invalid bootstrap or worker token")
+	case errors.Is(err, workerauth.ErrRevoked):
+		w.audit(ctx, "worker_refused", req.WorkerId, 0, nil, "registration of a revoked worker")
+		return nil, status.Errorf(codes.PermissionDenied, "worker %s is revoked", req.WorkerId)
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if !renewal {
+		trace.Logf(ctx, "Worker %s registered from %s", req.WorkerId, remoteAddr(ctx))
+		w.audit(ctx, "worker_registered", req.WorkerId, 0, nil, "")
+	}
+	return &pb.WorkerToken{Token: token, ExpiresAt: expires.Unix()}, nil
+}
+
+// SubmitPreParams verifies the items of an authenticated worker and adds
+// them to the pool
+func (w *WorkerServer) SubmitPreParams(ctx context.Context, req *pb.SubmitPreParamsRequest) (*pb.SubmitPreParamsResponse, error) {
+	id, err := w.registry.Authenticate(metadataValue(ctx, workerTokenHeader))
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid or expired worker token, register again")
+	}
+	if len(req.Params) > maxSubmission {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d items per call", maxSubmission)
+	}
+
+	// The authenticated worker is the generating instance, whatever the items claim
+	items := make([]*pool.PreParamsData, len(req.Params))
+	for i, params := range req.Params {
+		items[i] = fromPBParams(params)
+		items[i].Provenance.Instance = id
+		items[i].Provenance.Imported = false
+	}
+	m := poolFor(ctx, w.poolManager)
+	accepted := m.AddWorkerPreParams(ctx, items)
+	w.registry.RecordSubmitted(id, accepted)
+
+	trace.Logf(ctx, "Received %d parameters from worker %s (accepted: %d)", len(items), id, accepted)
+	w.audit(ctx, "worker_submit", id, accepted, acceptedSeqs(items), fmt.Sprintf("received %d", len(items)))
+	return &pb.SubmitPreParamsResponse{Accepted: uint32(accepted), PoolSize: uint32(m.Size())}, nil
+}
+
+// audit records a worker event, reporting audit failures as anomalies
+func (w *WorkerServer) audit(ctx context.Context, event, worker string, count int, items []uint64, detail string) {
+	if err := w.auditLog.Record(audit.Entry{Event: event, Peer: worker, Remote: remoteAddr(ctx), Count: count, Items: items, Detail: detail, TraceID: trace.ID(ctx)}); err != nil {
+		trace.Logf(ctx, "Failed to record audit entry: %v", err)
+		w.poolManager.ReportAnomaly(pool.AnomalyAudit, fmt.Errorf("failed to record audit entry: %w", err))
+	}
+}
+
+// ListWorkers reports the registered and revoked workers
+func (a *AdminServer) ListWorkers(ctx context.Context, req *pb.Empty) (*pb.WorkerList, error) {
+	if a.workers == nil {
+		return nil, errWorkersDisabled
+	}
+	workers := a.workers.Workers()
+	resp := &pb.WorkerList{Workers: make([]*pb.WorkerInfo, len(workers))}
+	for i, w := range workers {
+		resp.Workers[i] = toPBWorker(w)
+	}
+	return resp, nil
+}
+
+// RevokeWorker revokes or reinstates a worker
+func (a *AdminServer) RevokeWorker(ctx context.Context, req *pb.RevokeWorkerRequest) (*pb.WorkerInfo, error) {
+	if a.workers == nil {
+		return nil, errWorkersDisabled
+	}
+
+	var w workerauth.Worker
+	var err error
+	if req.Reinstate {
+		w, err = a.workers.Reinstate(req.WorkerId)
+	} else {
+		w, err = a.workers.Revoke(req.WorkerId)
+	}
+	if errors.Is(err, workerauth.ErrUnknownWorker) {
+		return nil, status.Errorf(codes.NotFound, "unknown worker %s", req.WorkerId)
+	}
+	if err != nil {
+		// The revocation is in effect even if it could not be persisted
+		trace.Logf(ctx, "Failed to persist worker revocation: %v", err)
+		a.poolManager.Errors().Record(errjournal.SeverityError, "persistence", err, map[string]string{"op": "worker_revocation"})
+	}
+
+	event, action := "worker_revoked", "revoked"
+	if req.Reinstate {
+		event, action = "worker_reinstated", "reinstated"
+	}
+	trace.Logf(ctx, "Worker %s %s by admin request", w.ID, action)
+	if err := a.auditLog.Record(audit.Entry{Event: event, Peer: w.ID, Remote: remoteAddr(ctx), TraceID: trace.ID(ctx)}); err != nil {
+		trace.Logf(ctx, "Failed to record audit entry: %v", err)
+		a.poolManager.ReportAnomaly(pool.AnomalyAudit, fmt.Errorf("failed to record audit entry: %w", err))
+	}
+	return toPBWorker(w), nil
+}
+
+// errWorkersDisabled answers worker admin calls without a bootstrap token
+var errWorkersDisabled = status.Error(codes.FailedPrecondition, "worker registration is not enabled (set worker.bootstrap_token)")
+
+// toPBWorker converts a worker to protobuf format
+func toPBWorker(w workerauth.Worker) *pb.WorkerInfo {
+	info := &pb.WorkerInfo{
+		WorkerId:  w.ID,
+		Address:   w.Address,
+		Submitted: w.Submitted,
+		Revoked:   w.Revoked,
+	}
+	if !w.Registered.IsZero() {
+		info.Registered = w.Registered.Unix()
+		info.ExpiresAt = w.Expires.Unix()
+	}
+	if !w.RevokedAt.IsZero() {
+		info.RevokedAt = w.RevokedAt.Unix()
+	}
+	return info
+}
+
+// metadataValue returns the single value of an incoming metadata header
+func metadataValue(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) == 1 {
+		return values[0]
+	}
+	return ""
+}
+
+// remoteAddr returns the caller's address
+func remoteAddr(ctx context.Context) string {
+	if pr, ok := peer.FromContext(ctx); ok {
+		return pr.Addr.String()
+	}
+	return "unknown"
+}
@@ -0,0 +1,85 @@
+// Package shred overwrites file contents before their storage is released.
+// Overwriting only reaches the original blocks on filesystems that update
+// in place: copy-on-write filesystems (btrfs, ZFS), snapshots, log-structured
+// storage and SSD wear leveling may keep old copies, so key material at rest
+// still needs encrypted storage.
+package shred
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNotOverwritten is wrapped by Replace when the new contents were written
+// but the replaced ones could not be overwritten
+var ErrNotOverwritten = errors.New("replaced contents not overwritten")
+
+// chunkSize is the size of the zero writes
+const chunkSize = 64 << 10
+
+// Replace atomically replaces path with data (temporary file and rename).
+// With overwrite set, the replaced contents are then overwritten with zeros
+// through a descriptor held across the rename.
+func Replace(path string, data []byte, perm os.FileMode, overwrite bool) error {
+	var old *os.File
+	if overwrite {
+		if f, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+			old = f
+			defer old.Close()
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	if old != nil {
+		if err := zero(old); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrNotOverwritten, path, err)
+		}
+	}
+	return nil
+}
+
+// File overwrites the file at path with zeros and removes it
+func File(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	err = zero(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to overwrite %s: %w", path, err)
+	}
+	return os.Remove(path)
+}
+
+// Overwrite overwrites the contents of an open file with zeros, e.g. one
+// whose name was just replaced by a rename
+func Overwrite(f *os.File) error {
+	return zero(f)
+}
+
+// zero overwrites the contents of f with zeros and syncs them to storage
+func zero(f *os.File) error {
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, chunkSize)
+	for off := int64(0); off < info.Size(); off += chunkSize {
+		n := min(int64(chunkSize), info.Size()-off)
+		if _, err := f.WriteAt(buf[:n], off); err != nil {
+			return err
+		}
+	}
+	return f.Sync()
+}
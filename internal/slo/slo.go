@@ -0,0 +1,189 @@
+// Package slo tracks per-RPC latency objectives and their error budgets
+package slo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TEENet-io/prime-service/internal/config"
+)
+
+// Call classes for GetPreParams objectives
+const (
+	ClassPoolHit   = "pool_hit"  // Every item came from the pool
+	ClassGenerated = "generated" // At least one item was generated on demand
+	ClassPoolMiss  = "pool_miss" // Fewer items than requested, none generated
+)
+
+// bucketSize is the granularity calls are counted at
+const bucketSize = time.Minute
+
+// BurnWindows are the lookbacks burn rates are reported for: short ones
+// catch fast burns, long ones slow leaks
+var BurnWindows = []struct {
+	Name   string
+	Window time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+	{"6h", 6 * time.Hour},
+}
+
+// Status is the state of an objective over its compliance window
+type Status struct {
+	Name          string  `json:"name"`
+	Method        string  `json:"method"`
+	Class         string  `json:"class,omitempty"`
+	ThresholdMs   int64   `json:"threshold_ms"`
+	Target        float64 `json:"target"`
+	WindowSeconds int64   `json:"window_seconds"`
+
+	Total      int64   `json:"total"`
+	Good       int64   `json:"good"`       // Completed within the threshold
+	Compliance float64 `json:"compliance"` // Good / Total (1 without traffic)
+
+	// BudgetRemaining is the share of the error budget (1 - Target of the
+	// calls) left in the window; negative once the objective is missed
+	BudgetRemaining float64 `json:"budget_remaining"`
+
+	// BurnRates is the budget spending rate per lookback in BurnWindows: 1
+	// spends the budget exactly over the window, 14.4 over 1h burns 2% of a
+	// 30-day budget
+	BurnRates map[string]float64 `json:"burn_rates"`
+
+	Met bool `json:"met"`
+}
+
+// Tracker counts good and total calls per objective in one-minute buckets
+type Tracker struct {
+	window     time.Duration
+	objectives []*objective
+}
+
+// objective is a configured objective and its ring of buckets
+type objective struct {
+	config.SLOObjective
+
+	mu      sync.Mutex
+	buckets []bucket
+}
+
+type bucket struct {
+	minute      int64 // Unix minute the counts belong to
+	total, good int64
+}
+
+// New creates a tracker for cfg
+func New(cfg config.SLOConfig) *Tracker {
+	n := int(cfg.Window/bucketSize) + 1
+	t := &Tracker{window: cfg.Window}
+	for _, o := range cfg.Objectives {
+		t.objectives = append(t.objectives, &objective{SLOObjective: o, buckets: make([]bucket, n)})
+	}
+	return t
+}
+
+// Record counts a call to method. Failed calls count against every
+// objective of the method regardless of class.
+func (t *Tracker) Record(method, class string, latency time.Duration, failed bool) {
+	minute := time.Now().Unix() / int64(bucketSize/time.Second)
+	for _, o := range t.objectives {
+		if o.Method != method || (o.Class != "" && o.Class != class && !failed) {
+			continue
+		}
+		o.mu.Lock()
+		b := &o.buckets[minute%int64(len(o.buckets))]
+		if b.minute != minute {
+			*b = bucket{minute: minute}
+		}
+		b.total++
+		if !failed && latency <= o.Threshold {
+			b.good++
+		}
+		o.mu.Unlock()
+	}
+}
+
+// Status reports every objective in configured order
+func (t *Tracker) Status() []Status {
+	now := time.Now().Unix() / int64(bucketSize/time.Second)
+	result := make([]Status, len(t.objectives))
+	for i, o := range t.objectives {
+		total, good := o.count(now, t.window)
+		s := Status{
+			Name:            o.Name,
+			Method:          o.Method,
+			Class:           o.Class,
+			ThresholdMs:     o.Threshold.Milliseconds(),
+			Target:          o.Target,
+			WindowSeconds:   int64(t.window.Seconds()),
+			Total:           total,
+			Good:            good,
+			Compliance:      1,
+			BudgetRemaining: 1,
+			BurnRates:       make(map[string]float64, len(BurnWindows)),
+		}
+		if total > 0 {
+			s.Compliance = float64(good) / float64(total)
+			s.BudgetRemaining = 1 - (1-s.Compliance)/(1-o.Target)
+		}
+		for _, w := range BurnWindows {
+			s.BurnRates[w.Name] = o.burnRate(now, min(w.Window, t.window))
+		}
+		s.Met = s.Compliance >= o.Target
+		result[i] = s
+	}
+	return result
+}
+
+// count sums the buckets within window of the minute now
+func (o *objective) count(now int64, window time.Duration) (total, good int64) {
+	since := now - int64(window/bucketSize)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, b := range o.buckets {
+		if b.minute > since && b.minute <= now {
+			total += b.total
+			good += b.good
+		}
+	}
+	return total, good
+}
+
+// burnRate is the bad-call fraction within window relative to the budget
+func (o *objective) burnRate(now int64, window time.Duration) float64 {
+	total, good := o.count(now, window)
+	if total == 0 {
+		return 0
+	}
+	return float64(total-good) / float64(total) / (1 - o.Target)
+}
+
+// call carries the class a handler assigned to the call in progress
+type call struct {
+	mu    sync.Mutex
+	class string
+}
+
+type callKey struct{}
+
+// WithCall prepares ctx for SetClass and returns a function reporting the
+// class set by the handler
+func WithCall(ctx context.Context) (context.Context, func() string) {
+	c := &call{}
+	return context.WithValue(ctx, callKey{}, c), func() string {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.class
+	}
+}
+
+// SetClass labels the call on ctx for class-specific objectives
+func SetClass(ctx context.Context, class string) {
+	if c, ok := ctx.Value(callKey{}).(*call); ok {
+		c.mu.Lock()
+		c.class = class
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,43 @@
+// Package trace carries per-request trace IDs through contexts so log lines,
+// journal entries and audit records of one request can be correlated
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+)
+
+// Header is the gRPC metadata key carrying the trace ID
+const Header = "x-request-id"
+
+type idKey struct{}
+
+// WithID returns a context carrying the trace ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// ID returns the trace ID carried by ctx, or "" if none
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+// NewID generates a random trace ID
+func NewID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Logf logs like log.Printf, prefixed with the trace ID of ctx if present
+func Logf(ctx context.Context, format string, args ...interface{}) {
+	if id := ID(ctx); id != "" {
+		log.Output(2, fmt.Sprintf("[%s] "+format, append([]interface{}{id}, args...)...))
+		return
+	}
+	log.Output(2, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,94 @@
+// Package traffic records anonymized GetPreParams traffic so it can be
+// replayed against test instances with different pool settings
+package traffic
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Record is one GetPreParams or StreamPreParams call. It carries nothing
+// identifying the client or the parameters: no peer address, trace ID,
+// idempotency key or item data.
+type Record struct {
+	Time       time.Time `json:"time"`
+	Pool       string    `json:"pool"`
+	Count      uint32    `json:"count"`
+	Distinct   bool      `json:"distinct_provenance,omitempty"`
+	Seconds    bool      `json:"distinct_seconds,omitempty"`
+	NoGenerate bool      `json:"no_generate,omitempty"`
+	Served     int       `json:"served"`
+	Generated  int       `json:"generated,omitempty"` // Served items generated on demand
+	Code       string    `json:"code"`                // gRPC status code
+	Reason     string    `json:"reason,omitempty"`    // ErrorInfo reason, e.g. POOL_EMPTY
+	LatencyMs  int64     `json:"latency_ms"`
+	PoolSize   int       `json:"pool_size"` // Items left in the pool after the call
+}
+
+// Recorder appends records as JSON lines to a file
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewRecorder opens (or creates) an append-only recording at path
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open traffic recording: %w", err)
+	}
+	return &Recorder{file: file}, nil
+}
+
+// Record appends r. A nil Recorder discards records.
+func (rec *Recorder) Record(r Record) error {
+	if rec == nil {
+		return nil
+	}
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to marshal traffic record: %w", err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if _, err := rec.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write traffic record: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file
+func (rec *Recorder) Close() error {
+	if rec == nil {
+		return nil
+	}
+	return rec.file.Close()
+}
+
+// Read parses a recording, returning its records in time order
+func Read(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
@@ -0,0 +1,267 @@
+// Package workerauth registers generate-only workers and issues the
+// short-lived tokens they submit parameters with
+package workerauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Registration and authentication errors
+var (
+	ErrInvalidCredential = errors.New("invalid or expired credential")
+	ErrRevoked           = errors.New("worker is revoked")
+	ErrUnknownWorker     = errors.New("unknown worker")
+)
+
+// Worker describes a registered or revoked worker
+type Worker struct {
+	ID         string    `json:"id"`
+	Address    string    `json:"address,omitempty"` // Remote address of the latest registration
+	Registered time.Time `json:"registered"`        // Latest token issued
+	Expires    time.Time `json:"expires"`           // Expiry of the current token
+	Submitted  int64     `json:"submitted"`         // Items accepted from the worker
+	Revoked    bool      `json:"revoked"`
+	RevokedAt  time.Time `json:"revoked_at,omitempty"`
+}
+
+// Registry issues and checks worker tokens. Tokens are kept in memory only
+// (as hashes), so workers register again after a restart; revocations are
+// persisted so a revoked worker cannot.
+type Registry struct {
+	mu        sync.Mutex
+	bootstrap string
+	ttl       time.Duration
+	path      string // Persisted revocations (empty: memory only)
+
+	workers map[string]*Worker
+	tokens  map[[sha256.Size]byte]string // Token hash to worker ID
+	current map[string][sha256.Size]byte // Worker ID to its token hash
+}
+
+// New creates a registry accepting the bootstrap token and issuing tokens
+// valid for ttl, loading the revocations persisted at path (if not empty)
+func New(bootstrap string, ttl time.Duration, path string) (*Registry, error) {
+	r := &Registry{
+		bootstrap: bootstrap,
+		ttl:       ttl,
+		path:      path,
+		workers:   make(map[string]*Worker),
+		tokens:    make(map[[sha256.Size]byte]string),
+		current:   make(map[string][sha256.Size]byte),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Register issues a new token to a worker that presents the bootstrap token
+// or its own current token (renewal). The worker's previous token stops
+// working.
+func (r *Registry) Register(id, credential, address string) (string, time.Time, error) {
+	if id == "" {
+		return "", time.Time{}, fmt.Errorf("worker ID must not be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	bootstrap := r.bootstrap != "" && subtle.ConstantTimeCompare([]byte(credential), []byte(r.bootstrap)) == 1
+	if !bootstrap {
+		if owner, ok := r.lookupLocked(credential, now); !ok || owner != id {
+			return "", time.Time{}, ErrInvalidCredential
+		}
+	}
+	w := r.workers[id]
+	if w != nil && w.Revoked {
+		return "", time.Time{}, ErrRevoked
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+	token := hex.EncodeToString(raw)
+	hash := sha256.Sum256([]byte(token))
+
+	if w == nil {
+		w = &Worker{ID: id}
+		r.workers[id] = w
+	}
+	delete(r.tokens, r.current[id])
+	r.tokens[hash] = id
+	r.current[id] = hash
+	w.Address = address
+	w.Registered = now
+	w.Expires = now.Add(r.ttl)
+	r.pruneLocked(now)
+	return token, w.Expires, nil
+}
+
+// Authenticate returns the worker a token was issued to
+func (r *Registry) Authenticate(token string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, ok := r.lookupLocked(token, time.Now())
+	if !ok {
+		return "", ErrInvalidCredential
+	}
+	return id, nil
+}
+
+// lookupLocked returns the worker holding an unexpired token
+// Caller must hold r.mu.
+func (r *Registry) lookupLocked(token string, now time.Time) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+	id, ok := r.tokens[sha256.Sum256([]byte(token))]
+	if !ok {
+		return "", false
+	}
+	w := r.workers[id]
+	if w.Revoked || !now.Before(w.Expires) {
+		return "", false
+	}
+	return id, true
+}
+
+// pruneLocked forgets expired tokens
+// Caller must hold r.mu.
+func (r *Registry) pruneLocked(now time.Time) {
+	for hash, id := range r.tokens {
+		if !now.Before(r.workers[id].Expires) {
+			delete(r.tokens, hash)
+			delete(r.current, id)
+		}
+	}
+}
+
+// RecordSubmitted counts items accepted from a worker
+func (r *Registry) RecordSubmitted(id string, n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if w := r.workers[id]; w != nil {
+		w.Submitted += int64(n)
+	}
+}
+
+// Revoke invalidates a worker's token and refuses its registrations until
+// it is reinstated. Unknown workers are revoked too, so a credential can be
+// blocked before it is used.
+func (r *Registry) Revoke(id string) (Worker, error) {
+	if id == "" {
+		return Worker{}, fmt.Errorf("worker ID must not be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w := r.workers[id]
+	if w == nil {
+		w = &Worker{ID: id}
+		r.workers[id] = w
+	}
+	if !w.Revoked {
+		w.Revoked = true
+		w.RevokedAt = time.Now()
+	}
+	delete(r.tokens, r.current[id])
+	delete(r.current, id)
+	return *w, r.saveLocked()
+}
+
+// Reinstate lets a revoked worker register again
+func (r *Registry) Reinstate(id string) (Worker, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w := r.workers[id]
+	if w == nil {
+		return Worker{}, ErrUnknownWorker
+	}
+	w.Revoked = false
+	w.RevokedAt = time.Time{}
+	return *w, r.saveLocked()
+}
+
+// Workers returns every known worker, ordered by ID
+func (r *Registry) Workers() []Worker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]Worker, 0, len(r.workers))
+	for _, w := range r.workers {
+		result = append(result, *w)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// revocation is a persisted revocation
+type revocation struct {
+	ID        string    `json:"id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// load restores persisted revocations
+func (r *Registry) load() error {
+	if r.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read worker revocations: %w", err)
+	}
+	var revoked []revocation
+	if err := json.Unmarshal(data, &revoked); err != nil {
+		return fmt.Errorf("failed to parse worker revocations %s: %w", r.path, err)
+	}
+	for _, rv := range revoked {
+		r.workers[rv.ID] = &Worker{ID: rv.ID, Revoked: true, RevokedAt: rv.RevokedAt}
+	}
+	return nil
+}
+
+// saveLocked persists the revocations atomically (temp file + rename)
+// Caller must hold r.mu.
+func (r *Registry) saveLocked() error {
+	if r.path == "" {
+		return nil
+	}
+	revoked := []revocation{}
+	for _, w := range r.workers {
+		if w.Revoked {
+			revoked = append(revoked, revocation{ID: w.ID, RevokedAt: w.RevokedAt})
+		}
+	}
+	sort.Slice(revoked, func(i, j int) bool { return revoked[i].ID < revoked[j].ID })
+
+	data, err := json.Marshal(revoked)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker revocations: %w", err)
+	}
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write worker revocations: %w", err)
+	}
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to replace worker revocations: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.7
+// 	protoc-gen-go v1.36.9
 // 	protoc        v5.29.3
 // source: proto/prime.proto
 
@@ -21,6 +21,104 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ItemSource int32
+
+const (
+	ItemSource_ITEM_SOURCE_UNSPECIFIED ItemSource = 0
+	ItemSource_ITEM_SOURCE_POOL        ItemSource = 1 // Served from the pre-computed pool
+	ItemSource_ITEM_SOURCE_GENERATED   ItemSource = 2 // Generated on demand for this request
+)
+
+// Enum value maps for ItemSource.
+var (
+	ItemSource_name = map[int32]string{
+		0: "ITEM_SOURCE_UNSPECIFIED",
+		1: "ITEM_SOURCE_POOL",
+		2: "ITEM_SOURCE_GENERATED",
+	}
+	ItemSource_value = map[string]int32{
+		"ITEM_SOURCE_UNSPECIFIED": 0,
+		"ITEM_SOURCE_POOL":        1,
+		"ITEM_SOURCE_GENERATED":   2,
+	}
+)
+
+func (x ItemSource) Enum() *ItemSource {
+	p := new(ItemSource)
+	*p = x
+	return p
+}
+
+func (x ItemSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ItemSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_prime_proto_enumTypes[0].Descriptor()
+}
+
+func (ItemSource) Type() protoreflect.EnumType {
+	return &file_proto_prime_proto_enumTypes[0]
+}
+
+func (x ItemSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ItemSource.Descriptor instead.
+func (ItemSource) EnumDescriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{0}
+}
+
+type ErrorSeverity int32
+
+const (
+	ErrorSeverity_ERROR_SEVERITY_UNSPECIFIED ErrorSeverity = 0 // No filter
+	ErrorSeverity_ERROR_SEVERITY_WARNING     ErrorSeverity = 1
+	ErrorSeverity_ERROR_SEVERITY_ERROR       ErrorSeverity = 2
+)
+
+// Enum value maps for ErrorSeverity.
+var (
+	ErrorSeverity_name = map[int32]string{
+		0: "ERROR_SEVERITY_UNSPECIFIED",
+		1: "ERROR_SEVERITY_WARNING",
+		2: "ERROR_SEVERITY_ERROR",
+	}
+	ErrorSeverity_value = map[string]int32{
+		"ERROR_SEVERITY_UNSPECIFIED": 0,
+		"ERROR_SEVERITY_WARNING":     1,
+		"ERROR_SEVERITY_ERROR":       2,
+	}
+)
+
+func (x ErrorSeverity) Enum() *ErrorSeverity {
+	p := new(ErrorSeverity)
+	*p = x
+	return p
+}
+
+func (x ErrorSeverity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorSeverity) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_prime_proto_enumTypes[1].Descriptor()
+}
+
+func (ErrorSeverity) Type() protoreflect.EnumType {
+	return &file_proto_prime_proto_enumTypes[1]
+}
+
+func (x ErrorSeverity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorSeverity.Descriptor instead.
+func (ErrorSeverity) EnumDescriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{1}
+}
+
 type Empty struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -67,14 +165,15 @@ type PreParamsData struct {
 	PaillierPhiN    []byte `protobuf:"bytes,4,opt,name=paillier_phi_n,json=paillierPhiN,proto3" json:"paillier_phi_n,omitempty"`
 	PaillierLambdaN []byte `protobuf:"bytes,5,opt,name=paillier_lambda_n,json=paillierLambdaN,proto3" json:"paillier_lambda_n,omitempty"`
 	// Additional parameters for ECDSA
-	NTildei       []byte `protobuf:"bytes,6,opt,name=n_tildei,json=nTildei,proto3" json:"n_tildei,omitempty"`
-	H1I           []byte `protobuf:"bytes,7,opt,name=h1i,proto3" json:"h1i,omitempty"`
-	H2I           []byte `protobuf:"bytes,8,opt,name=h2i,proto3" json:"h2i,omitempty"`
-	Alpha         []byte `protobuf:"bytes,9,opt,name=alpha,proto3" json:"alpha,omitempty"`
-	Beta          []byte `protobuf:"bytes,10,opt,name=beta,proto3" json:"beta,omitempty"`
-	P             []byte `protobuf:"bytes,11,opt,name=p,proto3" json:"p,omitempty"`                                         // safe prime for NTildei
-	Q             []byte `protobuf:"bytes,12,opt,name=q,proto3" json:"q,omitempty"`                                         // safe prime for NTildei
-	GeneratedAt   int64  `protobuf:"varint,13,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"` // Unix timestamp
+	NTildei       []byte        `protobuf:"bytes,6,opt,name=n_tildei,json=nTildei,proto3" json:"n_tildei,omitempty"`
+	H1I           []byte        `protobuf:"bytes,7,opt,name=h1i,proto3" json:"h1i,omitempty"`
+	H2I           []byte        `protobuf:"bytes,8,opt,name=h2i,proto3" json:"h2i,omitempty"`
+	Alpha         []byte        `protobuf:"bytes,9,opt,name=alpha,proto3" json:"alpha,omitempty"`
+	Beta          []byte        `protobuf:"bytes,10,opt,name=beta,proto3" json:"beta,omitempty"`
+	P             []byte        `protobuf:"bytes,11,opt,name=p,proto3" json:"p,omitempty"`                                         // safe prime for NTildei
+	Q             []byte        `protobuf:"bytes,12,opt,name=q,proto3" json:"q,omitempty"`                                         // safe prime for NTildei
+	GeneratedAt   int64         `protobuf:"varint,13,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"` // Unix timestamp
+	Metadata      *ItemMetadata `protobuf:"bytes,14,opt,name=metadata,proto3" json:"metadata,omitempty"`                           // How this item was provisioned for the request
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -200,27 +299,42 @@ func (x *PreParamsData) GetGeneratedAt() int64 {
 	return 0
 }
 
-type GetPreParamsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"` // Number of PreParams to return (default 1 if not specified)
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *PreParamsData) GetMetadata() *ItemMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
 }
 
-func (x *GetPreParamsRequest) Reset() {
-	*x = GetPreParamsRequest{}
+// Per-item provisioning metadata
+type ItemMetadata struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Source               ItemSource             `protobuf:"varint,1,opt,name=source,proto3,enum=prime.ItemSource" json:"source,omitempty"`
+	PoolAgeMs            int64                  `protobuf:"varint,2,opt,name=pool_age_ms,json=poolAgeMs,proto3" json:"pool_age_ms,omitempty"`                                  // Time since generation when served from pool
+	GenerationDurationMs int64                  `protobuf:"varint,3,opt,name=generation_duration_ms,json=generationDurationMs,proto3" json:"generation_duration_ms,omitempty"` // Time taken to generate the item
+	Replayed             bool                   `protobuf:"varint,4,opt,name=replayed,proto3" json:"replayed,omitempty"`                                                       // Returned again for a retried idempotency key
+	Provenance           *Provenance            `protobuf:"bytes,5,opt,name=provenance,proto3" json:"provenance,omitempty"`                                                    // Where and in which burst the item was generated
+	Stale                bool                   `protobuf:"varint,6,opt,name=stale,proto3" json:"stale,omitempty"`                                                             // Older than the service's max_age, served under stale_policy "serve"
+	SharedSecond         bool                   `protobuf:"varint,7,opt,name=shared_second,json=sharedSecond,proto3" json:"shared_second,omitempty"`                           // Generated in the same second as another item of a distinct_seconds batch
+	Assurance            string                 `protobuf:"bytes,8,opt,name=assurance,proto3" json:"assurance,omitempty"`                                                      // Assurance level the item passed: standard or audited
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ItemMetadata) Reset() {
+	*x = ItemMetadata{}
 	mi := &file_proto_prime_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPreParamsRequest) String() string {
+func (x *ItemMetadata) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPreParamsRequest) ProtoMessage() {}
+func (*ItemMetadata) ProtoMessage() {}
 
-func (x *GetPreParamsRequest) ProtoReflect() protoreflect.Message {
+func (x *ItemMetadata) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_prime_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -232,40 +346,95 @@ func (x *GetPreParamsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPreParamsRequest.ProtoReflect.Descriptor instead.
-func (*GetPreParamsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ItemMetadata.ProtoReflect.Descriptor instead.
+func (*ItemMetadata) Descriptor() ([]byte, []int) {
 	return file_proto_prime_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetPreParamsRequest) GetCount() uint32 {
+func (x *ItemMetadata) GetSource() ItemSource {
 	if x != nil {
-		return x.Count
+		return x.Source
+	}
+	return ItemSource_ITEM_SOURCE_UNSPECIFIED
+}
+
+func (x *ItemMetadata) GetPoolAgeMs() int64 {
+	if x != nil {
+		return x.PoolAgeMs
 	}
 	return 0
 }
 
-type GetPreParamsResponse struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	Params           []*PreParamsData       `protobuf:"bytes,1,rep,name=params,proto3" json:"params,omitempty"` // Returns 1 or more PreParamsData
-	GenerationTimeMs int64                  `protobuf:"varint,2,opt,name=generation_time_ms,json=generationTimeMs,proto3" json:"generation_time_ms,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *ItemMetadata) GetGenerationDurationMs() int64 {
+	if x != nil {
+		return x.GenerationDurationMs
+	}
+	return 0
 }
 
-func (x *GetPreParamsResponse) Reset() {
-	*x = GetPreParamsResponse{}
+func (x *ItemMetadata) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *ItemMetadata) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *ItemMetadata) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *ItemMetadata) GetSharedSecond() bool {
+	if x != nil {
+		return x.SharedSecond
+	}
+	return false
+}
+
+func (x *ItemMetadata) GetAssurance() string {
+	if x != nil {
+		return x.Assurance
+	}
+	return ""
+}
+
+// Provenance identifies the generation run an item came from
+type Provenance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`          // Host that generated the item
+	Burst         string                 `protobuf:"bytes,2,opt,name=burst,proto3" json:"burst,omitempty"`        // Refill or on-demand burst identifier
+	Worker        int32                  `protobuf:"varint,3,opt,name=worker,proto3" json:"worker,omitempty"`     // Worker within the burst
+	Instance      string                 `protobuf:"bytes,4,opt,name=instance,proto3" json:"instance,omitempty"`  // Instance ID of the generating service
+	Imported      bool                   `protobuf:"varint,5,opt,name=imported,proto3" json:"imported,omitempty"` // Came from elsewhere and was fully revalidated on import (pool.import_revalidate_age)
+	Seq           uint64                 `protobuf:"varint,6,opt,name=seq,proto3" json:"seq,omitempty"`           // Sequence number of the item in the serving pool (item 4182)
+	Version       string                 `protobuf:"bytes,7,opt,name=version,proto3" json:"version,omitempty"`    // Version of the generation code ("": not recorded)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Provenance) Reset() {
+	*x = Provenance{}
 	mi := &file_proto_prime_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPreParamsResponse) String() string {
+func (x *Provenance) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPreParamsResponse) ProtoMessage() {}
+func (*Provenance) ProtoMessage() {}
 
-func (x *GetPreParamsResponse) ProtoReflect() protoreflect.Message {
+func (x *Provenance) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_prime_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -277,48 +446,114 @@ func (x *GetPreParamsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPreParamsResponse.ProtoReflect.Descriptor instead.
-func (*GetPreParamsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use Provenance.ProtoReflect.Descriptor instead.
+func (*Provenance) Descriptor() ([]byte, []int) {
 	return file_proto_prime_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetPreParamsResponse) GetParams() []*PreParamsData {
+func (x *Provenance) GetHost() string {
 	if x != nil {
-		return x.Params
+		return x.Host
 	}
-	return nil
+	return ""
 }
 
-func (x *GetPreParamsResponse) GetGenerationTimeMs() int64 {
+func (x *Provenance) GetBurst() string {
 	if x != nil {
-		return x.GenerationTimeMs
+		return x.Burst
+	}
+	return ""
+}
+
+func (x *Provenance) GetWorker() int32 {
+	if x != nil {
+		return x.Worker
 	}
 	return 0
 }
 
-type HealthStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Healthy       bool                   `protobuf:"varint,1,opt,name=healthy,proto3" json:"healthy,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	UptimeSeconds int64                  `protobuf:"varint,3,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+func (x *Provenance) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *Provenance) GetImported() bool {
+	if x != nil {
+		return x.Imported
+	}
+	return false
+}
+
+func (x *Provenance) GetSeq() uint64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *Provenance) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type GetPreParamsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Count uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"` // Number of PreParams to return (default 1 if not specified)
+	// Retries carrying the same key return the originally allocated items,
+	// even across server restarts, instead of consuming more of the pool
+	IdempotencyKey string `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// Only return items with mutually distinct provenance (different host or
+	// burst), for ceremonies that must not share correlated parameters.
+	// Fewer items than requested may be returned.
+	DistinctProvenance bool `protobuf:"varint,3,opt,name=distinct_provenance,json=distinctProvenance,proto3" json:"distinct_provenance,omitempty"`
+	// Never generate synchronously for this request: if the pool has no
+	// suitable items, fail at once with RESOURCE_EXHAUSTED (ErrorInfo reason
+	// POOL_EMPTY) so the caller can try another instance. Fewer items than
+	// requested may be returned.
+	NoGenerate bool `protobuf:"varint,4,opt,name=no_generate,json=noGenerate,proto3" json:"no_generate,omitempty"`
+	// Also accept imported items that the service serves only on request
+	// (pool.import_demote)
+	AllowImported bool `protobuf:"varint,5,opt,name=allow_imported,json=allowImported,proto3" json:"allow_imported,omitempty"`
+	// Spread the batch across items generated in distinct seconds where the
+	// pool allows it. Cheaper than distinct_provenance: the full count is
+	// still returned, and items that could not be spread are flagged with
+	// ItemMetadata.shared_second.
+	DistinctSeconds bool `protobuf:"varint,6,opt,name=distinct_seconds,json=distinctSeconds,proto3" json:"distinct_seconds,omitempty"`
+	// Bit sizes to serve (0: those of the pool the request is routed to).
+	// The request goes to a pool serving these sizes; if none does, larger
+	// sizes up to server.max_requested_bit_size are generated synchronously.
+	PrimeBitSize    uint32 `protobuf:"varint,7,opt,name=prime_bit_size,json=primeBitSize,proto3" json:"prime_bit_size,omitempty"`
+	PaillierBitSize uint32 `protobuf:"varint,8,opt,name=paillier_bit_size,json=paillierBitSize,proto3" json:"paillier_bit_size,omitempty"`
+	// Only serve items of at least this assurance level: "standard" (passed
+	// the generator's checks; the default) or "audited" (also passed full
+	// primality validation). Items generated on demand are audited to reach it.
+	MinAssurance string `protobuf:"bytes,9,opt,name=min_assurance,json=minAssurance,proto3" json:"min_assurance,omitempty"`
+	// StreamPreParams only: send a message carrying queue (and no params)
+	// whenever the request's place changes while it waits for one of the
+	// service's pool.max_on_demand generation slots
+	ReportQueue   bool `protobuf:"varint,10,opt,name=report_queue,json=reportQueue,proto3" json:"report_queue,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HealthStatus) Reset() {
-	*x = HealthStatus{}
+func (x *GetPreParamsRequest) Reset() {
+	*x = GetPreParamsRequest{}
 	mi := &file_proto_prime_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HealthStatus) String() string {
+func (x *GetPreParamsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HealthStatus) ProtoMessage() {}
+func (*GetPreParamsRequest) ProtoMessage() {}
 
-func (x *HealthStatus) ProtoReflect() protoreflect.Message {
+func (x *GetPreParamsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_prime_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -330,127 +565,110 @@ func (x *HealthStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HealthStatus.ProtoReflect.Descriptor instead.
-func (*HealthStatus) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetPreParamsRequest.ProtoReflect.Descriptor instead.
+func (*GetPreParamsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_prime_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *HealthStatus) GetHealthy() bool {
+func (x *GetPreParamsRequest) GetCount() uint32 {
 	if x != nil {
-		return x.Healthy
+		return x.Count
 	}
-	return false
+	return 0
 }
 
-func (x *HealthStatus) GetMessage() string {
+func (x *GetPreParamsRequest) GetIdempotencyKey() string {
 	if x != nil {
-		return x.Message
+		return x.IdempotencyKey
 	}
 	return ""
 }
 
-func (x *HealthStatus) GetUptimeSeconds() int64 {
+func (x *GetPreParamsRequest) GetDistinctProvenance() bool {
 	if x != nil {
-		return x.UptimeSeconds
+		return x.DistinctProvenance
 	}
-	return 0
-}
-
-type PoolStatus struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Pools          map[string]*PoolInfo   `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Key: "1024_true" etc.
-	TotalGenerated int64                  `protobuf:"varint,2,opt,name=total_generated,json=totalGenerated,proto3" json:"total_generated,omitempty"`                                  // Total params generated since start
-	TotalServed    int64                  `protobuf:"varint,3,opt,name=total_served,json=totalServed,proto3" json:"total_served,omitempty"`                                           // Total params served to clients
-	GenerationRate float64                `protobuf:"fixed64,4,opt,name=generation_rate,json=generationRate,proto3" json:"generation_rate,omitempty"`                                 // Params per second
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
-}
-
-func (x *PoolStatus) Reset() {
-	*x = PoolStatus{}
-	mi := &file_proto_prime_proto_msgTypes[5]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+	return false
 }
 
-func (x *PoolStatus) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GetPreParamsRequest) GetNoGenerate() bool {
+	if x != nil {
+		return x.NoGenerate
+	}
+	return false
 }
 
-func (*PoolStatus) ProtoMessage() {}
-
-func (x *PoolStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_prime_proto_msgTypes[5]
+func (x *GetPreParamsRequest) GetAllowImported() bool {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.AllowImported
 	}
-	return mi.MessageOf(x)
+	return false
 }
 
-// Deprecated: Use PoolStatus.ProtoReflect.Descriptor instead.
-func (*PoolStatus) Descriptor() ([]byte, []int) {
-	return file_proto_prime_proto_rawDescGZIP(), []int{5}
+func (x *GetPreParamsRequest) GetDistinctSeconds() bool {
+	if x != nil {
+		return x.DistinctSeconds
+	}
+	return false
 }
 
-func (x *PoolStatus) GetPools() map[string]*PoolInfo {
+func (x *GetPreParamsRequest) GetPrimeBitSize() uint32 {
 	if x != nil {
-		return x.Pools
+		return x.PrimeBitSize
 	}
-	return nil
+	return 0
 }
 
-func (x *PoolStatus) GetTotalGenerated() int64 {
+func (x *GetPreParamsRequest) GetPaillierBitSize() uint32 {
 	if x != nil {
-		return x.TotalGenerated
+		return x.PaillierBitSize
 	}
 	return 0
 }
 
-func (x *PoolStatus) GetTotalServed() int64 {
+func (x *GetPreParamsRequest) GetMinAssurance() string {
 	if x != nil {
-		return x.TotalServed
+		return x.MinAssurance
 	}
-	return 0
+	return ""
 }
 
-func (x *PoolStatus) GetGenerationRate() float64 {
+func (x *GetPreParamsRequest) GetReportQueue() bool {
 	if x != nil {
-		return x.GenerationRate
+		return x.ReportQueue
 	}
-	return 0
+	return false
 }
 
-type PoolInfo struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Bits           uint32                 `protobuf:"varint,1,opt,name=bits,proto3" json:"bits,omitempty"`
-	SafePrime      bool                   `protobuf:"varint,2,opt,name=safe_prime,json=safePrime,proto3" json:"safe_prime,omitempty"`
-	Available      uint32                 `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`                                   // Current pool size
-	TargetSize     uint32                 `protobuf:"varint,4,opt,name=target_size,json=targetSize,proto3" json:"target_size,omitempty"`               // Target pool size
-	Generating     uint32                 `protobuf:"varint,5,opt,name=generating,proto3" json:"generating,omitempty"`                                 // Currently being generated
-	LastRefillTime int64                  `protobuf:"varint,6,opt,name=last_refill_time,json=lastRefillTime,proto3" json:"last_refill_time,omitempty"` // Unix timestamp
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type WaitForPreParamsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Count     uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`                          // Number of PreParams to wait for (default 1), at most max_pool_size
+	TimeoutMs uint32                 `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"` // Longest wait; the call deadline also bounds it
+	// As in GetPreParamsRequest
+	IdempotencyKey     string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	DistinctProvenance bool   `protobuf:"varint,4,opt,name=distinct_provenance,json=distinctProvenance,proto3" json:"distinct_provenance,omitempty"`
+	AllowImported      bool   `protobuf:"varint,5,opt,name=allow_imported,json=allowImported,proto3" json:"allow_imported,omitempty"`
+	DistinctSeconds    bool   `protobuf:"varint,6,opt,name=distinct_seconds,json=distinctSeconds,proto3" json:"distinct_seconds,omitempty"`
+	MinAssurance       string `protobuf:"bytes,7,opt,name=min_assurance,json=minAssurance,proto3" json:"min_assurance,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
-func (x *PoolInfo) Reset() {
-	*x = PoolInfo{}
-	mi := &file_proto_prime_proto_msgTypes[6]
+func (x *WaitForPreParamsRequest) Reset() {
+	*x = WaitForPreParamsRequest{}
+	mi := &file_proto_prime_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PoolInfo) String() string {
+func (x *WaitForPreParamsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PoolInfo) ProtoMessage() {}
+func (*WaitForPreParamsRequest) ProtoMessage() {}
 
-func (x *PoolInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_prime_proto_msgTypes[6]
+func (x *WaitForPreParamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -461,49 +679,4078 @@ func (x *PoolInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PoolInfo.ProtoReflect.Descriptor instead.
-func (*PoolInfo) Descriptor() ([]byte, []int) {
-	return file_proto_prime_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use WaitForPreParamsRequest.ProtoReflect.Descriptor instead.
+func (*WaitForPreParamsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *PoolInfo) GetBits() uint32 {
+func (x *WaitForPreParamsRequest) GetCount() uint32 {
 	if x != nil {
-		return x.Bits
+		return x.Count
 	}
 	return 0
 }
 
-func (x *PoolInfo) GetSafePrime() bool {
+func (x *WaitForPreParamsRequest) GetTimeoutMs() uint32 {
 	if x != nil {
-		return x.SafePrime
+		return x.TimeoutMs
 	}
-	return false
+	return 0
 }
 
-func (x *PoolInfo) GetAvailable() uint32 {
+func (x *WaitForPreParamsRequest) GetIdempotencyKey() string {
 	if x != nil {
-		return x.Available
+		return x.IdempotencyKey
 	}
-	return 0
+	return ""
+}
+
+func (x *WaitForPreParamsRequest) GetDistinctProvenance() bool {
+	if x != nil {
+		return x.DistinctProvenance
+	}
+	return false
+}
+
+func (x *WaitForPreParamsRequest) GetAllowImported() bool {
+	if x != nil {
+		return x.AllowImported
+	}
+	return false
+}
+
+func (x *WaitForPreParamsRequest) GetDistinctSeconds() bool {
+	if x != nil {
+		return x.DistinctSeconds
+	}
+	return false
+}
+
+func (x *WaitForPreParamsRequest) GetMinAssurance() string {
+	if x != nil {
+		return x.MinAssurance
+	}
+	return ""
+}
+
+type GetPreParamsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Params           []*PreParamsData       `protobuf:"bytes,1,rep,name=params,proto3" json:"params,omitempty"`                                                // Returns 1 or more PreParamsData
+	GenerationTimeMs int64                  `protobuf:"varint,2,opt,name=generation_time_ms,json=generationTimeMs,proto3" json:"generation_time_ms,omitempty"` // Server handler wall time
+	Queue            *QueueStatus           `protobuf:"bytes,3,opt,name=queue,proto3" json:"queue,omitempty"`                                                  // Set on StreamPreParams progress messages (report_queue)
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetPreParamsResponse) Reset() {
+	*x = GetPreParamsResponse{}
+	mi := &file_proto_prime_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPreParamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPreParamsResponse) ProtoMessage() {}
+
+func (x *GetPreParamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPreParamsResponse.ProtoReflect.Descriptor instead.
+func (*GetPreParamsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetPreParamsResponse) GetParams() []*PreParamsData {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *GetPreParamsResponse) GetGenerationTimeMs() int64 {
+	if x != nil {
+		return x.GenerationTimeMs
+	}
+	return 0
+}
+
+func (x *GetPreParamsResponse) GetQueue() *QueueStatus {
+	if x != nil {
+		return x.Queue
+	}
+	return nil
+}
+
+// Place of a request waiting for an on-demand generation slot
+type QueueStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Position      uint32                 `protobuf:"varint,1,opt,name=position,proto3" json:"position,omitempty"`                       // Requests ahead plus one; 0 once generating
+	EtaSeconds    int64                  `protobuf:"varint,2,opt,name=eta_seconds,json=etaSeconds,proto3" json:"eta_seconds,omitempty"` // Estimated wait for a slot (-1 if unknown)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueStatus) Reset() {
+	*x = QueueStatus{}
+	mi := &file_proto_prime_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueStatus) ProtoMessage() {}
+
+func (x *QueueStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueStatus.ProtoReflect.Descriptor instead.
+func (*QueueStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *QueueStatus) GetPosition() uint32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueueStatus) GetEtaSeconds() int64 {
+	if x != nil {
+		return x.EtaSeconds
+	}
+	return 0
+}
+
+type HealthStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Healthy       bool                   `protobuf:"varint,1,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	UptimeSeconds int64                  `protobuf:"varint,3,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	InstanceId    string                 `protobuf:"bytes,4,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Warnings      []string               `protobuf:"bytes,5,rep,name=warnings,proto3" json:"warnings,omitempty"` // Non-fatal problems, e.g. startup storage inconsistencies
+	Region        string                 `protobuf:"bytes,6,opt,name=region,proto3" json:"region,omitempty"`     // Locality hint for clients preferring nearby replicas (empty: not configured)
+	Zone          string                 `protobuf:"bytes,7,opt,name=zone,proto3" json:"zone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthStatus) Reset() {
+	*x = HealthStatus{}
+	mi := &file_proto_prime_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthStatus) ProtoMessage() {}
+
+func (x *HealthStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthStatus.ProtoReflect.Descriptor instead.
+func (*HealthStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HealthStatus) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *HealthStatus) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *HealthStatus) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *HealthStatus) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *HealthStatus) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *HealthStatus) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *HealthStatus) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+type PoolStatus struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Pools                map[string]*PoolInfo   `protobuf:"bytes,1,rep,name=pools,proto3" json:"pools,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Key: "1024_true" etc.
+	TotalGenerated       int64                  `protobuf:"varint,2,opt,name=total_generated,json=totalGenerated,proto3" json:"total_generated,omitempty"`                                  // Total params generated since start
+	TotalServed          int64                  `protobuf:"varint,3,opt,name=total_served,json=totalServed,proto3" json:"total_served,omitempty"`                                           // Total params served to clients
+	GenerationRate       float64                `protobuf:"fixed64,4,opt,name=generation_rate,json=generationRate,proto3" json:"generation_rate,omitempty"`                                 // Params per second
+	SelectionPolicy      string                 `protobuf:"bytes,5,opt,name=selection_policy,json=selectionPolicy,proto3" json:"selection_policy,omitempty"`                                // oldest, newest or random
+	InstanceId           string                 `protobuf:"bytes,6,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`                                               // Stable identity of the serving instance
+	Maintenance          bool                   `protobuf:"varint,7,opt,name=maintenance,proto3" json:"maintenance,omitempty"`                                                              // Refusing new GetPreParams calls
+	ConsistencyIssues    uint32                 `protobuf:"varint,8,opt,name=consistency_issues,json=consistencyIssues,proto3" json:"consistency_issues,omitempty"`                         // Discrepancies between ledger and storage found at startup
+	AlreadyServedDropped uint32                 `protobuf:"varint,9,opt,name=already_served_dropped,json=alreadyServedDropped,proto3" json:"already_served_dropped,omitempty"`              // Stored items found already served at startup (double-serve hazard)
+	Alarms               []*Alarm               `protobuf:"bytes,10,rep,name=alarms,proto3" json:"alarms,omitempty"`                                                                        // Firing consumption alarms
+	Frozen               bool                   `protobuf:"varint,11,opt,name=frozen,proto3" json:"frozen,omitempty"`                                                                       // Serving stopped after an anomaly
+	FreezeReason         string                 `protobuf:"bytes,12,opt,name=freeze_reason,json=freezeReason,proto3" json:"freeze_reason,omitempty"`                                        // Anomaly that froze the pool
+	// Pool version for change detection: pool_epoch changes when the service
+	// restarts, pool_version increases on every pool, generation, alarm or
+	// serving-mode change. A poller that sees the same pair has missed nothing.
+	PoolEpoch   int64  `protobuf:"varint,13,opt,name=pool_epoch,json=poolEpoch,proto3" json:"pool_epoch,omitempty"`
+	PoolVersion uint64 `protobuf:"varint,14,opt,name=pool_version,json=poolVersion,proto3" json:"pool_version,omitempty"`
+	Pool        string `protobuf:"bytes,15,opt,name=pool,proto3" json:"pool,omitempty"` // Name of the pool answering ("default" or a configured named pool)
+	// Time spent in each generation phase since start, across all pools
+	PhaseTimings []*PhaseTiming `protobuf:"bytes,16,rep,name=phase_timings,json=phaseTimings,proto3" json:"phase_timings,omitempty"`
+	// CPUs generation threads are pinned to as a Linux CPU list (empty: not pinned)
+	GenerationCpus string `protobuf:"bytes,17,opt,name=generation_cpus,json=generationCpus,proto3" json:"generation_cpus,omitempty"`
+	// Locality of the answering instance (empty: not configured)
+	Region string `protobuf:"bytes,18,opt,name=region,proto3" json:"region,omitempty"`
+	Zone   string `protobuf:"bytes,19,opt,name=zone,proto3" json:"zone,omitempty"`
+	// Items set aside by PinItem; not counted in pool sizes
+	Pinned []*PinnedItem `protobuf:"bytes,20,rep,name=pinned,proto3" json:"pinned,omitempty"`
+	// total_served split into items served from the pool and items generated
+	// synchronously because the pool could not serve them (misses), with the
+	// time requests spent generating the misses
+	PoolHits         int64 `protobuf:"varint,21,opt,name=pool_hits,json=poolHits,proto3" json:"pool_hits,omitempty"`
+	PoolMisses       int64 `protobuf:"varint,22,opt,name=pool_misses,json=poolMisses,proto3" json:"pool_misses,omitempty"`
+	MissGenerationMs int64 `protobuf:"varint,23,opt,name=miss_generation_ms,json=missGenerationMs,proto3" json:"miss_generation_ms,omitempty"`
+	// Pool items by assurance level (standard, audited)
+	AssuranceLevels map[string]uint32 `protobuf:"bytes,24,rep,name=assurance_levels,json=assuranceLevels,proto3" json:"assurance_levels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// Ages of the pool items, for monitoring freshness policies
+	ItemAges      *ItemAges `protobuf:"bytes,25,opt,name=item_ages,json=itemAges,proto3" json:"item_ages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PoolStatus) Reset() {
+	*x = PoolStatus{}
+	mi := &file_proto_prime_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolStatus) ProtoMessage() {}
+
+func (x *PoolStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolStatus.ProtoReflect.Descriptor instead.
+func (*PoolStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PoolStatus) GetPools() map[string]*PoolInfo {
+	if x != nil {
+		return x.Pools
+	}
+	return nil
+}
+
+func (x *PoolStatus) GetTotalGenerated() int64 {
+	if x != nil {
+		return x.TotalGenerated
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetTotalServed() int64 {
+	if x != nil {
+		return x.TotalServed
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetGenerationRate() float64 {
+	if x != nil {
+		return x.GenerationRate
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetSelectionPolicy() string {
+	if x != nil {
+		return x.SelectionPolicy
+	}
+	return ""
+}
+
+func (x *PoolStatus) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *PoolStatus) GetMaintenance() bool {
+	if x != nil {
+		return x.Maintenance
+	}
+	return false
+}
+
+func (x *PoolStatus) GetConsistencyIssues() uint32 {
+	if x != nil {
+		return x.ConsistencyIssues
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetAlreadyServedDropped() uint32 {
+	if x != nil {
+		return x.AlreadyServedDropped
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetAlarms() []*Alarm {
+	if x != nil {
+		return x.Alarms
+	}
+	return nil
+}
+
+func (x *PoolStatus) GetFrozen() bool {
+	if x != nil {
+		return x.Frozen
+	}
+	return false
+}
+
+func (x *PoolStatus) GetFreezeReason() string {
+	if x != nil {
+		return x.FreezeReason
+	}
+	return ""
+}
+
+func (x *PoolStatus) GetPoolEpoch() int64 {
+	if x != nil {
+		return x.PoolEpoch
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetPoolVersion() uint64 {
+	if x != nil {
+		return x.PoolVersion
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *PoolStatus) GetPhaseTimings() []*PhaseTiming {
+	if x != nil {
+		return x.PhaseTimings
+	}
+	return nil
+}
+
+func (x *PoolStatus) GetGenerationCpus() string {
+	if x != nil {
+		return x.GenerationCpus
+	}
+	return ""
+}
+
+func (x *PoolStatus) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *PoolStatus) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *PoolStatus) GetPinned() []*PinnedItem {
+	if x != nil {
+		return x.Pinned
+	}
+	return nil
+}
+
+func (x *PoolStatus) GetPoolHits() int64 {
+	if x != nil {
+		return x.PoolHits
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetPoolMisses() int64 {
+	if x != nil {
+		return x.PoolMisses
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetMissGenerationMs() int64 {
+	if x != nil {
+		return x.MissGenerationMs
+	}
+	return 0
+}
+
+func (x *PoolStatus) GetAssuranceLevels() map[string]uint32 {
+	if x != nil {
+		return x.AssuranceLevels
+	}
+	return nil
+}
+
+func (x *PoolStatus) GetItemAges() *ItemAges {
+	if x != nil {
+		return x.ItemAges
+	}
+	return nil
+}
+
+// Age statistics of the items in a pool. All fields are zero for an empty pool.
+type ItemAges struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	OldestGeneratedAt int64                  `protobuf:"varint,1,opt,name=oldest_generated_at,json=oldestGeneratedAt,proto3" json:"oldest_generated_at,omitempty"` // Unix timestamp of the oldest item
+	NewestGeneratedAt int64                  `protobuf:"varint,2,opt,name=newest_generated_at,json=newestGeneratedAt,proto3" json:"newest_generated_at,omitempty"` // Unix timestamp of the newest item
+	MinSeconds        int64                  `protobuf:"varint,3,opt,name=min_seconds,json=minSeconds,proto3" json:"min_seconds,omitempty"`                        // Age of the newest item
+	MedianSeconds     int64                  `protobuf:"varint,4,opt,name=median_seconds,json=medianSeconds,proto3" json:"median_seconds,omitempty"`
+	P90Seconds        int64                  `protobuf:"varint,5,opt,name=p90_seconds,json=p90Seconds,proto3" json:"p90_seconds,omitempty"`
+	MaxSeconds        int64                  `protobuf:"varint,6,opt,name=max_seconds,json=maxSeconds,proto3" json:"max_seconds,omitempty"` // Age of the oldest item
+	Stale             uint32                 `protobuf:"varint,7,opt,name=stale,proto3" json:"stale,omitempty"`                             // Older than pool.max_age
+	Buckets           []*AgeBucket           `protobuf:"bytes,8,rep,name=buckets,proto3" json:"buckets,omitempty"`                          // Histogram, youngest bucket first
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ItemAges) Reset() {
+	*x = ItemAges{}
+	mi := &file_proto_prime_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemAges) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemAges) ProtoMessage() {}
+
+func (x *ItemAges) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemAges.ProtoReflect.Descriptor instead.
+func (*ItemAges) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ItemAges) GetOldestGeneratedAt() int64 {
+	if x != nil {
+		return x.OldestGeneratedAt
+	}
+	return 0
+}
+
+func (x *ItemAges) GetNewestGeneratedAt() int64 {
+	if x != nil {
+		return x.NewestGeneratedAt
+	}
+	return 0
+}
+
+func (x *ItemAges) GetMinSeconds() int64 {
+	if x != nil {
+		return x.MinSeconds
+	}
+	return 0
+}
+
+func (x *ItemAges) GetMedianSeconds() int64 {
+	if x != nil {
+		return x.MedianSeconds
+	}
+	return 0
+}
+
+func (x *ItemAges) GetP90Seconds() int64 {
+	if x != nil {
+		return x.P90Seconds
+	}
+	return 0
+}
+
+func (x *ItemAges) GetMaxSeconds() int64 {
+	if x != nil {
+		return x.MaxSeconds
+	}
+	return 0
+}
+
+func (x *ItemAges) GetStale() uint32 {
+	if x != nil {
+		return x.Stale
+	}
+	return 0
+}
+
+func (x *ItemAges) GetBuckets() []*AgeBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+// Items up to an age and older than the bucket before it
+type AgeBucket struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UpTo          string                 `protobuf:"bytes,1,opt,name=up_to,json=upTo,proto3" json:"up_to,omitempty"` // 1h, 1d, 7d, 30d or older
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AgeBucket) Reset() {
+	*x = AgeBucket{}
+	mi := &file_proto_prime_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgeBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgeBucket) ProtoMessage() {}
+
+func (x *AgeBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AgeBucket.ProtoReflect.Descriptor instead.
+func (*AgeBucket) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AgeBucket) GetUpTo() string {
+	if x != nil {
+		return x.UpTo
+	}
+	return ""
+}
+
+func (x *AgeBucket) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Timing of one phase of parameter generation
+type PhaseTiming struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Phase         string                 `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`                           // paillier, safe_primes or pedersen
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`                          // Successful runs
+	TotalMs       int64                  `protobuf:"varint,3,opt,name=total_ms,json=totalMs,proto3" json:"total_ms,omitempty"`       // Time spent in successful runs
+	AverageMs     int64                  `protobuf:"varint,4,opt,name=average_ms,json=averageMs,proto3" json:"average_ms,omitempty"` // Average successful run
+	Failures      int64                  `protobuf:"varint,5,opt,name=failures,proto3" json:"failures,omitempty"`                    // Failed runs (errors or timeouts)
+	FailedMs      int64                  `protobuf:"varint,6,opt,name=failed_ms,json=failedMs,proto3" json:"failed_ms,omitempty"`    // Time spent in failed runs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PhaseTiming) Reset() {
+	*x = PhaseTiming{}
+	mi := &file_proto_prime_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PhaseTiming) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhaseTiming) ProtoMessage() {}
+
+func (x *PhaseTiming) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhaseTiming.ProtoReflect.Descriptor instead.
+func (*PhaseTiming) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PhaseTiming) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *PhaseTiming) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PhaseTiming) GetTotalMs() int64 {
+	if x != nil {
+		return x.TotalMs
+	}
+	return 0
+}
+
+func (x *PhaseTiming) GetAverageMs() int64 {
+	if x != nil {
+		return x.AverageMs
+	}
+	return 0
+}
+
+func (x *PhaseTiming) GetFailures() int64 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *PhaseTiming) GetFailedMs() int64 {
+	if x != nil {
+		return x.FailedMs
+	}
+	return 0
+}
+
+// A firing consumption alarm
+type Alarm struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // served_rate, pool_empty_soon or pool_frozen
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Since         int64                  `protobuf:"varint,3,opt,name=since,proto3" json:"since,omitempty"` // Unix timestamp the alarm started firing
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alarm) Reset() {
+	*x = Alarm{}
+	mi := &file_proto_prime_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alarm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alarm) ProtoMessage() {}
+
+func (x *Alarm) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alarm.ProtoReflect.Descriptor instead.
+func (*Alarm) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Alarm) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Alarm) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Alarm) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+type PoolInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Bits           uint32                 `protobuf:"varint,1,opt,name=bits,proto3" json:"bits,omitempty"`
+	SafePrime      bool                   `protobuf:"varint,2,opt,name=safe_prime,json=safePrime,proto3" json:"safe_prime,omitempty"`
+	Available      uint32                 `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`                                   // Current pool size
+	TargetSize     uint32                 `protobuf:"varint,4,opt,name=target_size,json=targetSize,proto3" json:"target_size,omitempty"`               // Target pool size
+	Generating     uint32                 `protobuf:"varint,5,opt,name=generating,proto3" json:"generating,omitempty"`                                 // Currently being generated
+	LastRefillTime int64                  `protobuf:"varint,6,opt,name=last_refill_time,json=lastRefillTime,proto3" json:"last_refill_time,omitempty"` // Unix timestamp
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PoolInfo) Reset() {
+	*x = PoolInfo{}
+	mi := &file_proto_prime_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolInfo) ProtoMessage() {}
+
+func (x *PoolInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolInfo.ProtoReflect.Descriptor instead.
+func (*PoolInfo) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PoolInfo) GetBits() uint32 {
+	if x != nil {
+		return x.Bits
+	}
+	return 0
+}
+
+func (x *PoolInfo) GetSafePrime() bool {
+	if x != nil {
+		return x.SafePrime
+	}
+	return false
+}
+
+func (x *PoolInfo) GetAvailable() uint32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *PoolInfo) GetTargetSize() uint32 {
+	if x != nil {
+		return x.TargetSize
+	}
+	return 0
+}
+
+func (x *PoolInfo) GetGenerating() uint32 {
+	if x != nil {
+		return x.Generating
+	}
+	return 0
+}
+
+func (x *PoolInfo) GetLastRefillTime() int64 {
+	if x != nil {
+		return x.LastRefillTime
+	}
+	return 0
+}
+
+type PullSurplusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"` // Maximum number of items wanted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullSurplusRequest) Reset() {
+	*x = PullSurplusRequest{}
+	mi := &file_proto_prime_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullSurplusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullSurplusRequest) ProtoMessage() {}
+
+func (x *PullSurplusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullSurplusRequest.ProtoReflect.Descriptor instead.
+func (*PullSurplusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PullSurplusRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type PullSurplusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Params        []*PreParamsData       `protobuf:"bytes,1,rep,name=params,proto3" json:"params,omitempty"` // May be empty if the donor has no surplus
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PullSurplusResponse) Reset() {
+	*x = PullSurplusResponse{}
+	mi := &file_proto_prime_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PullSurplusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PullSurplusResponse) ProtoMessage() {}
+
+func (x *PullSurplusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PullSurplusResponse.ProtoReflect.Descriptor instead.
+func (*PullSurplusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PullSurplusResponse) GetParams() []*PreParamsData {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type PoolPressure struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Desired               uint32                 `protobuf:"varint,1,opt,name=desired,proto3" json:"desired,omitempty"`                                                               // Min pool size
+	Actual                uint32                 `protobuf:"varint,2,opt,name=actual,proto3" json:"actual,omitempty"`                                                                 // Current pool size
+	Deficit               uint32                 `protobuf:"varint,3,opt,name=deficit,proto3" json:"deficit,omitempty"`                                                               // Items missing to reach desired (generation backlog)
+	FillRatio             float64                `protobuf:"fixed64,4,opt,name=fill_ratio,json=fillRatio,proto3" json:"fill_ratio,omitempty"`                                         // actual / desired
+	InFlight              uint32                 `protobuf:"varint,5,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`                                             // Items currently being generated
+	SupplyRatePerMin      float64                `protobuf:"fixed64,6,opt,name=supply_rate_per_min,json=supplyRatePerMin,proto3" json:"supply_rate_per_min,omitempty"`                // Items added per minute (recent window)
+	ConsumptionRatePerMin float64                `protobuf:"fixed64,7,opt,name=consumption_rate_per_min,json=consumptionRatePerMin,proto3" json:"consumption_rate_per_min,omitempty"` // Items removed per minute (recent window)
+	NetRatePerMin         float64                `protobuf:"fixed64,8,opt,name=net_rate_per_min,json=netRatePerMin,proto3" json:"net_rate_per_min,omitempty"`                         // Negative means the pool is draining
+	CapacityPerMin        float64                `protobuf:"fixed64,9,opt,name=capacity_per_min,json=capacityPerMin,proto3" json:"capacity_per_min,omitempty"`                        // Generation capacity at configured concurrency
+	EtaSeconds            int64                  `protobuf:"varint,10,opt,name=eta_seconds,json=etaSeconds,proto3" json:"eta_seconds,omitempty"`                                      // Time to clear the deficit (-1 if unknown)
+	Pressure              float64                `protobuf:"fixed64,11,opt,name=pressure,proto3" json:"pressure,omitempty"`                                                           // deficit/desired plus draining rate relative to capacity
+	AverageGenerationMs   int64                  `protobuf:"varint,12,opt,name=average_generation_ms,json=averageGenerationMs,proto3" json:"average_generation_ms,omitempty"`
+	InstanceId            string                 `protobuf:"bytes,13,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *PoolPressure) Reset() {
+	*x = PoolPressure{}
+	mi := &file_proto_prime_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolPressure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolPressure) ProtoMessage() {}
+
+func (x *PoolPressure) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolPressure.ProtoReflect.Descriptor instead.
+func (*PoolPressure) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PoolPressure) GetDesired() uint32 {
+	if x != nil {
+		return x.Desired
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetActual() uint32 {
+	if x != nil {
+		return x.Actual
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetDeficit() uint32 {
+	if x != nil {
+		return x.Deficit
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetFillRatio() float64 {
+	if x != nil {
+		return x.FillRatio
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetInFlight() uint32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetSupplyRatePerMin() float64 {
+	if x != nil {
+		return x.SupplyRatePerMin
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetConsumptionRatePerMin() float64 {
+	if x != nil {
+		return x.ConsumptionRatePerMin
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetNetRatePerMin() float64 {
+	if x != nil {
+		return x.NetRatePerMin
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetCapacityPerMin() float64 {
+	if x != nil {
+		return x.CapacityPerMin
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetEtaSeconds() int64 {
+	if x != nil {
+		return x.EtaSeconds
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetPressure() float64 {
+	if x != nil {
+		return x.Pressure
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetAverageGenerationMs() int64 {
+	if x != nil {
+		return x.AverageGenerationMs
+	}
+	return 0
+}
+
+func (x *PoolPressure) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+type GetErrorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinSeverity   ErrorSeverity          `protobuf:"varint,1,opt,name=min_severity,json=minSeverity,proto3,enum=prime.ErrorSeverity" json:"min_severity,omitempty"` // Only entries at least this severe
+	Component     string                 `protobuf:"bytes,2,opt,name=component,proto3" json:"component,omitempty"`                                                  // Only entries from this component (e.g. generator, persistence, peer)
+	Limit         uint32                 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`                                                         // Maximum entries to return (0: all retained)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetErrorsRequest) Reset() {
+	*x = GetErrorsRequest{}
+	mi := &file_proto_prime_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetErrorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetErrorsRequest) ProtoMessage() {}
+
+func (x *GetErrorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetErrorsRequest.ProtoReflect.Descriptor instead.
+func (*GetErrorsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetErrorsRequest) GetMinSeverity() ErrorSeverity {
+	if x != nil {
+		return x.MinSeverity
+	}
+	return ErrorSeverity_ERROR_SEVERITY_UNSPECIFIED
+}
+
+func (x *GetErrorsRequest) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *GetErrorsRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ErrorEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          int64                  `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"` // Unix timestamp
+	Severity      ErrorSeverity          `protobuf:"varint,2,opt,name=severity,proto3,enum=prime.ErrorSeverity" json:"severity,omitempty"`
+	Component     string                 `protobuf:"bytes,3,opt,name=component,proto3" json:"component,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Context       map[string]string      `protobuf:"bytes,5,rep,name=context,proto3" json:"context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorEntry) Reset() {
+	*x = ErrorEntry{}
+	mi := &file_proto_prime_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorEntry) ProtoMessage() {}
+
+func (x *ErrorEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorEntry.ProtoReflect.Descriptor instead.
+func (*ErrorEntry) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ErrorEntry) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *ErrorEntry) GetSeverity() ErrorSeverity {
+	if x != nil {
+		return x.Severity
+	}
+	return ErrorSeverity_ERROR_SEVERITY_UNSPECIFIED
+}
+
+func (x *ErrorEntry) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *ErrorEntry) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ErrorEntry) GetContext() map[string]string {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+type GetErrorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Errors        []*ErrorEntry          `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetErrorsResponse) Reset() {
+	*x = GetErrorsResponse{}
+	mi := &file_proto_prime_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetErrorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetErrorsResponse) ProtoMessage() {}
+
+func (x *GetErrorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetErrorsResponse.ProtoReflect.Descriptor instead.
+func (*GetErrorsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetErrorsResponse) GetErrors() []*ErrorEntry {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type SetMaintenanceRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Enabled             bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	DrainTimeoutSeconds uint32                 `protobuf:"varint,2,opt,name=drain_timeout_seconds,json=drainTimeoutSeconds,proto3" json:"drain_timeout_seconds,omitempty"` // When enabling, wait up to this long for in-flight requests (0: don't wait)
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SetMaintenanceRequest) Reset() {
+	*x = SetMaintenanceRequest{}
+	mi := &file_proto_prime_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetMaintenanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetMaintenanceRequest) ProtoMessage() {}
+
+func (x *SetMaintenanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetMaintenanceRequest.ProtoReflect.Descriptor instead.
+func (*SetMaintenanceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetMaintenanceRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SetMaintenanceRequest) GetDrainTimeoutSeconds() uint32 {
+	if x != nil {
+		return x.DrainTimeoutSeconds
+	}
+	return 0
+}
+
+type MaintenanceStatus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Enabled        bool                   `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	ActiveRequests uint32                 `protobuf:"varint,2,opt,name=active_requests,json=activeRequests,proto3" json:"active_requests,omitempty"` // GetPreParams calls still in progress
+	Drained        bool                   `protobuf:"varint,3,opt,name=drained,proto3" json:"drained,omitempty"`                                     // Enabled and no requests in progress
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MaintenanceStatus) Reset() {
+	*x = MaintenanceStatus{}
+	mi := &file_proto_prime_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MaintenanceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MaintenanceStatus) ProtoMessage() {}
+
+func (x *MaintenanceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MaintenanceStatus.ProtoReflect.Descriptor instead.
+func (*MaintenanceStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *MaintenanceStatus) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *MaintenanceStatus) GetActiveRequests() uint32 {
+	if x != nil {
+		return x.ActiveRequests
+	}
+	return 0
+}
+
+func (x *MaintenanceStatus) GetDrained() bool {
+	if x != nil {
+		return x.Drained
+	}
+	return false
+}
+
+type SetGenerationPauseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paused        bool                   `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetGenerationPauseRequest) Reset() {
+	*x = SetGenerationPauseRequest{}
+	mi := &file_proto_prime_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetGenerationPauseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetGenerationPauseRequest) ProtoMessage() {}
+
+func (x *SetGenerationPauseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetGenerationPauseRequest.ProtoReflect.Descriptor instead.
+func (*SetGenerationPauseRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SetGenerationPauseRequest) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+type GenerationPauseStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Paused        bool                   `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	InFlight      uint32                 `protobuf:"varint,2,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"` // Items being generated, including on-demand ones
+	Parked        uint32                 `protobuf:"varint,3,opt,name=parked,proto3" json:"parked,omitempty"`                     // Generations waiting at a yield point
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerationPauseStatus) Reset() {
+	*x = GenerationPauseStatus{}
+	mi := &file_proto_prime_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerationPauseStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerationPauseStatus) ProtoMessage() {}
+
+func (x *GenerationPauseStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerationPauseStatus.ProtoReflect.Descriptor instead.
+func (*GenerationPauseStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GenerationPauseStatus) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *GenerationPauseStatus) GetInFlight() uint32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *GenerationPauseStatus) GetParked() uint32 {
+	if x != nil {
+		return x.Parked
+	}
+	return 0
+}
+
+type FillPoolRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        uint32                 `protobuf:"varint,1,opt,name=target,proto3" json:"target,omitempty"`           // Pool size to reach (0: max_pool_size)
+	Concurrency   uint32                 `protobuf:"varint,2,opt,name=concurrency,proto3" json:"concurrency,omitempty"` // Generation workers (0: max_concurrent; capped at the CPU count)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FillPoolRequest) Reset() {
+	*x = FillPoolRequest{}
+	mi := &file_proto_prime_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FillPoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FillPoolRequest) ProtoMessage() {}
+
+func (x *FillPoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FillPoolRequest.ProtoReflect.Descriptor instead.
+func (*FillPoolRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *FillPoolRequest) GetTarget() uint32 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *FillPoolRequest) GetConcurrency() uint32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+type FillPoolResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        uint32                 `protobuf:"varint,1,opt,name=target,proto3" json:"target,omitempty"`                     // Effective target
+	Concurrency   uint32                 `protobuf:"varint,2,opt,name=concurrency,proto3" json:"concurrency,omitempty"`           // Effective workers
+	PoolSize      uint32                 `protobuf:"varint,3,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"` // Items in the pool when the fill started
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FillPoolResponse) Reset() {
+	*x = FillPoolResponse{}
+	mi := &file_proto_prime_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FillPoolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FillPoolResponse) ProtoMessage() {}
+
+func (x *FillPoolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FillPoolResponse.ProtoReflect.Descriptor instead.
+func (*FillPoolResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *FillPoolResponse) GetTarget() uint32 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *FillPoolResponse) GetConcurrency() uint32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+func (x *FillPoolResponse) GetPoolSize() uint32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+type ClearPoolRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PauseGeneration bool                   `protobuf:"varint,1,opt,name=pause_generation,json=pauseGeneration,proto3" json:"pause_generation,omitempty"` // Pause background generation first, so the pool stays empty
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ClearPoolRequest) Reset() {
+	*x = ClearPoolRequest{}
+	mi := &file_proto_prime_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearPoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearPoolRequest) ProtoMessage() {}
+
+func (x *ClearPoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearPoolRequest.ProtoReflect.Descriptor instead.
+func (*ClearPoolRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ClearPoolRequest) GetPauseGeneration() bool {
+	if x != nil {
+		return x.PauseGeneration
+	}
+	return false
+}
+
+type ClearPoolResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Cleared          uint32                 `protobuf:"varint,1,opt,name=cleared,proto3" json:"cleared,omitempty"` // Items discarded
+	GenerationPaused bool                   `protobuf:"varint,2,opt,name=generation_paused,json=generationPaused,proto3" json:"generation_paused,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ClearPoolResponse) Reset() {
+	*x = ClearPoolResponse{}
+	mi := &file_proto_prime_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearPoolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearPoolResponse) ProtoMessage() {}
+
+func (x *ClearPoolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearPoolResponse.ProtoReflect.Descriptor instead.
+func (*ClearPoolResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ClearPoolResponse) GetCleared() uint32 {
+	if x != nil {
+		return x.Cleared
+	}
+	return 0
+}
+
+func (x *ClearPoolResponse) GetGenerationPaused() bool {
+	if x != nil {
+		return x.GenerationPaused
+	}
+	return false
+}
+
+// Fields left 0 keep their current value
+type SetPoolLimitsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MinPoolSize     uint32                 `protobuf:"varint,1,opt,name=min_pool_size,json=minPoolSize,proto3" json:"min_pool_size,omitempty"`
+	MaxPoolSize     uint32                 `protobuf:"varint,2,opt,name=max_pool_size,json=maxPoolSize,proto3" json:"max_pool_size,omitempty"`
+	RefillThreshold uint32                 `protobuf:"varint,3,opt,name=refill_threshold,json=refillThreshold,proto3" json:"refill_threshold,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetPoolLimitsRequest) Reset() {
+	*x = SetPoolLimitsRequest{}
+	mi := &file_proto_prime_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetPoolLimitsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPoolLimitsRequest) ProtoMessage() {}
+
+func (x *SetPoolLimitsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPoolLimitsRequest.ProtoReflect.Descriptor instead.
+func (*SetPoolLimitsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SetPoolLimitsRequest) GetMinPoolSize() uint32 {
+	if x != nil {
+		return x.MinPoolSize
+	}
+	return 0
+}
+
+func (x *SetPoolLimitsRequest) GetMaxPoolSize() uint32 {
+	if x != nil {
+		return x.MaxPoolSize
+	}
+	return 0
+}
+
+func (x *SetPoolLimitsRequest) GetRefillThreshold() uint32 {
+	if x != nil {
+		return x.RefillThreshold
+	}
+	return 0
+}
+
+type PoolLimits struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MinPoolSize     uint32                 `protobuf:"varint,1,opt,name=min_pool_size,json=minPoolSize,proto3" json:"min_pool_size,omitempty"`
+	MaxPoolSize     uint32                 `protobuf:"varint,2,opt,name=max_pool_size,json=maxPoolSize,proto3" json:"max_pool_size,omitempty"`
+	RefillThreshold uint32                 `protobuf:"varint,3,opt,name=refill_threshold,json=refillThreshold,proto3" json:"refill_threshold,omitempty"`
+	PoolSize        uint32                 `protobuf:"varint,4,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PoolLimits) Reset() {
+	*x = PoolLimits{}
+	mi := &file_proto_prime_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolLimits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolLimits) ProtoMessage() {}
+
+func (x *PoolLimits) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolLimits.ProtoReflect.Descriptor instead.
+func (*PoolLimits) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PoolLimits) GetMinPoolSize() uint32 {
+	if x != nil {
+		return x.MinPoolSize
+	}
+	return 0
+}
+
+func (x *PoolLimits) GetMaxPoolSize() uint32 {
+	if x != nil {
+		return x.MaxPoolSize
+	}
+	return 0
+}
+
+func (x *PoolLimits) GetRefillThreshold() uint32 {
+	if x != nil {
+		return x.RefillThreshold
+	}
+	return 0
+}
+
+func (x *PoolLimits) GetPoolSize() uint32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+type FreezeStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Frozen        bool                   `protobuf:"varint,1,opt,name=frozen,proto3" json:"frozen,omitempty"`
+	Anomaly       string                 `protobuf:"bytes,2,opt,name=anomaly,proto3" json:"anomaly,omitempty"` // duplicate_params, validation_failures or audit_failure
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`   // Details of the anomaly
+	Since         int64                  `protobuf:"varint,4,opt,name=since,proto3" json:"since,omitempty"`    // Unix time the pool froze
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeStatus) Reset() {
+	*x = FreezeStatus{}
+	mi := &file_proto_prime_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeStatus) ProtoMessage() {}
+
+func (x *FreezeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeStatus.ProtoReflect.Descriptor instead.
+func (*FreezeStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *FreezeStatus) GetFrozen() bool {
+	if x != nil {
+		return x.Frozen
+	}
+	return false
+}
+
+func (x *FreezeStatus) GetAnomaly() string {
+	if x != nil {
+		return x.Anomaly
+	}
+	return ""
+}
+
+func (x *FreezeStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FreezeStatus) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+type ReplicaStatus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Address        string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"` // Empty for the answering instance itself
+	InstanceId     string                 `protobuf:"bytes,2,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	Self           bool                   `protobuf:"varint,3,opt,name=self,proto3" json:"self,omitempty"` // The instance that answered ListPeers
+	Reachable      bool                   `protobuf:"varint,4,opt,name=reachable,proto3" json:"reachable,omitempty"`
+	Error          string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`      // Why the replica could not be queried
+	Healthy        bool                   `protobuf:"varint,6,opt,name=healthy,proto3" json:"healthy,omitempty"` // Passing HealthCheck (false in maintenance)
+	PoolSize       uint32                 `protobuf:"varint,7,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"`
+	TargetSize     uint32                 `protobuf:"varint,8,opt,name=target_size,json=targetSize,proto3" json:"target_size,omitempty"` // Min pool size
+	TotalGenerated int64                  `protobuf:"varint,9,opt,name=total_generated,json=totalGenerated,proto3" json:"total_generated,omitempty"`
+	TotalServed    int64                  `protobuf:"varint,10,opt,name=total_served,json=totalServed,proto3" json:"total_served,omitempty"`
+	Maintenance    bool                   `protobuf:"varint,11,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	Region         string                 `protobuf:"bytes,12,opt,name=region,proto3" json:"region,omitempty"` // Locality the replica reports (empty: not configured)
+	Zone           string                 `protobuf:"bytes,13,opt,name=zone,proto3" json:"zone,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReplicaStatus) Reset() {
+	*x = ReplicaStatus{}
+	mi := &file_proto_prime_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplicaStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplicaStatus) ProtoMessage() {}
+
+func (x *ReplicaStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplicaStatus.ProtoReflect.Descriptor instead.
+func (*ReplicaStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReplicaStatus) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *ReplicaStatus) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *ReplicaStatus) GetSelf() bool {
+	if x != nil {
+		return x.Self
+	}
+	return false
+}
+
+func (x *ReplicaStatus) GetReachable() bool {
+	if x != nil {
+		return x.Reachable
+	}
+	return false
+}
+
+func (x *ReplicaStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ReplicaStatus) GetHealthy() bool {
+	if x != nil {
+		return x.Healthy
+	}
+	return false
+}
+
+func (x *ReplicaStatus) GetPoolSize() uint32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetTargetSize() uint32 {
+	if x != nil {
+		return x.TargetSize
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetTotalGenerated() int64 {
+	if x != nil {
+		return x.TotalGenerated
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetTotalServed() int64 {
+	if x != nil {
+		return x.TotalServed
+	}
+	return 0
+}
+
+func (x *ReplicaStatus) GetMaintenance() bool {
+	if x != nil {
+		return x.Maintenance
+	}
+	return false
+}
+
+func (x *ReplicaStatus) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *ReplicaStatus) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+type FleetStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Replicas      []*ReplicaStatus       `protobuf:"bytes,1,rep,name=replicas,proto3" json:"replicas,omitempty"` // Self first, then peers in configured order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FleetStatus) Reset() {
+	*x = FleetStatus{}
+	mi := &file_proto_prime_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FleetStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FleetStatus) ProtoMessage() {}
+
+func (x *FleetStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FleetStatus.ProtoReflect.Descriptor instead.
+func (*FleetStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *FleetStatus) GetReplicas() []*ReplicaStatus {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+type SLOObjectiveStatus struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Name            string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Method          string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"` // RPC the objective covers
+	Class           string                 `protobuf:"bytes,3,opt,name=class,proto3" json:"class,omitempty"`   // pool_hit, generated, pool_miss or empty for every call
+	ThresholdMs     int64                  `protobuf:"varint,4,opt,name=threshold_ms,json=thresholdMs,proto3" json:"threshold_ms,omitempty"`
+	Target          float64                `protobuf:"fixed64,5,opt,name=target,proto3" json:"target,omitempty"`                                                                                                   // Fraction of calls that must complete within threshold_ms
+	WindowSeconds   int64                  `protobuf:"varint,6,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`                                                                 // Compliance window
+	Total           int64                  `protobuf:"varint,7,opt,name=total,proto3" json:"total,omitempty"`                                                                                                      // Calls within the window
+	Good            int64                  `protobuf:"varint,8,opt,name=good,proto3" json:"good,omitempty"`                                                                                                        // Calls completing within threshold_ms
+	Compliance      float64                `protobuf:"fixed64,9,opt,name=compliance,proto3" json:"compliance,omitempty"`                                                                                           // good / total (1 without traffic)
+	BudgetRemaining float64                `protobuf:"fixed64,10,opt,name=budget_remaining,json=budgetRemaining,proto3" json:"budget_remaining,omitempty"`                                                         // Share of the error budget left (negative: objective missed)
+	BurnRates       map[string]float64     `protobuf:"bytes,11,rep,name=burn_rates,json=burnRates,proto3" json:"burn_rates,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"` // Budget burn rate per lookback ("5m", "1h", "6h"); 1 spends it exactly over the window
+	Met             bool                   `protobuf:"varint,12,opt,name=met,proto3" json:"met,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SLOObjectiveStatus) Reset() {
+	*x = SLOObjectiveStatus{}
+	mi := &file_proto_prime_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SLOObjectiveStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLOObjectiveStatus) ProtoMessage() {}
+
+func (x *SLOObjectiveStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLOObjectiveStatus.ProtoReflect.Descriptor instead.
+func (*SLOObjectiveStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *SLOObjectiveStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SLOObjectiveStatus) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *SLOObjectiveStatus) GetClass() string {
+	if x != nil {
+		return x.Class
+	}
+	return ""
+}
+
+func (x *SLOObjectiveStatus) GetThresholdMs() int64 {
+	if x != nil {
+		return x.ThresholdMs
+	}
+	return 0
+}
+
+func (x *SLOObjectiveStatus) GetTarget() float64 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *SLOObjectiveStatus) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+func (x *SLOObjectiveStatus) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SLOObjectiveStatus) GetGood() int64 {
+	if x != nil {
+		return x.Good
+	}
+	return 0
+}
+
+func (x *SLOObjectiveStatus) GetCompliance() float64 {
+	if x != nil {
+		return x.Compliance
+	}
+	return 0
+}
+
+func (x *SLOObjectiveStatus) GetBudgetRemaining() float64 {
+	if x != nil {
+		return x.BudgetRemaining
+	}
+	return 0
+}
+
+func (x *SLOObjectiveStatus) GetBurnRates() map[string]float64 {
+	if x != nil {
+		return x.BurnRates
+	}
+	return nil
+}
+
+func (x *SLOObjectiveStatus) GetMet() bool {
+	if x != nil {
+		return x.Met
+	}
+	return false
+}
+
+type SLOStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Objectives    []*SLOObjectiveStatus  `protobuf:"bytes,1,rep,name=objectives,proto3" json:"objectives,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SLOStatus) Reset() {
+	*x = SLOStatus{}
+	mi := &file_proto_prime_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SLOStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SLOStatus) ProtoMessage() {}
+
+func (x *SLOStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SLOStatus.ProtoReflect.Descriptor instead.
+func (*SLOStatus) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SLOStatus) GetObjectives() []*SLOObjectiveStatus {
+	if x != nil {
+		return x.Objectives
+	}
+	return nil
+}
+
+type ListPoolItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PageSize      uint32                 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // Items per page (0: 100, at most 1000)
+	PageToken     string                 `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page; empty for the first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoolItemsRequest) Reset() {
+	*x = ListPoolItemsRequest{}
+	mi := &file_proto_prime_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoolItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoolItemsRequest) ProtoMessage() {}
+
+func (x *ListPoolItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoolItemsRequest.ProtoReflect.Descriptor instead.
+func (*ListPoolItemsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListPoolItemsRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListPoolItemsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+// PoolItem describes a pooled item without its secret material
+type PoolItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Hex of the first 16 bytes of SHA-256(Paillier N || NTildei), big-endian
+	// bytes; computable by clients for the items they receive
+	Fingerprint          string      `protobuf:"bytes,1,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	GeneratedAt          int64       `protobuf:"varint,2,opt,name=generated_at,json=generatedAt,proto3" json:"generated_at,omitempty"` // Unix timestamp
+	AgeSeconds           int64       `protobuf:"varint,3,opt,name=age_seconds,json=ageSeconds,proto3" json:"age_seconds,omitempty"`
+	GenerationDurationMs int64       `protobuf:"varint,4,opt,name=generation_duration_ms,json=generationDurationMs,proto3" json:"generation_duration_ms,omitempty"`
+	Provenance           *Provenance `protobuf:"bytes,5,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	Stale                bool        `protobuf:"varint,6,opt,name=stale,proto3" json:"stale,omitempty"`        // Older than the service's max_age
+	Assurance            string      `protobuf:"bytes,7,opt,name=assurance,proto3" json:"assurance,omitempty"` // standard or audited
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *PoolItem) Reset() {
+	*x = PoolItem{}
+	mi := &file_proto_prime_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolItem) ProtoMessage() {}
+
+func (x *PoolItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolItem.ProtoReflect.Descriptor instead.
+func (*PoolItem) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *PoolItem) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *PoolItem) GetGeneratedAt() int64 {
+	if x != nil {
+		return x.GeneratedAt
+	}
+	return 0
+}
+
+func (x *PoolItem) GetAgeSeconds() int64 {
+	if x != nil {
+		return x.AgeSeconds
+	}
+	return 0
+}
+
+func (x *PoolItem) GetGenerationDurationMs() int64 {
+	if x != nil {
+		return x.GenerationDurationMs
+	}
+	return 0
+}
+
+func (x *PoolItem) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *PoolItem) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *PoolItem) GetAssurance() string {
+	if x != nil {
+		return x.Assurance
+	}
+	return ""
+}
+
+type PinItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fingerprint   string                 `protobuf:"bytes,1,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"` // As listed by ListPoolItems, or the item's sequence number
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`           // Recorded with the pin, e.g. an incident ticket
+	Unpin         bool                   `protobuf:"varint,3,opt,name=unpin,proto3" json:"unpin,omitempty"`            // Return a pinned item to the pool instead
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinItemRequest) Reset() {
+	*x = PinItemRequest{}
+	mi := &file_proto_prime_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinItemRequest) ProtoMessage() {}
+
+func (x *PinItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinItemRequest.ProtoReflect.Descriptor instead.
+func (*PinItemRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PinItemRequest) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+func (x *PinItemRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PinItemRequest) GetUnpin() bool {
+	if x != nil {
+		return x.Unpin
+	}
+	return false
+}
+
+type CollectDiagnosticsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	LogLines       uint32                 `protobuf:"varint,1,opt,name=log_lines,json=logLines,proto3" json:"log_lines,omitempty"`                   // Most recent log lines to include (0: all kept, see logging.keep_lines)
+	HistorySeconds int64                  `protobuf:"varint,2,opt,name=history_seconds,json=historySeconds,proto3" json:"history_seconds,omitempty"` // Usage history to include (0: 7 days)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CollectDiagnosticsRequest) Reset() {
+	*x = CollectDiagnosticsRequest{}
+	mi := &file_proto_prime_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectDiagnosticsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectDiagnosticsRequest) ProtoMessage() {}
+
+func (x *CollectDiagnosticsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectDiagnosticsRequest.ProtoReflect.Descriptor instead.
+func (*CollectDiagnosticsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *CollectDiagnosticsRequest) GetLogLines() uint32 {
+	if x != nil {
+		return x.LogLines
+	}
+	return 0
+}
+
+func (x *CollectDiagnosticsRequest) GetHistorySeconds() int64 {
+	if x != nil {
+		return x.HistorySeconds
+	}
+	return 0
+}
+
+type DiagnosticsBundle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Archive       []byte                 `protobuf:"bytes,1,opt,name=archive,proto3" json:"archive,omitempty"`   // .tar.gz
+	Filename      string                 `protobuf:"bytes,2,opt,name=filename,proto3" json:"filename,omitempty"` // Suggested name, e.g. prime-diag-<instance>-<time>.tar.gz
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiagnosticsBundle) Reset() {
+	*x = DiagnosticsBundle{}
+	mi := &file_proto_prime_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiagnosticsBundle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiagnosticsBundle) ProtoMessage() {}
+
+func (x *DiagnosticsBundle) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiagnosticsBundle.ProtoReflect.Descriptor instead.
+func (*DiagnosticsBundle) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DiagnosticsBundle) GetArchive() []byte {
+	if x != nil {
+		return x.Archive
+	}
+	return nil
+}
+
+func (x *DiagnosticsBundle) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+// A pool item set aside by PinItem
+type PinnedItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Item          *PoolItem              `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	PinnedAt      int64                  `protobuf:"varint,2,opt,name=pinned_at,json=pinnedAt,proto3" json:"pinned_at,omitempty"` // Unix timestamp
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinnedItem) Reset() {
+	*x = PinnedItem{}
+	mi := &file_proto_prime_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinnedItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinnedItem) ProtoMessage() {}
+
+func (x *PinnedItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinnedItem.ProtoReflect.Descriptor instead.
+func (*PinnedItem) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *PinnedItem) GetItem() *PoolItem {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *PinnedItem) GetPinnedAt() int64 {
+	if x != nil {
+		return x.PinnedAt
+	}
+	return 0
+}
+
+func (x *PinnedItem) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ListPoolItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*PoolItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	Total         uint32                 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`                                       // Items currently in the pool
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPoolItemsResponse) Reset() {
+	*x = ListPoolItemsResponse{}
+	mi := &file_proto_prime_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPoolItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPoolItemsResponse) ProtoMessage() {}
+
+func (x *ListPoolItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPoolItemsResponse.ProtoReflect.Descriptor instead.
+func (*ListPoolItemsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ListPoolItemsResponse) GetItems() []*PoolItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ListPoolItemsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListPoolItemsResponse) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type ForecastPoolRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EventItems      uint32                 `protobuf:"varint,1,opt,name=event_items,json=eventItems,proto3" json:"event_items,omitempty"`                // Items the planned event will take (0: runway only)
+	EventAt         int64                  `protobuf:"varint,2,opt,name=event_at,json=eventAt,proto3" json:"event_at,omitempty"`                         // Unix time the event starts (0: now)
+	LookbackSeconds int64                  `protobuf:"varint,3,opt,name=lookback_seconds,json=lookbackSeconds,proto3" json:"lookback_seconds,omitempty"` // Usage history to take rates from (0: 7 days)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ForecastPoolRequest) Reset() {
+	*x = ForecastPoolRequest{}
+	mi := &file_proto_prime_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForecastPoolRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastPoolRequest) ProtoMessage() {}
+
+func (x *ForecastPoolRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastPoolRequest.ProtoReflect.Descriptor instead.
+func (*ForecastPoolRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *ForecastPoolRequest) GetEventItems() uint32 {
+	if x != nil {
+		return x.EventItems
+	}
+	return 0
+}
+
+func (x *ForecastPoolRequest) GetEventAt() int64 {
+	if x != nil {
+		return x.EventAt
+	}
+	return 0
+}
+
+func (x *ForecastPoolRequest) GetLookbackSeconds() int64 {
+	if x != nil {
+		return x.LookbackSeconds
+	}
+	return 0
+}
+
+type EventForecast struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Items           uint32                 `protobuf:"varint,1,opt,name=items,proto3" json:"items,omitempty"`
+	At              int64                  `protobuf:"varint,2,opt,name=at,proto3" json:"at,omitempty"`                                                   // Unix time
+	BaselineServed  float64                `protobuf:"fixed64,3,opt,name=baseline_served,json=baselineServed,proto3" json:"baseline_served,omitempty"`    // Items expected to be served while the backlog is generated
+	Backlog         uint32                 `protobuf:"varint,4,opt,name=backlog,proto3" json:"backlog,omitempty"`                                         // Items to generate beyond the current pool (event items plus baseline)
+	GenerationHours float64                `protobuf:"fixed64,5,opt,name=generation_hours,json=generationHours,proto3" json:"generation_hours,omitempty"` // Time to generate the backlog at the configured concurrency (-1: unknown)
+	StartBy         int64                  `protobuf:"varint,6,opt,name=start_by,json=startBy,proto3" json:"start_by,omitempty"`                          // Unix time generation must start by (0: nothing to generate or unknown)
+	Feasible        bool                   `protobuf:"varint,7,opt,name=feasible,proto3" json:"feasible,omitempty"`                                       // The backlog can be generated in time at the configured concurrency
+	WorkersNeeded   uint32                 `protobuf:"varint,8,opt,name=workers_needed,json=workersNeeded,proto3" json:"workers_needed,omitempty"`        // Concurrency needed to be ready in time (0: unknown)
+	Recommendations []string               `protobuf:"bytes,9,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *EventForecast) Reset() {
+	*x = EventForecast{}
+	mi := &file_proto_prime_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventForecast) ProtoMessage() {}
+
+func (x *EventForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventForecast.ProtoReflect.Descriptor instead.
+func (*EventForecast) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *EventForecast) GetItems() uint32 {
+	if x != nil {
+		return x.Items
+	}
+	return 0
+}
+
+func (x *EventForecast) GetAt() int64 {
+	if x != nil {
+		return x.At
+	}
+	return 0
+}
+
+func (x *EventForecast) GetBaselineServed() float64 {
+	if x != nil {
+		return x.BaselineServed
+	}
+	return 0
+}
+
+func (x *EventForecast) GetBacklog() uint32 {
+	if x != nil {
+		return x.Backlog
+	}
+	return 0
+}
+
+func (x *EventForecast) GetGenerationHours() float64 {
+	if x != nil {
+		return x.GenerationHours
+	}
+	return 0
+}
+
+func (x *EventForecast) GetStartBy() int64 {
+	if x != nil {
+		return x.StartBy
+	}
+	return 0
+}
+
+func (x *EventForecast) GetFeasible() bool {
+	if x != nil {
+		return x.Feasible
+	}
+	return false
+}
+
+func (x *EventForecast) GetWorkersNeeded() uint32 {
+	if x != nil {
+		return x.WorkersNeeded
+	}
+	return 0
+}
+
+func (x *EventForecast) GetRecommendations() []string {
+	if x != nil {
+		return x.Recommendations
+	}
+	return nil
+}
+
+type PoolForecast struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	InstanceId          string                 `protobuf:"bytes,1,opt,name=instance_id,json=instanceId,proto3" json:"instance_id,omitempty"`
+	PoolSize            uint32                 `protobuf:"varint,2,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"`
+	MinPoolSize         uint32                 `protobuf:"varint,3,opt,name=min_pool_size,json=minPoolSize,proto3" json:"min_pool_size,omitempty"`
+	MaxPoolSize         uint32                 `protobuf:"varint,4,opt,name=max_pool_size,json=maxPoolSize,proto3" json:"max_pool_size,omitempty"`
+	HistoryHours        float64                `protobuf:"fixed64,5,opt,name=history_hours,json=historyHours,proto3" json:"history_hours,omitempty"` // History the rates are based on, at most the lookback
+	ServedPerDay        float64                `protobuf:"fixed64,6,opt,name=served_per_day,json=servedPerDay,proto3" json:"served_per_day,omitempty"`
+	PeakServedPerHour   int64                  `protobuf:"varint,7,opt,name=peak_served_per_hour,json=peakServedPerHour,proto3" json:"peak_served_per_hour,omitempty"`
+	GeneratedPerDay     float64                `protobuf:"fixed64,8,opt,name=generated_per_day,json=generatedPerDay,proto3" json:"generated_per_day,omitempty"`
+	Workers             uint32                 `protobuf:"varint,9,opt,name=workers,proto3" json:"workers,omitempty"`                                                        // Housekeeping generation concurrency
+	AverageGenerationMs int64                  `protobuf:"varint,10,opt,name=average_generation_ms,json=averageGenerationMs,proto3" json:"average_generation_ms,omitempty"`  // 0 if nothing was generated yet
+	CapacityPerDay      float64                `protobuf:"fixed64,11,opt,name=capacity_per_day,json=capacityPerDay,proto3" json:"capacity_per_day,omitempty"`                // Generation capacity (0: unknown)
+	RunwayDays          float64                `protobuf:"fixed64,12,opt,name=runway_days,json=runwayDays,proto3" json:"runway_days,omitempty"`                              // Days the pool lasts without generation (-1: no consumption)
+	SustainedRunwayDays float64                `protobuf:"fixed64,13,opt,name=sustained_runway_days,json=sustainedRunwayDays,proto3" json:"sustained_runway_days,omitempty"` // Days the pool lasts with generation at capacity (-1: never runs out)
+	Event               *EventForecast         `protobuf:"bytes,14,opt,name=event,proto3" json:"event,omitempty"`                                                            // Set when event_items was given
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *PoolForecast) Reset() {
+	*x = PoolForecast{}
+	mi := &file_proto_prime_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolForecast) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolForecast) ProtoMessage() {}
+
+func (x *PoolForecast) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolForecast.ProtoReflect.Descriptor instead.
+func (*PoolForecast) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *PoolForecast) GetInstanceId() string {
+	if x != nil {
+		return x.InstanceId
+	}
+	return ""
+}
+
+func (x *PoolForecast) GetPoolSize() uint32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetMinPoolSize() uint32 {
+	if x != nil {
+		return x.MinPoolSize
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetMaxPoolSize() uint32 {
+	if x != nil {
+		return x.MaxPoolSize
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetHistoryHours() float64 {
+	if x != nil {
+		return x.HistoryHours
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetServedPerDay() float64 {
+	if x != nil {
+		return x.ServedPerDay
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetPeakServedPerHour() int64 {
+	if x != nil {
+		return x.PeakServedPerHour
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetGeneratedPerDay() float64 {
+	if x != nil {
+		return x.GeneratedPerDay
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetWorkers() uint32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetAverageGenerationMs() int64 {
+	if x != nil {
+		return x.AverageGenerationMs
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetCapacityPerDay() float64 {
+	if x != nil {
+		return x.CapacityPerDay
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetRunwayDays() float64 {
+	if x != nil {
+		return x.RunwayDays
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetSustainedRunwayDays() float64 {
+	if x != nil {
+		return x.SustainedRunwayDays
+	}
+	return 0
+}
+
+func (x *PoolForecast) GetEvent() *EventForecast {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+type ListRefillCyclesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         uint32                 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"` // Latest cycles to return (0: all kept)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRefillCyclesRequest) Reset() {
+	*x = ListRefillCyclesRequest{}
+	mi := &file_proto_prime_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRefillCyclesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRefillCyclesRequest) ProtoMessage() {}
+
+func (x *ListRefillCyclesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRefillCyclesRequest.ProtoReflect.Descriptor instead.
+func (*ListRefillCyclesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListRefillCyclesRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type RefillCycle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Started       int64                  `protobuf:"varint,1,opt,name=started,proto3" json:"started,omitempty"` // Unix time
+	DurationMs    int64                  `protobuf:"varint,2,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	Mode          string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`     // refill, emergency or fill
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"` // Why it ran
+	Target        uint32                 `protobuf:"varint,5,opt,name=target,proto3" json:"target,omitempty"`
+	PoolSize      uint32                 `protobuf:"varint,6,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"` // Items in the pool when it started
+	Needed        uint32                 `protobuf:"varint,7,opt,name=needed,proto3" json:"needed,omitempty"`                     // Items it set out to generate
+	Generated     uint32                 `protobuf:"varint,8,opt,name=generated,proto3" json:"generated,omitempty"`               // Items it added to the pool
+	Failures      uint32                 `protobuf:"varint,9,opt,name=failures,proto3" json:"failures,omitempty"`
+	Workers       uint32                 `protobuf:"varint,10,opt,name=workers,proto3" json:"workers,omitempty"`
+	Interrupted   bool                   `protobuf:"varint,11,opt,name=interrupted,proto3" json:"interrupted,omitempty"` // Cut short by shutdown
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefillCycle) Reset() {
+	*x = RefillCycle{}
+	mi := &file_proto_prime_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefillCycle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefillCycle) ProtoMessage() {}
+
+func (x *RefillCycle) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefillCycle.ProtoReflect.Descriptor instead.
+func (*RefillCycle) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *RefillCycle) GetStarted() int64 {
+	if x != nil {
+		return x.Started
+	}
+	return 0
+}
+
+func (x *RefillCycle) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *RefillCycle) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *RefillCycle) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RefillCycle) GetTarget() uint32 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *RefillCycle) GetPoolSize() uint32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+func (x *RefillCycle) GetNeeded() uint32 {
+	if x != nil {
+		return x.Needed
+	}
+	return 0
+}
+
+func (x *RefillCycle) GetGenerated() uint32 {
+	if x != nil {
+		return x.Generated
+	}
+	return 0
+}
+
+func (x *RefillCycle) GetFailures() uint32 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *RefillCycle) GetWorkers() uint32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *RefillCycle) GetInterrupted() bool {
+	if x != nil {
+		return x.Interrupted
+	}
+	return false
+}
+
+type RefillCycleList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cycles        []*RefillCycle         `protobuf:"bytes,1,rep,name=cycles,proto3" json:"cycles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefillCycleList) Reset() {
+	*x = RefillCycleList{}
+	mi := &file_proto_prime_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefillCycleList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefillCycleList) ProtoMessage() {}
+
+func (x *RefillCycleList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefillCycleList.ProtoReflect.Descriptor instead.
+func (*RefillCycleList) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *RefillCycleList) GetCycles() []*RefillCycle {
+	if x != nil {
+		return x.Cycles
+	}
+	return nil
+}
+
+type RegisterWorkerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"` // Stable name of the worker, e.g. its pod name
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterWorkerRequest) Reset() {
+	*x = RegisterWorkerRequest{}
+	mi := &file_proto_prime_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterWorkerRequest) ProtoMessage() {}
+
+func (x *RegisterWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterWorkerRequest.ProtoReflect.Descriptor instead.
+func (*RegisterWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *RegisterWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type WorkerToken struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix time; register again before
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerToken) Reset() {
+	*x = WorkerToken{}
+	mi := &file_proto_prime_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerToken) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerToken) ProtoMessage() {}
+
+func (x *WorkerToken) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerToken.ProtoReflect.Descriptor instead.
+func (*WorkerToken) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *WorkerToken) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *WorkerToken) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type SubmitPreParamsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Params        []*PreParamsData       `protobuf:"bytes,1,rep,name=params,proto3" json:"params,omitempty"` // At most 100 per call
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitPreParamsRequest) Reset() {
+	*x = SubmitPreParamsRequest{}
+	mi := &file_proto_prime_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitPreParamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitPreParamsRequest) ProtoMessage() {}
+
+func (x *SubmitPreParamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitPreParamsRequest.ProtoReflect.Descriptor instead.
+func (*SubmitPreParamsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *SubmitPreParamsRequest) GetParams() []*PreParamsData {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type SubmitPreParamsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      uint32                 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`                 // Items added to the pool
+	PoolSize      uint32                 `protobuf:"varint,2,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"` // Pool size after adding them
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitPreParamsResponse) Reset() {
+	*x = SubmitPreParamsResponse{}
+	mi := &file_proto_prime_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitPreParamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitPreParamsResponse) ProtoMessage() {}
+
+func (x *SubmitPreParamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitPreParamsResponse.ProtoReflect.Descriptor instead.
+func (*SubmitPreParamsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *SubmitPreParamsResponse) GetAccepted() uint32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+func (x *SubmitPreParamsResponse) GetPoolSize() uint32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+type WorkerInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`                       // Remote address of the latest registration
+	Registered    int64                  `protobuf:"varint,3,opt,name=registered,proto3" json:"registered,omitempty"`                // Unix time the current token was issued
+	ExpiresAt     int64                  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Unix time the current token expires
+	Submitted     int64                  `protobuf:"varint,5,opt,name=submitted,proto3" json:"submitted,omitempty"`                  // Items accepted since start
+	Revoked       bool                   `protobuf:"varint,6,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	RevokedAt     int64                  `protobuf:"varint,7,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerInfo) Reset() {
+	*x = WorkerInfo{}
+	mi := &file_proto_prime_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerInfo) ProtoMessage() {}
+
+func (x *WorkerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerInfo.ProtoReflect.Descriptor instead.
+func (*WorkerInfo) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *WorkerInfo) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetRegistered() int64 {
+	if x != nil {
+		return x.Registered
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetSubmitted() int64 {
+	if x != nil {
+		return x.Submitted
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+func (x *WorkerInfo) GetRevokedAt() int64 {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return 0
+}
+
+type WorkerList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workers       []*WorkerInfo          `protobuf:"bytes,1,rep,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerList) Reset() {
+	*x = WorkerList{}
+	mi := &file_proto_prime_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerList) ProtoMessage() {}
+
+func (x *WorkerList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerList.ProtoReflect.Descriptor instead.
+func (*WorkerList) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *WorkerList) GetWorkers() []*WorkerInfo {
+	if x != nil {
+		return x.Workers
+	}
+	return nil
+}
+
+type RevokeWorkerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Reinstate     bool                   `protobuf:"varint,2,opt,name=reinstate,proto3" json:"reinstate,omitempty"` // Lift a revocation instead
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeWorkerRequest) Reset() {
+	*x = RevokeWorkerRequest{}
+	mi := &file_proto_prime_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeWorkerRequest) ProtoMessage() {}
+
+func (x *RevokeWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeWorkerRequest.ProtoReflect.Descriptor instead.
+func (*RevokeWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RevokeWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *RevokeWorkerRequest) GetReinstate() bool {
+	if x != nil {
+		return x.Reinstate
+	}
+	return false
+}
+
+type ExportItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         uint32                 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"` // Items to export, at most 100 per call
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportItemsRequest) Reset() {
+	*x = ExportItemsRequest{}
+	mi := &file_proto_prime_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportItemsRequest) ProtoMessage() {}
+
+func (x *ExportItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportItemsRequest.ProtoReflect.Descriptor instead.
+func (*ExportItemsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ExportItemsRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ExportItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Params        []*PreParamsData       `protobuf:"bytes,1,rep,name=params,proto3" json:"params,omitempty"`
+	PoolSize      uint32                 `protobuf:"varint,2,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"` // Items left in the pool
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportItemsResponse) Reset() {
+	*x = ExportItemsResponse{}
+	mi := &file_proto_prime_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportItemsResponse) ProtoMessage() {}
+
+func (x *ExportItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportItemsResponse.ProtoReflect.Descriptor instead.
+func (*ExportItemsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ExportItemsResponse) GetParams() []*PreParamsData {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+func (x *ExportItemsResponse) GetPoolSize() uint32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
 }
 
-func (x *PoolInfo) GetTargetSize() uint32 {
+type ImportItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Params        []*PreParamsData       `protobuf:"bytes,1,rep,name=params,proto3" json:"params,omitempty"` // At most 100 per call
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportItemsRequest) Reset() {
+	*x = ImportItemsRequest{}
+	mi := &file_proto_prime_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportItemsRequest) ProtoMessage() {}
+
+func (x *ImportItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[58]
 	if x != nil {
-		return x.TargetSize
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportItemsRequest.ProtoReflect.Descriptor instead.
+func (*ImportItemsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ImportItemsRequest) GetParams() []*PreParamsData {
+	if x != nil {
+		return x.Params
+	}
+	return nil
+}
+
+type ImportItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      uint32                 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"` // Added to the pool; the rest failed validation, repeated a pool item or found the pool full
+	PoolSize      uint32                 `protobuf:"varint,2,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportItemsResponse) Reset() {
+	*x = ImportItemsResponse{}
+	mi := &file_proto_prime_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportItemsResponse) ProtoMessage() {}
+
+func (x *ImportItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportItemsResponse.ProtoReflect.Descriptor instead.
+func (*ImportItemsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ImportItemsResponse) GetAccepted() uint32 {
+	if x != nil {
+		return x.Accepted
 	}
 	return 0
 }
 
-func (x *PoolInfo) GetGenerating() uint32 {
+func (x *ImportItemsResponse) GetPoolSize() uint32 {
 	if x != nil {
-		return x.Generating
+		return x.PoolSize
 	}
 	return 0
 }
 
-func (x *PoolInfo) GetLastRefillTime() int64 {
+type SnapshotInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TakenAt       int64                  `protobuf:"varint,2,opt,name=taken_at,json=takenAt,proto3" json:"taken_at,omitempty"` // Unix timestamp
+	Bytes         uint64                 `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	CommandError  string                 `protobuf:"bytes,4,opt,name=command_error,json=commandError,proto3" json:"command_error,omitempty"` // pool.snapshot_command failed; the snapshot was written
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotInfo) Reset() {
+	*x = SnapshotInfo{}
+	mi := &file_proto_prime_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotInfo) ProtoMessage() {}
+
+func (x *SnapshotInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[60]
 	if x != nil {
-		return x.LastRefillTime
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotInfo.ProtoReflect.Descriptor instead.
+func (*SnapshotInfo) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *SnapshotInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SnapshotInfo) GetTakenAt() int64 {
+	if x != nil {
+		return x.TakenAt
+	}
+	return 0
+}
+
+func (x *SnapshotInfo) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *SnapshotInfo) GetCommandError() string {
+	if x != nil {
+		return x.CommandError
+	}
+	return ""
+}
+
+type SnapshotList struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Snapshots      []*SnapshotInfo        `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+	Dir            string                 `protobuf:"bytes,2,opt,name=dir,proto3" json:"dir,omitempty"`
+	LastSnapshotAt int64                  `protobuf:"varint,3,opt,name=last_snapshot_at,json=lastSnapshotAt,proto3" json:"last_snapshot_at,omitempty"` // Unix timestamp of this instance's last snapshot since start (0: none)
+	Failures       int64                  `protobuf:"varint,4,opt,name=failures,proto3" json:"failures,omitempty"`                                     // Failed snapshots and commands since start
+	LastError      string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SnapshotList) Reset() {
+	*x = SnapshotList{}
+	mi := &file_proto_prime_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotList) ProtoMessage() {}
+
+func (x *SnapshotList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotList.ProtoReflect.Descriptor instead.
+func (*SnapshotList) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *SnapshotList) GetSnapshots() []*SnapshotInfo {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+func (x *SnapshotList) GetDir() string {
+	if x != nil {
+		return x.Dir
+	}
+	return ""
+}
+
+func (x *SnapshotList) GetLastSnapshotAt() int64 {
+	if x != nil {
+		return x.LastSnapshotAt
+	}
+	return 0
+}
+
+func (x *SnapshotList) GetFailures() int64 {
+	if x != nil {
+		return x.Failures
+	}
+	return 0
+}
+
+func (x *SnapshotList) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+type RestoreSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // As listed by ListSnapshots
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreSnapshotRequest) Reset() {
+	*x = RestoreSnapshotRequest{}
+	mi := &file_proto_prime_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreSnapshotRequest) ProtoMessage() {}
+
+func (x *RestoreSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*RestoreSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *RestoreSnapshotRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type RestoreSnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Restored      uint32                 `protobuf:"varint,1,opt,name=restored,proto3" json:"restored,omitempty"`                                // Added to the pool
+	AlreadyServed uint32                 `protobuf:"varint,2,opt,name=already_served,json=alreadyServed,proto3" json:"already_served,omitempty"` // Left the pool after the snapshot was taken
+	InPool        uint32                 `protobuf:"varint,3,opt,name=in_pool,json=inPool,proto3" json:"in_pool,omitempty"`                      // Still in the pool
+	Skipped       uint32                 `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`                                  // Failed validation, or the pool was full
+	PoolSize      uint32                 `protobuf:"varint,5,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreSnapshotResponse) Reset() {
+	*x = RestoreSnapshotResponse{}
+	mi := &file_proto_prime_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreSnapshotResponse) ProtoMessage() {}
+
+func (x *RestoreSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_prime_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*RestoreSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_proto_prime_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *RestoreSnapshotResponse) GetRestored() uint32 {
+	if x != nil {
+		return x.Restored
+	}
+	return 0
+}
+
+func (x *RestoreSnapshotResponse) GetAlreadyServed() uint32 {
+	if x != nil {
+		return x.AlreadyServed
+	}
+	return 0
+}
+
+func (x *RestoreSnapshotResponse) GetInPool() uint32 {
+	if x != nil {
+		return x.InPool
+	}
+	return 0
+}
+
+func (x *RestoreSnapshotResponse) GetSkipped() uint32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *RestoreSnapshotResponse) GetPoolSize() uint32 {
+	if x != nil {
+		return x.PoolSize
 	}
 	return 0
 }
@@ -513,7 +4760,7 @@ var File_proto_prime_proto protoreflect.FileDescriptor
 const file_proto_prime_proto_rawDesc = "" +
 	"\n" +
 	"\x11proto/prime.proto\x12\x05prime\"\a\n" +
-	"\x05Empty\"\xe6\x02\n" +
+	"\x05Empty\"\x97\x03\n" +
 	"\rPreParamsData\x12\x1d\n" +
 	"\n" +
 	"paillier_p\x18\x01 \x01(\fR\tpaillierP\x12\x1d\n" +
@@ -531,26 +4778,132 @@ const file_proto_prime_proto_rawDesc = "" +
 	" \x01(\fR\x04beta\x12\f\n" +
 	"\x01p\x18\v \x01(\fR\x01p\x12\f\n" +
 	"\x01q\x18\f \x01(\fR\x01q\x12!\n" +
-	"\fgenerated_at\x18\r \x01(\x03R\vgeneratedAt\"+\n" +
+	"\fgenerated_at\x18\r \x01(\x03R\vgeneratedAt\x12/\n" +
+	"\bmetadata\x18\x0e \x01(\v2\x13.prime.ItemMetadataR\bmetadata\"\xb7\x02\n" +
+	"\fItemMetadata\x12)\n" +
+	"\x06source\x18\x01 \x01(\x0e2\x11.prime.ItemSourceR\x06source\x12\x1e\n" +
+	"\vpool_age_ms\x18\x02 \x01(\x03R\tpoolAgeMs\x124\n" +
+	"\x16generation_duration_ms\x18\x03 \x01(\x03R\x14generationDurationMs\x12\x1a\n" +
+	"\breplayed\x18\x04 \x01(\bR\breplayed\x121\n" +
+	"\n" +
+	"provenance\x18\x05 \x01(\v2\x11.prime.ProvenanceR\n" +
+	"provenance\x12\x14\n" +
+	"\x05stale\x18\x06 \x01(\bR\x05stale\x12#\n" +
+	"\rshared_second\x18\a \x01(\bR\fsharedSecond\x12\x1c\n" +
+	"\tassurance\x18\b \x01(\tR\tassurance\"\xb2\x01\n" +
+	"\n" +
+	"Provenance\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x14\n" +
+	"\x05burst\x18\x02 \x01(\tR\x05burst\x12\x16\n" +
+	"\x06worker\x18\x03 \x01(\x05R\x06worker\x12\x1a\n" +
+	"\binstance\x18\x04 \x01(\tR\binstance\x12\x1a\n" +
+	"\bimported\x18\x05 \x01(\bR\bimported\x12\x10\n" +
+	"\x03seq\x18\x06 \x01(\x04R\x03seq\x12\x18\n" +
+	"\aversion\x18\a \x01(\tR\aversion\"\x92\x03\n" +
 	"\x13GetPreParamsRequest\x12\x14\n" +
-	"\x05count\x18\x01 \x01(\rR\x05count\"r\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\x12/\n" +
+	"\x13distinct_provenance\x18\x03 \x01(\bR\x12distinctProvenance\x12\x1f\n" +
+	"\vno_generate\x18\x04 \x01(\bR\n" +
+	"noGenerate\x12%\n" +
+	"\x0eallow_imported\x18\x05 \x01(\bR\rallowImported\x12)\n" +
+	"\x10distinct_seconds\x18\x06 \x01(\bR\x0fdistinctSeconds\x12$\n" +
+	"\x0eprime_bit_size\x18\a \x01(\rR\fprimeBitSize\x12*\n" +
+	"\x11paillier_bit_size\x18\b \x01(\rR\x0fpaillierBitSize\x12#\n" +
+	"\rmin_assurance\x18\t \x01(\tR\fminAssurance\x12!\n" +
+	"\freport_queue\x18\n" +
+	" \x01(\bR\vreportQueue\"\x9f\x02\n" +
+	"\x17WaitForPreParamsRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x02 \x01(\rR\ttimeoutMs\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12/\n" +
+	"\x13distinct_provenance\x18\x04 \x01(\bR\x12distinctProvenance\x12%\n" +
+	"\x0eallow_imported\x18\x05 \x01(\bR\rallowImported\x12)\n" +
+	"\x10distinct_seconds\x18\x06 \x01(\bR\x0fdistinctSeconds\x12#\n" +
+	"\rmin_assurance\x18\a \x01(\tR\fminAssurance\"\x9c\x01\n" +
 	"\x14GetPreParamsResponse\x12,\n" +
 	"\x06params\x18\x01 \x03(\v2\x14.prime.PreParamsDataR\x06params\x12,\n" +
-	"\x12generation_time_ms\x18\x02 \x01(\x03R\x10generationTimeMs\"i\n" +
+	"\x12generation_time_ms\x18\x02 \x01(\x03R\x10generationTimeMs\x12(\n" +
+	"\x05queue\x18\x03 \x01(\v2\x12.prime.QueueStatusR\x05queue\"J\n" +
+	"\vQueueStatus\x12\x1a\n" +
+	"\bposition\x18\x01 \x01(\rR\bposition\x12\x1f\n" +
+	"\veta_seconds\x18\x02 \x01(\x03R\n" +
+	"etaSeconds\"\xd2\x01\n" +
 	"\fHealthStatus\x12\x18\n" +
 	"\ahealthy\x18\x01 \x01(\bR\ahealthy\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
-	"\x0euptime_seconds\x18\x03 \x01(\x03R\ruptimeSeconds\"\x80\x02\n" +
+	"\x0euptime_seconds\x18\x03 \x01(\x03R\ruptimeSeconds\x12\x1f\n" +
+	"\vinstance_id\x18\x04 \x01(\tR\n" +
+	"instanceId\x12\x1a\n" +
+	"\bwarnings\x18\x05 \x03(\tR\bwarnings\x12\x16\n" +
+	"\x06region\x18\x06 \x01(\tR\x06region\x12\x12\n" +
+	"\x04zone\x18\a \x01(\tR\x04zone\"\xf6\b\n" +
 	"\n" +
 	"PoolStatus\x122\n" +
 	"\x05pools\x18\x01 \x03(\v2\x1c.prime.PoolStatus.PoolsEntryR\x05pools\x12'\n" +
 	"\x0ftotal_generated\x18\x02 \x01(\x03R\x0etotalGenerated\x12!\n" +
 	"\ftotal_served\x18\x03 \x01(\x03R\vtotalServed\x12'\n" +
-	"\x0fgeneration_rate\x18\x04 \x01(\x01R\x0egenerationRate\x1aI\n" +
+	"\x0fgeneration_rate\x18\x04 \x01(\x01R\x0egenerationRate\x12)\n" +
+	"\x10selection_policy\x18\x05 \x01(\tR\x0fselectionPolicy\x12\x1f\n" +
+	"\vinstance_id\x18\x06 \x01(\tR\n" +
+	"instanceId\x12 \n" +
+	"\vmaintenance\x18\a \x01(\bR\vmaintenance\x12-\n" +
+	"\x12consistency_issues\x18\b \x01(\rR\x11consistencyIssues\x124\n" +
+	"\x16already_served_dropped\x18\t \x01(\rR\x14alreadyServedDropped\x12$\n" +
+	"\x06alarms\x18\n" +
+	" \x03(\v2\f.prime.AlarmR\x06alarms\x12\x16\n" +
+	"\x06frozen\x18\v \x01(\bR\x06frozen\x12#\n" +
+	"\rfreeze_reason\x18\f \x01(\tR\ffreezeReason\x12\x1d\n" +
+	"\n" +
+	"pool_epoch\x18\r \x01(\x03R\tpoolEpoch\x12!\n" +
+	"\fpool_version\x18\x0e \x01(\x04R\vpoolVersion\x12\x12\n" +
+	"\x04pool\x18\x0f \x01(\tR\x04pool\x127\n" +
+	"\rphase_timings\x18\x10 \x03(\v2\x12.prime.PhaseTimingR\fphaseTimings\x12'\n" +
+	"\x0fgeneration_cpus\x18\x11 \x01(\tR\x0egenerationCpus\x12\x16\n" +
+	"\x06region\x18\x12 \x01(\tR\x06region\x12\x12\n" +
+	"\x04zone\x18\x13 \x01(\tR\x04zone\x12)\n" +
+	"\x06pinned\x18\x14 \x03(\v2\x11.prime.PinnedItemR\x06pinned\x12\x1b\n" +
+	"\tpool_hits\x18\x15 \x01(\x03R\bpoolHits\x12\x1f\n" +
+	"\vpool_misses\x18\x16 \x01(\x03R\n" +
+	"poolMisses\x12,\n" +
+	"\x12miss_generation_ms\x18\x17 \x01(\x03R\x10missGenerationMs\x12Q\n" +
+	"\x10assurance_levels\x18\x18 \x03(\v2&.prime.PoolStatus.AssuranceLevelsEntryR\x0fassuranceLevels\x12,\n" +
+	"\titem_ages\x18\x19 \x01(\v2\x0f.prime.ItemAgesR\bitemAges\x1aI\n" +
 	"\n" +
 	"PoolsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12%\n" +
-	"\x05value\x18\x02 \x01(\v2\x0f.prime.PoolInfoR\x05value:\x028\x01\"\xc6\x01\n" +
+	"\x05value\x18\x02 \x01(\v2\x0f.prime.PoolInfoR\x05value:\x028\x01\x1aB\n" +
+	"\x14AssuranceLevelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\rR\x05value:\x028\x01\"\xb6\x02\n" +
+	"\bItemAges\x12.\n" +
+	"\x13oldest_generated_at\x18\x01 \x01(\x03R\x11oldestGeneratedAt\x12.\n" +
+	"\x13newest_generated_at\x18\x02 \x01(\x03R\x11newestGeneratedAt\x12\x1f\n" +
+	"\vmin_seconds\x18\x03 \x01(\x03R\n" +
+	"minSeconds\x12%\n" +
+	"\x0emedian_seconds\x18\x04 \x01(\x03R\rmedianSeconds\x12\x1f\n" +
+	"\vp90_seconds\x18\x05 \x01(\x03R\n" +
+	"p90Seconds\x12\x1f\n" +
+	"\vmax_seconds\x18\x06 \x01(\x03R\n" +
+	"maxSeconds\x12\x14\n" +
+	"\x05stale\x18\a \x01(\rR\x05stale\x12*\n" +
+	"\abuckets\x18\b \x03(\v2\x10.prime.AgeBucketR\abuckets\"6\n" +
+	"\tAgeBucket\x12\x13\n" +
+	"\x05up_to\x18\x01 \x01(\tR\x04upTo\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\"\xac\x01\n" +
+	"\vPhaseTiming\x12\x14\n" +
+	"\x05phase\x18\x01 \x01(\tR\x05phase\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x19\n" +
+	"\btotal_ms\x18\x03 \x01(\x03R\atotalMs\x12\x1d\n" +
+	"\n" +
+	"average_ms\x18\x04 \x01(\x03R\taverageMs\x12\x1a\n" +
+	"\bfailures\x18\x05 \x01(\x03R\bfailures\x12\x1b\n" +
+	"\tfailed_ms\x18\x06 \x01(\x03R\bfailedMs\"K\n" +
+	"\x05Alarm\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x14\n" +
+	"\x05since\x18\x03 \x01(\x03R\x05since\"\xc6\x01\n" +
 	"\bPoolInfo\x12\x12\n" +
 	"\x04bits\x18\x01 \x01(\rR\x04bits\x12\x1d\n" +
 	"\n" +
@@ -561,11 +4914,319 @@ const file_proto_prime_proto_rawDesc = "" +
 	"\n" +
 	"generating\x18\x05 \x01(\rR\n" +
 	"generating\x12(\n" +
-	"\x10last_refill_time\x18\x06 \x01(\x03R\x0elastRefillTime2\xbb\x01\n" +
+	"\x10last_refill_time\x18\x06 \x01(\x03R\x0elastRefillTime\"*\n" +
+	"\x12PullSurplusRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\"C\n" +
+	"\x13PullSurplusResponse\x12,\n" +
+	"\x06params\x18\x01 \x03(\v2\x14.prime.PreParamsDataR\x06params\"\xe3\x03\n" +
+	"\fPoolPressure\x12\x18\n" +
+	"\adesired\x18\x01 \x01(\rR\adesired\x12\x16\n" +
+	"\x06actual\x18\x02 \x01(\rR\x06actual\x12\x18\n" +
+	"\adeficit\x18\x03 \x01(\rR\adeficit\x12\x1d\n" +
+	"\n" +
+	"fill_ratio\x18\x04 \x01(\x01R\tfillRatio\x12\x1b\n" +
+	"\tin_flight\x18\x05 \x01(\rR\binFlight\x12-\n" +
+	"\x13supply_rate_per_min\x18\x06 \x01(\x01R\x10supplyRatePerMin\x127\n" +
+	"\x18consumption_rate_per_min\x18\a \x01(\x01R\x15consumptionRatePerMin\x12'\n" +
+	"\x10net_rate_per_min\x18\b \x01(\x01R\rnetRatePerMin\x12(\n" +
+	"\x10capacity_per_min\x18\t \x01(\x01R\x0ecapacityPerMin\x12\x1f\n" +
+	"\veta_seconds\x18\n" +
+	" \x01(\x03R\n" +
+	"etaSeconds\x12\x1a\n" +
+	"\bpressure\x18\v \x01(\x01R\bpressure\x122\n" +
+	"\x15average_generation_ms\x18\f \x01(\x03R\x13averageGenerationMs\x12\x1f\n" +
+	"\vinstance_id\x18\r \x01(\tR\n" +
+	"instanceId\"\x7f\n" +
+	"\x10GetErrorsRequest\x127\n" +
+	"\fmin_severity\x18\x01 \x01(\x0e2\x14.prime.ErrorSeverityR\vminSeverity\x12\x1c\n" +
+	"\tcomponent\x18\x02 \x01(\tR\tcomponent\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\rR\x05limit\"\xfc\x01\n" +
+	"\n" +
+	"ErrorEntry\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x03R\x04time\x120\n" +
+	"\bseverity\x18\x02 \x01(\x0e2\x14.prime.ErrorSeverityR\bseverity\x12\x1c\n" +
+	"\tcomponent\x18\x03 \x01(\tR\tcomponent\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x128\n" +
+	"\acontext\x18\x05 \x03(\v2\x1e.prime.ErrorEntry.ContextEntryR\acontext\x1a:\n" +
+	"\fContextEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\">\n" +
+	"\x11GetErrorsResponse\x12)\n" +
+	"\x06errors\x18\x01 \x03(\v2\x11.prime.ErrorEntryR\x06errors\"e\n" +
+	"\x15SetMaintenanceRequest\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x122\n" +
+	"\x15drain_timeout_seconds\x18\x02 \x01(\rR\x13drainTimeoutSeconds\"p\n" +
+	"\x11MaintenanceStatus\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12'\n" +
+	"\x0factive_requests\x18\x02 \x01(\rR\x0eactiveRequests\x12\x18\n" +
+	"\adrained\x18\x03 \x01(\bR\adrained\"3\n" +
+	"\x19SetGenerationPauseRequest\x12\x16\n" +
+	"\x06paused\x18\x01 \x01(\bR\x06paused\"d\n" +
+	"\x15GenerationPauseStatus\x12\x16\n" +
+	"\x06paused\x18\x01 \x01(\bR\x06paused\x12\x1b\n" +
+	"\tin_flight\x18\x02 \x01(\rR\binFlight\x12\x16\n" +
+	"\x06parked\x18\x03 \x01(\rR\x06parked\"K\n" +
+	"\x0fFillPoolRequest\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\rR\x06target\x12 \n" +
+	"\vconcurrency\x18\x02 \x01(\rR\vconcurrency\"i\n" +
+	"\x10FillPoolResponse\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\rR\x06target\x12 \n" +
+	"\vconcurrency\x18\x02 \x01(\rR\vconcurrency\x12\x1b\n" +
+	"\tpool_size\x18\x03 \x01(\rR\bpoolSize\"=\n" +
+	"\x10ClearPoolRequest\x12)\n" +
+	"\x10pause_generation\x18\x01 \x01(\bR\x0fpauseGeneration\"Z\n" +
+	"\x11ClearPoolResponse\x12\x18\n" +
+	"\acleared\x18\x01 \x01(\rR\acleared\x12+\n" +
+	"\x11generation_paused\x18\x02 \x01(\bR\x10generationPaused\"\x89\x01\n" +
+	"\x14SetPoolLimitsRequest\x12\"\n" +
+	"\rmin_pool_size\x18\x01 \x01(\rR\vminPoolSize\x12\"\n" +
+	"\rmax_pool_size\x18\x02 \x01(\rR\vmaxPoolSize\x12)\n" +
+	"\x10refill_threshold\x18\x03 \x01(\rR\x0frefillThreshold\"\x9c\x01\n" +
+	"\n" +
+	"PoolLimits\x12\"\n" +
+	"\rmin_pool_size\x18\x01 \x01(\rR\vminPoolSize\x12\"\n" +
+	"\rmax_pool_size\x18\x02 \x01(\rR\vmaxPoolSize\x12)\n" +
+	"\x10refill_threshold\x18\x03 \x01(\rR\x0frefillThreshold\x12\x1b\n" +
+	"\tpool_size\x18\x04 \x01(\rR\bpoolSize\"n\n" +
+	"\fFreezeStatus\x12\x16\n" +
+	"\x06frozen\x18\x01 \x01(\bR\x06frozen\x12\x18\n" +
+	"\aanomaly\x18\x02 \x01(\tR\aanomaly\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05since\x18\x04 \x01(\x03R\x05since\"\x84\x03\n" +
+	"\rReplicaStatus\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x1f\n" +
+	"\vinstance_id\x18\x02 \x01(\tR\n" +
+	"instanceId\x12\x12\n" +
+	"\x04self\x18\x03 \x01(\bR\x04self\x12\x1c\n" +
+	"\treachable\x18\x04 \x01(\bR\treachable\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x18\n" +
+	"\ahealthy\x18\x06 \x01(\bR\ahealthy\x12\x1b\n" +
+	"\tpool_size\x18\a \x01(\rR\bpoolSize\x12\x1f\n" +
+	"\vtarget_size\x18\b \x01(\rR\n" +
+	"targetSize\x12'\n" +
+	"\x0ftotal_generated\x18\t \x01(\x03R\x0etotalGenerated\x12!\n" +
+	"\ftotal_served\x18\n" +
+	" \x01(\x03R\vtotalServed\x12 \n" +
+	"\vmaintenance\x18\v \x01(\bR\vmaintenance\x12\x16\n" +
+	"\x06region\x18\f \x01(\tR\x06region\x12\x12\n" +
+	"\x04zone\x18\r \x01(\tR\x04zone\"?\n" +
+	"\vFleetStatus\x120\n" +
+	"\breplicas\x18\x01 \x03(\v2\x14.prime.ReplicaStatusR\breplicas\"\xc6\x03\n" +
+	"\x12SLOObjectiveStatus\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x14\n" +
+	"\x05class\x18\x03 \x01(\tR\x05class\x12!\n" +
+	"\fthreshold_ms\x18\x04 \x01(\x03R\vthresholdMs\x12\x16\n" +
+	"\x06target\x18\x05 \x01(\x01R\x06target\x12%\n" +
+	"\x0ewindow_seconds\x18\x06 \x01(\x03R\rwindowSeconds\x12\x14\n" +
+	"\x05total\x18\a \x01(\x03R\x05total\x12\x12\n" +
+	"\x04good\x18\b \x01(\x03R\x04good\x12\x1e\n" +
+	"\n" +
+	"compliance\x18\t \x01(\x01R\n" +
+	"compliance\x12)\n" +
+	"\x10budget_remaining\x18\n" +
+	" \x01(\x01R\x0fbudgetRemaining\x12G\n" +
+	"\n" +
+	"burn_rates\x18\v \x03(\v2(.prime.SLOObjectiveStatus.BurnRatesEntryR\tburnRates\x12\x10\n" +
+	"\x03met\x18\f \x01(\bR\x03met\x1a<\n" +
+	"\x0eBurnRatesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"F\n" +
+	"\tSLOStatus\x129\n" +
+	"\n" +
+	"objectives\x18\x01 \x03(\v2\x19.prime.SLOObjectiveStatusR\n" +
+	"objectives\"R\n" +
+	"\x14ListPoolItemsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\rR\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"\x8d\x02\n" +
+	"\bPoolItem\x12 \n" +
+	"\vfingerprint\x18\x01 \x01(\tR\vfingerprint\x12!\n" +
+	"\fgenerated_at\x18\x02 \x01(\x03R\vgeneratedAt\x12\x1f\n" +
+	"\vage_seconds\x18\x03 \x01(\x03R\n" +
+	"ageSeconds\x124\n" +
+	"\x16generation_duration_ms\x18\x04 \x01(\x03R\x14generationDurationMs\x121\n" +
+	"\n" +
+	"provenance\x18\x05 \x01(\v2\x11.prime.ProvenanceR\n" +
+	"provenance\x12\x14\n" +
+	"\x05stale\x18\x06 \x01(\bR\x05stale\x12\x1c\n" +
+	"\tassurance\x18\a \x01(\tR\tassurance\"`\n" +
+	"\x0ePinItemRequest\x12 \n" +
+	"\vfingerprint\x18\x01 \x01(\tR\vfingerprint\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05unpin\x18\x03 \x01(\bR\x05unpin\"a\n" +
+	"\x19CollectDiagnosticsRequest\x12\x1b\n" +
+	"\tlog_lines\x18\x01 \x01(\rR\blogLines\x12'\n" +
+	"\x0fhistory_seconds\x18\x02 \x01(\x03R\x0ehistorySeconds\"I\n" +
+	"\x11DiagnosticsBundle\x12\x18\n" +
+	"\aarchive\x18\x01 \x01(\fR\aarchive\x12\x1a\n" +
+	"\bfilename\x18\x02 \x01(\tR\bfilename\"f\n" +
+	"\n" +
+	"PinnedItem\x12#\n" +
+	"\x04item\x18\x01 \x01(\v2\x0f.prime.PoolItemR\x04item\x12\x1b\n" +
+	"\tpinned_at\x18\x02 \x01(\x03R\bpinnedAt\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"|\n" +
+	"\x15ListPoolItemsResponse\x12%\n" +
+	"\x05items\x18\x01 \x03(\v2\x0f.prime.PoolItemR\x05items\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\rR\x05total\"|\n" +
+	"\x13ForecastPoolRequest\x12\x1f\n" +
+	"\vevent_items\x18\x01 \x01(\rR\n" +
+	"eventItems\x12\x19\n" +
+	"\bevent_at\x18\x02 \x01(\x03R\aeventAt\x12)\n" +
+	"\x10lookback_seconds\x18\x03 \x01(\x03R\x0flookbackSeconds\"\xab\x02\n" +
+	"\rEventForecast\x12\x14\n" +
+	"\x05items\x18\x01 \x01(\rR\x05items\x12\x0e\n" +
+	"\x02at\x18\x02 \x01(\x03R\x02at\x12'\n" +
+	"\x0fbaseline_served\x18\x03 \x01(\x01R\x0ebaselineServed\x12\x18\n" +
+	"\abacklog\x18\x04 \x01(\rR\abacklog\x12)\n" +
+	"\x10generation_hours\x18\x05 \x01(\x01R\x0fgenerationHours\x12\x19\n" +
+	"\bstart_by\x18\x06 \x01(\x03R\astartBy\x12\x1a\n" +
+	"\bfeasible\x18\a \x01(\bR\bfeasible\x12%\n" +
+	"\x0eworkers_needed\x18\b \x01(\rR\rworkersNeeded\x12(\n" +
+	"\x0frecommendations\x18\t \x03(\tR\x0frecommendations\"\xb5\x04\n" +
+	"\fPoolForecast\x12\x1f\n" +
+	"\vinstance_id\x18\x01 \x01(\tR\n" +
+	"instanceId\x12\x1b\n" +
+	"\tpool_size\x18\x02 \x01(\rR\bpoolSize\x12\"\n" +
+	"\rmin_pool_size\x18\x03 \x01(\rR\vminPoolSize\x12\"\n" +
+	"\rmax_pool_size\x18\x04 \x01(\rR\vmaxPoolSize\x12#\n" +
+	"\rhistory_hours\x18\x05 \x01(\x01R\fhistoryHours\x12$\n" +
+	"\x0eserved_per_day\x18\x06 \x01(\x01R\fservedPerDay\x12/\n" +
+	"\x14peak_served_per_hour\x18\a \x01(\x03R\x11peakServedPerHour\x12*\n" +
+	"\x11generated_per_day\x18\b \x01(\x01R\x0fgeneratedPerDay\x12\x18\n" +
+	"\aworkers\x18\t \x01(\rR\aworkers\x122\n" +
+	"\x15average_generation_ms\x18\n" +
+	" \x01(\x03R\x13averageGenerationMs\x12(\n" +
+	"\x10capacity_per_day\x18\v \x01(\x01R\x0ecapacityPerDay\x12\x1f\n" +
+	"\vrunway_days\x18\f \x01(\x01R\n" +
+	"runwayDays\x122\n" +
+	"\x15sustained_runway_days\x18\r \x01(\x01R\x13sustainedRunwayDays\x12*\n" +
+	"\x05event\x18\x0e \x01(\v2\x14.prime.EventForecastR\x05event\"/\n" +
+	"\x17ListRefillCyclesRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\rR\x05limit\"\xb7\x02\n" +
+	"\vRefillCycle\x12\x18\n" +
+	"\astarted\x18\x01 \x01(\x03R\astarted\x12\x1f\n" +
+	"\vduration_ms\x18\x02 \x01(\x03R\n" +
+	"durationMs\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06target\x18\x05 \x01(\rR\x06target\x12\x1b\n" +
+	"\tpool_size\x18\x06 \x01(\rR\bpoolSize\x12\x16\n" +
+	"\x06needed\x18\a \x01(\rR\x06needed\x12\x1c\n" +
+	"\tgenerated\x18\b \x01(\rR\tgenerated\x12\x1a\n" +
+	"\bfailures\x18\t \x01(\rR\bfailures\x12\x18\n" +
+	"\aworkers\x18\n" +
+	" \x01(\rR\aworkers\x12 \n" +
+	"\vinterrupted\x18\v \x01(\bR\vinterrupted\"=\n" +
+	"\x0fRefillCycleList\x12*\n" +
+	"\x06cycles\x18\x01 \x03(\v2\x12.prime.RefillCycleR\x06cycles\"4\n" +
+	"\x15RegisterWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"B\n" +
+	"\vWorkerToken\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"F\n" +
+	"\x16SubmitPreParamsRequest\x12,\n" +
+	"\x06params\x18\x01 \x03(\v2\x14.prime.PreParamsDataR\x06params\"R\n" +
+	"\x17SubmitPreParamsResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\rR\baccepted\x12\x1b\n" +
+	"\tpool_size\x18\x02 \x01(\rR\bpoolSize\"\xd9\x01\n" +
+	"\n" +
+	"WorkerInfo\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x1e\n" +
+	"\n" +
+	"registered\x18\x03 \x01(\x03R\n" +
+	"registered\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\x12\x1c\n" +
+	"\tsubmitted\x18\x05 \x01(\x03R\tsubmitted\x12\x18\n" +
+	"\arevoked\x18\x06 \x01(\bR\arevoked\x12\x1d\n" +
+	"\n" +
+	"revoked_at\x18\a \x01(\x03R\trevokedAt\"9\n" +
+	"\n" +
+	"WorkerList\x12+\n" +
+	"\aworkers\x18\x01 \x03(\v2\x11.prime.WorkerInfoR\aworkers\"P\n" +
+	"\x13RevokeWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x1c\n" +
+	"\treinstate\x18\x02 \x01(\bR\treinstate\"*\n" +
+	"\x12ExportItemsRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\rR\x05count\"`\n" +
+	"\x13ExportItemsResponse\x12,\n" +
+	"\x06params\x18\x01 \x03(\v2\x14.prime.PreParamsDataR\x06params\x12\x1b\n" +
+	"\tpool_size\x18\x02 \x01(\rR\bpoolSize\"B\n" +
+	"\x12ImportItemsRequest\x12,\n" +
+	"\x06params\x18\x01 \x03(\v2\x14.prime.PreParamsDataR\x06params\"N\n" +
+	"\x13ImportItemsResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\rR\baccepted\x12\x1b\n" +
+	"\tpool_size\x18\x02 \x01(\rR\bpoolSize\"x\n" +
+	"\fSnapshotInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x19\n" +
+	"\btaken_at\x18\x02 \x01(\x03R\atakenAt\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x04R\x05bytes\x12#\n" +
+	"\rcommand_error\x18\x04 \x01(\tR\fcommandError\"\xb8\x01\n" +
+	"\fSnapshotList\x121\n" +
+	"\tsnapshots\x18\x01 \x03(\v2\x13.prime.SnapshotInfoR\tsnapshots\x12\x10\n" +
+	"\x03dir\x18\x02 \x01(\tR\x03dir\x12(\n" +
+	"\x10last_snapshot_at\x18\x03 \x01(\x03R\x0elastSnapshotAt\x12\x1a\n" +
+	"\bfailures\x18\x04 \x01(\x03R\bfailures\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\",\n" +
+	"\x16RestoreSnapshotRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\xac\x01\n" +
+	"\x17RestoreSnapshotResponse\x12\x1a\n" +
+	"\brestored\x18\x01 \x01(\rR\brestored\x12%\n" +
+	"\x0ealready_served\x18\x02 \x01(\rR\ralreadyServed\x12\x17\n" +
+	"\ain_pool\x18\x03 \x01(\rR\x06inPool\x12\x18\n" +
+	"\askipped\x18\x04 \x01(\rR\askipped\x12\x1b\n" +
+	"\tpool_size\x18\x05 \x01(\rR\bpoolSize*Z\n" +
+	"\n" +
+	"ItemSource\x12\x1b\n" +
+	"\x17ITEM_SOURCE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10ITEM_SOURCE_POOL\x10\x01\x12\x19\n" +
+	"\x15ITEM_SOURCE_GENERATED\x10\x02*e\n" +
+	"\rErrorSeverity\x12\x1e\n" +
+	"\x1aERROR_SEVERITY_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16ERROR_SEVERITY_WARNING\x10\x01\x12\x18\n" +
+	"\x14ERROR_SEVERITY_ERROR\x10\x022\xda\x02\n" +
 	"\fPrimeService\x12G\n" +
-	"\fGetPreParams\x12\x1a.prime.GetPreParamsRequest\x1a\x1b.prime.GetPreParamsResponse\x120\n" +
+	"\fGetPreParams\x12\x1a.prime.GetPreParamsRequest\x1a\x1b.prime.GetPreParamsResponse\x12L\n" +
+	"\x0fStreamPreParams\x12\x1a.prime.GetPreParamsRequest\x1a\x1b.prime.GetPreParamsResponse0\x01\x12O\n" +
+	"\x10WaitForPreParams\x12\x1e.prime.WaitForPreParamsRequest\x1a\x1b.prime.GetPreParamsResponse\x120\n" +
 	"\vHealthCheck\x12\f.prime.Empty\x1a\x13.prime.HealthStatus\x120\n" +
-	"\rGetPoolStatus\x12\f.prime.Empty\x1a\x11.prime.PoolStatusB*Z(github.com/TEENet-io/prime-service/protob\x06proto3"
+	"\rGetPoolStatus\x12\f.prime.Empty\x1a\x11.prime.PoolStatus2\xac\f\n" +
+	"\fAdminService\x120\n" +
+	"\vGetPressure\x12\f.prime.Empty\x1a\x13.prime.PoolPressure\x12>\n" +
+	"\tGetErrors\x12\x17.prime.GetErrorsRequest\x1a\x18.prime.GetErrorsResponse\x12H\n" +
+	"\x0eSetMaintenance\x12\x1c.prime.SetMaintenanceRequest\x1a\x18.prime.MaintenanceStatus\x128\n" +
+	"\x0eGetMaintenance\x12\f.prime.Empty\x1a\x18.prime.MaintenanceStatus\x12T\n" +
+	"\x12SetGenerationPause\x12 .prime.SetGenerationPauseRequest\x1a\x1c.prime.GenerationPauseStatus\x12@\n" +
+	"\x12GetGenerationPause\x12\f.prime.Empty\x1a\x1c.prime.GenerationPauseStatus\x12.\n" +
+	"\tGetFreeze\x12\f.prime.Empty\x1a\x13.prime.FreezeStatus\x12-\n" +
+	"\bUnfreeze\x12\f.prime.Empty\x1a\x13.prime.FreezeStatus\x12;\n" +
+	"\bFillPool\x12\x16.prime.FillPoolRequest\x1a\x17.prime.FillPoolResponse\x12>\n" +
+	"\tClearPool\x12\x17.prime.ClearPoolRequest\x1a\x18.prime.ClearPoolResponse\x12?\n" +
+	"\rSetPoolLimits\x12\x1b.prime.SetPoolLimitsRequest\x1a\x11.prime.PoolLimits\x12-\n" +
+	"\tListPeers\x12\f.prime.Empty\x1a\x12.prime.FleetStatus\x12.\n" +
+	"\fGetSLOStatus\x12\f.prime.Empty\x1a\x10.prime.SLOStatus\x12J\n" +
+	"\rListPoolItems\x12\x1b.prime.ListPoolItemsRequest\x1a\x1c.prime.ListPoolItemsResponse\x123\n" +
+	"\aPinItem\x12\x15.prime.PinItemRequest\x1a\x11.prime.PinnedItem\x12P\n" +
+	"\x12CollectDiagnostics\x12 .prime.CollectDiagnosticsRequest\x1a\x18.prime.DiagnosticsBundle\x12?\n" +
+	"\fForecastPool\x12\x1a.prime.ForecastPoolRequest\x1a\x13.prime.PoolForecast\x12J\n" +
+	"\x10ListRefillCycles\x12\x1e.prime.ListRefillCyclesRequest\x1a\x16.prime.RefillCycleList\x12.\n" +
+	"\vListWorkers\x12\f.prime.Empty\x1a\x11.prime.WorkerList\x12=\n" +
+	"\fRevokeWorker\x12\x1a.prime.RevokeWorkerRequest\x1a\x11.prime.WorkerInfo\x12D\n" +
+	"\vExportItems\x12\x19.prime.ExportItemsRequest\x1a\x1a.prime.ExportItemsResponse\x12D\n" +
+	"\vImportItems\x12\x19.prime.ImportItemsRequest\x1a\x1a.prime.ImportItemsResponse\x122\n" +
+	"\rListSnapshots\x12\f.prime.Empty\x1a\x13.prime.SnapshotList\x121\n" +
+	"\fTakeSnapshot\x12\f.prime.Empty\x1a\x13.prime.SnapshotInfo\x12P\n" +
+	"\x0fRestoreSnapshot\x12\x1d.prime.RestoreSnapshotRequest\x1a\x1e.prime.RestoreSnapshotResponse2S\n" +
+	"\vPeerService\x12D\n" +
+	"\vPullSurplus\x12\x19.prime.PullSurplusRequest\x1a\x1a.prime.PullSurplusResponse2\xa5\x01\n" +
+	"\rWorkerService\x12B\n" +
+	"\x0eRegisterWorker\x12\x1c.prime.RegisterWorkerRequest\x1a\x12.prime.WorkerToken\x12P\n" +
+	"\x0fSubmitPreParams\x12\x1d.prime.SubmitPreParamsRequest\x1a\x1e.prime.SubmitPreParamsResponseB*Z(github.com/TEENet-io/prime-service/protob\x06proto3"
 
 var (
 	file_proto_prime_proto_rawDescOnce sync.Once
@@ -579,32 +5240,183 @@ func file_proto_prime_proto_rawDescGZIP() []byte {
 	return file_proto_prime_proto_rawDescData
 }
 
-var file_proto_prime_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_prime_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_prime_proto_msgTypes = make([]protoimpl.MessageInfo, 68)
 var file_proto_prime_proto_goTypes = []any{
-	(*Empty)(nil),                // 0: prime.Empty
-	(*PreParamsData)(nil),        // 1: prime.PreParamsData
-	(*GetPreParamsRequest)(nil),  // 2: prime.GetPreParamsRequest
-	(*GetPreParamsResponse)(nil), // 3: prime.GetPreParamsResponse
-	(*HealthStatus)(nil),         // 4: prime.HealthStatus
-	(*PoolStatus)(nil),           // 5: prime.PoolStatus
-	(*PoolInfo)(nil),             // 6: prime.PoolInfo
-	nil,                          // 7: prime.PoolStatus.PoolsEntry
+	(ItemSource)(0),                   // 0: prime.ItemSource
+	(ErrorSeverity)(0),                // 1: prime.ErrorSeverity
+	(*Empty)(nil),                     // 2: prime.Empty
+	(*PreParamsData)(nil),             // 3: prime.PreParamsData
+	(*ItemMetadata)(nil),              // 4: prime.ItemMetadata
+	(*Provenance)(nil),                // 5: prime.Provenance
+	(*GetPreParamsRequest)(nil),       // 6: prime.GetPreParamsRequest
+	(*WaitForPreParamsRequest)(nil),   // 7: prime.WaitForPreParamsRequest
+	(*GetPreParamsResponse)(nil),      // 8: prime.GetPreParamsResponse
+	(*QueueStatus)(nil),               // 9: prime.QueueStatus
+	(*HealthStatus)(nil),              // 10: prime.HealthStatus
+	(*PoolStatus)(nil),                // 11: prime.PoolStatus
+	(*ItemAges)(nil),                  // 12: prime.ItemAges
+	(*AgeBucket)(nil),                 // 13: prime.AgeBucket
+	(*PhaseTiming)(nil),               // 14: prime.PhaseTiming
+	(*Alarm)(nil),                     // 15: prime.Alarm
+	(*PoolInfo)(nil),                  // 16: prime.PoolInfo
+	(*PullSurplusRequest)(nil),        // 17: prime.PullSurplusRequest
+	(*PullSurplusResponse)(nil),       // 18: prime.PullSurplusResponse
+	(*PoolPressure)(nil),              // 19: prime.PoolPressure
+	(*GetErrorsRequest)(nil),          // 20: prime.GetErrorsRequest
+	(*ErrorEntry)(nil),                // 21: prime.ErrorEntry
+	(*GetErrorsResponse)(nil),         // 22: prime.GetErrorsResponse
+	(*SetMaintenanceRequest)(nil),     // 23: prime.SetMaintenanceRequest
+	(*MaintenanceStatus)(nil),         // 24: prime.MaintenanceStatus
+	(*SetGenerationPauseRequest)(nil), // 25: prime.SetGenerationPauseRequest
+	(*GenerationPauseStatus)(nil),     // 26: prime.GenerationPauseStatus
+	(*FillPoolRequest)(nil),           // 27: prime.FillPoolRequest
+	(*FillPoolResponse)(nil),          // 28: prime.FillPoolResponse
+	(*ClearPoolRequest)(nil),          // 29: prime.ClearPoolRequest
+	(*ClearPoolResponse)(nil),         // 30: prime.ClearPoolResponse
+	(*SetPoolLimitsRequest)(nil),      // 31: prime.SetPoolLimitsRequest
+	(*PoolLimits)(nil),                // 32: prime.PoolLimits
+	(*FreezeStatus)(nil),              // 33: prime.FreezeStatus
+	(*ReplicaStatus)(nil),             // 34: prime.ReplicaStatus
+	(*FleetStatus)(nil),               // 35: prime.FleetStatus
+	(*SLOObjectiveStatus)(nil),        // 36: prime.SLOObjectiveStatus
+	(*SLOStatus)(nil),                 // 37: prime.SLOStatus
+	(*ListPoolItemsRequest)(nil),      // 38: prime.ListPoolItemsRequest
+	(*PoolItem)(nil),                  // 39: prime.PoolItem
+	(*PinItemRequest)(nil),            // 40: prime.PinItemRequest
+	(*CollectDiagnosticsRequest)(nil), // 41: prime.CollectDiagnosticsRequest
+	(*DiagnosticsBundle)(nil),         // 42: prime.DiagnosticsBundle
+	(*PinnedItem)(nil),                // 43: prime.PinnedItem
+	(*ListPoolItemsResponse)(nil),     // 44: prime.ListPoolItemsResponse
+	(*ForecastPoolRequest)(nil),       // 45: prime.ForecastPoolRequest
+	(*EventForecast)(nil),             // 46: prime.EventForecast
+	(*PoolForecast)(nil),              // 47: prime.PoolForecast
+	(*ListRefillCyclesRequest)(nil),   // 48: prime.ListRefillCyclesRequest
+	(*RefillCycle)(nil),               // 49: prime.RefillCycle
+	(*RefillCycleList)(nil),           // 50: prime.RefillCycleList
+	(*RegisterWorkerRequest)(nil),     // 51: prime.RegisterWorkerRequest
+	(*WorkerToken)(nil),               // 52: prime.WorkerToken
+	(*SubmitPreParamsRequest)(nil),    // 53: prime.SubmitPreParamsRequest
+	(*SubmitPreParamsResponse)(nil),   // 54: prime.SubmitPreParamsResponse
+	(*WorkerInfo)(nil),                // 55: prime.WorkerInfo
+	(*WorkerList)(nil),                // 56: prime.WorkerList
+	(*RevokeWorkerRequest)(nil),       // 57: prime.RevokeWorkerRequest
+	(*ExportItemsRequest)(nil),        // 58: prime.ExportItemsRequest
+	(*ExportItemsResponse)(nil),       // 59: prime.ExportItemsResponse
+	(*ImportItemsRequest)(nil),        // 60: prime.ImportItemsRequest
+	(*ImportItemsResponse)(nil),       // 61: prime.ImportItemsResponse
+	(*SnapshotInfo)(nil),              // 62: prime.SnapshotInfo
+	(*SnapshotList)(nil),              // 63: prime.SnapshotList
+	(*RestoreSnapshotRequest)(nil),    // 64: prime.RestoreSnapshotRequest
+	(*RestoreSnapshotResponse)(nil),   // 65: prime.RestoreSnapshotResponse
+	nil,                               // 66: prime.PoolStatus.PoolsEntry
+	nil,                               // 67: prime.PoolStatus.AssuranceLevelsEntry
+	nil,                               // 68: prime.ErrorEntry.ContextEntry
+	nil,                               // 69: prime.SLOObjectiveStatus.BurnRatesEntry
 }
 var file_proto_prime_proto_depIdxs = []int32{
-	1, // 0: prime.GetPreParamsResponse.params:type_name -> prime.PreParamsData
-	7, // 1: prime.PoolStatus.pools:type_name -> prime.PoolStatus.PoolsEntry
-	6, // 2: prime.PoolStatus.PoolsEntry.value:type_name -> prime.PoolInfo
-	2, // 3: prime.PrimeService.GetPreParams:input_type -> prime.GetPreParamsRequest
-	0, // 4: prime.PrimeService.HealthCheck:input_type -> prime.Empty
-	0, // 5: prime.PrimeService.GetPoolStatus:input_type -> prime.Empty
-	3, // 6: prime.PrimeService.GetPreParams:output_type -> prime.GetPreParamsResponse
-	4, // 7: prime.PrimeService.HealthCheck:output_type -> prime.HealthStatus
-	5, // 8: prime.PrimeService.GetPoolStatus:output_type -> prime.PoolStatus
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	4,  // 0: prime.PreParamsData.metadata:type_name -> prime.ItemMetadata
+	0,  // 1: prime.ItemMetadata.source:type_name -> prime.ItemSource
+	5,  // 2: prime.ItemMetadata.provenance:type_name -> prime.Provenance
+	3,  // 3: prime.GetPreParamsResponse.params:type_name -> prime.PreParamsData
+	9,  // 4: prime.GetPreParamsResponse.queue:type_name -> prime.QueueStatus
+	66, // 5: prime.PoolStatus.pools:type_name -> prime.PoolStatus.PoolsEntry
+	15, // 6: prime.PoolStatus.alarms:type_name -> prime.Alarm
+	14, // 7: prime.PoolStatus.phase_timings:type_name -> prime.PhaseTiming
+	43, // 8: prime.PoolStatus.pinned:type_name -> prime.PinnedItem
+	67, // 9: prime.PoolStatus.assurance_levels:type_name -> prime.PoolStatus.AssuranceLevelsEntry
+	12, // 10: prime.PoolStatus.item_ages:type_name -> prime.ItemAges
+	13, // 11: prime.ItemAges.buckets:type_name -> prime.AgeBucket
+	3,  // 12: prime.PullSurplusResponse.params:type_name -> prime.PreParamsData
+	1,  // 13: prime.GetErrorsRequest.min_severity:type_name -> prime.ErrorSeverity
+	1,  // 14: prime.ErrorEntry.severity:type_name -> prime.ErrorSeverity
+	68, // 15: prime.ErrorEntry.context:type_name -> prime.ErrorEntry.ContextEntry
+	21, // 16: prime.GetErrorsResponse.errors:type_name -> prime.ErrorEntry
+	34, // 17: prime.FleetStatus.replicas:type_name -> prime.ReplicaStatus
+	69, // 18: prime.SLOObjectiveStatus.burn_rates:type_name -> prime.SLOObjectiveStatus.BurnRatesEntry
+	36, // 19: prime.SLOStatus.objectives:type_name -> prime.SLOObjectiveStatus
+	5,  // 20: prime.PoolItem.provenance:type_name -> prime.Provenance
+	39, // 21: prime.PinnedItem.item:type_name -> prime.PoolItem
+	39, // 22: prime.ListPoolItemsResponse.items:type_name -> prime.PoolItem
+	46, // 23: prime.PoolForecast.event:type_name -> prime.EventForecast
+	49, // 24: prime.RefillCycleList.cycles:type_name -> prime.RefillCycle
+	3,  // 25: prime.SubmitPreParamsRequest.params:type_name -> prime.PreParamsData
+	55, // 26: prime.WorkerList.workers:type_name -> prime.WorkerInfo
+	3,  // 27: prime.ExportItemsResponse.params:type_name -> prime.PreParamsData
+	3,  // 28: prime.ImportItemsRequest.params:type_name -> prime.PreParamsData
+	62, // 29: prime.SnapshotList.snapshots:type_name -> prime.SnapshotInfo
+	16, // 30: prime.PoolStatus.PoolsEntry.value:type_name -> prime.PoolInfo
+	6,  // 31: prime.PrimeService.GetPreParams:input_type -> prime.GetPreParamsRequest
+	6,  // 32: prime.PrimeService.StreamPreParams:input_type -> prime.GetPreParamsRequest
+	7,  // 33: prime.PrimeService.WaitForPreParams:input_type -> prime.WaitForPreParamsRequest
+	2,  // 34: prime.PrimeService.HealthCheck:input_type -> prime.Empty
+	2,  // 35: prime.PrimeService.GetPoolStatus:input_type -> prime.Empty
+	2,  // 36: prime.AdminService.GetPressure:input_type -> prime.Empty
+	20, // 37: prime.AdminService.GetErrors:input_type -> prime.GetErrorsRequest
+	23, // 38: prime.AdminService.SetMaintenance:input_type -> prime.SetMaintenanceRequest
+	2,  // 39: prime.AdminService.GetMaintenance:input_type -> prime.Empty
+	25, // 40: prime.AdminService.SetGenerationPause:input_type -> prime.SetGenerationPauseRequest
+	2,  // 41: prime.AdminService.GetGenerationPause:input_type -> prime.Empty
+	2,  // 42: prime.AdminService.GetFreeze:input_type -> prime.Empty
+	2,  // 43: prime.AdminService.Unfreeze:input_type -> prime.Empty
+	27, // 44: prime.AdminService.FillPool:input_type -> prime.FillPoolRequest
+	29, // 45: prime.AdminService.ClearPool:input_type -> prime.ClearPoolRequest
+	31, // 46: prime.AdminService.SetPoolLimits:input_type -> prime.SetPoolLimitsRequest
+	2,  // 47: prime.AdminService.ListPeers:input_type -> prime.Empty
+	2,  // 48: prime.AdminService.GetSLOStatus:input_type -> prime.Empty
+	38, // 49: prime.AdminService.ListPoolItems:input_type -> prime.ListPoolItemsRequest
+	40, // 50: prime.AdminService.PinItem:input_type -> prime.PinItemRequest
+	41, // 51: prime.AdminService.CollectDiagnostics:input_type -> prime.CollectDiagnosticsRequest
+	45, // 52: prime.AdminService.ForecastPool:input_type -> prime.ForecastPoolRequest
+	48, // 53: prime.AdminService.ListRefillCycles:input_type -> prime.ListRefillCyclesRequest
+	2,  // 54: prime.AdminService.ListWorkers:input_type -> prime.Empty
+	57, // 55: prime.AdminService.RevokeWorker:input_type -> prime.RevokeWorkerRequest
+	58, // 56: prime.AdminService.ExportItems:input_type -> prime.ExportItemsRequest
+	60, // 57: prime.AdminService.ImportItems:input_type -> prime.ImportItemsRequest
+	2,  // 58: prime.AdminService.ListSnapshots:input_type -> prime.Empty
+	2,  // 59: prime.AdminService.TakeSnapshot:input_type -> prime.Empty
+	64, // 60: prime.AdminService.RestoreSnapshot:input_type -> prime.RestoreSnapshotRequest
+	17, // 61: prime.PeerService.PullSurplus:input_type -> prime.PullSurplusRequest
+	51, // 62: prime.WorkerService.RegisterWorker:input_type -> prime.RegisterWorkerRequest
+	53, // 63: prime.WorkerService.SubmitPreParams:input_type -> prime.SubmitPreParamsRequest
+	8,  // 64: prime.PrimeService.GetPreParams:output_type -> prime.GetPreParamsResponse
+	8,  // 65: prime.PrimeService.StreamPreParams:output_type -> prime.GetPreParamsResponse
+	8,  // 66: prime.PrimeService.WaitForPreParams:output_type -> prime.GetPreParamsResponse
+	10, // 67: prime.PrimeService.HealthCheck:output_type -> prime.HealthStatus
+	11, // 68: prime.PrimeService.GetPoolStatus:output_type -> prime.PoolStatus
+	19, // 69: prime.AdminService.GetPressure:output_type -> prime.PoolPressure
+	22, // 70: prime.AdminService.GetErrors:output_type -> prime.GetErrorsResponse
+	24, // 71: prime.AdminService.SetMaintenance:output_type -> prime.MaintenanceStatus
+	24, // 72: prime.AdminService.GetMaintenance:output_type -> prime.MaintenanceStatus
+	26, // 73: prime.AdminService.SetGenerationPause:output_type -> prime.GenerationPauseStatus
+	26, // 74: prime.AdminService.GetGenerationPause:output_type -> prime.GenerationPauseStatus
+	33, // 75: prime.AdminService.GetFreeze:output_type -> prime.FreezeStatus
+	33, // 76: prime.AdminService.Unfreeze:output_type -> prime.FreezeStatus
+	28, // 77: prime.AdminService.FillPool:output_type -> prime.FillPoolResponse
+	30, // 78: prime.AdminService.ClearPool:output_type -> prime.ClearPoolResponse
+	32, // 79: prime.AdminService.SetPoolLimits:output_type -> prime.PoolLimits
+	35, // 80: prime.AdminService.ListPeers:output_type -> prime.FleetStatus
+	37, // 81: prime.AdminService.GetSLOStatus:output_type -> prime.SLOStatus
+	44, // 82: prime.AdminService.ListPoolItems:output_type -> prime.ListPoolItemsResponse
+	43, // 83: prime.AdminService.PinItem:output_type -> prime.PinnedItem
+	42, // 84: prime.AdminService.CollectDiagnostics:output_type -> prime.DiagnosticsBundle
+	47, // 85: prime.AdminService.ForecastPool:output_type -> prime.PoolForecast
+	50, // 86: prime.AdminService.ListRefillCycles:output_type -> prime.RefillCycleList
+	56, // 87: prime.AdminService.ListWorkers:output_type -> prime.WorkerList
+	55, // 88: prime.AdminService.RevokeWorker:output_type -> prime.WorkerInfo
+	59, // 89: prime.AdminService.ExportItems:output_type -> prime.ExportItemsResponse
+	61, // 90: prime.AdminService.ImportItems:output_type -> prime.ImportItemsResponse
+	63, // 91: prime.AdminService.ListSnapshots:output_type -> prime.SnapshotList
+	62, // 92: prime.AdminService.TakeSnapshot:output_type -> prime.SnapshotInfo
+	65, // 93: prime.AdminService.RestoreSnapshot:output_type -> prime.RestoreSnapshotResponse
+	18, // 94: prime.PeerService.PullSurplus:output_type -> prime.PullSurplusResponse
+	52, // 95: prime.WorkerService.RegisterWorker:output_type -> prime.WorkerToken
+	54, // 96: prime.WorkerService.SubmitPreParams:output_type -> prime.SubmitPreParamsResponse
+	64, // [64:97] is the sub-list for method output_type
+	31, // [31:64] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
 }
 
 func init() { file_proto_prime_proto_init() }
@@ -617,13 +5429,14 @@ func file_proto_prime_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_prime_proto_rawDesc), len(file_proto_prime_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      2,
+			NumMessages:   68,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   4,
 		},
 		GoTypes:           file_proto_prime_proto_goTypes,
 		DependencyIndexes: file_proto_prime_proto_depIdxs,
+		EnumInfos:         file_proto_prime_proto_enumTypes,
 		MessageInfos:      file_proto_prime_proto_msgTypes,
 	}.Build()
 	File_proto_prime_proto = out.File
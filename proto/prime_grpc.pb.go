@@ -19,9 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PrimeService_GetPreParams_FullMethodName  = "/prime.PrimeService/GetPreParams"
-	PrimeService_HealthCheck_FullMethodName   = "/prime.PrimeService/HealthCheck"
-	PrimeService_GetPoolStatus_FullMethodName = "/prime.PrimeService/GetPoolStatus"
+	PrimeService_GetPreParams_FullMethodName     = "/prime.PrimeService/GetPreParams"
+	PrimeService_StreamPreParams_FullMethodName  = "/prime.PrimeService/StreamPreParams"
+	PrimeService_WaitForPreParams_FullMethodName = "/prime.PrimeService/WaitForPreParams"
+	PrimeService_HealthCheck_FullMethodName      = "/prime.PrimeService/HealthCheck"
+	PrimeService_GetPoolStatus_FullMethodName    = "/prime.PrimeService/GetPoolStatus"
 )
 
 // PrimeServiceClient is the client API for PrimeService service.
@@ -30,6 +32,22 @@ const (
 type PrimeServiceClient interface {
 	// Get PreParamsData for ECDSA DKG (single or batch)
 	GetPreParams(ctx context.Context, in *GetPreParamsRequest, opts ...grpc.CallOption) (*GetPreParamsResponse, error)
+	// Same as GetPreParams, but each item is sent as soon as it is taken from
+	// the pool or generated, so clients can start on the first items while the
+	// rest are generated, in messages within the service's max_response_bytes.
+	// GetPreParams refuses batches too large for one message with
+	// RESOURCE_EXHAUSTED (ErrorInfo reason RESPONSE_TOO_LARGE) before consuming
+	// anything, and clients retry here. Items of replayed and distinct_seconds
+	// requests are sent once the batch is complete. With report_queue set,
+	// messages without params report the request's place while it waits for
+	// an on-demand generation slot.
+	StreamPreParams(ctx context.Context, in *GetPreParamsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetPreParamsResponse], error)
+	// Long-poll for parameters: wait until the pool can serve all count
+	// items, then take them at once. Never generates synchronously; a pool too
+	// small starts an emergency refill. If the items are not available within
+	// timeout_ms (0: the call deadline), fails with RESOURCE_EXHAUSTED
+	// (ErrorInfo reason POOL_EMPTY) without taking any.
+	WaitForPreParams(ctx context.Context, in *WaitForPreParamsRequest, opts ...grpc.CallOption) (*GetPreParamsResponse, error)
 	// Health check
 	HealthCheck(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HealthStatus, error)
 	// Get pool status
@@ -54,6 +72,35 @@ func (c *primeServiceClient) GetPreParams(ctx context.Context, in *GetPreParamsR
 	return out, nil
 }
 
+func (c *primeServiceClient) StreamPreParams(ctx context.Context, in *GetPreParamsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetPreParamsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PrimeService_ServiceDesc.Streams[0], PrimeService_StreamPreParams_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetPreParamsRequest, GetPreParamsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PrimeService_StreamPreParamsClient = grpc.ServerStreamingClient[GetPreParamsResponse]
+
+func (c *primeServiceClient) WaitForPreParams(ctx context.Context, in *WaitForPreParamsRequest, opts ...grpc.CallOption) (*GetPreParamsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPreParamsResponse)
+	err := c.cc.Invoke(ctx, PrimeService_WaitForPreParams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *primeServiceClient) HealthCheck(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*HealthStatus, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HealthStatus)
@@ -80,6 +127,22 @@ func (c *primeServiceClient) GetPoolStatus(ctx context.Context, in *Empty, opts
 type PrimeServiceServer interface {
 	// Get PreParamsData for ECDSA DKG (single or batch)
 	GetPreParams(context.Context, *GetPreParamsRequest) (*GetPreParamsResponse, error)
+	// Same as GetPreParams, but each item is sent as soon as it is taken from
+	// the pool or generated, so clients can start on the first items while the
+	// rest are generated, in messages within the service's max_response_bytes.
+	// GetPreParams refuses batches too large for one message with
+	// RESOURCE_EXHAUSTED (ErrorInfo reason RESPONSE_TOO_LARGE) before consuming
+	// anything, and clients retry here. Items of replayed and distinct_seconds
+	// requests are sent once the batch is complete. With report_queue set,
+	// messages without params report the request's place while it waits for
+	// an on-demand generation slot.
+	StreamPreParams(*GetPreParamsRequest, grpc.ServerStreamingServer[GetPreParamsResponse]) error
+	// Long-poll for parameters: wait until the pool can serve all count
+	// items, then take them at once. Never generates synchronously; a pool too
+	// small starts an emergency refill. If the items are not available within
+	// timeout_ms (0: the call deadline), fails with RESOURCE_EXHAUSTED
+	// (ErrorInfo reason POOL_EMPTY) without taking any.
+	WaitForPreParams(context.Context, *WaitForPreParamsRequest) (*GetPreParamsResponse, error)
 	// Health check
 	HealthCheck(context.Context, *Empty) (*HealthStatus, error)
 	// Get pool status
@@ -97,6 +160,12 @@ type UnimplementedPrimeServiceServer struct{}
 func (UnimplementedPrimeServiceServer) GetPreParams(context.Context, *GetPreParamsRequest) (*GetPreParamsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPreParams not implemented")
 }
+func (UnimplementedPrimeServiceServer) StreamPreParams(*GetPreParamsRequest, grpc.ServerStreamingServer[GetPreParamsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPreParams not implemented")
+}
+func (UnimplementedPrimeServiceServer) WaitForPreParams(context.Context, *WaitForPreParamsRequest) (*GetPreParamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WaitForPreParams not implemented")
+}
 func (UnimplementedPrimeServiceServer) HealthCheck(context.Context, *Empty) (*HealthStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
@@ -142,6 +211,35 @@ func _PrimeService_GetPreParams_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PrimeService_StreamPreParams_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetPreParamsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PrimeServiceServer).StreamPreParams(m, &grpc.GenericServerStream[GetPreParamsRequest, GetPreParamsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PrimeService_StreamPreParamsServer = grpc.ServerStreamingServer[GetPreParamsResponse]
+
+func _PrimeService_WaitForPreParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitForPreParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrimeServiceServer).WaitForPreParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PrimeService_WaitForPreParams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrimeServiceServer).WaitForPreParams(ctx, req.(*WaitForPreParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PrimeService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -189,6 +287,10 @@ var PrimeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetPreParams",
 			Handler:    _PrimeService_GetPreParams_Handler,
 		},
+		{
+			MethodName: "WaitForPreParams",
+			Handler:    _PrimeService_WaitForPreParams_Handler,
+		},
 		{
 			MethodName: "HealthCheck",
 			Handler:    _PrimeService_HealthCheck_Handler,
@@ -198,6 +300,1422 @@ var PrimeService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _PrimeService_GetPoolStatus_Handler,
 		},
 	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPreParams",
+			Handler:       _PrimeService_StreamPreParams_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/prime.proto",
+}
+
+const (
+	AdminService_GetPressure_FullMethodName        = "/prime.AdminService/GetPressure"
+	AdminService_GetErrors_FullMethodName          = "/prime.AdminService/GetErrors"
+	AdminService_SetMaintenance_FullMethodName     = "/prime.AdminService/SetMaintenance"
+	AdminService_GetMaintenance_FullMethodName     = "/prime.AdminService/GetMaintenance"
+	AdminService_SetGenerationPause_FullMethodName = "/prime.AdminService/SetGenerationPause"
+	AdminService_GetGenerationPause_FullMethodName = "/prime.AdminService/GetGenerationPause"
+	AdminService_GetFreeze_FullMethodName          = "/prime.AdminService/GetFreeze"
+	AdminService_Unfreeze_FullMethodName           = "/prime.AdminService/Unfreeze"
+	AdminService_FillPool_FullMethodName           = "/prime.AdminService/FillPool"
+	AdminService_ClearPool_FullMethodName          = "/prime.AdminService/ClearPool"
+	AdminService_SetPoolLimits_FullMethodName      = "/prime.AdminService/SetPoolLimits"
+	AdminService_ListPeers_FullMethodName          = "/prime.AdminService/ListPeers"
+	AdminService_GetSLOStatus_FullMethodName       = "/prime.AdminService/GetSLOStatus"
+	AdminService_ListPoolItems_FullMethodName      = "/prime.AdminService/ListPoolItems"
+	AdminService_PinItem_FullMethodName            = "/prime.AdminService/PinItem"
+	AdminService_CollectDiagnostics_FullMethodName = "/prime.AdminService/CollectDiagnostics"
+	AdminService_ForecastPool_FullMethodName       = "/prime.AdminService/ForecastPool"
+	AdminService_ListRefillCycles_FullMethodName   = "/prime.AdminService/ListRefillCycles"
+	AdminService_ListWorkers_FullMethodName        = "/prime.AdminService/ListWorkers"
+	AdminService_RevokeWorker_FullMethodName       = "/prime.AdminService/RevokeWorker"
+	AdminService_ExportItems_FullMethodName        = "/prime.AdminService/ExportItems"
+	AdminService_ImportItems_FullMethodName        = "/prime.AdminService/ImportItems"
+	AdminService_ListSnapshots_FullMethodName      = "/prime.AdminService/ListSnapshots"
+	AdminService_TakeSnapshot_FullMethodName       = "/prime.AdminService/TakeSnapshot"
+	AdminService_RestoreSnapshot_FullMethodName    = "/prime.AdminService/RestoreSnapshot"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Operator/automation API
+type AdminServiceClient interface {
+	// Machine-readable scaling signal (e.g. for HPA/KEDA scalers of generate-only workers)
+	GetPressure(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PoolPressure, error)
+	// Recent generation, persistence and peer errors, newest first
+	GetErrors(ctx context.Context, in *GetErrorsRequest, opts ...grpc.CallOption) (*GetErrorsResponse, error)
+	// Enter or leave maintenance mode: GetPreParams is refused with UNAVAILABLE
+	// (so clients fail over) and HealthCheck reports unhealthy, while
+	// generation keeps running. Entering optionally waits for in-flight
+	// requests to finish.
+	SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceStatus, error)
+	GetMaintenance(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MaintenanceStatus, error)
+	// Pause or resume background generation: refill and fill workers stop
+	// within milliseconds at the next yield point of the item they generate,
+	// keeping its progress, and start no new items. On-demand generation for
+	// requests carries on. Not persisted across restarts.
+	SetGenerationPause(ctx context.Context, in *SetGenerationPauseRequest, opts ...grpc.CallOption) (*GenerationPauseStatus, error)
+	GetGenerationPause(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GenerationPauseStatus, error)
+	// Show or lift an anomaly freeze. With freeze_on_anomaly enabled the pool
+	// stops serving (GetPreParams is refused with UNAVAILABLE) after duplicate
+	// parameters, a burst of validation failures or audit write failures, and
+	// stays frozen across restarts until an operator unfreezes it.
+	GetFreeze(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FreezeStatus, error)
+	Unfreeze(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FreezeStatus, error)
+	// Start generating up to max_pool_size (or a lower target) right away,
+	// ignoring the refill threshold, e.g. ahead of a large onboarding event.
+	// Returns once generation has started; fails with FAILED_PRECONDITION
+	// while a refill is already running.
+	FillPool(ctx context.Context, in *FillPoolRequest, opts ...grpc.CallOption) (*FillPoolResponse, error)
+	// Discard every item in the pool, e.g. after the host may have been
+	// compromised. Pinned items stay set aside. A refill replaces the items
+	// right away unless generation is paused, which pause_generation does
+	// first. Pools in shared storage are refused with FAILED_PRECONDITION.
+	ClearPool(ctx context.Context, in *ClearPoolRequest, opts ...grpc.CallOption) (*ClearPoolResponse, error)
+	// Change min_pool_size, max_pool_size and refill_threshold without a
+	// restart. The change is not persisted: a reload or restart restores the
+	// configured limits. A pool at or below the new threshold starts
+	// refilling right away.
+	SetPoolLimits(ctx context.Context, in *SetPoolLimitsRequest, opts ...grpc.CallOption) (*PoolLimits, error)
+	// Status of this instance and every configured peer, for coordinators
+	// that need a fleet-wide view
+	ListPeers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FleetStatus, error)
+	// Latency objectives (e.g. 99% of pool-served GetPreParams calls within
+	// 100ms) with compliance, remaining error budget and burn rates
+	GetSLOStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SLOStatus, error)
+	// Page through the pool contents (fingerprints, ages, provenance; never
+	// secret material), oldest first. Page tokens name a position, so items
+	// served or generated between pages do not shift the listing.
+	ListPoolItems(ctx context.Context, in *ListPoolItemsRequest, opts ...grpc.CallOption) (*ListPoolItemsResponse, error)
+	// Set a pool item aside by fingerprint, e.g. to preserve it for an
+	// incident investigation: it is never served and no longer counts towards
+	// min_pool_size, so the pool refills around it. Pinned items are listed in
+	// PoolStatus.pinned. With unpin set, the item returns to the pool.
+	PinItem(ctx context.Context, in *PinItemRequest, opts ...grpc.CallOption) (*PinnedItem, error)
+	// A gzipped tar archive for bug reports: the effective configuration
+	// with secrets redacted, recent log lines, pool status and usage history,
+	// the error journal, a goroutine dump and a metrics snapshot
+	CollectDiagnostics(ctx context.Context, in *CollectDiagnosticsRequest, opts ...grpc.CallOption) (*DiagnosticsBundle, error)
+	// Pool runway at the consumption recorded in the persisted usage history
+	// and, for a planned event (e.g. onboarding 40 signers on Friday), the
+	// generation backlog and when to start working it off
+	ForecastPool(ctx context.Context, in *ForecastPoolRequest, opts ...grpc.CallOption) (*PoolForecast, error)
+	// Summaries of the latest refills, emergency refills and fills, newest
+	// first, persisted across restarts: whether background generation keeps up
+	ListRefillCycles(ctx context.Context, in *ListRefillCyclesRequest, opts ...grpc.CallOption) (*RefillCycleList, error)
+	// Generate-only workers registered through WorkerService. Revoking a
+	// worker invalidates its token at once and refuses its registrations
+	// (persisted across restarts) until it is reinstated.
+	ListWorkers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*WorkerList, error)
+	RevokeWorker(ctx context.Context, in *RevokeWorkerRequest, opts ...grpc.CallOption) (*WorkerInfo, error)
+	// Move items out of the pool, oldest first, e.g. to migrate them to
+	// another instance with ImportItems. They are recorded as transferred,
+	// not served. A frozen pool exports nothing. Both calls carry secret
+	// material and need an API key once keys are configured.
+	ExportItems(ctx context.Context, in *ExportItemsRequest, opts ...grpc.CallOption) (*ExportItemsResponse, error)
+	// Add exported items to the pool up to max_pool_size. Every item runs
+	// the full primality validation and is flagged imported; items failing
+	// it are quarantined.
+	ImportItems(ctx context.Context, in *ImportItemsRequest, opts ...grpc.CallOption) (*ImportItemsResponse, error)
+	// Encrypted snapshots of the pool in pool.snapshot_dir, taken on a
+	// schedule, newest first. Only snapshots of the pool's bit sizes are
+	// listed, including those of other instances sharing the directory. All
+	// three calls fail with FAILED_PRECONDITION without a snapshot_dir.
+	ListSnapshots(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SnapshotList, error)
+	// Take a snapshot now, outside the schedule
+	TakeSnapshot(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SnapshotInfo, error)
+	// Add the items of a snapshot back to the pool up to max_pool_size, e.g.
+	// after the pool directory was lost. Items served after the snapshot was
+	// taken and items still in the pool are skipped; the rest run the full
+	// primality validation and are flagged imported, like ImportItems. Like
+	// ImportItems, it needs an API key once keys are configured.
+	RestoreSnapshot(ctx context.Context, in *RestoreSnapshotRequest, opts ...grpc.CallOption) (*RestoreSnapshotResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) GetPressure(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*PoolPressure, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PoolPressure)
+	err := c.cc.Invoke(ctx, AdminService_GetPressure_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetErrors(ctx context.Context, in *GetErrorsRequest, opts ...grpc.CallOption) (*GetErrorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetErrorsResponse)
+	err := c.cc.Invoke(ctx, AdminService_GetErrors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetMaintenance(ctx context.Context, in *SetMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MaintenanceStatus)
+	err := c.cc.Invoke(ctx, AdminService_SetMaintenance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetMaintenance(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*MaintenanceStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MaintenanceStatus)
+	err := c.cc.Invoke(ctx, AdminService_GetMaintenance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetGenerationPause(ctx context.Context, in *SetGenerationPauseRequest, opts ...grpc.CallOption) (*GenerationPauseStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerationPauseStatus)
+	err := c.cc.Invoke(ctx, AdminService_SetGenerationPause_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetGenerationPause(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*GenerationPauseStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerationPauseStatus)
+	err := c.cc.Invoke(ctx, AdminService_GetGenerationPause_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetFreeze(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FreezeStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FreezeStatus)
+	err := c.cc.Invoke(ctx, AdminService_GetFreeze_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Unfreeze(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FreezeStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FreezeStatus)
+	err := c.cc.Invoke(ctx, AdminService_Unfreeze_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) FillPool(ctx context.Context, in *FillPoolRequest, opts ...grpc.CallOption) (*FillPoolResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FillPoolResponse)
+	err := c.cc.Invoke(ctx, AdminService_FillPool_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ClearPool(ctx context.Context, in *ClearPoolRequest, opts ...grpc.CallOption) (*ClearPoolResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearPoolResponse)
+	err := c.cc.Invoke(ctx, AdminService_ClearPool_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetPoolLimits(ctx context.Context, in *SetPoolLimitsRequest, opts ...grpc.CallOption) (*PoolLimits, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PoolLimits)
+	err := c.cc.Invoke(ctx, AdminService_SetPoolLimits_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListPeers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*FleetStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FleetStatus)
+	err := c.cc.Invoke(ctx, AdminService_ListPeers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) GetSLOStatus(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SLOStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SLOStatus)
+	err := c.cc.Invoke(ctx, AdminService_GetSLOStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListPoolItems(ctx context.Context, in *ListPoolItemsRequest, opts ...grpc.CallOption) (*ListPoolItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPoolItemsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListPoolItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) PinItem(ctx context.Context, in *PinItemRequest, opts ...grpc.CallOption) (*PinnedItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PinnedItem)
+	err := c.cc.Invoke(ctx, AdminService_PinItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CollectDiagnostics(ctx context.Context, in *CollectDiagnosticsRequest, opts ...grpc.CallOption) (*DiagnosticsBundle, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiagnosticsBundle)
+	err := c.cc.Invoke(ctx, AdminService_CollectDiagnostics_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ForecastPool(ctx context.Context, in *ForecastPoolRequest, opts ...grpc.CallOption) (*PoolForecast, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PoolForecast)
+	err := c.cc.Invoke(ctx, AdminService_ForecastPool_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListRefillCycles(ctx context.Context, in *ListRefillCyclesRequest, opts ...grpc.CallOption) (*RefillCycleList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefillCycleList)
+	err := c.cc.Invoke(ctx, AdminService_ListRefillCycles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListWorkers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*WorkerList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkerList)
+	err := c.cc.Invoke(ctx, AdminService_ListWorkers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RevokeWorker(ctx context.Context, in *RevokeWorkerRequest, opts ...grpc.CallOption) (*WorkerInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkerInfo)
+	err := c.cc.Invoke(ctx, AdminService_RevokeWorker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ExportItems(ctx context.Context, in *ExportItemsRequest, opts ...grpc.CallOption) (*ExportItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportItemsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ExportItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ImportItems(ctx context.Context, in *ImportItemsRequest, opts ...grpc.CallOption) (*ImportItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportItemsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ImportItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListSnapshots(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SnapshotList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SnapshotList)
+	err := c.cc.Invoke(ctx, AdminService_ListSnapshots_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) TakeSnapshot(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SnapshotInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SnapshotInfo)
+	err := c.cc.Invoke(ctx, AdminService_TakeSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) RestoreSnapshot(ctx context.Context, in *RestoreSnapshotRequest, opts ...grpc.CallOption) (*RestoreSnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreSnapshotResponse)
+	err := c.cc.Invoke(ctx, AdminService_RestoreSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// Operator/automation API
+type AdminServiceServer interface {
+	// Machine-readable scaling signal (e.g. for HPA/KEDA scalers of generate-only workers)
+	GetPressure(context.Context, *Empty) (*PoolPressure, error)
+	// Recent generation, persistence and peer errors, newest first
+	GetErrors(context.Context, *GetErrorsRequest) (*GetErrorsResponse, error)
+	// Enter or leave maintenance mode: GetPreParams is refused with UNAVAILABLE
+	// (so clients fail over) and HealthCheck reports unhealthy, while
+	// generation keeps running. Entering optionally waits for in-flight
+	// requests to finish.
+	SetMaintenance(context.Context, *SetMaintenanceRequest) (*MaintenanceStatus, error)
+	GetMaintenance(context.Context, *Empty) (*MaintenanceStatus, error)
+	// Pause or resume background generation: refill and fill workers stop
+	// within milliseconds at the next yield point of the item they generate,
+	// keeping its progress, and start no new items. On-demand generation for
+	// requests carries on. Not persisted across restarts.
+	SetGenerationPause(context.Context, *SetGenerationPauseRequest) (*GenerationPauseStatus, error)
+	GetGenerationPause(context.Context, *Empty) (*GenerationPauseStatus, error)
+	// Show or lift an anomaly freeze. With freeze_on_anomaly enabled the pool
+	// stops serving (GetPreParams is refused with UNAVAILABLE) after duplicate
+	// parameters, a burst of validation failures or audit write failures, and
+	// stays frozen across restarts until an operator unfreezes it.
+	GetFreeze(context.Context, *Empty) (*FreezeStatus, error)
+	Unfreeze(context.Context, *Empty) (*FreezeStatus, error)
+	// Start generating up to max_pool_size (or a lower target) right away,
+	// ignoring the refill threshold, e.g. ahead of a large onboarding event.
+	// Returns once generation has started; fails with FAILED_PRECONDITION
+	// while a refill is already running.
+	FillPool(context.Context, *FillPoolRequest) (*FillPoolResponse, error)
+	// Discard every item in the pool, e.g. after the host may have been
+	// compromised. Pinned items stay set aside. A refill replaces the items
+	// right away unless generation is paused, which pause_generation does
+	// first. Pools in shared storage are refused with FAILED_PRECONDITION.
+	ClearPool(context.Context, *ClearPoolRequest) (*ClearPoolResponse, error)
+	// Change min_pool_size, max_pool_size and refill_threshold without a
+	// restart. The change is not persisted: a reload or restart restores the
+	// configured limits. A pool at or below the new threshold starts
+	// refilling right away.
+	SetPoolLimits(context.Context, *SetPoolLimitsRequest) (*PoolLimits, error)
+	// Status of this instance and every configured peer, for coordinators
+	// that need a fleet-wide view
+	ListPeers(context.Context, *Empty) (*FleetStatus, error)
+	// Latency objectives (e.g. 99% of pool-served GetPreParams calls within
+	// 100ms) with compliance, remaining error budget and burn rates
+	GetSLOStatus(context.Context, *Empty) (*SLOStatus, error)
+	// Page through the pool contents (fingerprints, ages, provenance; never
+	// secret material), oldest first. Page tokens name a position, so items
+	// served or generated between pages do not shift the listing.
+	ListPoolItems(context.Context, *ListPoolItemsRequest) (*ListPoolItemsResponse, error)
+	// Set a pool item aside by fingerprint, e.g. to preserve it for an
+	// incident investigation: it is never served and no longer counts towards
+	// min_pool_size, so the pool refills around it. Pinned items are listed in
+	// PoolStatus.pinned. With unpin set, the item returns to the pool.
+	PinItem(context.Context, *PinItemRequest) (*PinnedItem, error)
+	// A gzipped tar archive for bug reports: the effective configuration
+	// with secrets redacted, recent log lines, pool status and usage history,
+	// the error journal, a goroutine dump and a metrics snapshot
+	CollectDiagnostics(context.Context, *CollectDiagnosticsRequest) (*DiagnosticsBundle, error)
+	// Pool runway at the consumption recorded in the persisted usage history
+	// and, for a planned event (e.g. onboarding 40 signers on Friday), the
+	// generation backlog and when to start working it off
+	ForecastPool(context.Context, *ForecastPoolRequest) (*PoolForecast, error)
+	// Summaries of the latest refills, emergency refills and fills, newest
+	// first, persisted across restarts: whether background generation keeps up
+	ListRefillCycles(context.Context, *ListRefillCyclesRequest) (*RefillCycleList, error)
+	// Generate-only workers registered through WorkerService. Revoking a
+	// worker invalidates its token at once and refuses its registrations
+	// (persisted across restarts) until it is reinstated.
+	ListWorkers(context.Context, *Empty) (*WorkerList, error)
+	RevokeWorker(context.Context, *RevokeWorkerRequest) (*WorkerInfo, error)
+	// Move items out of the pool, oldest first, e.g. to migrate them to
+	// another instance with ImportItems. They are recorded as transferred,
+	// not served. A frozen pool exports nothing. Both calls carry secret
+	// material and need an API key once keys are configured.
+	ExportItems(context.Context, *ExportItemsRequest) (*ExportItemsResponse, error)
+	// Add exported items to the pool up to max_pool_size. Every item runs
+	// the full primality validation and is flagged imported; items failing
+	// it are quarantined.
+	ImportItems(context.Context, *ImportItemsRequest) (*ImportItemsResponse, error)
+	// Encrypted snapshots of the pool in pool.snapshot_dir, taken on a
+	// schedule, newest first. Only snapshots of the pool's bit sizes are
+	// listed, including those of other instances sharing the directory. All
+	// three calls fail with FAILED_PRECONDITION without a snapshot_dir.
+	ListSnapshots(context.Context, *Empty) (*SnapshotList, error)
+	// Take a snapshot now, outside the schedule
+	TakeSnapshot(context.Context, *Empty) (*SnapshotInfo, error)
+	// Add the items of a snapshot back to the pool up to max_pool_size, e.g.
+	// after the pool directory was lost. Items served after the snapshot was
+	// taken and items still in the pool are skipped; the rest run the full
+	// primality validation and are flagged imported, like ImportItems. Like
+	// ImportItems, it needs an API key once keys are configured.
+	RestoreSnapshot(context.Context, *RestoreSnapshotRequest) (*RestoreSnapshotResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) GetPressure(context.Context, *Empty) (*PoolPressure, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPressure not implemented")
+}
+func (UnimplementedAdminServiceServer) GetErrors(context.Context, *GetErrorsRequest) (*GetErrorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetErrors not implemented")
+}
+func (UnimplementedAdminServiceServer) SetMaintenance(context.Context, *SetMaintenanceRequest) (*MaintenanceStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaintenance not implemented")
+}
+func (UnimplementedAdminServiceServer) GetMaintenance(context.Context, *Empty) (*MaintenanceStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMaintenance not implemented")
+}
+func (UnimplementedAdminServiceServer) SetGenerationPause(context.Context, *SetGenerationPauseRequest) (*GenerationPauseStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetGenerationPause not implemented")
+}
+func (UnimplementedAdminServiceServer) GetGenerationPause(context.Context, *Empty) (*GenerationPauseStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGenerationPause not implemented")
+}
+func (UnimplementedAdminServiceServer) GetFreeze(context.Context, *Empty) (*FreezeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFreeze not implemented")
+}
+func (UnimplementedAdminServiceServer) Unfreeze(context.Context, *Empty) (*FreezeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unfreeze not implemented")
+}
+func (UnimplementedAdminServiceServer) FillPool(context.Context, *FillPoolRequest) (*FillPoolResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FillPool not implemented")
+}
+func (UnimplementedAdminServiceServer) ClearPool(context.Context, *ClearPoolRequest) (*ClearPoolResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearPool not implemented")
+}
+func (UnimplementedAdminServiceServer) SetPoolLimits(context.Context, *SetPoolLimitsRequest) (*PoolLimits, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPoolLimits not implemented")
+}
+func (UnimplementedAdminServiceServer) ListPeers(context.Context, *Empty) (*FleetStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPeers not implemented")
+}
+func (UnimplementedAdminServiceServer) GetSLOStatus(context.Context, *Empty) (*SLOStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSLOStatus not implemented")
+}
+func (UnimplementedAdminServiceServer) ListPoolItems(context.Context, *ListPoolItemsRequest) (*ListPoolItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPoolItems not implemented")
+}
+func (UnimplementedAdminServiceServer) PinItem(context.Context, *PinItemRequest) (*PinnedItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PinItem not implemented")
+}
+func (UnimplementedAdminServiceServer) CollectDiagnostics(context.Context, *CollectDiagnosticsRequest) (*DiagnosticsBundle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectDiagnostics not implemented")
+}
+func (UnimplementedAdminServiceServer) ForecastPool(context.Context, *ForecastPoolRequest) (*PoolForecast, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForecastPool not implemented")
+}
+func (UnimplementedAdminServiceServer) ListRefillCycles(context.Context, *ListRefillCyclesRequest) (*RefillCycleList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRefillCycles not implemented")
+}
+func (UnimplementedAdminServiceServer) ListWorkers(context.Context, *Empty) (*WorkerList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWorkers not implemented")
+}
+func (UnimplementedAdminServiceServer) RevokeWorker(context.Context, *RevokeWorkerRequest) (*WorkerInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeWorker not implemented")
+}
+func (UnimplementedAdminServiceServer) ExportItems(context.Context, *ExportItemsRequest) (*ExportItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportItems not implemented")
+}
+func (UnimplementedAdminServiceServer) ImportItems(context.Context, *ImportItemsRequest) (*ImportItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportItems not implemented")
+}
+func (UnimplementedAdminServiceServer) ListSnapshots(context.Context, *Empty) (*SnapshotList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSnapshots not implemented")
+}
+func (UnimplementedAdminServiceServer) TakeSnapshot(context.Context, *Empty) (*SnapshotInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TakeSnapshot not implemented")
+}
+func (UnimplementedAdminServiceServer) RestoreSnapshot(context.Context, *RestoreSnapshotRequest) (*RestoreSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSnapshot not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_GetPressure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetPressure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetPressure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetPressure(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetErrors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetErrorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetErrors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetErrors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetErrors(ctx, req.(*GetErrorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetMaintenance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetMaintenance(ctx, req.(*SetMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetMaintenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetMaintenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetMaintenance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetMaintenance(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetGenerationPause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetGenerationPauseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetGenerationPause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetGenerationPause_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetGenerationPause(ctx, req.(*SetGenerationPauseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetGenerationPause_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetGenerationPause(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetGenerationPause_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetGenerationPause(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetFreeze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetFreeze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetFreeze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetFreeze(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Unfreeze_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Unfreeze(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Unfreeze_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Unfreeze(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_FillPool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FillPoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).FillPool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_FillPool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).FillPool(ctx, req.(*FillPoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ClearPool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearPoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ClearPool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ClearPool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ClearPool(ctx, req.(*ClearPoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetPoolLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPoolLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetPoolLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetPoolLimits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetPoolLimits(ctx, req.(*SetPoolLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListPeers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListPeers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_GetSLOStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).GetSLOStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_GetSLOStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).GetSLOStatus(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListPoolItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPoolItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListPoolItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListPoolItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListPoolItems(ctx, req.(*ListPoolItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_PinItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PinItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).PinItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_PinItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).PinItem(ctx, req.(*PinItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CollectDiagnostics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectDiagnosticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CollectDiagnostics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CollectDiagnostics_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CollectDiagnostics(ctx, req.(*CollectDiagnosticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ForecastPool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForecastPoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ForecastPool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ForecastPool_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ForecastPool(ctx, req.(*ForecastPoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListRefillCycles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRefillCyclesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListRefillCycles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListRefillCycles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListRefillCycles(ctx, req.(*ListRefillCyclesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListWorkers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListWorkers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListWorkers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListWorkers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RevokeWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RevokeWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RevokeWorker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RevokeWorker(ctx, req.(*RevokeWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ExportItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ExportItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ExportItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ExportItems(ctx, req.(*ExportItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ImportItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ImportItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ImportItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ImportItems(ctx, req.(*ImportItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListSnapshots_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListSnapshots(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_TakeSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).TakeSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_TakeSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).TakeSnapshot(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_RestoreSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).RestoreSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_RestoreSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).RestoreSnapshot(ctx, req.(*RestoreSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "prime.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPressure",
+			Handler:    _AdminService_GetPressure_Handler,
+		},
+		{
+			MethodName: "GetErrors",
+			Handler:    _AdminService_GetErrors_Handler,
+		},
+		{
+			MethodName: "SetMaintenance",
+			Handler:    _AdminService_SetMaintenance_Handler,
+		},
+		{
+			MethodName: "GetMaintenance",
+			Handler:    _AdminService_GetMaintenance_Handler,
+		},
+		{
+			MethodName: "SetGenerationPause",
+			Handler:    _AdminService_SetGenerationPause_Handler,
+		},
+		{
+			MethodName: "GetGenerationPause",
+			Handler:    _AdminService_GetGenerationPause_Handler,
+		},
+		{
+			MethodName: "GetFreeze",
+			Handler:    _AdminService_GetFreeze_Handler,
+		},
+		{
+			MethodName: "Unfreeze",
+			Handler:    _AdminService_Unfreeze_Handler,
+		},
+		{
+			MethodName: "FillPool",
+			Handler:    _AdminService_FillPool_Handler,
+		},
+		{
+			MethodName: "ClearPool",
+			Handler:    _AdminService_ClearPool_Handler,
+		},
+		{
+			MethodName: "SetPoolLimits",
+			Handler:    _AdminService_SetPoolLimits_Handler,
+		},
+		{
+			MethodName: "ListPeers",
+			Handler:    _AdminService_ListPeers_Handler,
+		},
+		{
+			MethodName: "GetSLOStatus",
+			Handler:    _AdminService_GetSLOStatus_Handler,
+		},
+		{
+			MethodName: "ListPoolItems",
+			Handler:    _AdminService_ListPoolItems_Handler,
+		},
+		{
+			MethodName: "PinItem",
+			Handler:    _AdminService_PinItem_Handler,
+		},
+		{
+			MethodName: "CollectDiagnostics",
+			Handler:    _AdminService_CollectDiagnostics_Handler,
+		},
+		{
+			MethodName: "ForecastPool",
+			Handler:    _AdminService_ForecastPool_Handler,
+		},
+		{
+			MethodName: "ListRefillCycles",
+			Handler:    _AdminService_ListRefillCycles_Handler,
+		},
+		{
+			MethodName: "ListWorkers",
+			Handler:    _AdminService_ListWorkers_Handler,
+		},
+		{
+			MethodName: "RevokeWorker",
+			Handler:    _AdminService_RevokeWorker_Handler,
+		},
+		{
+			MethodName: "ExportItems",
+			Handler:    _AdminService_ExportItems_Handler,
+		},
+		{
+			MethodName: "ImportItems",
+			Handler:    _AdminService_ImportItems_Handler,
+		},
+		{
+			MethodName: "ListSnapshots",
+			Handler:    _AdminService_ListSnapshots_Handler,
+		},
+		{
+			MethodName: "TakeSnapshot",
+			Handler:    _AdminService_TakeSnapshot_Handler,
+		},
+		{
+			MethodName: "RestoreSnapshot",
+			Handler:    _AdminService_RestoreSnapshot_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/prime.proto",
+}
+
+const (
+	PeerService_PullSurplus_FullMethodName = "/prime.PeerService/PullSurplus"
+)
+
+// PeerServiceClient is the client API for PeerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Pool sharing between replicas; callers authenticate with the shared
+// peer token in the "x-peer-token" metadata header
+type PeerServiceClient interface {
+	// Hand surplus items (above the donor's min pool size) to an under-filled peer
+	PullSurplus(ctx context.Context, in *PullSurplusRequest, opts ...grpc.CallOption) (*PullSurplusResponse, error)
+}
+
+type peerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPeerServiceClient(cc grpc.ClientConnInterface) PeerServiceClient {
+	return &peerServiceClient{cc}
+}
+
+func (c *peerServiceClient) PullSurplus(ctx context.Context, in *PullSurplusRequest, opts ...grpc.CallOption) (*PullSurplusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PullSurplusResponse)
+	err := c.cc.Invoke(ctx, PeerService_PullSurplus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PeerServiceServer is the server API for PeerService service.
+// All implementations must embed UnimplementedPeerServiceServer
+// for forward compatibility.
+//
+// Pool sharing between replicas; callers authenticate with the shared
+// peer token in the "x-peer-token" metadata header
+type PeerServiceServer interface {
+	// Hand surplus items (above the donor's min pool size) to an under-filled peer
+	PullSurplus(context.Context, *PullSurplusRequest) (*PullSurplusResponse, error)
+	mustEmbedUnimplementedPeerServiceServer()
+}
+
+// UnimplementedPeerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPeerServiceServer struct{}
+
+func (UnimplementedPeerServiceServer) PullSurplus(context.Context, *PullSurplusRequest) (*PullSurplusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PullSurplus not implemented")
+}
+func (UnimplementedPeerServiceServer) mustEmbedUnimplementedPeerServiceServer() {}
+func (UnimplementedPeerServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafePeerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PeerServiceServer will
+// result in compilation errors.
+type UnsafePeerServiceServer interface {
+	mustEmbedUnimplementedPeerServiceServer()
+}
+
+func RegisterPeerServiceServer(s grpc.ServiceRegistrar, srv PeerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPeerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PeerService_ServiceDesc, srv)
+}
+
+func _PeerService_PullSurplus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PullSurplusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServiceServer).PullSurplus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PeerService_PullSurplus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServiceServer).PullSurplus(ctx, req.(*PullSurplusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PeerService_ServiceDesc is the grpc.ServiceDesc for PeerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PeerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "prime.PeerService",
+	HandlerType: (*PeerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PullSurplus",
+			Handler:    _PeerService_PullSurplus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/prime.proto",
+}
+
+const (
+	WorkerService_RegisterWorker_FullMethodName  = "/prime.WorkerService/RegisterWorker"
+	WorkerService_SubmitPreParams_FullMethodName = "/prime.WorkerService/SubmitPreParams"
+)
+
+// WorkerServiceClient is the client API for WorkerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Parameter submission from generate-only workers. A worker registers with
+// the bootstrap token in the "x-worker-bootstrap-token" metadata header and
+// gets a short-lived token of its own, which it sends in "x-worker-token"
+// to submit parameters and, before it expires, to renew it.
+type WorkerServiceClient interface {
+	RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*WorkerToken, error)
+	// Verify the items and add them to the pool up to its max size
+	SubmitPreParams(ctx context.Context, in *SubmitPreParamsRequest, opts ...grpc.CallOption) (*SubmitPreParamsResponse, error)
+}
+
+type workerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorkerServiceClient(cc grpc.ClientConnInterface) WorkerServiceClient {
+	return &workerServiceClient{cc}
+}
+
+func (c *workerServiceClient) RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*WorkerToken, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WorkerToken)
+	err := c.cc.Invoke(ctx, WorkerService_RegisterWorker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerServiceClient) SubmitPreParams(ctx context.Context, in *SubmitPreParamsRequest, opts ...grpc.CallOption) (*SubmitPreParamsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitPreParamsResponse)
+	err := c.cc.Invoke(ctx, WorkerService_SubmitPreParams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorkerServiceServer is the server API for WorkerService service.
+// All implementations must embed UnimplementedWorkerServiceServer
+// for forward compatibility.
+//
+// Parameter submission from generate-only workers. A worker registers with
+// the bootstrap token in the "x-worker-bootstrap-token" metadata header and
+// gets a short-lived token of its own, which it sends in "x-worker-token"
+// to submit parameters and, before it expires, to renew it.
+type WorkerServiceServer interface {
+	RegisterWorker(context.Context, *RegisterWorkerRequest) (*WorkerToken, error)
+	// Verify the items and add them to the pool up to its max size
+	SubmitPreParams(context.Context, *SubmitPreParamsRequest) (*SubmitPreParamsResponse, error)
+	mustEmbedUnimplementedWorkerServiceServer()
+}
+
+// UnimplementedWorkerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWorkerServiceServer struct{}
+
+func (UnimplementedWorkerServiceServer) RegisterWorker(context.Context, *RegisterWorkerRequest) (*WorkerToken, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterWorker not implemented")
+}
+func (UnimplementedWorkerServiceServer) SubmitPreParams(context.Context, *SubmitPreParamsRequest) (*SubmitPreParamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitPreParams not implemented")
+}
+func (UnimplementedWorkerServiceServer) mustEmbedUnimplementedWorkerServiceServer() {}
+func (UnimplementedWorkerServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeWorkerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WorkerServiceServer will
+// result in compilation errors.
+type UnsafeWorkerServiceServer interface {
+	mustEmbedUnimplementedWorkerServiceServer()
+}
+
+func RegisterWorkerServiceServer(s grpc.ServiceRegistrar, srv WorkerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWorkerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WorkerService_ServiceDesc, srv)
+}
+
+func _WorkerService_RegisterWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServiceServer).RegisterWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkerService_RegisterWorker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServiceServer).RegisterWorker(ctx, req.(*RegisterWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkerService_SubmitPreParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitPreParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServiceServer).SubmitPreParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorkerService_SubmitPreParams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServiceServer).SubmitPreParams(ctx, req.(*SubmitPreParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WorkerService_ServiceDesc is the grpc.ServiceDesc for WorkerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WorkerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "prime.WorkerService",
+	HandlerType: (*WorkerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterWorker",
+			Handler:    _WorkerService_RegisterWorker_Handler,
+		},
+		{
+			MethodName: "SubmitPreParams",
+			Handler:    _WorkerService_SubmitPreParams_Handler,
+		},
+	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/prime.proto",
 }
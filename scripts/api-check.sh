@@ -0,0 +1,63 @@
+#!/bin/bash
+
+# Check the public Go API (client, client/lite, client/web, proto) for
+# changes since a base revision (default: the latest tag) and fail on
+# incompatible ones.
+# Requires apidiff: go install golang.org/x/exp/cmd/apidiff@latest
+#
+# Usage: scripts/api-check.sh [base-revision]
+
+PACKAGES="client client/lite client/web proto"
+
+BASE=${1:-$(git describe --tags --abbrev=0 2>/dev/null)}
+if [ -z "$BASE" ]; then
+    echo "No base revision given and no tag found"
+    exit 2
+fi
+
+MODULE=$(go list -m)
+WORK=$(mktemp -d)
+trap 'git worktree remove --force "$WORK/base" >/dev/null 2>&1; rm -rf "$WORK"' EXIT
+
+if ! git worktree add --detach "$WORK/base" "$BASE" >/dev/null 2>&1; then
+    echo "Cannot check out $BASE"
+    exit 2
+fi
+
+incompatible=0
+compatible=0
+for pkg in $PACKAGES; do
+    export_file="$WORK/$(echo "$pkg" | tr / _).export"
+    if [ ! -d "$WORK/base/$pkg" ]; then
+        echo "$pkg: new since $BASE"
+        compatible=1
+        continue
+    fi
+    if ! (cd "$WORK/base" && apidiff -w "$export_file" "$MODULE/$pkg"); then
+        echo "Failed to read the $pkg API at $BASE"
+        exit 2
+    fi
+
+    report=$(apidiff "$export_file" "$MODULE/$pkg")
+    if [ -n "$report" ]; then
+        echo "== $pkg"
+        echo "$report"
+    fi
+    if echo "$report" | grep -q "^Incompatible changes:"; then
+        incompatible=1
+    elif [ -n "$report" ]; then
+        compatible=1
+    fi
+done
+
+# Semantic versioning: incompatible changes need a new major version (a new
+# module path), additions a new minor version
+if [ $incompatible -eq 1 ]; then
+    echo "FAIL: incompatible API changes since $BASE"
+    exit 1
+fi
+if [ $compatible -eq 1 ]; then
+    echo "OK: compatible additions since $BASE, next release must bump the minor version"
+else
+    echo "OK: no API changes since $BASE"
+fi